@@ -1,13 +1,35 @@
 package gojis
 
+import (
+	"fmt"
+	"math"
+
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+)
+
 // Args represent the arguments that are passed to a function call.
 // The arguments can be retrieved using Args#Get(int), and can be used
 // in the function.
 // The arguments are Objects, which can be Null or Undefined.
 // If 3 arguments are passed to the function, args.Get(5) will
 // return Undefined, NOT nil.
+//
+// The typed accessors like Args#String(int) and the Throw helpers throw
+// an exception into the calling script if an argument does not have the
+// expected type. They do so by panicking with a value that is recovered by
+// the function object that called the host function, so they must only be
+// used in the goroutine that the host function was called in.
 type Args struct {
-	o []Object
+	o         []Object
+	name      string
+	this      Object
+	newTarget Object
+}
+
+// thrownError is the value that the helpers of Args panic with, to throw
+// an exception into the calling script.
+type thrownError struct {
+	err errors.Error
 }
 
 // Get returns the argument at the given index.
@@ -27,3 +49,108 @@ func (a *Args) Get(index int) Object {
 func (a *Args) Len() int {
 	return len(a.o)
 }
+
+// This returns the this value of the function call, which is the object
+// that the function was called on, like obj in obj.f(). It is Undefined
+// for plain calls like f().
+func (a *Args) This() Object {
+	if a.this == nil {
+		return Undefined
+	}
+	return a.this
+}
+
+// NewTarget returns the constructor that new was applied to, if the
+// function was called as a constructor, and Undefined otherwise. Since
+// host functions can not be used as constructors, it is currently always
+// Undefined.
+func (a *Args) NewTarget() Object {
+	if a.newTarget == nil {
+		return Undefined
+	}
+	return a.newTarget
+}
+
+// Require throws a TypeError if less than n arguments were passed.
+func (a *Args) Require(n int) {
+	if a.Len() < n {
+		a.ThrowTypeError("%v requires at least %d arguments, but only %d were passed", a.function(), n, a.Len())
+	}
+}
+
+// String returns the argument at the given index, which must be a string.
+// A TypeError is thrown otherwise.
+func (a *Args) String(index int) string {
+	return a.typed(index, TypeString, "a string").(string)
+}
+
+// Number returns the argument at the given index, which must be a number.
+// A TypeError is thrown otherwise.
+func (a *Args) Number(index int) float64 {
+	return a.typed(index, TypeNumber, "a number").(float64)
+}
+
+// Int returns the argument at the given index, which must be a number. A
+// TypeError is thrown if it is not a number, and a RangeError if it is not
+// an integer that fits into an int.
+func (a *Args) Int(index int) int {
+	f := a.Number(index)
+	if f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 || int64(int(f)) != int64(f) {
+		a.ThrowRangeError("Argument %d of %v must be an integer, but is %v", index, a.function(), f)
+	}
+	return int(f)
+}
+
+// Bool returns the argument at the given index, which must be a boolean.
+// A TypeError is thrown otherwise.
+func (a *Args) Bool(index int) bool {
+	return a.typed(index, TypeBoolean, "a boolean").(bool)
+}
+
+// Func returns the argument at the given index, which must be a function.
+// A TypeError is thrown otherwise.
+func (a *Args) Func(index int) Object {
+	arg := a.Get(index)
+	if !arg.IsFunction() {
+		a.ThrowTypeError("Argument %d of %v must be a function", index, a.function())
+	}
+	return arg
+}
+
+// Object returns the argument at the given index, which must be an object.
+// A TypeError is thrown otherwise.
+func (a *Args) Object(index int) Object {
+	return a.typed(index, TypeObject, "an object").(Object)
+}
+
+// ThrowTypeError throws a TypeError with the given formatted message into
+// the calling script. It never returns, the result only allows to write
+// return args.ThrowTypeError(...) in host functions.
+func (a *Args) ThrowTypeError(format string, v ...interface{}) Object {
+	panic(&thrownError{errors.NewTypeError(fmt.Sprintf(format, v...))})
+}
+
+// ThrowRangeError throws a RangeError with the given formatted message into
+// the calling script. It never returns, the result only allows to write
+// return args.ThrowRangeError(...) in host functions.
+func (a *Args) ThrowRangeError(format string, v ...interface{}) Object {
+	panic(&thrownError{errors.NewRangeError(fmt.Sprintf(format, v...))})
+}
+
+// typed returns the Go value of the argument at the given index, which
+// must have the given type. A TypeError is thrown otherwise.
+func (a *Args) typed(index int, typ Type, what string) interface{} {
+	arg := a.Get(index)
+	if arg.Type() != typ {
+		a.ThrowTypeError("Argument %d of %v must be %v", index, a.function(), what)
+	}
+	return arg.Value()
+}
+
+// function returns the name of the called function for error messages.
+func (a *Args) function() string {
+	if a.name == "" {
+		return "function"
+	}
+	return a.name
+}
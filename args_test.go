@@ -0,0 +1,72 @@
+package gojis_test
+
+import (
+	"testing"
+
+	"github.com/gojisvm/gojis"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		expected interface{}
+	}{
+		{"string", `f("abc", "string")`, "abc"},
+		{"number", `f(1.5, "number")`, 1.5},
+		{"int", `f(-3, "int")`, float64(-3)},
+		{"bool", `f(true, "bool")`, true},
+		{"func", `typeof f(() => 1, "func")`, "function"},
+		{"object", `f({a: 1}, "object").a`, float64(1)},
+		{"this", `var o = {f: f}; o.f(null, "this") === o`, true},
+		{"plain call this", `f(null, "this")`, nil},
+		{"new target", `f(null, "newTarget")`, nil},
+		{"wrong type", `try { f(1, "string"); } catch (e) { e instanceof TypeError && e.message }`, "Argument 0 of f must be a string"},
+		{"missing argument", `try { f(undefined, "object"); } catch (e) { e.message }`, "Argument 0 of f must be an object"},
+		{"not a function", `try { f({}, "func"); } catch (e) { e.message }`, "Argument 0 of f must be a function"},
+		{"not an integer", `try { f(1.5, "int"); } catch (e) { e instanceof RangeError && e.message }`, "Argument 0 of f must be an integer, but is 1.5"},
+		{"int out of range", `try { f(2 ** 64, "int"); } catch (e) { e.name }`, "RangeError"},
+		{"require", `try { f(); } catch (e) { e.message }`, "f requires at least 2 arguments, but only 0 were passed"},
+		{"throw type error", `try { f(0, "throwTypeError"); } catch (e) { e.name + ": " + e.message }`, "TypeError: failed with 0"},
+		{"throw range error", `try { f(0, "throwRangeError"); } catch (e) { e.name }`, "RangeError"},
+		{"finally runs", `var done = false; try { try { f(); } finally { done = true; } } catch (e) {} done`, true},
+	}
+
+	vm := gojis.NewVM()
+	vm.SetFunction("f", func(args gojis.Args) gojis.Object {
+		args.Require(2)
+		switch args.String(1) {
+		case "string":
+			return vm.ToValue(args.String(0))
+		case "number":
+			return vm.ToValue(args.Number(0))
+		case "int":
+			return vm.ToValue(args.Int(0))
+		case "bool":
+			return vm.ToValue(args.Bool(0))
+		case "func":
+			return args.Func(0)
+		case "object":
+			return args.Object(0)
+		case "this":
+			return args.This()
+		case "newTarget":
+			return args.NewTarget()
+		case "throwTypeError":
+			return args.ThrowTypeError("failed with %v", args.Int(0))
+		case "throwRangeError":
+			return args.ThrowRangeError("failed")
+		}
+		return nil
+	})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+
+			result, err := vm.Eval(tt.src)
+			require.NoError(err)
+			require.Equal(tt.expected, result.Value())
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package gojis
+
+import "fmt"
+
+// BudgetExceededError is the error that is returned if the evaluation of
+// ECMAScript code exceeds the budget that was set with VM#SetBudget.
+type BudgetExceededError struct {
+	steps uint64
+}
+
+// Steps returns the number of steps that the VM had evaluated when the
+// budget was exceeded, as returned by VM#Steps.
+func (e *BudgetExceededError) Steps() uint64 {
+	return e.steps
+}
+
+func (e *BudgetExceededError) Error() string {
+	return fmt.Sprintf("Evaluation exceeded the budget after %d steps", e.steps)
+}
+
+// SetBudget limits the evaluation of ECMAScript code by the VM to the given
+// number of further steps. A step is an iteration of a loop, or a call of
+// an ECMAScript function. Built-in functions take steps proportional to
+// their work, like a step for every element that Array.prototype.join or
+// sort visits or compares, or that Function.prototype.apply passes, a step
+// for every character and repetition that a regular expression tries to
+// match, including the ones it backtracks, and a step for every character
+// that String.prototype.repeat or padStart create, which is taken before
+// the character is allocated. The number of steps that code takes does not
+// depend on the engine or on the time it takes, so it can be used to limit
+// and bill the evaluation of untrusted code.
+//
+// If an evaluation would take more steps than the budget allows, it is
+// stopped like by VM#Interrupt, and returns a *BudgetExceededError. The
+// budget stays exceeded until SetBudget or ClearBudget is called, so that
+// later evaluations are stopped at their first step.
+//
+// To limit a single evaluation, set the budget right before it.
+func (vm *VM) SetBudget(steps uint64) {
+	vm.runtime.SetBudget(steps)
+}
+
+// ClearBudget removes the limit that was set with SetBudget.
+func (vm *VM) ClearBudget() {
+	vm.runtime.ClearBudget()
+}
+
+// Steps returns the number of steps that the VM has evaluated since it was
+// created. The steps taken by an evaluation are the difference of the
+// number of steps before and after it.
+func (vm *VM) Steps() uint64 {
+	return vm.runtime.Steps()
+}
@@ -0,0 +1,125 @@
+package gojis_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gojisvm/gojis"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSteps(t *testing.T) {
+	tests := []struct {
+		name  string
+		src   string
+		steps uint64
+	}{
+		{"no loops", `var x = 1 + 2;`, 0},
+		{"while", `var i = 0; while (i < 10) i++;`, 10},
+		{"do while", `var i = 0; do { i++; } while (i < 10);`, 9},
+		{"for", `for (var i = 0; i < 10; i++) {}`, 10},
+		{"for let", `var fs = []; for (let i = 0; i < 10; i++) fs.push(() => i);`, 10},
+		{"for continue", `for (var i = 0; i < 10; i++) { if (i % 2) continue; }`, 10},
+		{"for break", `for (var i = 0; ; i++) { if (i == 5) break; }`, 5},
+		{"for in", `for (var k in {a: 1, b: 2, c: 3}) {}`, 3},
+		{"for of", `for (const x of [1, 2, 3]) { if (x == 2) continue; }`, 3},
+		{"calls", `function f(n) { return n && f(n - 1); } f(3);`, 4},
+		{"arrows", `[1, 2, 3].map(x => x * 2);`, 6},
+		{"constructors", `class A {} class B extends A {} new B();`, 2},
+		{"join", `[1, 2, 3].join();`, 3},
+		{"fill", `new Array(5).fill(0);`, 5},
+		{"repeat", `"ab".repeat(3);`, 6},
+		{"regexp", `/b/.test("ab");`, 4},
+		{"regexp backtracking", `/a*b/.test("aaa");`, 35},
+		{"apply", `Math.max.apply(null, [1, 2, 3]);`, 3},
+		{"regexp captures", `var re = /x/; re.exec = () => ({length: 4, 0: "", index: 0}); "".replace(re, "");`, 5},
+	}
+	for _, e := range engines {
+		for _, tt := range tests {
+			t.Run(e.name+"/"+tt.name, func(t *testing.T) {
+				require := require.New(t)
+
+				vm := gojis.NewVM()
+				vm.SetEngine(e.engine)
+				before := vm.Steps()
+				_, err := vm.Eval(tt.src)
+				require.NoError(err)
+				require.Equal(tt.steps, vm.Steps()-before)
+			})
+		}
+	}
+}
+
+func TestBudget(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{"loop", `while (true) {}`},
+		{"recursion", `function f() { return f(); } f();`},
+		{"catch and finally", `
+try {
+	for (;;) {}
+} catch (e) {
+	done = true;
+} finally {
+	done = true;
+}
+		`},
+		{"host function", `run(() => { while (true) {} });`},
+		{"regexp backtracking", `/(a+)+$/.test("a".repeat(40) + "b");`},
+		{"repeat", `"x".repeat(1e8);`},
+		{"pad", `"x".padEnd(1e8);`},
+		{"sort", `new Array(1e6).fill(0).sort();`},
+	}
+	for _, e := range engines {
+		for _, tt := range tests {
+			t.Run(e.name+"/"+tt.name, func(t *testing.T) {
+				require := require.New(t)
+
+				vm := gojis.NewVM()
+				vm.SetEngine(e.engine)
+				vm.SetObject("done", vm.ToValue(false))
+				vm.SetFunctionWithError("run", func(args gojis.Args) (gojis.Object, error) {
+					return args.Func(0).CallWithArgs()
+				})
+
+				vm.SetBudget(1000)
+				steps := vm.Steps()
+				result, err := vm.Eval(tt.src)
+				require.Equal(gojis.Undefined, result)
+				require.IsType(&gojis.BudgetExceededError{}, err)
+				require.Equal(steps+1000, vm.Steps())
+				require.Equal(vm.Steps(), err.(*gojis.BudgetExceededError).Steps())
+				require.Equal(false, vm.Lookup("done").Value())
+
+				// the budget stays exceeded
+				_, err = vm.Eval(`for (;;) {}`)
+				require.IsType(&gojis.BudgetExceededError{}, err)
+
+				vm.SetBudget(10)
+				result, err = vm.Eval(`var n = 0; for (var i = 0; i < 10; i++) n += i; n`)
+				require.NoError(err)
+				require.Equal(45.0, result.Value())
+
+				vm.ClearBudget()
+				result, err = vm.Eval(`for (var i = 0; i < 10000; i++) {} i`)
+				require.NoError(err)
+				require.Equal(10000.0, result.Value())
+			})
+		}
+	}
+}
+
+func TestBudgetMax(t *testing.T) {
+	require := require.New(t)
+
+	vm := gojis.NewVM()
+	_, err := vm.Eval(`for (var i = 0; i < 10; i++) {}`)
+	require.NoError(err)
+
+	vm.SetBudget(math.MaxUint64)
+	result, err := vm.Eval(`for (var i = 0; i < 10; i++) {} i`)
+	require.NoError(err)
+	require.Equal(10.0, result.Value())
+}
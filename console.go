@@ -0,0 +1,109 @@
+package gojis
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+	"github.com/gojisvm/gojis/internal/runtime/realm"
+)
+
+// newConsole creates the console object of the VM. console.log writes its
+// arguments, separated by spaces, to the console writer of the VM.
+func (vm *VM) newConsole() Object {
+	console := vm.wrap(lang.ObjectCreate(vm.runtime.Realm().GetIntrinsicObject(realm.IntrinsicNameObjectPrototype)))
+	console.SetFunction("log", func(args Args) Object {
+		parts := make([]string, args.Len())
+		for i := range parts {
+			parts[i] = inspect(vm.unwrap(args.Get(i)), false, 0)
+		}
+		_, _ = fmt.Fprintln(vm.console, strings.Join(parts, " "))
+		return Undefined
+	})
+	return console
+}
+
+// maxInspectDepth is the depth up to which nested objects are inspected.
+const maxInspectDepth = 2
+
+// inspect returns a human readable representation of the given value. If
+// nested is true, strings are quoted. Objects deeper than maxInspectDepth
+// are abbreviated.
+func inspect(v lang.Value, nested bool, depth int) string {
+	switch v := v.(type) {
+	case lang.String:
+		if nested {
+			return "'" + strings.Replace(v.String(), "'", "\\'", -1) + "'"
+		}
+		return v.String()
+	case *lang.Symbol:
+		return lang.SymbolDescriptiveString(v).String()
+	case *lang.Object:
+		return inspectObject(v, depth)
+	}
+
+	s, err := lang.ToString(v)
+	if err != nil {
+		return ""
+	}
+	return s.String()
+}
+
+func inspectObject(o *lang.Object, depth int) string {
+	if lang.InternalIsCallable(o) {
+		name, _ := lang.Get(o, lang.NewStringKey("name"))
+		if s, ok := name.(lang.String); ok && len(s) > 0 {
+			return "[Function: " + s.String() + "]"
+		}
+		return "[Function (anonymous)]"
+	}
+
+	isArray := lang.InternalIsArray(o)
+	if depth >= maxInspectDepth {
+		if isArray {
+			return "[Array]"
+		}
+		return "[Object]"
+	}
+
+	var parts []string
+	for _, key := range o.OwnPropertyKeys() {
+		desc := o.GetOwnProperty(key)
+		if desc == nil || !desc.Enumerable() {
+			continue
+		}
+
+		var value string
+		if desc.IsAccessorDescriptor() {
+			value = "[Getter/Setter]"
+		} else {
+			value = inspect(desc.Value(), true, depth+1)
+		}
+		if isArray && key.Type() == lang.TypeString {
+			if _, err := strconv.ParseUint(key.String().String(), 10, 32); err == nil {
+				parts = append(parts, value)
+				continue
+			}
+		}
+		parts = append(parts, inspectKey(key)+": "+value)
+	}
+
+	if isArray {
+		if len(parts) == 0 {
+			return "[]"
+		}
+		return "[ " + strings.Join(parts, ", ") + " ]"
+	}
+	if len(parts) == 0 {
+		return "{}"
+	}
+	return "{ " + strings.Join(parts, ", ") + " }"
+}
+
+func inspectKey(key lang.StringOrSymbol) string {
+	if key.Type() == lang.TypeSymbol {
+		return "[" + lang.SymbolDescriptiveString(key.Underlying()).String() + "]"
+	}
+	return key.String().String()
+}
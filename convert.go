@@ -0,0 +1,771 @@
+package gojis
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+	"github.com/gojisvm/gojis/internal/runtime/realm"
+)
+
+var (
+	typeObject = reflect.TypeOf((*Object)(nil)).Elem()
+	typeError  = reflect.TypeOf((*error)(nil)).Elem()
+	typeTime   = reflect.TypeOf(time.Time{})
+)
+
+// ToValue converts the given Go value to an Object of the VM. The value is
+// converted as follows.
+//
+//   - nil, and nil pointers, slices, maps, functions and interfaces become
+//     Null.
+//   - An Object is returned unchanged.
+//   - Booleans and strings become Booleans and Strings.
+//   - All integer and floating point types become Numbers. Integers whose
+//     absolute value is greater than 2^53 can not be represented exactly,
+//     and are rounded to the nearest Number.
+//   - A time.Time becomes a Date object.
+//   - Slices and arrays become Array objects holding the converted elements.
+//   - Maps become objects with a property for each entry. The keys are
+//     formatted with fmt.Sprint, and the properties are created in the
+//     order of the formatted keys.
+//   - Pointers to structs become host objects, that are bound to the
+//     struct. A host object has a property for each exported field of the
+//     struct, named by the js tag of the field, or if there is none, by its
+//     json tag. Fields tagged with "-" are skipped, and fields with the js
+//     tag option readonly, like `js:"total,readonly"`, can not be written
+//     by scripts. Reading a property converts the current value of the
+//     field, and writing it exports the value into the field, so changes
+//     are visible on both sides. Slices and maps in fields are copied when
+//     they are read, so their elements are changed by writing the whole
+//     field. The fields can not be deleted or reconfigured, other
+//     properties can be added like to ordinary objects. The exported
+//     methods of the pointer type are functions of the prototype of the
+//     host object, named like the method with a lower case first word, so
+//     ApplyDiscount becomes applyDiscount, and URL becomes url. They are
+//     called like functions, see below. Struct values become host objects
+//     bound to the struct if they are addressable, like the struct fields
+//     of other host objects, and bound to a copy of the struct otherwise.
+//   - Other pointers become the converted value they point to.
+//   - Functions become function objects. The arguments of a call are
+//     exported into the parameter types of the function, as by Export, and
+//     a TypeError is thrown if that fails. A function without results
+//     returns Undefined, a function with one result returns the converted
+//     result, and a function with more results returns an Array of them.
+//     If the last result is an error, it is not part of the results, and
+//     an Error object with its message is thrown if it is not nil. If the
+//     error is an *Exception, its value is thrown instead.
+//
+// Slices and maps that are reached more than once while converting a value
+// are converted to the same object, and the same struct is always bound to
+// the same host object, so that cyclic Go values become cyclic objects.
+// Because of that, the VM keeps every struct that is bound to a host
+// object, until it is released with ReleaseHostObject. Channels, complex
+// numbers and unsafe pointers can not be converted, Undefined is returned
+// for them, as well as for values that contain them.
+func (vm *VM) ToValue(v interface{}) Object {
+	value, err := vm.toValue(v)
+	if err != nil {
+		return Undefined
+	}
+	return vm.wrap(value)
+}
+
+// toValue converts the given Go value to a language value, as described by
+// ToValue.
+func (vm *VM) toValue(v interface{}) (lang.Value, error) {
+	return vm.convert(reflect.ValueOf(v))
+}
+
+// convert converts the given reflected Go value to a language value, as
+// described by ToValue. Addressable structs are bound to host objects.
+func (vm *VM) convert(v reflect.Value) (lang.Value, error) {
+	return vm.convertField(nil, v)
+}
+
+// convertField converts the given reflected Go value like convert does.
+// If parent is not nil, the value is a field of the struct with that key,
+// and the structs that are bound to host objects during the conversion are
+// released together with that struct.
+func (vm *VM) convertField(parent *seenKey, v reflect.Value) (lang.Value, error) {
+	c := &toValueConverter{vm: vm, parent: parent, seen: make(map[seenKey]*lang.Object)}
+	return c.convert(v)
+}
+
+// seenKey identifies a pointer, slice or map that has already been
+// converted. Slices that share their first element but have a different
+// length are different values, the same holds for pointers of different
+// types to the same address, e.g. a pointer to a struct and a pointer to
+// its first field.
+type seenKey struct {
+	ptr uintptr
+	typ reflect.Type
+	len int
+}
+
+type toValueConverter struct {
+	vm     *VM
+	parent *seenKey
+	seen   map[seenKey]*lang.Object
+}
+
+// hostObject returns the host object of the struct that the given pointer
+// points to, which is a child of the parent of the conversion.
+func (c *toValueConverter) hostObject(ptr reflect.Value) *lang.Object {
+	o := c.vm.hostObject(ptr)
+	if c.parent != nil {
+		children := c.vm.hostChildren[*c.parent]
+		if children == nil {
+			children = make(map[seenKey]bool)
+			c.vm.hostChildren[*c.parent] = children
+		}
+		children[hostKey(ptr)] = true
+	}
+	return o
+}
+
+func (c *toValueConverter) realm() *realm.Realm {
+	return c.vm.runtime.Realm()
+}
+
+func (c *toValueConverter) convert(v reflect.Value) (lang.Value, error) {
+	if !v.IsValid() {
+		return lang.Null, nil
+	}
+	if v.Type() == typeObject || v.Type().Implements(typeObject) && v.Kind() != reflect.Interface {
+		if isNil(v) {
+			return lang.Null, nil
+		}
+		return c.vm.unwrap(v.Interface().(Object)), nil
+	}
+	if v.Type() == typeTime {
+		t := v.Interface().(time.Time)
+		return c.realm().CreateDate(float64(t.Unix()*1000 + int64(t.Nanosecond()/1e6))), nil
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		return lang.Boolean(v.Bool()), nil
+	case reflect.String:
+		return lang.NewString(v.String()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return lang.NewNumber(float64(v.Int())), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return lang.NewNumber(float64(v.Uint())), nil
+	case reflect.Float32, reflect.Float64:
+		return lang.NewNumber(v.Float()), nil
+	case reflect.Interface:
+		if v.IsNil() {
+			return lang.Null, nil
+		}
+		return c.convert(v.Elem())
+	case reflect.Ptr:
+		if v.IsNil() {
+			return lang.Null, nil
+		}
+		switch elem := v.Elem(); {
+		case elem.Kind() == reflect.Struct && elem.Type() != typeTime:
+			return c.hostObject(v), nil
+		case elem.Kind() == reflect.Array:
+			key := seenKey{ptr: v.Pointer(), typ: v.Type()}
+			if o, ok := c.seen[key]; ok {
+				return o, nil
+			}
+			return c.convertArray(&key, elem)
+		default:
+			return c.convert(elem)
+		}
+	case reflect.Slice:
+		if v.IsNil() {
+			return lang.Null, nil
+		}
+		key := seenKey{ptr: v.Pointer(), typ: v.Type(), len: v.Len()}
+		if o, ok := c.seen[key]; ok {
+			return o, nil
+		}
+		return c.convertArray(&key, v)
+	case reflect.Array:
+		return c.convertArray(nil, v)
+	case reflect.Map:
+		if v.IsNil() {
+			return lang.Null, nil
+		}
+		key := seenKey{ptr: v.Pointer(), typ: v.Type()}
+		if o, ok := c.seen[key]; ok {
+			return o, nil
+		}
+		return c.convertMap(key, v)
+	case reflect.Struct:
+		if v.CanAddr() {
+			return c.hostObject(v.Addr()), nil
+		}
+		ptr := reflect.New(v.Type())
+		ptr.Elem().Set(v)
+		return c.vm.newHostObject(ptr), nil
+	case reflect.Func:
+		if v.IsNil() {
+			return lang.Null, nil
+		}
+		return c.vm.convertFunc(v), nil
+	}
+	return nil, fmt.Errorf("Cannot convert value of type %v", v.Type())
+}
+
+func (c *toValueConverter) convertArray(key *seenKey, v reflect.Value) (lang.Value, error) {
+	a, _ := lang.ArrayCreate(0, c.realm().GetIntrinsicObject(realm.IntrinsicNameArrayPrototype).(*lang.Object))
+	if key != nil {
+		c.seen[*key] = a
+	}
+	for i := 0; i < v.Len(); i++ {
+		elem, err := c.convert(v.Index(i))
+		if err != nil {
+			return nil, err
+		}
+		_, _ = lang.CreateDataProperty(a, lang.NewStringKey(strconv.Itoa(i)), elem)
+	}
+	return a, nil
+}
+
+func (c *toValueConverter) convertMap(key seenKey, v reflect.Value) (lang.Value, error) {
+	o := lang.ObjectCreate(c.realm().GetIntrinsicObject(realm.IntrinsicNameObjectPrototype).(*lang.Object))
+	c.seen[key] = o
+
+	keys := make([]string, v.Len())
+	values := make(map[string]reflect.Value, v.Len())
+	for i, k := range v.MapKeys() {
+		keys[i] = fmt.Sprint(k.Interface())
+		values[keys[i]] = v.MapIndex(k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		elem, err := c.convert(values[k])
+		if err != nil {
+			return nil, err
+		}
+		_, _ = lang.CreateDataProperty(o, lang.NewStringKey(k), elem)
+	}
+	return o, nil
+}
+
+// convertFunc creates a function object that calls the given Go function,
+// as described by ToValue.
+func (vm *VM) convertFunc(fn reflect.Value) *lang.Object {
+	return vm.runtime.Realm().NewFunction("", funcLength(fn.Type()), func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		return vm.callFunc(fn, args)
+	})
+}
+
+// funcLength returns the number of parameters of the given function type,
+// without the variadic parameter.
+func funcLength(typ reflect.Type) int {
+	if typ.IsVariadic() {
+		return typ.NumIn() - 1
+	}
+	return typ.NumIn()
+}
+
+// callFunc calls the given Go function with the given arguments, as
+// described by ToValue.
+func (vm *VM) callFunc(fn reflect.Value, args []lang.Value) (result lang.Value, err errors.Error) {
+	defer vm.recoverHostPanic(&err)
+
+	typ := fn.Type()
+	length := funcLength(typ)
+	numOut := typ.NumOut()
+	hasError := numOut > 0 && typ.Out(numOut-1) == typeError
+	if hasError {
+		numOut--
+	}
+
+	in := make([]reflect.Value, 0, len(args))
+	for i := 0; i < length || i < len(args) && typ.IsVariadic(); i++ {
+		var paramType reflect.Type
+		if i < length {
+			paramType = typ.In(i)
+		} else {
+			paramType = typ.In(length).Elem()
+		}
+		arg := reflect.New(paramType)
+		if exportErr := vm.export(argument(args, i), arg.Interface()); exportErr != nil {
+			return nil, errors.NewTypeError(fmt.Sprintf("Invalid argument %d: %v", i, exportErr))
+		}
+		in = append(in, arg.Elem())
+	}
+
+	out := fn.Call(in)
+	if hasError {
+		if goErr, _ := out[numOut].Interface().(error); goErr != nil {
+			return nil, vm.throwError(goErr)
+		}
+	}
+	results := make([]lang.Value, numOut)
+	for i := range results {
+		value, convertErr := vm.toValue(out[i].Interface())
+		if convertErr != nil {
+			return nil, errors.NewTypeError(convertErr.Error())
+		}
+		results[i] = value
+	}
+
+	switch numOut {
+	case 0:
+		return lang.Undefined, nil
+	case 1:
+		return results[0], nil
+	}
+	return lang.CreateArrayFromList(vm.runtime.Realm(), results), nil
+}
+
+// get returns the value of the property with the given name of the given
+// object.
+func get(o *lang.Object, name string) (lang.Value, errors.Error) {
+	v, err := lang.Get(o, lang.NewStringKey(name))
+	if err != nil {
+		return nil, err
+	}
+	return v.(lang.Value), nil
+}
+
+// argument returns the argument with the given index, or Undefined if
+// there is no such argument.
+func argument(args []lang.Value, i int) lang.Value {
+	if i < len(args) {
+		return args[i]
+	}
+	return lang.Undefined
+}
+
+// structField is an exported field of a struct that is converted to a
+// property. Readonly fields can not be written by scripts.
+type structField struct {
+	name     string
+	index    []int
+	readonly bool
+}
+
+// structFields returns the exported fields of the given struct type. The
+// name of a field is the name in its js tag, or if it has none, the name in
+// its json tag, and the name of the field if neither tag has a name. Fields
+// with a js or json tag "-" are skipped. A js tag with the option readonly,
+// like `js:"total,readonly"`, makes the field readonly.
+func structFields(typ reflect.Type) []structField {
+	var fields []structField
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		field := structField{name: f.Name, index: f.Index}
+		tag, isJS := f.Tag.Lookup("js")
+		if !isJS {
+			tag = f.Tag.Get("json")
+		}
+		if tag == "-" {
+			continue
+		}
+		name, options := tagName(tag)
+		if name != "" {
+			field.name = name
+		}
+		for _, option := range options {
+			field.readonly = field.readonly || isJS && option == "readonly"
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// tagName splits a struct tag value into its name, which is the part up to
+// the first comma, and its options.
+func tagName(tag string) (string, []string) {
+	parts := strings.Split(tag, ",")
+	return parts[0], parts[1:]
+}
+
+// isNil reports whether the given value is a nil pointer, slice, map,
+// function, channel or interface.
+func isNil(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Func, reflect.Chan, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
+
+// export stores the Go value of the given language value in the value that
+// target points to, as described by Object#Export. The VM may only be nil
+// if the value is Undefined or Null.
+func (vm *VM) export(v lang.Value, target interface{}) error {
+	ptr := reflect.ValueOf(target)
+	if ptr.Kind() != reflect.Ptr || ptr.IsNil() {
+		return fmt.Errorf("Export target must be a non-nil pointer, not %T", target)
+	}
+	e := &exporter{vm: vm, seen: make(map[exportKey]reflect.Value)}
+	return e.export(v, ptr.Elem())
+}
+
+// exportKey identifies an object that has already been exported into a
+// value of the given type.
+type exportKey struct {
+	o   *lang.Object
+	typ reflect.Type
+}
+
+type exporter struct {
+	vm   *VM
+	seen map[exportKey]reflect.Value
+}
+
+func (e *exporter) export(v lang.Value, dst reflect.Value) error {
+	typ := dst.Type()
+	if typ == typeObject {
+		dst.Set(reflect.ValueOf(e.vm.wrap(v)))
+		return nil
+	}
+	if v == lang.Undefined || v == lang.Null {
+		dst.Set(reflect.Zero(typ))
+		return nil
+	}
+	if typ == typeTime {
+		return e.exportTime(v, dst)
+	}
+	if typ.Kind() == reflect.Interface {
+		if typ.NumMethod() != 0 {
+			return fmt.Errorf("Cannot export into %v", typ)
+		}
+		return e.exportInterface(v, dst)
+	}
+
+	if h, ok := hostData(v); ok {
+		switch typ {
+		case h.ptr.Type():
+			dst.Set(h.ptr)
+			return nil
+		case h.ptr.Type().Elem():
+			dst.Set(h.ptr.Elem())
+			return nil
+		}
+	}
+
+	o, isObject := v.(*lang.Object)
+	if isObject {
+		switch typ.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Map:
+			if seen, ok := e.seen[exportKey{o, typ}]; ok {
+				dst.Set(seen)
+				return nil
+			}
+		}
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool:
+		dst.SetBool(bool(lang.ToBoolean(v)))
+		return nil
+	case reflect.String:
+		s, err := lang.ToString(v)
+		if err != nil {
+			return err
+		}
+		dst.SetString(s.String())
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		f, err := e.exportInteger(v, typ)
+		if err != nil {
+			return err
+		}
+		dst.SetInt(int64(f))
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		f, err := e.exportInteger(v, typ)
+		if err != nil {
+			return err
+		}
+		dst.SetUint(uint64(f))
+		return nil
+	case reflect.Float32, reflect.Float64:
+		n, err := lang.ToNumber(v)
+		if err != nil {
+			return err
+		}
+		dst.SetFloat(n.Float64())
+		return nil
+	case reflect.Ptr:
+		p := reflect.New(typ.Elem())
+		if isObject {
+			e.seen[exportKey{o, typ}] = p
+		}
+		if err := e.export(v, p.Elem()); err != nil {
+			return err
+		}
+		dst.Set(p)
+		return nil
+	case reflect.Func:
+		if !lang.InternalIsCallable(v) {
+			return fmt.Errorf("Cannot export non-function into %v", typ)
+		}
+		dst.Set(e.vm.exportFunc(o, typ))
+		return nil
+	}
+
+	if !isObject {
+		return fmt.Errorf("Cannot export %v into %v", v.Type(), typ)
+	}
+	switch typ.Kind() {
+	case reflect.Slice, reflect.Array:
+		return e.exportArray(o, dst)
+	case reflect.Map:
+		return e.exportMap(o, dst)
+	case reflect.Struct:
+		return e.exportStruct(o, dst)
+	}
+	return fmt.Errorf("Cannot export into %v", typ)
+}
+
+// exportInteger returns the Number of the given value, if it is an integer
+// that fits into the given integer type.
+func (e *exporter) exportInteger(v lang.Value, typ reflect.Type) (float64, error) {
+	n, err := lang.ToNumber(v)
+	if err != nil {
+		return 0, err
+	}
+	f := n.Float64()
+	if f != math.Trunc(f) || math.IsInf(f, 0) || math.IsNaN(f) {
+		return 0, fmt.Errorf("Cannot export %v into %v", f, typ)
+	}
+
+	var min, max float64
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		bits := uint(typ.Bits())
+		min, max = -math.Ldexp(1, int(bits-1)), math.Ldexp(1, int(bits-1))
+	default:
+		min, max = 0, math.Ldexp(1, typ.Bits())
+	}
+	if f < min || f >= max {
+		return 0, fmt.Errorf("Cannot export %v into %v", f, typ)
+	}
+	return f, nil
+}
+
+func (e *exporter) exportTime(v lang.Value, dst reflect.Value) error {
+	tv, ok := realm.DateValue(v)
+	if !ok {
+		n, isNumber := v.(lang.Number)
+		if !isNumber {
+			return fmt.Errorf("Cannot export %v into time.Time", v.Type())
+		}
+		tv = n.Float64()
+	}
+	if math.IsNaN(tv) || math.IsInf(tv, 0) {
+		return fmt.Errorf("Cannot export invalid date into time.Time")
+	}
+	sec := math.Floor(tv / 1000)
+	dst.Set(reflect.ValueOf(time.Unix(int64(sec), int64(tv-sec*1000)*1e6).UTC()))
+	return nil
+}
+
+// exportInterface exports the given value into an empty interface, as
+// described by Object#Export.
+func (e *exporter) exportInterface(v lang.Value, dst reflect.Value) error {
+	var target reflect.Type
+	switch v := v.(type) {
+	case lang.Boolean:
+		dst.Set(reflect.ValueOf(bool(v)))
+		return nil
+	case lang.Number:
+		dst.Set(reflect.ValueOf(v.Float64()))
+		return nil
+	case lang.String:
+		dst.Set(reflect.ValueOf(v.String()))
+		return nil
+	case *lang.Object:
+		if h, ok := hostData(v); ok {
+			dst.Set(h.ptr)
+			return nil
+		}
+		switch {
+		case lang.InternalIsCallable(v):
+			target = reflect.TypeOf(func(...interface{}) (interface{}, error) { return nil, nil })
+		case lang.InternalIsArray(v):
+			target = reflect.TypeOf([]interface{}(nil))
+		default:
+			if _, ok := realm.DateValue(v); ok {
+				target = typeTime
+			} else {
+				target = reflect.TypeOf(map[string]interface{}(nil))
+			}
+		}
+	default:
+		dst.Set(reflect.ValueOf(e.vm.wrap(v)))
+		return nil
+	}
+
+	value := reflect.New(target).Elem()
+	if err := e.export(v, value); err != nil {
+		return err
+	}
+	dst.Set(value)
+	return nil
+}
+
+func (e *exporter) exportArray(o *lang.Object, dst reflect.Value) error {
+	lengthValue, err := get(o, "length")
+	if err != nil {
+		return err
+	}
+	length, err := lang.ToLength(lengthValue)
+	if err != nil {
+		return err
+	}
+	n := int(length.Float64())
+
+	arr := dst
+	if dst.Kind() == reflect.Slice {
+		arr = reflect.MakeSlice(dst.Type(), n, n)
+		e.seen[exportKey{o, dst.Type()}] = arr
+	} else if n > arr.Len() {
+		n = arr.Len()
+	}
+	for i := 0; i < n; i++ {
+		elem, err := get(o, strconv.Itoa(i))
+		if err != nil {
+			return err
+		}
+		if err := e.export(elem, arr.Index(i)); err != nil {
+			return err
+		}
+	}
+	dst.Set(arr)
+	return nil
+}
+
+func (e *exporter) exportMap(o *lang.Object, dst reflect.Value) error {
+	typ := dst.Type()
+	m := reflect.MakeMap(typ)
+	e.seen[exportKey{o, typ}] = m
+
+	keys, err := lang.EnumerableOwnPropertyNames(nil, o, lang.EnumerateKey)
+	if err != nil {
+		return err
+	}
+	for _, k := range keys {
+		name := k.(lang.String).String()
+		key := reflect.New(typ.Key()).Elem()
+		if err := e.export(k, key); err != nil {
+			return fmt.Errorf("Cannot export key '%v' into %v", name, typ.Key())
+		}
+		value, err := get(o, name)
+		if err != nil {
+			return err
+		}
+		elem := reflect.New(typ.Elem()).Elem()
+		if err := e.export(value, elem); err != nil {
+			return err
+		}
+		m.SetMapIndex(key, elem)
+	}
+	dst.Set(m)
+	return nil
+}
+
+func (e *exporter) exportStruct(o *lang.Object, dst reflect.Value) error {
+	for _, f := range structFields(dst.Type()) {
+		value, err := get(o, f.name)
+		if err != nil {
+			return err
+		}
+		if value == lang.Undefined {
+			continue
+		}
+		if err := e.export(value, dst.FieldByIndex(f.index)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exportFunc returns a Go function of the given type that calls the given
+// function object, as described by Object#Export.
+func (vm *VM) exportFunc(fn *lang.Object, typ reflect.Type) reflect.Value {
+	numOut := typ.NumOut()
+	hasError := numOut > 0 && typ.Out(numOut-1) == typeError
+	if hasError {
+		numOut--
+	}
+
+	return reflect.MakeFunc(typ, func(in []reflect.Value) []reflect.Value {
+		out := make([]reflect.Value, typ.NumOut())
+		for i := range out {
+			out[i] = reflect.Zero(typ.Out(i))
+		}
+		fail := func(err error) []reflect.Value {
+			if !hasError {
+				panic(err)
+			}
+			out[numOut] = reflect.ValueOf(&err).Elem()
+			return out
+		}
+
+		if typ.IsVariadic() {
+			variadic := in[len(in)-1]
+			in = in[:len(in)-1]
+			for i := 0; i < variadic.Len(); i++ {
+				in = append(in, variadic.Index(i))
+			}
+		}
+		args := make([]lang.Value, len(in))
+		for i, arg := range in {
+			v, err := vm.toValue(arg.Interface())
+			if err != nil {
+				return fail(err)
+			}
+			args[i] = v
+		}
+
+		var result lang.Value
+		if err := vm.evaluate(func() error {
+			var thrown errors.Error
+			result, thrown = lang.Call(fn, lang.Undefined, args...)
+			if thrown != nil {
+				return vm.exception(thrown)
+			}
+			return nil
+		}); err != nil {
+			return fail(err)
+		}
+
+		var results []lang.Value
+
+		if numOut == 1 {
+			results = []lang.Value{result}
+		} else if numOut > 1 {
+			o, ok := result.(*lang.Object)
+			if !ok {
+				return fail(fmt.Errorf("Cannot export %v into %d results", result.Type(), numOut))
+			}
+			for i := 0; i < numOut; i++ {
+				elem, err := get(o, strconv.Itoa(i))
+				if err != nil {
+					return fail(vm.exception(err))
+				}
+				results = append(results, elem)
+			}
+		}
+		for i, result := range results {
+			outValue := reflect.New(typ.Out(i))
+			if err := vm.export(result, outValue.Interface()); err != nil {
+				return fail(err)
+			}
+			out[i] = outValue.Elem()
+		}
+		return out
+	})
+}
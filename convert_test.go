@@ -0,0 +1,189 @@
+package gojis_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gojisvm/gojis"
+	"github.com/stretchr/testify/require"
+)
+
+type point struct {
+	X, Y   int
+	Label  string `json:"label,omitempty"`
+	Hidden bool   `json:"-"`
+	secret int
+}
+
+type node struct {
+	Name string
+	Next *node
+}
+
+func TestToValue(t *testing.T) {
+	cyclic := &node{Name: "a"}
+	cyclic.Next = &node{Name: "b", Next: cyclic}
+	shared := []int{1}
+
+	tests := []struct {
+		name     string
+		value    interface{}
+		src      string
+		expected interface{}
+	}{
+		{"nil", nil, `v === null`, true},
+		{"nil pointer", (*point)(nil), `v === null`, true},
+		{"bool", true, `v === true`, true},
+		{"string", "abc", `v + v.length`, "abc3"},
+		{"int", -42, `v`, float64(-42)},
+		{"uint8", uint8(255), `v`, float64(255)},
+		{"int64 precision", int64(1<<53 + 1), `v === 2 ** 53`, true},
+		{"float32", float32(0.5), `v`, 0.5},
+		{"time", time.Date(2020, 1, 2, 3, 4, 5, 6e6, time.UTC), `v.toISOString()`, "2020-01-02T03:04:05.006Z"},
+		{"slice", []string{"a", "b"}, `Array.isArray(v) && v.join()`, "a,b"},
+		{"array", [2]int{1, 2}, `v[0] + v[1]`, float64(3)},
+		{"map", map[int]string{2: "b", 1: "a"}, `Object.keys(v).join() + v[2]`, "1,2b"},
+		{"struct", point{X: 1, Y: 2, Label: "p", Hidden: true}, `JSON.stringify(v)`, `{"X":1,"Y":2,"label":"p"}`},
+		{"pointer", &point{X: 3}, `v.X`, float64(3)},
+		{"nested", map[string]interface{}{"a": []interface{}{1, "x", nil}}, `JSON.stringify(v)`, `{"a":[1,"x",null]}`},
+		{"cycle", cyclic, `v.Next.Next === v && v.Next.Name`, "b"},
+		{"shared slice", [][]int{shared, shared}, `v[0] === v[1]`, true},
+		{"func", func(a, b int) int { return a + b }, `v(2, 3) + v.length`, float64(7)},
+		{"variadic func", func(prefix string, xs ...int) string { return prefix + string(rune('0'+len(xs))) }, `v("n", 1, 2, 3)`, "n3"},
+		{"func results", func() (int, string) { return 1, "a" }, `v().join()`, "1,a"},
+		{"func nil error", func() (int, error) { return 1, nil }, `v()`, float64(1)},
+		{"func error", func() error { return errors.New("failed") }, `try { v(); } catch (e) { e instanceof Error && e.message }`, "failed"},
+		{"func invalid argument", func(int) {}, `try { v(1.5); } catch (e) { e.name }`, "TypeError"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+
+			vm := gojis.NewVM()
+			vm.SetObject("v", vm.ToValue(tt.value))
+			result, err := vm.Eval(tt.src)
+			require.NoError(err)
+			require.Equal(tt.expected, result.Value())
+		})
+	}
+}
+
+func TestToValueUnsupported(t *testing.T) {
+	vm := gojis.NewVM()
+	require.Equal(t, gojis.Undefined, vm.ToValue(make(chan int)))
+	require.Equal(t, gojis.Undefined, vm.ToValue([]interface{}{1, complex(1, 2)}))
+}
+
+func TestExport(t *testing.T) {
+	require := require.New(t)
+
+	vm := gojis.NewVM()
+	eval := func(src string) gojis.Object {
+		result, err := vm.Eval(src)
+		require.NoError(err)
+		return result
+	}
+
+	var p point
+	require.NoError(eval(`({X: 1, Y: 2, label: "p", Hidden: true})`).Export(&p))
+	require.Equal(point{X: 1, Y: 2, Label: "p"}, p)
+
+	var pp *point
+	require.NoError(eval(`({X: 5})`).Export(&pp))
+	require.Equal(&point{X: 5}, pp)
+	require.NoError(eval(`null`).Export(&pp))
+	require.Nil(pp)
+
+	var m map[string]int
+	require.NoError(eval(`({a: 1, b: 2})`).Export(&m))
+	require.Equal(map[string]int{"a": 1, "b": 2}, m)
+
+	var keys map[int]bool
+	require.NoError(eval(`({1: true, 20: false})`).Export(&keys))
+	require.Equal(map[int]bool{1: true, 20: false}, keys)
+
+	var s []string
+	require.NoError(eval(`["a", 1, true]`).Export(&s))
+	require.Equal([]string{"a", "1", "true"}, s)
+
+	var arr [2]int
+	require.NoError(eval(`[1, 2, 3]`).Export(&arr))
+	require.Equal([2]int{1, 2}, arr)
+
+	var tm time.Time
+	require.NoError(eval(`new Date(Date.UTC(2020, 0, 2, 3, 4, 5, 6))`).Export(&tm))
+	require.Equal(time.Date(2020, 1, 2, 3, 4, 5, 6e6, time.UTC), tm)
+
+	var i64 int64
+	require.NoError(eval(`2 ** 53`).Export(&i64))
+	require.Equal(int64(1<<53), i64)
+	require.Error(eval(`1.5`).Export(&i64))
+	require.Error(eval(`2 ** 63`).Export(&i64))
+
+	var u8 uint8
+	require.Error(eval(`256`).Export(&u8))
+	require.Error(eval(`-1`).Export(&u8))
+
+	var any interface{}
+	require.NoError(eval(`({a: [1, "x", null], d: new Date(0)})`).Export(&any))
+	require.Equal(map[string]interface{}{
+		"a": []interface{}{float64(1), "x", nil},
+		"d": time.Unix(0, 0).UTC(),
+	}, any)
+
+	var obj gojis.Object
+	require.NoError(eval(`undefined`).Export(&obj))
+	require.Equal(gojis.Undefined, obj)
+
+	require.Error(eval(`1`).Export(i64))
+	require.Error(eval(`1`).Export(nil))
+}
+
+func TestExportCycle(t *testing.T) {
+	require := require.New(t)
+
+	vm := gojis.NewVM()
+	result, err := vm.Eval(`var a = {Name: "a"}; a.Next = {Name: "b", Next: a}; a`)
+	require.NoError(err)
+
+	var n *node
+	require.NoError(result.Export(&n))
+	require.Equal("b", n.Next.Name)
+	require.True(n.Next.Next == n)
+
+	var m map[string]interface{}
+	require.NoError(result.Export(&m))
+	next := m["Next"].(map[string]interface{})
+	require.Equal(reflect.ValueOf(m).Pointer(), reflect.ValueOf(next["Next"]).Pointer())
+}
+
+func TestExportFunc(t *testing.T) {
+	require := require.New(t)
+
+	vm := gojis.NewVM()
+	result, err := vm.Eval(`(function(a, b) { if (b === 0) throw new RangeError("division by zero"); return [Math.trunc(a / b), a % b]; })`)
+	require.NoError(err)
+
+	var div func(a, b int) (int, int, error)
+	require.NoError(result.Export(&div))
+	q, r, err := div(7, 2)
+	require.NoError(err)
+	require.Equal(3, q)
+	require.Equal(1, r)
+	_, _, err = div(1, 0)
+	require.IsType(&gojis.Exception{}, err)
+	require.Equal("RangeError: division by zero", err.Error())
+
+	var mustDiv func(a, b int) []float64
+	require.NoError(result.Export(&mustDiv))
+	require.Equal([]float64{3, 1}, mustDiv(7, 2))
+	require.Panics(func() { mustDiv(1, 0) })
+
+	var generic interface{}
+	require.NoError(result.Export(&generic))
+	value, err := generic.(func(...interface{}) (interface{}, error))(9, 3)
+	require.NoError(err)
+	require.Equal([]interface{}{float64(3), float64(0)}, value)
+}
@@ -0,0 +1,271 @@
+package gojis
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gojisvm/gojis/internal/parser"
+	"github.com/gojisvm/gojis/internal/runtime"
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+	"github.com/gojisvm/gojis/internal/runtime/realm"
+)
+
+// SyntaxError is the error that is returned for ECMAScript code that
+// contains syntax errors. It holds all syntax errors that were found in
+// the code.
+type SyntaxError struct {
+	err parser.ParserError
+}
+
+// syntaxError returns a *SyntaxError for the given error of the parser.
+// Errors other than parser errors are returned unchanged.
+func syntaxError(err error) error {
+	if perr, ok := err.(parser.ParserError); ok {
+		return &SyntaxError{perr}
+	}
+	return err
+}
+
+// File returns the name of the script that contains the syntax errors.
+func (e *SyntaxError) File() string {
+	return e.err.File()
+}
+
+// Errors returns all syntax errors of the script. Each error describes
+// the position of the error in the source code.
+func (e *SyntaxError) Errors() []error {
+	return e.err.Errors()
+}
+
+func (e *SyntaxError) Error() string {
+	return e.err.Error()
+}
+
+// Exception is the error that is returned if the evaluation of ECMAScript
+// code throws an exception that is not caught. It holds the value that was
+// thrown, and the stack trace of the point where it was thrown.
+type Exception struct {
+	value   Object
+	message string
+	frames  []StackFrame
+}
+
+// StackFrame is a frame of the stack trace of an *Exception, that is, a
+// script or function that was being evaluated when the value was thrown.
+type StackFrame struct {
+	// Function is the name of the function of the frame. It is empty for
+	// script code and anonymous functions.
+	Function string
+	// Script is the name of the script that contains the code of the
+	// frame, like "<eval-1>".
+	Script string
+	// Line and Column are the position of the statement, call or new
+	// expression that was evaluated in the frame. Both start at 1, and are
+	// 0 if the position is not known.
+	Line, Column int
+}
+
+// String returns the frame as it is printed in stack traces, like
+// "inner (<eval-1>:2:20)", or "<eval-1>:4:1" for script code.
+func (f StackFrame) String() string {
+	return runtime.StackFrame(f).String()
+}
+
+// newException creates a new Exception for the given thrown value, whose
+// stack trace is the one recorded by the runtime of the VM.
+func (vm *VM) newException(thrown lang.Value) *Exception {
+	e := new(Exception)
+	e.value = vm.wrap(thrown)
+	e.message = lang.NewThrowError(thrown).Message()
+	for _, frame := range vm.runtime.StackTrace(thrown) {
+		e.frames = append(e.frames, StackFrame(frame))
+	}
+	return e
+}
+
+// exception returns the *Exception for the given error, which was returned
+// by an operation that was called from Go.
+func (vm *VM) exception(err errors.Error) *Exception {
+	return vm.newException(vm.runtime.ErrorValue(err))
+}
+
+// Value returns the value that was thrown.
+func (e *Exception) Value() Object {
+	return e.value
+}
+
+// Message returns the message of the exception. If an error object was
+// thrown, this is the name of the error followed by its message, like
+// "TypeError: foo is not a function".
+func (e *Exception) Message() string {
+	return e.message
+}
+
+// Frames returns the frames of the stack trace of the point where the
+// value was thrown, starting with the innermost frame. Only scripts and
+// ECMAScript functions have frames, built-in functions and host functions
+// don't.
+func (e *Exception) Frames() []StackFrame {
+	return e.frames
+}
+
+// Stack returns the stack trace of the point where the value was thrown,
+// one frame per line, starting with the innermost frame. Each line is
+// indented and starts with "at", like "    at inner (<eval-1>:2:20)".
+func (e *Exception) Stack() string {
+	var b strings.Builder
+	for i, frame := range e.frames {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString("    at " + frame.String())
+	}
+	return b.String()
+}
+
+// Error returns the message of the exception, or "Uncaught exception" if
+// the message is empty, like for thrown objects without name and message.
+func (e *Exception) Error() string {
+	if e.message == "" {
+		return "Uncaught exception"
+	}
+	return e.message
+}
+
+// ErrorKind is the kind of an *Error, which determines the constructor of
+// the error object that is thrown for it.
+type ErrorKind uint8
+
+// Available error kinds.
+const (
+	// ErrorKindError is the kind of errors that are thrown as Error objects.
+	ErrorKindError ErrorKind = iota
+	ErrorKindTypeError
+	ErrorKindRangeError
+	ErrorKindReferenceError
+	ErrorKindSyntaxError
+	ErrorKindEvalError
+	ErrorKindURIError
+)
+
+// errorKindNames are the names of the constructors of the error kinds.
+var errorKindNames = map[ErrorKind]string{
+	ErrorKindError:          "Error",
+	ErrorKindTypeError:      "TypeError",
+	ErrorKindRangeError:     "RangeError",
+	ErrorKindReferenceError: "ReferenceError",
+	ErrorKindSyntaxError:    "SyntaxError",
+	ErrorKindEvalError:      "EvalError",
+	ErrorKindURIError:       "URIError",
+}
+
+// runtimeErrorKinds maps the error kinds, except ErrorKindError, to the
+// kinds of the errors of the runtime.
+var runtimeErrorKinds = map[ErrorKind]errors.ErrorKind{
+	ErrorKindTypeError:      errors.ErrorKindTypeError,
+	ErrorKindRangeError:     errors.ErrorKindRangeError,
+	ErrorKindReferenceError: errors.ErrorKindReferenceError,
+	ErrorKindSyntaxError:    errors.ErrorKindSyntaxError,
+	ErrorKindEvalError:      errors.ErrorKindEvalError,
+	ErrorKindURIError:       errors.ErrorKindURIError,
+}
+
+func (k ErrorKind) String() string {
+	return errorKindNames[k]
+}
+
+// Error is an error that host functions can return, to throw an error
+// object of a specific kind into the calling script.
+type Error struct {
+	Kind    ErrorKind
+	Message string
+}
+
+// NewError returns an *Error, that is thrown as an Error object with the
+// given formatted message.
+func NewError(format string, v ...interface{}) *Error {
+	return &Error{ErrorKindError, fmt.Sprintf(format, v...)}
+}
+
+// NewTypeError returns an *Error, that is thrown as a TypeError object
+// with the given formatted message.
+func NewTypeError(format string, v ...interface{}) *Error {
+	return &Error{ErrorKindTypeError, fmt.Sprintf(format, v...)}
+}
+
+// NewRangeError returns an *Error, that is thrown as a RangeError object
+// with the given formatted message.
+func NewRangeError(format string, v ...interface{}) *Error {
+	return &Error{ErrorKindRangeError, fmt.Sprintf(format, v...)}
+}
+
+// NewReferenceError returns an *Error, that is thrown as a ReferenceError
+// object with the given formatted message.
+func NewReferenceError(format string, v ...interface{}) *Error {
+	return &Error{ErrorKindReferenceError, fmt.Sprintf(format, v...)}
+}
+
+func (e *Error) Error() string {
+	return e.Kind.String() + ": " + e.Message
+}
+
+// throwError returns an error that throws an error object for the given
+// Go error into the calling script. An *Error is thrown as error object of
+// its kind, a *SyntaxError as SyntaxError, and an *Exception as the value
+// that was thrown originally. All other errors are thrown as Error objects
+// with the message of the error. An *InterruptedError,
+// *BudgetExceededError or *HeapExceededError is not thrown, but continues
+// to stop the evaluation of the calling script.
+func (vm *VM) throwError(err error) errors.Error {
+	switch err := err.(type) {
+	case *InterruptedError:
+		panic(&runtime.Interrupted{Reason: err.reason})
+	case *BudgetExceededError:
+		panic(&runtime.BudgetExceeded{Steps: err.steps})
+	case *HeapExceededError:
+		panic(&lang.HeapExceeded{Used: int64(err.usage), Limit: int64(err.limit)})
+	case *Exception:
+		return lang.NewThrowError(vm.unwrap(err.value))
+	case *SyntaxError:
+		return errors.NewSyntaxError(err.Error())
+	case *Error:
+		if kind, ok := runtimeErrorKinds[err.Kind]; ok {
+			return errors.New(kind, err.Message)
+		}
+		return vm.newErrorObject(err.Message)
+	}
+	return vm.newErrorObject(err.Error())
+}
+
+// newErrorObject returns an error that throws a new Error object with the
+// given message.
+func (vm *VM) newErrorObject(msg string) errors.Error {
+	ctor := vm.runtime.Realm().GetIntrinsicObject(realm.IntrinsicNameError).(*lang.Object)
+	errObj, thrown := lang.Construct(ctor, ctor, lang.NewString(msg))
+	if thrown != nil {
+		return thrown
+	}
+	return lang.NewThrowError(errObj)
+}
+
+// recoverHostPanic recovers a panic of a host function, and stores the
+// error that is thrown into the calling script for it in the given error.
+// The panics of the helpers of Args throw their exception, panics with an
+// error throw that error as described by throwError, and panics with other
+// values throw an Error object with the formatted value as message. The
+// panics that stop the evaluation are not recovered.
+func (vm *VM) recoverHostPanic(err *errors.Error) {
+	r := recover()
+	switch r := r.(type) {
+	case nil:
+	case *runtime.Interrupted, *runtime.BudgetExceeded, *lang.HeapExceeded:
+		panic(r)
+	case *thrownError:
+		*err = r.err
+	case error:
+		*err = vm.throwError(r)
+	default:
+		*err = vm.newErrorObject(fmt.Sprint(r))
+	}
+}
@@ -1,6 +1,9 @@
 package main
 
 import (
+	"io/ioutil"
+	"strings"
+
 	"github.com/gojisvm/gojis"
 )
 
@@ -43,15 +46,28 @@ func main() {
 	// go drainAlerts(alerts)
 
 	vm.SetFunction("alert", func(args gojis.Args) gojis.Object {
-		val := args.Get(0)
-		if val.IsUndefined() {
-			panic("No argument provided.")
-		}
-		if val.Type() != gojis.TypeString {
-			panic("Argument has to be a string")
-		}
-		alerts <- val.Value().(string)
+		// throws a TypeError into the script if no argument is provided,
+		// or if it is not a string
+		args.Require(1)
+		alerts <- args.String(0)
 
 		return nil
 	})
+
+	vm.SetFunction("repeat", func(args gojis.Args) gojis.Object {
+		count := args.Int(1)
+		if count < 0 {
+			return args.ThrowRangeError("Invalid count value: %d", count)
+		}
+		return vm.ToValue(strings.Repeat(args.String(0), count))
+	})
+
+	vm.SetFunctionWithError("readConfig", func(args gojis.Args) (gojis.Object, error) {
+		data, err := ioutil.ReadFile(args.String(0))
+		if err != nil {
+			// thrown into the script as an Error object
+			return nil, err
+		}
+		return vm.ToValue(string(data)), nil
+	})
 }
@@ -1,11 +1,15 @@
 module github.com/gojisvm/gojis
 
-go 1.12
+go 1.18
 
 require (
-	github.com/antlr/antlr4 v0.0.0-20190503160947-7c334b114c20
-	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/google/uuid v1.1.1
 	github.com/rs/zerolog v1.14.3
 	github.com/stretchr/testify v1.3.0
+	golang.org/x/text v0.14.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
 )
@@ -0,0 +1,65 @@
+package gojis
+
+import "fmt"
+
+// HeapExceededError is the error that is returned if the evaluation of
+// ECMAScript code is aborted, because it exceeded the heap limit that was
+// set with VM#SetHeapLimit.
+type HeapExceededError struct {
+	usage uint64
+	limit uint64
+}
+
+// Usage returns the number of bytes that the heap used when the evaluation
+// was aborted.
+func (e *HeapExceededError) Usage() uint64 {
+	return e.usage
+}
+
+// Limit returns the heap limit that was exceeded.
+func (e *HeapExceededError) Limit() uint64 {
+	return e.limit
+}
+
+func (e *HeapExceededError) Error() string {
+	return fmt.Sprintf("Evaluation exceeded the heap limit of %d bytes", e.limit)
+}
+
+// SetHeapLimit sets a cumulative allocation budget for the VM. It limits
+// the approximate number of bytes that are allocated for the properties of
+// objects and the entries of Map, Set, WeakMap and WeakSet objects,
+// including the strings that are stored in them, and for array buffers, as
+// returned by HeapUsage.
+//
+// The limit does not bound the memory that the VM uses. Strings that are
+// only held in variables, arguments and temporaries are not accounted, so
+// a script can hold more memory than the limit in them, and objects that
+// are garbage collected are not subtracted from the usage. A VM that
+// evaluates code for a long time, even code that does not leak, will
+// eventually exhaust its budget, so the limit is meant for evaluations of
+// bounded length, in combination with SetBudget.
+//
+// An allocation that would exceed the limit, including the creation of a
+// string that would not fit into the heap, throws a RangeError, which can
+// be caught by the script. Until the script releases memory by deleting
+// properties or entries or replacing their values, it may allocate a small
+// reserve of 64 KiB beyond the limit to handle the error. If it exceeds the
+// reserve, the evaluation is aborted like by VM#Interrupt, and returns a
+// *HeapExceededError.
+func (vm *VM) SetHeapLimit(bytes uint64) {
+	vm.runtime.Realm().Heap().SetLimit(int64(bytes))
+}
+
+// ClearHeapLimit removes the limit that was set with SetHeapLimit.
+func (vm *VM) ClearHeapLimit() {
+	vm.runtime.Realm().Heap().ClearLimit()
+}
+
+// HeapUsage returns the approximate number of bytes that have been
+// allocated for the objects of the VM, as described by SetHeapLimit. This
+// includes the built-in objects. Since the usage does not track the garbage
+// collection, objects that are no longer reachable are still included in
+// it.
+func (vm *VM) HeapUsage() uint64 {
+	return uint64(vm.runtime.Realm().Heap().Used())
+}
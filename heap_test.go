@@ -0,0 +1,155 @@
+package gojis_test
+
+import (
+	"testing"
+
+	"github.com/gojisvm/gojis"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeapUsage(t *testing.T) {
+	for _, e := range engines {
+		t.Run(e.name, func(t *testing.T) {
+			require := require.New(t)
+
+			vm := gojis.NewVM()
+			vm.SetEngine(e.engine)
+			_, err := vm.Eval(`var a = [], i;`)
+			require.NoError(err)
+
+			before := vm.HeapUsage()
+			require.NotZero(before)
+			_, err = vm.Eval(`for (i = 0; i < 1000; i++) a.push("x".repeat(1000));`)
+			require.NoError(err)
+			require.True(vm.HeapUsage()-before > 1000*2000)
+
+			// deleted properties are released
+			_, err = vm.Eval(`a.length = 0;`)
+			require.NoError(err)
+			require.True(vm.HeapUsage()-before < 100)
+		})
+	}
+}
+
+func TestHeapUsageCollections(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   string
+		add     string
+		release string
+	}{
+		{"map delete", `var c = new Map();`, `for (i = 0; i < 1000; i++) c.set(i, "x".repeat(1000));`, `for (i = 0; i < 1000; i++) c.delete(i);`},
+		{"map clear", `var c = new Map();`, `for (i = 0; i < 1000; i++) c.set(i, "x".repeat(1000));`, `c.clear();`},
+		{"map replace", `var c = new Map();`, `for (i = 0; i < 1000; i++) c.set(i, "x".repeat(1000));`, `for (i = 0; i < 1000; i++) c.set(i, 0); c.clear();`},
+		{"set delete", `var c = new Set();`, `for (i = 0; i < 1000; i++) c.add("x".repeat(1000) + i);`, `for (i = 0; i < 1000; i++) c.delete("x".repeat(1000) + i);`},
+		{"set clear", `var c = new Set();`, `for (i = 0; i < 1000; i++) c.add("x".repeat(1000) + i);`, `c.clear();`},
+		{"weak map delete", `var c = new WeakMap();`, `for (i = 0; i < 1000; i++) c.set(k[i], "x".repeat(1000));`, `for (i = 0; i < 1000; i++) c.delete(k[i]);`},
+	}
+	for _, e := range engines {
+		for _, tt := range tests {
+			t.Run(e.name+"/"+tt.name, func(t *testing.T) {
+				require := require.New(t)
+
+				vm := gojis.NewVM()
+				vm.SetEngine(e.engine)
+				_, err := vm.Eval(`var i, k = []; for (i = 0; i < 1000; i++) k.push({});` + tt.setup)
+				require.NoError(err)
+
+				before := vm.HeapUsage()
+				_, err = vm.Eval(tt.add)
+				require.NoError(err)
+				require.True(vm.HeapUsage()-before > 1000*2000)
+
+				// deleted entries are released
+				_, err = vm.Eval(tt.release)
+				require.NoError(err)
+				require.True(vm.HeapUsage()-before < 100)
+			})
+		}
+	}
+}
+
+func TestHeapLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		message string
+	}{
+		{"push", `var a = []; while (true) a.push("x".repeat(10000));`, "RangeError: Out of memory"},
+		{"properties", `var o = {}; for (var i = 0; ; i++) o["p" + i] = i;`, "RangeError: Out of memory"},
+		{"null prototype", `var o = Object.create(null); for (var i = 0; ; i++) o[i] = "x".repeat(1000);`, "RangeError: Out of memory"},
+		{"map", `let m = new Map(), i = 0; while (1) m.set(i++, "x".repeat(1e5));`, "RangeError: Out of memory"},
+		{"map keys", `let m = new Map(), i = 0; while (1) m.set("x".repeat(1e4) + i++, i);`, "RangeError: Out of memory"},
+		{"set", `let s = new Set(), i = 0; while (1) s.add("x".repeat(1e4) + i++);`, "RangeError: Out of memory"},
+		{"weak map", `let m = new WeakMap(); while (1) m.set({}, "x".repeat(1e4));`, "RangeError: Out of memory"},
+		{"weak set", `let s = new WeakSet(); while (1) s.add({});`, "RangeError: Out of memory"},
+		{"array buffer", `new ArrayBuffer(1 << 21);`, "RangeError: Out of memory"},
+		{"repeat", `"x".repeat(1 << 20);`, "RangeError: Out of memory"},
+		{"concatenation", `var s = "x"; while (true) s += s;`, "RangeError: Out of memory"},
+		{"template", `var s = "x"; while (true) s = ` + "`${s}${s}`;", "RangeError: Out of memory"},
+	}
+	for _, e := range engines {
+		for _, tt := range tests {
+			t.Run(e.name+"/"+tt.name, func(t *testing.T) {
+				require := require.New(t)
+
+				vm := gojis.NewVM()
+				vm.SetEngine(e.engine)
+				vm.SetHeapLimit(vm.HeapUsage() + 1<<20)
+				_, err := vm.Eval(tt.src)
+				require.IsType(&gojis.Exception{}, err)
+				require.Equal(tt.message, err.Error())
+			})
+		}
+	}
+}
+
+func TestHeapExceeded(t *testing.T) {
+	for _, e := range engines {
+		t.Run(e.name, func(t *testing.T) {
+			require := require.New(t)
+
+			vm := gojis.NewVM()
+			vm.SetEngine(e.engine)
+			limit := vm.HeapUsage() + 1<<20
+			vm.SetHeapLimit(limit)
+
+			// the RangeError can be caught, and memory can be released
+			result, err := vm.Eval(`
+var a = [], errors = 0;
+for (var k = 0; k < 3; k++) {
+	try {
+		while (true) a.push("x".repeat(10000));
+	} catch (e) {
+		if (e instanceof RangeError) errors++;
+		a.length = 0;
+	}
+}
+errors
+			`)
+			require.NoError(err)
+			require.Equal(3.0, result.Value())
+
+			// the evaluation is aborted if the script keeps allocating
+			_, err = vm.Eval(`
+var done = false;
+try {
+	while (true) a.push("x".repeat(10000));
+} catch (e) {
+	while (true) a.push("x".repeat(10000));
+} finally {
+	done = true;
+}
+			`)
+			require.IsType(&gojis.HeapExceededError{}, err)
+			require.Equal(limit, err.(*gojis.HeapExceededError).Limit())
+			require.True(err.(*gojis.HeapExceededError).Usage() > limit)
+			require.Equal(false, vm.Lookup("done").Value())
+
+			vm.ClearHeapLimit()
+			result, err = vm.Eval(`a.push("x"); a.length = 0; "ok"`)
+			require.NoError(err)
+			require.Equal("ok", result.Value())
+		})
+	}
+}
@@ -0,0 +1,312 @@
+package gojis
+
+import (
+	"fmt"
+	"reflect"
+	"unicode"
+
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+	"github.com/gojisvm/gojis/internal/runtime/realm"
+)
+
+// slotHostValue is the internal slot of host objects, that holds the
+// hostValue of the struct that the object is bound to.
+var slotHostValue = lang.NewStringKey("HostValue")
+
+// newHostObjectMethods returns the internal methods of host objects,
+// which are exotic objects that are bound to a Go struct, as described by
+// VM#ToValue. The properties of the fields of the struct are data
+// properties, that are writable unless the field is readonly, enumerable
+// and not configurable. All other properties are ordinary properties.
+func newHostObjectMethods() *lang.ExoticMethods {
+	return &lang.ExoticMethods{
+		GetOwnProperty:    hostGetOwnProperty,
+		DefineOwnProperty: hostDefineOwnProperty,
+		Get:               hostGet,
+		Set:               hostSet,
+		Delete:            hostDelete,
+		OwnPropertyKeys:   hostOwnPropertyKeys,
+	}
+}
+
+// hostType holds the fields and the prototype of the host objects of a
+// struct type in a VM.
+type hostType struct {
+	fields []structField
+	byName map[string]*structField
+	proto  *lang.Object
+}
+
+// hostValue is the value of the HostValue slot of a host object.
+type hostValue struct {
+	vm  *VM
+	typ *hostType
+	ptr reflect.Value // pointer to the struct
+}
+
+// Type returns lang.TypeInternal.
+func (*hostValue) Type() lang.Type { return lang.TypeInternal }
+
+// Value returns the hostValue itself.
+func (h *hostValue) Value() interface{} { return h }
+
+// hostObject returns the host object that is bound to the struct that the
+// given pointer points to. The same host object is returned for the same
+// struct until it is released with ReleaseHostObject.
+func (vm *VM) hostObject(ptr reflect.Value) *lang.Object {
+	key := hostKey(ptr)
+	if o, ok := vm.hostObjects[key]; ok {
+		return o
+	}
+
+	o := vm.newHostObject(ptr)
+	vm.hostObjects[key] = o
+	return o
+}
+
+// newHostObject returns a new host object that is bound to the struct that
+// the given pointer points to. Unlike hostObject, the object is not kept by
+// the VM, which is used for copies of structs that can not be reached from
+// Go anyway.
+func (vm *VM) newHostObject(ptr reflect.Value) *lang.Object {
+	typ := vm.hostType(ptr.Type().Elem())
+	o := lang.ObjectCreate(typ.proto, slotHostValue)
+	o.SetSlot(slotHostValue, &hostValue{vm: vm, typ: typ, ptr: ptr})
+	o.Exotic = vm.hostMethods
+	return o
+}
+
+// hostKey returns the key of the host object of the struct that the given
+// pointer points to.
+func hostKey(ptr reflect.Value) seenKey {
+	return seenKey{ptr: ptr.Pointer(), typ: ptr.Type()}
+}
+
+// ReleaseHostObject releases the host object that is bound to the struct
+// that the given pointer points to, as well as the host objects of the
+// struct fields of the struct and of the structs that were reached through
+// its fields, e.g. through pointers, slices or maps, recursively. The VM
+// keeps the host object of every struct that was converted by ToValue, so
+// that the struct is always bound to the same object, which keeps the
+// struct alive for the lifetime of the VM. After it is released, the
+// struct can be garbage collected once no script references its host
+// object anymore. Objects that are still referenced stay bound to the
+// struct, but converting the pointer again binds the struct to a new host
+// object. Values other than non-nil pointers to structs are ignored.
+func (vm *VM) ReleaseHostObject(v interface{}) {
+	ptr := reflect.ValueOf(v)
+	if ptr.Kind() != reflect.Ptr || ptr.IsNil() || ptr.Elem().Kind() != reflect.Struct {
+		return
+	}
+
+	pending := []seenKey{hostKey(ptr)}
+	pending = appendStructFieldKeys(pending, ptr.Pointer(), ptr.Elem().Type())
+	for len(pending) > 0 {
+		key := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		delete(vm.hostObjects, key)
+		// the children are deleted before they are visited, so that
+		// cycles end
+		for child := range vm.hostChildren[key] {
+			pending = append(pending, child)
+		}
+		delete(vm.hostChildren, key)
+	}
+}
+
+// appendStructFieldKeys appends the keys of the host objects of the struct
+// fields of the struct of the given type at the given address to keys,
+// including the struct fields of these fields.
+func appendStructFieldKeys(keys []seenKey, addr uintptr, typ reflect.Type) []seenKey {
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.Type.Kind() != reflect.Struct {
+			continue
+		}
+		keys = append(keys, seenKey{ptr: addr + f.Offset, typ: reflect.PtrTo(f.Type)})
+		keys = appendStructFieldKeys(keys, addr+f.Offset, f.Type)
+	}
+	return keys
+}
+
+// hostType returns the hostType of the given struct type, which is created
+// when it is needed for the first time. The prototype of the host objects
+// holds a function for each exported method of the pointer type, and has
+// the name of the struct type as @@toStringTag.
+func (vm *VM) hostType(typ reflect.Type) *hostType {
+	if t, ok := vm.hostTypes[typ]; ok {
+		return t
+	}
+
+	r := vm.runtime.Realm()
+	t := new(hostType)
+	t.fields = structFields(typ)
+	t.byName = make(map[string]*structField, len(t.fields))
+	for i := range t.fields {
+		t.byName[t.fields[i].name] = &t.fields[i]
+	}
+	t.proto = lang.ObjectCreate(r.GetIntrinsicObject(realm.IntrinsicNameObjectPrototype))
+	if typ.Name() != "" {
+		_, _ = t.proto.DefineOwnProperty(lang.NewStringOrSymbol(lang.SymbolToStringTag), lang.NewDataProperty(lang.NewString(typ.Name()), lang.False, lang.False, lang.True))
+	}
+
+	ptrType := reflect.PtrTo(typ)
+	for i := 0; i < ptrType.NumMethod(); i++ {
+		i, method := i, ptrType.Method(i)
+		name := methodName(method.Name)
+		fn := r.NewFunction(name, funcLength(method.Type)-1, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+			h, ok := hostData(this)
+			if !ok || h.ptr.Type() != ptrType {
+				return nil, errors.NewTypeError(fmt.Sprintf("Method %v.%v called on incompatible receiver", typ.Name(), name))
+			}
+			return vm.callFunc(h.ptr.Method(i), args)
+		})
+		_, _ = t.proto.DefineOwnProperty(lang.NewStringKey(name), lang.NewDataProperty(fn, lang.True, lang.False, lang.True))
+	}
+
+	vm.hostTypes[typ] = t
+	return t
+}
+
+// methodName returns the name of the function of the Go method with the
+// given name, whose first word is in lower case. The first word of a name
+// that starts with several upper case letters ends before the last of
+// them, if it is followed by a lower case letter.
+func methodName(name string) string {
+	runes := []rune(name)
+	for i := 0; i < len(runes) && unicode.IsUpper(runes[i]); i++ {
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
+
+// hostData returns the hostValue of the given value, if it is a host
+// object.
+func hostData(v lang.Value) (*hostValue, bool) {
+	o, ok := v.(*lang.Object)
+	if !ok || !o.HasSlot(slotHostValue) {
+		return nil, false
+	}
+	return o.GetSlot(slotHostValue).(*hostValue), true
+}
+
+// hostField returns the hostValue of the given host object, and the field
+// of the struct that the given property key names, if there is one.
+func hostField(o *lang.Object, p lang.StringOrSymbol) (*hostValue, *structField, bool) {
+	h, _ := hostData(o)
+	if p.Type() != lang.TypeString {
+		return h, nil, false
+	}
+	f, ok := h.typ.byName[p.String().String()]
+	return h, f, ok
+}
+
+// get returns the converted value of the given field. The structs that
+// are reached through the field are released together with the struct of
+// the host value, as long as the VM binds it to its host object.
+func (h *hostValue) get(f *structField) (lang.Value, errors.Error) {
+	var parent *seenKey
+	key := hostKey(h.ptr)
+	if o, ok := h.vm.hostObjects[key]; ok && o.GetSlot(slotHostValue) == lang.Value(h) {
+		parent = &key
+	}
+	v, err := h.vm.convertField(parent, h.ptr.Elem().FieldByIndex(f.index))
+	if err != nil {
+		return nil, errors.NewTypeError(err.Error())
+	}
+	return v, nil
+}
+
+// set exports the given value into the given field. The field is left
+// unchanged if the value can not be exported.
+func (h *hostValue) set(f *structField, v lang.Value) errors.Error {
+	field := h.ptr.Elem().FieldByIndex(f.index)
+	value := reflect.New(field.Type())
+	if err := h.vm.export(v, value.Interface()); err != nil {
+		return errors.NewTypeError(fmt.Sprintf("Cannot set property %v: %v", f.name, err))
+	}
+	field.Set(value.Elem())
+	return nil
+}
+
+func hostGetOwnProperty(o *lang.Object, p lang.StringOrSymbol) *lang.Property {
+	h, f, ok := hostField(o, p)
+	if !ok {
+		return o.OrdinaryGetOwnProperty(p)
+	}
+	v, err := h.get(f)
+	if err != nil {
+		v = lang.Undefined
+	}
+	return lang.NewDataProperty(v, lang.Boolean(!f.readonly), lang.True, lang.False)
+}
+
+func hostDefineOwnProperty(o *lang.Object, p lang.StringOrSymbol, desc *lang.Property) (lang.Boolean, errors.Error) {
+	h, f, ok := hostField(o, p)
+	if !ok {
+		return o.OrdinaryDefineOwnProperty(p, desc), nil
+	}
+
+	if bool(desc.IsAccessorDescriptor()) ||
+		desc.Has(lang.FieldNameConfigurable) && bool(desc.Configurable()) ||
+		desc.Has(lang.FieldNameEnumerable) && !bool(desc.Enumerable()) ||
+		desc.Has(lang.FieldNameWritable) && bool(desc.Writable()) == f.readonly {
+		return lang.False, nil
+	}
+	if !desc.Has(lang.FieldNameValue) {
+		return lang.True, nil
+	}
+	if f.readonly {
+		current, err := h.get(f)
+		if err != nil {
+			return lang.False, err
+		}
+		return lang.SameValue(current, desc.Value()), nil
+	}
+	if err := h.set(f, desc.Value()); err != nil {
+		return lang.False, err
+	}
+	return lang.True, nil
+}
+
+func hostGet(o *lang.Object, p lang.StringOrSymbol, receiver lang.Value) (lang.Value, errors.Error) {
+	h, f, ok := hostField(o, p)
+	if !ok {
+		return o.OrdinaryGet(p, receiver)
+	}
+	return h.get(f)
+}
+
+func hostSet(o *lang.Object, p lang.StringOrSymbol, v, receiver lang.Value) (lang.Boolean, errors.Error) {
+	h, f, ok := hostField(o, p)
+	if !ok || receiver != lang.Value(o) {
+		return o.OrdinarySet(p, v, receiver)
+	}
+	if f.readonly {
+		return lang.False, nil
+	}
+	if err := h.set(f, v); err != nil {
+		return lang.False, err
+	}
+	return lang.True, nil
+}
+
+func hostDelete(o *lang.Object, p lang.StringOrSymbol) lang.Boolean {
+	if _, _, ok := hostField(o, p); ok {
+		return lang.False
+	}
+	return o.OrdinaryDelete(p)
+}
+
+func hostOwnPropertyKeys(o *lang.Object) []lang.StringOrSymbol {
+	h, _ := hostData(o)
+	keys := make([]lang.StringOrSymbol, 0, len(h.typ.fields))
+	for _, f := range h.typ.fields {
+		keys = append(keys, lang.NewStringKey(f.name))
+	}
+	return append(keys, o.OrdinaryOwnPropertyKeys()...)
+}
@@ -0,0 +1,114 @@
+package gojis_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gojisvm/gojis"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetFunctionWithError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{"go error", errors.New("failed"), "Error: failed"},
+		{"error", gojis.NewError("failed %d", 1), "Error: failed 1"},
+		{"type error", gojis.NewTypeError("not a %v", "string"), "TypeError: not a string"},
+		{"range error", gojis.NewRangeError("too large"), "RangeError: too large"},
+		{"reference error", gojis.NewReferenceError("x is not defined"), "ReferenceError: x is not defined"},
+		{"uri error", &gojis.Error{Kind: gojis.ErrorKindURIError, Message: "malformed"}, "URIError: malformed"},
+		{"eval error", &gojis.Error{Kind: gojis.ErrorKindEvalError, Message: "failed"}, "EvalError: failed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+
+			vm := gojis.NewVM()
+			vm.SetFunctionWithError("f", func(gojis.Args) (gojis.Object, error) {
+				return nil, tt.err
+			})
+			result, err := vm.Eval(`try { f(); "not thrown"; } catch (e) { String(e); }`)
+			require.NoError(err)
+			require.Equal(tt.expected, result.Value())
+		})
+	}
+}
+
+func TestSetFunctionWithErrorResult(t *testing.T) {
+	require := require.New(t)
+
+	vm := gojis.NewVM()
+	vm.SetFunctionWithError("f", func(args gojis.Args) (gojis.Object, error) {
+		return vm.ToValue(args.Len()), nil
+	})
+	result, err := vm.Eval(`f(1, 2)`)
+	require.NoError(err)
+	require.Equal(float64(2), result.Value())
+}
+
+func TestRethrowException(t *testing.T) {
+	require := require.New(t)
+
+	vm := gojis.NewVM()
+	vm.SetFunctionWithError("call", func(args gojis.Args) (gojis.Object, error) {
+		return args.Func(0).CallWithArgs()
+	})
+	vm.SetFunctionWithError("eval", func(args gojis.Args) (gojis.Object, error) {
+		return vm.Eval(args.String(0))
+	})
+	result, err := vm.Eval(`
+var thrown = {};
+var results = [];
+try { eval("throw thrown"); } catch (e) { results.push(e === thrown); }
+try { eval("null.x"); } catch (e) { results.push(e instanceof TypeError); }
+try { eval("var = 1"); } catch (e) { results.push(e instanceof SyntaxError); }
+results.join();
+	`)
+	require.NoError(err)
+	require.Equal("true,true,true", result.Value())
+}
+
+func TestHostPanic(t *testing.T) {
+	tests := []struct {
+		name     string
+		fn       interface{}
+		expected string
+	}{
+		{"panic string", func(gojis.Args) gojis.Object { panic("boom") }, "Error: boom"},
+		{"panic error", func(gojis.Args) gojis.Object { panic(gojis.NewRangeError("out of range")) }, "RangeError: out of range"},
+		{"runtime error", func(gojis.Args) gojis.Object {
+			var m map[string]int
+			m["x"] = 1
+			return nil
+		}, "Error: assignment to entry in nil map"},
+		{"converted function", func() int { panic("converted") }, "Error: converted"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+
+			vm := gojis.NewVM()
+			if fn, ok := tt.fn.(func(gojis.Args) gojis.Object); ok {
+				vm.SetFunction("f", fn)
+			} else {
+				vm.SetObject("f", vm.ToValue(tt.fn))
+			}
+			vm.SetObject("obj", vm.ToValue(&panicky{}))
+			result, err := vm.Eval(`try { f(); "not thrown"; } catch (e) { String(e); }`)
+			require.NoError(err)
+			require.Equal(tt.expected, result.Value())
+
+			// the VM is still usable
+			result, err = vm.Eval(`try { obj.method(); } catch (e) { String(e); }`)
+			require.NoError(err)
+			require.Equal("Error: method", result.Value())
+		})
+	}
+}
+
+type panicky struct{}
+
+func (*panicky) Method() { panic("method") }
@@ -0,0 +1,205 @@
+package gojis_test
+
+import (
+	"errors"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gojisvm/gojis"
+	"github.com/stretchr/testify/require"
+)
+
+type customer struct {
+	Name string `json:"name"`
+}
+
+type lineItem struct {
+	Name  string  `json:"name"`
+	Price float64 `json:"price"`
+}
+
+type Order struct {
+	ID       string     `js:"id,readonly"`
+	Total    float64    `json:"total"`
+	Customer customer   `json:"customer"`
+	Items    []lineItem `js:"items" json:"-"`
+	Note     string     `js:"-"`
+	internal int
+}
+
+func (o *Order) ApplyDiscount(percent float64) float64 {
+	o.Total -= o.Total * percent / 100
+	return o.Total
+}
+
+func (o Order) ItemCount() int {
+	return len(o.Items)
+}
+
+func (o *Order) Validate() error {
+	if o.Total < 0 {
+		return errors.New("negative total")
+	}
+	return nil
+}
+
+func TestHostObject(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		expected interface{}
+		check    func(*require.Assertions, *Order)
+	}{
+		{"read field", `order.total + order.id`, "100A-1", nil},
+		{"write field", `order.total = 50`, float64(50), func(require *require.Assertions, o *Order) {
+			require.Equal(float64(50), o.Total)
+		}},
+		{"call method", `order.applyDiscount(5)`, float64(95), func(require *require.Assertions, o *Order) {
+			require.Equal(float64(95), o.Total)
+		}},
+		{"value receiver method", `order.itemCount()`, float64(1), nil},
+		{"method error", `order.total = -1; try { order.validate(); } catch (e) { e.message }`, "negative total", nil},
+		{"incompatible receiver", `try { order.validate.call({}); } catch (e) { e.name }`, "TypeError", nil},
+		{"readonly field", `order.id = "B"; order.id`, "A-1", func(require *require.Assertions, o *Order) {
+			require.Equal("A-1", o.ID)
+		}},
+		{"readonly field in strict mode", `"use strict"; try { order.id = "B"; } catch (e) { e.name }`, "TypeError", nil},
+		{"nested struct", `order.customer.name = "Bob"; order.customer === order.customer`, true, func(require *require.Assertions, o *Order) {
+			require.Equal("Bob", o.Customer.Name)
+		}},
+		{"slice field", `order.items = order.items.concat([{name: "b", price: 2}]); order.items.length`, float64(2), func(require *require.Assertions, o *Order) {
+			require.Equal([]lineItem{{"a", 1}, {"b", 2}}, o.Items)
+		}},
+		{"invalid write", `try { order.items = 5; } catch (e) { e.name }`, "TypeError", func(require *require.Assertions, o *Order) {
+			require.Len(o.Items, 1)
+		}},
+		{"keys", `Object.keys(order).join()`, "id,total,customer,items", nil},
+		{"json", `JSON.stringify(order.customer)`, `{"name":"Alice"}`, nil},
+		{"delete field", `delete order.total`, false, nil},
+		{"define field", `Object.defineProperty(order, "total", {value: 10}); order.total`, float64(10), func(require *require.Assertions, o *Order) {
+			require.Equal(float64(10), o.Total)
+		}},
+		{"define accessor", `try { Object.defineProperty(order, "total", {get() {}}); } catch (e) { e.name }`, "TypeError", nil},
+		{"descriptor", `var d = Object.getOwnPropertyDescriptor(order, "id"); [d.writable, d.enumerable, d.configurable].join()`, "false,true,false", nil},
+		{"other properties", `order.extra = 1; order.extra + ("Note" in order)`, float64(1), nil},
+		{"to string tag", `Object.prototype.toString.call(order)`, "[object Order]", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+
+			order := &Order{ID: "A-1", Total: 100, Customer: customer{"Alice"}, Items: []lineItem{{"a", 1}}}
+			vm := gojis.NewVM()
+			vm.SetObject("order", vm.ToValue(order))
+			result, err := vm.Eval(tt.src)
+			require.NoError(err)
+			require.Equal(tt.expected, result.Value())
+			if tt.check != nil {
+				tt.check(require, order)
+			}
+		})
+	}
+}
+
+func TestHostObjectIdentity(t *testing.T) {
+	require := require.New(t)
+
+	order := &Order{ID: "A-1"}
+	vm := gojis.NewVM()
+	vm.SetObject("a", vm.ToValue(order))
+	vm.SetObject("b", vm.ToValue(order))
+	result, err := vm.Eval(`a === b && a`)
+	require.NoError(err)
+
+	var exported *Order
+	require.NoError(result.Export(&exported))
+	require.True(exported == order)
+
+	var any interface{}
+	require.NoError(result.Export(&any))
+	require.True(any == order)
+}
+
+func TestReleaseHostObject(t *testing.T) {
+	require := require.New(t)
+
+	order := &Order{ID: "A-1", Customer: customer{Name: "Jane"}}
+	vm := gojis.NewVM()
+	vm.SetObject("a", vm.ToValue(order))
+	vm.SetObject("c", vm.ToValue(&order.Customer))
+	result, err := vm.Eval(`a.customer === c`)
+	require.NoError(err)
+	require.Equal(true, result.Value())
+
+	vm.ReleaseHostObject(order)
+	vm.ReleaseHostObject(nil)
+	vm.ReleaseHostObject(order.Customer)
+	vm.SetObject("b", vm.ToValue(order))
+	vm.SetObject("d", vm.ToValue(&order.Customer))
+
+	// the fields of released objects are bound to the new objects
+	result, err = vm.Eval(`a !== b && c !== d && a.customer === d && b.customer === d`)
+	require.NoError(err)
+	require.Equal(true, result.Value())
+
+	// released objects stay bound to the struct
+	_, err = vm.Eval(`a.total = 10; c.name = "John"`)
+	require.NoError(err)
+	require.Equal(10.0, order.Total)
+	require.Equal("John", order.Customer.Name)
+	result, err = vm.Eval(`b.total + d.name`)
+	require.NoError(err)
+	require.Equal("10John", result.Value())
+}
+
+func TestReleaseHostObjectChildren(t *testing.T) {
+	require := require.New(t)
+
+	first := &node{Name: "a"}
+	first.Next = &node{Name: "b", Next: &node{Name: "c", Next: first}}
+	order := &Order{ID: "A-1"}
+	vm := gojis.NewVM()
+	vm.SetObject("a", vm.ToValue(first))
+	vm.SetObject("c", vm.ToValue(&order.Customer))
+	result, err := vm.Eval(`var b = a.Next; b.Next.Next === a && b.Next.Name`)
+	require.NoError(err)
+	require.Equal("c", result.Value())
+
+	// the structs reached through pointers and struct fields are released
+	// as well, even if they form a cycle
+	vm.ReleaseHostObject(first)
+	vm.ReleaseHostObject(order)
+	vm.SetObject("b2", vm.ToValue(first.Next))
+	vm.SetObject("c2", vm.ToValue(&order.Customer))
+	result, err = vm.Eval(`b !== b2 && c !== c2 && b2.Name`)
+	require.NoError(err)
+	require.Equal("b", result.Value())
+}
+
+func TestReleaseHostObjectGarbage(t *testing.T) {
+	require := require.New(t)
+
+	vm := gojis.NewVM()
+	var finalized int64
+	const count = 20000
+	for i := 0; i < count; i++ {
+		n := &node{Next: &node{}}
+		runtime.SetFinalizer(n.Next, func(*node) { atomic.AddInt64(&finalized, 1) })
+		vm.SetObject("n", vm.ToValue(n))
+		_, err := vm.Eval(`n.Next.Name`)
+		require.NoError(err)
+		vm.ReleaseHostObject(n)
+	}
+	vm.SetObject("n", gojis.Null)
+
+	for i := 0; i < 10 && atomic.LoadInt64(&finalized) < count/2; i++ {
+		runtime.GC()
+		time.Sleep(10 * time.Millisecond)
+	}
+	runtime.KeepAlive(vm)
+	// the structs that were reached through released structs are not kept
+	// by the VM
+	require.True(atomic.LoadInt64(&finalized) >= count/2, "only %v of %v structs were collected", finalized, count)
+}
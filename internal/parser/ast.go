@@ -0,0 +1,46 @@
+package parser
+
+import "github.com/gojisvm/gojis/internal/parser/ast"
+
+// Ast is the result of parsing one or more files. It holds one root
+// per parsed file, which can be retrieved by the name the file was
+// parsed with. Every root is an *ast.Program.
+type Ast struct {
+	names []string
+	roots map[string]*ast.Program
+}
+
+// NewEmptyAst creates a new Ast that does not hold any roots.
+func NewEmptyAst() *Ast {
+	a := new(Ast)
+	a.names = make([]string, 0)
+	a.roots = make(map[string]*ast.Program)
+	return a
+}
+
+// AddRoot adds the given tree as root for the file with the given name.
+// If a root with the given name already exists, it will be replaced.
+func (a *Ast) AddRoot(name string, root *ast.Program) {
+	if _, ok := a.roots[name]; !ok {
+		a.names = append(a.names, name)
+	}
+	a.roots[name] = root
+}
+
+// Root returns the root of the file with the given name, and a flag
+// indicating whether such a root exists.
+func (a *Ast) Root(name string) (*ast.Program, bool) {
+	root, ok := a.roots[name]
+	return root, ok
+}
+
+// Names returns the names of all roots of this Ast, in the order
+// they were added.
+func (a *Ast) Names() []string {
+	return append([]string(nil), a.names...)
+}
+
+// Len returns the amount of roots in this Ast.
+func (a *Ast) Len() int {
+	return len(a.names)
+}
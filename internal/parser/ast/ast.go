@@ -0,0 +1,112 @@
+// Package ast declares the types used to represent the syntax tree of
+// ECMAScript source code. The node types are modelled after ESTree
+// (https://github.com/estree/estree), so the name of every node type,
+// as returned by Node#Type, as well as the names of the node's fields
+// correspond to the ESTree specification.
+//
+// Every node carries its location in the source code, that is, the byte
+// offsets of the first character of the node and the first character
+// after the node, as well as the lines and columns of these positions.
+//
+// Nodes are identified by their pointer, so the same node will always
+// be represented by the same pointer. This allows using nodes as keys,
+// as it is required for a realm's template map, which is keyed by parse
+// nodes.
+//
+// Interfaces like Statement, Expression or Pattern are used to group node
+// types. A node type can be part of multiple groups, e.g. an Identifier is
+// an Expression as well as a Pattern.
+package ast
+
+import "fmt"
+
+// Position describes a position in the source code.
+// The offset is a byte offset into the source code, starting at 0.
+// Lines start at 1, columns start at 0, just like in ESTree. Columns
+// are counted in characters, not in bytes.
+type Position struct {
+	Offset int
+	Line   int
+	Column int
+}
+
+// IsValid is used to determine whether the position has been set. The
+// zero value of Position is not a valid position, since lines start at 1.
+func (p Position) IsValid() bool {
+	return p.Line > 0
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("%v:%v", p.Line, p.Column)
+}
+
+// Location is the location of a node in the source code. Start is the
+// position of the first character of the node, End the position of the
+// first character after the node.
+type Location struct {
+	Start Position
+	End   Position
+}
+
+// Loc returns the location itself. Embedding Location in a node type
+// makes that type implement the Loc method of Node.
+func (l *Location) Loc() *Location {
+	return l
+}
+
+// Node is implemented by all node types.
+type Node interface {
+	// Type returns the ESTree type name of the node, e.g. "Identifier".
+	Type() string
+	// Loc returns the location of the node in the source code.
+	Loc() *Location
+}
+
+// Statement is implemented by all statement and declaration node types.
+type Statement interface {
+	Node
+	statementNode()
+}
+
+// Expression is implemented by all expression node types.
+type Expression interface {
+	Node
+	expressionNode()
+}
+
+// Pattern is implemented by all node types that can be the target of
+// a binding or an assignment, i.e. identifiers, member expressions and
+// destructuring patterns.
+type Pattern interface {
+	Node
+	patternNode()
+}
+
+// Program is the root of every syntax tree.
+type Program struct {
+	Location
+	// SourceType is either "script" or "module".
+	SourceType string
+	Body       []Statement
+	// Strict is true if the program is strict mode code.
+	Strict bool
+
+	// The following fields are only set for modules. They hold the module
+	// specifiers of all imported modules in source code order, without
+	// duplicates, and the import and export entries of the module, as
+	// specified in 15.2.1.16.1.
+	RequestedModules      []string
+	ImportEntries         []*ImportEntry
+	LocalExportEntries    []*ExportEntry
+	IndirectExportEntries []*ExportEntry
+	StarExportEntries     []*ExportEntry
+}
+
+// Available values for Program#SourceType.
+const (
+	SourceTypeScript = "script"
+	SourceTypeModule = "module"
+)
+
+// Type returns "Program".
+func (*Program) Type() string { return "Program" }
@@ -0,0 +1,109 @@
+package ast
+
+// Available values for VariableDeclaration#Kind.
+const (
+	VariableKindVar   = "var"
+	VariableKindLet   = "let"
+	VariableKindConst = "const"
+)
+
+// Available values for MethodDefinition#Kind and Property#Kind.
+const (
+	KindInit        = "init"
+	KindGet         = "get"
+	KindSet         = "set"
+	KindMethod      = "method"
+	KindConstructor = "constructor"
+)
+
+// Function holds the fields that all function node types have in common.
+// Body is a *BlockStatement, except for arrow functions with a concise body,
+// where Body is an Expression.
+type Function struct {
+	ID        *Identifier
+	Params    []Pattern
+	Body      Node
+	Generator bool
+	Async     bool
+	// Strict is true if the function is strict mode code, either because
+	// it is contained in strict mode code, or because its body contains a
+	// "use strict" directive.
+	Strict bool
+}
+
+// FunctionDeclaration is a function declaration. ID is only nil for a
+// function declaration that is the default export of a module.
+type FunctionDeclaration struct {
+	Location
+	Function
+}
+
+// VariableDeclaration is a var, let or const declaration.
+type VariableDeclaration struct {
+	Location
+	Declarations []*VariableDeclarator
+	// Kind is one of VariableKindVar, VariableKindLet and VariableKindConst.
+	Kind string
+}
+
+// VariableDeclarator is a single binding of a variable declaration. Init is
+// nil if the binding has no initializer.
+type VariableDeclarator struct {
+	Location
+	ID   Pattern
+	Init Expression
+}
+
+// Class holds the fields that class declarations and class expressions
+// have in common. SuperClass is nil if there is no heritage.
+type Class struct {
+	ID         *Identifier
+	SuperClass Expression
+	Body       *ClassBody
+}
+
+// ClassDeclaration is a class declaration. ID is only nil for a class
+// declaration that is the default export of a module.
+type ClassDeclaration struct {
+	Location
+	Class
+}
+
+// ClassBody is the body of a class.
+type ClassBody struct {
+	Location
+	Body []*MethodDefinition
+}
+
+// MethodDefinition is a method of a class.
+type MethodDefinition struct {
+	Location
+	Key   Expression
+	Value *FunctionExpression
+	// Kind is one of KindConstructor, KindMethod, KindGet and KindSet.
+	Kind     string
+	Computed bool
+	Static   bool
+}
+
+// Type returns "FunctionDeclaration".
+func (*FunctionDeclaration) Type() string { return "FunctionDeclaration" }
+
+// Type returns "VariableDeclaration".
+func (*VariableDeclaration) Type() string { return "VariableDeclaration" }
+
+// Type returns "VariableDeclarator".
+func (*VariableDeclarator) Type() string { return "VariableDeclarator" }
+
+// Type returns "ClassDeclaration".
+func (*ClassDeclaration) Type() string { return "ClassDeclaration" }
+
+// Type returns "ClassBody".
+func (*ClassBody) Type() string { return "ClassBody" }
+
+// Type returns "MethodDefinition".
+func (*MethodDefinition) Type() string { return "MethodDefinition" }
+
+func (*FunctionDeclaration) statementNode() {}
+func (*VariableDeclaration) statementNode() {}
+func (*ClassDeclaration) statementNode()    {}
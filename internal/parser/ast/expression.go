@@ -0,0 +1,325 @@
+package ast
+
+// Identifier is an identifier, which can be used as an expression or a
+// binding pattern.
+type Identifier struct {
+	Location
+	Name string
+}
+
+// Literal is a null, boolean, number, string or regular expression literal.
+// Value is nil for null, a bool for booleans, a float64 for numbers and a
+// string for strings. For regular expressions, Value is nil and Regex is set.
+// Raw is the literal as it appears in the source code.
+type Literal struct {
+	Location
+	Value interface{}
+	Raw   string
+	Regex *RegExpLiteral
+}
+
+// RegExpLiteral holds the pattern and the flags of a regular expression literal.
+type RegExpLiteral struct {
+	Pattern string
+	Flags   string
+}
+
+// ThisExpression is the keyword this.
+type ThisExpression struct {
+	Location
+}
+
+// Super is the keyword super, used as callee of a call expression or as
+// object of a member expression.
+type Super struct {
+	Location
+}
+
+// ArrayExpression is an array literal. An element is nil if it is a hole,
+// and a *SpreadElement for a spread element.
+type ArrayExpression struct {
+	Location
+	Elements []Expression
+}
+
+// ObjectExpression is an object literal. Every property is either a
+// *Property or a *SpreadElement.
+type ObjectExpression struct {
+	Location
+	Properties []Node
+}
+
+// Property is a property of an object literal or an object pattern.
+// In an object literal, Value is an Expression, in an object pattern,
+// Value is a Pattern.
+type Property struct {
+	Location
+	Key   Expression
+	Value Node
+	// Kind is one of KindInit, KindGet and KindSet.
+	Kind      string
+	Method    bool
+	Shorthand bool
+	Computed  bool
+}
+
+// FunctionExpression is a function expression.
+type FunctionExpression struct {
+	Location
+	Function
+}
+
+// ArrowFunctionExpression is an arrow function. If the arrow function has a
+// concise body, Expression is true and Body is an Expression.
+type ArrowFunctionExpression struct {
+	Location
+	Function
+	Expression bool
+}
+
+// ClassExpression is a class expression.
+type ClassExpression struct {
+	Location
+	Class
+}
+
+// TemplateLiteral is a template literal. There is always exactly one more
+// quasi than there are expressions.
+type TemplateLiteral struct {
+	Location
+	Quasis      []*TemplateElement
+	Expressions []Expression
+}
+
+// TemplateElement is a string part of a template literal. Cooked is nil if
+// the template element contains an invalid escape sequence, which is only
+// allowed in tagged templates.
+type TemplateElement struct {
+	Location
+	Tail   bool
+	Cooked *string
+	Raw    string
+}
+
+// TaggedTemplateExpression is a tagged template.
+type TaggedTemplateExpression struct {
+	Location
+	Tag   Expression
+	Quasi *TemplateLiteral
+}
+
+// UnaryExpression is a unary operation, e.g. !x or typeof x.
+type UnaryExpression struct {
+	Location
+	Operator string
+	Prefix   bool
+	Argument Expression
+}
+
+// UpdateExpression is an increment or a decrement, either prefix or postfix.
+type UpdateExpression struct {
+	Location
+	Operator string
+	Prefix   bool
+	Argument Expression
+}
+
+// BinaryExpression is a binary operation, e.g. x + y.
+type BinaryExpression struct {
+	Location
+	Operator string
+	Left     Expression
+	Right    Expression
+}
+
+// LogicalExpression is a logical binary operation, i.e. x && y or x || y.
+type LogicalExpression struct {
+	Location
+	Operator string
+	Left     Expression
+	Right    Expression
+}
+
+// AssignmentExpression is an assignment, e.g. x = y or x += y.
+type AssignmentExpression struct {
+	Location
+	Operator string
+	Left     Pattern
+	Right    Expression
+}
+
+// ConditionalExpression is a ternary operation, x ? y : z.
+type ConditionalExpression struct {
+	Location
+	Test       Expression
+	Consequent Expression
+	Alternate  Expression
+}
+
+// MemberExpression is a property access. If Computed is true, the property
+// was accessed with brackets, x[y], otherwise Property is an *Identifier.
+// Object is a *Super for super property accesses.
+type MemberExpression struct {
+	Location
+	Object   Node
+	Property Expression
+	Computed bool
+}
+
+// CallExpression is a function call. Callee is a *Super for super calls.
+// An argument is a *SpreadElement for spread arguments.
+type CallExpression struct {
+	Location
+	Callee    Node
+	Arguments []Expression
+}
+
+// NewExpression is a constructor call using new.
+type NewExpression struct {
+	Location
+	Callee    Expression
+	Arguments []Expression
+}
+
+// SequenceExpression is a comma separated list of expressions.
+type SequenceExpression struct {
+	Location
+	Expressions []Expression
+}
+
+// YieldExpression is a yield or yield* expression. Argument is nil if
+// no value is yielded.
+type YieldExpression struct {
+	Location
+	Argument Expression
+	Delegate bool
+}
+
+// AwaitExpression is an await expression.
+type AwaitExpression struct {
+	Location
+	Argument Expression
+}
+
+// SpreadElement is a spread element in an array literal, an object literal
+// or the arguments of a call.
+type SpreadElement struct {
+	Location
+	Argument Expression
+}
+
+// MetaProperty is a meta property, i.e. new.target.
+type MetaProperty struct {
+	Location
+	Meta     *Identifier
+	Property *Identifier
+}
+
+// Type returns "Identifier".
+func (*Identifier) Type() string { return "Identifier" }
+
+// Type returns "Literal".
+func (*Literal) Type() string { return "Literal" }
+
+// Type returns "ThisExpression".
+func (*ThisExpression) Type() string { return "ThisExpression" }
+
+// Type returns "Super".
+func (*Super) Type() string { return "Super" }
+
+// Type returns "ArrayExpression".
+func (*ArrayExpression) Type() string { return "ArrayExpression" }
+
+// Type returns "ObjectExpression".
+func (*ObjectExpression) Type() string { return "ObjectExpression" }
+
+// Type returns "Property".
+func (*Property) Type() string { return "Property" }
+
+// Type returns "FunctionExpression".
+func (*FunctionExpression) Type() string { return "FunctionExpression" }
+
+// Type returns "ArrowFunctionExpression".
+func (*ArrowFunctionExpression) Type() string { return "ArrowFunctionExpression" }
+
+// Type returns "ClassExpression".
+func (*ClassExpression) Type() string { return "ClassExpression" }
+
+// Type returns "TemplateLiteral".
+func (*TemplateLiteral) Type() string { return "TemplateLiteral" }
+
+// Type returns "TemplateElement".
+func (*TemplateElement) Type() string { return "TemplateElement" }
+
+// Type returns "TaggedTemplateExpression".
+func (*TaggedTemplateExpression) Type() string { return "TaggedTemplateExpression" }
+
+// Type returns "UnaryExpression".
+func (*UnaryExpression) Type() string { return "UnaryExpression" }
+
+// Type returns "UpdateExpression".
+func (*UpdateExpression) Type() string { return "UpdateExpression" }
+
+// Type returns "BinaryExpression".
+func (*BinaryExpression) Type() string { return "BinaryExpression" }
+
+// Type returns "LogicalExpression".
+func (*LogicalExpression) Type() string { return "LogicalExpression" }
+
+// Type returns "AssignmentExpression".
+func (*AssignmentExpression) Type() string { return "AssignmentExpression" }
+
+// Type returns "ConditionalExpression".
+func (*ConditionalExpression) Type() string { return "ConditionalExpression" }
+
+// Type returns "MemberExpression".
+func (*MemberExpression) Type() string { return "MemberExpression" }
+
+// Type returns "CallExpression".
+func (*CallExpression) Type() string { return "CallExpression" }
+
+// Type returns "NewExpression".
+func (*NewExpression) Type() string { return "NewExpression" }
+
+// Type returns "SequenceExpression".
+func (*SequenceExpression) Type() string { return "SequenceExpression" }
+
+// Type returns "YieldExpression".
+func (*YieldExpression) Type() string { return "YieldExpression" }
+
+// Type returns "AwaitExpression".
+func (*AwaitExpression) Type() string { return "AwaitExpression" }
+
+// Type returns "SpreadElement".
+func (*SpreadElement) Type() string { return "SpreadElement" }
+
+// Type returns "MetaProperty".
+func (*MetaProperty) Type() string { return "MetaProperty" }
+
+func (*Identifier) expressionNode()               {}
+func (*Literal) expressionNode()                  {}
+func (*ThisExpression) expressionNode()           {}
+func (*ArrayExpression) expressionNode()          {}
+func (*ObjectExpression) expressionNode()         {}
+func (*FunctionExpression) expressionNode()       {}
+func (*ArrowFunctionExpression) expressionNode()  {}
+func (*ClassExpression) expressionNode()          {}
+func (*TemplateLiteral) expressionNode()          {}
+func (*TaggedTemplateExpression) expressionNode() {}
+func (*UnaryExpression) expressionNode()          {}
+func (*UpdateExpression) expressionNode()         {}
+func (*BinaryExpression) expressionNode()         {}
+func (*LogicalExpression) expressionNode()        {}
+func (*AssignmentExpression) expressionNode()     {}
+func (*ConditionalExpression) expressionNode()    {}
+func (*MemberExpression) expressionNode()         {}
+func (*CallExpression) expressionNode()           {}
+func (*NewExpression) expressionNode()            {}
+func (*SequenceExpression) expressionNode()       {}
+func (*YieldExpression) expressionNode()          {}
+func (*AwaitExpression) expressionNode()          {}
+func (*SpreadElement) expressionNode()            {}
+func (*MetaProperty) expressionNode()             {}
+
+func (*Identifier) patternNode()       {}
+func (*MemberExpression) patternNode() {}
@@ -0,0 +1,136 @@
+package ast
+
+// ImportDeclaration is an import declaration of a module. Specifiers holds
+// *ImportSpecifier, *ImportDefaultSpecifier and *ImportNamespaceSpecifier
+// nodes, it is empty for an import declaration without import clause, e.g.
+// import "mod";
+type ImportDeclaration struct {
+	Location
+	Specifiers []Node
+	Source     *Literal
+}
+
+// ImportSpecifier is a named import, e.g. a or a as b in
+// import { a, a as b } from "mod". If the import is not renamed, Imported
+// and Local are the same identifier.
+type ImportSpecifier struct {
+	Location
+	Imported *Identifier
+	Local    *Identifier
+}
+
+// ImportDefaultSpecifier is the import of a default export, e.g. a in
+// import a from "mod".
+type ImportDefaultSpecifier struct {
+	Location
+	Local *Identifier
+}
+
+// ImportNamespaceSpecifier is the import of a module namespace object, e.g.
+// * as a in import * as a from "mod".
+type ImportNamespaceSpecifier struct {
+	Location
+	Local *Identifier
+}
+
+// ExportNamedDeclaration is an export of a declaration, e.g.
+// export var a = 1, or an export clause, e.g. export { a, b as c }. Either
+// Declaration is set, or Specifiers holds the exports of the export clause.
+// Source is only set for a re-export, e.g. export { a } from "mod".
+type ExportNamedDeclaration struct {
+	Location
+	Declaration Statement
+	Specifiers  []*ExportSpecifier
+	Source      *Literal
+}
+
+// ExportSpecifier is a single export of an export clause. If the export is
+// not renamed, Local and Exported are the same identifier.
+type ExportSpecifier struct {
+	Location
+	Local    *Identifier
+	Exported *Identifier
+}
+
+// ExportDefaultDeclaration is the default export of a module. Declaration
+// is a *FunctionDeclaration, a *ClassDeclaration or an Expression. The ID of
+// a function or class declaration may be nil.
+type ExportDefaultDeclaration struct {
+	Location
+	Declaration Node
+}
+
+// ExportAllDeclaration re-exports all exports of another module, e.g.
+// export * from "mod", or its module namespace object under the name
+// Exported, e.g. export * as ns from "mod". Exported is nil if there is no
+// such name.
+type ExportAllDeclaration struct {
+	Location
+	Exported *Identifier
+	Source   *Literal
+}
+
+// Type returns "ImportDeclaration".
+func (*ImportDeclaration) Type() string { return "ImportDeclaration" }
+
+// Type returns "ImportSpecifier".
+func (*ImportSpecifier) Type() string { return "ImportSpecifier" }
+
+// Type returns "ImportDefaultSpecifier".
+func (*ImportDefaultSpecifier) Type() string { return "ImportDefaultSpecifier" }
+
+// Type returns "ImportNamespaceSpecifier".
+func (*ImportNamespaceSpecifier) Type() string { return "ImportNamespaceSpecifier" }
+
+// Type returns "ExportNamedDeclaration".
+func (*ExportNamedDeclaration) Type() string { return "ExportNamedDeclaration" }
+
+// Type returns "ExportSpecifier".
+func (*ExportSpecifier) Type() string { return "ExportSpecifier" }
+
+// Type returns "ExportDefaultDeclaration".
+func (*ExportDefaultDeclaration) Type() string { return "ExportDefaultDeclaration" }
+
+// Type returns "ExportAllDeclaration".
+func (*ExportAllDeclaration) Type() string { return "ExportAllDeclaration" }
+
+// Import and export declarations can only appear at the top level of a
+// module, but they are statements in the sense that they are elements of
+// Program#Body.
+func (*ImportDeclaration) statementNode()        {}
+func (*ExportNamedDeclaration) statementNode()   {}
+func (*ExportDefaultDeclaration) statementNode() {}
+func (*ExportAllDeclaration) statementNode()     {}
+
+// DefaultExportName is the local name of a default export that is not a
+// named function or class declaration, as specified in 15.2.3.2.
+const DefaultExportName = "*default*"
+
+// ImportEntry describes a single binding imported by a module, as specified
+// in 15.2.1.16. ImportName is "*" for a namespace import.
+type ImportEntry struct {
+	// ModuleRequest is the module specifier of the import declaration.
+	ModuleRequest string
+	// ImportName is the name under which the binding is exported by the
+	// requested module.
+	ImportName string
+	// LocalName is the name of the binding in the importing module.
+	LocalName string
+}
+
+// ExportEntry describes a single binding exported by a module, as specified
+// in 15.2.1.16. Fields that do not apply to an export, which are null in
+// the specification, are empty. ImportName is "*" for an export * from
+// declaration, whose ExportName is empty, and for an export * as ns from
+// declaration.
+type ExportEntry struct {
+	// ExportName is the name under which the binding is exported.
+	ExportName string
+	// ModuleRequest is the module specifier of a re-export.
+	ModuleRequest string
+	// ImportName is the name of a re-exported binding in the requested
+	// module.
+	ImportName string
+	// LocalName is the name of an exported local binding.
+	LocalName string
+}
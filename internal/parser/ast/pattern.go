@@ -0,0 +1,47 @@
+package ast
+
+// ObjectPattern is an object destructuring pattern. Every property is
+// either a *Property with a Pattern as value, or a *RestElement.
+type ObjectPattern struct {
+	Location
+	Properties []Node
+}
+
+// ArrayPattern is an array destructuring pattern. An element is nil if
+// it is an elision.
+type ArrayPattern struct {
+	Location
+	Elements []Pattern
+}
+
+// RestElement is a rest element in a destructuring pattern or in the
+// parameters of a function.
+type RestElement struct {
+	Location
+	Argument Pattern
+}
+
+// AssignmentPattern is a pattern with a default value, e.g. the parameter
+// x = 1 in function f(x = 1) {}.
+type AssignmentPattern struct {
+	Location
+	Left  Pattern
+	Right Expression
+}
+
+// Type returns "ObjectPattern".
+func (*ObjectPattern) Type() string { return "ObjectPattern" }
+
+// Type returns "ArrayPattern".
+func (*ArrayPattern) Type() string { return "ArrayPattern" }
+
+// Type returns "RestElement".
+func (*RestElement) Type() string { return "RestElement" }
+
+// Type returns "AssignmentPattern".
+func (*AssignmentPattern) Type() string { return "AssignmentPattern" }
+
+func (*ObjectPattern) patternNode()     {}
+func (*ArrayPattern) patternNode()      {}
+func (*RestElement) patternNode()       {}
+func (*AssignmentPattern) patternNode() {}
@@ -0,0 +1,226 @@
+package ast
+
+// ExpressionStatement is a statement consisting of a single expression.
+// If the statement is part of a directive prologue, Directive holds the
+// raw string of the directive without quotes, e.g. "use strict".
+type ExpressionStatement struct {
+	Location
+	Expression Expression
+	Directive  string
+}
+
+// BlockStatement is a block of statements surrounded by braces.
+type BlockStatement struct {
+	Location
+	Body []Statement
+}
+
+// EmptyStatement is a single semicolon.
+type EmptyStatement struct {
+	Location
+}
+
+// DebuggerStatement is a debugger statement.
+type DebuggerStatement struct {
+	Location
+}
+
+// WithStatement is a with statement.
+type WithStatement struct {
+	Location
+	Object Expression
+	Body   Statement
+}
+
+// ReturnStatement is a return statement. Argument is nil if no value is returned.
+type ReturnStatement struct {
+	Location
+	Argument Expression
+}
+
+// LabeledStatement is a statement prefixed by a label.
+type LabeledStatement struct {
+	Location
+	Label *Identifier
+	Body  Statement
+}
+
+// BreakStatement is a break statement. Label is nil if no label is given.
+type BreakStatement struct {
+	Location
+	Label *Identifier
+}
+
+// ContinueStatement is a continue statement. Label is nil if no label is given.
+type ContinueStatement struct {
+	Location
+	Label *Identifier
+}
+
+// IfStatement is an if statement. Alternate is nil if there is no else branch.
+type IfStatement struct {
+	Location
+	Test       Expression
+	Consequent Statement
+	Alternate  Statement
+}
+
+// SwitchStatement is a switch statement.
+type SwitchStatement struct {
+	Location
+	Discriminant Expression
+	Cases        []*SwitchCase
+}
+
+// SwitchCase is a case clause of a switch statement. Test is nil for the
+// default clause.
+type SwitchCase struct {
+	Location
+	Test       Expression
+	Consequent []Statement
+}
+
+// ThrowStatement is a throw statement.
+type ThrowStatement struct {
+	Location
+	Argument Expression
+}
+
+// TryStatement is a try statement. At least one of Handler and Finalizer
+// is not nil.
+type TryStatement struct {
+	Location
+	Block     *BlockStatement
+	Handler   *CatchClause
+	Finalizer *BlockStatement
+}
+
+// CatchClause is the catch clause of a try statement. Param is nil if the
+// catch clause does not bind the caught value.
+type CatchClause struct {
+	Location
+	Param Pattern
+	Body  *BlockStatement
+}
+
+// WhileStatement is a while loop.
+type WhileStatement struct {
+	Location
+	Test Expression
+	Body Statement
+}
+
+// DoWhileStatement is a do-while loop.
+type DoWhileStatement struct {
+	Location
+	Body Statement
+	Test Expression
+}
+
+// ForStatement is a for loop. Init is either nil, a *VariableDeclaration
+// or an Expression. Test and Update may be nil.
+type ForStatement struct {
+	Location
+	Init   Node
+	Test   Expression
+	Update Expression
+	Body   Statement
+}
+
+// ForInStatement is a for-in loop. Left is either a *VariableDeclaration
+// or a Pattern.
+type ForInStatement struct {
+	Location
+	Left  Node
+	Right Expression
+	Body  Statement
+}
+
+// ForOfStatement is a for-of loop. Left is either a *VariableDeclaration
+// or a Pattern. Await is true for for-await-of loops.
+type ForOfStatement struct {
+	Location
+	Left  Node
+	Right Expression
+	Body  Statement
+	Await bool
+}
+
+// Type returns "ExpressionStatement".
+func (*ExpressionStatement) Type() string { return "ExpressionStatement" }
+
+// Type returns "BlockStatement".
+func (*BlockStatement) Type() string { return "BlockStatement" }
+
+// Type returns "EmptyStatement".
+func (*EmptyStatement) Type() string { return "EmptyStatement" }
+
+// Type returns "DebuggerStatement".
+func (*DebuggerStatement) Type() string { return "DebuggerStatement" }
+
+// Type returns "WithStatement".
+func (*WithStatement) Type() string { return "WithStatement" }
+
+// Type returns "ReturnStatement".
+func (*ReturnStatement) Type() string { return "ReturnStatement" }
+
+// Type returns "LabeledStatement".
+func (*LabeledStatement) Type() string { return "LabeledStatement" }
+
+// Type returns "BreakStatement".
+func (*BreakStatement) Type() string { return "BreakStatement" }
+
+// Type returns "ContinueStatement".
+func (*ContinueStatement) Type() string { return "ContinueStatement" }
+
+// Type returns "IfStatement".
+func (*IfStatement) Type() string { return "IfStatement" }
+
+// Type returns "SwitchStatement".
+func (*SwitchStatement) Type() string { return "SwitchStatement" }
+
+// Type returns "SwitchCase".
+func (*SwitchCase) Type() string { return "SwitchCase" }
+
+// Type returns "ThrowStatement".
+func (*ThrowStatement) Type() string { return "ThrowStatement" }
+
+// Type returns "TryStatement".
+func (*TryStatement) Type() string { return "TryStatement" }
+
+// Type returns "CatchClause".
+func (*CatchClause) Type() string { return "CatchClause" }
+
+// Type returns "WhileStatement".
+func (*WhileStatement) Type() string { return "WhileStatement" }
+
+// Type returns "DoWhileStatement".
+func (*DoWhileStatement) Type() string { return "DoWhileStatement" }
+
+// Type returns "ForStatement".
+func (*ForStatement) Type() string { return "ForStatement" }
+
+// Type returns "ForInStatement".
+func (*ForInStatement) Type() string { return "ForInStatement" }
+
+// Type returns "ForOfStatement".
+func (*ForOfStatement) Type() string { return "ForOfStatement" }
+
+func (*ExpressionStatement) statementNode() {}
+func (*BlockStatement) statementNode()      {}
+func (*EmptyStatement) statementNode()      {}
+func (*DebuggerStatement) statementNode()   {}
+func (*WithStatement) statementNode()       {}
+func (*ReturnStatement) statementNode()     {}
+func (*LabeledStatement) statementNode()    {}
+func (*BreakStatement) statementNode()      {}
+func (*ContinueStatement) statementNode()   {}
+func (*IfStatement) statementNode()         {}
+func (*SwitchStatement) statementNode()     {}
+func (*ThrowStatement) statementNode()      {}
+func (*TryStatement) statementNode()        {}
+func (*WhileStatement) statementNode()      {}
+func (*DoWhileStatement) statementNode()    {}
+func (*ForStatement) statementNode()        {}
+func (*ForInStatement) statementNode()      {}
+func (*ForOfStatement) statementNode()      {}
@@ -0,0 +1,281 @@
+package ast
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Visitor is used to traverse a syntax tree with Walk. The Visit method is
+// invoked for each node encountered by Walk. If the result visitor w is not
+// nil, Walk visits each of the children of node with the visitor w, followed
+// by a call of w.Visit(nil).
+type Visitor interface {
+	Visit(node Node) (w Visitor)
+}
+
+// Walk traverses a syntax tree in depth-first order. It starts by calling
+// v.Visit(node). If the visitor returned by v.Visit(node) is not nil, Walk
+// is invoked recursively with that visitor for each of the non-nil children
+// of node, followed by a call of w.Visit(nil). Children are visited in the
+// order they appear in the source code.
+func Walk(v Visitor, node Node) {
+	if v = v.Visit(node); v == nil {
+		return
+	}
+
+	switch n := node.(type) {
+	case *Program:
+		walkStatements(v, n.Body)
+	case *ExpressionStatement:
+		Walk(v, n.Expression)
+	case *BlockStatement:
+		walkStatements(v, n.Body)
+	case *EmptyStatement, *DebuggerStatement, *ThisExpression, *Super, *Literal, *Identifier, *TemplateElement:
+		// no children
+	case *WithStatement:
+		Walk(v, n.Object)
+		Walk(v, n.Body)
+	case *ReturnStatement:
+		walkOptional(v, n.Argument)
+	case *LabeledStatement:
+		Walk(v, n.Label)
+		Walk(v, n.Body)
+	case *BreakStatement:
+		walkOptional(v, n.Label)
+	case *ContinueStatement:
+		walkOptional(v, n.Label)
+	case *IfStatement:
+		Walk(v, n.Test)
+		Walk(v, n.Consequent)
+		walkOptional(v, n.Alternate)
+	case *SwitchStatement:
+		Walk(v, n.Discriminant)
+		for _, c := range n.Cases {
+			Walk(v, c)
+		}
+	case *SwitchCase:
+		walkOptional(v, n.Test)
+		walkStatements(v, n.Consequent)
+	case *ThrowStatement:
+		Walk(v, n.Argument)
+	case *TryStatement:
+		Walk(v, n.Block)
+		walkOptional(v, n.Handler)
+		walkOptional(v, n.Finalizer)
+	case *CatchClause:
+		walkOptional(v, n.Param)
+		Walk(v, n.Body)
+	case *WhileStatement:
+		Walk(v, n.Test)
+		Walk(v, n.Body)
+	case *DoWhileStatement:
+		Walk(v, n.Body)
+		Walk(v, n.Test)
+	case *ForStatement:
+		walkOptional(v, n.Init)
+		walkOptional(v, n.Test)
+		walkOptional(v, n.Update)
+		Walk(v, n.Body)
+	case *ForInStatement:
+		Walk(v, n.Left)
+		Walk(v, n.Right)
+		Walk(v, n.Body)
+	case *ForOfStatement:
+		Walk(v, n.Left)
+		Walk(v, n.Right)
+		Walk(v, n.Body)
+	case *FunctionDeclaration:
+		walkFunction(v, &n.Function)
+	case *VariableDeclaration:
+		for _, d := range n.Declarations {
+			Walk(v, d)
+		}
+	case *VariableDeclarator:
+		Walk(v, n.ID)
+		walkOptional(v, n.Init)
+	case *ClassDeclaration:
+		walkClass(v, &n.Class)
+	case *ClassBody:
+		for _, m := range n.Body {
+			Walk(v, m)
+		}
+	case *MethodDefinition:
+		Walk(v, n.Key)
+		Walk(v, n.Value)
+	case *ArrayExpression:
+		walkExpressions(v, n.Elements)
+	case *ObjectExpression:
+		walkNodes(v, n.Properties)
+	case *Property:
+		if n.Shorthand {
+			// key and value are the same identifier in the source code
+			Walk(v, n.Value)
+		} else {
+			Walk(v, n.Key)
+			Walk(v, n.Value)
+		}
+	case *FunctionExpression:
+		walkFunction(v, &n.Function)
+	case *ArrowFunctionExpression:
+		walkFunction(v, &n.Function)
+	case *ClassExpression:
+		walkClass(v, &n.Class)
+	case *TemplateLiteral:
+		for i, q := range n.Quasis {
+			Walk(v, q)
+			if i < len(n.Expressions) {
+				Walk(v, n.Expressions[i])
+			}
+		}
+	case *TaggedTemplateExpression:
+		Walk(v, n.Tag)
+		Walk(v, n.Quasi)
+	case *UnaryExpression:
+		Walk(v, n.Argument)
+	case *UpdateExpression:
+		Walk(v, n.Argument)
+	case *BinaryExpression:
+		Walk(v, n.Left)
+		Walk(v, n.Right)
+	case *LogicalExpression:
+		Walk(v, n.Left)
+		Walk(v, n.Right)
+	case *AssignmentExpression:
+		Walk(v, n.Left)
+		Walk(v, n.Right)
+	case *ConditionalExpression:
+		Walk(v, n.Test)
+		Walk(v, n.Consequent)
+		Walk(v, n.Alternate)
+	case *MemberExpression:
+		Walk(v, n.Object)
+		Walk(v, n.Property)
+	case *CallExpression:
+		Walk(v, n.Callee)
+		walkExpressions(v, n.Arguments)
+	case *NewExpression:
+		Walk(v, n.Callee)
+		walkExpressions(v, n.Arguments)
+	case *SequenceExpression:
+		walkExpressions(v, n.Expressions)
+	case *YieldExpression:
+		walkOptional(v, n.Argument)
+	case *AwaitExpression:
+		Walk(v, n.Argument)
+	case *SpreadElement:
+		Walk(v, n.Argument)
+	case *MetaProperty:
+		Walk(v, n.Meta)
+		Walk(v, n.Property)
+	case *ObjectPattern:
+		walkNodes(v, n.Properties)
+	case *ArrayPattern:
+		for _, e := range n.Elements {
+			walkOptional(v, e)
+		}
+	case *RestElement:
+		Walk(v, n.Argument)
+	case *AssignmentPattern:
+		Walk(v, n.Left)
+		Walk(v, n.Right)
+	case *ImportDeclaration:
+		walkNodes(v, n.Specifiers)
+		Walk(v, n.Source)
+	case *ImportSpecifier:
+		Walk(v, n.Imported)
+		if n.Local != n.Imported {
+			Walk(v, n.Local)
+		}
+	case *ImportDefaultSpecifier:
+		Walk(v, n.Local)
+	case *ImportNamespaceSpecifier:
+		Walk(v, n.Local)
+	case *ExportNamedDeclaration:
+		walkOptional(v, n.Declaration)
+		for _, s := range n.Specifiers {
+			Walk(v, s)
+		}
+		walkOptional(v, n.Source)
+	case *ExportSpecifier:
+		Walk(v, n.Local)
+		if n.Exported != n.Local {
+			Walk(v, n.Exported)
+		}
+	case *ExportDefaultDeclaration:
+		Walk(v, n.Declaration)
+	case *ExportAllDeclaration:
+		if n.Exported != nil {
+			Walk(v, n.Exported)
+		}
+		Walk(v, n.Source)
+	default:
+		panic(fmt.Errorf("ast.Walk: unexpected node type %T", n))
+	}
+
+	v.Visit(nil)
+}
+
+type inspector func(Node) bool
+
+func (f inspector) Visit(node Node) Visitor {
+	if f(node) {
+		return f
+	}
+	return nil
+}
+
+// Inspect traverses a syntax tree in depth-first order. It starts by calling
+// f(node). If f returns true, Inspect invokes f recursively for each of the
+// non-nil children of node, followed by a call of f(nil).
+func Inspect(node Node, f func(Node) bool) {
+	Walk(inspector(f), node)
+}
+
+// walkOptional walks the given node if it is not nil. Since the node is
+// passed as an interface, a typed nil pointer is also considered nil.
+func walkOptional(v Visitor, node Node) {
+	if !isNil(node) {
+		Walk(v, node)
+	}
+}
+
+func walkStatements(v Visitor, list []Statement) {
+	for _, n := range list {
+		Walk(v, n)
+	}
+}
+
+func walkExpressions(v Visitor, list []Expression) {
+	for _, n := range list {
+		walkOptional(v, n)
+	}
+}
+
+func walkNodes(v Visitor, list []Node) {
+	for _, n := range list {
+		walkOptional(v, n)
+	}
+}
+
+func walkFunction(v Visitor, f *Function) {
+	walkOptional(v, f.ID)
+	for _, p := range f.Params {
+		Walk(v, p)
+	}
+	Walk(v, f.Body)
+}
+
+func walkClass(v Visitor, c *Class) {
+	walkOptional(v, c.ID)
+	walkOptional(v, c.SuperClass)
+	Walk(v, c.Body)
+}
+
+func isNil(node Node) bool {
+	if node == nil {
+		return true
+	}
+
+	v := reflect.ValueOf(node)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
@@ -0,0 +1,122 @@
+package ast
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// program returns the tree for
+//
+//	if (a) { f(1, ...b); } else return;
+func program() *Program {
+	call := new(CallExpression)
+	call.Callee = &Identifier{Name: "f"}
+	call.Arguments = []Expression{
+		&Literal{Value: 1.0, Raw: "1"},
+		&SpreadElement{Argument: &Identifier{Name: "b"}},
+	}
+
+	ifStmt := new(IfStatement)
+	ifStmt.Test = &Identifier{Name: "a"}
+	ifStmt.Consequent = &BlockStatement{Body: []Statement{&ExpressionStatement{Expression: call}}}
+	ifStmt.Alternate = &ReturnStatement{}
+
+	p := new(Program)
+	p.SourceType = SourceTypeScript
+	p.Body = []Statement{ifStmt}
+	return p
+}
+
+func TestInspect(t *testing.T) {
+	require := require.New(t)
+
+	var types []string
+	Inspect(program(), func(n Node) bool {
+		if n != nil {
+			types = append(types, n.Type())
+		}
+		return true
+	})
+
+	require.Equal([]string{
+		"Program",
+		"IfStatement",
+		"Identifier",
+		"BlockStatement",
+		"ExpressionStatement",
+		"CallExpression",
+		"Identifier",
+		"Literal",
+		"SpreadElement",
+		"Identifier",
+		"ReturnStatement",
+	}, types)
+}
+
+func TestInspectSkipChildren(t *testing.T) {
+	require := require.New(t)
+
+	var types []string
+	Inspect(program(), func(n Node) bool {
+		if n == nil {
+			return false
+		}
+		types = append(types, n.Type())
+		_, isBlock := n.(*BlockStatement)
+		return !isBlock
+	})
+
+	require.Equal([]string{"Program", "IfStatement", "Identifier", "BlockStatement", "ReturnStatement"}, types)
+}
+
+func TestInspectModule(t *testing.T) {
+	require := require.New(t)
+
+	// import { a } from "m"; export { a as b };
+	a := &Identifier{Name: "a"}
+	p := new(Program)
+	p.SourceType = SourceTypeModule
+	p.Body = []Statement{
+		&ImportDeclaration{
+			Specifiers: []Node{&ImportSpecifier{Imported: a, Local: a}},
+			Source:     &Literal{Value: "m", Raw: `"m"`},
+		},
+		&ExportNamedDeclaration{
+			Specifiers: []*ExportSpecifier{{Local: a, Exported: &Identifier{Name: "b"}}},
+		},
+	}
+
+	var types []string
+	Inspect(p, func(n Node) bool {
+		if n != nil {
+			types = append(types, n.Type())
+		}
+		return true
+	})
+
+	require.Equal([]string{
+		"Program",
+		"ImportDeclaration",
+		"ImportSpecifier",
+		"Identifier",
+		"Literal",
+		"ExportNamedDeclaration",
+		"ExportSpecifier",
+		"Identifier",
+		"Identifier",
+	}, types)
+}
+
+func TestLocation(t *testing.T) {
+	require := require.New(t)
+
+	id := new(Identifier)
+	require.False(id.Loc().Start.IsValid())
+
+	id.Loc().Start = Position{Offset: 4, Line: 1, Column: 4}
+	id.Loc().End = Position{Offset: 7, Line: 1, Column: 7}
+	require.True(id.Loc().Start.IsValid())
+	require.Equal("1:4", id.Start.String())
+	require.Equal(7, id.End.Offset)
+}
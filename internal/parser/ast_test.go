@@ -0,0 +1,34 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/gojisvm/gojis/internal/parser/ast"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAstAddRoot(t *testing.T) {
+	require := require.New(t)
+
+	a := NewEmptyAst()
+	require.Equal(0, a.Len())
+
+	first := new(ast.Program)
+	second := new(ast.Program)
+
+	a.AddRoot("a.js", first)
+	a.AddRoot("b.js", second)
+	require.Equal(2, a.Len())
+	require.Equal([]string{"a.js", "b.js"}, a.Names())
+
+	root, ok := a.Root("a.js")
+	require.True(ok)
+	require.Equal(first, root)
+
+	// replacing a root must not change the order of the roots
+	a.AddRoot("a.js", second)
+	require.Equal([]string{"a.js", "b.js"}, a.Names())
+
+	_, ok = a.Root("c.js")
+	require.False(ok)
+}
@@ -1,37 +1,38 @@
 package parser
 
-import (
-	"fmt"
-
-	"github.com/antlr/antlr4/runtime/Go/antlr"
-)
+var _ ErrorListener = (*CollectingErrorListener)(nil) // ensure that CollectingErrorListener implements ErrorListener
 
+// CollectingErrorListener is an ErrorListener that collects all syntax
+// errors reported by the parser, instead of printing them.
 type CollectingErrorListener struct {
+	name string
 	errs []error
 }
 
-func NewCollectingErrorListener() *CollectingErrorListener {
+// NewCollectingErrorListener creates a new CollectingErrorListener that has not
+// collected any errors yet. The given name is the name of the source that is
+// being parsed. Errors reported for other sources are collected as well.
+func NewCollectingErrorListener(name string) *CollectingErrorListener {
 	l := new(CollectingErrorListener)
+	l.name = name
 	return l
 }
 
-func (l *CollectingErrorListener) SyntaxError(recognizer antlr.Recognizer, offendingSymbol interface{}, line, column int, msg string, e antlr.RecognitionException) {
-	l.Append(fmt.Errorf("Syntax error at line %v column %v: %v", line, column, msg))
-}
-
-func (l *CollectingErrorListener) ReportAmbiguity(recognizer antlr.Parser, dfa *antlr.DFA, startIndex, stopIndex int, exact bool, ambigAlts *antlr.BitSet, configs antlr.ATNConfigSet) {
-}
-
-func (l *CollectingErrorListener) ReportAttemptingFullContext(recognizer antlr.Parser, dfa *antlr.DFA, startIndex, stopIndex int, conflictingAlts *antlr.BitSet, configs antlr.ATNConfigSet) {
-}
-
-func (l *CollectingErrorListener) ReportContextSensitivity(recognizer antlr.Parser, dfa *antlr.DFA, startIndex, stopIndex, prediction int, configs antlr.ATNConfigSet) {
+// SyntaxError collects the given error.
+func (l *CollectingErrorListener) SyntaxError(err *Error) {
+	if err.File == "" {
+		err.File = l.name
+	}
+	l.Append(err)
 }
 
+// Append adds the given errors to the collected errors.
 func (l *CollectingErrorListener) Append(errs ...error) {
 	l.errs = append(l.errs, errs...)
 }
 
+// Errors returns all collected errors, and a flag indicating whether any
+// errors were collected at all.
 func (l *CollectingErrorListener) Errors() ([]error, bool) {
 	return l.errs, l.errs != nil && len(l.errs) > 0
 }
@@ -0,0 +1,901 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/gojisvm/gojis/internal/parser/ast"
+)
+
+// bindingKind describes how an identifier in a pattern is bound.
+type bindingKind uint8
+
+const (
+	// bindNone is used for assignment targets, which do not declare
+	// anything.
+	bindNone bindingKind = iota
+	// bindVar is used for var declarations and formal parameters.
+	bindVar
+	// bindLexical is used for let, const and class declarations, as well
+	// as for function declarations that are lexically scoped.
+	bindLexical
+	// bindFunction is used for function declarations that may be
+	// redeclared in non-strict code, as specified in B.3.3.
+	bindFunction
+	// bindSimpleCatch is used for a catch parameter that is a single
+	// identifier, which may be redeclared by a var declaration, as
+	// specified in B.3.5.
+	bindSimpleCatch
+)
+
+// scope holds the names declared in a function, a block or a script.
+type scope struct {
+	// isVar is true for the scope of a function or a script, which var
+	// declarations are hoisted to.
+	isVar bool
+	// simpleCatch is the name of the catch parameter if this is the scope
+	// of a catch clause with a single identifier as parameter.
+	simpleCatch string
+	// module is true for the scope of a module, where function
+	// declarations are lexically scoped.
+	module bool
+
+	vars      map[string]bool
+	lexical   map[string]bool
+	functions map[string]bool
+}
+
+func newScope(isVar bool) *scope {
+	s := new(scope)
+	s.isVar = isVar
+	s.vars = make(map[string]bool)
+	s.lexical = make(map[string]bool)
+	s.functions = make(map[string]bool)
+	return s
+}
+
+// label is a label of a labelled statement.
+type label struct {
+	name string
+	// iteration is true if the labelled statement is an iteration
+	// statement, which makes the label a valid continue target.
+	iteration bool
+}
+
+// functionState holds the state of the function that is currently
+// checked. Arrow functions get a new state, but inherit whether super and
+// new.target are allowed.
+type functionState struct {
+	labels []label
+	// loops and switches count the enclosing iteration and switch
+	// statements, which are targets of unlabelled break and continue
+	// statements.
+	loops    int
+	switches int
+
+	newTarget     bool
+	superProperty bool
+	superCall     bool
+}
+
+// earlyErrorChecker checks a program for all early errors that are not
+// detected while parsing, as specified in the Static Semantics: Early
+// Errors sections of the specification. This includes all errors that
+// depend on declared names, labels, the placement of super and new.target,
+// and strict mode code, since a function may only turn out to be strict
+// mode code after its name and parameters have been parsed.
+type earlyErrorChecker struct {
+	name     string
+	src      string
+	listener ErrorListener
+
+	strict bool
+	scopes []*scope
+	fn     *functionState
+}
+
+// checkEarlyErrors reports all early errors in the given program to the
+// given listener.
+func checkEarlyErrors(name, src string, program *ast.Program, listener ErrorListener) {
+	c := new(earlyErrorChecker)
+	c.name = name
+	c.src = src
+	c.listener = listener
+	c.strict = program.Strict
+	c.fn = new(functionState)
+
+	s := newScope(true)
+	s.module = program.SourceType == ast.SourceTypeModule
+	c.enterScope(s)
+	c.checkStatements(program.Body)
+	if s.module {
+		c.checkExports(program.Body)
+	}
+	c.exitScope()
+}
+
+func (c *earlyErrorChecker) errorAt(pos ast.Position, format string, args ...interface{}) {
+	c.listener.SyntaxError(&Error{
+		File:    c.name,
+		Pos:     pos,
+		Token:   tokenText(c.src, pos),
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (c *earlyErrorChecker) enterScope(s *scope) {
+	c.scopes = append(c.scopes, s)
+}
+
+func (c *earlyErrorChecker) exitScope() {
+	c.scopes = c.scopes[:len(c.scopes)-1]
+}
+
+func (c *earlyErrorChecker) currentScope() *scope {
+	return c.scopes[len(c.scopes)-1]
+}
+
+// declare declares the given identifier in the current scope, and reports
+// an error if the name conflicts with a name that has already been
+// declared. If forOf is true, the declaration is the var declaration of a
+// for-of statement, which must not redeclare a catch parameter.
+func (c *earlyErrorChecker) declare(id *ast.Identifier, kind bindingKind, forOf bool) {
+	name := id.Name
+	if kind == bindLexical && name == "let" {
+		c.errorAt(id.Start, "let is disallowed as a lexically bound name")
+	}
+
+	redeclared := false
+	current := c.currentScope()
+	switch kind {
+	case bindLexical:
+		redeclared = current.lexical[name] || current.functions[name] || current.vars[name]
+		current.lexical[name] = true
+	case bindSimpleCatch:
+		current.lexical[name] = true
+	case bindFunction:
+		if current.isVar {
+			redeclared = current.lexical[name]
+		} else {
+			redeclared = current.lexical[name] || current.vars[name]
+		}
+		current.functions[name] = true
+	case bindVar:
+		for i := len(c.scopes) - 1; i >= 0; i-- {
+			s := c.scopes[i]
+			if s.lexical[name] && (s.simpleCatch != name || forOf) || !s.isVar && s.functions[name] {
+				redeclared = true
+				break
+			}
+			s.vars[name] = true
+			if s.isVar {
+				break
+			}
+		}
+	}
+
+	if redeclared {
+		c.errorAt(id.Start, "Identifier '%v' has already been declared", name)
+	}
+}
+
+// checkBindingIdentifier reports an error if the given identifier cannot be
+// bound or assigned to in the current context.
+func (c *earlyErrorChecker) checkBindingIdentifier(id *ast.Identifier, strict bool) {
+	if !strict {
+		return
+	}
+	switch {
+	case id.Name == "eval" || id.Name == "arguments":
+		c.errorAt(id.Start, "Unexpected eval or arguments in strict mode")
+	case strictReservedWords[id.Name]:
+		c.errorAt(id.Start, "Unexpected strict mode reserved word '%v'", id.Name)
+	}
+}
+
+// checkIdentifierReference reports an error if the given identifier, which
+// is used as reference or label, is a reserved word in the current context.
+func (c *earlyErrorChecker) checkIdentifierReference(id *ast.Identifier) {
+	if c.strict && strictReservedWords[id.Name] {
+		c.errorAt(id.Start, "Unexpected strict mode reserved word '%v'", id.Name)
+	}
+}
+
+// checkStatements checks a statement list. The caller is responsible for
+// setting up the scope.
+func (c *earlyErrorChecker) checkStatements(stmts []ast.Statement) {
+	for _, stmt := range stmts {
+		c.checkStatement(stmt)
+	}
+}
+
+func (c *earlyErrorChecker) checkStatement(stmt ast.Statement) {
+	switch n := stmt.(type) {
+	case *ast.ExpressionStatement:
+		c.checkExpression(n.Expression)
+	case *ast.BlockStatement:
+		c.enterScope(newScope(false))
+		c.checkStatements(n.Body)
+		c.exitScope()
+	case *ast.EmptyStatement, *ast.DebuggerStatement:
+		// nothing to check
+	case *ast.VariableDeclaration:
+		c.checkVariableDeclaration(n, false)
+	case *ast.FunctionDeclaration:
+		c.checkFunctionDeclaration(n)
+	case *ast.ClassDeclaration:
+		if n.ID != nil {
+			c.checkBindingIdentifier(n.ID, true)
+			c.declare(n.ID, bindLexical, false)
+		}
+		c.checkClass(&n.Class)
+	case *ast.WithStatement:
+		if c.strict {
+			c.errorAt(n.Start, "Strict mode code may not include a with statement")
+		}
+		c.checkExpression(n.Object)
+		c.checkSubStatement(n.Body)
+	case *ast.ReturnStatement:
+		c.checkOptionalExpression(n.Argument)
+	case *ast.LabeledStatement:
+		c.checkLabeledStatement(n)
+	case *ast.BreakStatement:
+		c.checkBreakStatement(n)
+	case *ast.ContinueStatement:
+		c.checkContinueStatement(n)
+	case *ast.IfStatement:
+		c.checkExpression(n.Test)
+		c.checkSubStatement(n.Consequent)
+		if n.Alternate != nil {
+			c.checkSubStatement(n.Alternate)
+		}
+	case *ast.SwitchStatement:
+		c.checkExpression(n.Discriminant)
+		c.enterScope(newScope(false))
+		c.fn.switches++
+		for _, cs := range n.Cases {
+			c.checkOptionalExpression(cs.Test)
+			c.checkStatements(cs.Consequent)
+		}
+		c.fn.switches--
+		c.exitScope()
+	case *ast.ThrowStatement:
+		c.checkExpression(n.Argument)
+	case *ast.TryStatement:
+		c.checkStatement(n.Block)
+		if n.Handler != nil {
+			c.checkCatchClause(n.Handler)
+		}
+		if n.Finalizer != nil {
+			c.checkStatement(n.Finalizer)
+		}
+	case *ast.WhileStatement:
+		c.checkExpression(n.Test)
+		c.checkLoopBody(n.Body)
+	case *ast.DoWhileStatement:
+		c.checkLoopBody(n.Body)
+		c.checkExpression(n.Test)
+	case *ast.ForStatement:
+		c.enterScope(newScope(false))
+		switch init := n.Init.(type) {
+		case nil:
+		case *ast.VariableDeclaration:
+			c.checkVariableDeclaration(init, false)
+		default:
+			c.checkExpression(init.(ast.Expression))
+		}
+		c.checkOptionalExpression(n.Test)
+		c.checkOptionalExpression(n.Update)
+		c.checkLoopBody(n.Body)
+		c.exitScope()
+	case *ast.ForInStatement:
+		c.checkForInOf(n.Left, n.Right, n.Body, false)
+	case *ast.ForOfStatement:
+		c.checkForInOf(n.Left, n.Right, n.Body, true)
+	case *ast.ImportDeclaration:
+		for _, spec := range n.Specifiers {
+			local := importedBinding(spec)
+			c.checkBindingIdentifier(local, true)
+			c.declare(local, bindLexical, false)
+		}
+	case *ast.ExportNamedDeclaration:
+		if n.Declaration != nil {
+			c.checkStatement(n.Declaration)
+		} else if n.Source == nil {
+			for _, spec := range n.Specifiers {
+				c.checkIdentifierReference(spec.Local)
+			}
+		}
+	case *ast.ExportDefaultDeclaration:
+		switch d := n.Declaration.(type) {
+		case *ast.FunctionDeclaration:
+			c.checkStatement(d)
+		case *ast.ClassDeclaration:
+			c.checkStatement(d)
+		default:
+			c.checkExpression(d.(ast.Expression))
+		}
+	case *ast.ExportAllDeclaration:
+		// nothing to check
+	default:
+		panic(fmt.Sprintf("unexpected statement type %T", stmt))
+	}
+}
+
+// importedBinding returns the local binding of the given import specifier.
+func importedBinding(spec ast.Node) *ast.Identifier {
+	switch spec := spec.(type) {
+	case *ast.ImportDefaultSpecifier:
+		return spec.Local
+	case *ast.ImportNamespaceSpecifier:
+		return spec.Local
+	case *ast.ImportSpecifier:
+		return spec.Local
+	}
+	panic(fmt.Sprintf("unexpected import specifier type %T", spec))
+}
+
+// checkExports reports duplicate exported names, and local exports of
+// names that are not declared in the module, as specified in 15.2.1.1. The
+// scope of the module must be the current scope.
+func (c *earlyErrorChecker) checkExports(body []ast.Statement) {
+	exported := make(map[string]bool)
+	export := func(name string, pos ast.Position) {
+		if exported[name] {
+			c.errorAt(pos, "Duplicate export of '%v'", name)
+		}
+		exported[name] = true
+	}
+
+	s := c.currentScope()
+	for _, stmt := range body {
+		switch n := stmt.(type) {
+		case *ast.ExportNamedDeclaration:
+			for _, id := range declarationBoundNames(n.Declaration) {
+				export(id.Name, id.Start)
+			}
+			for _, spec := range n.Specifiers {
+				export(spec.Exported.Name, spec.Exported.Start)
+				name := spec.Local.Name
+				if n.Source == nil && !s.vars[name] && !s.lexical[name] && !s.functions[name] {
+					c.errorAt(spec.Local.Start, "Export '%v' is not defined in module", name)
+				}
+			}
+		case *ast.ExportDefaultDeclaration:
+			export("default", n.Start)
+		case *ast.ExportAllDeclaration:
+			if n.Exported != nil {
+				export(n.Exported.Name, n.Exported.Start)
+			}
+		}
+	}
+}
+
+// checkSubStatement checks a statement that is not part of a statement
+// list, e.g. the body of an if statement. A function declaration in such a
+// position, which is only allowed in non-strict code, is treated as if it
+// was the only statement of a block, as specified in B.3.4.
+func (c *earlyErrorChecker) checkSubStatement(stmt ast.Statement) {
+	if _, ok := stmt.(*ast.FunctionDeclaration); ok {
+		c.enterScope(newScope(false))
+		c.checkStatement(stmt)
+		c.exitScope()
+		return
+	}
+	c.checkStatement(stmt)
+}
+
+func (c *earlyErrorChecker) checkLoopBody(body ast.Statement) {
+	c.fn.loops++
+	c.checkSubStatement(body)
+	c.fn.loops--
+}
+
+func (c *earlyErrorChecker) checkForInOf(left ast.Node, right ast.Expression, body ast.Statement, forOf bool) {
+	c.enterScope(newScope(false))
+	if decl, ok := left.(*ast.VariableDeclaration); ok {
+		c.checkVariableDeclaration(decl, forOf)
+	} else {
+		c.checkPattern(left.(ast.Pattern), bindNone, false)
+	}
+	c.checkExpression(right)
+	c.checkLoopBody(body)
+	c.exitScope()
+}
+
+func (c *earlyErrorChecker) checkVariableDeclaration(decl *ast.VariableDeclaration, forOf bool) {
+	kind := bindLexical
+	if decl.Kind == ast.VariableKindVar {
+		kind = bindVar
+	}
+	for _, d := range decl.Declarations {
+		c.checkPattern(d.ID, kind, forOf)
+		c.checkOptionalExpression(d.Init)
+	}
+}
+
+func (c *earlyErrorChecker) checkCatchClause(clause *ast.CatchClause) {
+	// the parameter and the body share a scope, so that the body must not
+	// redeclare the parameter
+	s := newScope(false)
+	c.enterScope(s)
+	if id, ok := clause.Param.(*ast.Identifier); ok {
+		s.simpleCatch = id.Name
+		c.checkBindingIdentifier(id, c.strict)
+		c.declare(id, bindSimpleCatch, false)
+	} else if clause.Param != nil {
+		c.checkPattern(clause.Param, bindLexical, false)
+	}
+	c.checkStatements(clause.Body.Body)
+	c.exitScope()
+}
+
+func (c *earlyErrorChecker) checkLabeledStatement(n *ast.LabeledStatement) {
+	c.checkIdentifierReference(n.Label)
+	for _, l := range c.fn.labels {
+		if l.name == n.Label.Name {
+			c.errorAt(n.Label.Start, "Label '%v' has already been declared", n.Label.Name)
+		}
+	}
+
+	// a label of a label of an iteration statement is a continue target
+	// as well
+	body := n.Body
+	for {
+		labeled, ok := body.(*ast.LabeledStatement)
+		if !ok {
+			break
+		}
+		body = labeled.Body
+	}
+	iteration := false
+	switch body.(type) {
+	case *ast.ForStatement, *ast.ForInStatement, *ast.ForOfStatement, *ast.WhileStatement, *ast.DoWhileStatement:
+		iteration = true
+	}
+
+	// a labelled function declaration is declared in the enclosing scope,
+	// as specified in B.3.2
+	c.fn.labels = append(c.fn.labels, label{name: n.Label.Name, iteration: iteration})
+	c.checkStatement(n.Body)
+	c.fn.labels = c.fn.labels[:len(c.fn.labels)-1]
+}
+
+// findLabel returns the enclosing label with the given name, or nil if no
+// such label exists.
+func (c *earlyErrorChecker) findLabel(id *ast.Identifier) *label {
+	for i := len(c.fn.labels) - 1; i >= 0; i-- {
+		if c.fn.labels[i].name == id.Name {
+			return &c.fn.labels[i]
+		}
+	}
+	return nil
+}
+
+func (c *earlyErrorChecker) checkBreakStatement(n *ast.BreakStatement) {
+	if n.Label == nil {
+		if c.fn.loops == 0 && c.fn.switches == 0 {
+			c.errorAt(n.Start, "Illegal break statement")
+		}
+		return
+	}
+
+	c.checkIdentifierReference(n.Label)
+	if c.findLabel(n.Label) == nil {
+		c.errorAt(n.Label.Start, "Undefined label '%v'", n.Label.Name)
+	}
+}
+
+func (c *earlyErrorChecker) checkContinueStatement(n *ast.ContinueStatement) {
+	if n.Label == nil {
+		if c.fn.loops == 0 {
+			c.errorAt(n.Start, "Illegal continue statement: no surrounding iteration statement")
+		}
+		return
+	}
+
+	c.checkIdentifierReference(n.Label)
+	l := c.findLabel(n.Label)
+	switch {
+	case l == nil:
+		c.errorAt(n.Label.Start, "Undefined label '%v'", n.Label.Name)
+	case !l.iteration:
+		c.errorAt(n.Label.Start, "Illegal continue statement: '%v' does not denote an iteration statement", n.Label.Name)
+	}
+}
+
+// checkPattern checks a binding pattern or an assignment target, and
+// declares all bound names with the given kind.
+func (c *earlyErrorChecker) checkPattern(pattern ast.Pattern, kind bindingKind, forOf bool) {
+	switch n := pattern.(type) {
+	case *ast.Identifier:
+		c.checkBindingIdentifier(n, c.strict)
+		if kind != bindNone {
+			c.declare(n, kind, forOf)
+		}
+	case *ast.MemberExpression:
+		c.checkExpression(n)
+	case *ast.ObjectPattern:
+		for _, prop := range n.Properties {
+			switch prop := prop.(type) {
+			case *ast.Property:
+				if prop.Computed {
+					c.checkExpression(prop.Key)
+				}
+				c.checkPattern(prop.Value.(ast.Pattern), kind, forOf)
+			case *ast.RestElement:
+				c.checkPattern(prop.Argument, kind, forOf)
+			}
+		}
+	case *ast.ArrayPattern:
+		for _, element := range n.Elements {
+			if element != nil {
+				c.checkPattern(element, kind, forOf)
+			}
+		}
+	case *ast.RestElement:
+		c.checkPattern(n.Argument, kind, forOf)
+	case *ast.AssignmentPattern:
+		c.checkPattern(n.Left, kind, forOf)
+		c.checkExpression(n.Right)
+	default:
+		panic(fmt.Sprintf("unexpected pattern type %T", pattern))
+	}
+}
+
+func (c *earlyErrorChecker) checkOptionalExpression(expr ast.Expression) {
+	if expr != nil {
+		c.checkExpression(expr)
+	}
+}
+
+func (c *earlyErrorChecker) checkExpressions(exprs []ast.Expression) {
+	for _, expr := range exprs {
+		c.checkOptionalExpression(expr)
+	}
+}
+
+func (c *earlyErrorChecker) checkExpression(expr ast.Expression) {
+	switch n := expr.(type) {
+	case *ast.Identifier:
+		c.checkIdentifierReference(n)
+	case *ast.Literal:
+		c.checkLiteral(n)
+	case *ast.ThisExpression:
+		// nothing to check
+	case *ast.ArrayExpression:
+		c.checkExpressions(n.Elements)
+	case *ast.ObjectExpression:
+		c.checkObjectExpression(n)
+	case *ast.FunctionExpression:
+		c.checkFunction(&n.Function, functionKindNormal)
+	case *ast.ArrowFunctionExpression:
+		c.checkFunction(&n.Function, functionKindArrow)
+	case *ast.ClassExpression:
+		if n.ID != nil {
+			c.checkBindingIdentifier(n.ID, true)
+		}
+		c.checkClass(&n.Class)
+	case *ast.TemplateLiteral:
+		c.checkExpressions(n.Expressions)
+	case *ast.TaggedTemplateExpression:
+		c.checkExpression(n.Tag)
+		c.checkExpressions(n.Quasi.Expressions)
+	case *ast.UnaryExpression:
+		if _, ok := n.Argument.(*ast.Identifier); ok && n.Operator == "delete" && c.strict {
+			c.errorAt(n.Start, "Delete of an unqualified identifier in strict mode")
+		}
+		c.checkExpression(n.Argument)
+	case *ast.UpdateExpression:
+		c.checkPattern(n.Argument.(ast.Pattern), bindNone, false)
+	case *ast.BinaryExpression:
+		c.checkExpression(n.Left)
+		c.checkExpression(n.Right)
+	case *ast.LogicalExpression:
+		c.checkExpression(n.Left)
+		c.checkExpression(n.Right)
+	case *ast.AssignmentExpression:
+		c.checkPattern(n.Left, bindNone, false)
+		c.checkExpression(n.Right)
+	case *ast.ConditionalExpression:
+		c.checkExpression(n.Test)
+		c.checkExpression(n.Consequent)
+		c.checkExpression(n.Alternate)
+	case *ast.MemberExpression:
+		if super, ok := n.Object.(*ast.Super); ok {
+			if !c.fn.superProperty {
+				c.errorAt(super.Start, "'super' keyword unexpected here")
+			}
+		} else {
+			c.checkExpression(n.Object.(ast.Expression))
+		}
+		if n.Computed {
+			c.checkExpression(n.Property)
+		}
+	case *ast.CallExpression:
+		if super, ok := n.Callee.(*ast.Super); ok {
+			if !c.fn.superCall {
+				c.errorAt(super.Start, "'super' keyword unexpected here")
+			}
+		} else {
+			c.checkExpression(n.Callee.(ast.Expression))
+		}
+		c.checkExpressions(n.Arguments)
+	case *ast.NewExpression:
+		c.checkExpression(n.Callee)
+		c.checkExpressions(n.Arguments)
+	case *ast.SequenceExpression:
+		c.checkExpressions(n.Expressions)
+	case *ast.YieldExpression:
+		c.checkOptionalExpression(n.Argument)
+	case *ast.AwaitExpression:
+		c.checkExpression(n.Argument)
+	case *ast.SpreadElement:
+		c.checkExpression(n.Argument)
+	case *ast.MetaProperty:
+		if !c.fn.newTarget {
+			c.errorAt(n.Start, "new.target expression is not allowed here")
+		}
+	default:
+		panic(fmt.Sprintf("unexpected expression type %T", expr))
+	}
+}
+
+func (c *earlyErrorChecker) checkLiteral(n *ast.Literal) {
+	if n.Regex != nil {
+		if err := validateRegExp(n.Regex.Pattern, n.Regex.Flags); err != nil {
+			c.errorAt(n.Start, "Invalid regular expression: /%v/: %v", n.Regex.Pattern, err)
+		}
+		return
+	}
+	if !c.strict {
+		return
+	}
+
+	switch n.Value.(type) {
+	case float64:
+		if len(n.Raw) > 1 && n.Raw[0] == '0' && isDecimalDigit(rune(n.Raw[1])) {
+			c.errorAt(n.Start, "Octal literals are not allowed in strict mode")
+		}
+	case string:
+		if hasLegacyOctalEscape(n.Raw) {
+			c.errorAt(n.Start, "Octal escape sequences are not allowed in strict mode")
+		}
+	}
+}
+
+// hasLegacyOctalEscape reports whether the given raw string literal contains
+// a LegacyOctalEscapeSequence or a NonOctalDecimalEscapeSequence, i.e. a
+// backslash followed by a non-zero digit or by a zero that is followed by a
+// digit.
+func hasLegacyOctalEscape(raw string) bool {
+	for i := 0; i < len(raw)-1; i++ {
+		if raw[i] != '\\' {
+			continue
+		}
+		i++
+		switch {
+		case raw[i] >= '1' && raw[i] <= '9':
+			return true
+		case raw[i] == '0' && i+1 < len(raw) && isDecimalDigit(rune(raw[i+1])):
+			return true
+		}
+	}
+	return false
+}
+
+func (c *earlyErrorChecker) checkObjectExpression(n *ast.ObjectExpression) {
+	hasProto := false
+	for _, prop := range n.Properties {
+		switch prop := prop.(type) {
+		case *ast.Property:
+			if prop.Computed {
+				c.checkExpression(prop.Key)
+			} else if _, ok := prop.Key.(*ast.Literal); ok {
+				c.checkExpression(prop.Key)
+			}
+
+			if prop.Kind != ast.KindInit || prop.Method {
+				c.checkFunction(&prop.Value.(*ast.FunctionExpression).Function, functionKindMethod)
+				continue
+			}
+
+			if !prop.Computed && !prop.Shorthand && propertyName(prop.Key) == "__proto__" {
+				if hasProto {
+					c.errorAt(prop.Key.Loc().Start, "Duplicate __proto__ fields are not allowed in object literals")
+				}
+				hasProto = true
+			}
+			c.checkExpression(prop.Value.(ast.Expression))
+		case *ast.SpreadElement:
+			c.checkExpression(prop.Argument)
+		}
+	}
+}
+
+// functionKind distinguishes the kinds of functions, which differ in the
+// early errors that apply to them.
+type functionKind uint8
+
+const (
+	functionKindNormal functionKind = iota
+	functionKindArrow
+	functionKindMethod
+	functionKindConstructor
+	functionKindDerivedConstructor
+)
+
+func (c *earlyErrorChecker) checkFunctionDeclaration(n *ast.FunctionDeclaration) {
+	if n.ID != nil {
+		kind := bindFunction
+		if c.strict || n.Generator || n.Async {
+			kind = bindLexical
+		}
+		if s := c.currentScope(); s.isVar && !s.module {
+			// functions at the top level of a function or script are var
+			// scoped, they may only conflict with lexical declarations
+			kind = bindFunction
+		}
+		c.declare(n.ID, kind, false)
+	}
+	c.checkFunction(&n.Function, functionKindNormal)
+}
+
+// checkFunction checks the parameters and the body of a function. The name
+// of a function declaration must already be declared in the enclosing scope.
+func (c *earlyErrorChecker) checkFunction(fn *ast.Function, kind functionKind) {
+	oldStrict, oldFn := c.strict, c.fn
+	c.strict = fn.Strict
+
+	state := new(functionState)
+	switch kind {
+	case functionKindArrow:
+		state.newTarget = oldFn.newTarget
+		state.superProperty = oldFn.superProperty
+		state.superCall = oldFn.superCall
+	default:
+		state.newTarget = true
+		state.superProperty = kind != functionKindNormal
+		state.superCall = kind == functionKindDerivedConstructor
+	}
+	c.fn = state
+
+	if fn.ID != nil {
+		// the name is strict mode code if the function is
+		c.checkBindingIdentifier(fn.ID, fn.Strict)
+	}
+
+	simple := true
+	for _, param := range fn.Params {
+		if _, ok := param.(*ast.Identifier); !ok {
+			simple = false
+		}
+	}
+
+	body, hasBlock := fn.Body.(*ast.BlockStatement)
+	if hasBlock && !simple && hasUseStrictDirective(body) {
+		c.errorAt(body.Start, "Illegal 'use strict' directive in function with non-simple parameter list")
+	}
+
+	s := newScope(true)
+	c.enterScope(s)
+
+	allowDuplicates := !fn.Strict && kind == functionKindNormal && simple
+	names := make(map[string]bool)
+	for _, param := range fn.Params {
+		c.checkPattern(param, bindVar, false)
+		for _, id := range boundNames(param) {
+			if names[id.Name] && !allowDuplicates {
+				c.errorAt(id.Start, "Duplicate parameter name not allowed in this context")
+			}
+			names[id.Name] = true
+		}
+	}
+
+	if hasBlock {
+		c.checkStatements(body.Body)
+	} else {
+		c.checkExpression(fn.Body.(ast.Expression))
+	}
+
+	c.exitScope()
+	c.strict, c.fn = oldStrict, oldFn
+}
+
+// hasUseStrictDirective reports whether the directive prologue of the given
+// function body contains a Use Strict Directive.
+func hasUseStrictDirective(body *ast.BlockStatement) bool {
+	for _, stmt := range body.Body {
+		exprStmt, ok := stmt.(*ast.ExpressionStatement)
+		if !ok || exprStmt.Directive == "" {
+			return false
+		}
+		if exprStmt.Directive == "use strict" {
+			return true
+		}
+	}
+	return false
+}
+
+// boundNames returns all identifiers bound by the given pattern, as
+// specified in 13.3.3.1.
+func boundNames(pattern ast.Pattern) []*ast.Identifier {
+	switch n := pattern.(type) {
+	case *ast.Identifier:
+		return []*ast.Identifier{n}
+	case *ast.ObjectPattern:
+		var names []*ast.Identifier
+		for _, prop := range n.Properties {
+			switch prop := prop.(type) {
+			case *ast.Property:
+				names = append(names, boundNames(prop.Value.(ast.Pattern))...)
+			case *ast.RestElement:
+				names = append(names, boundNames(prop.Argument)...)
+			}
+		}
+		return names
+	case *ast.ArrayPattern:
+		var names []*ast.Identifier
+		for _, element := range n.Elements {
+			if element != nil {
+				names = append(names, boundNames(element)...)
+			}
+		}
+		return names
+	case *ast.RestElement:
+		return boundNames(n.Argument)
+	case *ast.AssignmentPattern:
+		return boundNames(n.Left)
+	}
+	return nil
+}
+
+// checkClass checks the heritage and the methods of a class. All parts of a
+// class are strict mode code. The name of a class must already be checked.
+func (c *earlyErrorChecker) checkClass(class *ast.Class) {
+	oldStrict := c.strict
+	c.strict = true
+	defer func() { c.strict = oldStrict }()
+
+	c.checkOptionalExpression(class.SuperClass)
+
+	hasConstructor := false
+	for _, method := range class.Body.Body {
+		if method.Computed {
+			c.checkExpression(method.Key)
+		} else if _, ok := method.Key.(*ast.Literal); ok {
+			c.checkExpression(method.Key)
+		}
+
+		name := ""
+		if !method.Computed {
+			name = propertyName(method.Key)
+		}
+
+		kind := functionKindMethod
+		switch {
+		case method.Kind == ast.KindConstructor:
+			if hasConstructor {
+				c.errorAt(method.Key.Loc().Start, "A class may only have one constructor")
+			}
+			hasConstructor = true
+			if method.Value.Generator {
+				c.errorAt(method.Key.Loc().Start, "Class constructor may not be a generator")
+			}
+			if method.Value.Async {
+				c.errorAt(method.Key.Loc().Start, "Class constructor may not be an async method")
+			}
+			kind = functionKindConstructor
+			if class.SuperClass != nil {
+				kind = functionKindDerivedConstructor
+			}
+		case method.Static && name == "prototype":
+			c.errorAt(method.Key.Loc().Start, "Classes may not have a static property named 'prototype'")
+		case !method.Static && name == "constructor":
+			// only getters and setters remain, since all other methods
+			// with this name are constructors
+			c.errorAt(method.Key.Loc().Start, "Class constructor may not be an accessor")
+		}
+
+		c.checkFunction(&method.Value.Function, kind)
+	}
+}
@@ -0,0 +1,166 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/gojisvm/gojis/internal/parser/ast"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEarlyErrorsValid(t *testing.T) {
+	tests := []string{
+		`var a; var a;`,
+		`function f() {} function f() {}`,
+		`var f; function f() {}`,
+		`{ function f() {} function f() {} }`,
+		`let a; { let a; }`,
+		`try {} catch (e) { var e; }`,
+		`try {} catch (e) { { let e; } }`,
+		`for (let a;;) { let a; }`,
+		`function f(a) { var a; function a() {} }`,
+		`function f(a, a) {}`,
+		`var let; var yield; var static; eval = 1; arguments++;`,
+		`if (a) function f() {} else function f() {}`,
+		`class A extends B { constructor() { super(); () => super(); } m() { super.m(); } }`,
+		`({ m() { super.m(); }, get a() { return super.a; } })`,
+		`function f() { new.target; () => new.target; }`,
+		`a: b: while (true) { continue a; }`,
+		`a: { break a; } a: ;`,
+		`switch (a) { case 1: break; }`,
+		`({ __proto__: a, __proto__: b } = c)`,
+		`({ __proto__: a, __proto__ })`,
+		`({ __proto__: a, ["__proto__"]: b })`,
+		`010; "\01"; "\8"; with (a) {} delete a;`,
+		`"use strict"; 0; 0.5; "\0"; "\\1"; delete a.b;`,
+		`class A { static constructor() {} static constructor() {} prototype() {} }`,
+		`function f(a) { "use strict"; }`,
+		`/(?<a>x)\k<a>/; /\1(a)/; /[\d-a]/; /\c/; /{/; /a{,5}/; /(?=a)*/; /\u{41}/u; /\p{Script=Latin}/u`,
+	}
+
+	for _, src := range tests {
+		t.Run(src, func(t *testing.T) {
+			require := require.New(t)
+
+			p := New()
+			require.NoError(p.ParseString("test.js", src))
+		})
+	}
+}
+
+func TestEarlyErrorsInvalid(t *testing.T) {
+	tests := []struct {
+		src     string
+		pos     ast.Position
+		message string
+	}{
+		{`let a; var a;`, ast.Position{Offset: 11, Line: 1, Column: 11}, "Identifier 'a' has already been declared"},
+		{`var a; let a;`, ast.Position{Offset: 11, Line: 1, Column: 11}, "Identifier 'a' has already been declared"},
+		{`let a; { var a; }`, ast.Position{Offset: 13, Line: 1, Column: 13}, "Identifier 'a' has already been declared"},
+		{`function f() {} let f;`, ast.Position{Offset: 20, Line: 1, Column: 20}, "Identifier 'f' has already been declared"},
+		{`{ function f() {} let f; }`, ast.Position{Offset: 22, Line: 1, Column: 22}, "Identifier 'f' has already been declared"},
+		{`{ async function f() {} function f() {} }`, ast.Position{Offset: 33, Line: 1, Column: 33}, "Identifier 'f' has already been declared"},
+		{`"use strict"; { function f() {} function f() {} }`, ast.Position{Offset: 41, Line: 1, Column: 41}, "Identifier 'f' has already been declared"},
+		{`switch (a) { case 1: let b; default: let b; }`, ast.Position{Offset: 41, Line: 1, Column: 41}, "Identifier 'b' has already been declared"},
+		{`try {} catch (e) { let e; }`, ast.Position{Offset: 23, Line: 1, Column: 23}, "Identifier 'e' has already been declared"},
+		{`try {} catch ([e]) { var e; }`, ast.Position{Offset: 25, Line: 1, Column: 25}, "Identifier 'e' has already been declared"},
+		{`for (let a of b) { var a; }`, ast.Position{Offset: 23, Line: 1, Column: 23}, "Identifier 'a' has already been declared"},
+		{`function f(a) { let a; }`, ast.Position{Offset: 20, Line: 1, Column: 20}, "Identifier 'a' has already been declared"},
+		{`let let = 1;`, ast.Position{Offset: 4, Line: 1, Column: 4}, "let is disallowed as a lexically bound name"},
+		{`function f(a, a) { "use strict"; }`, ast.Position{Offset: 14, Line: 1, Column: 14}, "Duplicate parameter name not allowed in this context"},
+		{`(a, a) => 1`, ast.Position{Offset: 4, Line: 1, Column: 4}, "Duplicate parameter name not allowed in this context"},
+		{`function f(a, [a]) {}`, ast.Position{Offset: 15, Line: 1, Column: 15}, "Duplicate parameter name not allowed in this context"},
+		{`({ m(a, a) {} })`, ast.Position{Offset: 8, Line: 1, Column: 8}, "Duplicate parameter name not allowed in this context"},
+		{`function f(a = 1) { "use strict"; }`, ast.Position{Offset: 18, Line: 1, Column: 18}, "Illegal 'use strict' directive in function with non-simple parameter list"},
+		{`function eval() { "use strict"; }`, ast.Position{Offset: 9, Line: 1, Column: 9}, "Unexpected eval or arguments in strict mode"},
+		{`function f(static) { "use strict"; }`, ast.Position{Offset: 11, Line: 1, Column: 11}, "Unexpected strict mode reserved word 'static'"},
+		{`"use strict"; eval = 1;`, ast.Position{Offset: 14, Line: 1, Column: 14}, "Unexpected eval or arguments in strict mode"},
+		{`"use strict"; arguments++;`, ast.Position{Offset: 14, Line: 1, Column: 14}, "Unexpected eval or arguments in strict mode"},
+		{`"use strict"; ({ eval } = a);`, ast.Position{Offset: 17, Line: 1, Column: 17}, "Unexpected eval or arguments in strict mode"},
+		{`"use strict"; try {} catch (arguments) {}`, ast.Position{Offset: 28, Line: 1, Column: 28}, "Unexpected eval or arguments in strict mode"},
+		{`class eval {}`, ast.Position{Offset: 6, Line: 1, Column: 6}, "Unexpected eval or arguments in strict mode"},
+		{`"use strict"; var let;`, ast.Position{Offset: 18, Line: 1, Column: 18}, "Unexpected strict mode reserved word 'let'"},
+		{`"use strict"; implements;`, ast.Position{Offset: 14, Line: 1, Column: 14}, "Unexpected strict mode reserved word 'implements'"},
+		{`"use strict"; yield: ;`, ast.Position{Offset: 14, Line: 1, Column: 14}, "Unexpected strict mode reserved word 'yield'"},
+		{`"use strict"; with (a) {}`, ast.Position{Offset: 14, Line: 1, Column: 14}, "Strict mode code may not include a with statement"},
+		{`class A { m() { with (a) {} } }`, ast.Position{Offset: 16, Line: 1, Column: 16}, "Strict mode code may not include a with statement"},
+		{`"use strict"; 010`, ast.Position{Offset: 14, Line: 1, Column: 14}, "Octal literals are not allowed in strict mode"},
+		{`"use strict"; 08`, ast.Position{Offset: 14, Line: 1, Column: 14}, "Octal literals are not allowed in strict mode"},
+		{`"use strict"; ({ 01: a })`, ast.Position{Offset: 17, Line: 1, Column: 17}, "Octal literals are not allowed in strict mode"},
+		{`"use strict"; "\01"`, ast.Position{Offset: 14, Line: 1, Column: 14}, "Octal escape sequences are not allowed in strict mode"},
+		{`"use strict"; "\8"`, ast.Position{Offset: 14, Line: 1, Column: 14}, "Octal escape sequences are not allowed in strict mode"},
+		{`function f() { "\01"; "use strict"; }`, ast.Position{Offset: 15, Line: 1, Column: 15}, "Octal escape sequences are not allowed in strict mode"},
+		{`"use strict"; delete a;`, ast.Position{Offset: 14, Line: 1, Column: 14}, "Delete of an unqualified identifier in strict mode"},
+		{`class A { constructor() {} constructor() {} }`, ast.Position{Offset: 27, Line: 1, Column: 27}, "A class may only have one constructor"},
+		{`class A { get constructor() {} }`, ast.Position{Offset: 14, Line: 1, Column: 14}, "Class constructor may not be an accessor"},
+		{`class A { *constructor() {} }`, ast.Position{Offset: 11, Line: 1, Column: 11}, "Class constructor may not be a generator"},
+		{`class A { async constructor() {} }`, ast.Position{Offset: 16, Line: 1, Column: 16}, "Class constructor may not be an async method"},
+		{`class A { static prototype() {} }`, ast.Position{Offset: 17, Line: 1, Column: 17}, "Classes may not have a static property named 'prototype'"},
+		{`class A { constructor() { super(); } }`, ast.Position{Offset: 26, Line: 1, Column: 26}, "'super' keyword unexpected here"},
+		{`class A extends B { m() { super(); } }`, ast.Position{Offset: 26, Line: 1, Column: 26}, "'super' keyword unexpected here"},
+		{`({ m: function () { super.m; } })`, ast.Position{Offset: 20, Line: 1, Column: 20}, "'super' keyword unexpected here"},
+		{`super.m;`, ast.Position{Offset: 0, Line: 1, Column: 0}, "'super' keyword unexpected here"},
+		{`new.target`, ast.Position{Offset: 0, Line: 1, Column: 0}, "new.target expression is not allowed here"},
+		{`() => new.target`, ast.Position{Offset: 6, Line: 1, Column: 6}, "new.target expression is not allowed here"},
+		{`a: a: ;`, ast.Position{Offset: 3, Line: 1, Column: 3}, "Label 'a' has already been declared"},
+		{`break;`, ast.Position{Offset: 0, Line: 1, Column: 0}, "Illegal break statement"},
+		{`switch (a) { case 1: continue; }`, ast.Position{Offset: 21, Line: 1, Column: 21}, "Illegal continue statement: no surrounding iteration statement"},
+		{`while (a) { function f() { break; } }`, ast.Position{Offset: 27, Line: 1, Column: 27}, "Illegal break statement"},
+		{`a: while (b) { break c; }`, ast.Position{Offset: 21, Line: 1, Column: 21}, "Undefined label 'c'"},
+		{`a: { continue a; }`, ast.Position{Offset: 14, Line: 1, Column: 14}, "Illegal continue statement: 'a' does not denote an iteration statement"},
+		{`a: while (b) { () => { break a; }; }`, ast.Position{Offset: 29, Line: 1, Column: 29}, "Undefined label 'a'"},
+		{`({ __proto__: a, "__proto__": b })`, ast.Position{Offset: 17, Line: 1, Column: 17}, "Duplicate __proto__ fields are not allowed in object literals"},
+		{`/a{2,1}/`, ast.Position{Offset: 0, Line: 1, Column: 0}, "Invalid regular expression: /a{2,1}/: numbers out of order in {} quantifier"},
+		{`a = /(?<n>a)\k<m>/`, ast.Position{Offset: 4, Line: 1, Column: 4}, `Invalid regular expression: /(?<n>a)\k<m>/: Invalid named capture referenced`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.src, func(t *testing.T) {
+			require := require.New(t)
+
+			p := New()
+			err := p.ParseString("test.js", test.src)
+			require.Error(err)
+			require.IsType(ParserError{}, err)
+
+			errs := err.(ParserError).Errors()
+			require.Len(errs, 1)
+			e := errs[0].(*Error)
+			require.Equal("test.js", e.File)
+			require.Equal(test.pos, e.Pos)
+			require.Equal(test.message, e.Message)
+			require.Empty(e.Expected)
+		})
+	}
+}
+
+func TestEarlyErrorsReportsAll(t *testing.T) {
+	require := require.New(t)
+
+	p := New()
+	err := p.ParseString("test.js", "\"use strict\";\nwith (a) {}\nlet b; var b;\nbreak;")
+	require.Error(err)
+
+	errs := err.(ParserError).Errors()
+	require.Len(errs, 3)
+	require.Equal(2, errs[0].(*Error).Pos.Line)
+	require.Equal(3, errs[1].(*Error).Pos.Line)
+	require.Equal(4, errs[2].(*Error).Pos.Line)
+	require.Equal(0, p.Ast().Len())
+}
+
+func TestEarlyErrorsStrict(t *testing.T) {
+	require := require.New(t)
+
+	p := New()
+	require.NoError(p.ParseString("sloppy.js", `with (a) {} var static; 010;`))
+	sloppy, _ := p.Ast().Root("sloppy.js")
+	require.False(sloppy.Strict)
+
+	p.SetStrict(true)
+	err := p.ParseString("strict.js", `with (a) {}`)
+	require.Error(err)
+	require.Contains(err.Error(), "Strict mode code may not include a with statement")
+	require.NoError(p.ParseString("strict-valid.js", `var a = 1;`))
+	strict, _ := p.Ast().Root("strict-valid.js")
+	require.True(strict.Strict)
+}
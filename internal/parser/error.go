@@ -0,0 +1,74 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/gojisvm/gojis/internal/parser/ast"
+)
+
+// Error is a syntax error at a position in the source code.
+type Error struct {
+	// File is the name of the source that contains the error.
+	File string
+	// Pos is the position of the error in the source code. Like all
+	// positions of the AST, its column starts at 0, but the column is
+	// printed starting at 1, just like the columns of stack frames.
+	Pos ast.Position
+	// Token is the source text of the offending token, which is the token
+	// at Pos. It is empty if the error occurred at the end of the input.
+	Token string
+	// Expected describes what the parser expected instead of the offending
+	// token, e.g. "';'" or "identifier". It is empty if there is no single
+	// expectation, which is the case for all early errors.
+	Expected string
+	// Message describes the error.
+	Message string
+}
+
+func (e *Error) Error() string {
+	if e.Expected != "" {
+		return fmt.Sprintf("Syntax error at %v:%v:%v: %v, expected %v", e.File, e.Pos.Line, e.Pos.Column+1, e.Message, e.Expected)
+	}
+	return fmt.Sprintf("Syntax error at %v:%v:%v: %v", e.File, e.Pos.Line, e.Pos.Column+1, e.Message)
+}
+
+// sortErrors sorts the given errors by their position in the source code.
+// Errors that are not syntax errors keep their order after the syntax
+// errors, and syntax errors at the same position keep the order in which
+// they were reported.
+func sortErrors(errs []error) {
+	sort.SliceStable(errs, func(i, j int) bool {
+		a, ok := errs[i].(*Error)
+		if !ok {
+			return false
+		}
+		b, ok := errs[j].(*Error)
+		if !ok {
+			return true
+		}
+		return a.Pos.Offset < b.Pos.Offset
+	})
+}
+
+// ErrorListener is notified about every syntax error that occurs while
+// parsing.
+type ErrorListener interface {
+	SyntaxError(err *Error)
+}
+
+// tokenText returns the source text of the token that starts at the given
+// position. A slash is assumed to start a regular expression, since errors
+// are never reported at a division operator.
+func tokenText(src string, pos ast.Position) string {
+	l := newLexer(src, false, func(ast.Position, string) {})
+	l.reset(pos)
+	t := l.next()
+	if t.kind == tokenEOF {
+		return ""
+	}
+	if t.kind == tokenPunctuator && (t.value == "/" || t.value == "/=") {
+		t = l.rescanRegExp(t)
+	}
+	return t.raw
+}
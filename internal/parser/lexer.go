@@ -0,0 +1,824 @@
+package parser
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/gojisvm/gojis/internal/parser/ast"
+)
+
+// Characters with a special meaning in ECMAScript source code.
+const (
+	charZWNJ = '\u200C'
+	charZWJ  = '\u200D'
+	charLS   = '\u2028'
+	charPS   = '\u2029'
+	charBOM  = '\uFEFF'
+	charNBSP = '\u00A0'
+)
+
+// lexer converts ECMAScript source code into tokens. The lexer is driven by the
+// parser, which decides whether a slash starts a regular expression or is a
+// division operator, and when a template literal continues after a
+// substitution. To do this, the parser rescans the current token with
+// rescanRegExp and rescanTemplate.
+//
+// A lexer is a value type, so the state of a lexer can be saved by copying it,
+// which is used by the parser to look ahead.
+type lexer struct {
+	src    string
+	offset int
+	line   int
+	column int
+
+	// module is true if the source code is parsed as module, in which case
+	// HTML-like comments are not allowed.
+	module bool
+
+	// onError is called for every error encountered by the lexer. The lexer
+	// continues after reporting an error, but the produced token may be
+	// incomplete.
+	onError func(pos ast.Position, msg string)
+}
+
+// newLexer creates a new lexer for the given source code.
+func newLexer(src string, module bool, onError func(ast.Position, string)) lexer {
+	l := lexer{
+		src:     src,
+		line:    1,
+		module:  module,
+		onError: onError,
+	}
+	return l
+}
+
+func (l *lexer) pos() ast.Position {
+	return ast.Position{
+		Offset: l.offset,
+		Line:   l.line,
+		Column: l.column,
+	}
+}
+
+func (l *lexer) error(pos ast.Position, msg string) {
+	l.onError(pos, msg)
+}
+
+func (l *lexer) eof() bool {
+	return l.offset >= len(l.src)
+}
+
+// peek returns the rune at the current offset without consuming it, or -1 if
+// the end of the source code is reached.
+func (l *lexer) peek() rune {
+	return l.peekAt(0)
+}
+
+// peekAt returns the rune that starts n bytes after the current offset. For
+// n > 0, only use this with ASCII characters before.
+func (l *lexer) peekAt(n int) rune {
+	if l.offset+n >= len(l.src) {
+		return -1
+	}
+	if c := l.src[l.offset+n]; c < utf8.RuneSelf {
+		return rune(c)
+	}
+	r, _ := utf8.DecodeRuneInString(l.src[l.offset+n:])
+	return r
+}
+
+// advance consumes one rune and returns it, updating the line and column
+// information.
+func (l *lexer) advance() rune {
+	if l.eof() {
+		return -1
+	}
+
+	r, size := rune(l.src[l.offset]), 1
+	if r >= utf8.RuneSelf {
+		r, size = utf8.DecodeRuneInString(l.src[l.offset:])
+	}
+	l.offset += size
+
+	switch r {
+	case '\r':
+		if l.peek() == '\n' {
+			// \r\n is one line terminator, the line is incremented by the \n
+			l.column++
+			break
+		}
+		fallthrough
+	case '\n', charLS, charPS:
+		l.line++
+		l.column = 0
+	default:
+		l.column++
+	}
+
+	return r
+}
+
+func isLineTerminator(r rune) bool {
+	return r == '\n' || r == '\r' || r == charLS || r == charPS
+}
+
+func isWhitespace(r rune) bool {
+	switch r {
+	case '\t', '\v', '\f', ' ', charNBSP, charBOM:
+		return true
+	}
+	return r > utf8.RuneSelf && unicode.Is(unicode.Zs, r)
+}
+
+func isIdentifierStart(r rune) bool {
+	if r < utf8.RuneSelf {
+		return r == '$' || r == '_' || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z')
+	}
+	return unicode.IsLetter(r) || unicode.Is(unicode.Nl, r) || unicode.Is(unicode.Other_ID_Start, r)
+}
+
+func isIdentifierPart(r rune) bool {
+	if r < utf8.RuneSelf {
+		return isIdentifierStart(r) || isDecimalDigit(r)
+	}
+	return isIdentifierStart(r) ||
+		r == charZWNJ || r == charZWJ ||
+		unicode.In(r, unicode.Mn, unicode.Mc, unicode.Nd, unicode.Pc, unicode.Other_ID_Continue)
+}
+
+func isDecimalDigit(r rune) bool {
+	return '0' <= r && r <= '9'
+}
+
+func isHexDigit(r rune) bool {
+	return isDecimalDigit(r) || ('a' <= r && r <= 'f') || ('A' <= r && r <= 'F')
+}
+
+func hexValue(r rune) int {
+	switch {
+	case isDecimalDigit(r):
+		return int(r - '0')
+	case 'a' <= r && r <= 'f':
+		return int(r-'a') + 10
+	case 'A' <= r && r <= 'F':
+		return int(r-'A') + 10
+	}
+	return -1
+}
+
+// skipWhitespaceAndComments skips all whitespace, line terminators and comments
+// at the current offset, and reports whether a line terminator was skipped.
+func (l *lexer) skipWhitespaceAndComments() (newline bool) {
+	// HTML close comments are only allowed at the start of a line
+	lineStart := l.offset == 0
+
+	for !l.eof() {
+		r := l.peek()
+		switch {
+		case isWhitespace(r):
+			l.advance()
+		case isLineTerminator(r):
+			l.advance()
+			newline = true
+			lineStart = true
+		case r == '/' && l.peekAt(1) == '/':
+			l.skipLineComment()
+		case r == '/' && l.peekAt(1) == '*':
+			if l.skipBlockComment() {
+				newline = true
+				lineStart = true
+			}
+		case r == '<' && !l.module && strings.HasPrefix(l.src[l.offset:], "<!--"):
+			l.skipLineComment()
+		case r == '-' && !l.module && lineStart && strings.HasPrefix(l.src[l.offset:], "-->"):
+			l.skipLineComment()
+		default:
+			return
+		}
+	}
+	return
+}
+
+func (l *lexer) skipLineComment() {
+	for !l.eof() && !isLineTerminator(l.peek()) {
+		l.advance()
+	}
+}
+
+// skipBlockComment skips a block comment and reports whether it contained a
+// line terminator.
+func (l *lexer) skipBlockComment() (newline bool) {
+	start := l.pos()
+	l.advance() // '/'
+	l.advance() // '*'
+	for !l.eof() {
+		r := l.advance()
+		if r == '*' && l.peek() == '/' {
+			l.advance()
+			return
+		}
+		if isLineTerminator(r) {
+			newline = true
+		}
+	}
+	l.error(start, "Unterminated comment")
+	return
+}
+
+// next scans the next token. A slash is always scanned as a division
+// operator, and a closing brace always as punctuator.
+func (l *lexer) next() token {
+	var t token
+	t.newlineBefore = l.skipWhitespaceAndComments()
+	t.start = l.pos()
+
+	if l.eof() {
+		t.kind = tokenEOF
+		t.end = t.start
+		return t
+	}
+
+	r := l.peek()
+	switch {
+	case isIdentifierStart(r) || r == '\\':
+		l.scanIdentifier(&t)
+	case isDecimalDigit(r) || (r == '.' && isDecimalDigit(l.peekAt(1))):
+		l.scanNumber(&t)
+	case r == '"' || r == '\'':
+		l.scanString(&t)
+	case r == '`':
+		l.advance()
+		l.scanTemplate(&t)
+	default:
+		l.scanPunctuator(&t)
+	}
+
+	t.end = l.pos()
+	t.raw = l.src[t.start.Offset:t.end.Offset]
+	return t
+}
+
+func (l *lexer) scanPunctuator(t *token) {
+	t.kind = tokenPunctuator
+	rest := l.src[l.offset:]
+	for n := maxPunctuatorLength; n > 0; n-- {
+		if len(rest) >= n && punctuators[rest[:n]] {
+			for i := 0; i < n; i++ {
+				l.advance()
+			}
+			t.value = rest[:n]
+			return
+		}
+	}
+
+	r := l.advance()
+	l.error(t.start, "Unexpected character '"+string(r)+"'")
+	t.value = string(r)
+}
+
+// scanIdentifierEscape scans a unicode escape sequence \uXXXX or \u{X...} in an
+// identifier, the backslash already being consumed.
+func (l *lexer) scanIdentifierEscape(start ast.Position) rune {
+	if l.peek() != 'u' {
+		l.error(start, "Invalid escape sequence in identifier")
+		return utf8.RuneError
+	}
+	l.advance()
+	r, ok := l.scanUnicodeEscapeValue()
+	if !ok {
+		l.error(start, "Invalid unicode escape sequence in identifier")
+		return utf8.RuneError
+	}
+	return r
+}
+
+func (l *lexer) scanIdentifier(t *token) {
+	t.kind = tokenName
+
+	start := l.offset
+	var buf strings.Builder
+	first := true
+	for !l.eof() {
+		r := l.peek()
+		pos := l.pos()
+		if r == '\\' {
+			if !t.escaped {
+				buf.WriteString(l.src[start:l.offset])
+				t.escaped = true
+			}
+			l.advance()
+			r = l.scanIdentifierEscape(pos)
+			if (first && !isIdentifierStart(r)) || (!first && !isIdentifierPart(r)) {
+				l.error(pos, "Invalid identifier escape sequence")
+			}
+			buf.WriteRune(r)
+			first = false
+			continue
+		}
+
+		if (first && !isIdentifierStart(r)) || (!first && !isIdentifierPart(r)) {
+			break
+		}
+
+		l.advance()
+		if t.escaped {
+			buf.WriteRune(r)
+		}
+		first = false
+	}
+
+	if t.escaped {
+		t.value = buf.String()
+	} else {
+		t.value = l.src[start:l.offset]
+	}
+}
+
+func (l *lexer) scanDigits(isDigit func(rune) bool) string {
+	start := l.offset
+	for isDigit(l.peek()) {
+		l.advance()
+	}
+	return l.src[start:l.offset]
+}
+
+func isOctalDigit(r rune) bool  { return '0' <= r && r <= '7' }
+func isBinaryDigit(r rune) bool { return r == '0' || r == '1' }
+
+func (l *lexer) scanNumber(t *token) {
+	t.kind = tokenNumber
+	start := l.offset
+
+	if l.peek() == '0' {
+		var base int
+		var isDigit func(rune) bool
+		switch l.peekAt(1) {
+		case 'x', 'X':
+			base, isDigit = 16, isHexDigit
+		case 'o', 'O':
+			base, isDigit = 8, isOctalDigit
+		case 'b', 'B':
+			base, isDigit = 2, isBinaryDigit
+		}
+
+		if base != 0 {
+			l.advance()
+			l.advance()
+			digits := l.scanDigits(isDigit)
+			if digits == "" {
+				l.error(t.start, "Missing digits after base prefix")
+			}
+			t.number = parseInteger(digits, base)
+			l.checkAfterNumber()
+			return
+		}
+
+		if isDecimalDigit(l.peekAt(1)) {
+			// legacy octal literal or non-octal decimal literal
+			t.octal = true
+			l.advance()
+			digits := l.scanDigits(isDecimalDigit)
+			if !strings.ContainsAny(digits, "89") {
+				t.number = parseInteger(digits, 8)
+				l.checkAfterNumber()
+				return
+			}
+			// non-octal decimal integer literals may have a fraction and an exponent
+		}
+	}
+
+	l.scanDigits(isDecimalDigit)
+	if l.peek() == '.' {
+		l.advance()
+		l.scanDigits(isDecimalDigit)
+	}
+	if r := l.peek(); r == 'e' || r == 'E' {
+		l.advance()
+		if r := l.peek(); r == '+' || r == '-' {
+			l.advance()
+		}
+		if l.scanDigits(isDecimalDigit) == "" {
+			l.error(t.start, "Missing exponent")
+		}
+	}
+
+	raw := l.src[start:l.offset]
+	f, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		// ParseFloat returns +/-Inf with an error on overflow, which is the
+		// value we want
+		if numErr, ok := err.(*strconv.NumError); !ok || numErr.Err != strconv.ErrRange {
+			l.error(t.start, "Invalid number")
+		}
+	}
+	t.number = f
+	l.checkAfterNumber()
+}
+
+// checkAfterNumber reports an error if a numeric literal is immediately followed
+// by an identifier start or a digit.
+func (l *lexer) checkAfterNumber() {
+	if r := l.peek(); r != -1 && (isIdentifierStart(r) || isDecimalDigit(r) || r == '\\') {
+		l.error(l.pos(), "Identifier starts immediately after numeric literal")
+	}
+}
+
+// parseInteger parses the given digits in the given base. Large values are
+// correctly rounded to the nearest float64.
+func parseInteger(digits string, base int) float64 {
+	if digits == "" {
+		return 0
+	}
+	if v, err := strconv.ParseUint(digits, base, 64); err == nil && v <= 1<<53 {
+		return float64(v)
+	}
+	i, ok := new(big.Int).SetString(digits, base)
+	if !ok {
+		return math.NaN()
+	}
+	f, _ := new(big.Float).SetInt(i).Float64()
+	return f
+}
+
+// scanHexDigits scans exactly n hex digits and returns their value, or -1 if
+// there are not enough hex digits.
+func (l *lexer) scanHexDigits(n int) rune {
+	var v rune
+	for i := 0; i < n; i++ {
+		r := l.peek()
+		if !isHexDigit(r) {
+			return -1
+		}
+		l.advance()
+		v = v<<4 | rune(hexValue(r))
+	}
+	return v
+}
+
+// scanUnicodeEscapeValue scans the part of a unicode escape sequence after \u,
+// i.e. XXXX or {X...}, and returns the code point.
+func (l *lexer) scanUnicodeEscapeValue() (rune, bool) {
+	if l.peek() != '{' {
+		v := l.scanHexDigits(4)
+		return v, v >= 0
+	}
+
+	l.advance()
+	var v rune
+	digits := 0
+	for isHexDigit(l.peek()) {
+		v = v<<4 | rune(hexValue(l.advance()))
+		digits++
+		if v > unicode.MaxRune {
+			return 0, false
+		}
+	}
+	if digits == 0 || l.peek() != '}' {
+		return 0, false
+	}
+	l.advance()
+	return v, true
+}
+
+// codeUnits is a buffer of UTF-16 code units, which is used to build the value
+// of string literals and templates. Using code units allows representing lone
+// surrogates.
+type codeUnits []uint16
+
+func (c *codeUnits) appendRune(r rune) {
+	if r >= 0x10000 {
+		r1, r2 := utf16.EncodeRune(r)
+		*c = append(*c, uint16(r1), uint16(r2))
+		return
+	}
+	*c = append(*c, uint16(r))
+}
+
+func (c *codeUnits) appendString(s string) {
+	for _, r := range s {
+		c.appendRune(r)
+	}
+}
+
+// String converts the code units to a string. Surrogate pairs are combined,
+// lone surrogates are encoded in the generalized UTF-8 form (WTF-8), so that
+// they can be restored when the string is converted back to code units.
+func (c codeUnits) String() string {
+	var buf strings.Builder
+	for i := 0; i < len(c); i++ {
+		u := rune(c[i])
+		if utf16.IsSurrogate(u) && i+1 < len(c) {
+			if r := utf16.DecodeRune(u, rune(c[i+1])); r != unicode.ReplacementChar {
+				buf.WriteRune(r)
+				i++
+				continue
+			}
+		}
+		if utf16.IsSurrogate(u) {
+			buf.WriteByte(byte(0xE0 | u>>12))
+			buf.WriteByte(byte(0x80 | (u>>6)&0x3F))
+			buf.WriteByte(byte(0x80 | u&0x3F))
+			continue
+		}
+		buf.WriteRune(u)
+	}
+	return buf.String()
+}
+
+func (l *lexer) scanString(t *token) {
+	t.kind = tokenString
+	quote := l.advance()
+	start := l.offset
+
+	var cooked codeUnits
+	escaped := false
+	for {
+		if l.eof() {
+			l.error(t.start, "Unterminated string literal")
+			break
+		}
+
+		r := l.peek()
+		if r == quote {
+			break
+		}
+		if r == '\n' || r == '\r' {
+			l.error(t.start, "Unterminated string literal")
+			break
+		}
+
+		if r != '\\' {
+			l.advance()
+			if escaped {
+				cooked.appendRune(r)
+			}
+			continue
+		}
+
+		if !escaped {
+			cooked.appendString(l.src[start:l.offset])
+			escaped = true
+		}
+
+		pos := l.pos()
+		l.advance() // '\'
+		ok, octal := l.scanEscape(&cooked, false)
+		if !ok {
+			l.error(pos, "Invalid escape sequence")
+		}
+		if octal {
+			t.octal = true
+		}
+	}
+
+	if escaped {
+		t.value = cooked.String()
+	} else {
+		t.value = l.src[start:l.offset]
+	}
+
+	if !l.eof() && l.peek() == quote {
+		l.advance()
+	}
+}
+
+// scanEscape scans an escape sequence in a string literal or a template, the
+// backslash already being consumed, and appends the escaped value to the given
+// buffer. It reports whether the escape sequence was valid, and whether it was
+// a legacy octal escape sequence, which is not allowed in templates.
+func (l *lexer) scanEscape(buf *codeUnits, template bool) (ok, octal bool) {
+	r := l.advance()
+	switch r {
+	case -1:
+		return false, false
+	case 'n':
+		buf.appendRune('\n')
+	case 't':
+		buf.appendRune('\t')
+	case 'r':
+		buf.appendRune('\r')
+	case 'b':
+		buf.appendRune('\b')
+	case 'f':
+		buf.appendRune('\f')
+	case 'v':
+		buf.appendRune('\v')
+	case '\r':
+		// line continuation, \r\n is a single line terminator
+		if l.peek() == '\n' {
+			l.advance()
+		}
+	case '\n', charLS, charPS:
+		// line continuation
+	case 'x':
+		v := l.scanHexDigits(2)
+		if v < 0 {
+			return false, false
+		}
+		buf.appendRune(v)
+	case 'u':
+		v, valid := l.scanUnicodeEscapeValue()
+		if !valid {
+			return false, false
+		}
+		if v <= 0xFFFF {
+			// may be a lone surrogate, which must be preserved
+			*buf = append(*buf, uint16(v))
+		} else {
+			buf.appendRune(v)
+		}
+	case '0', '1', '2', '3', '4', '5', '6', '7':
+		if r == '0' && !isDecimalDigit(l.peek()) {
+			buf.appendRune(0)
+			return true, false
+		}
+		if template {
+			return false, true
+		}
+		// legacy octal escape sequence with up to three digits and a value of at most 0377
+		v := r - '0'
+		if isOctalDigit(l.peek()) {
+			v = v*8 + (l.advance() - '0')
+			if r <= '3' && isOctalDigit(l.peek()) {
+				v = v*8 + (l.advance() - '0')
+			}
+		}
+		buf.appendRune(v)
+		return true, true
+	case '8', '9':
+		if template {
+			return false, true
+		}
+		buf.appendRune(r)
+		return true, true
+	default:
+		buf.appendRune(r)
+	}
+	return true, false
+}
+
+// scanTemplate scans a template, starting after the opening backtick or the
+// closing brace of a substitution, up to and including the closing backtick
+// or the '${' starting the next substitution.
+func (l *lexer) scanTemplate(t *token) {
+	t.kind = tokenTemplate
+
+	var cooked codeUnits
+	var raw strings.Builder
+	for {
+		if l.eof() {
+			l.error(t.start, "Unterminated template literal")
+			t.tail = true
+			break
+		}
+
+		r := l.peek()
+		if r == '`' {
+			l.advance()
+			t.tail = true
+			break
+		}
+		if r == '$' && l.peekAt(1) == '{' {
+			l.advance()
+			l.advance()
+			break
+		}
+
+		if r == '\\' {
+			escStart := l.offset
+			l.advance()
+			ok, _ := l.scanEscape(&cooked, true)
+			if !ok {
+				t.invalidEscape = true
+				// skip the rest of the invalid escape sequence up to the next
+				// character that could end the template
+				for r := l.peek(); !l.eof() && r != '`' && r != '\\' && r != '$' && !isLineTerminator(r) && isIdentifierPart(r); r = l.peek() {
+					l.advance()
+				}
+			}
+			raw.WriteString(normalizeLineTerminators(l.src[escStart:l.offset]))
+			continue
+		}
+
+		l.advance()
+		if r == '\r' {
+			// \r and \r\n are normalized to \n in both cooked and raw values
+			if l.peek() == '\n' {
+				l.advance()
+			}
+			r = '\n'
+		}
+		cooked.appendRune(r)
+		raw.WriteRune(r)
+	}
+
+	t.value = cooked.String()
+	t.pattern = raw.String() // the raw value of the template, used for TemplateElement.Raw
+}
+
+func normalizeLineTerminators(s string) string {
+	if !strings.ContainsRune(s, '\r') {
+		return s
+	}
+	s = strings.Replace(s, "\r\n", "\n", -1)
+	return strings.Replace(s, "\r", "\n", -1)
+}
+
+// rescanTemplate scans the continuation of a template literal after a
+// substitution. The given token must be the closing brace of the substitution.
+func (l *lexer) rescanTemplate(brace token) token {
+	l.reset(brace.start)
+
+	var t token
+	t.newlineBefore = brace.newlineBefore
+	t.start = l.pos()
+	l.advance() // '}'
+	l.scanTemplate(&t)
+	t.end = l.pos()
+	t.raw = l.src[t.start.Offset:t.end.Offset]
+	return t
+}
+
+// rescanRegExp scans a regular expression literal. The given token must be the
+// '/' or '/=' punctuator that starts the regular expression.
+func (l *lexer) rescanRegExp(slash token) token {
+	l.reset(slash.start)
+
+	var t token
+	t.kind = tokenRegExp
+	t.newlineBefore = slash.newlineBefore
+	t.start = l.pos()
+	l.advance() // '/'
+
+	patternStart := l.offset
+	inClass := false
+	for {
+		r := l.peek()
+		if r == -1 || isLineTerminator(r) {
+			l.error(t.start, "Unterminated regular expression")
+			break
+		}
+		if r == '/' && !inClass {
+			break
+		}
+
+		l.advance()
+		switch r {
+		case '\\':
+			if r := l.peek(); r == -1 || isLineTerminator(r) {
+				l.error(t.start, "Unterminated regular expression")
+				continue
+			}
+			l.advance()
+		case '[':
+			inClass = true
+		case ']':
+			inClass = false
+		}
+	}
+	t.pattern = l.src[patternStart:l.offset]
+	if l.peek() == '/' {
+		l.advance()
+	}
+
+	flagsStart := l.offset
+	for r := l.peek(); r != -1 && (isIdentifierPart(r) || r == '\\'); r = l.peek() {
+		if r == '\\' {
+			l.error(l.pos(), "Invalid regular expression flags")
+		}
+		l.advance()
+	}
+	t.flags = l.src[flagsStart:l.offset]
+	if !validRegExpFlags(t.flags) {
+		l.error(t.start, "Invalid regular expression flags")
+	}
+
+	t.end = l.pos()
+	t.raw = l.src[t.start.Offset:t.end.Offset]
+	return t
+}
+
+// validRegExpFlags is used to determine whether all given flags are valid
+// regular expression flags, and no flag appears twice.
+func validRegExpFlags(flags string) bool {
+	seen := make(map[rune]bool)
+	for _, f := range flags {
+		if !strings.ContainsRune("gimsuy", f) || seen[f] {
+			return false
+		}
+		seen[f] = true
+	}
+	return true
+}
+
+// reset moves the lexer back to the given position.
+func (l *lexer) reset(pos ast.Position) {
+	l.offset = pos.Offset
+	l.line = pos.Line
+	l.column = pos.Column
+}
@@ -0,0 +1,166 @@
+package parser
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gojisvm/gojis/internal/parser/ast"
+	"github.com/stretchr/testify/require"
+)
+
+func lex(t *testing.T, src string) []token {
+	l := newLexer(src, false, func(pos ast.Position, msg string) {
+		t.Fatalf("unexpected error at %v: %v", pos, msg)
+	})
+
+	var tokens []token
+	for {
+		tok := l.next()
+		if tok.kind == tokenEOF {
+			return tokens
+		}
+		tokens = append(tokens, tok)
+	}
+}
+
+func TestLexerNumbers(t *testing.T) {
+	tests := []struct {
+		src   string
+		value float64
+		octal bool
+	}{
+		{"0", 0, false},
+		{"42", 42, false},
+		{"1.5", 1.5, false},
+		{".5", .5, false},
+		{"5.", 5, false},
+		{"1e3", 1000, false},
+		{"1E-3", 0.001, false},
+		{"0x1F", 31, false},
+		{"0o17", 15, false},
+		{"0b101", 5, false},
+		{"017", 15, true},
+		{"019", 19, true},
+		{"08.5", 8.5, true},
+		{"0x20000000000001", 9007199254740992, false},
+		{"1e400", math.Inf(1), false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.src, func(t *testing.T) {
+			require := require.New(t)
+
+			tokens := lex(t, test.src)
+			require.Len(tokens, 1)
+			require.Equal(tokenNumber, tokens[0].kind)
+			require.Equal(test.value, tokens[0].number)
+			require.Equal(test.octal, tokens[0].octal)
+			require.Equal(test.src, tokens[0].raw)
+		})
+	}
+}
+
+func TestLexerStrings(t *testing.T) {
+	tests := []struct {
+		src   string
+		value string
+		octal bool
+	}{
+		{`"abc"`, "abc", false},
+		{`'a\'b'`, "a'b", false},
+		{`"\n\t\r\b\f\v\0"`, "\n\t\r\b\f\v\x00", false},
+		{`"\x41B\u{43}\u{1F600}"`, "ABC\U0001F600", false},
+		{`"😀"`, "\U0001F600", false},
+		{`"\uD800"`, "\xed\xa0\x80", false},
+		{"\"a\\\nb\"", "ab", false},
+		{"\"a\\\r\nb\"", "ab", false},
+		{`"\101"`, "A", true},
+		{`"\8"`, "8", true},
+		{`"\q"`, "q", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.src, func(t *testing.T) {
+			require := require.New(t)
+
+			tokens := lex(t, test.src)
+			require.Len(tokens, 1)
+			require.Equal(tokenString, tokens[0].kind)
+			require.Equal(test.value, tokens[0].value)
+			require.Equal(test.octal, tokens[0].octal)
+		})
+	}
+}
+
+func TestLexerIdentifiers(t *testing.T) {
+	require := require.New(t)
+
+	tokens := lex(t, `a $b _c \u0064 e\u{66} ÄÖÜ`)
+	require.Len(tokens, 6)
+	for i, name := range []string{"a", "$b", "_c", "d", "ef", "ÄÖÜ"} {
+		require.Equal(tokenName, tokens[i].kind)
+		require.Equal(name, tokens[i].value)
+	}
+	require.False(tokens[0].escaped)
+	require.True(tokens[3].escaped)
+	require.True(tokens[4].escaped)
+}
+
+func TestLexerPositions(t *testing.T) {
+	require := require.New(t)
+
+	tokens := lex(t, "a\n  ä b\r\nc /* \n */ d")
+	require.Len(tokens, 5)
+	require.Equal(ast.Position{Offset: 0, Line: 1, Column: 0}, tokens[0].start)
+	require.Equal(ast.Position{Offset: 4, Line: 2, Column: 2}, tokens[1].start)
+	require.Equal(ast.Position{Offset: 6, Line: 2, Column: 3}, tokens[1].end)
+	require.Equal(ast.Position{Offset: 7, Line: 2, Column: 4}, tokens[2].start)
+	require.Equal(ast.Position{Offset: 10, Line: 3, Column: 0}, tokens[3].start)
+	require.Equal(ast.Position{Offset: 20, Line: 4, Column: 4}, tokens[4].start)
+
+	require.False(tokens[0].newlineBefore)
+	require.True(tokens[1].newlineBefore)
+	require.False(tokens[2].newlineBefore)
+	require.True(tokens[3].newlineBefore)
+	require.True(tokens[4].newlineBefore)
+}
+
+func TestLexerRegExp(t *testing.T) {
+	require := require.New(t)
+
+	l := newLexer(`/[/]\//gi`, false, func(pos ast.Position, msg string) {
+		t.Fatalf("unexpected error at %v: %v", pos, msg)
+	})
+	tok := l.next()
+	require.Equal(tokenPunctuator, tok.kind)
+	require.Equal("/", tok.value)
+
+	tok = l.rescanRegExp(tok)
+	require.Equal(tokenRegExp, tok.kind)
+	require.Equal(`[/]\/`, tok.pattern)
+	require.Equal("gi", tok.flags)
+	require.Equal(tokenEOF, l.next().kind)
+}
+
+func TestLexerTemplate(t *testing.T) {
+	require := require.New(t)
+
+	l := newLexer("`a\\n${b}c\r\n`", false, func(pos ast.Position, msg string) {
+		t.Fatalf("unexpected error at %v: %v", pos, msg)
+	})
+	tok := l.next()
+	require.Equal(tokenTemplate, tok.kind)
+	require.False(tok.tail)
+	require.Equal("a\n", tok.value)
+	require.Equal(`a\n`, tok.pattern)
+
+	require.Equal("b", l.next().value)
+	brace := l.next()
+	require.Equal("}", brace.value)
+
+	tok = l.rescanTemplate(brace)
+	require.Equal(tokenTemplate, tok.kind)
+	require.True(tok.tail)
+	require.Equal("c\n", tok.value)
+	require.Equal("c\n", tok.pattern)
+}
@@ -0,0 +1,1079 @@
+package parser
+
+import (
+	"github.com/gojisvm/gojis/internal/parser/ast"
+)
+
+// expressionKeywords are the keywords that can start an expression.
+var expressionKeywords = map[string]bool{
+	"class":    true,
+	"delete":   true,
+	"false":    true,
+	"function": true,
+	"new":      true,
+	"null":     true,
+	"super":    true,
+	"this":     true,
+	"true":     true,
+	"typeof":   true,
+	"void":     true,
+}
+
+// startsExpression reports whether the current token can start an
+// expression.
+func (p *sourceParser) startsExpression() bool {
+	switch p.tok.kind {
+	case tokenName:
+		return !keywords[p.tok.value] || expressionKeywords[p.tok.value]
+	case tokenNumber, tokenString, tokenTemplate:
+		return true
+	case tokenPunctuator:
+		switch p.tok.value {
+		case "(", "[", "{", "+", "-", "!", "~", "++", "--", "/", "/=":
+			return true
+		}
+	}
+	return false
+}
+
+// checkExpressionErrors reports an error if the given destructuring errors
+// contain an error, i.e. if the expression that has been parsed with the
+// cover grammar is not converted to a pattern.
+func (p *sourceParser) checkExpressionErrors(refDestructuringErrors *destructuringErrors) {
+	if refDestructuringErrors != nil && refDestructuringErrors.shorthandAssign.IsValid() {
+		p.errorAt(refDestructuringErrors.shorthandAssign, "Shorthand property assignments are valid only in destructuring patterns")
+	}
+}
+
+// checkExpressionErrorsSince is like checkExpressionErrors, but only reports
+// errors at or after the given position. This is used if an expression
+// starting at the given position is used as operand, which means that it can
+// no longer be converted to a pattern.
+func (p *sourceParser) checkExpressionErrorsSince(refDestructuringErrors *destructuringErrors, start ast.Position) {
+	if refDestructuringErrors != nil && refDestructuringErrors.shorthandAssign.Offset >= start.Offset {
+		p.checkExpressionErrors(refDestructuringErrors)
+	}
+}
+
+// isArrowAt reports whether the given expression is an arrow function that
+// starts at the given position, i.e. is not wrapped in parentheses.
+func isArrowAt(expr ast.Expression, start ast.Position) bool {
+	arrow, ok := expr.(*ast.ArrowFunctionExpression)
+	return ok && arrow.Start.Offset == start.Offset
+}
+
+// isPlainIdentifier reports whether the given node is an identifier with the
+// given name, written without escape sequences.
+func isPlainIdentifier(node ast.Node, name string) bool {
+	id, ok := node.(*ast.Identifier)
+	return ok && id.Name == name && id.End.Offset-id.Start.Offset == len(name)
+}
+
+// parseExpression parses an Expression, i.e. a comma separated list of
+// assignment expressions. If noIn is true, the 'in' operator is not allowed.
+func (p *sourceParser) parseExpression(noIn bool, refDestructuringErrors *destructuringErrors) ast.Expression {
+	start := p.tok.start
+	expr := p.parseMaybeAssign(noIn, refDestructuringErrors)
+	if !p.is(",") {
+		return expr
+	}
+
+	exprs := []ast.Expression{expr}
+	for p.eat(",") {
+		exprs = append(exprs, p.parseMaybeAssign(noIn, refDestructuringErrors))
+	}
+	return &ast.SequenceExpression{
+		Location:    p.loc(start),
+		Expressions: exprs,
+	}
+}
+
+// parseMaybeAssign parses an AssignmentExpression. If the given destructuring
+// errors are nil, all errors that are only errors in expressions are reported
+// immediately, otherwise they are recorded in the given destructuring errors,
+// so that the caller can decide whether the expression is a pattern.
+func (p *sourceParser) parseMaybeAssign(noIn bool, refDestructuringErrors *destructuringErrors) ast.Expression {
+	p.enter()
+	defer p.leave()
+
+	if p.fn.generator && p.isName("yield") {
+		return p.parseYield(noIn)
+	}
+
+	ownDestructuringErrors := refDestructuringErrors == nil
+	if ownDestructuringErrors {
+		refDestructuringErrors = new(destructuringErrors)
+	}
+
+	start := p.tok.start
+	if p.is("(") || p.tok.kind == tokenName {
+		p.potentialArrowAt = start.Offset
+	}
+
+	left := p.parseMaybeConditional(noIn, refDestructuringErrors)
+	if p.tok.kind == tokenPunctuator && assignmentOperators[p.tok.value] {
+		operator := p.tok.value
+		var target ast.Pattern
+		if operator == "=" {
+			target = p.toAssignableTarget(left)
+		} else {
+			target = p.checkSimpleTarget(left)
+		}
+		if refDestructuringErrors.shorthandAssign.Offset >= start.Offset {
+			// the shorthand assignment is part of the pattern
+			refDestructuringErrors.shorthandAssign = ast.Position{}
+		}
+
+		p.next()
+		right := p.parseMaybeAssign(noIn, nil)
+		return &ast.AssignmentExpression{
+			Location: p.loc(start),
+			Operator: operator,
+			Left:     target,
+			Right:    right,
+		}
+	}
+
+	if ownDestructuringErrors {
+		p.checkExpressionErrors(refDestructuringErrors)
+	}
+	return left
+}
+
+// parseMaybeConditional parses a ConditionalExpression.
+func (p *sourceParser) parseMaybeConditional(noIn bool, refDestructuringErrors *destructuringErrors) ast.Expression {
+	start := p.tok.start
+	expr := p.parseExprOps(noIn, refDestructuringErrors)
+	if isArrowAt(expr, start) || !p.is("?") {
+		return expr
+	}
+
+	p.checkExpressionErrorsSince(refDestructuringErrors, start)
+	p.next()
+	consequent := p.parseMaybeAssign(false, nil)
+	p.expect(":")
+	alternate := p.parseMaybeAssign(noIn, nil)
+	return &ast.ConditionalExpression{
+		Location:   p.loc(start),
+		Test:       expr,
+		Consequent: consequent,
+		Alternate:  alternate,
+	}
+}
+
+// parseExprOps parses a sequence of binary operations.
+func (p *sourceParser) parseExprOps(noIn bool, refDestructuringErrors *destructuringErrors) ast.Expression {
+	start := p.tok.start
+	expr := p.parseMaybeUnary(refDestructuringErrors, false, noIn)
+	if isArrowAt(expr, start) || p.binaryOperatorPrecedence(noIn) == 0 {
+		return expr
+	}
+
+	p.checkExpressionErrorsSince(refDestructuringErrors, start)
+	return p.parseExprOp(expr, start, 0, noIn)
+}
+
+// binaryOperatorPrecedence returns the precedence of the current token if it
+// is a binary operator, and 0 otherwise.
+func (p *sourceParser) binaryOperatorPrecedence(noIn bool) int {
+	switch p.tok.kind {
+	case tokenPunctuator:
+		return binaryPrecedence[p.tok.value]
+	case tokenName:
+		if p.isName("instanceof") || (!noIn && p.isName("in")) {
+			return binaryPrecedence[p.tok.value]
+		}
+	}
+	return 0
+}
+
+// parseExprOp parses binary operations with a precedence higher than the given
+// minimum precedence, using the given expression as left operand. Every
+// operation is counted as nesting, since it holds the operations before it as
+// its left operand.
+func (p *sourceParser) parseExprOp(left ast.Expression, start ast.Position, minPrecedence int, noIn bool) ast.Expression {
+	depth := p.depth
+	defer func() { p.depth = depth }()
+
+	for {
+		precedence := p.binaryOperatorPrecedence(noIn)
+		if precedence <= minPrecedence {
+			return left
+		}
+
+		operator := p.tok.value
+		p.next()
+
+		rightStart := p.tok.start
+		right := p.parseMaybeUnary(nil, false, noIn)
+		if operator == "**" {
+			// exponentiation is right associative
+			p.enter()
+			right = p.parseExprOp(right, rightStart, precedence-1, noIn)
+			p.leave()
+		} else {
+			right = p.parseExprOp(right, rightStart, precedence, noIn)
+		}
+
+		if operator == "||" || operator == "&&" {
+			left = &ast.LogicalExpression{
+				Location: p.loc(start),
+				Operator: operator,
+				Left:     left,
+				Right:    right,
+			}
+		} else {
+			left = &ast.BinaryExpression{
+				Location: p.loc(start),
+				Operator: operator,
+				Left:     left,
+				Right:    right,
+			}
+		}
+		p.enter()
+	}
+}
+
+// parseMaybeUnary parses a UnaryExpression or an UpdateExpression. If operand
+// is true, the expression is the operand of a unary operator, and must not be
+// followed by an exponentiation operator.
+func (p *sourceParser) parseMaybeUnary(refDestructuringErrors *destructuringErrors, operand, noIn bool) ast.Expression {
+	start := p.tok.start
+
+	var expr ast.Expression
+	switch {
+	case p.fn.async && p.isName("await"):
+		expr = p.parseAwait(noIn)
+	case p.tok.kind == tokenPunctuator && (p.tok.value == "!" || p.tok.value == "~" || p.tok.value == "+" || p.tok.value == "-"),
+		p.isName("typeof") || p.isName("void") || p.isName("delete"):
+		operator := p.tok.value
+		p.next()
+		p.enter()
+		argument := p.parseMaybeUnary(nil, true, noIn)
+		p.leave()
+		expr = &ast.UnaryExpression{
+			Location: p.loc(start),
+			Operator: operator,
+			Prefix:   true,
+			Argument: argument,
+		}
+	case p.is("++") || p.is("--"):
+		operator := p.tok.value
+		p.next()
+		p.enter()
+		argument := p.parseMaybeUnary(nil, false, noIn)
+		p.leave()
+		expr = &ast.UpdateExpression{
+			Location: p.loc(start),
+			Operator: operator,
+			Prefix:   true,
+			Argument: p.checkSimpleTarget(argument).(ast.Expression),
+		}
+	default:
+		expr = p.parseExprSubscripts(refDestructuringErrors, noIn)
+		if (p.is("++") || p.is("--")) && !p.tok.newlineBefore && !isArrowAt(expr, start) {
+			p.checkExpressionErrorsSince(refDestructuringErrors, start)
+			target := p.checkSimpleTarget(expr)
+			operator := p.tok.value
+			p.next()
+			expr = &ast.UpdateExpression{
+				Location: p.loc(start),
+				Operator: operator,
+				Argument: target.(ast.Expression),
+			}
+		}
+	}
+
+	if operand && p.is("**") {
+		p.errorAt(p.tok.start, "Unary operator used immediately before exponentiation expression, parentheses must be used to disambiguate operator precedence")
+	}
+	return expr
+}
+
+// checkSimpleTarget reports an error if the given expression is not a simple
+// assignment target, i.e. an identifier or a member expression.
+func (p *sourceParser) checkSimpleTarget(expr ast.Expression) ast.Pattern {
+	switch n := expr.(type) {
+	case *ast.Identifier:
+		return n
+	case *ast.MemberExpression:
+		return n
+	}
+	p.errorAt(expr.Loc().Start, "Invalid left-hand side in assignment")
+	return nil
+}
+
+// parseExprSubscripts parses a LeftHandSideExpression.
+func (p *sourceParser) parseExprSubscripts(refDestructuringErrors *destructuringErrors, noIn bool) ast.Expression {
+	start := p.tok.start
+
+	var expr ast.Expression
+	if p.isName("super") {
+		expr = p.parseSuper(false)
+	} else {
+		expr = p.parseExprAtom(refDestructuringErrors, noIn)
+		if isArrowAt(expr, start) {
+			return expr
+		}
+	}
+
+	result := p.parseSubscripts(expr, start, false, noIn)
+	if result != expr {
+		p.checkExpressionErrorsSince(refDestructuringErrors, start)
+	}
+	return result
+}
+
+// parseSuper parses a super call or a super property access. If noCalls is
+// true, only super property accesses are allowed.
+func (p *sourceParser) parseSuper(noCalls bool) ast.Expression {
+	start := p.tok.start
+	p.next()
+	super := &ast.Super{Location: p.loc(start)}
+
+	switch {
+	case p.is("(") && !noCalls:
+		arguments := p.parseArguments()
+		return &ast.CallExpression{
+			Location:  p.loc(start),
+			Callee:    super,
+			Arguments: arguments,
+		}
+	case p.eat("."):
+		property := p.parseIdentifier(true)
+		return &ast.MemberExpression{
+			Location: p.loc(start),
+			Object:   super,
+			Property: property,
+		}
+	case p.eat("["):
+		property := p.parseExpression(false, nil)
+		p.expect("]")
+		return &ast.MemberExpression{
+			Location: p.loc(start),
+			Object:   super,
+			Property: property,
+			Computed: true,
+		}
+	}
+
+	p.unexpected("'(', '.' or '['")
+	return nil
+}
+
+// parseSubscripts parses member accesses, calls and tagged templates on the
+// given base expression. If noCalls is true, calls are not parsed, which is
+// used for the callee of a new expression. Like binary operations, every
+// subscript is counted as nesting.
+func (p *sourceParser) parseSubscripts(base ast.Expression, start ast.Position, noCalls, noIn bool) ast.Expression {
+	depth := p.depth
+	defer func() { p.depth = depth }()
+
+	maybeAsyncArrow := isPlainIdentifier(base, "async") && p.potentialArrowAt == start.Offset && !p.tok.newlineBefore
+
+	for {
+		switch {
+		case p.eat("."):
+			property := p.parseIdentifier(true)
+			base = &ast.MemberExpression{
+				Location: p.loc(start),
+				Object:   base,
+				Property: property,
+			}
+		case p.eat("["):
+			property := p.parseExpression(false, nil)
+			p.expect("]")
+			base = &ast.MemberExpression{
+				Location: p.loc(start),
+				Object:   base,
+				Property: property,
+				Computed: true,
+			}
+		case p.is("(") && !noCalls:
+			if maybeAsyncArrow {
+				return p.parseAsyncArrowOrCall(base, start, noIn)
+			}
+			arguments := p.parseArguments()
+			base = &ast.CallExpression{
+				Location:  p.loc(start),
+				Callee:    base,
+				Arguments: arguments,
+			}
+		case p.tok.kind == tokenTemplate:
+			quasi := p.parseTemplate(true)
+			base = &ast.TaggedTemplateExpression{
+				Location: p.loc(start),
+				Tag:      base,
+				Quasi:    quasi,
+			}
+		default:
+			return base
+		}
+		maybeAsyncArrow = false
+		p.enter()
+	}
+}
+
+// parseAsyncArrowOrCall parses the arguments of a call of 'async', which might
+// turn out to be the parameters of an async arrow function.
+func (p *sourceParser) parseAsyncArrowOrCall(callee ast.Expression, start ast.Position, noIn bool) ast.Expression {
+	oldYield, oldAwait, oldAwaitIdent := p.yieldPos, p.awaitPos, p.awaitIdentPos
+	p.yieldPos, p.awaitPos, p.awaitIdentPos = ast.Position{}, ast.Position{}, ast.Position{}
+
+	refDestructuringErrors := new(destructuringErrors)
+	p.expect("(")
+	arguments, spreadTrailingComma := p.parseExprList(")", false, refDestructuringErrors)
+
+	if p.is("=>") && !p.tok.newlineBefore {
+		if spreadTrailingComma {
+			p.errorAt(p.prevEnd, "Comma is not permitted after the rest element")
+		}
+		p.checkYieldAwaitInParams(true)
+		p.yieldPos, p.awaitPos, p.awaitIdentPos = oldYield, oldAwait, oldAwaitIdent
+		params := p.toAssignableList(arguments, true)
+		return p.parseArrowExpression(start, params, true, noIn)
+	}
+
+	p.checkExpressionErrors(refDestructuringErrors)
+	p.restoreYieldAwait(oldYield, oldAwait, oldAwaitIdent)
+
+	call := &ast.CallExpression{
+		Location:  p.loc(start),
+		Callee:    callee,
+		Arguments: arguments,
+	}
+	return p.parseSubscripts(call, start, false, noIn)
+}
+
+// checkYieldAwaitInParams reports an error if a yield or an await expression
+// was encountered since the positions were last reset, because the parsed
+// expressions turned out to be arrow parameters. For async arrow functions,
+// await must not even be used as identifier.
+func (p *sourceParser) checkYieldAwaitInParams(async bool) {
+	if p.yieldPos.IsValid() {
+		p.errorAt(p.yieldPos, "Yield expression cannot be a default value")
+	}
+	if p.awaitPos.IsValid() {
+		p.errorAt(p.awaitPos, "Await expression cannot be a default value")
+	}
+	if async && p.awaitIdentPos.IsValid() {
+		p.errorAt(p.awaitIdentPos, "Cannot use 'await' as identifier inside an async function")
+	}
+}
+
+// restoreYieldAwait restores the given positions of yield and await, unless
+// they are not set, in which case the current positions are kept.
+func (p *sourceParser) restoreYieldAwait(yieldPos, awaitPos, awaitIdentPos ast.Position) {
+	if yieldPos.IsValid() {
+		p.yieldPos = yieldPos
+	}
+	if awaitPos.IsValid() {
+		p.awaitPos = awaitPos
+	}
+	if awaitIdentPos.IsValid() {
+		p.awaitIdentPos = awaitIdentPos
+	}
+}
+
+func (p *sourceParser) parseArguments() []ast.Expression {
+	p.expect("(")
+	arguments, _ := p.parseExprList(")", false, nil)
+	return arguments
+}
+
+// parseExprList parses a comma separated list of assignment expressions and
+// spread elements up to the given closing punctuator. A trailing comma is
+// allowed. If allowHoles is true, elisions are allowed and represented as nil.
+// The returned flag reports whether the last element was a spread element
+// that was followed by a trailing comma.
+func (p *sourceParser) parseExprList(close string, allowHoles bool, refDestructuringErrors *destructuringErrors) (elements []ast.Expression, spreadTrailingComma bool) {
+	first := true
+	for !p.eat(close) {
+		if !first {
+			p.expect(",")
+			if p.is(close) {
+				if len(elements) > 0 {
+					_, spreadTrailingComma = elements[len(elements)-1].(*ast.SpreadElement)
+				}
+				p.next()
+				break
+			}
+		}
+		first = false
+
+		if allowHoles && p.is(",") {
+			elements = append(elements, nil)
+			continue
+		}
+		if p.is("...") {
+			elements = append(elements, p.parseSpread(refDestructuringErrors))
+			continue
+		}
+		elements = append(elements, p.parseMaybeAssign(false, refDestructuringErrors))
+	}
+	return
+}
+
+func (p *sourceParser) parseSpread(refDestructuringErrors *destructuringErrors) *ast.SpreadElement {
+	start := p.tok.start
+	p.expect("...")
+	argument := p.parseMaybeAssign(false, refDestructuringErrors)
+	return &ast.SpreadElement{
+		Location: p.loc(start),
+		Argument: argument,
+	}
+}
+
+// parseExprAtom parses a PrimaryExpression, or a new expression, or an arrow
+// function.
+func (p *sourceParser) parseExprAtom(refDestructuringErrors *destructuringErrors, noIn bool) ast.Expression {
+	start := p.tok.start
+	canBeArrow := p.potentialArrowAt == start.Offset
+
+	switch p.tok.kind {
+	case tokenName:
+		if !p.tok.escaped {
+			switch p.tok.value {
+			case "this":
+				p.next()
+				return &ast.ThisExpression{Location: p.loc(start)}
+			case "null", "true", "false":
+				var value interface{}
+				if p.tok.value != "null" {
+					value = p.tok.value == "true"
+				}
+				raw := p.tok.raw
+				p.next()
+				return &ast.Literal{
+					Location: p.loc(start),
+					Value:    value,
+					Raw:      raw,
+				}
+			case "function":
+				p.next()
+				return p.parseFunction(start, false, false, false)
+			case "class":
+				return p.parseClassExpression(start)
+			case "new":
+				return p.parseNew()
+			case "async":
+				next := p.peek()
+				if isName(next, "function") && !next.newlineBefore {
+					p.next()
+					p.next()
+					return p.parseFunction(start, false, false, true)
+				}
+				if canBeArrow && next.kind == tokenName && !keywords[next.value] && !next.newlineBefore {
+					// async x => ...
+					p.next()
+					if p.isName("await") {
+						p.errorAt(p.tok.start, "Cannot use 'await' as identifier inside an async function")
+					}
+					param := p.parseBindingIdentifier()
+					if !p.is("=>") || p.tok.newlineBefore {
+						p.unexpected("'=>'")
+					}
+					return p.parseArrowExpression(start, []ast.Pattern{param}, true, noIn)
+				}
+			}
+		}
+
+		id := p.parseIdentifier(false)
+		if canBeArrow && p.is("=>") && !p.tok.newlineBefore {
+			return p.parseArrowExpression(start, []ast.Pattern{id}, false, noIn)
+		}
+		return id
+	case tokenNumber:
+		value, raw := p.tok.number, p.tok.raw
+		p.next()
+		return &ast.Literal{
+			Location: p.loc(start),
+			Value:    value,
+			Raw:      raw,
+		}
+	case tokenString:
+		value, raw := p.tok.value, p.tok.raw
+		p.next()
+		return &ast.Literal{
+			Location: p.loc(start),
+			Value:    value,
+			Raw:      raw,
+		}
+	case tokenTemplate:
+		return p.parseTemplate(false)
+	case tokenPunctuator:
+		switch p.tok.value {
+		case "/", "/=":
+			p.tok = p.lexer.rescanRegExp(p.tok)
+			regex := &ast.RegExpLiteral{
+				Pattern: p.tok.pattern,
+				Flags:   p.tok.flags,
+			}
+			raw := p.tok.raw
+			p.next()
+			return &ast.Literal{
+				Location: p.loc(start),
+				Raw:      raw,
+				Regex:    regex,
+			}
+		case "(":
+			return p.parseParenAndDistinguish(canBeArrow, noIn)
+		case "[":
+			p.next()
+			elements, spreadTrailingComma := p.parseExprList("]", true, refDestructuringErrors)
+			array := &ast.ArrayExpression{
+				Location: p.loc(start),
+				Elements: elements,
+			}
+			if spreadTrailingComma {
+				p.spreadTrailingComma[array] = true
+			}
+			return array
+		case "{":
+			return p.parseObject(refDestructuringErrors)
+		}
+	}
+
+	p.unexpected("expression")
+	return nil
+}
+
+// parseParenAndDistinguish parses a parenthesized expression, or the
+// parameters of an arrow function if canBeArrow is true and the closing
+// parenthesis is followed by an arrow.
+func (p *sourceParser) parseParenAndDistinguish(canBeArrow, noIn bool) ast.Expression {
+	start := p.tok.start
+	p.expect("(")
+
+	oldYield, oldAwait := p.yieldPos, p.awaitPos
+	p.yieldPos, p.awaitPos = ast.Position{}, ast.Position{}
+
+	innerStart := p.tok.start
+	refDestructuringErrors := new(destructuringErrors)
+	var exprs []ast.Expression
+	var rest *ast.RestElement
+	var trailingComma ast.Position
+	for first := true; !p.is(")"); first = false {
+		if !first {
+			commaPos := p.tok.start
+			p.expect(",")
+			if p.is(")") {
+				trailingComma = commaPos
+				break
+			}
+		}
+
+		if p.is("...") {
+			restStart := p.tok.start
+			p.next()
+			argument := p.parseBindingAtom()
+			rest = &ast.RestElement{
+				Location: p.loc(restStart),
+				Argument: argument,
+			}
+			if p.is(",") {
+				p.errorAt(p.tok.start, "Comma is not permitted after the rest element")
+			}
+			break
+		}
+		exprs = append(exprs, p.parseMaybeAssign(false, refDestructuringErrors))
+	}
+	innerEnd := p.tok.start
+	p.expect(")")
+
+	if canBeArrow && p.is("=>") && !p.tok.newlineBefore {
+		p.checkYieldAwaitInParams(false)
+		p.yieldPos, p.awaitPos = oldYield, oldAwait
+		params := p.toAssignableList(exprs, true)
+		if rest != nil {
+			params = append(params, rest)
+		}
+		return p.parseArrowExpression(start, params, false, noIn)
+	}
+
+	if len(exprs) == 0 {
+		p.unexpectedToken(token{kind: tokenPunctuator, raw: ")", start: p.prevEnd}, "'=>'")
+	}
+	if rest != nil {
+		p.errorAt(rest.Start, "Unexpected token '...'")
+	}
+	if trailingComma.IsValid() {
+		p.errorAt(trailingComma, "Unexpected token ','")
+	}
+	p.checkExpressionErrors(refDestructuringErrors)
+	p.restoreYieldAwait(oldYield, oldAwait, ast.Position{})
+
+	var expr ast.Expression
+	if len(exprs) == 1 {
+		expr = exprs[0]
+	} else {
+		expr = &ast.SequenceExpression{
+			Location:    ast.Location{Start: innerStart, End: innerEnd},
+			Expressions: exprs,
+		}
+	}
+	p.parenthesized[expr] = true
+	return expr
+}
+
+// parseNew parses a new expression or new.target.
+func (p *sourceParser) parseNew() ast.Expression {
+	start := p.tok.start
+	p.next()
+
+	if p.eat(".") {
+		meta := &ast.Identifier{
+			Location: p.loc(start),
+			Name:     "new",
+		}
+		if !p.isName("target") {
+			p.errorAt(p.tok.start, "The only valid meta property for new is new.target")
+		}
+		property := p.parseIdentifier(true)
+		return &ast.MetaProperty{
+			Location: p.loc(start),
+			Meta:     meta,
+			Property: property,
+		}
+	}
+
+	calleeStart := p.tok.start
+	var callee ast.Expression
+	if p.isName("super") {
+		callee = p.parseSuper(true)
+	} else {
+		p.enter()
+		callee = p.parseExprAtom(nil, false)
+		p.leave()
+	}
+	callee = p.parseSubscripts(callee, calleeStart, true, false)
+
+	var arguments []ast.Expression
+	if p.is("(") {
+		arguments = p.parseArguments()
+	}
+	return &ast.NewExpression{
+		Location:  p.loc(start),
+		Callee:    callee,
+		Arguments: arguments,
+	}
+}
+
+// parseTemplate parses a template literal. Invalid escape sequences are only
+// allowed in tagged templates.
+func (p *sourceParser) parseTemplate(tagged bool) *ast.TemplateLiteral {
+	start := p.tok.start
+
+	var quasis []*ast.TemplateElement
+	var exprs []ast.Expression
+	for {
+		t := p.tok
+		if t.kind != tokenTemplate {
+			p.unexpected("template")
+		}
+		quasis = append(quasis, p.templateElement(t, tagged))
+		p.next()
+		if t.tail {
+			break
+		}
+
+		exprs = append(exprs, p.parseExpression(false, nil))
+		if !p.is("}") {
+			p.unexpected("'}'")
+		}
+		p.tok = p.lexer.rescanTemplate(p.tok)
+	}
+
+	return &ast.TemplateLiteral{
+		Location:    p.loc(start),
+		Quasis:      quasis,
+		Expressions: exprs,
+	}
+}
+
+// templateElement creates the template element for the given template token.
+// The location of the element excludes the delimiting backticks, braces and
+// dollar signs.
+func (p *sourceParser) templateElement(t token, tagged bool) *ast.TemplateElement {
+	var cooked *string
+	if t.invalidEscape {
+		if !tagged {
+			p.errorAt(t.start, "Invalid escape sequence in template")
+		}
+	} else {
+		value := t.value
+		cooked = &value
+	}
+
+	start, end := t.start, t.end
+	start.Offset++
+	start.Column++
+	delimiter := 2 // ${
+	if t.tail {
+		delimiter = 1 // `
+	}
+	end.Offset -= delimiter
+	end.Column -= delimiter
+
+	return &ast.TemplateElement{
+		Location: ast.Location{Start: start, End: end},
+		Tail:     t.tail,
+		Cooked:   cooked,
+		Raw:      t.pattern,
+	}
+}
+
+// parseYield parses a yield expression. The current token must be 'yield'.
+func (p *sourceParser) parseYield(noIn bool) ast.Expression {
+	start := p.tok.start
+	if !p.yieldPos.IsValid() {
+		p.yieldPos = start
+	}
+	p.next()
+
+	if p.is(";") || p.canInsertSemicolon() || (!p.is("*") && !p.startsExpression()) {
+		return &ast.YieldExpression{Location: p.loc(start)}
+	}
+
+	delegate := p.eat("*")
+	argument := p.parseMaybeAssign(noIn, nil)
+	return &ast.YieldExpression{
+		Location: p.loc(start),
+		Argument: argument,
+		Delegate: delegate,
+	}
+}
+
+// parseAwait parses an await expression. The current token must be 'await'.
+func (p *sourceParser) parseAwait(noIn bool) ast.Expression {
+	start := p.tok.start
+	if !p.awaitPos.IsValid() {
+		p.awaitPos = start
+	}
+	p.next()
+
+	argument := p.parseMaybeUnary(nil, true, noIn)
+	return &ast.AwaitExpression{
+		Location: p.loc(start),
+		Argument: argument,
+	}
+}
+
+// parseIdentifier parses an identifier. If liberal is true, the identifier
+// may be a reserved word, which is the case for property names.
+func (p *sourceParser) parseIdentifier(liberal bool) *ast.Identifier {
+	if p.tok.kind != tokenName {
+		p.unexpected("identifier")
+	}
+	if !liberal {
+		p.checkUnreserved(p.tok)
+	}
+
+	id := &ast.Identifier{
+		Location: ast.Location{Start: p.tok.start, End: p.tok.end},
+		Name:     p.tok.value,
+	}
+	p.next()
+	return id
+}
+
+// parseBindingIdentifier parses an identifier that is the target of a
+// binding.
+func (p *sourceParser) parseBindingIdentifier() *ast.Identifier {
+	return p.parseIdentifier(false)
+}
+
+// checkUnreserved reports an error if the given name token is a reserved word
+// that cannot be used as identifier in the current context.
+func (p *sourceParser) checkUnreserved(t token) {
+	if keywords[t.value] {
+		if t.escaped {
+			p.errorAt(t.start, "Keyword must not contain escaped characters")
+		}
+		p.errorAt(t.start, "Unexpected keyword '%v'", t.value)
+	}
+
+	switch t.value {
+	case "yield":
+		if p.fn.generator {
+			p.errorAt(t.start, "Cannot use 'yield' as identifier inside a generator")
+		}
+	case "await":
+		if p.fn.async {
+			p.errorAt(t.start, "Cannot use 'await' as identifier inside an async function")
+		}
+		if p.module {
+			p.errorAt(t.start, "Cannot use 'await' as identifier inside a module")
+		}
+		if !p.awaitIdentPos.IsValid() {
+			p.awaitIdentPos = t.start
+		}
+	}
+}
+
+// parseObject parses an object literal.
+func (p *sourceParser) parseObject(refDestructuringErrors *destructuringErrors) ast.Expression {
+	start := p.tok.start
+	p.expect("{")
+
+	var properties []ast.Node
+	for first := true; !p.eat("}"); first = false {
+		if !first {
+			p.expect(",")
+			if p.eat("}") {
+				break
+			}
+		}
+		properties = append(properties, p.parseObjectMember(refDestructuringErrors))
+	}
+
+	return &ast.ObjectExpression{
+		Location:   p.loc(start),
+		Properties: properties,
+	}
+}
+
+// parseObjectMember parses a property definition of an object literal.
+func (p *sourceParser) parseObjectMember(refDestructuringErrors *destructuringErrors) ast.Node {
+	start := p.tok.start
+	if p.is("...") {
+		return p.parseSpread(refDestructuringErrors)
+	}
+
+	generator, async, kind := p.parseMethodModifiers()
+	keyToken := p.tok
+	key, computed := p.parsePropertyName()
+
+	if kind != ast.KindInit {
+		value := p.parseMethod(false, false)
+		p.checkAccessorParams(kind, value)
+		return &ast.Property{
+			Location: p.loc(start),
+			Key:      key,
+			Value:    value,
+			Kind:     kind,
+			Computed: computed,
+		}
+	}
+
+	if p.is("(") {
+		value := p.parseMethod(generator, async)
+		return &ast.Property{
+			Location: p.loc(start),
+			Key:      key,
+			Value:    value,
+			Kind:     ast.KindInit,
+			Method:   true,
+			Computed: computed,
+		}
+	}
+	if generator || async {
+		p.unexpected("'('")
+	}
+
+	if p.eat(":") {
+		value := p.parseMaybeAssign(false, refDestructuringErrors)
+		return &ast.Property{
+			Location: p.loc(start),
+			Key:      key,
+			Value:    value,
+			Kind:     ast.KindInit,
+			Computed: computed,
+		}
+	}
+
+	// shorthand property, the key must be an identifier reference
+	if keyToken.kind != tokenName {
+		p.unexpected("':'")
+	}
+	p.checkUnreserved(keyToken)
+	id := key.(*ast.Identifier)
+	var value ast.Node = &ast.Identifier{
+		Location: id.Location,
+		Name:     id.Name,
+	}
+
+	if p.is("=") {
+		if refDestructuringErrors == nil {
+			p.unexpected("':'")
+		}
+		if !refDestructuringErrors.shorthandAssign.IsValid() {
+			refDestructuringErrors.shorthandAssign = p.tok.start
+		}
+		p.next()
+		right := p.parseMaybeAssign(false, nil)
+		value = &ast.AssignmentPattern{
+			Location: p.loc(start),
+			Left:     value.(ast.Pattern),
+			Right:    right,
+		}
+	}
+
+	return &ast.Property{
+		Location:  p.loc(start),
+		Key:       key,
+		Value:     value,
+		Kind:      ast.KindInit,
+		Shorthand: true,
+	}
+}
+
+// startsPropertyName reports whether the given token can start a property
+// name.
+func startsPropertyName(t token) bool {
+	switch t.kind {
+	case tokenName, tokenString, tokenNumber:
+		return true
+	case tokenPunctuator:
+		return t.value == "["
+	}
+	return false
+}
+
+// parseMethodModifiers parses the modifiers that may precede the property
+// name of a method, i.e. '*', 'async', 'get' and 'set'. The returned kind is
+// KindInit, unless the method is a getter or a setter.
+func (p *sourceParser) parseMethodModifiers() (generator, async bool, kind string) {
+	kind = ast.KindInit
+	if p.eat("*") {
+		generator = true
+		return
+	}
+
+	if p.isName("async") {
+		next := p.peek()
+		if !next.newlineBefore && (startsPropertyName(next) || (next.kind == tokenPunctuator && next.value == "*")) {
+			p.next()
+			async = true
+			generator = p.eat("*")
+		}
+		return
+	}
+
+	if p.isName("get") || p.isName("set") {
+		if startsPropertyName(p.peek()) {
+			kind = p.tok.value
+			p.next()
+		}
+	}
+	return
+}
+
+// parsePropertyName parses a literal or computed property name.
+func (p *sourceParser) parsePropertyName() (key ast.Expression, computed bool) {
+	switch p.tok.kind {
+	case tokenName:
+		return p.parseIdentifier(true), false
+	case tokenString, tokenNumber:
+		return p.parseExprAtom(nil, false), false
+	case tokenPunctuator:
+		if p.eat("[") {
+			key = p.parseMaybeAssign(false, nil)
+			p.expect("]")
+			return key, true
+		}
+	}
+
+	p.unexpected("property name")
+	return nil, false
+}
@@ -0,0 +1,214 @@
+package parser
+
+import (
+	"github.com/gojisvm/gojis/internal/parser/ast"
+)
+
+// parseFunction parses a function declaration or a function expression,
+// after the 'function' keyword. The name of a declaration is required,
+// unless nullableID is true, which is the case for a default export.
+func (p *sourceParser) parseFunction(start ast.Position, statement, nullableID, async bool) *ast.FunctionExpression {
+	generator := p.eat("*")
+
+	fn := new(ast.FunctionExpression)
+	if statement && !(nullableID && p.is("(")) {
+		// the name of a declaration is bound in the enclosing context
+		fn.ID = p.parseBindingIdentifier()
+	}
+
+	p.withFunction(functionContext{function: true, generator: generator, async: async}, func() {
+		if !statement && p.tok.kind == tokenName {
+			// the name of an expression is bound in the function itself
+			fn.ID = p.parseBindingIdentifier()
+		}
+		p.parseFunctionParamsAndBody(&fn.Function)
+	})
+
+	fn.Generator = generator
+	fn.Async = async
+	fn.Location = p.loc(start)
+	return fn
+}
+
+// parseFunctionParamsAndBody parses the formal parameters and the body of a
+// function. The function context must already be set up.
+func (p *sourceParser) parseFunctionParamsAndBody(fn *ast.Function) {
+	p.expect("(")
+	fn.Params = p.parseBindingList(")", false)
+	fn.Body, fn.Strict = p.parseFunctionBody()
+}
+
+// parseFunctionBody parses the body of a function, and reports whether the
+// function is strict mode code.
+func (p *sourceParser) parseFunctionBody() (*ast.BlockStatement, bool) {
+	start := p.tok.start
+	p.expect("{")
+	body := p.parseDirectivesAndStatements(func() bool { return p.is("}") })
+	p.expect("}")
+	return &ast.BlockStatement{
+		Location: p.loc(start),
+		Body:     body,
+	}, p.strict
+}
+
+// parseArrowExpression parses the body of an arrow function with the given
+// parameters. The current token must be the arrow.
+func (p *sourceParser) parseArrowExpression(start ast.Position, params []ast.Pattern, async, noIn bool) ast.Expression {
+	p.expect("=>")
+
+	arrow := &ast.ArrowFunctionExpression{}
+	arrow.Params = params
+	arrow.Async = async
+	p.withFunction(functionContext{function: true, async: async}, func() {
+		if p.is("{") {
+			arrow.Body, arrow.Strict = p.parseFunctionBody()
+			return
+		}
+		arrow.Body = p.parseMaybeAssign(noIn, nil)
+		arrow.Expression = true
+		arrow.Strict = p.strict
+	})
+
+	arrow.Location = p.loc(start)
+	return arrow
+}
+
+// parseMethod parses the parameters and the body of a method, starting at
+// the opening parenthesis.
+func (p *sourceParser) parseMethod(generator, async bool) *ast.FunctionExpression {
+	start := p.tok.start
+
+	fn := new(ast.FunctionExpression)
+	p.withFunction(functionContext{function: true, generator: generator, async: async}, func() {
+		p.parseFunctionParamsAndBody(&fn.Function)
+	})
+
+	fn.Generator = generator
+	fn.Async = async
+	fn.Location = p.loc(start)
+	return fn
+}
+
+// checkAccessorParams reports an error if the parameters of a getter or a
+// setter do not match the grammar, which requires no parameters for getters
+// and exactly one parameter for setters.
+func (p *sourceParser) checkAccessorParams(kind string, fn *ast.FunctionExpression) {
+	switch kind {
+	case ast.KindGet:
+		if len(fn.Params) != 0 {
+			p.errorAt(fn.Start, "Getter must not have any formal parameters")
+		}
+	case ast.KindSet:
+		if len(fn.Params) != 1 {
+			p.errorAt(fn.Start, "Setter must have exactly one formal parameter")
+		}
+		if _, ok := fn.Params[0].(*ast.RestElement); ok {
+			p.errorAt(fn.Params[0].Loc().Start, "Setter function argument must not be a rest parameter")
+		}
+	}
+}
+
+func (p *sourceParser) parseClassDeclaration(start ast.Position, nullableID bool) *ast.ClassDeclaration {
+	class := p.parseClass(true, nullableID)
+	return &ast.ClassDeclaration{
+		Location: p.loc(start),
+		Class:    class,
+	}
+}
+
+func (p *sourceParser) parseClassExpression(start ast.Position) ast.Expression {
+	class := p.parseClass(false, false)
+	return &ast.ClassExpression{
+		Location: p.loc(start),
+		Class:    class,
+	}
+}
+
+// parseClass parses a class declaration or a class expression. All parts of
+// a class are strict mode code. The name of a declaration is required,
+// unless nullableID is true, which is the case for a default export.
+func (p *sourceParser) parseClass(statement, nullableID bool) ast.Class {
+	p.expectName("class")
+
+	oldStrict := p.strict
+	p.strict = true
+	defer func() { p.strict = oldStrict }()
+
+	var class ast.Class
+	if p.tok.kind == tokenName && !p.isName("extends") {
+		class.ID = p.parseBindingIdentifier()
+	} else if statement && !nullableID {
+		p.unexpected("identifier")
+	}
+
+	if p.eatName("extends") {
+		p.enter()
+		class.SuperClass = p.parseExprSubscripts(nil, false)
+		p.leave()
+	}
+
+	start := p.tok.start
+	p.expect("{")
+	var methods []*ast.MethodDefinition
+	for !p.eat("}") {
+		if p.eat(";") {
+			continue
+		}
+		methods = append(methods, p.parseClassMethod())
+	}
+	class.Body = &ast.ClassBody{
+		Location: p.loc(start),
+		Body:     methods,
+	}
+
+	return class
+}
+
+// parseClassMethod parses a method definition in a class body.
+func (p *sourceParser) parseClassMethod() *ast.MethodDefinition {
+	start := p.tok.start
+
+	static := false
+	if p.isName("static") {
+		if next := p.peek(); next.kind != tokenPunctuator || next.value != "(" {
+			static = true
+			p.next()
+		}
+	}
+
+	generator, async, kind := p.parseMethodModifiers()
+	key, computed := p.parsePropertyName()
+	value := p.parseMethod(generator, async)
+
+	switch {
+	case kind != ast.KindInit:
+		p.checkAccessorParams(kind, value)
+	case !static && !computed && propertyName(key) == "constructor":
+		kind = ast.KindConstructor
+	default:
+		kind = ast.KindMethod
+	}
+
+	return &ast.MethodDefinition{
+		Location: p.loc(start),
+		Key:      key,
+		Value:    value,
+		Kind:     kind,
+		Computed: computed,
+		Static:   static,
+	}
+}
+
+// propertyName returns the name of a non-computed property key, which is
+// either an identifier or a literal.
+func propertyName(key ast.Expression) string {
+	switch k := key.(type) {
+	case *ast.Identifier:
+		return k.Name
+	case *ast.Literal:
+		if s, ok := k.Value.(string); ok {
+			return s
+		}
+	}
+	return ""
+}
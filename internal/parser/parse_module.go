@@ -0,0 +1,403 @@
+package parser
+
+import (
+	"github.com/gojisvm/gojis/internal/parser/ast"
+)
+
+// parseModule parses the source as a Module. Module code is always strict
+// mode code, await is a reserved word, and HTML-like comments are not
+// allowed. Errors are handled just like in parseScript. The import and
+// export entries of the module are set on the returned program.
+func (p *sourceParser) parseModule() (program *ast.Program) {
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(bailout); !ok {
+				panic(r)
+			}
+			program = nil
+		}
+	}()
+
+	p.module = true
+	p.strict = true
+	p.lexer.module = true
+	p.next()
+
+	start := p.tok.start
+	var body []ast.Statement
+	for p.tok.kind != tokenEOF {
+		if stmt := p.parseModuleItem(); stmt != nil {
+			body = append(body, stmt)
+		}
+	}
+
+	program = &ast.Program{
+		Location:   p.loc(start),
+		SourceType: ast.SourceTypeModule,
+		Body:       body,
+		Strict:     true,
+	}
+	setModuleEntries(program)
+	return program
+}
+
+// parseModuleItem parses an import declaration, an export declaration or a
+// statement list item.
+func (p *sourceParser) parseModuleItem() ast.Statement {
+	return p.recoverStatement(func() ast.Statement {
+		switch {
+		case p.isName("import"):
+			return p.parseImport()
+		case p.isName("export"):
+			return p.parseExport()
+		}
+		return p.parseStatement(contextStatementList)
+	})
+}
+
+// parseImport parses an import declaration, as specified in 15.2.2.
+func (p *sourceParser) parseImport() ast.Statement {
+	start := p.tok.start
+	p.next() // 'import'
+
+	decl := new(ast.ImportDeclaration)
+	if p.tok.kind != tokenString {
+		decl.Specifiers = p.parseImportClause()
+		p.expectName("from")
+	}
+	decl.Source = p.parseModuleSpecifier()
+	p.semicolon()
+	decl.Location = p.loc(start)
+	return decl
+}
+
+// parseImportClause parses the bindings of an import declaration, that is,
+// an optional default binding, followed by either a namespace import or
+// named imports.
+func (p *sourceParser) parseImportClause() []ast.Node {
+	var specifiers []ast.Node
+	if p.tok.kind == tokenName {
+		start := p.tok.start
+		local := p.parseBindingIdentifier()
+		specifiers = append(specifiers, &ast.ImportDefaultSpecifier{
+			Location: p.loc(start),
+			Local:    local,
+		})
+		if !p.eat(",") {
+			return specifiers
+		}
+	}
+
+	if p.is("*") {
+		start := p.tok.start
+		p.next()
+		p.expectName("as")
+		local := p.parseBindingIdentifier()
+		return append(specifiers, &ast.ImportNamespaceSpecifier{
+			Location: p.loc(start),
+			Local:    local,
+		})
+	}
+
+	p.expect("{")
+	for first := true; !p.eat("}"); first = false {
+		if !first {
+			p.expect(",")
+			if p.eat("}") {
+				break
+			}
+		}
+
+		start := p.tok.start
+		if !isName(p.peek(), "as") {
+			// the imported name is the binding as well
+			p.checkUnreserved(p.tok)
+		}
+		imported := p.parseIdentifier(true)
+		local := imported
+		if p.eatName("as") {
+			local = p.parseBindingIdentifier()
+		}
+		specifiers = append(specifiers, &ast.ImportSpecifier{
+			Location: p.loc(start),
+			Imported: imported,
+			Local:    local,
+		})
+	}
+	return specifiers
+}
+
+// parseModuleSpecifier parses the string literal that specifies the module
+// of an import declaration or a re-export.
+func (p *sourceParser) parseModuleSpecifier() *ast.Literal {
+	if p.tok.kind != tokenString {
+		p.unexpected("module specifier")
+	}
+	return p.parseExprAtom(nil, false).(*ast.Literal)
+}
+
+// parseExport parses an export declaration, as specified in 15.2.3.
+func (p *sourceParser) parseExport() ast.Statement {
+	start := p.tok.start
+	p.next() // 'export'
+
+	switch {
+	case p.eat("*"):
+		var exported *ast.Identifier
+		if p.eatName("as") {
+			exported = p.parseIdentifier(true)
+		}
+		p.expectName("from")
+		source := p.parseModuleSpecifier()
+		p.semicolon()
+		return &ast.ExportAllDeclaration{
+			Location: p.loc(start),
+			Exported: exported,
+			Source:   source,
+		}
+	case p.eatName("default"):
+		return p.parseExportDefault(start)
+	case p.is("{"):
+		decl := new(ast.ExportNamedDeclaration)
+		var locals []token
+		decl.Specifiers, locals = p.parseExportClause()
+		if p.eatName("from") {
+			decl.Source = p.parseModuleSpecifier()
+		} else {
+			// local exports refer to bindings, so they cannot be
+			// reserved words
+			for _, t := range locals {
+				p.checkUnreserved(t)
+			}
+		}
+		p.semicolon()
+		decl.Location = p.loc(start)
+		return decl
+	}
+
+	if !p.isExportableDeclaration() {
+		p.unexpected("declaration")
+	}
+	declaration := p.parseStatement(contextStatementList)
+	return &ast.ExportNamedDeclaration{
+		Location:    p.loc(start),
+		Declaration: declaration,
+	}
+}
+
+// isExportableDeclaration is used to determine whether the current token
+// starts a declaration that can be exported with export.
+func (p *sourceParser) isExportableDeclaration() bool {
+	switch {
+	case p.isName("var"), p.isName("const"), p.isName("function"), p.isName("class"):
+		return true
+	case p.isName("let"):
+		return p.isLetDeclaration(true)
+	case p.isName("async"):
+		next := p.peek()
+		return isName(next, "function") && !next.newlineBefore
+	}
+	return false
+}
+
+// parseExportClause parses the exports between braces of an export
+// declaration. The tokens of the local names are returned as well, since
+// whether they must be valid identifier references is only known after
+// the clause.
+func (p *sourceParser) parseExportClause() (specifiers []*ast.ExportSpecifier, locals []token) {
+	p.expect("{")
+	for first := true; !p.eat("}"); first = false {
+		if !first {
+			p.expect(",")
+			if p.eat("}") {
+				break
+			}
+		}
+
+		start := p.tok.start
+		locals = append(locals, p.tok)
+		local := p.parseIdentifier(true)
+		exported := local
+		if p.eatName("as") {
+			exported = p.parseIdentifier(true)
+		}
+		specifiers = append(specifiers, &ast.ExportSpecifier{
+			Location: p.loc(start),
+			Local:    local,
+			Exported: exported,
+		})
+	}
+	return
+}
+
+// parseExportDefault parses the declaration or expression of a default
+// export, after the 'default' keyword.
+func (p *sourceParser) parseExportDefault(start ast.Position) ast.Statement {
+	var declaration ast.Node
+	declStart := p.tok.start
+	switch {
+	case p.isName("function"):
+		p.next()
+		fn := p.parseFunction(declStart, true, true, false)
+		declaration = &ast.FunctionDeclaration{
+			Location: fn.Location,
+			Function: fn.Function,
+		}
+	case p.isName("async") && isName(p.peek(), "function") && !p.peek().newlineBefore:
+		p.next()
+		p.next()
+		fn := p.parseFunction(declStart, true, true, true)
+		declaration = &ast.FunctionDeclaration{
+			Location: fn.Location,
+			Function: fn.Function,
+		}
+	case p.isName("class"):
+		declaration = p.parseClassDeclaration(declStart, true)
+	default:
+		declaration = p.parseMaybeAssign(false, nil)
+		p.semicolon()
+	}
+
+	return &ast.ExportDefaultDeclaration{
+		Location:    p.loc(start),
+		Declaration: declaration,
+	}
+}
+
+// setModuleEntries sets the requested modules and the import and export
+// entries of the given module, as specified in 15.2.1.16.1.
+func setModuleEntries(program *ast.Program) {
+	requested := make(map[string]bool)
+	request := func(source *ast.Literal) string {
+		specifier := source.Value.(string)
+		if !requested[specifier] {
+			requested[specifier] = true
+			program.RequestedModules = append(program.RequestedModules, specifier)
+		}
+		return specifier
+	}
+
+	var exportEntries []*ast.ExportEntry
+	for _, stmt := range program.Body {
+		switch n := stmt.(type) {
+		case *ast.ImportDeclaration:
+			program.ImportEntries = append(program.ImportEntries, importEntries(n, request(n.Source))...)
+		case *ast.ExportNamedDeclaration:
+			if n.Declaration != nil {
+				for _, id := range declarationBoundNames(n.Declaration) {
+					exportEntries = append(exportEntries, &ast.ExportEntry{
+						ExportName: id.Name,
+						LocalName:  id.Name,
+					})
+				}
+				continue
+			}
+
+			moduleRequest := ""
+			if n.Source != nil {
+				moduleRequest = request(n.Source)
+			}
+			for _, s := range n.Specifiers {
+				entry := &ast.ExportEntry{
+					ExportName:    s.Exported.Name,
+					ModuleRequest: moduleRequest,
+				}
+				if n.Source != nil {
+					entry.ImportName = s.Local.Name
+				} else {
+					entry.LocalName = s.Local.Name
+				}
+				exportEntries = append(exportEntries, entry)
+			}
+		case *ast.ExportDefaultDeclaration:
+			localName := ast.DefaultExportName
+			if names := declarationBoundNames(n.Declaration); len(names) > 0 {
+				localName = names[0].Name
+			}
+			exportEntries = append(exportEntries, &ast.ExportEntry{
+				ExportName: "default",
+				LocalName:  localName,
+			})
+		case *ast.ExportAllDeclaration:
+			entry := &ast.ExportEntry{
+				ModuleRequest: request(n.Source),
+				ImportName:    "*",
+			}
+			if n.Exported != nil {
+				entry.ExportName = n.Exported.Name
+			}
+			exportEntries = append(exportEntries, entry)
+		}
+	}
+
+	imports := make(map[string]*ast.ImportEntry)
+	for _, ie := range program.ImportEntries {
+		imports[ie.LocalName] = ie
+	}
+	for _, ee := range exportEntries {
+		switch {
+		case ee.ModuleRequest == "":
+			ie, imported := imports[ee.LocalName]
+			if !imported || ie.ImportName == "*" {
+				// a re-export of an imported module namespace object is
+				// a local export
+				program.LocalExportEntries = append(program.LocalExportEntries, ee)
+				continue
+			}
+			program.IndirectExportEntries = append(program.IndirectExportEntries, &ast.ExportEntry{
+				ExportName:    ee.ExportName,
+				ModuleRequest: ie.ModuleRequest,
+				ImportName:    ie.ImportName,
+			})
+		case ee.ImportName == "*" && ee.ExportName == "":
+			program.StarExportEntries = append(program.StarExportEntries, ee)
+		default:
+			program.IndirectExportEntries = append(program.IndirectExportEntries, ee)
+		}
+	}
+}
+
+// importEntries returns the import entries of the given import declaration,
+// whose module specifier is moduleRequest.
+func importEntries(decl *ast.ImportDeclaration, moduleRequest string) []*ast.ImportEntry {
+	var entries []*ast.ImportEntry
+	for _, s := range decl.Specifiers {
+		entry := &ast.ImportEntry{ModuleRequest: moduleRequest}
+		switch s := s.(type) {
+		case *ast.ImportDefaultSpecifier:
+			entry.ImportName = "default"
+			entry.LocalName = s.Local.Name
+		case *ast.ImportNamespaceSpecifier:
+			entry.ImportName = "*"
+			entry.LocalName = s.Local.Name
+		case *ast.ImportSpecifier:
+			entry.ImportName = s.Imported.Name
+			entry.LocalName = s.Local.Name
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// declarationBoundNames returns the names bound by the given declaration of
+// an export declaration. A function or class declaration without name does
+// not bind any names.
+func declarationBoundNames(decl ast.Node) []*ast.Identifier {
+	switch n := decl.(type) {
+	case *ast.VariableDeclaration:
+		var names []*ast.Identifier
+		for _, d := range n.Declarations {
+			names = append(names, boundNames(d.ID)...)
+		}
+		return names
+	case *ast.FunctionDeclaration:
+		if n.ID != nil {
+			return []*ast.Identifier{n.ID}
+		}
+	case *ast.ClassDeclaration:
+		if n.ID != nil {
+			return []*ast.Identifier{n.ID}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/gojisvm/gojis/internal/parser/ast"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseModuleValid(t *testing.T) {
+	tests := []string{
+		``,
+		`import a from "m";`,
+		`import * as ns from "m";`,
+		`import { a, b as c, default as d, if as e, } from "m";`,
+		`import a, { b } from "m"; import c, * as ns from "m";`,
+		`import "m"; import {} from "m";`,
+		`export var a = 1, [b, c] = d, {e, f: g} = h;`,
+		`export let a; export const b = 1;`,
+		`export function f() {} export function* g() {} export async function h() {}`,
+		`export class A {}`,
+		`export default 1;`,
+		`export default function () {}`,
+		`export default function* f() {}`,
+		`export default async function () {}`,
+		`export default class extends B {}`,
+		`export default (function () {});`,
+		`export default async () => 1;`,
+		`var a, b; export { a, b as c, a as default, b as if, };`,
+		`export { };`,
+		`export { a, b as c, default, if as d } from "m";`,
+		`export * from "m";`,
+		`export * as ns from "m";`,
+		`export * as default from "m";`,
+		`import a from "m"; export { a };`,
+		`export { a }; let a = 1;`,
+		`export { b }; { var b; }`,
+		`var a; export { a as eval, a as await };`,
+		`({ await: 1 }); a.await; class A { await() {} }`,
+		`async function f() { await 1; }`,
+		`function f() { var g; function g() {} }`,
+		`{ function f() {} } { function f() {} }`,
+		`import a from 'm'
+		export default a`,
+		"a < !--b",
+	}
+
+	for _, src := range tests {
+		t.Run(src, func(t *testing.T) {
+			require := require.New(t)
+
+			p := New()
+			require.NoError(p.ParseModule("test.js", src))
+		})
+	}
+}
+
+func TestParseModuleInvalid(t *testing.T) {
+	tests := []struct {
+		src     string
+		message string
+	}{
+		{`import { if } from "m";`, "Unexpected keyword 'if'"},
+		{`import { a as if } from "m";`, "Unexpected keyword 'if'"},
+		{`import a, b from "m";`, "Unexpected token 'b'"},
+		{`import * from "m";`, "Unexpected token 'from'"},
+		{`import a from b;`, "Unexpected token 'b'"},
+		{`import { eval } from "m";`, "Unexpected eval or arguments in strict mode"},
+		{`import { a as b, c as b } from "m";`, "Identifier 'b' has already been declared"},
+		{`import a from "m"; var a;`, "Identifier 'a' has already been declared"},
+		{`function f() {} function f() {}`, "Identifier 'f' has already been declared"},
+		{`var f; function f() {}`, "Identifier 'f' has already been declared"},
+		{`export default var a;`, "Unexpected keyword 'var'"},
+		{`export default a, b;`, "Unexpected token ','"},
+		{`export let;`, "Unexpected token 'let'"},
+		{`export a;`, "Unexpected token 'a'"},
+		{`export { if };`, "Unexpected keyword 'if'"},
+		{`var let; export { let };`, "Unexpected strict mode reserved word 'let'"},
+		{`export { a };`, "Export 'a' is not defined in module"},
+		{`export { a }; { let a; }`, "Export 'a' is not defined in module"},
+		{`var a; export { a, a };`, "Duplicate export of 'a'"},
+		{`var a; export { a }; export * as a from "m";`, "Duplicate export of 'a'"},
+		{`var a, b; export { a as c, b as c };`, "Duplicate export of 'c'"},
+		{`export default 1; export default 2;`, "Duplicate export of 'default'"},
+		{`export var a; export { a };`, "Duplicate export of 'a'"},
+		{`export { x as y } from "m"; export { z as y } from "m";`, "Duplicate export of 'y'"},
+		{`{ export var a; }`, "'export' may only appear at the top level of a module"},
+		{`function f() { import a from "m"; }`, "'import' may only appear at the top level of a module"},
+		{`await;`, "Cannot use 'await' as identifier inside a module"},
+		{`function f(await) {}`, "Cannot use 'await' as identifier inside a module"},
+		{`({ await } = a);`, "Cannot use 'await' as identifier inside a module"},
+		{`with (a) {}`, "Strict mode code may not include a with statement"},
+		{`010`, "Octal literals are not allowed in strict mode"},
+		{`<!-- comment`, "Unexpected token '<'"},
+		{`return;`, "Illegal return statement"},
+		{`new.target;`, "new.target expression is not allowed here"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.src, func(t *testing.T) {
+			require := require.New(t)
+
+			p := New()
+			err := p.ParseModule("test.js", test.src)
+			require.Error(err)
+
+			errs := err.(ParserError).Errors()
+			require.Equal(test.message, errs[0].(*Error).Message)
+		})
+	}
+}
+
+func TestParseModuleScriptGoal(t *testing.T) {
+	require := require.New(t)
+
+	p := New()
+	require.NoError(p.ParseString("script.js", `var await; <!-- comment`))
+	require.NoError(p.ParseModule("module.js", `export var a;`))
+
+	script, ok := p.Ast().Root("script.js")
+	require.True(ok)
+	require.Equal(ast.SourceTypeScript, script.SourceType)
+	require.False(script.Strict)
+
+	module, ok := p.Ast().Root("module.js")
+	require.True(ok)
+	require.Equal(ast.SourceTypeModule, module.SourceType)
+	require.True(module.Strict)
+}
+
+func TestParseModuleDeclarations(t *testing.T) {
+	require := require.New(t)
+
+	p := New()
+	require.NoError(p.ParseModule("test.js", `import a, { b as c, d } from "m"; export default function () {}`))
+
+	program, _ := p.Ast().Root("test.js")
+	require.Len(program.Body, 2)
+
+	imp := program.Body[0].(*ast.ImportDeclaration)
+	require.Equal("m", imp.Source.Value)
+	require.Len(imp.Specifiers, 3)
+	require.Equal("a", imp.Specifiers[0].(*ast.ImportDefaultSpecifier).Local.Name)
+	named := imp.Specifiers[1].(*ast.ImportSpecifier)
+	require.Equal("b", named.Imported.Name)
+	require.Equal("c", named.Local.Name)
+	shorthand := imp.Specifiers[2].(*ast.ImportSpecifier)
+	require.True(shorthand.Imported == shorthand.Local)
+
+	def := program.Body[1].(*ast.ExportDefaultDeclaration)
+	fn := def.Declaration.(*ast.FunctionDeclaration)
+	require.Nil(fn.ID)
+	require.Equal(ast.Position{Offset: 49, Line: 1, Column: 49}, fn.Start)
+}
+
+func TestParseModuleEntries(t *testing.T) {
+	require := require.New(t)
+
+	p := New()
+	require.NoError(p.ParseModule("test.js", `
+		import a, { b as c } from "x";
+		import * as ns from "y";
+		import "z";
+		export { a as d, ns, e as f };
+		export { g as h } from "x";
+		export * from "w";
+		export * as v from "w";
+		export default function () {}
+		export let [e, i] = j;
+		export class K {}
+	`))
+
+	program, _ := p.Ast().Root("test.js")
+	require.Equal([]string{"x", "y", "z", "w"}, program.RequestedModules)
+	require.Equal([]*ast.ImportEntry{
+		{ModuleRequest: "x", ImportName: "default", LocalName: "a"},
+		{ModuleRequest: "x", ImportName: "b", LocalName: "c"},
+		{ModuleRequest: "y", ImportName: "*", LocalName: "ns"},
+	}, program.ImportEntries)
+	require.Equal([]*ast.ExportEntry{
+		{ExportName: "ns", LocalName: "ns"},
+		{ExportName: "f", LocalName: "e"},
+		{ExportName: "default", LocalName: ast.DefaultExportName},
+		{ExportName: "e", LocalName: "e"},
+		{ExportName: "i", LocalName: "i"},
+		{ExportName: "K", LocalName: "K"},
+	}, program.LocalExportEntries)
+	require.Equal([]*ast.ExportEntry{
+		{ExportName: "d", ModuleRequest: "x", ImportName: "default"},
+		{ExportName: "h", ModuleRequest: "x", ImportName: "g"},
+		{ExportName: "v", ModuleRequest: "w", ImportName: "*"},
+	}, program.IndirectExportEntries)
+	require.Equal([]*ast.ExportEntry{
+		{ModuleRequest: "w", ImportName: "*"},
+	}, program.StarExportEntries)
+}
@@ -0,0 +1,323 @@
+package parser
+
+import (
+	"github.com/gojisvm/gojis/internal/parser/ast"
+)
+
+// parseBindingAtom parses a BindingIdentifier or a BindingPattern.
+func (p *sourceParser) parseBindingAtom() ast.Pattern {
+	p.enter()
+	defer p.leave()
+
+	start := p.tok.start
+
+	switch {
+	case p.eat("["):
+		elements := p.parseBindingList("]", true)
+		return &ast.ArrayPattern{
+			Location: p.loc(start),
+			Elements: elements,
+		}
+	case p.is("{"):
+		return p.parseObjectBindingPattern()
+	}
+
+	return p.parseBindingIdentifier()
+}
+
+// parseBindingList parses a comma separated list of binding elements and an
+// optional rest element up to the given closing punctuator, which is used for
+// array binding patterns and formal parameters. If allowHoles is true,
+// elisions are allowed and represented as nil.
+func (p *sourceParser) parseBindingList(close string, allowHoles bool) []ast.Pattern {
+	var elements []ast.Pattern
+	for first := true; !p.eat(close); first = false {
+		if !first {
+			p.expect(",")
+			if p.eat(close) {
+				break
+			}
+		}
+
+		if allowHoles && p.is(",") {
+			elements = append(elements, nil)
+			continue
+		}
+
+		if p.is("...") {
+			start := p.tok.start
+			p.next()
+			argument := p.parseBindingAtom()
+			elements = append(elements, &ast.RestElement{
+				Location: p.loc(start),
+				Argument: argument,
+			})
+			if p.is(",") {
+				p.errorAt(p.tok.start, "Comma is not permitted after the rest element")
+			}
+			p.expect(close)
+			break
+		}
+
+		elements = append(elements, p.parseBindingElement())
+	}
+	return elements
+}
+
+// parseBindingElement parses a binding pattern or identifier with an optional
+// default value.
+func (p *sourceParser) parseBindingElement() ast.Pattern {
+	start := p.tok.start
+	left := p.parseBindingAtom()
+	if !p.eat("=") {
+		return left
+	}
+
+	right := p.parseMaybeAssign(false, nil)
+	return &ast.AssignmentPattern{
+		Location: p.loc(start),
+		Left:     left,
+		Right:    right,
+	}
+}
+
+func (p *sourceParser) parseObjectBindingPattern() ast.Pattern {
+	start := p.tok.start
+	p.expect("{")
+
+	var properties []ast.Node
+	for first := true; !p.eat("}"); first = false {
+		if !first {
+			p.expect(",")
+			if p.eat("}") {
+				break
+			}
+		}
+
+		propStart := p.tok.start
+		if p.eat("...") {
+			argument := p.parseBindingIdentifier()
+			properties = append(properties, &ast.RestElement{
+				Location: p.loc(propStart),
+				Argument: argument,
+			})
+			if !p.is("}") {
+				p.errorAt(p.tok.start, "Rest element must be last element")
+			}
+			continue
+		}
+
+		keyToken := p.tok
+		key, computed := p.parsePropertyName()
+		if p.eat(":") {
+			value := p.parseBindingElement()
+			properties = append(properties, &ast.Property{
+				Location: p.loc(propStart),
+				Key:      key,
+				Value:    value,
+				Kind:     ast.KindInit,
+				Computed: computed,
+			})
+			continue
+		}
+
+		// shorthand property, the key must be a binding identifier
+		if keyToken.kind != tokenName {
+			p.unexpected("':'")
+		}
+		p.checkUnreserved(keyToken)
+		id := key.(*ast.Identifier)
+		var value ast.Pattern = &ast.Identifier{
+			Location: id.Location,
+			Name:     id.Name,
+		}
+		if p.eat("=") {
+			right := p.parseMaybeAssign(false, nil)
+			value = &ast.AssignmentPattern{
+				Location: p.loc(propStart),
+				Left:     value,
+				Right:    right,
+			}
+		}
+		properties = append(properties, &ast.Property{
+			Location:  p.loc(propStart),
+			Key:       key,
+			Value:     value,
+			Kind:      ast.KindInit,
+			Shorthand: true,
+		})
+	}
+
+	return &ast.ObjectPattern{
+		Location:   p.loc(start),
+		Properties: properties,
+	}
+}
+
+// toAssignableTarget converts the target of an assignment or the left-hand
+// side of a for-in or for-of loop to a pattern. The target must either be a
+// simple assignment target or an object or array literal, which is converted
+// to a pattern.
+func (p *sourceParser) toAssignableTarget(expr ast.Expression) ast.Pattern {
+	switch expr.(type) {
+	case *ast.ObjectExpression, *ast.ArrayExpression:
+		return p.toAssignable(expr, false)
+	}
+	return p.checkSimpleTarget(expr)
+}
+
+// toAssignable converts an expression that has been parsed with the cover
+// grammar to a pattern. If binding is true, the pattern is the target of a
+// binding, e.g. arrow parameters, which does not allow member expressions.
+func (p *sourceParser) toAssignable(expr ast.Expression, binding bool) ast.Pattern {
+	if p.parenthesized[expr] {
+		// only simple assignment targets may be parenthesized
+		_, simple := expr.(*ast.Identifier)
+		if _, ok := expr.(*ast.MemberExpression); ok {
+			simple = true
+		}
+		if binding || !simple {
+			p.errorAt(expr.Loc().Start, "Invalid destructuring assignment target")
+		}
+	}
+
+	switch n := expr.(type) {
+	case *ast.Identifier:
+		return n
+	case *ast.MemberExpression:
+		if binding {
+			p.errorAt(n.Start, "Invalid destructuring assignment target")
+		}
+		return n
+	case *ast.ObjectExpression:
+		pattern := &ast.ObjectPattern{Location: n.Location}
+		for i, prop := range n.Properties {
+			switch prop := prop.(type) {
+			case *ast.Property:
+				if prop.Kind != ast.KindInit || prop.Method {
+					p.errorAt(prop.Start, "Object pattern can't contain getter, setter or method")
+				}
+				prop.Value = p.toAssignableValue(prop.Value, binding)
+				pattern.Properties = append(pattern.Properties, prop)
+			case *ast.SpreadElement:
+				if i != len(n.Properties)-1 {
+					p.errorAt(prop.Start, "Rest element must be last element")
+				}
+				var argument ast.Pattern
+				if binding {
+					id, ok := prop.Argument.(*ast.Identifier)
+					if !ok || p.parenthesized[id] {
+						p.errorAt(prop.Argument.Loc().Start, "Invalid rest element")
+					}
+					argument = id
+				} else {
+					argument = p.checkSimpleTarget(prop.Argument)
+				}
+				pattern.Properties = append(pattern.Properties, &ast.RestElement{
+					Location: prop.Location,
+					Argument: argument,
+				})
+			}
+		}
+		return pattern
+	case *ast.ArrayExpression:
+		if p.spreadTrailingComma[n] {
+			p.errorAt(n.Start, "Comma is not permitted after the rest element")
+		}
+		return &ast.ArrayPattern{
+			Location: n.Location,
+			Elements: p.toAssignableList(n.Elements, binding),
+		}
+	case *ast.AssignmentExpression:
+		if n.Operator != "=" {
+			p.errorAt(n.Left.Loc().End, "Only '=' operator can be used for specifying default value.")
+		}
+		if binding {
+			p.checkBindingPattern(n.Left)
+		}
+		return &ast.AssignmentPattern{
+			Location: n.Location,
+			Left:     n.Left,
+			Right:    n.Right,
+		}
+	}
+
+	p.errorAt(expr.Loc().Start, "Invalid destructuring assignment target")
+	return nil
+}
+
+// toAssignableValue converts the value of a property in an object literal to
+// a pattern.
+func (p *sourceParser) toAssignableValue(value ast.Node, binding bool) ast.Pattern {
+	if pattern, ok := value.(*ast.AssignmentPattern); ok {
+		// a CoverInitializedName, whose left-hand side is an identifier
+		return pattern
+	}
+	return p.toAssignable(value.(ast.Expression), binding)
+}
+
+// toAssignableList converts a list of expressions, e.g. the elements of an
+// array literal or the parameters of an arrow function, to a list of
+// patterns. Holes are kept, a trailing spread element is converted to a rest
+// element.
+func (p *sourceParser) toAssignableList(exprs []ast.Expression, binding bool) []ast.Pattern {
+	patterns := make([]ast.Pattern, len(exprs))
+	for i, expr := range exprs {
+		if expr == nil {
+			continue
+		}
+
+		spread, ok := expr.(*ast.SpreadElement)
+		if !ok {
+			patterns[i] = p.toAssignable(expr, binding)
+			continue
+		}
+
+		if i != len(exprs)-1 {
+			p.errorAt(spread.Start, "Rest element must be last element")
+		}
+		if _, ok := spread.Argument.(*ast.AssignmentExpression); ok && !p.parenthesized[spread.Argument] {
+			p.errorAt(spread.Argument.Loc().Start, "Rest elements cannot have a default value")
+		}
+		patterns[i] = &ast.RestElement{
+			Location: spread.Location,
+			Argument: p.toAssignable(spread.Argument, binding),
+		}
+	}
+	return patterns
+}
+
+// checkBindingPattern reports an error if the given pattern, which has been
+// converted to an assignment pattern, is not a valid binding pattern.
+func (p *sourceParser) checkBindingPattern(pattern ast.Pattern) {
+	switch n := pattern.(type) {
+	case *ast.Identifier:
+		if p.parenthesized[n] {
+			p.errorAt(n.Start, "Invalid destructuring assignment target")
+		}
+	case *ast.ObjectPattern:
+		for _, prop := range n.Properties {
+			switch prop := prop.(type) {
+			case *ast.Property:
+				p.checkBindingPattern(prop.Value.(ast.Pattern))
+			case *ast.RestElement:
+				if _, ok := prop.Argument.(*ast.Identifier); !ok {
+					p.errorAt(prop.Argument.Loc().Start, "Invalid rest element")
+				}
+				p.checkBindingPattern(prop.Argument)
+			}
+		}
+	case *ast.ArrayPattern:
+		for _, element := range n.Elements {
+			if element != nil {
+				p.checkBindingPattern(element)
+			}
+		}
+	case *ast.RestElement:
+		p.checkBindingPattern(n.Argument)
+	case *ast.AssignmentPattern:
+		p.checkBindingPattern(n.Left)
+	default:
+		p.errorAt(pattern.Loc().Start, "Invalid destructuring assignment target")
+	}
+}
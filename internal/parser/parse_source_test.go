@@ -0,0 +1,84 @@
+package parser
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) { return 0, errors.New("read failed") }
+
+func TestParseStringErrorName(t *testing.T) {
+	require := require.New(t)
+
+	p := New()
+	err := p.ParseString("inline.js", "var = ;")
+	require.Error(err)
+	require.IsType(ParserError{}, err)
+	require.Equal("inline.js", err.(ParserError).File())
+	require.Equal(0, p.Ast().Len())
+}
+
+func TestParseReaderErrorName(t *testing.T) {
+	require := require.New(t)
+
+	p := New()
+	err := p.ParseReader("reader.js", strings.NewReader("var = ;"))
+	require.Error(err)
+	require.IsType(ParserError{}, err)
+	require.Equal("reader.js", err.(ParserError).File())
+}
+
+func TestParseReaderReadError(t *testing.T) {
+	require := require.New(t)
+
+	p := New()
+	err := p.ParseReader("broken.js", failingReader{})
+	require.Error(err)
+	require.Contains(err.Error(), "broken.js")
+	require.Contains(err.Error(), "read failed")
+}
+
+func TestParseNestingDepth(t *testing.T) {
+	nested := func(open, inner, close string, n int) string {
+		return strings.Repeat(open, n) + inner + strings.Repeat(close, n)
+	}
+
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{"parentheses", nested("(", "1", ")", 300000)},
+		{"arrays", nested("[", "", "]", 300000)},
+		{"blocks", nested("{", "", "}", 300000)},
+		{"unary operators", nested("!", "1", "", 300000)},
+		{"new", nested("new ", "a", "", 300000)},
+		{"exponentiation", nested("2**", "2", "", 300000)},
+		{"arrow functions", nested("x=>", "1", "", 300000)},
+		{"patterns", "var " + nested("[", "a", "]", 300000) + " = a"},
+		{"classes", nested("(class extends ", "a", "{})", 300000)},
+		{"binary operators", nested("a+", "a", "", 300000)},
+		{"logical operators", nested("a||", "a", "", 300000)},
+		{"member accesses", nested("", "o", ".x", 300000)},
+		{"computed member accesses", nested("", "o", "[0]", 300000)},
+		{"calls", nested("", "f", "()", 300000)},
+		{"tagged templates", nested("", "f", "``", 300000)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+
+			err := New().ParseString("nested.js", tt.src)
+			require.IsType(ParserError{}, err)
+			require.Len(err.(ParserError).Errors(), 1)
+			require.Contains(err.Error(), "Maximum nesting depth of 1000 exceeded")
+		})
+	}
+
+	require.NoError(t, New().ParseString("nested.js", nested("(", "1", ")", 500)))
+	require.NoError(t, New().ParseString("nested.js", nested("a+", "o.x()", "", 900)))
+}
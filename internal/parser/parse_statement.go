@@ -0,0 +1,596 @@
+package parser
+
+import (
+	"github.com/gojisvm/gojis/internal/parser/ast"
+)
+
+// statementContext describes where a statement appears, which determines
+// whether declarations are allowed.
+type statementContext uint8
+
+const (
+	// contextStatementList is a statement in a StatementList, where all
+	// declarations are allowed.
+	contextStatementList statementContext = iota
+	// contextIf is the body of an if statement, where function declarations
+	// are allowed in non-strict code, as specified in B.3.4.
+	contextIf
+	// contextLabel is the body of a labelled statement, where function
+	// declarations are allowed in non-strict code, as specified in B.3.2.
+	contextLabel
+	// contextStatement is any other single statement position, where no
+	// declarations are allowed.
+	contextStatement
+)
+
+// parseDirectivesAndStatements parses a directive prologue, followed by a
+// statement list, until end returns true. If the directive prologue contains
+// a Use Strict Directive, the parser switches to strict mode.
+func (p *sourceParser) parseDirectivesAndStatements(end func() bool) []ast.Statement {
+	var body []ast.Statement
+	directives := true
+	for !end() {
+		if p.tok.kind == tokenEOF {
+			p.unexpected("'}'")
+		}
+		stmt := p.parseStatementListItem()
+		if stmt == nil {
+			directives = false
+			continue
+		}
+		if directives {
+			if directive, ok := p.directive(stmt); ok {
+				stmt.(*ast.ExpressionStatement).Directive = directive
+				if directive == "use strict" {
+					p.strict = true
+				}
+			} else {
+				directives = false
+			}
+		}
+		body = append(body, stmt)
+	}
+	return body
+}
+
+// directive returns the directive of the given statement, and whether the
+// statement is part of a directive prologue, as specified in 14.1.1.
+func (p *sourceParser) directive(stmt ast.Statement) (string, bool) {
+	exprStmt, ok := stmt.(*ast.ExpressionStatement)
+	if !ok {
+		return "", false
+	}
+	lit, ok := exprStmt.Expression.(*ast.Literal)
+	if !ok || p.parenthesized[lit] {
+		return "", false
+	}
+	if _, ok := lit.Value.(string); !ok {
+		return "", false
+	}
+	// unterminated string literals only reach this point in recovery mode
+	if len(lit.Raw) < 2 || lit.Raw[len(lit.Raw)-1] != lit.Raw[0] {
+		return "", false
+	}
+	return lit.Raw[1 : len(lit.Raw)-1], true
+}
+
+// parseStatement parses a statement or, depending on the given context, a
+// declaration.
+func (p *sourceParser) parseStatement(ctx statementContext) ast.Statement {
+	p.enter()
+	defer p.leave()
+
+	start := p.tok.start
+
+	if p.is("{") {
+		return p.parseBlock()
+	}
+	if p.eat(";") {
+		return &ast.EmptyStatement{Location: p.loc(start)}
+	}
+
+	if p.tok.kind == tokenName && !p.tok.escaped {
+		switch p.tok.value {
+		case "var":
+			return p.parseVarStatement(start, ast.VariableKindVar)
+		case "let":
+			if p.isLetDeclaration(ctx == contextStatementList) {
+				if ctx != contextStatementList {
+					p.errorAt(start, "Lexical declaration cannot appear in a single-statement context")
+				}
+				return p.parseVarStatement(start, ast.VariableKindLet)
+			}
+		case "const":
+			if ctx != contextStatementList {
+				p.errorAt(start, "Lexical declaration cannot appear in a single-statement context")
+			}
+			return p.parseVarStatement(start, ast.VariableKindConst)
+		case "function":
+			return p.parseFunctionStatement(start, false, ctx)
+		case "async":
+			if next := p.peek(); isName(next, "function") && !next.newlineBefore {
+				if ctx != contextStatementList {
+					p.errorAt(start, "Async functions can only be declared at the top level or inside a block")
+				}
+				p.next()
+				return p.parseFunctionStatement(start, true, ctx)
+			}
+		case "class":
+			if ctx != contextStatementList {
+				p.unexpected("statement")
+			}
+			return p.parseClassDeclaration(start, false)
+		case "if":
+			return p.parseIfStatement(start)
+		case "for":
+			return p.parseForStatement(start)
+		case "while":
+			return p.parseWhileStatement(start)
+		case "do":
+			return p.parseDoWhileStatement(start)
+		case "continue", "break":
+			return p.parseBreakContinueStatement(start)
+		case "return":
+			return p.parseReturnStatement(start)
+		case "with":
+			return p.parseWithStatement(start)
+		case "switch":
+			return p.parseSwitchStatement(start)
+		case "throw":
+			return p.parseThrowStatement(start)
+		case "try":
+			return p.parseTryStatement(start)
+		case "debugger":
+			p.next()
+			p.semicolon()
+			return &ast.DebuggerStatement{Location: p.loc(start)}
+		case "import", "export":
+			if p.module {
+				p.errorAt(start, "'%v' may only appear at the top level of a module", p.tok.value)
+			}
+			p.errorAt(start, "'%v' may only appear in a module", p.tok.value)
+		}
+	}
+
+	startsWithName := p.tok.kind == tokenName
+	expr := p.parseExpression(false, nil)
+	if id, ok := expr.(*ast.Identifier); ok && startsWithName && !p.parenthesized[id] && p.eat(":") {
+		bodyCtx := contextStatement
+		if ctx == contextStatementList || ctx == contextLabel {
+			bodyCtx = contextLabel
+		}
+		body := p.parseStatement(bodyCtx)
+		return &ast.LabeledStatement{
+			Location: p.loc(start),
+			Label:    id,
+			Body:     body,
+		}
+	}
+
+	p.semicolon()
+	return &ast.ExpressionStatement{
+		Location:   p.loc(start),
+		Expression: expr,
+	}
+}
+
+// isLetDeclaration is used to determine whether the current token 'let' starts
+// a lexical declaration, or is an identifier. If declarations are not allowed,
+// only 'let [' is considered a declaration, which is always an error.
+func (p *sourceParser) isLetDeclaration(declarationAllowed bool) bool {
+	if !p.isName("let") {
+		return false
+	}
+
+	next := p.peek()
+	if next.kind == tokenPunctuator && next.value == "[" {
+		return true
+	}
+	if !declarationAllowed {
+		return false
+	}
+	if next.kind == tokenPunctuator && next.value == "{" {
+		return true
+	}
+	return next.kind == tokenName && !isName(next, "in") && !isName(next, "instanceof")
+}
+
+func (p *sourceParser) parseBlock() *ast.BlockStatement {
+	start := p.tok.start
+	p.expect("{")
+	var body []ast.Statement
+	for !p.eat("}") {
+		if p.tok.kind == tokenEOF {
+			p.unexpected("'}'")
+		}
+		if stmt := p.parseStatementListItem(); stmt != nil {
+			body = append(body, stmt)
+		}
+	}
+	return &ast.BlockStatement{
+		Location: p.loc(start),
+		Body:     body,
+	}
+}
+
+func (p *sourceParser) parseVarStatement(start ast.Position, kind string) ast.Statement {
+	p.next()
+	decl := p.parseVar(start, kind, false)
+	p.checkInitializers(decl)
+	p.semicolon()
+	decl.Location = p.loc(start)
+	return decl
+}
+
+// parseVar parses the declarators of a variable declaration. The keyword
+// must already be consumed.
+func (p *sourceParser) parseVar(start ast.Position, kind string, noIn bool) *ast.VariableDeclaration {
+	decl := &ast.VariableDeclaration{Kind: kind}
+	for {
+		declStart := p.tok.start
+		id := p.parseBindingAtom()
+		var init ast.Expression
+		if p.eat("=") {
+			init = p.parseMaybeAssign(noIn, nil)
+		}
+		decl.Declarations = append(decl.Declarations, &ast.VariableDeclarator{
+			Location: p.loc(declStart),
+			ID:       id,
+			Init:     init,
+		})
+
+		if !p.eat(",") {
+			break
+		}
+	}
+	decl.Location = p.loc(start)
+	return decl
+}
+
+// checkInitializers reports an error if a const declaration or a
+// destructuring declaration misses its initializer. This does not apply to
+// declarations in the head of for-in and for-of loops.
+func (p *sourceParser) checkInitializers(decl *ast.VariableDeclaration) {
+	for _, d := range decl.Declarations {
+		if d.Init != nil {
+			continue
+		}
+		if decl.Kind == ast.VariableKindConst {
+			p.errorAt(d.Start, "Missing initializer in const declaration")
+		}
+		if _, ok := d.ID.(*ast.Identifier); !ok {
+			p.errorAt(d.Start, "Missing initializer in destructuring declaration")
+		}
+	}
+}
+
+func (p *sourceParser) parseFunctionStatement(start ast.Position, async bool, ctx statementContext) ast.Statement {
+	p.next() // 'function'
+	fn := p.parseFunction(start, true, false, async)
+
+	if ctx != contextStatementList {
+		if p.strict || ctx == contextStatement {
+			p.errorAt(start, "Function declarations are not allowed in this context")
+		}
+		if fn.Generator || fn.Async {
+			p.errorAt(start, "Generator and async function declarations are not allowed in this context")
+		}
+	}
+
+	return &ast.FunctionDeclaration{
+		Location: fn.Location,
+		Function: fn.Function,
+	}
+}
+
+func (p *sourceParser) parseIfStatement(start ast.Position) ast.Statement {
+	p.next()
+	test := p.parseParenExpression()
+	consequent := p.parseStatement(contextIf)
+	var alternate ast.Statement
+	if p.eatName("else") {
+		alternate = p.parseStatement(contextIf)
+	}
+	return &ast.IfStatement{
+		Location:   p.loc(start),
+		Test:       test,
+		Consequent: consequent,
+		Alternate:  alternate,
+	}
+}
+
+func (p *sourceParser) parseParenExpression() ast.Expression {
+	p.expect("(")
+	expr := p.parseExpression(false, nil)
+	p.expect(")")
+	return expr
+}
+
+func (p *sourceParser) parseWhileStatement(start ast.Position) ast.Statement {
+	p.next()
+	test := p.parseParenExpression()
+	body := p.parseStatement(contextStatement)
+	return &ast.WhileStatement{
+		Location: p.loc(start),
+		Test:     test,
+		Body:     body,
+	}
+}
+
+func (p *sourceParser) parseDoWhileStatement(start ast.Position) ast.Statement {
+	p.next()
+	body := p.parseStatement(contextStatement)
+	p.expectName("while")
+	test := p.parseParenExpression()
+	// a semicolon is always inserted after a do-while statement, if missing
+	p.eat(";")
+	return &ast.DoWhileStatement{
+		Location: p.loc(start),
+		Body:     body,
+		Test:     test,
+	}
+}
+
+func (p *sourceParser) parseForStatement(start ast.Position) ast.Statement {
+	p.next()
+
+	await := false
+	if p.fn.async && p.isName("await") {
+		if !p.awaitPos.IsValid() {
+			p.awaitPos = p.tok.start
+		}
+		await = true
+		p.next()
+	}
+	p.expect("(")
+
+	if p.is(";") {
+		if await {
+			p.unexpected("'of'")
+		}
+		return p.parseFor(start, nil)
+	}
+
+	initStart := p.tok.start
+	if p.isName("var") || p.isName("const") || p.isLetDeclaration(true) {
+		kind := p.tok.value
+		p.next()
+		decl := p.parseVar(initStart, kind, true)
+		if len(decl.Declarations) == 1 && (p.isName("in") || p.isName("of")) {
+			d := decl.Declarations[0]
+			if d.Init != nil {
+				_, simple := d.ID.(*ast.Identifier)
+				// B.3.5 allows initializers in for-in heads of var declarations in non-strict code
+				if p.isName("of") || p.strict || kind != ast.VariableKindVar || !simple {
+					p.errorAt(d.Start, "for-%v loop variable declaration may not have an initializer", p.tok.value)
+				}
+			}
+			return p.parseForInOf(start, decl, await)
+		}
+		if await {
+			p.unexpected("'of'")
+		}
+		p.checkInitializers(decl)
+		return p.parseFor(start, decl)
+	}
+
+	startsWithLet := p.isName("let")
+	refDestructuringErrors := new(destructuringErrors)
+	init := p.parseExpression(true, refDestructuringErrors)
+	if p.isName("in") || p.isName("of") {
+		if p.isName("of") {
+			if startsWithLet {
+				p.errorAt(initStart, "The left-hand side of a for-of loop may not start with 'let'")
+			}
+			if isPlainIdentifier(init, "async") && !p.parenthesized[init] {
+				p.errorAt(initStart, "The left-hand side of a for-of loop may not be 'async'")
+			}
+		} else if await {
+			p.unexpected("'of'")
+		}
+		left := p.toAssignableTarget(init)
+		return p.parseForInOf(start, left, await)
+	}
+	p.checkExpressionErrors(refDestructuringErrors)
+	if await {
+		p.unexpected("'of'")
+	}
+	return p.parseFor(start, init)
+}
+
+// parseFor parses the rest of a for statement, after the initialization.
+func (p *sourceParser) parseFor(start ast.Position, init ast.Node) ast.Statement {
+	p.expect(";")
+	var test, update ast.Expression
+	if !p.is(";") {
+		test = p.parseExpression(false, nil)
+	}
+	p.expect(";")
+	if !p.is(")") {
+		update = p.parseExpression(false, nil)
+	}
+	p.expect(")")
+	body := p.parseStatement(contextStatement)
+	return &ast.ForStatement{
+		Location: p.loc(start),
+		Init:     init,
+		Test:     test,
+		Update:   update,
+		Body:     body,
+	}
+}
+
+// parseForInOf parses the rest of a for-in or for-of statement, after the
+// left-hand side.
+func (p *sourceParser) parseForInOf(start ast.Position, left ast.Node, await bool) ast.Statement {
+	if p.eatName("of") {
+		right := p.parseMaybeAssign(false, nil)
+		p.expect(")")
+		body := p.parseStatement(contextStatement)
+		return &ast.ForOfStatement{
+			Location: p.loc(start),
+			Left:     left,
+			Right:    right,
+			Body:     body,
+			Await:    await,
+		}
+	}
+
+	p.expectName("in")
+	right := p.parseExpression(false, nil)
+	p.expect(")")
+	body := p.parseStatement(contextStatement)
+	return &ast.ForInStatement{
+		Location: p.loc(start),
+		Left:     left,
+		Right:    right,
+		Body:     body,
+	}
+}
+
+func (p *sourceParser) parseBreakContinueStatement(start ast.Position) ast.Statement {
+	isBreak := p.tok.value == "break"
+	p.next()
+
+	var label *ast.Identifier
+	if !p.is(";") && !p.canInsertSemicolon() {
+		if p.tok.kind != tokenName {
+			p.unexpected("identifier")
+		}
+		label = p.parseIdentifier(false)
+	}
+	p.semicolon()
+
+	if isBreak {
+		return &ast.BreakStatement{
+			Location: p.loc(start),
+			Label:    label,
+		}
+	}
+	return &ast.ContinueStatement{
+		Location: p.loc(start),
+		Label:    label,
+	}
+}
+
+func (p *sourceParser) parseReturnStatement(start ast.Position) ast.Statement {
+	if !p.fn.function {
+		p.errorAt(start, "Illegal return statement")
+	}
+	p.next()
+
+	var argument ast.Expression
+	if !p.is(";") && !p.canInsertSemicolon() {
+		argument = p.parseExpression(false, nil)
+	}
+	p.semicolon()
+	return &ast.ReturnStatement{
+		Location: p.loc(start),
+		Argument: argument,
+	}
+}
+
+func (p *sourceParser) parseWithStatement(start ast.Position) ast.Statement {
+	p.next()
+	object := p.parseParenExpression()
+	body := p.parseStatement(contextStatement)
+	return &ast.WithStatement{
+		Location: p.loc(start),
+		Object:   object,
+		Body:     body,
+	}
+}
+
+func (p *sourceParser) parseSwitchStatement(start ast.Position) ast.Statement {
+	p.next()
+	discriminant := p.parseParenExpression()
+	p.expect("{")
+
+	var cases []*ast.SwitchCase
+	sawDefault := false
+	for !p.eat("}") {
+		caseStart := p.tok.start
+		var test ast.Expression
+		if p.eatName("case") {
+			test = p.parseExpression(false, nil)
+		} else if p.isName("default") {
+			if sawDefault {
+				p.errorAt(caseStart, "More than one default clause in switch statement")
+			}
+			sawDefault = true
+			p.next()
+		} else {
+			p.unexpected("'case', 'default' or '}'")
+		}
+		p.expect(":")
+
+		var consequent []ast.Statement
+		for !p.is("}") && !p.isName("case") && !p.isName("default") && p.tok.kind != tokenEOF {
+			if stmt := p.parseStatementListItem(); stmt != nil {
+				consequent = append(consequent, stmt)
+			}
+		}
+		cases = append(cases, &ast.SwitchCase{
+			Location:   p.loc(caseStart),
+			Test:       test,
+			Consequent: consequent,
+		})
+	}
+
+	return &ast.SwitchStatement{
+		Location:     p.loc(start),
+		Discriminant: discriminant,
+		Cases:        cases,
+	}
+}
+
+func (p *sourceParser) parseThrowStatement(start ast.Position) ast.Statement {
+	p.next()
+	if p.tok.newlineBefore {
+		p.errorAt(p.prevEnd, "Illegal newline after throw")
+	}
+	argument := p.parseExpression(false, nil)
+	p.semicolon()
+	return &ast.ThrowStatement{
+		Location: p.loc(start),
+		Argument: argument,
+	}
+}
+
+func (p *sourceParser) parseTryStatement(start ast.Position) ast.Statement {
+	p.next()
+	block := p.parseBlock()
+
+	var handler *ast.CatchClause
+	if p.isName("catch") {
+		catchStart := p.tok.start
+		p.next()
+		var param ast.Pattern
+		if p.eat("(") {
+			param = p.parseBindingAtom()
+			p.expect(")")
+		}
+		body := p.parseBlock()
+		handler = &ast.CatchClause{
+			Location: p.loc(catchStart),
+			Param:    param,
+			Body:     body,
+		}
+	}
+
+	var finalizer *ast.BlockStatement
+	if p.eatName("finally") {
+		finalizer = p.parseBlock()
+	}
+
+	if handler == nil && finalizer == nil {
+		p.errorAt(p.tok.start, "Missing catch or finally after try")
+	}
+
+	return &ast.TryStatement{
+		Location:  p.loc(start),
+		Block:     block,
+		Handler:   handler,
+		Finalizer: finalizer,
+	}
+}
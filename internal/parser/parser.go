@@ -3,23 +3,51 @@ package parser
 import (
 	"bytes"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"strconv"
 
-	"github.com/antlr/antlr4/runtime/Go/antlr"
+	"github.com/gojisvm/gojis/internal/parser/ast"
 )
 
-//go:generate antlr -Dlanguage=Go -visitor ECMAScript.g4
-
+// Parser is used to parse ECMAScript source files. All successfully
+// parsed files are added as roots to the parser's Ast.
 type Parser struct {
-	ast interface{}
+	ast *Ast
+
+	recovery bool
+	strict   bool
 }
 
+// New creates a new parser with an empty Ast.
 func New() *Parser {
 	p := new(Parser)
-	// p.ast = NewEmptyAst()
+	p.ast = NewEmptyAst()
 	return p
 }
 
+// SetRecovery enables or disables the recovery mode. By default, a file
+// that contains syntax errors is not added to the Ast. In recovery mode, the
+// parser resynchronizes at the next statement boundary after a syntax error
+// instead of giving up, so that all syntax errors of a file are reported.
+// The partial program, which contains all statements that could be parsed,
+// is added to the Ast, and a ParserError holding all errors is returned as
+// usual.
+func (p *Parser) SetRecovery(enabled bool) {
+	p.recovery = enabled
+}
+
+// SetStrict enables or disables the strict mode. By default, a script is
+// strict mode code only if it begins with a Use Strict Directive. If the
+// strict mode is enabled, all scripts are parsed as strict mode code, as is
+// needed for the code of a direct eval in strict mode code.
+func (p *Parser) SetStrict(enabled bool) {
+	p.strict = enabled
+}
+
+// ParseFiles parses all files with the given paths. Parsing does not stop
+// at the first file that contains errors, instead, all errors that occurred
+// are returned.
 func (p *Parser) ParseFiles(paths ...string) (errs []error) {
 	for _, path := range paths {
 		err := p.ParseFile(path)
@@ -30,45 +58,106 @@ func (p *Parser) ParseFiles(paths ...string) (errs []error) {
 	return
 }
 
+// ParseFile parses the file with the given path. If the file contains syntax
+// errors, a ParserError containing all of them is returned, and the file is
+// not added to the Ast.
 func (p *Parser) ParseFile(path string) error {
-	input, err := antlr.NewFileStream(path)
+	src, err := ioutil.ReadFile(path)
 	if err != nil {
 		return fmt.Errorf("Error while loading file: %v", err)
 	}
 
-	_ = input
+	return p.parse(path, string(src), false)
+}
 
-	// lexer := NewECMAScriptLexer(input)
-	// lexer.RemoveErrorListeners()
+// ParseString parses the given source code. The given name is used to
+// identify the source, that is, it is used as the name of the root in the Ast
+// and in all error messages. If the source contains syntax errors, a
+// ParserError containing all of them is returned, and the source is not added
+// to the Ast.
+func (p *Parser) ParseString(name, src string) error {
+	return p.parse(name, src, false)
+}
 
-	// stream := antlr.NewCommonTokenStream(lexer, 0)
-	// par := NewECMAScriptParser(stream)
-	// par.RemoveErrorListeners()
+// ParseReader reads all source code from the given reader and parses it, just
+// like ParseString does.
+func (p *Parser) ParseReader(name string, r io.Reader) error {
+	src, err := ioutil.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("Error while reading '%v': %v", name, err)
+	}
 
-	// errorCollector := NewCollectingErrorListener()
-	// par.AddErrorListener(errorCollector)
-	// par.BuildParseTrees = true
+	return p.ParseString(name, string(src))
+}
 
-	// tree := par.Program()
-	// if errs, hasErrors := errorCollector.Errors(); hasErrors {
-	// 	return NewParserError(path, errs...)
-	// }
+// ParseModuleFile parses the file with the given path as module, just like
+// ParseModule does.
+func (p *Parser) ParseModuleFile(path string) error {
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("Error while loading file: %v", err)
+	}
 
-	// // only append root if no errors occurred while parsing
-	// p.ast.AddRoot(path, tree)
+	return p.ParseModule(path, string(src))
+}
 
-	panic("TODO")
+// ParseModule parses the given source code as module, that is, with Module
+// as goal symbol. Module code is always strict mode code, and may contain
+// import and export declarations, whose import and export entries are
+// available in the resulting program. Apart from that, ParseModule works
+// just like ParseString.
+func (p *Parser) ParseModule(name, src string) error {
+	return p.parse(name, src, true)
 }
 
-func (p *Parser) Ast() interface{} {
+// parse parses the given source code as script or, if module is true, as
+// module, and checks it for early errors. All syntax errors are collected
+// and returned as a ParserError, sorted by their position. Only if no errors
+// occurred or the parser is in recovery mode, the resulting program is added
+// to the Ast as root for the given name.
+func (p *Parser) parse(name, src string, module bool) error {
+	errorCollector := NewCollectingErrorListener(name)
+
+	sourceParser := newSourceParser(name, src, errorCollector, p.recovery)
+	sourceParser.strict = p.strict
+	var program *ast.Program
+	if module {
+		program = sourceParser.parseModule()
+	} else {
+		program = sourceParser.parseScript()
+	}
+	if program != nil {
+		checkEarlyErrors(name, src, program, errorCollector)
+	}
+	errs, hasErrors := errorCollector.Errors()
+	// early errors are found after all syntax errors have been reported
+	sortErrors(errs)
+	if hasErrors && !p.recovery {
+		return NewParserError(name, errs...)
+	}
+
+	p.ast.AddRoot(name, program)
+
+	if hasErrors {
+		return NewParserError(name, errs...)
+	}
+	return nil
+}
+
+// Ast returns the Ast that holds all files that were successfully parsed
+// by this parser.
+func (p *Parser) Ast() *Ast {
 	return p.ast
 }
 
+// ParserError holds all errors that occurred while parsing a single file.
 type ParserError struct {
 	file string
 	errs []error
 }
 
+// NewParserError creates a new ParserError for the given file, holding
+// the given errors.
 func NewParserError(file string, errs ...error) ParserError {
 	return ParserError{
 		file: file,
@@ -76,6 +165,16 @@ func NewParserError(file string, errs ...error) ParserError {
 	}
 }
 
+// File returns the name of the file that the errors occurred in.
+func (e ParserError) File() string {
+	return e.file
+}
+
+// Errors returns all errors that occurred while parsing the file.
+func (e ParserError) Errors() []error {
+	return e.errs
+}
+
 func (e ParserError) Error() string {
 	if len(e.errs) == 0 {
 		return ""
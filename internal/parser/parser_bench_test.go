@@ -0,0 +1,85 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const benchmarkSource = `
+var counter = 0, name = "gojis", pattern = /[a-z]+\d*/gi;
+
+function fib(n) {
+	if (n < 2) {
+		return n;
+	}
+	return fib(n - 1) + fib(n - 2);
+}
+
+class Point extends Object {
+	constructor(x, y) {
+		super();
+		this.x = x;
+		this.y = y;
+	}
+	get length() { return Math.sqrt(this.x ** 2 + this.y ** 2); }
+	static origin() { return new Point(0, 0); }
+}
+
+const { x, y = 1, ...rest } = { x: 1, z: 3, [name]: true };
+let [first, , third = 3] = [1, 2];
+const add = (a, b = 0) => a + b;
+const greet = async (who) => await Promise.resolve(` + "`hello ${who}!`" + `);
+
+function* range(from, to) {
+	for (let i = from; i < to; i++) {
+		yield i;
+	}
+}
+
+for (const v of range(0, 10)) {
+	switch (v % 3) {
+	case 0:
+		counter += v;
+		break;
+	default:
+		counter -= v / 2;
+	}
+}
+
+try {
+	JSON.parse("{}");
+} catch (e) {
+	throw e;
+} finally {
+	counter = counter > 0 ? counter : -counter;
+}
+`
+
+func benchmarkSourceOfSize(copies int) string {
+	var buf strings.Builder
+	for i := 0; i < copies; i++ {
+		buf.WriteString("(function () {")
+		buf.WriteString(benchmarkSource)
+		buf.WriteString("})();\n")
+	}
+	return buf.String()
+}
+
+// BenchmarkParseString measures the hand-written parser only. The comparison
+// with the ANTLR parser that it replaces is descoped, since the generated
+// ANTLR parser has never been part of the tree.
+func BenchmarkParseString(b *testing.B) {
+	for _, copies := range []int{1, 10, 100} {
+		src := benchmarkSourceOfSize(copies)
+		b.Run(fmt.Sprintf("%vB", len(src)), func(b *testing.B) {
+			b.SetBytes(int64(len(src)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if err := New().ParseString("bench.js", src); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package parser
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -71,7 +72,12 @@ func parse(t *testing.T, path string, successfulParse bool) {
 		require := require.New(t)
 
 		p := New()
-		err := p.ParseFile(path)
+		var err error
+		if strings.HasSuffix(path, ".module.js") {
+			err = p.ParseModuleFile(path)
+		} else {
+			err = p.ParseFile(path)
+		}
 
 		if successfulParse {
 			require.NoError(err)
@@ -0,0 +1,155 @@
+package parser
+
+import (
+	"github.com/gojisvm/gojis/internal/parser/ast"
+)
+
+// parserState is the part of the parser's state that depends on the
+// enclosing function and statement, and that has to be restored when
+// recovering from a syntax error.
+type parserState struct {
+	strict           bool
+	fn               functionContext
+	potentialArrowAt int
+	yieldPos         ast.Position
+	awaitPos         ast.Position
+	awaitIdentPos    ast.Position
+	depth            int
+}
+
+func (p *sourceParser) saveState() parserState {
+	return parserState{
+		strict:           p.strict,
+		fn:               p.fn,
+		potentialArrowAt: p.potentialArrowAt,
+		yieldPos:         p.yieldPos,
+		awaitPos:         p.awaitPos,
+		awaitIdentPos:    p.awaitIdentPos,
+		depth:            p.depth,
+	}
+}
+
+func (p *sourceParser) restoreState(s parserState) {
+	p.strict = s.strict
+	p.fn = s.fn
+	p.potentialArrowAt = s.potentialArrowAt
+	p.yieldPos = s.yieldPos
+	p.awaitPos = s.awaitPos
+	p.awaitIdentPos = s.awaitIdentPos
+	p.depth = s.depth
+}
+
+// statementKeywords are the keywords that start a statement. In recovery
+// mode, a statement keyword at the beginning of a line is considered to be
+// the start of the next statement.
+var statementKeywords = map[string]bool{
+	"break":    true,
+	"class":    true,
+	"const":    true,
+	"continue": true,
+	"debugger": true,
+	"do":       true,
+	"export":   true,
+	"for":      true,
+	"function": true,
+	"if":       true,
+	"import":   true,
+	"let":      true,
+	"return":   true,
+	"switch":   true,
+	"throw":    true,
+	"try":      true,
+	"var":      true,
+	"while":    true,
+	"with":     true,
+}
+
+// parseStatementListItem parses a statement or a declaration in a statement
+// list.
+func (p *sourceParser) parseStatementListItem() ast.Statement {
+	return p.recoverStatement(func() ast.Statement {
+		return p.parseStatement(contextStatementList)
+	})
+}
+
+// recoverStatement parses a statement with the given function. In recovery
+// mode, a syntax error in the statement does not abort parsing. Instead, the
+// parser skips to the next statement boundary and returns nil, so that the
+// statement is left out of the tree.
+func (p *sourceParser) recoverStatement(parse func() ast.Statement) (stmt ast.Statement) {
+	if !p.recovery {
+		return parse()
+	}
+
+	start := p.tok.start
+	state := p.saveState()
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(bailout); !ok {
+				panic(r)
+			}
+			p.restoreState(state)
+			p.synchronize(start)
+			stmt = nil
+		}
+	}()
+
+	return parse()
+}
+
+// synchronize skips tokens up to the next statement boundary. The given
+// position is the start of the statement that contained the error. At least
+// one token is skipped if the parser is still at that position, so that
+// parsing always makes progress.
+//
+// Brackets are skipped as a whole. Outside of brackets, a statement boundary
+// is after a semicolon, before a line break, and before the closing brace of
+// an enclosing block. Brackets that are not closed are abandoned at a
+// semicolon or at a statement keyword at the beginning of a line, unless
+// they contain an unclosed brace, since braces enclose function bodies that
+// may contain both.
+func (p *sourceParser) synchronize(start ast.Position) {
+	p.skipping = true
+	defer func() { p.skipping = false }()
+
+	if p.tok.start == start {
+		p.next()
+	}
+
+	var brackets []string
+	braces := 0
+	for p.tok.kind != tokenEOF {
+		if len(brackets) == 0 && p.tok.newlineBefore {
+			return
+		}
+
+		switch {
+		case p.is("{"), p.is("("), p.is("["):
+			brackets = append(brackets, p.tok.value)
+			if p.is("{") {
+				braces++
+			}
+		case p.is("}") && braces == 0:
+			return
+		case p.is("}"), p.is(")"), p.is("]"):
+			if n := len(brackets); n > 0 && closingBracket[brackets[n-1]] == p.tok.value {
+				brackets = brackets[:n-1]
+				if p.is("}") {
+					braces--
+				}
+			}
+		case p.is(";") && braces == 0:
+			p.next()
+			return
+		case braces == 0 && p.tok.newlineBefore && isName(p.tok, p.tok.value) && statementKeywords[p.tok.value]:
+			return
+		}
+		p.next()
+	}
+}
+
+var closingBracket = map[string]string{
+	"{": "}",
+	"(": ")",
+	"[": "]",
+}
@@ -0,0 +1,160 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/gojisvm/gojis/internal/parser/ast"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecovery(t *testing.T) {
+	type diagnostic struct {
+		line     int
+		column   int
+		token    string
+		expected string
+		message  string
+	}
+
+	tests := []struct {
+		src         string
+		diagnostics []diagnostic
+		statements  []string
+	}{
+		{
+			"var a = ;\nvar b = 1;\nfoo(1, 2;\nvar c = 2;",
+			[]diagnostic{
+				{1, 8, ";", "expression", "Unexpected token ';'"},
+				{3, 8, ";", "','", "Unexpected token ';'"},
+			},
+			[]string{"VariableDeclaration", "VariableDeclaration"},
+		},
+		{
+			"function f() {\n  let x = 1 +;\n  return x;\n}\nif (a { b }\nvar c;",
+			[]diagnostic{
+				{2, 13, ";", "expression", "Unexpected token ';'"},
+				{5, 6, "{", "')'", "Unexpected token '{'"},
+			},
+			[]string{"FunctionDeclaration", "VariableDeclaration"},
+		},
+		{
+			"var = 1\nclass {}\nx = `a${",
+			[]diagnostic{
+				{1, 4, "=", "identifier", "Unexpected token '='"},
+				{2, 6, "{", "identifier", "Unexpected token '{'"},
+				{3, 8, "", "expression", "Unexpected end of input"},
+			},
+			nil,
+		},
+		{
+			"@;\nvar a;\n# ;",
+			[]diagnostic{
+				{1, 0, "@", "", "Unexpected character '@'"},
+				{3, 0, "#", "", "Unexpected character '#'"},
+			},
+			[]string{"VariableDeclaration"},
+		},
+		{
+			"{\n  a(;\n  b();\n",
+			[]diagnostic{
+				{2, 4, ";", "expression", "Unexpected token ';'"},
+				{4, 0, "", "'}'", "Unexpected end of input"},
+			},
+			nil,
+		},
+		{
+			"switch (a) {\n  case 1: b = );\n  case 2: c();\n}\n}\nd();",
+			[]diagnostic{
+				{2, 14, ")", "expression", "Unexpected token ')'"},
+				{5, 0, "}", "expression", "Unexpected token '}'"},
+			},
+			[]string{"SwitchStatement", "ExpressionStatement"},
+		},
+		{
+			"let a;\nlet a;\nvar b = ;",
+			[]diagnostic{
+				{2, 4, "a", "", "Identifier 'a' has already been declared"},
+				{3, 8, ";", "expression", "Unexpected token ';'"},
+			},
+			[]string{"VariableDeclaration", "VariableDeclaration"},
+		},
+		{
+			"\"use strict\nvar a = 1 2;\nwith (a) {}",
+			[]diagnostic{
+				{1, 0, "\"use strict", "", "Unterminated string literal"},
+				{2, 10, "2", "';'", "Unexpected token number 2"},
+			},
+			[]string{"ExpressionStatement", "WithStatement"},
+		},
+		{
+			"/a{2,1}/;\nfor (;;) {}",
+			[]diagnostic{
+				{1, 0, "/a{2,1}/", "", "Invalid regular expression: /a{2,1}/: numbers out of order in {} quantifier"},
+			},
+			[]string{"ExpressionStatement", "ForStatement"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.src, func(t *testing.T) {
+			require := require.New(t)
+
+			p := New()
+			p.SetRecovery(true)
+			err := p.ParseString("test.js", test.src)
+			require.Error(err)
+			require.IsType(ParserError{}, err)
+
+			errs := err.(ParserError).Errors()
+			require.Len(errs, len(test.diagnostics))
+			for i, d := range test.diagnostics {
+				e := errs[i].(*Error)
+				require.Equal("test.js", e.File)
+				require.Equal(d.line, e.Pos.Line)
+				require.Equal(d.column, e.Pos.Column)
+				require.Equal(d.token, e.Token)
+				require.Equal(d.expected, e.Expected)
+				require.Equal(d.message, e.Message)
+			}
+
+			program, ok := p.Ast().Root("test.js")
+			require.True(ok)
+			require.Len(program.Body, len(test.statements))
+			for i, typ := range test.statements {
+				require.Equal(typ, program.Body[i].Type())
+			}
+		})
+	}
+}
+
+func TestRecoveryDisabled(t *testing.T) {
+	require := require.New(t)
+
+	p := New()
+	err := p.ParseString("test.js", "var a = ;\nvar b = ;")
+	require.Error(err)
+
+	errs := err.(ParserError).Errors()
+	require.Len(errs, 1)
+	require.Equal(&Error{
+		File:     "test.js",
+		Pos:      ast.Position{Offset: 8, Line: 1, Column: 8},
+		Token:    ";",
+		Expected: "expression",
+		Message:  "Unexpected token ';'",
+	}, errs[0])
+	require.Equal("Syntax error at test.js:1:9: Unexpected token ';', expected expression", errs[0].Error())
+	require.Equal(0, p.Ast().Len())
+}
+
+func TestRecoveryValid(t *testing.T) {
+	require := require.New(t)
+
+	p := New()
+	p.SetRecovery(true)
+	require.NoError(p.ParseString("test.js", "var a = 1;\nfunction f() { return a; }"))
+
+	program, ok := p.Ast().Root("test.js")
+	require.True(ok)
+	require.Len(program.Body, 2)
+}
@@ -0,0 +1,654 @@
+package parser
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf16"
+)
+
+// regExpError is used as panic value to abort the validation of a regular
+// expression pattern.
+type regExpError struct {
+	msg string
+}
+
+// regExpValidator validates the pattern of a regular expression literal
+// against the grammar specified in 21.2.1, or, if the pattern does not have
+// the u flag, against the extended grammar specified in B.1.4.
+type regExpValidator struct {
+	src []rune
+	pos int
+
+	// unicode is true if the u flag is set.
+	unicode bool
+	// namedGroups is true if the pattern contains named groups, which
+	// makes \k a reference to a group name.
+	namedGroups bool
+
+	groupCount    int
+	groupNames    map[string]bool
+	backReference []string
+	maxBackRef    int
+}
+
+// validateRegExp reports an error if the given pattern is not a valid
+// pattern of a regular expression literal with the given flags, which must
+// already be valid. Unicode property escapes are only checked syntactically,
+// the names and values of the properties are not validated.
+func validateRegExp(pattern, flags string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			e, ok := r.(regExpError)
+			if !ok {
+				panic(r)
+			}
+			err = errors.New(e.msg)
+		}
+	}()
+
+	v := new(regExpValidator)
+	v.unicode = strings.ContainsRune(flags, 'u')
+	v.src = []rune(pattern)
+	if !v.unicode {
+		// without the u flag, the pattern is interpreted as sequence of
+		// code units, so that surrogate pairs are two characters
+		units := utf16.Encode(v.src)
+		v.src = make([]rune, len(units))
+		for i, u := range units {
+			v.src[i] = rune(u)
+		}
+	}
+	v.groupNames = make(map[string]bool)
+	v.countGroups()
+	v.namedGroups = v.namedGroups || v.unicode
+
+	v.parseDisjunction()
+	if v.pos < len(v.src) {
+		switch v.src[v.pos] {
+		case ')':
+			v.fail("Unmatched ')'")
+		case ']', '}':
+			v.fail("Lone quantifier brackets")
+		}
+		v.fail("Unexpected character")
+	}
+
+	for _, name := range v.backReference {
+		if !v.groupNames[name] {
+			v.fail("Invalid named capture referenced")
+		}
+	}
+	if v.unicode && v.maxBackRef > v.groupCount {
+		v.fail("Invalid escape")
+	}
+	return nil
+}
+
+func (v *regExpValidator) fail(msg string) {
+	panic(regExpError{msg})
+}
+
+func (v *regExpValidator) peek() rune {
+	return v.peekAt(0)
+}
+
+// peekAt returns the character n characters after the current one, or -1
+// if the end of the pattern is reached.
+func (v *regExpValidator) peekAt(n int) rune {
+	if v.pos+n >= len(v.src) {
+		return -1
+	}
+	return v.src[v.pos+n]
+}
+
+func (v *regExpValidator) eat(r rune) bool {
+	if v.peek() == r {
+		v.pos++
+		return true
+	}
+	return false
+}
+
+// countGroups counts the capturing groups of the pattern and detects named
+// groups, which is required to decide whether a back reference is valid
+// before all groups have been parsed.
+func (v *regExpValidator) countGroups() {
+	inClass := false
+	for i := 0; i < len(v.src); i++ {
+		switch v.src[i] {
+		case '\\':
+			i++
+		case '[':
+			inClass = true
+		case ']':
+			inClass = false
+		case '(':
+			if inClass {
+				continue
+			}
+			if i+1 < len(v.src) && v.src[i+1] == '?' {
+				if i+2 >= len(v.src) || v.src[i+2] != '<' || i+3 < len(v.src) && (v.src[i+3] == '=' || v.src[i+3] == '!') {
+					continue
+				}
+				v.namedGroups = true
+			}
+			v.groupCount++
+		}
+	}
+}
+
+func (v *regExpValidator) parseDisjunction() {
+	v.parseAlternative()
+	for v.eat('|') {
+		v.parseAlternative()
+	}
+}
+
+func (v *regExpValidator) parseAlternative() {
+	for v.pos < len(v.src) && v.peek() != '|' && v.peek() != ')' {
+		v.parseTerm()
+	}
+}
+
+func (v *regExpValidator) parseTerm() {
+	if ok, lookahead := v.parseAssertion(); ok {
+		// lookahead assertions are quantifiable without the u flag, as
+		// specified in B.1.4
+		if lookahead && !v.unicode && v.parseQuantifier() {
+			return
+		}
+		if v.isQuantifier() {
+			v.fail("Nothing to repeat")
+		}
+		return
+	}
+
+	v.parseAtom()
+	v.parseQuantifier()
+}
+
+// parseAssertion parses an Assertion, and reports whether an assertion was
+// found and whether it is a lookahead assertion.
+func (v *regExpValidator) parseAssertion() (ok, lookahead bool) {
+	switch {
+	case v.eat('^'), v.eat('$'):
+		return true, false
+	case v.peek() == '\\' && (v.peekAt(1) == 'b' || v.peekAt(1) == 'B'):
+		v.pos += 2
+		return true, false
+	case v.peek() == '(' && v.peekAt(1) == '?':
+		lookbehind := false
+		switch {
+		case v.peekAt(2) == '=' || v.peekAt(2) == '!':
+			v.pos += 3
+		case v.peekAt(2) == '<' && (v.peekAt(3) == '=' || v.peekAt(3) == '!'):
+			lookbehind = true
+			v.pos += 4
+		default:
+			return false, false
+		}
+		v.parseDisjunction()
+		if !v.eat(')') {
+			v.fail("Unterminated group")
+		}
+		return true, !lookbehind
+	}
+	return false, false
+}
+
+// isQuantifier reports whether the pattern continues with a quantifier.
+func (v *regExpValidator) isQuantifier() bool {
+	pos := v.pos
+	defer func() { v.pos = pos }()
+	return v.parseQuantifierPrefix(true)
+}
+
+// parseQuantifier parses an optional Quantifier, and reports whether one
+// was found.
+func (v *regExpValidator) parseQuantifier() bool {
+	if !v.parseQuantifierPrefix(false) {
+		return false
+	}
+	v.eat('?')
+	return true
+}
+
+func (v *regExpValidator) parseQuantifierPrefix(noError bool) bool {
+	switch {
+	case v.eat('*'), v.eat('+'), v.eat('?'):
+		return true
+	case v.peek() == '{':
+		start := v.pos
+		v.pos++
+		min := v.parseDigits()
+		if min != "" {
+			max := min
+			if v.eat(',') {
+				max = v.parseDigits()
+			}
+			if v.eat('}') {
+				if max != "" && compareDecimals(min, max) > 0 && !noError {
+					v.fail("numbers out of order in {} quantifier")
+				}
+				return true
+			}
+		}
+		if v.unicode && !noError {
+			v.fail("Incomplete quantifier")
+		}
+		v.pos = start
+	}
+	return false
+}
+
+func (v *regExpValidator) parseDigits() string {
+	start := v.pos
+	for isDecimalDigit(v.peek()) {
+		v.pos++
+	}
+	return string(v.src[start:v.pos])
+}
+
+// compareDecimals compares two non-negative decimal integers of arbitrary
+// size, and returns -1, 0 or 1 like strings.Compare.
+func compareDecimals(a, b string) int {
+	a = strings.TrimLeft(a, "0")
+	b = strings.TrimLeft(b, "0")
+	switch {
+	case len(a) < len(b):
+		return -1
+	case len(a) > len(b):
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+func (v *regExpValidator) parseAtom() {
+	switch r := v.peek(); r {
+	case '.':
+		v.pos++
+	case '(':
+		v.parseGroup()
+	case '\\':
+		v.pos++
+		v.parseAtomEscape()
+	case '[':
+		v.parseCharacterClass()
+	case '*', '+', '?':
+		v.fail("Nothing to repeat")
+	case '{':
+		if v.unicode {
+			v.fail("Lone quantifier brackets")
+		}
+		if v.isQuantifier() {
+			v.fail("Nothing to repeat")
+		}
+		// an ExtendedPatternCharacter, as specified in B.1.4
+		v.pos++
+	case ']', '}':
+		if v.unicode {
+			v.fail("Lone quantifier brackets")
+		}
+		v.pos++
+	default:
+		v.pos++
+	}
+}
+
+func (v *regExpValidator) parseGroup() {
+	v.pos++
+	if v.eat('?') {
+		switch {
+		case v.eat(':'):
+		case v.eat('<'):
+			name := v.parseGroupName()
+			if v.groupNames[name] {
+				v.fail("Duplicate capture group name")
+			}
+			v.groupNames[name] = true
+		default:
+			v.fail("Invalid group")
+		}
+	}
+
+	v.parseDisjunction()
+	if !v.eat(')') {
+		v.fail("Unterminated group")
+	}
+}
+
+// parseGroupName parses a RegExpIdentifierName followed by '>', the opening
+// '<' already being consumed.
+func (v *regExpValidator) parseGroupName() string {
+	var name []rune
+	for {
+		r := v.peek()
+		if r == '>' && len(name) > 0 {
+			v.pos++
+			return string(name)
+		}
+		if r == -1 {
+			break
+		}
+		v.pos++
+		if r == '\\' {
+			if !v.eat('u') {
+				break
+			}
+			var ok bool
+			if r, ok = v.parseUnicodeEscape(true); !ok {
+				break
+			}
+		}
+
+		if len(name) == 0 && !isIdentifierStart(r) || !isIdentifierPart(r) {
+			break
+		}
+		name = append(name, r)
+	}
+	v.fail("Invalid capture group name")
+	return ""
+}
+
+// parseAtomEscape parses an AtomEscape, the backslash already being
+// consumed.
+func (v *regExpValidator) parseAtomEscape() {
+	r := v.peek()
+	switch {
+	case r == -1:
+		v.fail("\\ at end of pattern")
+	case r == 'k' && v.namedGroups:
+		v.pos++
+		if !v.eat('<') {
+			v.fail("Invalid named reference")
+		}
+		v.backReference = append(v.backReference, v.parseGroupName())
+		return
+	case r >= '1' && r <= '9':
+		start := v.pos
+		n := atoiSaturated(v.parseDigits())
+		if v.unicode || n <= v.groupCount {
+			if n > v.maxBackRef {
+				v.maxBackRef = n
+			}
+			return
+		}
+		// a legacy octal escape or an identity escape, as specified in
+		// B.1.4
+		v.pos = start
+	}
+
+	if v.parseCharacterClassEscape() {
+		return
+	}
+	v.parseCharacterEscape()
+}
+
+// atoiSaturated converts the given decimal digits to an int, saturating
+// at a large value instead of overflowing.
+func atoiSaturated(digits string) int {
+	const max = 1 << 30
+	n := 0
+	for _, d := range digits {
+		n = n*10 + int(d-'0')
+		if n > max {
+			return max
+		}
+	}
+	return n
+}
+
+func isControlLetter(r rune) bool {
+	return 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z'
+}
+
+// parseCharacterClassEscape parses an optional CharacterClassEscape, and
+// reports whether one was found.
+func (v *regExpValidator) parseCharacterClassEscape() bool {
+	switch v.peek() {
+	case 'd', 'D', 's', 'S', 'w', 'W':
+		v.pos++
+		return true
+	case 'p', 'P':
+		if !v.unicode {
+			return false
+		}
+		v.pos++
+		if !v.eat('{') || !v.parseUnicodePropertyValueExpression() || !v.eat('}') {
+			v.fail("Invalid property name")
+		}
+		return true
+	}
+	return false
+}
+
+// parseUnicodePropertyValueExpression parses the name and the optional
+// value of a unicode property escape.
+func (v *regExpValidator) parseUnicodePropertyValueExpression() bool {
+	isNameChar := func(r rune) bool { return isControlLetter(r) || r == '_' }
+	isValueChar := func(r rune) bool { return isNameChar(r) || isDecimalDigit(r) }
+
+	start := v.pos
+	for isValueChar(v.peek()) {
+		v.pos++
+	}
+	if v.pos == start {
+		return false
+	}
+	if !v.eat('=') {
+		return true
+	}
+	for _, r := range v.src[start : v.pos-1] {
+		if !isNameChar(r) {
+			return false
+		}
+	}
+
+	start = v.pos
+	for isValueChar(v.peek()) {
+		v.pos++
+	}
+	return v.pos > start
+}
+
+// parseCharacterEscape parses a CharacterEscape, and returns the character
+// value of the escape.
+func (v *regExpValidator) parseCharacterEscape() rune {
+	r := v.peek()
+	v.pos++
+	switch r {
+	case 'f':
+		return '\f'
+	case 'n':
+		return '\n'
+	case 'r':
+		return '\r'
+	case 't':
+		return '\t'
+	case 'v':
+		return '\v'
+	case 'c':
+		if c := v.peek(); isControlLetter(c) {
+			v.pos++
+			return c % 32
+		}
+		if v.unicode {
+			v.fail("Invalid unicode escape")
+		}
+		// the backslash is treated literally, as specified in B.1.4
+		v.pos--
+		return '\\'
+	case '0':
+		if !isDecimalDigit(v.peek()) {
+			return 0
+		}
+		if v.unicode {
+			v.fail("Invalid decimal escape")
+		}
+		return v.parseLegacyOctalEscape(r)
+	case 'x':
+		if isHexDigit(v.peek()) && isHexDigit(v.peekAt(1)) {
+			value := rune(hexValue(v.peek())<<4 | hexValue(v.peekAt(1)))
+			v.pos += 2
+			return value
+		}
+		if v.unicode {
+			v.fail("Invalid escape")
+		}
+		return 'x'
+	case 'u':
+		value, ok := v.parseUnicodeEscape(v.unicode)
+		if ok {
+			return value
+		}
+		if v.unicode {
+			v.fail("Invalid unicode escape")
+		}
+		return 'u'
+	}
+
+	if !v.unicode {
+		if isOctalDigit(r) {
+			return v.parseLegacyOctalEscape(r)
+		}
+		// an IdentityEscape, any character but 'c' and, if the pattern
+		// has named groups, 'k'
+		if r == 'k' && v.namedGroups {
+			v.fail("Invalid escape")
+		}
+		return r
+	}
+
+	if strings.ContainsRune("^$\\.*+?()[]{}|/", r) {
+		return r
+	}
+	v.fail("Invalid escape")
+	return 0
+}
+
+// parseLegacyOctalEscape parses the rest of a LegacyOctalEscapeSequence,
+// whose first digit has already been consumed.
+func (v *regExpValidator) parseLegacyOctalEscape(first rune) rune {
+	value := first - '0'
+	if isOctalDigit(v.peek()) {
+		value = value*8 + v.peek() - '0'
+		v.pos++
+		if first <= '3' && isOctalDigit(v.peek()) {
+			value = value*8 + v.peek() - '0'
+			v.pos++
+		}
+	}
+	return value
+}
+
+// parseUnicodeEscape parses a RegExpUnicodeEscapeSequence after the 'u',
+// and returns its value and whether it is valid. If unicode is true, code
+// point escapes \u{...} and surrogate pairs are allowed. If the escape is
+// not valid, the position is reset.
+func (v *regExpValidator) parseUnicodeEscape(unicode bool) (rune, bool) {
+	start := v.pos
+	if unicode && v.eat('{') {
+		value := rune(0)
+		digits := 0
+		for isHexDigit(v.peek()) {
+			value = value<<4 | rune(hexValue(v.peek()))
+			if value > 0x10FFFF {
+				v.fail("Invalid unicode escape")
+			}
+			v.pos++
+			digits++
+		}
+		if digits > 0 && v.eat('}') {
+			return value, true
+		}
+		v.pos = start
+		return 0, false
+	}
+
+	value, ok := v.parseHex4()
+	if !ok {
+		v.pos = start
+		return 0, false
+	}
+	if unicode && 0xD800 <= value && value <= 0xDBFF && v.peek() == '\\' && v.peekAt(1) == 'u' {
+		lead := v.pos
+		v.pos += 2
+		if trail, ok := v.parseHex4(); ok && 0xDC00 <= trail && trail <= 0xDFFF {
+			return (value-0xD800)*0x400 + trail - 0xDC00 + 0x10000, true
+		}
+		v.pos = lead
+	}
+	return value, true
+}
+
+func (v *regExpValidator) parseHex4() (rune, bool) {
+	value := rune(0)
+	for i := 0; i < 4; i++ {
+		if !isHexDigit(v.peekAt(i)) {
+			return 0, false
+		}
+		value = value<<4 | rune(hexValue(v.peekAt(i)))
+	}
+	v.pos += 4
+	return value, true
+}
+
+func (v *regExpValidator) parseCharacterClass() {
+	v.pos++
+	v.eat('^')
+	for !v.eat(']') {
+		if v.pos >= len(v.src) {
+			v.fail("Unterminated character class")
+		}
+
+		from, fromIsClass := v.parseClassAtom()
+		if v.peek() != '-' || v.peekAt(1) == ']' || v.peekAt(1) == -1 {
+			continue
+		}
+		v.pos++
+		to, toIsClass := v.parseClassAtom()
+		if fromIsClass || toIsClass {
+			if v.unicode {
+				v.fail("Invalid character class")
+			}
+			continue
+		}
+		if from > to {
+			v.fail("Range out of order in character class")
+		}
+	}
+}
+
+// parseClassAtom parses a ClassAtom, and returns its character value and
+// whether it is a character class escape, which has no character value.
+func (v *regExpValidator) parseClassAtom() (rune, bool) {
+	r := v.peek()
+	v.pos++
+	if r != '\\' {
+		return r, false
+	}
+
+	switch c := v.peek(); {
+	case c == -1:
+		v.fail("\\ at end of pattern")
+	case c == 'b':
+		v.pos++
+		return '\b', false
+	case c == '-' && v.unicode:
+		v.pos++
+		return '-', false
+	case c == 'c' && !v.unicode && (isDecimalDigit(v.peekAt(1)) || v.peekAt(1) == '_'):
+		// a ClassControlLetter, as specified in B.1.4
+		v.pos += 2
+		return v.src[v.pos-1] % 32, false
+	case isDecimalDigit(c) && c != '0':
+		if v.unicode {
+			v.fail("Invalid class escape")
+		}
+	}
+
+	if v.parseCharacterClassEscape() {
+		return 0, true
+	}
+	return v.parseCharacterEscape(), false
+}
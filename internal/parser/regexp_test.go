@@ -0,0 +1,97 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateRegExp(t *testing.T) {
+	tests := []struct {
+		pattern string
+		flags   string
+		valid   bool
+	}{
+		{`abc`, "", true},
+		{`a|b|`, "", true},
+		{`a*b+c?d{2}e{2,}f{2,3}g*?`, "", true},
+		{`^a$\b\B`, "", true},
+		{`(a)(?:b)(?=c)(?!d)(?<=e)(?<!f)`, "", true},
+		{`(?<name>a)\k<name>`, "", true},
+		{`(?<ab>.)`, "", true},
+		{`[a-z\d\-\]]`, "", true},
+		{`[^]`, "", true},
+		{`\0\f\n\r\t\v\cA\x41A`, "", true},
+		{`\u{1F600}😀`, "u", true},
+		{`[😀-😁]`, "u", true},
+		{`\p{L}\P{Script=Latin}`, "u", true},
+		{`(a)\1`, "u", true},
+
+		// extended syntax without the u flag, as specified in B.1.4
+		{`\1`, "", true},
+		{`\8`, "", true},
+		{`{`, "", true},
+		{`a{,5}`, "", true},
+		{`]`, "", true},
+		{`}`, "", true},
+		{`\c`, "", true},
+		{`[\c_]`, "", true},
+		{`\a`, "", true},
+		{`\k`, "", true},
+		{`\x`, "", true},
+		{`\u{41}`, "", true},
+		{`[\d-a]`, "", true},
+		{`(?=a)*`, "", true},
+		{`\p{L}`, "", true},
+
+		{`(`, "", false},
+		{`)`, "", false},
+		{`[`, "", false},
+		{`\`, "", false},
+		{`*`, "", false},
+		{`a**`, "", false},
+		{`a|*`, "", false},
+		{`^*`, "", false},
+		{`(?<=a)*`, "", false},
+		{`{1}`, "", false},
+		{`a{2,1}`, "", false},
+		{`[b-a]`, "", false},
+		{`[😀-😁]`, "", false},
+		{`(?a)`, "", false},
+		{`(?<a>a)(?<a>b)`, "", false},
+		{`(?<1>a)`, "", false},
+		{`(?<a>a)\k<b>`, "", false},
+		{`(?<a>a)\k`, "", false},
+
+		// restricted syntax with the u flag
+		{`{`, "u", false},
+		{`]`, "u", false},
+		{`a{,5}`, "u", false},
+		{`\1`, "u", false},
+		{`\00`, "u", false},
+		{`\8`, "u", false},
+		{`\a`, "u", false},
+		{`\-`, "u", false},
+		{`\c`, "u", false},
+		{`\x4`, "u", false},
+		{`\u{110000}`, "u", false},
+		{`\k`, "u", false},
+		{`[\d-a]`, "u", false},
+		{`(?=a)*`, "u", false},
+		{`\p`, "u", false},
+		{`\p{=L}`, "u", false},
+	}
+
+	for _, test := range tests {
+		t.Run("/"+test.pattern+"/"+test.flags, func(t *testing.T) {
+			require := require.New(t)
+
+			err := validateRegExp(test.pattern, test.flags)
+			if test.valid {
+				require.NoError(err)
+			} else {
+				require.Error(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,311 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/gojisvm/gojis/internal/parser/ast"
+)
+
+// maxNestingDepth is the maximum depth of nested statements, expressions and
+// patterns, where chains of binary operations, member accesses and calls
+// count as nested expressions. Deeper nesting is reported as syntax error, so
+// that the recursion of the parser, and of everything that walks the tree, is
+// bounded for any input.
+const maxNestingDepth = 1000
+
+// bailout is used as panic value to abort parsing after a syntax error has
+// been reported.
+type bailout struct{}
+
+// functionContext describes the function that the parser is currently in.
+type functionContext struct {
+	// function is false at the top level of a script, where return
+	// statements are not allowed.
+	function bool
+	// generator is true in generator functions, where yield is an operator.
+	generator bool
+	// async is true in async functions, where await is an operator.
+	async bool
+}
+
+// destructuringErrors records errors that are only errors if an expression
+// that has been parsed with the cover grammar turns out not to be a pattern.
+type destructuringErrors struct {
+	// shorthandAssign is the position of a CoverInitializedName, e.g.
+	// {a = 1}, which is only allowed in patterns.
+	shorthandAssign ast.Position
+}
+
+// sourceParser is a recursive descent parser for a single source. It is
+// created for every source that is parsed, and must not be reused.
+type sourceParser struct {
+	name     string
+	lexer    lexer
+	listener ErrorListener
+
+	// tok is the current token.
+	tok token
+	// prevEnd is the end position of the previous token.
+	prevEnd ast.Position
+
+	strict bool
+	fn     functionContext
+	// module is true if the source is parsed as module, where await is a
+	// reserved word.
+	module bool
+
+	// potentialArrowAt is the offset at which the current assignment
+	// expression started, which is the only offset at which an arrow
+	// function may start.
+	potentialArrowAt int
+	// yieldPos, awaitPos and awaitIdentPos are the positions of the first
+	// yield expression, await expression and await identifier encountered
+	// since they were last reset. They are used to detect yield and await in
+	// arrow parameters, which are only known to be parameters after they
+	// have been parsed.
+	yieldPos      ast.Position
+	awaitPos      ast.Position
+	awaitIdentPos ast.Position
+
+	// parenthesized holds all expressions that were wrapped in parentheses.
+	// Parenthesized expressions cannot be converted to patterns.
+	parenthesized map[ast.Expression]bool
+	// spreadTrailingComma holds all array literals whose last element is a
+	// spread element followed by a comma. Such literals cannot be converted
+	// to patterns.
+	spreadTrailingComma map[*ast.ArrayExpression]bool
+
+	// recovery enables the recovery mode, in which the parser continues with
+	// the next statement after a syntax error.
+	recovery bool
+	// skipping is true while the parser skips invalid input in recovery
+	// mode.
+	skipping bool
+	// errorCount is the number of reported syntax errors, and lastErrorPos
+	// is the position of the last one.
+	errorCount   int
+	lastErrorPos ast.Position
+
+	// depth is the current nesting depth, see enter.
+	depth int
+}
+
+// newSourceParser creates a parser for the given source code. All syntax
+// errors are reported to the given listener. If recovery is true, the parser
+// runs in recovery mode, see parseScript.
+func newSourceParser(name, src string, listener ErrorListener, recovery bool) *sourceParser {
+	p := new(sourceParser)
+	p.name = name
+	p.listener = listener
+	p.recovery = recovery
+	p.parenthesized = make(map[ast.Expression]bool)
+	p.spreadTrailingComma = make(map[*ast.ArrayExpression]bool)
+	p.lexer = newLexer(src, false, p.lexerError)
+	p.potentialArrowAt = -1
+	return p
+}
+
+// parseScript parses the source as a Script. If a syntax error occurs, it is
+// reported to the listener and nil is returned. In recovery mode, the parser
+// skips to the next statement after a syntax error and continues, so that
+// all syntax errors are reported. The returned program then contains all
+// statements that could be parsed.
+func (p *sourceParser) parseScript() (program *ast.Program) {
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(bailout); !ok {
+				panic(r)
+			}
+			program = nil
+		}
+	}()
+
+	p.next()
+
+	start := p.tok.start
+	body := p.parseDirectivesAndStatements(func() bool { return p.tok.kind == tokenEOF })
+	return &ast.Program{
+		Location:   p.loc(start),
+		SourceType: ast.SourceTypeScript,
+		Body:       body,
+		Strict:     p.strict,
+	}
+}
+
+// lexerError reports an error of the lexer. In recovery mode, the parser
+// continues with the token produced by the lexer, and the error is reported
+// even while skipping invalid input, since it is not caused by a previous
+// error.
+func (p *sourceParser) lexerError(pos ast.Position, msg string) {
+	if !p.recovery {
+		p.errorAt(pos, msg)
+	}
+	p.emit(pos, "", msg)
+}
+
+// errorAt reports a syntax error at the given position and aborts parsing.
+func (p *sourceParser) errorAt(pos ast.Position, format string, args ...interface{}) {
+	p.report(pos, "", fmt.Sprintf(format, args...))
+	panic(bailout{})
+}
+
+// report reports a syntax error to the listener. In recovery mode, errors
+// that occur while skipping invalid input are not reported, since they are
+// most likely caused by the error that is being recovered from.
+func (p *sourceParser) report(pos ast.Position, expected, msg string) {
+	if p.skipping {
+		return
+	}
+	p.emit(pos, expected, msg)
+}
+
+// emit sends a syntax error to the listener, unless there already was an
+// error at the same position.
+func (p *sourceParser) emit(pos ast.Position, expected, msg string) {
+	if p.errorCount > 0 && pos == p.lastErrorPos {
+		return
+	}
+	p.errorCount++
+	p.lastErrorPos = pos
+
+	p.listener.SyntaxError(&Error{
+		File:     p.name,
+		Pos:      pos,
+		Token:    tokenText(p.lexer.src, pos),
+		Expected: expected,
+		Message:  msg,
+	})
+}
+
+// unexpected reports the current token as unexpected. The given hint
+// describes what was expected instead, it may be empty.
+func (p *sourceParser) unexpected(expected string) {
+	p.unexpectedToken(p.tok, expected)
+}
+
+func (p *sourceParser) unexpectedToken(t token, expected string) {
+	msg := fmt.Sprintf("Unexpected token %v", t)
+	if t.kind == tokenEOF {
+		msg = "Unexpected end of input"
+	}
+	p.report(t.start, expected, msg)
+	panic(bailout{})
+}
+
+// enter increments the nesting depth, and reports a syntax error if it
+// exceeds maxNestingDepth. It must be called before the parser recurses
+// into a nested statement, expression or pattern, and leave must be called
+// when it returns.
+func (p *sourceParser) enter() {
+	p.depth++
+	if p.depth > maxNestingDepth {
+		p.errorAt(p.tok.start, "Maximum nesting depth of %v exceeded", maxNestingDepth)
+	}
+}
+
+// leave decrements the nesting depth that has been incremented by enter.
+func (p *sourceParser) leave() {
+	p.depth--
+}
+
+// loc returns a location from the given start position to the end of the
+// previous token.
+func (p *sourceParser) loc(start ast.Position) ast.Location {
+	return ast.Location{
+		Start: start,
+		End:   p.prevEnd,
+	}
+}
+
+// next advances to the next token.
+func (p *sourceParser) next() {
+	p.prevEnd = p.tok.end
+	p.tok = p.lexer.next()
+}
+
+// peek returns the token after the current token, without advancing. This
+// must not be used if the next token could be a regular expression or a
+// template continuation.
+func (p *sourceParser) peek() token {
+	saved := p.lexer
+	t := p.lexer.next()
+	p.lexer = saved
+	return t
+}
+
+// is reports whether the current token is the given punctuator.
+func (p *sourceParser) is(punctuator string) bool {
+	return p.tok.kind == tokenPunctuator && p.tok.value == punctuator
+}
+
+// isName reports whether the current token is the given keyword or
+// contextual keyword, written without escape sequences.
+func (p *sourceParser) isName(name string) bool {
+	return isName(p.tok, name)
+}
+
+func isName(t token, name string) bool {
+	return t.kind == tokenName && t.value == name && !t.escaped
+}
+
+// eat advances to the next token and returns true if the current token is the
+// given punctuator, and returns false otherwise.
+func (p *sourceParser) eat(punctuator string) bool {
+	if p.is(punctuator) {
+		p.next()
+		return true
+	}
+	return false
+}
+
+// eatName is like eat, but for keywords and contextual keywords.
+func (p *sourceParser) eatName(name string) bool {
+	if p.isName(name) {
+		p.next()
+		return true
+	}
+	return false
+}
+
+// expect consumes the given punctuator, or reports an error if the current
+// token is a different token.
+func (p *sourceParser) expect(punctuator string) {
+	if !p.eat(punctuator) {
+		p.unexpected("'" + punctuator + "'")
+	}
+}
+
+// expectName is like expect, but for keywords and contextual keywords.
+func (p *sourceParser) expectName(name string) {
+	if !p.eatName(name) {
+		p.unexpected("'" + name + "'")
+	}
+}
+
+// canInsertSemicolon reports whether a semicolon can automatically be
+// inserted before the current token, as specified in 11.9.1.
+func (p *sourceParser) canInsertSemicolon() bool {
+	return p.tok.kind == tokenEOF || p.is("}") || p.tok.newlineBefore
+}
+
+// semicolon consumes a semicolon, which may be inserted automatically.
+func (p *sourceParser) semicolon() {
+	if !p.eat(";") && !p.canInsertSemicolon() {
+		p.unexpected("';'")
+	}
+}
+
+// withFunction runs the given function with the given function context.
+// The positions of yield and await expressions are reset, so that they only
+// describe the new function.
+func (p *sourceParser) withFunction(ctx functionContext, f func()) {
+	oldFn, oldStrict := p.fn, p.strict
+	oldYield, oldAwait, oldAwaitIdent := p.yieldPos, p.awaitPos, p.awaitIdentPos
+	p.fn = ctx
+	p.yieldPos, p.awaitPos, p.awaitIdentPos = ast.Position{}, ast.Position{}, ast.Position{}
+
+	f()
+
+	p.fn, p.strict = oldFn, oldStrict
+	p.yieldPos, p.awaitPos, p.awaitIdentPos = oldYield, oldAwait, oldAwaitIdent
+}
@@ -0,0 +1,283 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseValid(t *testing.T) {
+	tests := []string{
+		``,
+		`;`,
+		`var a = 1, b, c = a + b;`,
+		`let a = 1; const b = 2;`,
+		`let
+		a = 1`,
+		`var let = 1; let = 2; let.x; a = let[0];`,
+		`if (a) b; else c`,
+		`if (a) function f() {}`,
+		`l: function f() {}`,
+		`a: b: c: while (true) { break a; continue c }`,
+		`for (;;) {}`,
+		`for (var i = 0; i < 10; i++) {}`,
+		`for (let i = 0, j; i < 10; i++) {}`,
+		`for (x in y);`,
+		`for (var x in y);`,
+		`for (var x = 1 in y);`,
+		`for (let x of y);`,
+		`for (const [a, b] of y);`,
+		`for ([a, b] of c);`,
+		`for ({a, b} in c);`,
+		`for (a.b of c);`,
+		`for (let in x);`,
+		`for (let.x in y);`,
+		`for (async of => {};;);`,
+		`for ((x) in y);`,
+		`while (a) b()`,
+		`do a(); while (b) c()`,
+		`do ; while (0) 0`,
+		`switch (a) { case 1: b; case 2: default: c; case 3: }`,
+		`try {} catch (e) {} finally {}`,
+		`try {} catch ({a, b: [c]}) {}`,
+		`try {} catch {}`,
+		`throw new Error("x")`,
+		`function f() { return }`,
+		`function f() { return
+		a }`,
+		`with (a) b`,
+		`debugger;`,
+		`a
+		++b`,
+		`a = b
+		(c)`,
+		`"use strict"; 'another directive'`,
+		`function f(a, b = 1, ...c) {}`,
+		`function* g() { yield; yield 1; yield* g(); yield
+		a }`,
+		`function* g() { var x = yield; }`,
+		`function g() { var yield = 1; }`,
+		`async function f() { await x; await (async () => 1)(); for await (x of y); }`,
+		`var async; async = 1; async(1); async
+		function f() {}`,
+		`var await = 1; await(1);`,
+		`a => a`,
+		`(a, b) => a + b`,
+		`() => {}`,
+		`(a = 1, {b, c: [d]}, ...e) => 0`,
+		`([a, , b], {c = 1}) => 0`,
+		`async a => a`,
+		`async (a, b) => a`,
+		`async () => { await a }`,
+		`(a, b,) => 0`,
+		`f(a, b,)`,
+		`x = a => b => c`,
+		`x ? y => 1 : z => 2`,
+		`({a, b: c, [d]: e, "f": g, 1: h, get i() {}, set j(v) {}, k() {}, *l() {}, async m() {}, async *n() {}, get: 1, set: 2, async: 3, ...o})`,
+		`({if: 1, class: 2, get if() {}})`,
+		`[a, , b, ...c]`,
+		`[, , ]`,
+		`[a, b] = [b, a]`,
+		`({a, b = 1, c: {d}, ...e} = f)`,
+		`[a.b, c[d], ...e.f] = g`,
+		`[(a), (b.c)] = d`,
+		`({a: (b)} = c)`,
+		`(a) = 1; (a.b) = 2; (a) += 1`,
+		`a = b = c`,
+		`a += 1; a -= 1; a **= 2; a >>>= 1`,
+		`a || b && c | d ^ e & f == g != h === i !== j < k > l <= m >= n instanceof o in p << q >> r >>> s + t - u * v / w % x ** y`,
+		`2 ** 3 ** 2; (-2) ** 2; (++a) ** 2; a++ ** 2`,
+		`!a; ~a; +a; -a; typeof a; void a; delete a.b; ++a; --a; a++; a--`,
+		`a ? b : c ? d : e`,
+		`a, b, c`,
+		"new a; new a(); new a.b.c(); new (a()); new new a()(); new a.b``",
+		`function f() { new.target }`,
+		"a.b.c; a[b][c]; a(); a()(); a.b(); a`x`; a.if; a.class",
+		`/a/g; /[/]/; /a\/b/; x = /=/; a / b / c; a /= 2`,
+		"`a${b}c${d}e`; `\\n`; tag`\\unicode`",
+		"`${`${a}`}`",
+		"`${ {} }`",
+		`class A {}`,
+		`class A extends B { constructor() { super(); } static m() { super.m(); } get x() {} set x(v) {} *g() {} async a() {} static static() {} static() {} ;; }`,
+		`(class {}); (class A extends (B, C) {})`,
+		`class A { "constructor"() {} ['constructor']() {} }`,
+		`0; 1.5; .5; 5.; 1e10; 1E-5; 0x1F; 0o17; 0b101; 017; 019; 08.5`,
+		`'a'; "b"; '\x41A\u{41}\n\0\
+		'`,
+		`'\251'`,
+		`abc; \u{61}`,
+		`<!-- html comment
+		a --> b
+		--> comment`,
+		`/* multi
+		line */ --> comment`,
+		`a = function b() {}; a = function* () {}; a = async function () {}`,
+		`var async; async (x) => x`,
+		`yield: 1`,
+		`function* g() { (function yield() {}); }`,
+		`a = { get() {}, set() {}, async() {}, static() {} }`,
+		`label: { break label; }`,
+		`function f(a,) {}`,
+		`({ __proto__: a, __proto__: b } = c)`,
+		`var {a} = b, [c] = d;`,
+		`x = y => ({})`,
+		`(a, b)`,
+		`((a))`,
+		`if (a) { } else if (b) { } else { }`,
+		"a b",
+		`/[\]/]/`,
+		`async function f() { async () => await x }`,
+		`async function* g() { yield await x; for await (const y of z); }`,
+		`({ async *[Symbol.iterator]() {} })`,
+	}
+
+	for _, src := range tests {
+		t.Run(src, func(t *testing.T) {
+			require := require.New(t)
+
+			p := New()
+			require.NoError(p.ParseString("test.js", src))
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []string{
+		`var`,
+		`var = 1`,
+		`var a b`,
+		`a b`,
+		`const a;`,
+		`var [a];`,
+		`let [a];`,
+		`if (a) let [b] = c`,
+		`if (a) const b = 1`,
+		`if (a) class A {}`,
+		`while (a) function f() {}`,
+		`if (a) function* g() {}`,
+		`if (a) async function f() {}`,
+		`"use strict"; if (a) function f() {}`,
+		`for (let x = 1 of y);`,
+		`for (let x = 1 in y);`,
+		`for (var [x] = 1 in y);`,
+		`for (let of y);`,
+		`for (async of x);`,
+		`for (a + b in c);`,
+		`for (a = b of c);`,
+		`for await (x of y);`,
+		`return`,
+		`throw
+		a`,
+		`switch (a) { default: default: }`,
+		`try {}`,
+		`a++
+		++`,
+		`a ++ ++`,
+		`++a++`,
+		`1 = a`,
+		`a() = 1`,
+		`a + b = c`,
+		`(a + b) = c`,
+		`({a}) = b`,
+		`([a]) = b`,
+		`[...a, b] = c`,
+		`[...a,] = c`,
+		`({...a, b} = c)`,
+		`({...{a}} = c)`,
+		`({a = 1})`,
+		`({a = 1}).b`,
+		`[{a = 1}]`,
+		`({get a() {}} = b)`,
+		`({a() {}} = b)`,
+		`(a.b) => 1`,
+		`((a)) => 1`,
+		`(a, ...b,) => 1`,
+		`(...a = 1) => 1`,
+		`(a
+		)
+		=> 1`,
+		`a
+		=> 1`,
+		`async a
+		=> 1`,
+		`()`,
+		`(a,)`,
+		`(...a)`,
+		`-a ** 2`,
+		`typeof a ** 2`,
+		`2 ** -a ** 2`,
+		`async function f() { await a ** 2 }`,
+		`function* g() { (a = yield) => 1 }`,
+		`async function f() { (a = await b) => 1 }`,
+		`async (await) => 1`,
+		`async (a = await) => 1`,
+		`async await => 1`,
+		`function* g() { var yield; }`,
+		`async function f() { var await; }`,
+		`function* g() { a + yield }`,
+		`(function* yield() {})`,
+		`(async function await() {})`,
+		`var if = 1`,
+		`var a = {if}`,
+		`({a, b: 1} = c)`,
+		`new.foo`,
+		`new super()`,
+		`super`,
+		`({get a(b) {}})`,
+		`({set a() {}})`,
+		`({set a(...b) {}})`,
+		`({*a: 1})`,
+		`({async a: 1})`,
+		`class A extends B, C {}`,
+		`class {}`,
+		`class A { x = 1 }`,
+		`'abc`,
+		`'a
+		b'`,
+		"`abc",
+		"`\\u{110000}`",
+		"`\\01`",
+		`"\u"`,
+		`"\x4"`,
+		`/a`,
+		`/a
+		/`,
+		`/a/gg`,
+		`/a/x`,
+		`0x`,
+		`1e`,
+		`3in []`,
+		`0b12`,
+		`/* unterminated`,
+		`a @ b`,
+		`import a from "b"`,
+		`export var a`,
+		`({a: 1} = b)`,
+		`[a + 1] = b`,
+		`function f(...a, b) {}`,
+		`function f(...a,) {}`,
+		`function f(a,,) {}`,
+		`f(,)`,
+		`a ? b`,
+		`{`,
+		`}`,
+		`if`,
+		`.5e`,
+		`x = ;`,
+		`for (x => x in y;;);`,
+		`({ async
+		x() {} })`,
+		`let[0] = 1`,
+	}
+
+	for _, src := range tests {
+		t.Run(src, func(t *testing.T) {
+			require := require.New(t)
+
+			p := New()
+			err := p.ParseString("test.js", src)
+			require.Error(err)
+			require.IsType(ParserError{}, err)
+		})
+	}
+}
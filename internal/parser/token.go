@@ -0,0 +1,249 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/gojisvm/gojis/internal/parser/ast"
+)
+
+// tokenKind is the kind of a token produced by the lexer.
+type tokenKind uint8
+
+// Available token kinds. Keywords and reserved words are not distinguished
+// from identifiers by the lexer, they all are of kind tokenName.
+const (
+	tokenEOF tokenKind = iota
+	tokenName
+	tokenPunctuator
+	tokenNumber
+	tokenString
+	tokenTemplate
+	tokenRegExp
+)
+
+func (k tokenKind) String() string {
+	switch k {
+	case tokenEOF:
+		return "end of input"
+	case tokenName:
+		return "identifier"
+	case tokenPunctuator:
+		return "punctuator"
+	case tokenNumber:
+		return "number"
+	case tokenString:
+		return "string"
+	case tokenTemplate:
+		return "template"
+	case tokenRegExp:
+		return "regular expression"
+	default:
+		return "unknown"
+	}
+}
+
+// token is a single token produced by the lexer.
+type token struct {
+	kind tokenKind
+	// value is the name of an identifier (with all escape sequences resolved),
+	// the punctuator, or the cooked value of a string literal or a template.
+	value string
+	// raw is the token as it appears in the source code.
+	raw string
+	// number is the value of a numeric literal.
+	number float64
+
+	start ast.Position
+	end   ast.Position
+
+	// newlineBefore is true if there was a line terminator between this
+	// token and the previous token.
+	newlineBefore bool
+	// escaped is true if an identifier contained unicode escape sequences.
+	escaped bool
+	// octal is true if a numeric literal is a legacy octal literal, or if a
+	// string literal contains a legacy octal escape sequence.
+	octal bool
+
+	// invalidEscape is true if a template contains an invalid escape sequence.
+	// This is only allowed in tagged templates, and the cooked value of such a
+	// template is undefined.
+	invalidEscape bool
+	// tail is true if a template is the last part of a template literal.
+	tail bool
+
+	// pattern and flags of a regular expression literal
+	pattern string
+	flags   string
+}
+
+// String returns a description of the token for use in error messages.
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of input"
+	case tokenName, tokenPunctuator:
+		return fmt.Sprintf("'%v'", t.raw)
+	default:
+		return fmt.Sprintf("%v %v", t.kind, t.raw)
+	}
+}
+
+// keywords are the reserved words that can never be used as identifiers.
+var keywords = map[string]bool{
+	"break":      true,
+	"case":       true,
+	"catch":      true,
+	"class":      true,
+	"const":      true,
+	"continue":   true,
+	"debugger":   true,
+	"default":    true,
+	"delete":     true,
+	"do":         true,
+	"else":       true,
+	"enum":       true,
+	"export":     true,
+	"extends":    true,
+	"false":      true,
+	"finally":    true,
+	"for":        true,
+	"function":   true,
+	"if":         true,
+	"import":     true,
+	"in":         true,
+	"instanceof": true,
+	"new":        true,
+	"null":       true,
+	"return":     true,
+	"super":      true,
+	"switch":     true,
+	"this":       true,
+	"throw":      true,
+	"true":       true,
+	"try":        true,
+	"typeof":     true,
+	"var":        true,
+	"void":       true,
+	"while":      true,
+	"with":       true,
+}
+
+// strictReservedWords are the words that are reserved in strict mode code
+// only.
+var strictReservedWords = map[string]bool{
+	"implements": true,
+	"interface":  true,
+	"let":        true,
+	"package":    true,
+	"private":    true,
+	"protected":  true,
+	"public":     true,
+	"static":     true,
+	"yield":      true,
+}
+
+// punctuators contains all punctuators. The longest punctuator has a length
+// of maxPunctuatorLength.
+var punctuators = map[string]bool{
+	">>>=": true,
+	"...":  true,
+	"===":  true,
+	"!==":  true,
+	"**=":  true,
+	"<<=":  true,
+	">>=":  true,
+	">>>":  true,
+	"=>":   true,
+	"==":   true,
+	"!=":   true,
+	"<=":   true,
+	">=":   true,
+	"&&":   true,
+	"||":   true,
+	"++":   true,
+	"--":   true,
+	"+=":   true,
+	"-=":   true,
+	"*=":   true,
+	"/=":   true,
+	"%=":   true,
+	"&=":   true,
+	"|=":   true,
+	"^=":   true,
+	"<<":   true,
+	">>":   true,
+	"**":   true,
+	"{":    true,
+	"}":    true,
+	"(":    true,
+	")":    true,
+	"[":    true,
+	"]":    true,
+	".":    true,
+	";":    true,
+	",":    true,
+	"<":    true,
+	">":    true,
+	"+":    true,
+	"-":    true,
+	"*":    true,
+	"%":    true,
+	"&":    true,
+	"|":    true,
+	"^":    true,
+	"!":    true,
+	"~":    true,
+	"?":    true,
+	":":    true,
+	"=":    true,
+	"/":    true,
+}
+
+const maxPunctuatorLength = 4
+
+// assignmentOperators are all operators of an AssignmentExpression.
+var assignmentOperators = map[string]bool{
+	"=":    true,
+	"+=":   true,
+	"-=":   true,
+	"*=":   true,
+	"/=":   true,
+	"%=":   true,
+	"**=":  true,
+	"<<=":  true,
+	">>=":  true,
+	">>>=": true,
+	"&=":   true,
+	"|=":   true,
+	"^=":   true,
+}
+
+// binaryPrecedence holds the precedence of all binary operators. Higher
+// numbers bind tighter.
+var binaryPrecedence = map[string]int{
+	"||":         1,
+	"&&":         2,
+	"|":          3,
+	"^":          4,
+	"&":          5,
+	"==":         6,
+	"!=":         6,
+	"===":        6,
+	"!==":        6,
+	"<":          7,
+	">":          7,
+	"<=":         7,
+	">=":         7,
+	"instanceof": 7,
+	"in":         7,
+	"<<":         8,
+	">>":         8,
+	">>>":        8,
+	"+":          9,
+	"-":          9,
+	"*":          10,
+	"/":          10,
+	"%":          10,
+	"**":         11,
+}
@@ -58,6 +58,12 @@ type Agent struct {
 
 	ScriptJobs  *job.Queue
 	PromiseJobs *job.Queue
+
+	// HostReportErrors reports the error of a job that completed abruptly
+	// in RunJobs. It is set by the host that runs the jobs, errors are
+	// ignored if it is nil.
+	// HostReportErrors is specified in 16.1.
+	HostReportErrors func(err errors.Error)
 }
 
 // New creates a new agent that is ready to use.
@@ -91,26 +97,30 @@ func (a *Agent) RunningExecutionContext() *ExecutionContext {
 // GetActiveScriptOrModule returns the active script or module.
 // GetActiveScriptOrModule is specified in 8.3.1.
 func (a *Agent) GetActiveScriptOrModule() lang.InternalValue {
-	if a.ExecutionContextStack.IsEmpty() {
-		return lang.Null
-	}
-
-	panic("#40: 8.3.1")
+	var scriptOrModule lang.InternalValue = lang.Null
+	a.ExecutionContextStack.Range(func(ctx *ExecutionContext) bool {
+		if ctx.ScriptOrModule != nil && ctx.ScriptOrModule != lang.Null {
+			scriptOrModule = ctx.ScriptOrModule
+			return false
+		}
+		return true
+	})
+	return scriptOrModule
 }
 
 // ResolveBinding is used to determine the binding with the given name. The
 // optional argument env can be used to explicitly provide the Lexical
-// Environment that is to be searched for the binding. During execution of
-// ECMAScript code, ResolveBinding is performed using the following algorithm.
+// Environment that is to be searched for the binding. If env is nil, the
+// LexicalEnvironment of the running execution context is used. strict must
+// be true if the code matching the syntactic production that is being
+// evaluated is contained in strict mode code, since only the evaluator
+// knows that.
 // ResolveBinding is specified in 8.3.2.
-func (a *Agent) ResolveBinding(name lang.String, env binding.Environment) *binding.Reference {
+func (a *Agent) ResolveBinding(name lang.String, env binding.Environment, strict bool) (*binding.Reference, errors.Error) {
 	if env == nil {
 		env = a.RunningExecutionContext().LexicalEnvironment
 	}
 
-	strict := false // FIXME: 8.3.2, Step 3
-	panic("#38: 8.3.2, Step 3")
-
 	return binding.GetIdentifierReference(env, name, strict)
 }
 
@@ -148,7 +158,14 @@ func (a *Agent) ResolveThisBinding() (lang.Value, errors.Error) {
 // running execution context.
 // GetNewTarget is specified in 8.3.5.
 func (a *Agent) GetNewTarget() lang.Value {
-	panic("#8: 8.3.5")
+	envRec, ok := a.GetThisEnvironment().(*binding.FunctionEnvironment)
+	if !ok {
+		// new.target is only allowed in functions, so this is the global
+		// environment of eval code
+		return lang.Undefined
+	}
+
+	return envRec.NewTarget
 }
 
 // GetGlobalObject returns the global object used by the running execution context.
@@ -158,19 +175,19 @@ func (a *Agent) GetGlobalObject() lang.Value {
 }
 
 // EnqueueJob enqueues a new job into a given kind of queue, script or promise.
+// The job is run by RunJobs, with the given arguments.
 // EnqueueJob is specified in 8.4.1.
-func (a *Agent) EnqueueJob(q QueueKind, jobName string, arguments []lang.Value) {
+func (a *Agent) EnqueueJob(q QueueKind, j job.Func, arguments []lang.Value) {
 	callerCtx := a.RunningExecutionContext()
 	callerRealm := callerCtx.Realm
 	callerScriptOrModule := callerCtx.ScriptOrModule
 	pending := job.PendingJob{
-		Job:            jobName,
+		Job:            j,
 		Arguments:      arguments,
 		Realm:          callerRealm,
 		ScriptOrModule: callerScriptOrModule,
 		HostDefined:    lang.Undefined,
 	}
-	// do we need to modify pending in any way?
 
 	switch q {
 	case QueueScript:
@@ -185,12 +202,14 @@ func (a *Agent) EnqueueJob(q QueueKind, jobName string, arguments []lang.Value)
 // InitializeHostDefinedRealm is specified in 8.5.
 func (a *Agent) InitializeHostDefinedRealm() {
 	r := realm.CreateRealm()
+	r.HostEnqueuePromiseJob = a.enqueuePromiseJob
+	r.CallStack = a.ExecutionContextStack
 	newCtx := &ExecutionContext{
 		Function:       lang.Null,
 		Realm:          r,
 		ScriptOrModule: lang.Null,
 	}
-	a.ExecutionContextStack.Push(newCtx)
+	_ = a.ExecutionContextStack.Push(newCtx) // the stack is empty
 
 	/*
 		If the host requires use of an exotic object to serve as realm's global object, let global be such an object created in
@@ -215,9 +234,62 @@ func (a *Agent) InitializeHostDefinedRealm() {
 	_ = globalObj
 }
 
+// enqueuePromiseJob enqueues a job into the PromiseJobs queue, that calls
+// the given function of a realm with the given arguments.
+func (a *Agent) enqueuePromiseJob(fn realm.BuiltinFunction, args []lang.Value) {
+	a.EnqueueJob(QueuePromise, func(args ...lang.Value) errors.Error {
+		_, err := fn(lang.Undefined, args...)
+		return err
+	}, args)
+}
+
+// RunJobs runs the pending jobs of the agent, until both of its queues are
+// empty. Every script job is followed by all promise jobs, including the
+// ones that are enqueued while the promise jobs run, so that the host can
+// enqueue script jobs and call RunJobs again whenever it has new work.
+// The realm of the agent must have been initialized with
+// InitializeHostDefinedRealm. Errors of jobs that complete abruptly are
+// passed to HostReportErrors.
 // RunJobs is specified in 8.6.
 func (a *Agent) RunJobs() {
-	a.InitializeHostDefinedRealm()
+	a.runQueue(a.PromiseJobs)
+	for {
+		pending, ok := a.ScriptJobs.TryDequeue()
+		if !ok {
+			return
+		}
+		a.runJob(pending)
+		a.runQueue(a.PromiseJobs)
+	}
+}
+
+// runQueue runs the jobs of the given queue until it is empty.
+func (a *Agent) runQueue(q *job.Queue) {
+	for {
+		pending, ok := q.TryDequeue()
+		if !ok {
+			return
+		}
+		a.runJob(pending)
+	}
+}
 
-	panic("#9: 8.6")
+// runJob runs the given job in a new execution context, and reports the
+// error if the job completes abruptly. The execution context of the job
+// is pushed on top of the execution context of the realm instead of
+// replacing it, so that the realm remains available between the jobs.
+func (a *Agent) runJob(pending job.PendingJob) {
+	newCtx := &ExecutionContext{
+		Function:       lang.Null,
+		Realm:          pending.Realm,
+		ScriptOrModule: pending.ScriptOrModule,
+	}
+	err := a.ExecutionContextStack.Push(newCtx)
+	if err == nil {
+		defer a.ExecutionContextStack.Pop()
+		err = pending.Job(pending.Arguments...)
+	}
+	if err != nil && a.HostReportErrors != nil {
+		a.HostReportErrors(err)
+	}
 }
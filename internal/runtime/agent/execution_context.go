@@ -1,6 +1,7 @@
 package agent
 
 import (
+	"github.com/gojisvm/gojis/internal/parser/ast"
 	"github.com/gojisvm/gojis/internal/runtime/binding"
 	"github.com/gojisvm/gojis/internal/runtime/lang"
 	"github.com/gojisvm/gojis/internal/runtime/realm"
@@ -22,4 +23,10 @@ type ExecutionContext struct {
 	VariableEnvironment binding.Environment
 
 	Generator interface{} // #41: Table 23, GeneratorObject
+
+	// Position is the position in the source code of the statement, call
+	// or new expression that is being evaluated by the execution context.
+	// It is maintained by the runtime for stack traces, and is not part of
+	// the specification.
+	Position ast.Position
 }
@@ -1,43 +1,115 @@
 package agent
 
-import "github.com/gojisvm/gojis/internal/runtime/agent/stack"
+import (
+	"github.com/gojisvm/gojis/internal/runtime/agent/stack"
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+)
+
+const (
+	// DefaultMaxDepth is the maximum number of ExecutionContexts that a new
+	// ExecutionContextStack can hold.
+	DefaultMaxDepth = 10000
+	// MaxDepthLimit is the largest maximum number of ExecutionContexts that
+	// can be set on an ExecutionContextStack. A call of an ECMAScript
+	// function takes up to about 16KB of the Go stack, without the nested
+	// expressions and statements that the tree-walking engine evaluates
+	// for it, which the runtime limits separately. Together, they use less
+	// than the 1GB that the stack of a goroutine is limited to by default.
+	MaxDepthLimit = 25000
+)
 
 // ExecutionContextStack is a stack that is used to keep track of the currently
 // executing and soon to execute ExecutionContexts.
 // ExecutionContextStack is mentioned in 8.3.
 type ExecutionContextStack struct {
-	stack stack.Stack
+	stack    stack.Stack
+	depth    int
+	maxDepth int
+	// nested is the number of nested calls that have been counted by
+	// Enter.
+	nested int
 }
 
 // NewExecutionContextStack creates a new ExecutionContextStack that is using a SliceStack.
+// It can hold DefaultMaxDepth ExecutionContexts.
 func NewExecutionContextStack() *ExecutionContextStack {
 	s := new(ExecutionContextStack)
 	s.stack = stack.NewSliceStack()
+	s.maxDepth = DefaultMaxDepth
 	return s
 }
 
 // IsEmpty is used to determine if there are any ExecutionContexts on the stack.
-func (s ExecutionContextStack) IsEmpty() bool {
-	return s.stack.Peek() == nil
+func (s *ExecutionContextStack) IsEmpty() bool {
+	return s.depth == 0
+}
+
+// Len returns the number of ExecutionContexts on the stack.
+func (s *ExecutionContextStack) Len() int {
+	return s.depth
+}
+
+// SetMaxDepth sets the maximum number of ExecutionContexts that the stack
+// can hold. The depth is limited to the range from 1 to MaxDepthLimit.
+func (s *ExecutionContextStack) SetMaxDepth(depth int) {
+	switch {
+	case depth < 1:
+		depth = 1
+	case depth > MaxDepthLimit:
+		depth = MaxDepthLimit
+	}
+	s.maxDepth = depth
 }
 
-// Push adds a new ExecutionContext to the stack.
-func (s ExecutionContextStack) Push(ctx *ExecutionContext) {
+// Push adds a new ExecutionContext to the stack. If the stack already holds
+// the maximum number of ExecutionContexts, including the calls counted by
+// Enter, the context is not added, and a RangeError is returned instead.
+func (s *ExecutionContextStack) Push(ctx *ExecutionContext) errors.Error {
+	if s.depth+s.nested >= s.maxDepth {
+		return errMaxDepth()
+	}
 	s.stack.Push(ctx)
+	s.depth++
+	return nil
+}
+
+// Enter counts a nested call that has no ExecutionContext, like a call of a
+// built-in function, against the maximum depth of the stack, so that such
+// calls cannot recurse without limit. If the maximum depth has been
+// reached, a RangeError is returned instead. Every successful Enter must be
+// followed by a call of Leave.
+func (s *ExecutionContextStack) Enter() errors.Error {
+	if s.depth+s.nested >= s.maxDepth {
+		return errMaxDepth()
+	}
+	s.nested++
+	return nil
+}
+
+// Leave ends a nested call that was counted by Enter.
+func (s *ExecutionContextStack) Leave() {
+	s.nested--
+}
+
+// errMaxDepth returns the RangeError for exceeding the maximum depth of the
+// stack.
+func errMaxDepth() errors.Error {
+	return errors.NewRangeError("Maximum call stack size exceeded")
 }
 
 // Pop removes the topmost ExecutionContext from the stack and returns it.
-func (s ExecutionContextStack) Pop() *ExecutionContext {
+func (s *ExecutionContextStack) Pop() *ExecutionContext {
 	elem := s.stack.Pop()
 	if elem == nil {
 		return nil
 	}
 
+	s.depth--
 	return elem.(*ExecutionContext)
 }
 
 // Peek returns the topmost ExecutionContext without removing it.
-func (s ExecutionContextStack) Peek() *ExecutionContext {
+func (s *ExecutionContextStack) Peek() *ExecutionContext {
 	elem := s.stack.Peek()
 	if elem == nil {
 		return nil
@@ -45,3 +117,11 @@ func (s ExecutionContextStack) Peek() *ExecutionContext {
 
 	return elem.(*ExecutionContext)
 }
+
+// Range calls fn for each ExecutionContext on the stack, starting with the
+// topmost one, until fn returns false.
+func (s *ExecutionContextStack) Range(fn func(ctx *ExecutionContext) bool) {
+	s.stack.Range(func(elem interface{}) bool {
+		return fn(elem.(*ExecutionContext))
+	})
+}
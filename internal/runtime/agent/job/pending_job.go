@@ -1,15 +1,22 @@
 package job
 
 import (
+	"github.com/gojisvm/gojis/internal/runtime/errors"
 	"github.com/gojisvm/gojis/internal/runtime/lang"
 	"github.com/gojisvm/gojis/internal/runtime/realm"
 )
 
+// Func is the abstract operation of a job, that is called with the
+// arguments of the PendingJob when the job is run. An error is returned
+// if the job completes abruptly.
+type Func func(args ...lang.Value) errors.Error
+
 // PendingJob represents a pending job that is to be executed by an agent.
+// PendingJob is specified in 8.4, Table 24.
 type PendingJob struct {
-	Job            string
+	Job            Func
 	Arguments      []lang.Value
 	Realm          *realm.Realm
-	ScriptOrModule interface{} // FIXME: 8.4, Table 24
+	ScriptOrModule lang.InternalValue
 	HostDefined    lang.InternalValue
 }
@@ -1,29 +1,67 @@
 package job
 
-const (
-	// QueueBuffer is the buffer size that all job queues are using.
-	QueueBuffer = 10
-)
+import "sync"
 
-// Queue represents a JobQueue as specified in 8.4.
+// compactThreshold is the number of dequeued jobs, after which the jobs
+// that are still in a queue are moved to the front of its slice, if they
+// take up less than half of it.
+const compactThreshold = 1024
+
+// Queue represents a JobQueue as specified in 8.4. It is an unbounded
+// first-in first-out queue, so adding a job never blocks, even if the
+// jobs are added by the thread that runs them. A Queue is safe for
+// concurrent use.
 type Queue struct {
-	q chan PendingJob
+	mu   sync.Mutex
+	jobs []PendingJob
+	head int // the index of the first job in jobs
 }
 
-// NewQueue returns a new Queue.
+// NewQueue returns a new, empty Queue.
 func NewQueue() *Queue {
-	q := new(Queue)
-	q.q = make(chan PendingJob, QueueBuffer)
-	return q
+	return new(Queue)
 }
 
 // Enqueue adds a new PendingJob at the very end of the queue.
 func (q *Queue) Enqueue(j PendingJob) {
-	q.q <- j
+	q.mu.Lock()
+	q.jobs = append(q.jobs, j)
+	q.mu.Unlock()
+}
+
+// Len returns the number of PendingJobs in the queue.
+func (q *Queue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.jobs) - q.head
 }
 
-// Dequeue removes the first element from the queue and returns it.
-func (q *Queue) Dequeue() (PendingJob, bool) {
-	j, ok := <-q.q
-	return j, ok
+// TryDequeue removes the first element from the queue and returns it. If
+// the queue is empty, it returns immediately, and false is returned.
+func (q *Queue) TryDequeue() (PendingJob, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.head == len(q.jobs) {
+		return PendingJob{}, false
+	}
+
+	j := q.jobs[q.head]
+	q.jobs[q.head] = PendingJob{} // release the arguments of the job
+	q.head++
+	switch {
+	case q.head == len(q.jobs):
+		// reuse the slice, which is the common case of a queue that is
+		// drained as fast as it is filled
+		q.jobs = q.jobs[:0]
+		q.head = 0
+	case q.head >= compactThreshold && q.head*2 >= len(q.jobs):
+		n := copy(q.jobs, q.jobs[q.head:])
+		for i := n; i < len(q.jobs); i++ {
+			q.jobs[i] = PendingJob{}
+		}
+		q.jobs = q.jobs[:n]
+		q.head = 0
+	}
+	return j, true
 }
@@ -0,0 +1,30 @@
+package job
+
+import "testing"
+
+var j PendingJob
+
+func BenchmarkQueue(b *testing.B) {
+	b.Run("Enqueue TryDequeue", func(b *testing.B) {
+		q := NewQueue()
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			q.Enqueue(PendingJob{})
+			j, _ = q.TryDequeue()
+		}
+	})
+	b.Run("Enqueue all", func(b *testing.B) {
+		q := NewQueue()
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			q.Enqueue(PendingJob{})
+		}
+		for i := 0; i < b.N; i++ {
+			j, _ = q.TryDequeue()
+		}
+	})
+}
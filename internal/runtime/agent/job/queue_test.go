@@ -0,0 +1,45 @@
+package job
+
+import (
+	"testing"
+
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+	"github.com/stretchr/testify/require"
+)
+
+func pendingJob(i int) PendingJob {
+	return PendingJob{Arguments: []lang.Value{lang.NewNumber(float64(i))}}
+}
+
+func TestQueue(t *testing.T) {
+	require := require.New(t)
+
+	q := NewQueue()
+	_, ok := q.TryDequeue()
+	require.False(ok)
+
+	// the queue is drained by the thread that fills it
+	const n = 3 * compactThreshold
+	for i := 0; i < n; i++ {
+		q.Enqueue(pendingJob(i))
+	}
+	require.Equal(n, q.Len())
+	for i := 0; i < n; i++ {
+		if i%2 == 0 {
+			q.Enqueue(pendingJob(n + i/2))
+		}
+		j, ok := q.TryDequeue()
+		require.True(ok)
+		require.Equal(pendingJob(i), j)
+	}
+	require.Equal(n/2, q.Len())
+	for i := 0; i < n/2; i++ {
+		j, ok := q.TryDequeue()
+		require.True(ok)
+		require.Equal(pendingJob(n+i), j)
+	}
+
+	require.Zero(q.Len())
+	_, ok = q.TryDequeue()
+	require.False(ok)
+}
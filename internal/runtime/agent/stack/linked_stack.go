@@ -38,3 +38,11 @@ func (s *linkedStack) Push(v interface{}) {
 	s.top = &node{v, s.top}
 	s.length++
 }
+
+func (s *linkedStack) Range(fn func(interface{}) bool) {
+	for n := s.top; n != nil; n = n.prev {
+		if !fn(n.value) {
+			return
+		}
+	}
+}
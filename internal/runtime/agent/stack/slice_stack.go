@@ -25,6 +25,14 @@ func (s *sliceStack) Peek() (elem interface{}) {
 	return
 }
 
+func (s *sliceStack) Range(fn func(interface{}) bool) {
+	for i := len(s.s) - 1; i >= 0; i-- {
+		if !fn(s.s[i]) {
+			return
+		}
+	}
+}
+
 type internalSliceStack []interface{}
 
 func (s internalSliceStack) push(v interface{}) internalSliceStack {
@@ -8,4 +8,7 @@ type Stack interface {
 	Pop() interface{}
 	// Peek returns the topmost element of the stack without removing it.
 	Peek() interface{}
+	// Range calls fn for each element of the stack, starting with the
+	// topmost one, until fn returns false.
+	Range(fn func(elem interface{}) bool)
 }
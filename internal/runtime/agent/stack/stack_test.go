@@ -9,6 +9,7 @@ import (
 func testStack(t *testing.T, s Stack) {
 	t.Run("Push Pop", testPushPop(s))
 	t.Run("Peek", testPeek(s))
+	t.Run("Range", testRange(s))
 }
 
 func testPushPop(s Stack) func(t *testing.T) {
@@ -51,3 +52,36 @@ func testPeek(s Stack) func(t *testing.T) {
 		require.Nil(s.Peek())
 	}
 }
+
+func testRange(s Stack) func(t *testing.T) {
+	return func(t *testing.T) {
+		require := require.New(t)
+
+		s.Push("foo")
+		s.Push("bar")
+		s.Push("snafu")
+
+		var elems []interface{}
+		s.Range(func(elem interface{}) bool {
+			elems = append(elems, elem)
+			return true
+		})
+		require.Equal([]interface{}{"snafu", "bar", "foo"}, elems)
+
+		elems = nil
+		s.Range(func(elem interface{}) bool {
+			elems = append(elems, elem)
+			return elem != "bar"
+		})
+		require.Equal([]interface{}{"snafu", "bar"}, elems)
+
+		_ = s.Pop()
+		_ = s.Pop()
+		_ = s.Pop()
+
+		s.Range(func(interface{}) bool {
+			require.Fail("Range called fn for an empty stack")
+			return true
+		})
+	}
+}
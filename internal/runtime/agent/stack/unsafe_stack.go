@@ -42,3 +42,11 @@ func (s *unsafeStack) Peek() (elem interface{}) {
 	elem = *(*interface{})(elem.(unsafe.Pointer))
 	return
 }
+
+func (s *unsafeStack) Range(fn func(interface{}) bool) {
+	for i := len(s.s) - 1; i >= 0; i-- {
+		if !fn(*(*interface{})(s.s[i].(unsafe.Pointer))) {
+			return
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package runtime
+
+import (
+	"strconv"
+
+	"github.com/gojisvm/gojis/internal/runtime/binding"
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+	"github.com/gojisvm/gojis/internal/runtime/realm"
+)
+
+// parameterMap is the [[ParameterMap]] of a mapped arguments object. It
+// maps the indices of the arguments, whose properties are still aliases of
+// parameters, to the names of the parameters in the environment of the
+// call.
+type parameterMap struct {
+	env   binding.Environment
+	names map[string]lang.String
+}
+
+// Type returns lang.TypeInternal.
+func (*parameterMap) Type() lang.Type { return lang.TypeInternal }
+
+// Value returns the parameter map itself.
+func (m *parameterMap) Value() interface{} { return m }
+
+// mapped returns the parameter map of the given arguments object and the
+// name of the parameter that the property with the given key is an alias
+// of. If the property is not mapped, ok is false.
+func mapped(o *lang.Object, p lang.StringOrSymbol) (m *parameterMap, name lang.String, ok bool) {
+	if p.Type() != lang.TypeString {
+		return nil, nil, false
+	}
+	m = o.GetSlot(lang.SlotParameterMap).(*parameterMap)
+	name, ok = m.names[p.Underlying().(lang.String).String()]
+	return m, name, ok
+}
+
+// get returns the value of the parameter with the given name.
+func (m *parameterMap) get(name lang.String) lang.Value {
+	v, _ := m.env.GetBindingValue(name, false)
+	return v
+}
+
+// set sets the value of the parameter with the given name.
+func (m *parameterMap) set(name lang.String, v lang.Value) {
+	_ = m.env.SetMutableBinding(name, v, false)
+}
+
+// remove removes the alias of the property with the given key.
+func (m *parameterMap) remove(p lang.StringOrSymbol) {
+	delete(m.names, p.Underlying().(lang.String).String())
+}
+
+var argumentsExoticMethods = &lang.ExoticMethods{
+	GetOwnProperty:    argumentsGetOwnProperty,
+	DefineOwnProperty: argumentsDefineOwnProperty,
+	Get:               argumentsGet,
+	Set:               argumentsSet,
+	Delete:            argumentsDelete,
+}
+
+// createUnmappedArgumentsObject creates the arguments object of a call of
+// a strict function or a function with a non-simple parameter list, whose
+// properties are not aliases of the parameters.
+// CreateUnmappedArgumentsObject is specified in 9.4.4.6.
+func (r *Runtime) createUnmappedArgumentsObject(args []lang.Value) *lang.Object {
+	obj := lang.ObjectCreate(r.intrinsic(realm.IntrinsicNameObjectPrototype), lang.SlotParameterMap)
+	r.defineArguments(obj, args)
+	thrower := r.intrinsic(realm.IntrinsicNameThrowTypeError)
+	_, _ = lang.DefinePropertyOrThrow(obj, lang.NewStringKey("callee"), lang.NewAccessorProperty(thrower, thrower, lang.False, lang.False))
+	return obj
+}
+
+// createMappedArgumentsObject creates the arguments object of a call of
+// the function with the given arguments. The properties of the arguments
+// that have a parameter with the given names are aliases of the bindings
+// of the parameters in the given environment, until they are deleted or
+// redefined. If a name occurs more than once, the last parameter with that
+// name is mapped.
+// CreateMappedArgumentsObject is specified in 9.4.4.7.
+func (f *function) createMappedArgumentsObject(args []lang.Value, parameterNames []string, env binding.Environment) *lang.Object {
+	r := f.runtime
+	obj := lang.ObjectCreate(r.intrinsic(realm.IntrinsicNameObjectPrototype), lang.SlotParameterMap)
+	r.defineArguments(obj, args)
+
+	m := &parameterMap{env: env, names: make(map[string]lang.String)}
+	mappedNames := make(map[string]bool)
+	for index := len(parameterNames) - 1; index >= 0; index-- {
+		name := parameterNames[index]
+		if mappedNames[name] {
+			continue
+		}
+		mappedNames[name] = true
+		if index < len(args) {
+			m.names[strconv.Itoa(index)] = lang.NewString(name)
+		}
+	}
+	obj.SetSlot(lang.SlotParameterMap, m)
+	obj.Exotic = argumentsExoticMethods
+	_, _ = lang.DefinePropertyOrThrow(obj, lang.NewStringKey("callee"), lang.NewDataProperty(f.object, lang.True, lang.False, lang.True))
+	return obj
+}
+
+// defineArguments defines the indexed properties, the length and the
+// @@iterator property of the given arguments object.
+func (r *Runtime) defineArguments(obj *lang.Object, args []lang.Value) {
+	_, _ = lang.DefinePropertyOrThrow(obj, lang.NewStringKey("length"), lang.NewDataProperty(lang.NewNumber(float64(len(args))), lang.True, lang.False, lang.True))
+	for index, val := range args {
+		_, _ = lang.CreateDataProperty(obj, lang.NewStringKey(strconv.Itoa(index)), val)
+	}
+	_, _ = lang.DefinePropertyOrThrow(obj, lang.NewStringOrSymbol(lang.SymbolIterator), lang.NewDataProperty(r.intrinsic(realm.IntrinsicNameArrayProtoValues), lang.True, lang.False, lang.True))
+}
+
+// argumentsGetOwnProperty is specified in 9.4.4.1.
+func argumentsGetOwnProperty(o *lang.Object, p lang.StringOrSymbol) *lang.Property {
+	desc := o.OrdinaryGetOwnProperty(p)
+	if desc == nil {
+		return nil
+	}
+	if m, name, ok := mapped(o, p); ok {
+		desc.SetField(lang.FieldNameValue, m.get(name))
+	}
+	return desc
+}
+
+// argumentsDefineOwnProperty is specified in 9.4.4.2.
+func argumentsDefineOwnProperty(o *lang.Object, p lang.StringOrSymbol, desc *lang.Property) (lang.Boolean, errors.Error) {
+	m, name, isMapped := mapped(o, p)
+	newArgDesc := desc
+	if isMapped && bool(desc.IsDataDescriptor()) && !desc.Has(lang.FieldNameValue) && desc.Has(lang.FieldNameWritable) && !bool(desc.Writable()) {
+		newArgDesc = lang.NewProperty()
+		for _, field := range []string{lang.FieldNameWritable, lang.FieldNameEnumerable, lang.FieldNameConfigurable} {
+			if v, ok := desc.GetField(field); ok {
+				newArgDesc.SetField(field, v)
+			}
+		}
+		newArgDesc.SetField(lang.FieldNameValue, m.get(name))
+	}
+	if !o.OrdinaryDefineOwnProperty(p, newArgDesc) {
+		return lang.False, nil
+	}
+	if isMapped {
+		if desc.IsAccessorDescriptor() {
+			m.remove(p)
+		} else {
+			if desc.Has(lang.FieldNameValue) {
+				m.set(name, desc.Value())
+			}
+			if desc.Has(lang.FieldNameWritable) && !bool(desc.Writable()) {
+				m.remove(p)
+			}
+		}
+	}
+	return lang.True, nil
+}
+
+// argumentsGet is specified in 9.4.4.3.
+func argumentsGet(o *lang.Object, p lang.StringOrSymbol, receiver lang.Value) (lang.Value, errors.Error) {
+	if m, name, ok := mapped(o, p); ok {
+		return m.get(name), nil
+	}
+	return o.OrdinaryGet(p, receiver)
+}
+
+// argumentsSet is specified in 9.4.4.4.
+func argumentsSet(o *lang.Object, p lang.StringOrSymbol, v, receiver lang.Value) (lang.Boolean, errors.Error) {
+	if receiver == lang.Value(o) {
+		if m, name, ok := mapped(o, p); ok {
+			m.set(name, v)
+		}
+	}
+	return o.OrdinarySet(p, v, receiver)
+}
+
+// argumentsDelete is specified in 9.4.4.5.
+func argumentsDelete(o *lang.Object, p lang.StringOrSymbol) lang.Boolean {
+	m, _, isMapped := mapped(o, p)
+	result := o.OrdinaryDelete(p)
+	if bool(result) && isMapped {
+		m.remove(p)
+	}
+	return result
+}
@@ -14,14 +14,14 @@ type Environment interface {
 
 	Outer() Environment
 
-	HasBinding(n lang.String) bool
+	HasBinding(n lang.String) (bool, errors.Error)
 	CreateMutableBinding(n lang.String, deletable bool) errors.Error
 	CreateImmutableBinding(n lang.String, strict bool) errors.Error
 	InitializeBinding(n lang.String, val lang.Value) errors.Error
 	SetMutableBinding(n lang.String, val lang.Value, strict bool) errors.Error
 	GetThisBinding() (lang.Value, errors.Error)
 	GetBindingValue(n lang.String, strict bool) (lang.Value, errors.Error)
-	DeleteBinding(n lang.String) bool
+	DeleteBinding(n lang.String) (bool, errors.Error)
 	HasThisBinding() bool
 	HasSuperBinding() bool
 	WithBaseObject() lang.Value
@@ -1,24 +1,35 @@
 package binding
 
-import "github.com/gojisvm/gojis/internal/runtime/lang"
+import (
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+)
 
 // GetIdentifierReference returns a named reference with n as the name.
 // If strict is true, the reference will be a strict reference.
-// The given environment must be of type Environment or Null.
+// The given environment must be of type Environment or Null. A nil
+// environment, which is the outer environment of the global environment,
+// is treated like Null.
 // If the environment does not have a binding with the given name, its outer
 // environment is used.
 // GetIdentifierReference is specified in 8.1.2.1.
-func GetIdentifierReference(env lang.InternalValue, n lang.String, strict bool) *Reference {
-	if env == lang.Null {
-		return NewReference(lang.NewStringOrSymbol(n), lang.Undefined, strict)
-	}
+func GetIdentifierReference(env lang.InternalValue, n lang.String, strict bool) (*Reference, errors.Error) {
+	for {
+		if env == nil || env == lang.Null {
+			return NewReference(lang.NewStringOrSymbol(n), lang.Undefined, strict), nil
+		}
 
-	lex := env.(Environment)
-	if lex.HasBinding(n) {
-		return NewReference(lang.NewStringOrSymbol(n), lex, strict)
-	}
+		lex := env.(Environment)
+		exists, err := lex.HasBinding(n)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return NewReference(lang.NewStringOrSymbol(n), lex, strict), nil
+		}
 
-	return GetIdentifierReference(lex.Outer(), n, strict)
+		env = lex.Outer()
+	}
 }
 
 // NewDeclarativeEnvironment creates a new declarative environment with
@@ -41,14 +52,29 @@ func NewObjectEnvironment(outer Environment, obj *lang.Object) *ObjectEnvironmen
 	return env
 }
 
-// NewFunctionEnvironment creates a new function environment with
-// the given outer environment for the given function object.
-// The passed function object can either be Undefined or an Object.
+// NewFunctionEnvironment creates a new function environment with the given
+// outer environment for the given function object. The outer environment,
+// lexicalThis and homeObject are the [[Environment]], [[ThisMode]] and
+// [[HomeObject]] of the function. lexicalThis is true if the function is an
+// arrow function, and homeObject is an Object or Undefined. newTarget is the
+// newTarget of the Construct internal method, or Undefined for a call.
 // NewFunctionEnvironment is specified in 8.1.2.4.
-func NewFunctionEnvironment(outer Environment, f lang.Value) *FunctionEnvironment {
-	lang.EnsureTypeOneOf(f, lang.TypeObject, lang.TypeUndefined)
+func NewFunctionEnvironment(outer Environment, f *lang.Object, lexicalThis bool, homeObject, newTarget lang.Value) *FunctionEnvironment {
+	lang.EnsureTypeOneOf(homeObject, lang.TypeObject, lang.TypeUndefined)
+	lang.EnsureTypeOneOf(newTarget, lang.TypeObject, lang.TypeUndefined)
 
-	panic("#42: 8.1.2.4 NewFunctionEnvironment")
+	env := new(FunctionEnvironment)
+	env.DeclarativeEnvironment = NewDeclarativeEnvironment(outer)
+	env.FunctionObject = f
+	if lexicalThis {
+		env.ThisBindingStatus = StatusLexical
+	} else {
+		env.ThisBindingStatus = StatusUninitialized
+	}
+	env.ThisValue = lang.Undefined
+	env.HomeObject = homeObject
+	env.NewTarget = newTarget
+	return env
 }
 
 // NewGlobalEnvironment creates a new global environment with the given global object and
@@ -69,6 +95,6 @@ func NewGlobalEnvironment(globalObj, thisValue *lang.Object) *GlobalEnvironment
 // outer environment.
 func NewModuleEnvironment(outer Environment) *ModuleEnvironment {
 	env := new(ModuleEnvironment)
-	env.outer = outer
+	env.DeclarativeEnvironment = NewDeclarativeEnvironment(outer)
 	return env
 }
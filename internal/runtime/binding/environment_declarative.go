@@ -50,9 +50,9 @@ func (e *DeclarativeEnvironment) mustGetBinding(n lang.String) *Binding {
 // HasBinding determines whether the given name is an identifier bound
 // by the record.
 // HasBinding is specified in 8.1.1.1.1.
-func (e *DeclarativeEnvironment) HasBinding(n lang.String) bool {
+func (e *DeclarativeEnvironment) HasBinding(n lang.String) (bool, errors.Error) {
 	_, ok := e.getBinding(n)
-	return ok
+	return ok, nil
 }
 
 // CreateMutableBinding creates a new mutable binding for the name N that is uninitialized.
@@ -101,7 +101,7 @@ func (e *DeclarativeEnvironment) InitializeBinding(n lang.String, val lang.Value
 // If the binding with the given name is not yet initialized, a ReferenceError is raised.
 // SetMutableBinding is specified in 8.1.1.1.5.
 func (e *DeclarativeEnvironment) SetMutableBinding(n lang.String, val lang.Value, strict bool) errors.Error {
-	if !e.HasBinding(n) {
+	if _, ok := e.getBinding(n); !ok {
 		if strict {
 			return errors.NewReferenceError("Cannot set mutable binding in strict mode if binding does not exist")
 		}
@@ -117,12 +117,12 @@ func (e *DeclarativeEnvironment) SetMutableBinding(n lang.String, val lang.Value
 	}
 
 	if !b.IsInitialized() {
-		return errors.NewReferenceError(fmt.Sprintf("The binding for '%v' has not been initialized yet", n))
+		return errors.NewReferenceError(fmt.Sprintf("Cannot access '%v' before initialization", n))
 	} else if !b.IsImmutable() {
 		b.Set(val)
 	} else {
 		if strict {
-			return errors.NewTypeError(fmt.Sprintf("Attempting to change value of an immutable binding '%v'", n))
+			return errors.NewTypeError("Assignment to constant variable.")
 		}
 	}
 
@@ -140,7 +140,7 @@ func (e *DeclarativeEnvironment) GetThisBinding() (lang.Value, errors.Error) {
 func (e *DeclarativeEnvironment) GetBindingValue(n lang.String, strict bool) (lang.Value, errors.Error) {
 	b := e.mustGetBinding(n)
 	if !b.IsInitialized() {
-		return nil, errors.NewReferenceError(fmt.Sprintf("Binding '%v' has not been initialized yet", n))
+		return nil, errors.NewReferenceError(fmt.Sprintf("Cannot access '%v' before initialization", n))
 	}
 
 	return b.Value(), nil
@@ -150,18 +150,18 @@ func (e *DeclarativeEnvironment) GetBindingValue(n lang.String, strict bool) (la
 // If the binding is not deletable, this function returns false.
 // If the binding does not exist or is deletable, this function returns true.
 // DeleteBinding is specified in 8.1.1.1.7.
-func (e *DeclarativeEnvironment) DeleteBinding(n lang.String) bool {
+func (e *DeclarativeEnvironment) DeleteBinding(n lang.String) (bool, errors.Error) {
 	b, ok := e.getBinding(n)
 	if !ok {
-		return true
+		return true, nil
 	}
 
 	if !b.IsDeletable() {
-		return false
+		return false, nil
 	}
 
 	e.deleteBinding(n)
-	return true
+	return true, nil
 }
 
 // HasThisBinding returns false.
@@ -51,7 +51,7 @@ type FunctionEnvironment struct {
 // BindThisValue is specified in 8.1.1.3.1.
 func (e *FunctionEnvironment) BindThisValue(val lang.Value) (lang.Value, errors.Error) {
 	if e.ThisBindingStatus == StatusInitialized {
-		return nil, errors.NewReferenceError("'This' has already been initialized")
+		return nil, errors.NewReferenceError("Super constructor may only be called once")
 	}
 
 	e.ThisValue = val
@@ -80,7 +80,7 @@ func (e *FunctionEnvironment) HasSuperBinding() bool {
 // GetThisBinding is specified in 8.1.1.3.4.
 func (e *FunctionEnvironment) GetThisBinding() (lang.Value, errors.Error) {
 	if e.ThisBindingStatus == StatusUninitialized {
-		return nil, errors.NewReferenceError("'This' has not been initialized yet")
+		return nil, errors.NewReferenceError("Must call super constructor in derived class before accessing 'this' or returning from derived constructor")
 	}
 
 	return e.ThisValue, nil
@@ -72,7 +72,8 @@ func (e *GlobalEnvironment) HasVarDeclaration(n lang.String) bool {
 // such as a LexicalDeclaration or a ClassDeclaration.
 // HasLexicalDeclaration is specified in 8.1.1.4.13.
 func (e *GlobalEnvironment) HasLexicalDeclaration(n lang.String) bool {
-	return e.DeclarativeRecord.HasBinding(n)
+	_, ok := e.DeclarativeRecord.getBinding(n)
+	return ok
 }
 
 // HasRestrictedGlobalProperty determines if the argument identifier is the
@@ -80,12 +81,12 @@ func (e *GlobalEnvironment) HasLexicalDeclaration(n lang.String) bool {
 // global lexical binding.
 // HasRestrictedGlobalProperty is specified in 8.1.1.4.14.
 func (e *GlobalEnvironment) HasRestrictedGlobalProperty(n lang.String) bool {
-	existingProp := e.ObjectRecord.bindingObject.GetOwnProperty(lang.NewStringOrSymbol(n)).Value()
-	if existingProp == lang.Undefined {
+	existingProp := e.ObjectRecord.bindingObject.GetOwnProperty(lang.NewStringOrSymbol(n))
+	if existingProp == nil {
 		return false
 	}
 
-	panic("#43: 8.1.1.4.14")
+	return !bool(existingProp.Configurable())
 }
 
 // CanDeclareGlobalVar determines if a corresponding CreateGlobalVarBinding call
@@ -104,35 +105,73 @@ func (e *GlobalEnvironment) CanDeclareGlobalVar(n lang.String) bool {
 // CanDeclareGlobalFunction determines if a corresponding
 // CreateGlobalFunctionBinding call would succeed if called for the same given
 // name. CanDeclareGlobalFunction is specified in 8.1.1.4.16.
-func (e *GlobalEnvironment) CanDeclareGlobalFunction(n lang.String) {
-	panic("TODO: properties")
+func (e *GlobalEnvironment) CanDeclareGlobalFunction(n lang.String) bool {
+	globalObj := e.ObjectRecord.bindingObject
+
+	existingProp := globalObj.GetOwnProperty(lang.NewStringOrSymbol(n))
+	if existingProp == nil {
+		return lang.InternalIsExtensible(globalObj)
+	}
+
+	if existingProp.Configurable() {
+		return true
+	}
+
+	return bool(existingProp.IsDataDescriptor() && existingProp.Writable() && existingProp.Enumerable())
 }
 
 // CreateGlobalVarBinding creates and initializes a mutable binding in the
 // associated object Environment Record and records the bound name in the
 // associated [[VarNames]] List. If a binding already exists, it is reused and
 // assumed to be initialized. CreateGlobalVarBinding is specified in 8.1.1.4.17.
-func (e *GlobalEnvironment) CreateGlobalVarBinding(n lang.String, deletable bool) {
+func (e *GlobalEnvironment) CreateGlobalVarBinding(n lang.String, deletable bool) errors.Error {
 	globalObj := e.ObjectRecord.bindingObject
 
 	hasProperty := lang.HasOwnProperty(globalObj, lang.NewStringOrSymbol(n))
 	extensible := lang.InternalIsExtensible(globalObj)
-	if !hasProperty.Value().(bool) && extensible {
-		e.ObjectRecord.CreateMutableBinding(n, deletable)
-		e.ObjectRecord.InitializeBinding(n, lang.Undefined)
+	if !bool(hasProperty) && extensible {
+		if err := e.ObjectRecord.CreateMutableBinding(n, deletable); err != nil {
+			return err
+		}
+		if err := e.ObjectRecord.InitializeBinding(n, lang.Undefined); err != nil {
+			return err
+		}
 	}
 
 	if !e.HasVarDeclaration(n) {
 		e.VarNames = append(e.VarNames, n.Value().(string))
 	}
+	return nil
 }
 
 // CreateGlobalFunctionBinding creates and initializes a mutable binding in the
 // associated object Environment Record and records the bound name in the
 // associated [[VarNames]] List. If a binding already exists, it is replaced.
-// CreateGlobalFunctionBinding
-func (e *GlobalEnvironment) CreateGlobalFunctionBinding(n lang.String, val lang.Value, deletable bool) {
-	panic("TODO: properties")
+// CreateGlobalFunctionBinding is specified in 8.1.1.4.18.
+func (e *GlobalEnvironment) CreateGlobalFunctionBinding(n lang.String, val lang.Value, deletable bool) errors.Error {
+	globalObj := e.ObjectRecord.bindingObject
+	key := lang.NewStringOrSymbol(n)
+
+	var desc *lang.Property
+	existingProp := globalObj.GetOwnProperty(key)
+	if existingProp == nil || existingProp.Configurable() {
+		desc = lang.NewDataProperty(val, lang.True, lang.True, lang.Boolean(deletable))
+	} else {
+		desc = lang.NewProperty()
+		desc.SetField(lang.FieldNameValue, val)
+	}
+
+	if _, err := lang.DefinePropertyOrThrow(globalObj, key, desc); err != nil {
+		return err
+	}
+	if _, err := lang.Set(globalObj, key, val, false); err != nil {
+		return err
+	}
+
+	if !e.HasVarDeclaration(n) {
+		e.VarNames = append(e.VarNames, n.Value().(string))
+	}
+	return nil
 }
 
 /* -- implements Environment -- */
@@ -140,8 +179,11 @@ func (e *GlobalEnvironment) CreateGlobalFunctionBinding(n lang.String, val lang.
 // HasBinding  determines if the argument identifier is one of the identifiers
 // bound by the record.
 // HasBinding is specified in 8.1.1.4.1.
-func (e *GlobalEnvironment) HasBinding(n lang.String) bool {
-	return e.DeclarativeRecord.HasBinding(n) || e.ObjectRecord.HasBinding(n)
+func (e *GlobalEnvironment) HasBinding(n lang.String) (bool, errors.Error) {
+	if e.HasLexicalDeclaration(n) {
+		return true, nil
+	}
+	return e.ObjectRecord.HasBinding(n)
 }
 
 // CreateMutableBinding creates a new mutable binding for the name N that is
@@ -150,7 +192,7 @@ func (e *GlobalEnvironment) HasBinding(n lang.String) bool {
 // is true the new binding is marked as being subject to deletion.
 // CreateMutableBinding is specified in 8.1.1.4.2.
 func (e *GlobalEnvironment) CreateMutableBinding(n lang.String, deletable bool) errors.Error {
-	if e.DeclarativeRecord.HasBinding(n) {
+	if e.HasLexicalDeclaration(n) {
 		return errors.NewTypeError(fmt.Sprintf("Declarative environment record already has a binding for '%v'", n))
 	}
 
@@ -162,7 +204,7 @@ func (e *GlobalEnvironment) CreateMutableBinding(n lang.String, deletable bool)
 // for n. If strict is true the new binding is marked as a strict binding.
 // CreateImmutableBinding is specified in 8.1.1.4.3.
 func (e *GlobalEnvironment) CreateImmutableBinding(n lang.String, strict bool) errors.Error {
-	if e.DeclarativeRecord.HasBinding(n) {
+	if e.HasLexicalDeclaration(n) {
 		return errors.NewTypeError(fmt.Sprintf("Declarative environment record already has a binding for '%v'", n))
 	}
 
@@ -174,7 +216,7 @@ func (e *GlobalEnvironment) CreateImmutableBinding(n lang.String, strict bool) e
 // argument val. An uninitialized binding for n must already exist.
 // InitializeBinding is specified in 8.1.1.4.4.
 func (e *GlobalEnvironment) InitializeBinding(n lang.String, val lang.Value) errors.Error {
-	if e.DeclarativeRecord.HasBinding(n) {
+	if e.HasLexicalDeclaration(n) {
 		return e.DeclarativeRecord.InitializeBinding(n, val)
 	}
 
@@ -188,7 +230,7 @@ func (e *GlobalEnvironment) InitializeBinding(n lang.String, val lang.Value) err
 // not or is not currently writable, error handling is determined by the value
 // of strict. SetMutableBinding is specified in 8.1.1.4.5.
 func (e *GlobalEnvironment) SetMutableBinding(n lang.String, val lang.Value, strict bool) errors.Error {
-	if e.DeclarativeRecord.HasBinding(n) {
+	if e.HasLexicalDeclaration(n) {
 		return e.DeclarativeRecord.SetMutableBinding(n, val, strict)
 	}
 
@@ -201,7 +243,7 @@ func (e *GlobalEnvironment) SetMutableBinding(n lang.String, val lang.Value, str
 // it does not or is not currently writable, error handling is determined by the
 // value of struct. GetBindingValue is specified in 8.1.1.4.6.
 func (e *GlobalEnvironment) GetBindingValue(n lang.String, strict bool) (lang.Value, errors.Error) {
-	if e.DeclarativeRecord.HasBinding(n) {
+	if e.HasLexicalDeclaration(n) {
 		return e.DeclarativeRecord.GetBindingValue(n, strict)
 	}
 
@@ -210,13 +252,16 @@ func (e *GlobalEnvironment) GetBindingValue(n lang.String, strict bool) (lang.Va
 
 // DeleteBinding deletes a binding from thie environment. DeleteBinding can only
 // delete deletable bindings. DeleteBinding is specified in 8.1.1.4.7.
-func (e *GlobalEnvironment) DeleteBinding(n lang.String) bool {
-	if e.DeclarativeRecord.HasBinding(n) {
+func (e *GlobalEnvironment) DeleteBinding(n lang.String) (bool, errors.Error) {
+	if e.HasLexicalDeclaration(n) {
 		return e.DeclarativeRecord.DeleteBinding(n)
 	}
 
 	if lang.HasOwnProperty(e.ObjectRecord.bindingObject, lang.NewStringOrSymbol(n)) {
-		status := e.ObjectRecord.DeleteBinding(n)
+		status, err := e.ObjectRecord.DeleteBinding(n)
+		if err != nil {
+			return false, err
+		}
 		if status {
 			nVal := n.Value().(string)
 			for i, varName := range e.VarNames {
@@ -228,10 +273,10 @@ func (e *GlobalEnvironment) DeleteBinding(n lang.String) bool {
 			}
 		}
 
-		return status
+		return status, nil
 	}
 
-	return true
+	return true, nil
 }
 
 // HasThisBinding returns true.
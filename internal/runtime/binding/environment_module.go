@@ -1,6 +1,11 @@
 package binding
 
-import "github.com/gojisvm/gojis/internal/runtime/lang"
+import (
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+)
+
+var _ Environment = (*ModuleEnvironment)(nil)
 
 // ModuleEnvironment is a declarative environment that is used to represent the
 // outer scope of an ECMAScript Module. In addition to normal mutable and
@@ -21,6 +26,12 @@ func (e *ModuleEnvironment) CreateImportBinding(n lang.String, m interface{}, n2
 
 // GetThisBinding returns Undefined.
 // GetThisBinding is specified in 8.1.1.5.4.
-func (e *ModuleEnvironment) GetThisBinding() lang.Value {
-	return lang.Undefined
+func (e *ModuleEnvironment) GetThisBinding() (lang.Value, errors.Error) {
+	return lang.Undefined, nil
+}
+
+// HasThisBinding returns true.
+// HasThisBinding is specified in 8.1.1.5.3.
+func (e *ModuleEnvironment) HasThisBinding() bool {
+	return true
 }
@@ -1,6 +1,8 @@
 package binding
 
 import (
+	"fmt"
+
 	"github.com/gojisvm/gojis/internal/runtime/errors"
 	"github.com/gojisvm/gojis/internal/runtime/lang"
 )
@@ -30,6 +32,11 @@ type ObjectEnvironment struct {
 	outer Environment
 
 	bindingObject *lang.Object
+
+	// WithEnvironment is true if the environment was created for a with
+	// statement. If so, the binding object is provided as implicit this
+	// value for function calls.
+	WithEnvironment bool
 }
 
 // Outer returns the outer environment of this object environment.
@@ -37,6 +44,11 @@ func (e *ObjectEnvironment) Outer() Environment {
 	return e.outer
 }
 
+// BindingObject returns the binding object of this object environment.
+func (e *ObjectEnvironment) BindingObject() *lang.Object {
+	return e.bindingObject
+}
+
 // IsGlobalEnvironment returns false.
 func (e *ObjectEnvironment) IsGlobalEnvironment() bool {
 	return false
@@ -48,10 +60,36 @@ func (e *ObjectEnvironment) IsModuleEnvironment() bool {
 }
 
 // HasBinding determines if its associated binding object has a property whose
-// name is the value of the argument n.
+// name is the value of the argument n. If this is an environment of a with
+// statement, properties that are excluded by the @@unscopables property of
+// the binding object are not bound.
 // HasBinding is specified in 8.1.1.2.1.
-func (e *ObjectEnvironment) HasBinding(n lang.String) bool {
-	panic("#11: 8.1.1.2")
+func (e *ObjectEnvironment) HasBinding(n lang.String) (bool, errors.Error) {
+	bindings := e.bindingObject
+	key := lang.NewStringOrSymbol(n)
+	if !bindings.HasProperty(key) {
+		return false, nil
+	}
+
+	if !e.WithEnvironment {
+		return true, nil
+	}
+
+	unscopables, err := lang.Get(bindings, lang.NewStringOrSymbol(lang.SymbolUnscopables))
+	if err != nil {
+		return false, err
+	}
+	if unscopablesObj, ok := unscopables.(*lang.Object); ok {
+		blocked, err := lang.Get(unscopablesObj, key)
+		if err != nil {
+			return false, err
+		}
+		if lang.ToBoolean(blocked) {
+			return false, nil
+		}
+	}
+
+	return true, nil
 }
 
 // CreateMutableBinding creates in an Environment Record's associated binding
@@ -60,7 +98,9 @@ func (e *ObjectEnvironment) HasBinding(n lang.String) bool {
 // [[Configurable]] attribute is set to true; otherwise it is set to false.
 // CreateMutableBinding is specified in 8.1.1.2.2.
 func (e *ObjectEnvironment) CreateMutableBinding(n lang.String, deletable bool) errors.Error {
-	panic("#11: 8.1.1.2")
+	desc := lang.NewDataProperty(lang.Undefined, lang.True, lang.True, lang.Boolean(deletable))
+	_, err := lang.DefinePropertyOrThrow(e.bindingObject, lang.NewStringOrSymbol(n), desc)
+	return err
 }
 
 // CreateImmutableBinding is not described by the specification and will panic
@@ -76,7 +116,7 @@ func (e *ObjectEnvironment) CreateImmutableBinding(n lang.String, strict bool) e
 // already exist.
 // InitializeBinding is specified in 8.1.1.2.4.
 func (e *ObjectEnvironment) InitializeBinding(n lang.String, val lang.Value) errors.Error {
-	panic("#11: 8.1.1.2")
+	return e.SetMutableBinding(n, val, false)
 }
 
 // SetMutableBinding attempts to set the value of the Environment Record's
@@ -86,7 +126,13 @@ func (e *ObjectEnvironment) InitializeBinding(n lang.String, val lang.Value) err
 // the value of strict.
 // SetMutableBinding is specified in 8.1.1.2.5.
 func (e *ObjectEnvironment) SetMutableBinding(n lang.String, val lang.Value, strict bool) errors.Error {
-	panic("#11: 8.1.1.2")
+	key := lang.NewStringOrSymbol(n)
+	if !bool(e.bindingObject.HasProperty(key)) && strict {
+		return errors.NewReferenceError(fmt.Sprintf("%v is not defined", n))
+	}
+
+	_, err := lang.Set(e.bindingObject, key, val, strict)
+	return err
 }
 
 // GetThisBinding is not specified for ObjectEnvironment.
@@ -100,14 +146,22 @@ func (e *ObjectEnvironment) GetThisBinding() (lang.Value, errors.Error) {
 // the strict argument.
 // GetBindingValue is specified in 8.1.1.2.6.
 func (e *ObjectEnvironment) GetBindingValue(n lang.String, strict bool) (lang.Value, errors.Error) {
-	panic("#11: 8.1.1.2")
+	key := lang.NewStringOrSymbol(n)
+	if !e.bindingObject.HasProperty(key) {
+		if !strict {
+			return lang.Undefined, nil
+		}
+		return nil, errors.NewReferenceError(fmt.Sprintf("%v is not defined", n))
+	}
+
+	return lang.Get(e.bindingObject, key)
 }
 
 // DeleteBinding can only delete bindings that correspond to properties of the
 // environment object whose [[Configurable]] attribute have the value true.
 // DeleteBinding is specified in 8.1.1.2.7.
-func (e *ObjectEnvironment) DeleteBinding(n lang.String) bool {
-	panic("#11: 8.1.1.2")
+func (e *ObjectEnvironment) DeleteBinding(n lang.String) (bool, errors.Error) {
+	return bool(e.bindingObject.Delete(lang.NewStringOrSymbol(n))), nil
 }
 
 // HasThisBinding returns false.
@@ -126,7 +180,10 @@ func (e *ObjectEnvironment) HasSuperBinding() bool {
 // withEnvironment flag is true.
 // WithBaseObject is specified in 8.1.1.2.10.
 func (e *ObjectEnvironment) WithBaseObject() lang.Value {
-	panic("#11: 8.1.1.2")
+	if e.WithEnvironment {
+		return e.bindingObject
+	}
+	return lang.Undefined
 }
 
 // Type returns TypeInternal.
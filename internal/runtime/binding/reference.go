@@ -2,6 +2,7 @@ package binding
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/gojisvm/gojis/internal/runtime/errors"
 	"github.com/gojisvm/gojis/internal/runtime/lang"
@@ -101,29 +102,77 @@ func (r *Reference) IsSuperReference() bool {
 	return r.thisValue != nil
 }
 
-// GetValue returns the value of this reference.
+// Realm is the part of a realm record that is needed to evaluate
+// references. Primitive base values are converted to objects with the
+// intrinsics of the realm, and unresolvable references are set on its
+// global object.
+type Realm interface {
+	lang.Realm
+
+	// GlobalObject returns the global object of the realm.
+	GlobalObject() *lang.Object
+}
+
+// GetValue returns the value of this reference. The given realm is used
+// to convert a primitive base value to an object.
 // GetValue is specified in 6.2.4.8.
-func (r *Reference) GetValue() (lang.Value, errors.Error) {
+func (r *Reference) GetValue(realm Realm) (lang.Value, errors.Error) {
 	base := r.GetBase()
 
 	if r.IsUnresolvableReference() {
-		return nil, errors.NewReferenceError(fmt.Sprintf("Unresolvable reference: '%v'", r.referencedName))
+		return nil, errors.NewReferenceError(fmt.Sprintf("%v is not defined", r.referencedName.String()))
 	}
 
 	if r.IsPropertyReference() {
-		if r.HasPrimitiveBase() {
-			base = lang.ToObject(base)
+		baseObj, err := lang.ToObject(realm, base)
+		if err != nil {
+			return nil, err
 		}
-		panic("TODO: properties")
+		return baseObj.Get(r.GetReferencedName(), r.GetThisValue())
 	}
 
-	return base.Value().(Environment).GetBindingValue(r.GetReferencedName().String(), r.IsStrictReference())
+	return base.(Environment).GetBindingValue(r.GetReferencedName().String(), r.IsStrictReference())
 }
 
-// PutValue sets the value of this reference to the given one.
+// PutValue sets the value of this reference to the given one. If the
+// reference is unresolvable and not strict, the value is set on the global
+// object of the given realm.
 // PutValue is specified in 6.2.4.9.
-func (r *Reference) PutValue(lang.Value) errors.Error {
-	panic("TODO: 6.2.4.9 PutValue")
+func (r *Reference) PutValue(realm Realm, w lang.Value) errors.Error {
+	base := r.GetBase()
+
+	if r.IsUnresolvableReference() {
+		if r.IsStrictReference() {
+			return errors.NewReferenceError(fmt.Sprintf("%v is not defined", r.referencedName.String()))
+		}
+
+		_, err := lang.Set(realm.GlobalObject(), r.GetReferencedName(), w, false)
+		return err
+	}
+
+	if r.IsPropertyReference() {
+		baseObj, err := lang.ToObject(realm, base)
+		if err != nil {
+			return err
+		}
+
+		succeeded, err := baseObj.Set(r.GetReferencedName(), w, r.GetThisValue())
+		if err != nil {
+			return err
+		}
+		if !bool(succeeded) && r.IsStrictReference() {
+			return errors.NewTypeError(fmt.Sprintf("Cannot assign to read only property '%v' of %v", r.referencedName.String(), describe(base)))
+		}
+		return nil
+	}
+
+	return base.(Environment).SetMutableBinding(r.GetReferencedName().String(), w, r.IsStrictReference())
+}
+
+// describe returns a short description of the given base value for error
+// messages.
+func describe(base lang.Value) string {
+	return strings.ToLower(base.Type().String())
 }
 
 // GetThisValue returns the thisValue of this reference. If this reference is
@@ -0,0 +1,74 @@
+package runtime
+
+import "math"
+
+// BudgetExceeded is the value that the runtime panics with, if the
+// evaluation of ECMAScript code exceeds the step budget that was set with
+// Runtime#SetBudget. Like an *Interrupted, the panic unwinds the execution
+// context stack, without evaluating catch and finally blocks, and must be
+// recovered by the caller of the runtime.
+type BudgetExceeded struct {
+	// Steps is the number of steps that the evaluation had taken when it
+	// exceeded the budget.
+	Steps uint64
+}
+
+// budget counts the steps that the runtime has evaluated, and holds the
+// number of steps after which the evaluation is aborted.
+type budget struct {
+	steps   uint64
+	limited bool
+	limit   uint64
+}
+
+// SetBudget limits the evaluation to the given number of further steps. A
+// step is an iteration of a loop, a call of an ECMAScript function, or a
+// unit of the work of a built-in function, so the number of steps does not
+// depend on the engine. The evaluation panics with a *BudgetExceeded, when
+// it would take more steps than the budget allows. A budget that exceeds
+// the range of the step count is not limited.
+func (r *Runtime) SetBudget(steps uint64) {
+	limit := r.budget.steps + steps
+	if limit < steps {
+		limit = math.MaxUint64
+	}
+	r.budget.limited = true
+	r.budget.limit = limit
+}
+
+// ClearBudget removes the limit that was set with SetBudget.
+func (r *Runtime) ClearBudget() {
+	r.budget.limited = false
+	r.budget.limit = 0
+}
+
+// Steps returns the number of steps that the runtime has evaluated.
+func (r *Runtime) Steps() uint64 {
+	return r.budget.steps
+}
+
+// step counts a step of the evaluation. It panics with an *Interrupted, if
+// the evaluation has been interrupted, and with a *BudgetExceeded, if the
+// step exceeds the budget.
+func (r *Runtime) step() {
+	r.CheckInterrupt()
+	if r.budget.limited && r.budget.steps >= r.budget.limit {
+		panic(&BudgetExceeded{Steps: r.budget.steps})
+	}
+	r.budget.steps++
+}
+
+// work counts the work of a built-in function as the given number of
+// steps, see realm.Realm#Work. Like step, it panics with an *Interrupted
+// or a *BudgetExceeded. If the work exceeds the budget, the steps up to the
+// limit are counted.
+func (r *Runtime) work(n int) {
+	r.CheckInterrupt()
+	if r.budget.limited && r.budget.steps+uint64(n) > r.budget.limit {
+		if r.budget.steps < r.budget.limit {
+			r.budget.steps = r.budget.limit
+		}
+		panic(&BudgetExceeded{Steps: r.budget.steps})
+	}
+	r.budget.steps += uint64(n)
+}
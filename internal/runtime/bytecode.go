@@ -0,0 +1,266 @@
+package runtime
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gojisvm/gojis/internal/parser/ast"
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+)
+
+// opcode is the operation of a bytecode instruction. The comments describe
+// the operands a and b of the instruction, and the effect of the
+// instruction on the operand stack, with the top of the stack on the right.
+type opcode uint8
+
+// Available opcodes.
+const (
+	opNop opcode = iota
+
+	opConst     // a: value index; → v
+	opUndefined // → undefined
+	opPop       // v →
+	opDup       // v → v v
+	opDup2      // v w → v w v w
+	opSwap      // v w → w v
+
+	opLoadSlot         // a: slot; → v, throws if the slot is uninitialized
+	opStoreSlot        // a: slot; v → v, throws if the slot is uninitialized
+	opInitSlot         // a: slot; v →
+	opClearSlot        // a: slot; marks the slot as uninitialized
+	opThrowConstAssign // a: slot; throws a TypeError for assigning a constant
+	opLoadName         // a: name index; → v
+	opLoadNameThis     // a: name index; → f this
+	opTypeofName       // a: name index; → typeof v
+	opStoreName        // a: name index; v → v
+	opInitBinding      // a: name index; v →
+	opDeleteName       // a: name index; → bool
+	opThis             // → this
+	opNewTarget        // → new.target
+
+	opGetPropNamed   // a: key index; obj → v
+	opGetProp        // obj key → v
+	opMakeKey        // obj key → obj k
+	opCheckBase      // a: key index; obj → obj
+	opGetPropKey     // obj k → v
+	opSetPropNamed   // a: key index; obj v → v
+	opSetProp        // obj k v → v
+	opDeleteProp     // obj key → bool
+	opGetMethodNamed // a: key index; obj → f obj
+	opGetMethod      // obj key → f obj
+	opCall           // a: call site; f this args... → v
+	opCallSpread     // a: call site; f this list → v
+	opNew            // a: call site; f args... → v
+	opNewSpread      // a: call site; f list → v
+	opEvalExpr       // a: node index; → v, evaluated by the tree-walking evaluator
+	opEvalStmt       // a: node index; evaluated by the tree-walking evaluator
+	opClosure        // a: closure index; → f
+	opClosureKeyed   // a: closure index; k → k f, the function is named by k
+	opFunctionDecl   // a: node index; → f
+	opClass          // a: node index; → class
+	opBindPattern    // a: pattern index; v →
+	opNewObject      // → obj
+	opDefineField    // a: key index; obj v → obj
+	opToPropertyKey  // v → k
+	opDefineComputed // obj k v → obj
+	opDefineMethod   // a: node index; obj → obj
+	opSetProto       // obj v → obj
+	opCopyData       // obj v → obj
+	opList           // → list
+	opListPush       // list v → list
+	opListHole       // list → list
+	opListSpread     // list v → list, appends the values produced by iterating v
+	opListToArray    // list → array
+
+	opAdd // v w → v+w
+	opSub
+	opMul
+	opDiv
+	opMod
+	opExp
+	opShl
+	opShr
+	opUshr
+	opBitAnd
+	opBitOr
+	opBitXor
+	opLt
+	opGt
+	opLe
+	opGe
+	opEq
+	opNe
+	opStrictEq
+	opStrictNe
+	opInstanceof
+	opIn
+
+	opNot      // v → !v
+	opNeg      // v → -v
+	opToNumber // v → +v
+	opBitNot   // v → ~v
+	opTypeof   // v → typeof v
+	opInc      // n → n+1
+	opDec      // n → n-1
+	opToString // v → string
+	opConcat   // a: count; s... → s
+
+	opJump            // a: target
+	opJumpIfFalse     // a: target; v →
+	opJumpIfTrue      // a: target; v →
+	opJumpIfFalseKeep // a: target; v → v if jumping, v → otherwise
+	opJumpIfTrueKeep  // a: target; v → v if jumping, v → otherwise
+
+	opPushScope // a: scope index; pushes a declarative environment
+	opPopScope  // restores the outer environment
+	opCopyScope // a: scope index; replaces the environment with a copy
+	opWith      // v →, pushes an object environment for v
+
+	opTry         // a: catch target, b: finally target, one of them is -1
+	opPopTry      // removes the innermost exception handler
+	opPushPending // a: jump target or -1; → pending completion
+	opEndFinally  // pending completion →, resumes the completion
+	opThrow       // v →, throws v
+	opSetReturn   // v →, sets the return value
+	opReturn      // returns the return value
+
+	opSetCompletion       // v →, sets the completion value of the script
+	opCompletionUndefined // sets the completion value of the script to undefined
+
+	opGetIterator        // a: slot; v →
+	opIteratorNext       // a: slot, b: target if done; → v
+	opIteratorClose      // a: slot
+	opIteratorCloseThrow // a: slot; exception →, rethrows the exception
+	opEnumerate          // a: slot; v →
+	opEnumerateNext      // a: slot, b: target if done; → key
+)
+
+var opcodeNames = [...]string{
+	opNop: "nop", opConst: "const", opUndefined: "undefined", opPop: "pop", opDup: "dup", opDup2: "dup2", opSwap: "swap",
+	opLoadSlot: "load_slot", opStoreSlot: "store_slot", opInitSlot: "init_slot", opClearSlot: "clear_slot",
+	opThrowConstAssign: "throw_const_assign", opLoadName: "load_name", opLoadNameThis: "load_name_this",
+	opTypeofName: "typeof_name", opStoreName: "store_name", opInitBinding: "init_binding", opDeleteName: "delete_name",
+	opThis: "this", opNewTarget: "new_target",
+	opGetPropNamed: "get_prop_named", opGetProp: "get_prop", opMakeKey: "make_key", opCheckBase: "check_base",
+	opGetPropKey: "get_prop_key", opSetPropNamed: "set_prop_named", opSetProp: "set_prop", opDeleteProp: "delete_prop",
+	opGetMethodNamed: "get_method_named", opGetMethod: "get_method", opCall: "call", opCallSpread: "call_spread",
+	opNew: "new", opNewSpread: "new_spread", opEvalExpr: "eval_expr", opEvalStmt: "eval_stmt", opClosure: "closure", opClosureKeyed: "closure_keyed",
+	opBindPattern: "bind_pattern", opNewObject: "new_object",
+	opDefineField: "define_field", opToPropertyKey: "to_property_key", opDefineComputed: "define_computed", opDefineMethod: "define_method",
+	opSetProto: "set_proto", opCopyData: "copy_data", opList: "list", opListPush: "list_push",
+	opListHole: "list_hole", opListSpread: "list_spread", opListToArray: "list_to_array",
+	opAdd: "add", opSub: "sub", opMul: "mul", opDiv: "div", opMod: "mod", opExp: "exp", opShl: "shl", opShr: "shr",
+	opUshr: "ushr", opBitAnd: "bit_and", opBitOr: "bit_or", opBitXor: "bit_xor", opLt: "lt", opGt: "gt", opLe: "le",
+	opGe: "ge", opEq: "eq", opNe: "ne", opStrictEq: "strict_eq", opStrictNe: "strict_ne", opInstanceof: "instanceof",
+	opIn:  "in",
+	opNot: "not", opNeg: "neg", opToNumber: "to_number", opBitNot: "bit_not", opTypeof: "typeof", opInc: "inc",
+	opDec: "dec", opToString: "to_string", opConcat: "concat",
+	opJump: "jump", opJumpIfFalse: "jump_if_false", opJumpIfTrue: "jump_if_true",
+	opJumpIfFalseKeep: "jump_if_false_keep", opJumpIfTrueKeep: "jump_if_true_keep",
+	opPushScope: "push_scope", opPopScope: "pop_scope", opCopyScope: "copy_scope", opWith: "with",
+	opTry: "try", opPopTry: "pop_try", opPushPending: "push_pending", opEndFinally: "end_finally", opThrow: "throw",
+	opSetReturn: "set_return", opReturn: "return",
+	opSetCompletion: "set_completion", opCompletionUndefined: "completion_undefined",
+	opGetIterator: "get_iterator", opIteratorNext: "iterator_next", opIteratorClose: "iterator_close",
+	opIteratorCloseThrow: "iterator_close_throw", opEnumerate: "enumerate", opEnumerateNext: "enumerate_next",
+}
+
+func (op opcode) String() string {
+	return opcodeNames[op]
+}
+
+// binaryOperators maps the opcodes of binary operations to their operator.
+var binaryOperators = map[opcode]string{
+	opAdd: "+", opSub: "-", opMul: "*", opDiv: "/", opMod: "%", opExp: "**",
+	opShl: "<<", opShr: ">>", opUshr: ">>>", opBitAnd: "&", opBitOr: "|", opBitXor: "^",
+	opLt: "<", opGt: ">", opLe: "<=", opGe: ">=", opEq: "==", opNe: "!=", opStrictEq: "===", opStrictNe: "!==",
+	opInstanceof: "instanceof", opIn: "in",
+}
+
+// instruction is a single bytecode instruction with up to two operands.
+type instruction struct {
+	op   opcode
+	a, b int
+}
+
+// callSite holds the argument count and the callee expression of a call,
+// which is used in error messages.
+type callSite struct {
+	argc   int
+	callee ast.Node
+}
+
+// closureSite holds a function or class expression, and the name it gets
+// by named evaluation, if any.
+type closureSite struct {
+	node    ast.Node
+	name    lang.StringOrSymbol
+	hasName bool
+}
+
+// patternSite holds a binding pattern or assignment target, that is bound
+// by the tree-walking evaluator. If lexical is true, the bindings are
+// initialized in the running lexical environment, otherwise they are
+// assigned.
+type patternSite struct {
+	node    ast.Node
+	lexical bool
+}
+
+// scopeBinding is a binding of a declarative environment created by a
+// scope.
+type scopeBinding struct {
+	name     lang.String
+	constant bool
+}
+
+// scopeInfo describes the declarative environment that is created when a
+// block is entered, and the function declarations instantiated in it.
+type scopeInfo struct {
+	bindings  []scopeBinding
+	functions []*ast.FunctionDeclaration
+}
+
+// prologueBinding is a binding created by FunctionDeclarationInstantiation,
+// whose value is copied to a slot before the body of a function is
+// executed.
+type prologueBinding struct {
+	name lang.String
+	slot int
+}
+
+// code is the compiled bytecode of a script or a function body. Code is
+// immutable once compiled.
+type code struct {
+	instructions []instruction
+	// positions holds the position in the source code of each
+	// instruction, for stack traces.
+	positions []ast.Position
+
+	values   []lang.Value
+	names    []lang.String
+	keys     []lang.StringOrSymbol
+	nodes    []ast.Node
+	calls    []callSite
+	closures []closureSite
+	patterns []patternSite
+	scopes   []*scopeInfo
+
+	// slotNames holds the names of the slots, for error messages. Hidden
+	// slots, which are used for temporary values, have an empty name.
+	slotNames []string
+	prologue  []prologueBinding
+
+	// script is true for the code of a script, whose completion value is
+	// the result of the evaluation, rather than the return value.
+	script bool
+}
+
+// String returns a human readable listing of the instructions.
+func (c *code) String() string {
+	var b strings.Builder
+	for pc, ins := range c.instructions {
+		fmt.Fprintf(&b, "%4d %v %v %v\n", pc, ins.op, ins.a, ins.b)
+	}
+	return b.String()
+}
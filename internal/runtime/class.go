@@ -0,0 +1,157 @@
+package runtime
+
+import (
+	"fmt"
+
+	"github.com/gojisvm/gojis/internal/parser/ast"
+	"github.com/gojisvm/gojis/internal/runtime/binding"
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+	"github.com/gojisvm/gojis/internal/runtime/realm"
+)
+
+// evaluateClassDeclaration creates the class of the given class
+// declaration, and initializes its binding in the running lexical
+// environment.
+// BindingClassDeclarationEvaluation is specified in 14.6.14.
+func (r *Runtime) evaluateClassDeclaration(n *ast.ClassDeclaration) lang.Completion {
+	className := n.ID.Name
+	value, err := r.classDefinitionEvaluation(className, lang.NewStringKey(className), &n.Class)
+	if err != nil {
+		return r.throw(err)
+	}
+	if err := r.lexicalEnvironment().InitializeBinding(lang.NewString(className), value); err != nil {
+		return r.throw(err)
+	}
+	return lang.NormalCompletion(nil)
+}
+
+// evaluateClassExpression creates the class of the given class expression.
+// If the class expression has no name, the given name is used as name of
+// the class.
+// The evaluation of ClassExpression is specified in 14.6.16.
+func (r *Runtime) evaluateClassExpression(n *ast.ClassExpression, name lang.StringOrSymbol) (lang.Value, errors.Error) {
+	var classBinding string
+	if n.ID != nil {
+		classBinding = n.ID.Name
+		name = lang.NewStringKey(classBinding)
+	}
+	return r.classDefinitionEvaluation(classBinding, name, &n.Class)
+}
+
+// classDefinitionEvaluation creates the constructor and the prototype of
+// the given class. If classBinding is not empty, the class is bound to that
+// name in the scope of the class. The constructor function gets the given
+// name. All parts of a class are strict mode code.
+// ClassDefinitionEvaluation is specified in 14.6.13.
+func (r *Runtime) classDefinitionEvaluation(classBinding string, className lang.StringOrSymbol, class *ast.Class) (*lang.Object, errors.Error) {
+	strict := r.strict
+	r.strict = true
+	defer func() { r.strict = strict }()
+
+	lex := r.lexicalEnvironment()
+	classScope := binding.NewDeclarativeEnvironment(lex)
+	if classBinding != "" {
+		_ = classScope.CreateImmutableBinding(lang.NewString(classBinding), true)
+	}
+
+	var protoParent lang.Value = r.intrinsic(realm.IntrinsicNameObjectPrototype)
+	constructorParent := r.intrinsic(realm.IntrinsicNameFunctionPrototype)
+	if class.SuperClass != nil {
+		r.setLexicalEnvironment(classScope)
+		superclass, err := r.evaluate(class.SuperClass)
+		r.setLexicalEnvironment(lex)
+		if err != nil {
+			return nil, err
+		}
+
+		if superclass == lang.Null {
+			protoParent = lang.Null
+		} else if !lang.InternalIsConstructor(superclass) {
+			return nil, errors.NewTypeError(fmt.Sprintf("Class extends value %v is not a constructor or null", describe(superclass)))
+		} else {
+			superObj := superclass.(*lang.Object)
+			if protoParent, err = lang.Get(superObj, lang.NewStringKey("prototype")); err != nil {
+				return nil, err
+			}
+			if protoParent.Type() != lang.TypeObject && protoParent != lang.Null {
+				return nil, errors.NewTypeError(fmt.Sprintf("Class extends value does not have valid prototype property %v", describe(protoParent)))
+			}
+			constructorParent = superObj
+		}
+	}
+
+	proto := lang.ObjectCreate(protoParent)
+	// the prototype may be null, but the instances are accounted to the
+	// heap of the realm
+	proto.SetHeap(r.Realm().Heap())
+	constructor := constructorMethod(class.Body)
+	var code *ast.Function
+	if constructor != nil {
+		code = &constructor.Value.Function
+	} else {
+		code = defaultConstructor(class.SuperClass != nil, *class.Body.Loc())
+	}
+
+	r.setLexicalEnvironment(classScope)
+	defer r.setLexicalEnvironment(lex)
+
+	f := r.functionAllocate(constructorParent, true, functionKindClassConstructor)
+	f.initialize(code, classScope, false)
+	f.makeMethod(proto)
+	if class.SuperClass != nil {
+		f.constructorKind = constructorKindDerived
+	}
+	lang.MakeConstructor(r.Realm(), f.object, false, proto)
+	lang.SetFunctionName(f.object, className, "")
+	_, _ = lang.CreateMethodProperty(proto, lang.NewStringKey("constructor"), f.object)
+
+	for _, m := range class.Body.Body {
+		if m.Kind == ast.KindConstructor {
+			continue
+		}
+
+		target := proto
+		if m.Static {
+			target = f.object
+		}
+		if err := r.methodDefinitionEvaluation(m.Key, m.Computed, m.Kind, m.Value, target, false); err != nil {
+			return nil, err
+		}
+	}
+
+	if classBinding != "" {
+		_ = classScope.InitializeBinding(lang.NewString(classBinding), f.object)
+	}
+	return f.object, nil
+}
+
+// defaultConstructor returns the code of the constructor of a class that
+// does not define one, which is constructor(...args){ super(...args); } for
+// derived classes and constructor(){} otherwise. All nodes of the code are
+// located at the given location of the class body, which is used in stack
+// traces.
+func defaultConstructor(derived bool, loc ast.Location) *ast.Function {
+	code := &ast.Function{
+		Body:   &ast.BlockStatement{Location: loc},
+		Strict: true,
+	}
+	if derived {
+		args := &ast.Identifier{Location: loc, Name: "args"}
+		code.Params = []ast.Pattern{&ast.RestElement{Location: loc, Argument: args}}
+		code.Body = &ast.BlockStatement{
+			Location: loc,
+			Body: []ast.Statement{
+				&ast.ExpressionStatement{
+					Location: loc,
+					Expression: &ast.CallExpression{
+						Location:  loc,
+						Callee:    &ast.Super{Location: loc},
+						Arguments: []ast.Expression{&ast.SpreadElement{Location: loc, Argument: args}},
+					},
+				},
+			},
+		}
+	}
+	return code
+}
@@ -0,0 +1,1308 @@
+package runtime
+
+import (
+	"strings"
+
+	"github.com/gojisvm/gojis/internal/parser/ast"
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+)
+
+// The compiler translates the body of a script or function to bytecode,
+// which is executed by the interpreter in interpreter.go. Bindings that can
+// only be referenced by the compiled code itself are resolved to slots of
+// the frame of the interpreter at compile time, so that no environment
+// lookup is needed to access them. Bindings that may be referenced by other
+// code, like nested functions, or by constructs that are evaluated by the
+// tree-walking evaluator, live in environments as usual.
+
+// operatorOpcodes maps binary operators to the opcodes that apply them.
+var operatorOpcodes = make(map[string]opcode)
+
+func init() {
+	for op, operator := range binaryOperators {
+		operatorOpcodes[operator] = op
+	}
+}
+
+// slotRef describes where a binding that is known to the compiler is
+// stored. If slot is -1, the binding is stored in an environment.
+type slotRef struct {
+	slot     int
+	constant bool
+}
+
+// compileScope holds the bindings that are declared in a scope of the
+// compiled code.
+type compileScope struct {
+	outer *compileScope
+	names map[string]slotRef
+}
+
+// controlKind is the kind of a statement that has to be considered when
+// control is transferred out of it by break, continue or return.
+type controlKind uint8
+
+// Available control kinds.
+const (
+	controlLoop controlKind = iota
+	controlSwitch
+	controlLabel
+	controlScope
+	controlTry
+	controlFinally
+)
+
+// exitKind is the kind of a jump out of statements.
+type exitKind uint8
+
+// Available exit kinds.
+const (
+	exitBreak exitKind = iota
+	exitContinue
+	exitReturn
+)
+
+// control is an entry of the stack of statements that enclose the
+// statement that is being compiled.
+type control struct {
+	outer *control
+	kind  controlKind
+
+	labels    []string
+	breaks    []int
+	continues []int
+
+	// iterator is the slot of the iterator of a for-of loop, which is
+	// closed when the loop is left, or -1.
+	iterator int
+
+	// finally is true for a try statement with a finally block, and
+	// finallyJumps holds the jumps to the finally block of the statement.
+	finally      bool
+	finallyJumps []int
+}
+
+// matches returns whether the statement is the target of the given break
+// or continue.
+func (e *control) matches(kind exitKind, label string) bool {
+	switch kind {
+	case exitBreak:
+		if label == "" {
+			return e.kind == controlLoop || e.kind == controlSwitch
+		}
+		return (e.kind == controlLoop || e.kind == controlSwitch || e.kind == controlLabel) && containsString(e.labels, label)
+	case exitContinue:
+		return e.kind == controlLoop && (label == "" || containsString(e.labels, label))
+	}
+	return false
+}
+
+type compiler struct {
+	code    *code
+	scope   *compileScope
+	control *control
+
+	names map[string]int
+
+	// escaping holds the names of the identifiers that are referenced by
+	// code that is not compiled, so that bindings with these names must be
+	// stored in environments.
+	escaping map[string]bool
+	// slots is false if no binding may be stored in a slot, because the
+	// code contains with statements or calls eval.
+	slots bool
+	// position is the position of the instructions that are emitted, which
+	// is the start of the innermost statement, call or new expression that
+	// is compiled.
+	position ast.Position
+}
+
+func newCompiler(script bool) *compiler {
+	c := new(compiler)
+	c.code = new(code)
+	c.code.script = script
+	c.scope = &compileScope{names: make(map[string]slotRef)}
+	c.names = make(map[string]int)
+	c.escaping = make(map[string]bool)
+	c.slots = true
+	return c
+}
+
+// compileScript compiles the body of the given script. The top-level
+// declarations of the script are instantiated by
+// GlobalDeclarationInstantiation, so they are never stored in slots.
+func compileScript(script *ast.Program) *code {
+	c := newCompiler(true)
+	c.position = script.Loc().Start
+	for _, stmt := range script.Body {
+		c.analyze(stmt)
+	}
+	c.compileStatements(script.Body)
+	return c.code
+}
+
+// compileFunction compiles the body of the given function. The code runs
+// after FunctionDeclarationInstantiation, and copies the values of the
+// parameters and var declarations that are stored in slots from the
+// environments of the function.
+func compileFunction(fn *ast.Function) *code {
+	c := newCompiler(false)
+	c.position = fn.Body.Loc().Start
+	for _, p := range fn.Params {
+		c.analyze(p)
+	}
+	c.analyze(fn.Body)
+
+	body, ok := fn.Body.(*ast.BlockStatement)
+	if !ok {
+		// concise body of an arrow function
+		c.compileExpression(fn.Body)
+		c.emit(opSetReturn, 0, 0)
+		c.emit(opReturn, 0, 0)
+		return c.code
+	}
+
+	varNames := parameterNames(fn.Params)
+	if c.escaping["arguments"] && !fn.Strict && isSimpleParameterList(fn.Params) {
+		// the parameters are aliased by the properties of a mapped
+		// arguments object
+		for _, name := range varNames {
+			c.escaping[name] = true
+		}
+	}
+	for _, d := range varScopedDeclarations(body.Body, true) {
+		if _, ok := d.(*ast.VariableDeclaration); ok {
+			varNames = append(varNames, boundNames(d)...)
+		}
+	}
+	for _, name := range varNames {
+		if _, ok := c.scope.names[name]; ok || !c.slotted(name) {
+			continue
+		}
+		slot := c.newSlot(name)
+		c.scope.names[name] = slotRef{slot: slot}
+		c.code.prologue = append(c.code.prologue, prologueBinding{lang.NewString(name), slot})
+	}
+	for _, d := range lexicallyScopedDeclarations(body.Body, true) {
+		for _, name := range boundNames(d) {
+			if c.slotted(name) {
+				// the slot is uninitialized until the declaration is
+				// evaluated
+				c.scope.names[name] = slotRef{slot: c.newSlot(name), constant: isConstantDeclaration(d)}
+			}
+		}
+	}
+
+	c.compileStatements(body.Body)
+	c.emit(opReturn, 0, 0)
+	return c.code
+}
+
+// analyze determines the names of the bindings that are referenced by code
+// that is not compiled, and whether slots can be used at all.
+func (c *compiler) analyze(n ast.Node) {
+	ast.Inspect(n, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case nil:
+			return false
+		case *ast.WithStatement:
+			c.slots = false
+		case *ast.Identifier:
+			switch n.Name {
+			case "eval":
+				c.slots = false
+			case "arguments":
+				c.escaping[n.Name] = true
+			}
+		case *ast.ForInStatement:
+			c.analyzeForInOfTarget(n.Left)
+		case *ast.ForOfStatement:
+			c.analyzeForInOfTarget(n.Left)
+		}
+		if isFallback(n) {
+			c.escape(n)
+			return false
+		}
+		return true
+	})
+}
+
+// analyzeForInOfTarget marks the names in the given left hand side of a
+// for-in or for-of loop as escaping, if the target is a property, which is
+// assigned by the tree-walking evaluator.
+func (c *compiler) analyzeForInOfTarget(left ast.Node) {
+	if _, ok := left.(*ast.MemberExpression); ok {
+		c.escape(left)
+	}
+}
+
+// escape marks the names of all identifiers in the given node as escaping.
+func (c *compiler) escape(n ast.Node) {
+	ast.Inspect(n, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Identifier); ok {
+			c.escaping[id.Name] = true
+			if id.Name == "eval" {
+				c.slots = false
+			}
+		}
+		return n != nil
+	})
+}
+
+// slotted returns whether a binding with the given name is stored in a
+// slot.
+func (c *compiler) slotted(name string) bool {
+	return c.slots && !c.escaping[name]
+}
+
+// isFallback returns whether the given node is evaluated by the tree-walking
+// evaluator rather than compiled. Function and class definitions capture the
+// running lexical environment, and the remaining nodes are rare enough that
+// compiling them is not worth it.
+func isFallback(n ast.Node) bool {
+	switch n := n.(type) {
+	case *ast.FunctionExpression, *ast.ArrowFunctionExpression, *ast.ClassExpression,
+		*ast.FunctionDeclaration, *ast.ClassDeclaration, *ast.TaggedTemplateExpression,
+		*ast.YieldExpression, *ast.AwaitExpression, *ast.Super,
+		*ast.ObjectPattern, *ast.ArrayPattern, *ast.AssignmentPattern:
+		return true
+	case *ast.Literal:
+		return n.Regex != nil
+	case *ast.Property:
+		return n.Method || n.Kind == ast.KindGet || n.Kind == ast.KindSet
+	case *ast.MemberExpression:
+		return isSuper(n.Object)
+	case *ast.CallExpression:
+		return isSuper(n.Callee) || isSuperProperty(n.Callee)
+	case *ast.AssignmentExpression:
+		if n.Operator != "=" {
+			if _, ok := operatorOpcodes[strings.TrimSuffix(n.Operator, "=")]; !ok {
+				return true
+			}
+		}
+		return isSuperProperty(n.Left)
+	case *ast.UpdateExpression:
+		return isSuperProperty(n.Argument)
+	case *ast.UnaryExpression:
+		return isSuperProperty(n.Argument)
+	case *ast.BinaryExpression:
+		_, ok := operatorOpcodes[n.Operator]
+		return !ok
+	case *ast.LogicalExpression:
+		return n.Operator != "&&" && n.Operator != "||"
+	case *ast.ForOfStatement:
+		return n.Await
+	}
+	return false
+}
+
+func isSuper(n ast.Node) bool {
+	_, ok := n.(*ast.Super)
+	return ok
+}
+
+func isSuperProperty(n ast.Node) bool {
+	member, ok := n.(*ast.MemberExpression)
+	return ok && isSuper(member.Object)
+}
+
+func (c *compiler) pc() int {
+	return len(c.code.instructions)
+}
+
+func (c *compiler) emit(op opcode, a, b int) int {
+	c.code.instructions = append(c.code.instructions, instruction{op, a, b})
+	c.code.positions = append(c.code.positions, c.position)
+	return len(c.code.instructions) - 1
+}
+
+// enter sets the position of the instructions that are emitted to the
+// start of the given node, and returns the previous position, which is
+// restored by leave.
+func (c *compiler) enter(n ast.Node) ast.Position {
+	pos := c.position
+	c.position = n.Loc().Start
+	return pos
+}
+
+// leave restores the given position, which was returned by enter.
+func (c *compiler) leave(pos ast.Position) {
+	c.position = pos
+}
+
+// patch sets the target of the jump at the given position to the current
+// position.
+func (c *compiler) patch(pc int) {
+	c.code.instructions[pc].a = c.pc()
+}
+
+func (c *compiler) value(v lang.Value) int {
+	c.code.values = append(c.code.values, v)
+	return len(c.code.values) - 1
+}
+
+func (c *compiler) name(name string) int {
+	if i, ok := c.names[name]; ok {
+		return i
+	}
+	c.code.names = append(c.code.names, lang.NewString(name))
+	c.names[name] = len(c.code.names) - 1
+	return c.names[name]
+}
+
+func (c *compiler) key(k lang.StringOrSymbol) int {
+	c.code.keys = append(c.code.keys, k)
+	return len(c.code.keys) - 1
+}
+
+func (c *compiler) node(n ast.Node) int {
+	c.code.nodes = append(c.code.nodes, n)
+	return len(c.code.nodes) - 1
+}
+
+// newSlot allocates a slot for a binding with the given name, or a hidden
+// slot for temporary values if the name is empty.
+func (c *compiler) newSlot(name string) int {
+	c.code.slotNames = append(c.code.slotNames, name)
+	return len(c.code.slotNames) - 1
+}
+
+func (c *compiler) pushControl(kind controlKind, labels []string) *control {
+	c.control = &control{outer: c.control, kind: kind, labels: labels, iterator: -1}
+	return c.control
+}
+
+// popControl removes the innermost control entry, and patches its breaks
+// to jump to the current position, and its continues to jump to the given
+// target.
+func (c *compiler) popControl(continueTarget int) {
+	e := c.control
+	c.control = e.outer
+	for _, pc := range e.breaks {
+		c.patch(pc)
+	}
+	for _, pc := range e.continues {
+		c.code.instructions[pc].a = continueTarget
+	}
+}
+
+// completionUndefined sets the completion value of a script to undefined,
+// which is done by statements whose completion value is never empty.
+func (c *compiler) completionUndefined() {
+	if c.code.script {
+		c.emit(opCompletionUndefined, 0, 0)
+	}
+}
+
+// enterScope enters a scope with the given lexically scoped declarations.
+// If any of the bindings is stored in an environment, a declarative
+// environment is pushed, and the index of its scope is returned. Otherwise
+// -1 is returned.
+func (c *compiler) enterScope(decls []ast.Node) int {
+	c.scope = &compileScope{outer: c.scope, names: make(map[string]slotRef)}
+	info := new(scopeInfo)
+	for _, d := range decls {
+		constant := isConstantDeclaration(d)
+		for _, name := range boundNames(d) {
+			if c.slotted(name) {
+				slot := c.newSlot(name)
+				c.scope.names[name] = slotRef{slot: slot, constant: constant}
+				c.emit(opClearSlot, slot, 0)
+				continue
+			}
+			c.scope.names[name] = slotRef{slot: -1}
+			info.bindings = append(info.bindings, scopeBinding{lang.NewString(name), constant})
+		}
+		if f, ok := d.(*ast.FunctionDeclaration); ok {
+			info.functions = append(info.functions, f)
+		}
+	}
+	if len(info.bindings) == 0 {
+		return -1
+	}
+
+	c.code.scopes = append(c.code.scopes, info)
+	c.emit(opPushScope, len(c.code.scopes)-1, 0)
+	c.pushControl(controlScope, nil)
+	return len(c.code.scopes) - 1
+}
+
+// leaveScope leaves the innermost scope, which was entered by enterScope
+// and returned the given scope index.
+func (c *compiler) leaveScope(scope int) {
+	c.scope = c.scope.outer
+	if scope >= 0 {
+		c.popControl(-1)
+		c.emit(opPopScope, 0, 0)
+	}
+}
+
+func (c *compiler) lookup(name string) slotRef {
+	for s := c.scope; s != nil; s = s.outer {
+		if ref, ok := s.names[name]; ok {
+			return ref
+		}
+	}
+	return slotRef{slot: -1}
+}
+
+// load pushes the value of the binding with the given name.
+func (c *compiler) load(name string) {
+	if ref := c.lookup(name); ref.slot >= 0 {
+		c.emit(opLoadSlot, ref.slot, 0)
+		return
+	}
+	c.emit(opLoadName, c.name(name), 0)
+}
+
+// store assigns the value on top of the stack to the binding with the
+// given name, and leaves the value on the stack.
+func (c *compiler) store(name string) {
+	ref := c.lookup(name)
+	switch {
+	case ref.slot < 0:
+		c.emit(opStoreName, c.name(name), 0)
+	case ref.constant:
+		c.emit(opThrowConstAssign, ref.slot, 0)
+	default:
+		c.emit(opStoreSlot, ref.slot, 0)
+	}
+}
+
+// initialize pops the value on top of the stack and initializes the
+// binding with the given name with it. Bindings of var declarations are
+// assigned instead.
+func (c *compiler) initialize(name string, lexical bool) {
+	if ref := c.lookup(name); ref.slot >= 0 {
+		c.emit(opInitSlot, ref.slot, 0)
+		return
+	}
+	if lexical {
+		c.emit(opInitBinding, c.name(name), 0)
+		return
+	}
+	c.emit(opStoreName, c.name(name), 0)
+	c.emit(opPop, 0, 0)
+}
+
+// bind pops the value on top of the stack and binds it to the given
+// identifier or binding pattern.
+func (c *compiler) bind(target ast.Node, lexical bool) {
+	if id, ok := target.(*ast.Identifier); ok {
+		c.initialize(id.Name, lexical)
+		return
+	}
+	c.code.patterns = append(c.code.patterns, patternSite{target, lexical})
+	c.emit(opBindPattern, len(c.code.patterns)-1, 0)
+}
+
+// exit emits the code that transfers control out of the enclosing
+// statements to the target of the given break or continue, or out of the
+// function for a return. Environments are popped, iterators are closed and
+// finally blocks are run on the way.
+func (c *compiler) exit(kind exitKind, label string) {
+	for e := c.control; e != nil; e = e.outer {
+		if e.matches(kind, label) {
+			if kind == exitContinue {
+				e.continues = append(e.continues, c.emit(opJump, -1, 0))
+				return
+			}
+			if e.iterator >= 0 {
+				c.emit(opIteratorClose, e.iterator, 0)
+			}
+			e.breaks = append(e.breaks, c.emit(opJump, -1, 0))
+			return
+		}
+
+		switch e.kind {
+		case controlScope:
+			c.emit(opPopScope, 0, 0)
+		case controlFinally:
+			// discard the pending completion of the finally block
+			c.emit(opPop, 0, 0)
+		case controlLoop:
+			if e.iterator >= 0 {
+				c.emit(opIteratorClose, e.iterator, 0)
+			}
+		case controlTry:
+			c.emit(opPopTry, 0, 0)
+			if e.finally {
+				// run the finally block, which resumes after the jump
+				c.emit(opPushPending, c.pc()+2, 0)
+				e.finallyJumps = append(e.finallyJumps, c.emit(opJump, -1, 0))
+			}
+		}
+	}
+	c.emit(opReturn, 0, 0)
+}
+
+func (c *compiler) compileStatements(list []ast.Statement) {
+	for _, stmt := range list {
+		c.compileStatement(stmt)
+	}
+}
+
+func (c *compiler) compileStatement(n ast.Statement) {
+	defer c.leave(c.enter(n))
+
+	switch n := n.(type) {
+	case *ast.ExpressionStatement:
+		c.compileExpression(n.Expression)
+		if c.code.script {
+			c.emit(opSetCompletion, 0, 0)
+		} else {
+			c.emit(opPop, 0, 0)
+		}
+	case *ast.VariableDeclaration:
+		c.compileVariableDeclaration(n)
+	case *ast.FunctionDeclaration:
+		// functions are instantiated by the declaration instantiation of
+		// the enclosing scope, but functions in blocks may assign their
+		// value to a var binding as well
+		if c.scope.outer != nil {
+			c.emit(opEvalStmt, c.node(n), 0)
+		}
+	case *ast.EmptyStatement, *ast.DebuggerStatement:
+	case *ast.ClassDeclaration:
+		c.emit(opEvalStmt, c.node(n), 0)
+	case *ast.BlockStatement:
+		c.compileBlock(n)
+	case *ast.IfStatement:
+		c.completionUndefined()
+		c.compileExpression(n.Test)
+		alternate := c.emit(opJumpIfFalse, -1, 0)
+		c.compileStatement(ifClause(n.Consequent))
+		if n.Alternate != nil {
+			end := c.emit(opJump, -1, 0)
+			c.patch(alternate)
+			c.compileStatement(ifClause(n.Alternate))
+			c.patch(end)
+		} else {
+			c.patch(alternate)
+		}
+	case *ast.ReturnStatement:
+		if n.Argument != nil {
+			c.compileExpression(n.Argument)
+		} else {
+			c.emit(opUndefined, 0, 0)
+		}
+		c.emit(opSetReturn, 0, 0)
+		c.exit(exitReturn, "")
+	case *ast.ThrowStatement:
+		c.compileExpression(n.Argument)
+		c.emit(opThrow, 0, 0)
+	case *ast.BreakStatement:
+		c.exit(exitBreak, labelName(n.Label))
+	case *ast.ContinueStatement:
+		c.exit(exitContinue, labelName(n.Label))
+	case *ast.WithStatement:
+		c.completionUndefined()
+		c.compileExpression(n.Object)
+		c.emit(opWith, 0, 0)
+		c.pushControl(controlScope, nil)
+		c.compileStatement(n.Body)
+		c.popControl(-1)
+		c.emit(opPopScope, 0, 0)
+	case *ast.TryStatement:
+		c.compileTry(n)
+	case *ast.LabeledStatement:
+		c.compileLabeled(n)
+	case *ast.WhileStatement, *ast.DoWhileStatement, *ast.ForStatement,
+		*ast.ForInStatement, *ast.ForOfStatement, *ast.SwitchStatement:
+		c.compileBreakable(n, nil)
+	default:
+		c.emit(opEvalStmt, c.node(n), 0)
+	}
+}
+
+func (c *compiler) compileVariableDeclaration(n *ast.VariableDeclaration) {
+	lexical := n.Kind != ast.VariableKindVar
+	for _, decl := range n.Declarations {
+		switch {
+		case decl.Init == nil && !lexical:
+			continue
+		case decl.Init == nil:
+			// let x; initializes x with undefined
+			c.emit(opUndefined, 0, 0)
+		default:
+			c.compileNamedExpression(decl.Init, decl.ID)
+		}
+		c.bind(decl.ID, lexical)
+	}
+}
+
+func (c *compiler) compileBlock(n *ast.BlockStatement) {
+	scope := c.enterScope(lexicallyScopedDeclarations(n.Body, false))
+	c.compileStatements(n.Body)
+	c.leaveScope(scope)
+}
+
+func (c *compiler) compileLabeled(n *ast.LabeledStatement) {
+	var labels []string
+	var body ast.Statement = n
+	for {
+		labelled, ok := body.(*ast.LabeledStatement)
+		if !ok {
+			break
+		}
+		labels = append(labels, labelled.Label.Name)
+		body = labelled.Body
+	}
+
+	switch body.(type) {
+	case *ast.WhileStatement, *ast.DoWhileStatement, *ast.ForStatement,
+		*ast.ForInStatement, *ast.ForOfStatement, *ast.SwitchStatement:
+		c.compileBreakable(body, labels)
+		return
+	}
+	c.pushControl(controlLabel, labels)
+	c.compileStatement(body)
+	c.popControl(-1)
+}
+
+// compileBreakable compiles the given loop or switch statement, where
+// labels holds the labels of the statement.
+func (c *compiler) compileBreakable(n ast.Statement, labels []string) {
+	switch n := n.(type) {
+	case *ast.WhileStatement:
+		c.completionUndefined()
+		c.pushControl(controlLoop, labels)
+		start := c.pc()
+		c.compileExpression(n.Test)
+		end := c.emit(opJumpIfFalse, -1, 0)
+		c.compileStatement(n.Body)
+		c.emit(opJump, start, 0)
+		c.patch(end)
+		c.popControl(start)
+	case *ast.DoWhileStatement:
+		c.completionUndefined()
+		c.pushControl(controlLoop, labels)
+		start := c.pc()
+		c.compileStatement(n.Body)
+		test := c.pc()
+		c.compileExpression(n.Test)
+		c.emit(opJumpIfTrue, start, 0)
+		c.popControl(test)
+	case *ast.ForStatement:
+		c.compileFor(n, labels)
+	case *ast.ForInStatement:
+		c.compileForInOf(n.Left, n.Right, n.Body, false, labels)
+	case *ast.ForOfStatement:
+		if n.Await {
+			c.emit(opEvalStmt, c.node(n), 0)
+			return
+		}
+		c.compileForInOf(n.Left, n.Right, n.Body, true, labels)
+	case *ast.SwitchStatement:
+		c.compileSwitch(n, labels)
+	}
+}
+
+// compileFor compiles the given for loop. If the loop declares let bindings
+// that are stored in an environment, every iteration gets a copy of the
+// environment, so that closures created in the body capture the bindings of
+// their iteration.
+func (c *compiler) compileFor(n *ast.ForStatement, labels []string) {
+	c.completionUndefined()
+	scope, lexical, perIteration := -1, false, false
+	switch init := n.Init.(type) {
+	case nil:
+	case *ast.VariableDeclaration:
+		if init.Kind != ast.VariableKindVar {
+			lexical = true
+			scope = c.enterScope([]ast.Node{init})
+			perIteration = scope >= 0 && init.Kind == ast.VariableKindLet
+		}
+		c.compileVariableDeclaration(init)
+	default:
+		c.compileExpression(init)
+		c.emit(opPop, 0, 0)
+	}
+
+	c.pushControl(controlLoop, labels)
+	if perIteration {
+		c.emit(opCopyScope, scope, 0)
+	}
+	start := c.pc()
+	end := -1
+	if n.Test != nil {
+		c.compileExpression(n.Test)
+		end = c.emit(opJumpIfFalse, -1, 0)
+	}
+	c.compileStatement(n.Body)
+	update := c.pc()
+	if perIteration {
+		c.emit(opCopyScope, scope, 0)
+	}
+	if n.Update != nil {
+		c.compileExpression(n.Update)
+		c.emit(opPop, 0, 0)
+	}
+	c.emit(opJump, start, 0)
+	if end >= 0 {
+		c.patch(end)
+	}
+	c.popControl(update)
+	if lexical {
+		c.leaveScope(scope)
+	}
+}
+
+// compileForInOf compiles a for-in loop, or a for-of loop if iterate is
+// true, with the given left hand side, expression and body. The body of a
+// for-of loop is guarded by an exception handler that closes the iterator.
+func (c *compiler) compileForInOf(left ast.Node, right ast.Expression, body ast.Statement, iterate bool, labels []string) {
+	c.completionUndefined()
+	var decl *ast.VariableDeclaration
+	target := left
+	if d, ok := left.(*ast.VariableDeclaration); ok {
+		decl = d
+		target = d.Declarations[0].ID
+	}
+	lexical := decl != nil && decl.Kind != ast.VariableKindVar
+
+	if lexical {
+		// the bound names are in the temporal dead zone while the
+		// expression is evaluated
+		tdz := c.enterScope([]ast.Node{decl})
+		c.compileExpression(right)
+		c.leaveScope(tdz)
+	} else {
+		c.compileExpression(right)
+	}
+
+	slot := c.newSlot("")
+	if iterate {
+		c.emit(opGetIterator, slot, 0)
+	} else {
+		c.emit(opEnumerate, slot, 0)
+	}
+
+	loop := c.pushControl(controlLoop, labels)
+	start := c.pc()
+	var next, guard int
+	if iterate {
+		loop.iterator = slot
+		next = c.emit(opIteratorNext, slot, -1)
+		guard = c.emit(opTry, -1, -1)
+		c.pushControl(controlTry, nil)
+	} else {
+		next = c.emit(opEnumerateNext, slot, -1)
+	}
+
+	scope := -1
+	if lexical {
+		scope = c.enterScope([]ast.Node{decl})
+	}
+	switch {
+	case decl != nil:
+		c.bind(target, lexical)
+	case isIdentifier(target):
+		c.store(target.(*ast.Identifier).Name)
+		c.emit(opPop, 0, 0)
+	default:
+		c.bind(target, false)
+	}
+	c.compileStatement(body)
+	if lexical {
+		c.leaveScope(scope)
+	}
+
+	if iterate {
+		c.popControl(-1)
+		c.emit(opPopTry, 0, 0)
+	}
+	c.emit(opJump, start, 0)
+	if iterate {
+		c.code.instructions[guard].a = c.pc()
+		c.emit(opIteratorCloseThrow, slot, 0)
+	}
+	c.code.instructions[next].b = c.pc()
+	c.popControl(start)
+}
+
+func isIdentifier(n ast.Node) bool {
+	_, ok := n.(*ast.Identifier)
+	return ok
+}
+
+// compileSwitch compiles the given switch statement. The case clauses
+// share one scope for their lexically scoped declarations, and the
+// selectors are compared with the discriminant in source order.
+func (c *compiler) compileSwitch(n *ast.SwitchStatement, labels []string) {
+	c.completionUndefined()
+	c.compileExpression(n.Discriminant)
+	discriminant := c.newSlot("")
+	c.emit(opInitSlot, discriminant, 0)
+
+	var code []ast.Statement
+	for _, clause := range n.Cases {
+		code = append(code, clause.Consequent...)
+	}
+	scope := c.enterScope(lexicallyScopedDeclarations(code, false))
+	c.pushControl(controlSwitch, labels)
+
+	matches := make([]int, len(n.Cases))
+	for i, clause := range n.Cases {
+		if clause.Test == nil {
+			continue
+		}
+		c.emit(opLoadSlot, discriminant, 0)
+		c.compileExpression(clause.Test)
+		c.emit(opStrictEq, 0, 0)
+		matches[i] = c.emit(opJumpIfTrue, -1, 0)
+	}
+	noMatch := c.emit(opJump, -1, 0)
+
+	hasDefault := false
+	for i, clause := range n.Cases {
+		if clause.Test == nil {
+			hasDefault = true
+			c.patch(noMatch)
+		} else {
+			c.patch(matches[i])
+		}
+		c.compileStatements(clause.Consequent)
+	}
+	if !hasDefault {
+		c.patch(noMatch)
+	}
+	c.popControl(-1)
+	c.leaveScope(scope)
+}
+
+// compileTry compiles the given try statement. The finally block is entered
+// with a pending completion on the stack, which is resumed at the end of
+// the block.
+func (c *compiler) compileTry(n *ast.TryStatement) {
+	c.completionUndefined()
+	var finally *control
+	var tryFinally int
+	if n.Finalizer != nil {
+		tryFinally = c.emit(opTry, -1, -1)
+		finally = c.pushControl(controlTry, nil)
+		finally.finally = true
+	}
+
+	if n.Handler != nil {
+		tryCatch := c.emit(opTry, -1, -1)
+		c.pushControl(controlTry, nil)
+		c.compileBlock(n.Block)
+		c.popControl(-1)
+		c.emit(opPopTry, 0, 0)
+		end := c.emit(opJump, -1, 0)
+
+		c.patch(tryCatch)
+		c.completionUndefined()
+		if n.Handler.Param == nil {
+			c.emit(opPop, 0, 0)
+			c.compileBlock(n.Handler.Body)
+		} else {
+			scope := c.enterScope([]ast.Node{n.Handler.Param})
+			c.bind(n.Handler.Param, true)
+			c.compileBlock(n.Handler.Body)
+			c.leaveScope(scope)
+		}
+		c.patch(end)
+	} else {
+		c.compileBlock(n.Block)
+	}
+
+	if finally != nil {
+		c.popControl(-1)
+		c.emit(opPopTry, 0, 0)
+		c.emit(opPushPending, -1, 0)
+		c.code.instructions[tryFinally].b = c.pc()
+		for _, pc := range finally.finallyJumps {
+			c.patch(pc)
+		}
+		c.pushControl(controlFinally, nil)
+		c.completionUndefined()
+		c.compileBlock(n.Finalizer)
+		c.popControl(-1)
+		c.emit(opEndFinally, 0, 0)
+	}
+}
+
+// compileNamedExpression compiles the given expression, which is the
+// initializer of the given binding target. Anonymous function definitions
+// are named after an identifier target.
+func (c *compiler) compileNamedExpression(n ast.Expression, target ast.Node) {
+	if id, ok := target.(*ast.Identifier); ok && isAnonymousFunctionDefinition(n) {
+		c.emitClosure(n, lang.NewStringKey(id.Name), true)
+		return
+	}
+	c.compileExpression(n)
+}
+
+func (c *compiler) emitClosure(n ast.Node, name lang.StringOrSymbol, hasName bool) {
+	c.code.closures = append(c.code.closures, closureSite{n, name, hasName})
+	c.emit(opClosure, len(c.code.closures)-1, 0)
+}
+
+func (c *compiler) compileExpression(n ast.Node) {
+	if isFallback(n) {
+		switch n.(type) {
+		case *ast.FunctionExpression, *ast.ArrowFunctionExpression, *ast.ClassExpression:
+			c.emitClosure(n, lang.StringOrSymbol{}, false)
+		default:
+			c.emit(opEvalExpr, c.node(n), 0)
+		}
+		return
+	}
+
+	switch n := n.(type) {
+	case *ast.Identifier:
+		c.load(n.Name)
+	case *ast.Literal:
+		switch v := n.Value.(type) {
+		case nil:
+			c.emit(opConst, c.value(lang.Null), 0)
+		case bool:
+			c.emit(opConst, c.value(lang.Boolean(v)), 0)
+		case float64:
+			c.emit(opConst, c.value(lang.NewNumber(v)), 0)
+		case string:
+			c.emit(opConst, c.value(stringValue(v)), 0)
+		default:
+			c.emit(opEvalExpr, c.node(n), 0)
+		}
+	case *ast.ThisExpression:
+		c.emit(opThis, 0, 0)
+	case *ast.MetaProperty:
+		// new.target is the only meta property
+		c.emit(opNewTarget, 0, 0)
+	case *ast.ArrayExpression:
+		c.compileArrayLiteral(n)
+	case *ast.ObjectExpression:
+		c.compileObjectLiteral(n)
+	case *ast.TemplateLiteral:
+		for i, quasi := range n.Quasis {
+			c.emit(opConst, c.value(stringValue(*quasi.Cooked)), 0)
+			if i < len(n.Expressions) {
+				c.compileExpression(n.Expressions[i])
+				c.emit(opToString, 0, 0)
+			}
+		}
+		c.emit(opConcat, len(n.Quasis)+len(n.Expressions), 0)
+	case *ast.MemberExpression:
+		c.compileExpression(n.Object)
+		if n.Computed {
+			c.compileExpression(n.Property)
+			c.emit(opGetProp, 0, 0)
+		} else {
+			c.emit(opGetPropNamed, c.key(literalPropertyKey(n.Property)), 0)
+		}
+	case *ast.CallExpression:
+		c.compileCall(n)
+	case *ast.NewExpression:
+		pos := c.enter(n)
+		c.compileExpression(n.Callee)
+		c.compileArguments(n.Arguments, n.Callee, opNew, opNewSpread)
+		c.leave(pos)
+	case *ast.UnaryExpression:
+		c.compileUnary(n)
+	case *ast.UpdateExpression:
+		c.compileUpdate(n)
+	case *ast.BinaryExpression:
+		c.compileExpression(n.Left)
+		c.compileExpression(n.Right)
+		c.emit(operatorOpcodes[n.Operator], 0, 0)
+	case *ast.LogicalExpression:
+		c.compileExpression(n.Left)
+		op := opJumpIfFalseKeep
+		if n.Operator == "||" {
+			op = opJumpIfTrueKeep
+		}
+		end := c.emit(op, -1, 0)
+		c.compileExpression(n.Right)
+		c.patch(end)
+	case *ast.ConditionalExpression:
+		c.compileExpression(n.Test)
+		alternate := c.emit(opJumpIfFalse, -1, 0)
+		c.compileExpression(n.Consequent)
+		end := c.emit(opJump, -1, 0)
+		c.patch(alternate)
+		c.compileExpression(n.Alternate)
+		c.patch(end)
+	case *ast.AssignmentExpression:
+		c.compileAssignment(n)
+	case *ast.SequenceExpression:
+		for i, expr := range n.Expressions {
+			if i > 0 {
+				c.emit(opPop, 0, 0)
+			}
+			c.compileExpression(expr)
+		}
+	default:
+		c.emit(opEvalExpr, c.node(n), 0)
+	}
+}
+
+func (c *compiler) compileArrayLiteral(n *ast.ArrayExpression) {
+	c.emit(opList, 0, 0)
+	for _, elem := range n.Elements {
+		switch elem := elem.(type) {
+		case nil:
+			// elision
+			c.emit(opListHole, 0, 0)
+		case *ast.SpreadElement:
+			c.compileExpression(elem.Argument)
+			c.emit(opListSpread, 0, 0)
+		default:
+			c.compileExpression(elem)
+			c.emit(opListPush, 0, 0)
+		}
+	}
+	c.emit(opListToArray, 0, 0)
+}
+
+func (c *compiler) compileObjectLiteral(n *ast.ObjectExpression) {
+	c.emit(opNewObject, 0, 0)
+	for _, prop := range n.Properties {
+		switch prop := prop.(type) {
+		case *ast.SpreadElement:
+			c.compileExpression(prop.Argument)
+			c.emit(opCopyData, 0, 0)
+		case *ast.Property:
+			switch {
+			case isFallback(prop):
+				c.emit(opDefineMethod, c.node(prop), 0)
+			case prop.Computed:
+				c.compileExpression(prop.Key)
+				c.emit(opToPropertyKey, 0, 0)
+				if isAnonymousFunctionDefinition(prop.Value) {
+					c.code.closures = append(c.code.closures, closureSite{node: prop.Value})
+					c.emit(opClosureKeyed, len(c.code.closures)-1, 0)
+				} else {
+					c.compileExpression(prop.Value)
+				}
+				c.emit(opDefineComputed, 0, 0)
+			default:
+				key := literalPropertyKey(prop.Key)
+				if !prop.Shorthand && key.String().String() == "__proto__" {
+					// __proto__: value sets the prototype of the object
+					c.compileExpression(prop.Value)
+					c.emit(opSetProto, 0, 0)
+					continue
+				}
+				if isAnonymousFunctionDefinition(prop.Value) {
+					c.emitClosure(prop.Value, key, true)
+				} else {
+					c.compileExpression(prop.Value)
+				}
+				c.emit(opDefineField, c.key(key), 0)
+			}
+		}
+	}
+}
+
+// compileCall compiles the given call. The callee is compiled to push the
+// function and the this value of the call.
+func (c *compiler) compileCall(n *ast.CallExpression) {
+	defer c.leave(c.enter(n))
+
+	switch callee := n.Callee.(type) {
+	case *ast.Identifier:
+		if ref := c.lookup(callee.Name); ref.slot >= 0 {
+			c.emit(opLoadSlot, ref.slot, 0)
+			c.emit(opUndefined, 0, 0)
+		} else {
+			c.emit(opLoadNameThis, c.name(callee.Name), 0)
+		}
+	case *ast.MemberExpression:
+		c.compileExpression(callee.Object)
+		if callee.Computed {
+			c.compileExpression(callee.Property)
+			c.emit(opGetMethod, 0, 0)
+		} else {
+			c.emit(opGetMethodNamed, c.key(literalPropertyKey(callee.Property)), 0)
+		}
+	default:
+		c.compileExpression(callee)
+		c.emit(opUndefined, 0, 0)
+	}
+	c.compileArguments(n.Arguments, n.Callee, opCall, opCallSpread)
+}
+
+// compileArguments compiles the given arguments of a call or new
+// expression, followed by the given operation, or by the given spread
+// operation if the arguments contain spread elements.
+func (c *compiler) compileArguments(args []ast.Expression, callee ast.Node, op, spreadOp opcode) {
+	spread := false
+	for _, arg := range args {
+		if _, ok := arg.(*ast.SpreadElement); ok {
+			spread = true
+		}
+	}
+
+	c.code.calls = append(c.code.calls, callSite{len(args), callee})
+	site := len(c.code.calls) - 1
+	if !spread {
+		for _, arg := range args {
+			c.compileExpression(arg)
+		}
+		c.emit(op, site, 0)
+		return
+	}
+
+	c.emit(opList, 0, 0)
+	for _, arg := range args {
+		if s, ok := arg.(*ast.SpreadElement); ok {
+			c.compileExpression(s.Argument)
+			c.emit(opListSpread, 0, 0)
+		} else {
+			c.compileExpression(arg)
+			c.emit(opListPush, 0, 0)
+		}
+	}
+	c.emit(spreadOp, site, 0)
+}
+
+func (c *compiler) compileUnary(n *ast.UnaryExpression) {
+	switch n.Operator {
+	case "delete":
+		switch arg := n.Argument.(type) {
+		case *ast.Identifier:
+			if ref := c.lookup(arg.Name); ref.slot >= 0 {
+				// declared bindings cannot be deleted
+				c.emit(opConst, c.value(lang.False), 0)
+			} else {
+				c.emit(opDeleteName, c.name(arg.Name), 0)
+			}
+		case *ast.MemberExpression:
+			c.compileExpression(arg.Object)
+			if arg.Computed {
+				c.compileExpression(arg.Property)
+			} else {
+				c.emit(opConst, c.value(lang.NewString(arg.Property.(*ast.Identifier).Name)), 0)
+			}
+			c.emit(opDeleteProp, 0, 0)
+		default:
+			c.compileExpression(arg)
+			c.emit(opPop, 0, 0)
+			c.emit(opConst, c.value(lang.True), 0)
+		}
+		return
+	case "typeof":
+		if id, ok := n.Argument.(*ast.Identifier); ok && c.lookup(id.Name).slot < 0 {
+			c.emit(opTypeofName, c.name(id.Name), 0)
+			return
+		}
+	}
+
+	c.compileExpression(n.Argument)
+	switch n.Operator {
+	case "void":
+		c.emit(opPop, 0, 0)
+		c.emit(opUndefined, 0, 0)
+	case "typeof":
+		c.emit(opTypeof, 0, 0)
+	case "!":
+		c.emit(opNot, 0, 0)
+	case "+":
+		c.emit(opToNumber, 0, 0)
+	case "-":
+		c.emit(opNeg, 0, 0)
+	case "~":
+		c.emit(opBitNot, 0, 0)
+	}
+}
+
+func (c *compiler) compileUpdate(n *ast.UpdateExpression) {
+	op := opInc
+	if n.Operator == "--" {
+		op = opDec
+	}
+
+	switch arg := n.Argument.(type) {
+	case *ast.Identifier:
+		c.load(arg.Name)
+		c.emit(opToNumber, 0, 0)
+		if n.Prefix {
+			c.emit(op, 0, 0)
+			c.store(arg.Name)
+			return
+		}
+		c.emit(opDup, 0, 0)
+		c.emit(op, 0, 0)
+		c.store(arg.Name)
+		c.emit(opPop, 0, 0)
+	case *ast.MemberExpression:
+		set := c.compilePropertyTarget(arg, true)
+		c.emit(opToNumber, 0, 0)
+		if n.Prefix {
+			c.emit(op, 0, 0)
+			c.emit(set.op, set.a, 0)
+			return
+		}
+		old := c.newSlot("")
+		c.emit(opDup, 0, 0)
+		c.emit(opInitSlot, old, 0)
+		c.emit(op, 0, 0)
+		c.emit(set.op, set.a, 0)
+		c.emit(opPop, 0, 0)
+		c.emit(opLoadSlot, old, 0)
+	default:
+		c.emit(opEvalExpr, c.node(n), 0)
+	}
+}
+
+// compilePropertyTarget compiles the object and key of the given property
+// reference, which is the target of an assignment, and returns the
+// instruction that sets the property. If get is true, the current value of
+// the property is pushed as well.
+func (c *compiler) compilePropertyTarget(n *ast.MemberExpression, get bool) instruction {
+	c.compileExpression(n.Object)
+	if !n.Computed {
+		key := c.key(literalPropertyKey(n.Property))
+		c.emit(opCheckBase, key, 0)
+		if get {
+			c.emit(opDup, 0, 0)
+			c.emit(opGetPropNamed, key, 0)
+		}
+		return instruction{op: opSetPropNamed, a: key}
+	}
+
+	c.compileExpression(n.Property)
+	c.emit(opMakeKey, 0, 0)
+	if get {
+		c.emit(opDup2, 0, 0)
+		c.emit(opGetPropKey, 0, 0)
+	}
+	return instruction{op: opSetProp}
+}
+
+func (c *compiler) compileAssignment(n *ast.AssignmentExpression) {
+	if n.Operator == "=" {
+		switch left := n.Left.(type) {
+		case *ast.ObjectPattern, *ast.ArrayPattern:
+			c.compileExpression(n.Right)
+			c.emit(opDup, 0, 0)
+			c.bind(left, false)
+			return
+		case *ast.Identifier:
+			c.compileNamedExpression(n.Right, left)
+			c.store(left.Name)
+			return
+		}
+	}
+
+	op := operatorOpcodes[strings.TrimSuffix(n.Operator, "=")]
+	switch left := n.Left.(type) {
+	case *ast.Identifier:
+		c.load(left.Name)
+		c.compileExpression(n.Right)
+		c.emit(op, 0, 0)
+		c.store(left.Name)
+	case *ast.MemberExpression:
+		set := c.compilePropertyTarget(left, n.Operator != "=")
+		c.compileExpression(n.Right)
+		if n.Operator != "=" {
+			c.emit(op, 0, 0)
+		}
+		c.emit(set.op, set.a, 0)
+	default:
+		c.emit(opEvalExpr, c.node(n), 0)
+	}
+}
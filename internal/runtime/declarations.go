@@ -0,0 +1,303 @@
+package runtime
+
+import (
+	"fmt"
+
+	"github.com/gojisvm/gojis/internal/parser/ast"
+	"github.com/gojisvm/gojis/internal/runtime/binding"
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+)
+
+// globalDeclarationInstantiation creates the bindings of the top-level
+// declarations of the given script in the global environment.
+// GlobalDeclarationInstantiation is specified in 15.1.11.
+func (r *Runtime) globalDeclarationInstantiation(script *ast.Program, env *binding.GlobalEnvironment) errors.Error {
+	lexDeclarations := lexicallyScopedDeclarations(script.Body, true)
+	varDeclarations := varScopedDeclarations(script.Body, true)
+
+	for _, name := range declarationNames(lexDeclarations) {
+		n := lang.NewString(name)
+		if env.HasVarDeclaration(n) || env.HasLexicalDeclaration(n) || env.HasRestrictedGlobalProperty(n) {
+			return errors.NewSyntaxError(fmt.Sprintf("Identifier '%v' has already been declared", name))
+		}
+	}
+	for _, name := range declarationNames(varDeclarations) {
+		if env.HasLexicalDeclaration(lang.NewString(name)) {
+			return errors.NewSyntaxError(fmt.Sprintf("Identifier '%v' has already been declared", name))
+		}
+	}
+
+	var functionsToInitialize []*ast.FunctionDeclaration
+	var declaredFunctionNames []string
+	for i := len(varDeclarations) - 1; i >= 0; i-- {
+		d, ok := varDeclarations[i].(*ast.FunctionDeclaration)
+		if !ok {
+			continue
+		}
+		fn := boundNames(d)[0]
+		if containsString(declaredFunctionNames, fn) {
+			continue
+		}
+		if !env.CanDeclareGlobalFunction(lang.NewString(fn)) {
+			return errors.NewTypeError(fmt.Sprintf("Cannot redefine property: %v", fn))
+		}
+		declaredFunctionNames = append(declaredFunctionNames, fn)
+		functionsToInitialize = append([]*ast.FunctionDeclaration{d}, functionsToInitialize...)
+	}
+
+	var declaredVarNames []string
+	for _, d := range varDeclarations {
+		if _, ok := d.(*ast.VariableDeclaration); !ok {
+			continue
+		}
+		for _, vn := range boundNames(d) {
+			if containsString(declaredFunctionNames, vn) || containsString(declaredVarNames, vn) {
+				continue
+			}
+			if !env.CanDeclareGlobalVar(lang.NewString(vn)) {
+				return errors.NewTypeError(fmt.Sprintf("Cannot define global variable '%v', global object is not extensible", vn))
+			}
+			declaredVarNames = append(declaredVarNames, vn)
+		}
+	}
+
+	if !script.Strict {
+		for _, f := range blockFunctionDeclarations(script.Body, nil) {
+			fn := boundNames(f)[0]
+			n := lang.NewString(fn)
+			if env.HasLexicalDeclaration(n) || !env.CanDeclareGlobalVar(n) {
+				continue
+			}
+			if !containsString(declaredFunctionNames, fn) && !containsString(declaredVarNames, fn) {
+				declaredVarNames = append(declaredVarNames, fn)
+			}
+			r.markBlockFunction(f)
+		}
+	}
+
+	for _, d := range lexDeclarations {
+		for _, dn := range boundNames(d) {
+			var err errors.Error
+			if isConstantDeclaration(d) {
+				err = env.CreateImmutableBinding(lang.NewString(dn), true)
+			} else {
+				err = env.CreateMutableBinding(lang.NewString(dn), false)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, f := range functionsToInitialize {
+		fn := boundNames(f)[0]
+		fo := r.instantiateFunctionObject(f, env)
+		if err := env.CreateGlobalFunctionBinding(lang.NewString(fn), fo, false); err != nil {
+			return err
+		}
+	}
+
+	for _, vn := range declaredVarNames {
+		if err := env.CreateGlobalVarBinding(lang.NewString(vn), false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// evalDeclarationInstantiation creates the bindings of the top-level
+// declarations of the given eval code. Var and function declarations are
+// instantiated in varEnv, and lexical declarations in lexEnv, which is a
+// new environment of the eval code. Var declarations of non-strict code
+// must not conflict with lexical declarations in the environments between
+// lexEnv and varEnv.
+// EvalDeclarationInstantiation is specified in 18.2.1.3.
+func (r *Runtime) evalDeclarationInstantiation(body *ast.Program, varEnv, lexEnv binding.Environment, strict bool) errors.Error {
+	varDeclarations := varScopedDeclarations(body.Body, true)
+	varNames := declarationNames(varDeclarations)
+	globalEnv, global := varEnv.(*binding.GlobalEnvironment)
+
+	if !strict {
+		if global {
+			for _, name := range varNames {
+				if globalEnv.HasLexicalDeclaration(lang.NewString(name)) {
+					return errors.NewSyntaxError(fmt.Sprintf("Identifier '%v' has already been declared", name))
+				}
+			}
+		}
+		for _, name := range varNames {
+			if declaredBetween(lexEnv, varEnv, lang.NewString(name)) {
+				return errors.NewSyntaxError(fmt.Sprintf("Identifier '%v' has already been declared", name))
+			}
+		}
+	}
+
+	var functionsToInitialize []*ast.FunctionDeclaration
+	var declaredFunctionNames []string
+	for i := len(varDeclarations) - 1; i >= 0; i-- {
+		d, ok := varDeclarations[i].(*ast.FunctionDeclaration)
+		if !ok {
+			continue
+		}
+		fn := boundNames(d)[0]
+		if containsString(declaredFunctionNames, fn) {
+			continue
+		}
+		if global && !globalEnv.CanDeclareGlobalFunction(lang.NewString(fn)) {
+			return errors.NewTypeError(fmt.Sprintf("Cannot redefine property: %v", fn))
+		}
+		declaredFunctionNames = append(declaredFunctionNames, fn)
+		functionsToInitialize = append([]*ast.FunctionDeclaration{d}, functionsToInitialize...)
+	}
+
+	var declaredVarNames []string
+	for _, d := range varDeclarations {
+		if _, ok := d.(*ast.VariableDeclaration); !ok {
+			continue
+		}
+		for _, vn := range boundNames(d) {
+			if containsString(declaredFunctionNames, vn) || containsString(declaredVarNames, vn) {
+				continue
+			}
+			if global && !globalEnv.CanDeclareGlobalVar(lang.NewString(vn)) {
+				return errors.NewTypeError(fmt.Sprintf("Cannot define global variable '%v', global object is not extensible", vn))
+			}
+			declaredVarNames = append(declaredVarNames, vn)
+		}
+	}
+
+	if !strict {
+		for _, f := range blockFunctionDeclarations(body.Body, nil) {
+			fn := boundNames(f)[0]
+			n := lang.NewString(fn)
+			if declaredBetween(lexEnv, varEnv, n) || global && (globalEnv.HasLexicalDeclaration(n) || !globalEnv.CanDeclareGlobalVar(n)) {
+				continue
+			}
+			if !containsString(declaredFunctionNames, fn) && !containsString(declaredVarNames, fn) {
+				declaredVarNames = append(declaredVarNames, fn)
+			}
+			r.markBlockFunction(f)
+		}
+	}
+
+	for _, d := range lexicallyScopedDeclarations(body.Body, true) {
+		for _, dn := range boundNames(d) {
+			var err errors.Error
+			if isConstantDeclaration(d) {
+				err = lexEnv.CreateImmutableBinding(lang.NewString(dn), true)
+			} else {
+				err = lexEnv.CreateMutableBinding(lang.NewString(dn), false)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, f := range functionsToInitialize {
+		fn := lang.NewString(boundNames(f)[0])
+		fo := r.instantiateFunctionObject(f, lexEnv)
+		if global {
+			if err := globalEnv.CreateGlobalFunctionBinding(fn, fo, true); err != nil {
+				return err
+			}
+			continue
+		}
+		if exists, _ := varEnv.HasBinding(fn); exists {
+			if err := varEnv.SetMutableBinding(fn, fo, false); err != nil {
+				return err
+			}
+			continue
+		}
+		_ = varEnv.CreateMutableBinding(fn, true)
+		_ = varEnv.InitializeBinding(fn, fo)
+	}
+
+	for _, vn := range declaredVarNames {
+		n := lang.NewString(vn)
+		if global {
+			if err := globalEnv.CreateGlobalVarBinding(n, true); err != nil {
+				return err
+			}
+			continue
+		}
+		if exists, _ := varEnv.HasBinding(n); !exists {
+			_ = varEnv.CreateMutableBinding(n, true)
+			_ = varEnv.InitializeBinding(n, lang.Undefined)
+		}
+	}
+	return nil
+}
+
+// declaredBetween returns whether the given name is bound in one of the
+// declarative environments from lexEnv up to, but excluding, varEnv.
+func declaredBetween(lexEnv, varEnv binding.Environment, name lang.String) bool {
+	for env := lexEnv; env != nil && env != varEnv; env = env.Outer() {
+		if _, ok := env.(*binding.ObjectEnvironment); ok {
+			continue
+		}
+		if exists, _ := env.HasBinding(name); exists {
+			return true
+		}
+	}
+	return false
+}
+
+// markBlockFunction records that the given function declaration in a block
+// assigns its function object to the var binding of its name, when it is
+// evaluated.
+func (r *Runtime) markBlockFunction(f *ast.FunctionDeclaration) {
+	if record, ok := r.context().ScriptOrModule.(*scriptRecord); ok {
+		record.blockFunctions[f] = true
+	}
+}
+
+// evaluateBlockFunction assigns the function object of the given function
+// declaration, which is bound in the running lexical environment, to the
+// var binding of its name, if the declaration is in a block and is bound in
+// the var scope as well.
+// The evaluation of block-level function declarations is specified in
+// B.3.3.
+func (r *Runtime) evaluateBlockFunction(f *ast.FunctionDeclaration) {
+	ctx := r.context()
+	if record, ok := ctx.ScriptOrModule.(*scriptRecord); !ok || !record.blockFunctions[f] {
+		return
+	}
+	name := lang.NewString(boundNames(f)[0])
+	fo, _ := ctx.LexicalEnvironment.GetBindingValue(name, false)
+	_ = ctx.VariableEnvironment.SetMutableBinding(name, fo, false)
+}
+
+// blockDeclarationInstantiation creates the bindings of the lexically
+// scoped declarations of the given statement list in the given environment,
+// and initializes the bindings of function declarations.
+// BlockDeclarationInstantiation is specified in 13.2.14.
+func (r *Runtime) blockDeclarationInstantiation(code []ast.Statement, env *binding.DeclarativeEnvironment) {
+	for _, d := range lexicallyScopedDeclarations(code, false) {
+		for _, dn := range boundNames(d) {
+			if isConstantDeclaration(d) {
+				_ = env.CreateImmutableBinding(lang.NewString(dn), true)
+			} else {
+				_ = env.CreateMutableBinding(lang.NewString(dn), false)
+			}
+		}
+
+		if f, ok := d.(*ast.FunctionDeclaration); ok {
+			fn := boundNames(f)[0]
+			fo := r.instantiateFunctionObject(f, env)
+			_ = env.InitializeBinding(lang.NewString(fn), fo)
+		}
+	}
+}
+
+// instantiateFunctionObject creates the function object of the given
+// function declaration, whose scope is the given environment.
+// InstantiateFunctionObject is specified in 14.1.20.
+func (r *Runtime) instantiateFunctionObject(decl *ast.FunctionDeclaration, scope binding.Environment) *lang.Object {
+	name := lang.NewStringKey(boundNames(decl)[0])
+	if decl.ID == nil {
+		name = lang.NewStringKey("default")
+	}
+	return r.functionDefinition(&decl.Function, scope, name).object
+}
@@ -10,47 +10,119 @@ const (
 	ErrorKindTypeError ErrorKind = iota
 	ErrorKindReferenceError
 	ErrorKindRangeError
+	ErrorKindSyntaxError
+	ErrorKindEvalError
+	ErrorKindURIError
+	// ErrorKindAggregateError is the kind of errors that hold several
+	// other errors, which are thrown as AggregateError objects.
+	ErrorKindAggregateError
+	// ErrorKindThrow is the kind of errors that carry an arbitrary
+	// language value, that was thrown by a throw statement.
+	ErrorKindThrow
 )
 
+// kindNames are the names of the constructors of the error objects that
+// are thrown for the error kinds.
+var kindNames = map[ErrorKind]string{
+	ErrorKindTypeError:      "TypeError",
+	ErrorKindReferenceError: "ReferenceError",
+	ErrorKindRangeError:     "RangeError",
+	ErrorKindSyntaxError:    "SyntaxError",
+	ErrorKindEvalError:      "EvalError",
+	ErrorKindURIError:       "URIError",
+	ErrorKindAggregateError: "AggregateError",
+}
+
+// String returns the name of the constructor of the error objects that
+// are thrown for errors of the kind, like "TypeError". The empty string is
+// returned for ErrorKindThrow, since any value can be thrown.
+func (k ErrorKind) String() string {
+	return kindNames[k]
+}
+
 // Error is an error that can be thrown during runtime.
-// When implementing the specification, maybe this layer will be
-// reduced to the ThrowXXXError function objects.
+// Errors of all kinds except ErrorKindThrow are turned into error objects
+// of the current realm when they are thrown into ECMAScript code, that
+// is, an error of kind ErrorKindTypeError is thrown as an object created
+// by %TypeError%.
 type Error interface {
 	error
 	Kind() ErrorKind
+	// Message returns the message of the error, without the name of
+	// its kind.
+	Message() string
 }
 
 var _ Error = (*errorImpl)(nil) // ensure that errorImpl implements Error
 
 type errorImpl struct {
 	error
-	kind ErrorKind
+	kind    ErrorKind
+	message string
 }
 
 func (e errorImpl) Kind() ErrorKind {
 	return e.kind
 }
 
-// NewTypeError creates a new type error with the given error.
-func NewTypeError(msg string) Error {
+func (e errorImpl) Message() string {
+	return e.message
+}
+
+// New creates a new error of the given kind with the given message. The
+// kind must not be ErrorKindThrow.
+func New(kind ErrorKind, msg string) Error {
 	return errorImpl{
-		error: fmt.Errorf("TypeError: %v", msg),
-		kind:  ErrorKindTypeError,
+		error:   fmt.Errorf("%v: %v", kind, msg),
+		kind:    kind,
+		message: msg,
 	}
 }
 
+// NewTypeError creates a new type error with the given error.
+func NewTypeError(msg string) Error {
+	return New(ErrorKindTypeError, msg)
+}
+
 // NewReferenceError creates a new reference error with the given error.
 func NewReferenceError(msg string) Error {
-	return errorImpl{
-		error: fmt.Errorf("ReferenceError: %v", msg),
-		kind:  ErrorKindReferenceError,
-	}
+	return New(ErrorKindReferenceError, msg)
 }
 
 // NewRangeError creates a new range error with the given error.
 func NewRangeError(msg string) Error {
-	return errorImpl{
-		error: fmt.Errorf("RangeError: %v", msg),
-		kind:  ErrorKindRangeError,
+	return New(ErrorKindRangeError, msg)
+}
+
+// NewSyntaxError creates a new syntax error with the given error.
+func NewSyntaxError(msg string) Error {
+	return New(ErrorKindSyntaxError, msg)
+}
+
+// NewEvalError creates a new eval error with the given error.
+func NewEvalError(msg string) Error {
+	return New(ErrorKindEvalError, msg)
+}
+
+// NewURIError creates a new URI error with the given error.
+func NewURIError(msg string) Error {
+	return New(ErrorKindURIError, msg)
+}
+
+// AggregateError is an error of kind ErrorKindAggregateError, which holds
+// the errors that it aggregates. It is thrown as an AggregateError object,
+// whose errors property holds the values that are thrown for the
+// aggregated errors.
+type AggregateError struct {
+	errorImpl
+	Errors []Error
+}
+
+// NewAggregateError creates a new aggregate error with the given errors
+// and message.
+func NewAggregateError(errs []Error, msg string) *AggregateError {
+	return &AggregateError{
+		errorImpl: New(ErrorKindAggregateError, msg).(errorImpl),
+		Errors:    errs,
 	}
 }
@@ -13,8 +13,41 @@ func TestNewTypeError(t *testing.T) {
 	err := NewTypeError(msg)
 	require.Equal(ErrorKindTypeError, err.Kind())
 	require.Equal("TypeError: "+msg, err.Error())
+	require.Equal(msg, err.Message())
 
 	err = NewReferenceError(msg)
 	require.Equal(ErrorKindReferenceError, err.Kind())
 	require.Equal("ReferenceError: "+msg, err.Error())
+	require.Equal(msg, err.Message())
+
+	err = NewRangeError(msg)
+	require.Equal(ErrorKindRangeError, err.Kind())
+	require.Equal("RangeError: "+msg, err.Error())
+	require.Equal(msg, err.Message())
+
+	err = NewSyntaxError(msg)
+	require.Equal(ErrorKindSyntaxError, err.Kind())
+	require.Equal("SyntaxError: "+msg, err.Error())
+	require.Equal(msg, err.Message())
+
+	err = NewEvalError(msg)
+	require.Equal(ErrorKindEvalError, err.Kind())
+	require.Equal("EvalError: "+msg, err.Error())
+	require.Equal(msg, err.Message())
+
+	err = NewURIError(msg)
+	require.Equal(ErrorKindURIError, err.Kind())
+	require.Equal("URIError: "+msg, err.Error())
+	require.Equal(msg, err.Message())
+}
+
+func TestNewAggregateError(t *testing.T) {
+	require := require.New(t)
+
+	errs := []Error{NewTypeError("a"), NewRangeError("b")}
+	err := NewAggregateError(errs, "all failed")
+	require.Equal(ErrorKindAggregateError, err.Kind())
+	require.Equal("AggregateError: all failed", err.Error())
+	require.Equal("all failed", err.Message())
+	require.Equal(errs, err.Errors)
 }
@@ -0,0 +1,174 @@
+package runtime
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gojisvm/gojis/internal/parser"
+	"github.com/gojisvm/gojis/internal/parser/ast"
+	"github.com/gojisvm/gojis/internal/runtime/agent"
+	"github.com/gojisvm/gojis/internal/runtime/binding"
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+	"github.com/gojisvm/gojis/internal/runtime/realm"
+)
+
+// setHostHooks sets the hooks of the given realm, that need to parse and
+// evaluate ECMAScript code, to inspect the execution context stack or to
+// interrupt the evaluation, to functions of the runtime.
+func (r *Runtime) setHostHooks(rlm *realm.Realm) {
+	rlm.PerformEval = r.performEval
+	rlm.CreateDynamicFunction = r.createDynamicFunction
+	rlm.StackTrace = r.errorStackTrace
+	rlm.Work = r.work
+}
+
+// parseDynamic parses the given source text of eval or the Function
+// constructor as a script. If strict is true, the script is strict mode
+// code, even if it does not begin with a Use Strict Directive.
+func (r *Runtime) parseDynamic(src string, strict bool) (string, *ast.Program, errors.Error) {
+	r.dynamicCount++
+	name := fmt.Sprintf("<dynamic-%d>", r.dynamicCount)
+
+	p := parser.New()
+	p.SetStrict(strict)
+	if err := p.ParseString(name, src); err != nil {
+		return "", nil, errors.NewSyntaxError(err.Error())
+	}
+	script, _ := p.Ast().Root(name)
+	return name, script, nil
+}
+
+// evaluateDynamic evaluates the given script, and returns the value of the
+// completion, or the thrown value as error.
+func (r *Runtime) evaluateDynamic(name string, script *ast.Program) (lang.Value, errors.Error) {
+	result := r.scriptEvaluation(r.newScriptRecord(name, script, nil))
+	if result.Type == lang.CompletionThrow {
+		return nil, lang.NewThrowError(result.Value)
+	}
+	return result.Value, nil
+}
+
+// performEval evaluates the given source text as indirect eval, that is,
+// in a new declarative environment whose outer environment is the global
+// environment.
+func (r *Runtime) performEval(x lang.Value) (lang.Value, errors.Error) {
+	return r.evaluateEval(x, false)
+}
+
+// evaluateEval evaluates the given value as the code of a call to eval. If
+// x is not a String, it is returned unchanged. The code of a direct eval
+// is evaluated in the environments of the running execution context, and
+// is strict mode code if the caller is. The var declarations of strict
+// mode code are instantiated in a new environment, so that they do not
+// leak into the environment of the caller.
+// PerformEval is specified in 18.2.1.1.
+func (r *Runtime) evaluateEval(x lang.Value, direct bool) (lang.Value, errors.Error) {
+	if x.Type() != lang.TypeString {
+		return x, nil
+	}
+	name, script, err := r.parseDynamic(x.(lang.String).String(), direct && r.strict)
+	if err != nil {
+		return nil, err
+	}
+
+	var lexEnv, varEnv binding.Environment
+	if direct {
+		ctx := r.context()
+		lexEnv = binding.NewDeclarativeEnvironment(ctx.LexicalEnvironment)
+		varEnv = ctx.VariableEnvironment
+	} else {
+		globalEnv := r.Realm().GlobalEnvironment()
+		lexEnv = binding.NewDeclarativeEnvironment(globalEnv)
+		varEnv = globalEnv
+	}
+	if script.Strict {
+		varEnv = lexEnv
+	}
+
+	record := r.newScriptRecord(name, script, nil)
+	evalCtx := &agent.ExecutionContext{
+		Function:            lang.Null,
+		Realm:               r.Realm(),
+		ScriptOrModule:      record,
+		VariableEnvironment: varEnv,
+		LexicalEnvironment:  lexEnv,
+		Position:            script.Loc().Start,
+	}
+	if err := r.agent.ExecutionContextStack.Push(evalCtx); err != nil {
+		return nil, err
+	}
+	defer r.agent.ExecutionContextStack.Pop()
+
+	strict := r.strict
+	r.strict = script.Strict
+	defer func() { r.strict = strict }()
+
+	if err := r.evalDeclarationInstantiation(script, varEnv, lexEnv, script.Strict); err != nil {
+		return nil, err
+	}
+
+	var result lang.Completion
+	if r.engine == EngineBytecode {
+		result = r.run(record.compiled(script))
+	} else {
+		result = r.evaluateStatementList(script.Body)
+	}
+	if result.Type == lang.CompletionThrow {
+		return nil, lang.NewThrowError(result.Value)
+	}
+	if result.Value == nil {
+		return lang.Undefined, nil
+	}
+	return result.Value, nil
+}
+
+// createDynamicFunction creates a function from the arguments of a call to
+// the Function constructor. The parameters and the body are parsed as part
+// of a function expression, which must be the only statement of the
+// resulting script, so that neither of them can end the function early.
+// CreateDynamicFunction is specified in 19.2.1.1.1.
+func (r *Runtime) createDynamicFunction(newTarget lang.Value, args []lang.Value) (lang.Value, errors.Error) {
+	params := make([]string, 0, len(args))
+	body := ""
+	for i, arg := range args {
+		s, err := lang.ToString(arg)
+		if err != nil {
+			return nil, err
+		}
+		if i == len(args)-1 {
+			body = s.String()
+		} else {
+			params = append(params, s.String())
+		}
+	}
+
+	src := "(function (" + strings.Join(params, ",") + "\n) {\n" + body + "\n})"
+	name, script, err := r.parseDynamic(src, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(script.Body) != 1 {
+		return nil, errors.NewSyntaxError("Invalid function parameters or body")
+	}
+	if stmt, ok := script.Body[0].(*ast.ExpressionStatement); !ok {
+		return nil, errors.NewSyntaxError("Invalid function parameters or body")
+	} else if _, ok := stmt.Expression.(*ast.FunctionExpression); !ok {
+		return nil, errors.NewSyntaxError("Invalid function parameters or body")
+	}
+
+	result, err := r.evaluateDynamic(name, script)
+	if err != nil {
+		return nil, err
+	}
+	f := result.(*lang.Object)
+	if nt, ok := newTarget.(*lang.Object); ok {
+		proto, err := realm.GetPrototypeFromConstructor(nt, realm.IntrinsicNameFunctionPrototype)
+		if err != nil {
+			return nil, err
+		}
+		f.SetPrototypeOf(proto)
+	}
+	lang.SetFunctionName(f, lang.NewStringKey("anonymous"), "")
+	return f, nil
+}
@@ -0,0 +1,707 @@
+package runtime
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gojisvm/gojis/internal/parser/ast"
+	"github.com/gojisvm/gojis/internal/runtime/binding"
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+	"github.com/gojisvm/gojis/internal/runtime/realm"
+)
+
+// maxNesting is the maximum number of nested expressions and statements
+// that the tree-walking engine evaluates at once, summed over all calls.
+// Every nested evaluation takes up to about 3KB of the Go stack, so that
+// together with the calls that are limited by the ExecutionContextStack,
+// the stack of the goroutine cannot overflow.
+const maxNesting = 150000
+
+// nest counts a nested evaluation of an expression or statement against
+// maxNesting, and returns a RangeError if the maximum has been reached.
+// Every successful nest must be followed by a call of unnest.
+func (r *Runtime) nest() errors.Error {
+	if r.nesting >= maxNesting {
+		return errors.NewRangeError("Maximum call stack size exceeded")
+	}
+	r.nesting++
+	return nil
+}
+
+// unnest ends a nested evaluation that was counted by nest.
+func (r *Runtime) unnest() {
+	r.nesting--
+}
+
+// evaluate evaluates the given expression and returns its value. If the
+// expression evaluates to a reference, the value of the reference is
+// returned. An abrupt completion of the evaluation is returned as error.
+func (r *Runtime) evaluate(n ast.Node) (lang.Value, errors.Error) {
+	if err := r.nest(); err != nil {
+		return nil, err
+	}
+	defer r.unnest()
+	return r.evaluateNode(n)
+}
+
+// evaluateNode evaluates the given expression, see evaluate.
+func (r *Runtime) evaluateNode(n ast.Node) (lang.Value, errors.Error) {
+	switch n := n.(type) {
+	case *ast.Identifier, *ast.MemberExpression:
+		ref, err := r.evaluateReference(n)
+		if err != nil {
+			return nil, err
+		}
+		return ref.GetValue(r.Realm())
+	case *ast.ThisExpression:
+		return r.agent.ResolveThisBinding()
+	case *ast.Literal:
+		return r.evaluateLiteral(n)
+	case *ast.ArrayExpression:
+		return r.evaluateArrayLiteral(n)
+	case *ast.ObjectExpression:
+		return r.evaluateObjectLiteral(n)
+	case *ast.FunctionExpression:
+		return r.evaluateFunctionExpression(n, lang.NewStringKey(""))
+	case *ast.ArrowFunctionExpression:
+		return r.evaluateArrowFunction(n, lang.NewStringKey("")), nil
+	case *ast.ClassExpression:
+		return r.evaluateClassExpression(n, lang.NewStringKey(""))
+	case *ast.TemplateLiteral:
+		return r.evaluateTemplateLiteral(n)
+	case *ast.TaggedTemplateExpression:
+		return r.evaluateTaggedTemplate(n)
+	case *ast.CallExpression:
+		return r.evaluateCall(n)
+	case *ast.NewExpression:
+		return r.evaluateNew(n)
+	case *ast.MetaProperty:
+		// new.target is the only meta property
+		return r.agent.GetNewTarget(), nil
+	case *ast.UnaryExpression:
+		return r.evaluateUnary(n)
+	case *ast.UpdateExpression:
+		return r.evaluateUpdate(n)
+	case *ast.BinaryExpression:
+		return r.evaluateBinary(n)
+	case *ast.LogicalExpression:
+		return r.evaluateLogical(n)
+	case *ast.ConditionalExpression:
+		test, err := r.evaluate(n.Test)
+		if err != nil {
+			return nil, err
+		}
+		if lang.ToBoolean(test) {
+			return r.evaluate(n.Consequent)
+		}
+		return r.evaluate(n.Alternate)
+	case *ast.AssignmentExpression:
+		return r.evaluateAssignment(n)
+	case *ast.SequenceExpression:
+		var v lang.Value
+		for _, expr := range n.Expressions {
+			var err errors.Error
+			if v, err = r.evaluate(expr); err != nil {
+				return nil, err
+			}
+		}
+		return v, nil
+	case *ast.YieldExpression, *ast.AwaitExpression:
+		// generator and async functions cannot be called, so this is
+		// never reached
+		return nil, errors.NewSyntaxError(fmt.Sprintf("Unsupported expression %v", n.Type()))
+	}
+	panic(fmt.Sprintf("Unexpected expression %T", n))
+}
+
+// evaluateReference evaluates the given identifier or member expression to
+// a reference.
+// The evaluation of identifier references and property accessors is
+// specified in 12.1.6 and 12.3.2.1.
+func (r *Runtime) evaluateReference(n ast.Node) (*binding.Reference, errors.Error) {
+	switch n := n.(type) {
+	case *ast.Identifier:
+		return r.agent.ResolveBinding(lang.NewString(n.Name), nil, r.strict)
+	case *ast.MemberExpression:
+		if _, ok := n.Object.(*ast.Super); ok {
+			return r.evaluateSuperProperty(n)
+		}
+
+		baseValue, err := r.evaluate(n.Object)
+		if err != nil {
+			return nil, err
+		}
+		var propertyNameValue lang.Value
+		if n.Computed {
+			if propertyNameValue, err = r.evaluate(n.Property); err != nil {
+				return nil, err
+			}
+		} else {
+			propertyNameValue = lang.NewString(n.Property.(*ast.Identifier).Name)
+		}
+
+		propertyKey, err := propertyReferenceKey(baseValue, propertyNameValue)
+		if err != nil {
+			return nil, err
+		}
+		return binding.NewReference(propertyKey, baseValue, r.strict), nil
+	}
+	return nil, errors.NewReferenceError("Invalid left-hand side in assignment")
+}
+
+// propertyReferenceKey checks that the given base value of a property
+// access is neither undefined nor null, and converts the given property
+// name value to a property key.
+func propertyReferenceKey(baseValue, propertyNameValue lang.Value) (lang.StringOrSymbol, errors.Error) {
+	if baseValue == lang.Undefined || baseValue == lang.Null {
+		if propertyNameValue.Type() == lang.TypeString || propertyNameValue.Type() == lang.TypeNumber {
+			name, _ := lang.ToString(propertyNameValue)
+			return lang.StringOrSymbol{}, errors.NewTypeError(fmt.Sprintf("Cannot read properties of %v (reading '%v')", describe(baseValue), name))
+		}
+		return lang.StringOrSymbol{}, errors.NewTypeError(fmt.Sprintf("Cannot read properties of %v", describe(baseValue)))
+	}
+	return lang.ToPropertyKey(propertyNameValue)
+}
+
+// evaluateSuperProperty evaluates a property access on super, that is, a
+// property lookup on the prototype of the home object of the function.
+// The evaluation of SuperProperty is specified in 12.3.5.1.
+func (r *Runtime) evaluateSuperProperty(n *ast.MemberExpression) (*binding.Reference, errors.Error) {
+	env := r.agent.GetThisEnvironment().(*binding.FunctionEnvironment)
+	actualThis, err := env.GetThisBinding()
+	if err != nil {
+		return nil, err
+	}
+
+	var propertyKey lang.StringOrSymbol
+	if n.Computed {
+		propertyNameValue, err := r.evaluate(n.Property)
+		if err != nil {
+			return nil, err
+		}
+		if propertyKey, err = lang.ToPropertyKey(propertyNameValue); err != nil {
+			return nil, err
+		}
+	} else {
+		propertyKey = lang.NewStringKey(n.Property.(*ast.Identifier).Name)
+	}
+
+	baseValue := env.GetSuperBase()
+	if _, err := lang.RequireObjectCoercible(baseValue); err != nil {
+		return nil, err
+	}
+	return binding.NewSuperReference(propertyKey, baseValue, actualThis, r.strict), nil
+}
+
+// evaluateLiteral returns the value of the given literal.
+// The evaluation of literals is specified in 12.2.4.1.
+func (r *Runtime) evaluateLiteral(n *ast.Literal) (lang.Value, errors.Error) {
+	if n.Regex != nil {
+		// 12.2.8.3, a new RegExp object is created on every evaluation
+		rx, err := r.Realm().RegExpCreate(stringValue(n.Regex.Pattern), stringValue(n.Regex.Flags))
+		if err != nil {
+			return nil, err
+		}
+		return rx, nil
+	}
+
+	switch v := n.Value.(type) {
+	case nil:
+		return lang.Null, nil
+	case bool:
+		return lang.Boolean(v), nil
+	case float64:
+		return lang.NewNumber(v), nil
+	case string:
+		return stringValue(v), nil
+	}
+	panic(fmt.Sprintf("Unexpected literal value %T", n.Value))
+}
+
+// propertyKey returns the property key of a property definition or an
+// object pattern. If the key is computed, the key expression is evaluated.
+// The evaluation of PropertyName is specified in 12.2.6.7.
+func (r *Runtime) propertyKey(key ast.Node, computed bool) (lang.StringOrSymbol, errors.Error) {
+	if computed {
+		v, err := r.evaluate(key)
+		if err != nil {
+			return lang.StringOrSymbol{}, err
+		}
+		return lang.ToPropertyKey(v)
+	}
+	return literalPropertyKey(key), nil
+}
+
+// literalPropertyKey returns the property key of the given identifier,
+// string literal or numeric literal, which is the key of a property
+// definition that is not computed.
+func literalPropertyKey(key ast.Node) lang.StringOrSymbol {
+	switch key := key.(type) {
+	case *ast.Identifier:
+		return lang.NewStringKey(key.Name)
+	case *ast.Literal:
+		switch v := key.Value.(type) {
+		case string:
+			return lang.NewStringOrSymbol(stringValue(v))
+		case float64:
+			return lang.NewStringOrSymbol(lang.NumberToString(lang.NewNumber(v)))
+		}
+	}
+	panic(fmt.Sprintf("Unexpected property key %T", key))
+}
+
+// evaluateArrayLiteral creates an array from the given array literal.
+// The evaluation of ArrayLiteral is specified in 12.2.5.3.
+func (r *Runtime) evaluateArrayLiteral(n *ast.ArrayExpression) (lang.Value, errors.Error) {
+	array, err := lang.ArrayCreate(0, r.intrinsic(realm.IntrinsicNameArrayPrototype))
+	if err != nil {
+		return nil, err
+	}
+
+	nextIndex := 0
+	appendValue := func(v lang.Value) errors.Error {
+		_, err := lang.CreateDataPropertyOrThrow(array, lang.NewStringKey(strconv.Itoa(nextIndex)), v)
+		nextIndex++
+		return err
+	}
+	for _, elem := range n.Elements {
+		switch elem := elem.(type) {
+		case nil:
+			// elision
+			nextIndex++
+		case *ast.SpreadElement:
+			if err := r.evaluateSpread(elem, appendValue); err != nil {
+				return nil, err
+			}
+		default:
+			v, err := r.evaluate(elem)
+			if err != nil {
+				return nil, err
+			}
+			if err := appendValue(v); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if _, err := lang.Set(array, lang.NewStringKey("length"), lang.NewNumber(float64(nextIndex)), true); err != nil {
+		return nil, err
+	}
+	return array, nil
+}
+
+// evaluateSpread evaluates the argument of the given spread element, and
+// passes each value produced by iterating the result to fn.
+func (r *Runtime) evaluateSpread(n *ast.SpreadElement, fn func(lang.Value) errors.Error) errors.Error {
+	spreadObj, err := r.evaluate(n.Argument)
+	if err != nil {
+		return err
+	}
+	return r.iterateValues(spreadObj, fn)
+}
+
+// iterateValues passes each value produced by iterating the given value to
+// fn.
+func (r *Runtime) iterateValues(spreadObj lang.Value, fn func(lang.Value) errors.Error) errors.Error {
+	iteratorRecord, err := lang.GetIterator(r.Realm(), spreadObj)
+	if err != nil {
+		return err
+	}
+	for {
+		next, err := iteratorStepValue(iteratorRecord)
+		if err != nil {
+			return err
+		}
+		if iteratorRecord.Done {
+			return nil
+		}
+		if err := fn(next); err != nil {
+			return err
+		}
+	}
+}
+
+// evaluateArguments evaluates the given arguments of a call.
+// ArgumentListEvaluation is specified in 12.3.6.1.
+func (r *Runtime) evaluateArguments(args []ast.Expression) ([]lang.Value, errors.Error) {
+	list := make([]lang.Value, 0, len(args))
+	for _, arg := range args {
+		if spread, ok := arg.(*ast.SpreadElement); ok {
+			err := r.evaluateSpread(spread, func(v lang.Value) errors.Error {
+				list = append(list, v)
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		v, err := r.evaluate(arg)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, v)
+	}
+	return list, nil
+}
+
+// evaluateObjectLiteral creates an object from the given object literal.
+// The evaluation of ObjectLiteral is specified in 12.2.6.7.
+func (r *Runtime) evaluateObjectLiteral(n *ast.ObjectExpression) (lang.Value, errors.Error) {
+	obj := lang.ObjectCreate(r.intrinsic(realm.IntrinsicNameObjectPrototype))
+	for _, prop := range n.Properties {
+		var err errors.Error
+		switch prop := prop.(type) {
+		case *ast.SpreadElement:
+			var fromValue lang.Value
+			if fromValue, err = r.evaluate(prop.Argument); err == nil {
+				_, err = lang.CopyDataProperties(r.Realm(), obj, fromValue, nil)
+			}
+		case *ast.Property:
+			err = r.propertyDefinitionEvaluation(prop, obj)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return obj, nil
+}
+
+// propertyDefinitionEvaluation defines the property of the given property
+// definition of an object literal on the given object.
+// PropertyDefinitionEvaluation is specified in 12.2.6.8.
+func (r *Runtime) propertyDefinitionEvaluation(prop *ast.Property, obj *lang.Object) errors.Error {
+	if prop.Method || prop.Kind == ast.KindGet || prop.Kind == ast.KindSet {
+		return r.methodDefinitionEvaluation(prop.Key, prop.Computed, prop.Kind, prop.Value.(*ast.FunctionExpression), obj, true)
+	}
+
+	if !prop.Computed && !prop.Shorthand {
+		if key, _ := r.propertyKey(prop.Key, false); key.String().String() == "__proto__" {
+			// __proto__: value sets the prototype of the object
+			v, err := r.evaluate(prop.Value)
+			if err != nil {
+				return err
+			}
+			if v.Type() == lang.TypeObject || v.Type() == lang.TypeNull {
+				obj.SetPrototypeOf(v)
+			}
+			return nil
+		}
+	}
+
+	propKey, err := r.propertyKey(prop.Key, prop.Computed)
+	if err != nil {
+		return err
+	}
+	var propValue lang.Value
+	if isAnonymousFunctionDefinition(prop.Value) {
+		propValue, err = r.namedEvaluation(prop.Value, propKey)
+	} else {
+		propValue, err = r.evaluate(prop.Value)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = lang.CreateDataPropertyOrThrow(obj, propKey, propValue)
+	return err
+}
+
+// methodDefinitionEvaluation defines the method, getter or setter with the
+// given key on the given object, whose home object is the object.
+// MethodDefinitionEvaluation is specified in 14.3.8, 14.4.13 and 14.7.10.
+func (r *Runtime) methodDefinitionEvaluation(key ast.Node, computed bool, kind string, value *ast.FunctionExpression, obj *lang.Object, enumerable bool) errors.Error {
+	propKey, err := r.propertyKey(key, computed)
+	if err != nil {
+		return err
+	}
+
+	closure := r.functionCreate(&value.Function, r.lexicalEnvironment(), nil, false, false)
+	closure.makeMethod(obj)
+
+	// the descriptor of a getter has no [[Set]] field and the reverse, so
+	// that a getter and a setter with the same key form a single property
+	var desc *lang.Property
+	switch kind {
+	case ast.KindGet:
+		lang.SetFunctionName(closure.object, propKey, "get")
+		desc = lang.NewPropertyBase(lang.Boolean(enumerable), lang.True)
+		desc.SetField(lang.FieldNameGet, closure.object)
+	case ast.KindSet:
+		lang.SetFunctionName(closure.object, propKey, "set")
+		desc = lang.NewPropertyBase(lang.Boolean(enumerable), lang.True)
+		desc.SetField(lang.FieldNameSet, closure.object)
+	default:
+		lang.SetFunctionName(closure.object, propKey, "")
+		desc = lang.NewDataProperty(closure.object, lang.True, lang.Boolean(enumerable), lang.True)
+	}
+	_, err = lang.DefinePropertyOrThrow(obj, propKey, desc)
+	return err
+}
+
+// namedEvaluation evaluates the given anonymous function or class
+// definition, whose name is the given name.
+// NamedEvaluation is specified in 12.15.2, 14.1.21, 14.2.17 and 14.6.15.
+func (r *Runtime) namedEvaluation(n ast.Node, name lang.StringOrSymbol) (lang.Value, errors.Error) {
+	switch n := n.(type) {
+	case *ast.FunctionExpression:
+		return r.evaluateFunctionExpression(n, name)
+	case *ast.ArrowFunctionExpression:
+		return r.evaluateArrowFunction(n, name), nil
+	case *ast.ClassExpression:
+		return r.evaluateClassExpression(n, name)
+	}
+	return r.evaluate(n)
+}
+
+// evaluateFunctionExpression creates the function object of the given
+// function expression. If the function expression has no name, the given
+// name is used as name of the function.
+// The evaluation of FunctionExpression is specified in 14.1.22.
+func (r *Runtime) evaluateFunctionExpression(n *ast.FunctionExpression, name lang.StringOrSymbol) (lang.Value, errors.Error) {
+	scope := r.lexicalEnvironment()
+	if n.ID == nil {
+		return r.functionDefinition(&n.Function, scope, name).object, nil
+	}
+
+	// the name of a named function expression is bound in an environment
+	// of its own, so that the function can refer to itself
+	funcEnv := binding.NewDeclarativeEnvironment(scope)
+	funcName := lang.NewString(n.ID.Name)
+	_ = funcEnv.CreateImmutableBinding(funcName, false)
+	closure := r.functionDefinition(&n.Function, funcEnv, lang.NewStringOrSymbol(funcName))
+	_ = funcEnv.InitializeBinding(funcName, closure.object)
+	return closure.object, nil
+}
+
+// evaluateArrowFunction creates the function object of the given arrow
+// function with the given name.
+// The evaluation of ArrowFunction is specified in 14.2.17.
+func (r *Runtime) evaluateArrowFunction(n *ast.ArrowFunctionExpression, name lang.StringOrSymbol) lang.Value {
+	closure := r.functionCreate(&n.Function, r.lexicalEnvironment(), nil, false, true)
+	lang.SetFunctionName(closure.object, name, "")
+	return closure.object
+}
+
+// evaluateTemplateLiteral concatenates the strings and substitutions of the
+// given template literal.
+// The evaluation of TemplateLiteral is specified in 12.2.9.6.
+func (r *Runtime) evaluateTemplateLiteral(n *ast.TemplateLiteral) (lang.Value, errors.Error) {
+	parts := make([]lang.String, 0, len(n.Quasis)+len(n.Expressions))
+	for i, quasi := range n.Quasis {
+		parts = append(parts, stringValue(*quasi.Cooked))
+		if i >= len(n.Expressions) {
+			continue
+		}
+
+		sub, err := r.evaluate(n.Expressions[i])
+		if err != nil {
+			return nil, err
+		}
+		middle, err := lang.ToString(sub)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, middle)
+	}
+	return r.concat(parts...)
+}
+
+// evaluateTaggedTemplate calls the tag function of the given tagged
+// template with the template object and the substitutions.
+// The evaluation of tagged templates is specified in 12.3.7.1.
+func (r *Runtime) evaluateTaggedTemplate(n *ast.TaggedTemplateExpression) (lang.Value, errors.Error) {
+	tagFunc, thisValue, err := r.evaluateCallee(n.Tag)
+	if err != nil {
+		return nil, err
+	}
+
+	args := []lang.Value{r.getTemplateObject(n.Quasi)}
+	for _, expr := range n.Quasi.Expressions {
+		sub, err := r.evaluate(expr)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, sub)
+	}
+	return r.evaluateCallTo(n.Tag, tagFunc, thisValue, args)
+}
+
+// getTemplateObject returns the frozen template object of the given
+// template literal, which holds the cooked strings, and the raw strings in
+// its raw property. Template objects are cached in the template map of the
+// realm, so every evaluation of the same template literal returns the same
+// object.
+// GetTemplateObject is specified in 12.2.9.4.
+func (r *Runtime) getTemplateObject(n *ast.TemplateLiteral) *lang.Object {
+	rlm := r.Realm()
+	if template, ok := rlm.TemplateMap[n]; ok {
+		return template
+	}
+
+	arrayProto := r.intrinsic(realm.IntrinsicNameArrayPrototype)
+	template, _ := lang.ArrayCreate(float64(len(n.Quasis)), arrayProto)
+	rawObj, _ := lang.ArrayCreate(float64(len(n.Quasis)), arrayProto)
+	for index, quasi := range n.Quasis {
+		prop := lang.NewStringKey(strconv.Itoa(index))
+		var cookedValue lang.Value = lang.Undefined
+		if quasi.Cooked != nil {
+			cookedValue = stringValue(*quasi.Cooked)
+		}
+		_, _ = lang.DefinePropertyOrThrow(template, prop, lang.NewDataProperty(cookedValue, lang.False, lang.True, lang.False))
+		_, _ = lang.DefinePropertyOrThrow(rawObj, prop, lang.NewDataProperty(stringValue(quasi.Raw), lang.False, lang.True, lang.False))
+	}
+	_, _ = lang.SetIntegrityLevel(rawObj, lang.IntegrityLevelFrozen)
+	_, _ = lang.DefinePropertyOrThrow(template, lang.NewStringKey("raw"), lang.NewDataProperty(rawObj, lang.False, lang.False, lang.False))
+	_, _ = lang.SetIntegrityLevel(template, lang.IntegrityLevelFrozen)
+
+	rlm.TemplateMap[n] = template
+	return template
+}
+
+// evaluateCallee evaluates the callee of a call, and returns the function
+// value and the this value for the call. If the callee is a property
+// reference, its base is the this value.
+// The evaluation of the callee is specified in 12.3.4.1.
+func (r *Runtime) evaluateCallee(callee ast.Node) (lang.Value, lang.Value, errors.Error) {
+	switch callee.(type) {
+	case *ast.Identifier, *ast.MemberExpression:
+	default:
+		f, err := r.evaluate(callee)
+		return f, lang.Undefined, err
+	}
+
+	ref, err := r.evaluateReference(callee)
+	if err != nil {
+		return nil, nil, err
+	}
+	f, err := ref.GetValue(r.Realm())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if ref.IsPropertyReference() {
+		return f, ref.GetThisValue(), nil
+	}
+	return f, ref.GetBase().(binding.Environment).WithBaseObject(), nil
+}
+
+// evaluateCall evaluates the given call expression.
+// The evaluation of CallExpression is specified in 12.3.4.1.
+func (r *Runtime) evaluateCall(n *ast.CallExpression) (lang.Value, errors.Error) {
+	pos := r.enter(n)
+	if _, ok := n.Callee.(*ast.Super); ok {
+		v, err := r.evaluateSuperCall(n)
+		if err == nil {
+			r.leave(pos)
+		}
+		return v, err
+	}
+
+	f, thisValue, err := r.evaluateCallee(n.Callee)
+	if err != nil {
+		return nil, err
+	}
+	args, err := r.evaluateArguments(n.Arguments)
+	if err != nil {
+		return nil, err
+	}
+	v, err := r.evaluateCallTo(n.Callee, f, thisValue, args)
+	if err == nil {
+		r.leave(pos)
+	}
+	return v, err
+}
+
+// evaluateCallTo calls the given function value, which is the value of the
+// given callee expression. A call of %eval% through an identifier named
+// eval is a direct eval, whose code is evaluated in the environments of
+// the caller.
+// EvaluateCall is specified in 12.3.4.2, and direct eval in 12.3.4.1.
+func (r *Runtime) evaluateCallTo(callee ast.Node, f, thisValue lang.Value, args []lang.Value) (lang.Value, errors.Error) {
+	if !lang.InternalIsCallable(f) {
+		return nil, errors.NewTypeError(fmt.Sprintf("%v is not a function", sourceName(callee)))
+	}
+	if id, ok := callee.(*ast.Identifier); ok && id.Name == "eval" && f == lang.Value(r.intrinsic(realm.IntrinsicNameEval)) {
+		if len(args) == 0 {
+			return lang.Undefined, nil
+		}
+		return r.evaluateEval(args[0], true)
+	}
+	return lang.Call(f.(*lang.Object), thisValue, args...)
+}
+
+// evaluateNew evaluates the given new expression.
+// EvaluateNew is specified in 12.3.3.1.1.
+func (r *Runtime) evaluateNew(n *ast.NewExpression) (lang.Value, errors.Error) {
+	pos := r.enter(n)
+	constructor, err := r.evaluate(n.Callee)
+	if err != nil {
+		return nil, err
+	}
+	args, err := r.evaluateArguments(n.Arguments)
+	if err != nil {
+		return nil, err
+	}
+	v, err := r.evaluateNewTo(n.Callee, constructor, args)
+	if err == nil {
+		r.leave(pos)
+	}
+	return v, err
+}
+
+// evaluateNewTo constructs a new object with the given constructor value,
+// which is the value of the given callee expression.
+func (r *Runtime) evaluateNewTo(callee ast.Node, constructor lang.Value, args []lang.Value) (lang.Value, errors.Error) {
+	if !lang.InternalIsConstructor(constructor) {
+		return nil, errors.NewTypeError(fmt.Sprintf("%v is not a constructor", sourceName(callee)))
+	}
+	return lang.Construct(constructor.(*lang.Object), nil, args...)
+}
+
+// evaluateSuperCall calls the constructor of the parent class with the
+// given arguments, and binds the result as this value.
+// The evaluation of SuperCall is specified in 12.3.5.1.
+func (r *Runtime) evaluateSuperCall(n *ast.CallExpression) (lang.Value, errors.Error) {
+	newTarget := r.agent.GetNewTarget()
+	thisER := r.agent.GetThisEnvironment().(*binding.FunctionEnvironment)
+	activeFunction := thisER.FunctionObject
+	superConstructor := activeFunction.GetPrototypeOf()
+
+	args, err := r.evaluateArguments(n.Arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	if !lang.InternalIsConstructor(superConstructor) {
+		return nil, errors.NewTypeError("Super constructor is not a constructor")
+	}
+	result, err := lang.Construct(superConstructor.(*lang.Object), newTarget.(*lang.Object), args...)
+	if err != nil {
+		return nil, err
+	}
+	return thisER.BindThisValue(result)
+}
+
+// sourceName returns a short representation of the given expression, like
+// its source code, that is used in error messages.
+func sourceName(n ast.Node) string {
+	switch n := n.(type) {
+	case *ast.Identifier:
+		return n.Name
+	case *ast.ThisExpression:
+		return "this"
+	case *ast.Super:
+		return "super"
+	case *ast.MemberExpression:
+		if !n.Computed {
+			return sourceName(n.Object) + "." + n.Property.(*ast.Identifier).Name
+		}
+		if lit, ok := n.Property.(*ast.Literal); ok && lit.Regex == nil {
+			return sourceName(n.Object) + "[" + lit.Raw + "]"
+		}
+		return sourceName(n.Object) + "[...]"
+	}
+	return "(intermediate value)"
+}
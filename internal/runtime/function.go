@@ -0,0 +1,469 @@
+package runtime
+
+import (
+	"fmt"
+
+	"github.com/gojisvm/gojis/internal/parser/ast"
+	"github.com/gojisvm/gojis/internal/runtime/agent"
+	"github.com/gojisvm/gojis/internal/runtime/binding"
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+	"github.com/gojisvm/gojis/internal/runtime/realm"
+)
+
+// functionKind is the [[FunctionKind]] of an ECMAScript function object.
+type functionKind uint8
+
+// Available function kinds, as used by FunctionAllocate in 9.2.3.
+const (
+	functionKindNormal functionKind = iota
+	functionKindNonConstructor
+	functionKindClassConstructor
+	functionKindGenerator
+	functionKindAsync
+)
+
+// thisMode is the [[ThisMode]] of an ECMAScript function object, as
+// described in 9.2, Table 27.
+type thisMode uint8
+
+// Available this modes.
+const (
+	thisModeGlobal thisMode = iota
+	thisModeStrict
+	thisModeLexical
+)
+
+// constructorKind is the [[ConstructorKind]] of an ECMAScript function
+// object, as described in 9.2, Table 27.
+type constructorKind uint8
+
+// Available constructor kinds.
+const (
+	constructorKindBase constructorKind = iota
+	constructorKindDerived
+)
+
+// function holds the internal slots of an ECMAScript function object, that
+// is, of a function whose code is ECMAScript code. The Call and Construct
+// internal methods of the function object are implemented by its methods.
+// ECMAScript function objects are specified in 9.2.
+type function struct {
+	runtime *Runtime
+	object  *lang.Object
+
+	kind            functionKind
+	constructorKind constructorKind
+	thisMode        thisMode
+	strict          bool
+
+	environment binding.Environment
+	code        *ast.Function
+	realm       *realm.Realm
+	homeObject  lang.Value // Object or Undefined
+}
+
+// functionAllocate allocates a new function object with the given
+// prototype. The internal methods of the object are set, but the function
+// must be initialized with functionInitialize before it can be called.
+// FunctionAllocate is specified in 9.2.3.
+func (r *Runtime) functionAllocate(proto *lang.Object, strict bool, kind functionKind) *function {
+	f := new(function)
+	f.runtime = r
+	f.kind = kind
+	f.strict = strict
+	f.realm = r.Realm()
+	f.homeObject = lang.Undefined
+
+	f.object = lang.ObjectCreate(proto)
+	f.object.Realm = f.realm
+	f.object.ScriptOrModule = r.agent.GetActiveScriptOrModule()
+	f.object.Call = f.call
+	if kind == functionKindNormal || kind == functionKindClassConstructor {
+		f.object.Construct = f.construct
+	}
+	return f
+}
+
+// functionInitialize sets the length property and the remaining internal
+// slots of the given function. arrow must be true for arrow functions,
+// whose this value is lexically bound.
+// FunctionInitialize is specified in 9.2.4.
+func (f *function) initialize(code *ast.Function, scope binding.Environment, arrow bool) {
+	lang.SetFunctionLength(f.object, expectedArgumentCount(code.Params))
+	f.environment = scope
+	f.code = code
+	switch {
+	case arrow:
+		f.thisMode = thisModeLexical
+	case f.strict:
+		f.thisMode = thisModeStrict
+	default:
+		f.thisMode = thisModeGlobal
+	}
+}
+
+// functionCreate creates a new function object for the given code, whose
+// scope is the given environment. Only normal functions, which are not
+// arrow functions or methods, are constructors. If proto is nil,
+// %FunctionPrototype% is used as prototype.
+// FunctionCreate is specified in 9.2.5.
+func (r *Runtime) functionCreate(code *ast.Function, scope binding.Environment, proto *lang.Object, normal, arrow bool) *function {
+	if proto == nil {
+		proto = r.intrinsic(realm.IntrinsicNameFunctionPrototype)
+	}
+	allocKind := functionKindNonConstructor
+	switch {
+	case code.Generator:
+		allocKind = functionKindGenerator
+	case code.Async:
+		allocKind = functionKindAsync
+	case normal:
+		allocKind = functionKindNormal
+	}
+	f := r.functionAllocate(proto, code.Strict, allocKind)
+	f.initialize(code, scope, arrow)
+	return f
+}
+
+// functionDefinition creates the function object of a function declaration
+// or expression with the given name, which is a constructor unless it is a
+// generator or async function.
+func (r *Runtime) functionDefinition(code *ast.Function, scope binding.Environment, name lang.StringOrSymbol) *function {
+	f := r.functionCreate(code, scope, nil, true, false)
+	lang.SetFunctionName(f.object, name, "")
+	if f.kind == functionKindNormal {
+		lang.MakeConstructor(r.Realm(), f.object, true, nil)
+	}
+	return f
+}
+
+// makeMethod configures the function as a method of the given home object,
+// which is used to look up properties with super.
+// MakeMethod is specified in 9.2.12.
+func (f *function) makeMethod(homeObject *lang.Object) {
+	f.homeObject = homeObject
+}
+
+// name returns the value of the name property of the function, as used in
+// error messages.
+func (f *function) name() string {
+	desc := f.object.GetOwnProperty(lang.NewStringKey("name"))
+	if desc == nil || !desc.IsDataDescriptor() || desc.Value().Type() != lang.TypeString {
+		return ""
+	}
+	name, _ := lang.ToString(desc.Value())
+	return name.String()
+}
+
+// call is the Call internal method of the function.
+// Call is specified in 9.2.1.
+func (f *function) call(thisArgument lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+	switch f.kind {
+	case functionKindClassConstructor:
+		return nil, errors.NewTypeError(fmt.Sprintf("Class constructor %v cannot be invoked without 'new'", f.name()))
+	case functionKindGenerator:
+		return nil, errors.NewTypeError("Generator functions are not supported yet")
+	case functionKindAsync:
+		return nil, errors.NewTypeError("Async functions are not supported yet")
+	}
+
+	calleeContext, err := f.prepareForOrdinaryCall(lang.Undefined)
+	if err != nil {
+		return nil, err
+	}
+	defer f.runtime.agent.ExecutionContextStack.Pop()
+	f.ordinaryCallBindThis(calleeContext, thisArgument)
+	result := f.ordinaryCallEvaluateBody(args)
+
+	switch result.Type {
+	case lang.CompletionReturn:
+		return result.Value, nil
+	case lang.CompletionThrow:
+		return nil, lang.NewThrowError(result.Value)
+	}
+	return lang.Undefined, nil
+}
+
+// construct is the Construct internal method of the function.
+// Construct is specified in 9.2.2.
+func (f *function) construct(newTarget *lang.Object, args ...lang.Value) (*lang.Object, errors.Error) {
+	var thisArgument *lang.Object
+	if f.constructorKind == constructorKindBase {
+		var err errors.Error
+		thisArgument, err = realm.OrdinaryCreateFromConstructor(newTarget, realm.IntrinsicNameObjectPrototype)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	calleeContext, err := f.prepareForOrdinaryCall(newTarget)
+	if err != nil {
+		return nil, err
+	}
+	defer f.runtime.agent.ExecutionContextStack.Pop()
+	if f.constructorKind == constructorKindBase {
+		f.ordinaryCallBindThis(calleeContext, thisArgument)
+	}
+	constructorEnv := calleeContext.LexicalEnvironment.(*binding.FunctionEnvironment)
+	result := f.ordinaryCallEvaluateBody(args)
+
+	switch result.Type {
+	case lang.CompletionThrow:
+		return nil, lang.NewThrowError(result.Value)
+	case lang.CompletionReturn:
+		if o, ok := result.Value.(*lang.Object); ok {
+			return o, nil
+		}
+		if f.constructorKind == constructorKindBase {
+			return thisArgument, nil
+		}
+		if result.Value != lang.Undefined {
+			return nil, errors.NewTypeError("Derived constructors may only return object or undefined")
+		}
+	default:
+		if f.constructorKind == constructorKindBase {
+			return thisArgument, nil
+		}
+	}
+
+	thisBinding, err := constructorEnv.GetThisBinding()
+	if err != nil {
+		return nil, err
+	}
+	return thisBinding.(*lang.Object), nil
+}
+
+// prepareForOrdinaryCall creates the execution context for a call of the
+// function, pushes it onto the execution context stack and returns it. The
+// call counts as a step of the evaluation. A RangeError is returned if the
+// execution context stack is full.
+// PrepareForOrdinaryCall is specified in 9.2.1.1.
+func (f *function) prepareForOrdinaryCall(newTarget lang.Value) (*agent.ExecutionContext, errors.Error) {
+	f.runtime.step()
+	localEnv := binding.NewFunctionEnvironment(f.environment, f.object, f.thisMode == thisModeLexical, f.homeObject, newTarget)
+	calleeContext := &agent.ExecutionContext{
+		Function:            f.object,
+		Realm:               f.realm,
+		ScriptOrModule:      f.object.ScriptOrModule.(lang.InternalValue),
+		LexicalEnvironment:  localEnv,
+		VariableEnvironment: localEnv,
+		Position:            f.code.Body.Loc().Start,
+	}
+	if err := f.runtime.agent.ExecutionContextStack.Push(calleeContext); err != nil {
+		return nil, err
+	}
+	return calleeContext, nil
+}
+
+// ordinaryCallBindThis binds the this value of the call in the function
+// environment of the given callee context.
+// OrdinaryCallBindThis is specified in 9.2.1.2.
+func (f *function) ordinaryCallBindThis(calleeContext *agent.ExecutionContext, thisArgument lang.Value) {
+	if f.thisMode == thisModeLexical {
+		return
+	}
+
+	thisValue := thisArgument
+	if f.thisMode == thisModeGlobal {
+		if thisArgument == lang.Undefined || thisArgument == lang.Null {
+			thisValue = f.realm.GlobalEnvironment().GlobalThisValue
+		} else {
+			thisValue, _ = lang.ToObject(f.realm, thisArgument)
+		}
+	}
+
+	localEnv := calleeContext.LexicalEnvironment.(*binding.FunctionEnvironment)
+	_, _ = localEnv.BindThisValue(thisValue)
+}
+
+// ordinaryCallEvaluateBody instantiates the declarations of the function
+// and evaluates its body with the given arguments.
+// OrdinaryCallEvaluateBody is specified in 9.2.1.3.
+func (f *function) ordinaryCallEvaluateBody(args []lang.Value) lang.Completion {
+	r := f.runtime
+	strict := r.strict
+	r.strict = f.strict
+	defer func() { r.strict = strict }()
+
+	if err := f.functionDeclarationInstantiation(args); err != nil {
+		return r.throw(err)
+	}
+	if r.engine == EngineBytecode {
+		return r.run(f.object.ScriptOrModule.(*scriptRecord).compiled(f.code))
+	}
+
+	body, ok := f.code.Body.(*ast.BlockStatement)
+	if !ok {
+		// concise body of an arrow function
+		v, err := r.evaluate(f.code.Body)
+		if err != nil {
+			return r.throw(err)
+		}
+		return lang.Completion{Type: lang.CompletionReturn, Value: v}
+	}
+	return r.evaluateStatementList(body.Body)
+}
+
+// functionDeclarationInstantiation creates the bindings of the parameters
+// and the declarations of the function in the environment of the running
+// execution context, and binds the given arguments to the parameters.
+// FunctionDeclarationInstantiation is specified in 9.2.15.
+func (f *function) functionDeclarationInstantiation(args []lang.Value) errors.Error {
+	r := f.runtime
+	calleeContext := r.context()
+	env := calleeContext.LexicalEnvironment.(*binding.FunctionEnvironment)
+
+	var body []ast.Statement
+	if block, ok := f.code.Body.(*ast.BlockStatement); ok {
+		body = block.Body
+	}
+
+	formals := f.code.Params
+	parameterNames := parameterNames(formals)
+	hasDuplicates := false
+	for i, name := range parameterNames {
+		if containsString(parameterNames[:i], name) {
+			hasDuplicates = true
+			break
+		}
+	}
+	simpleParameterList := isSimpleParameterList(formals)
+	hasParameterExpressions := containsExpression(formals)
+
+	varDeclarations := varScopedDeclarations(body, true)
+	varNames := declarationNames(varDeclarations)
+	lexDeclarations := lexicallyScopedDeclarations(body, true)
+	lexicalNames := declarationNames(lexDeclarations)
+
+	var functionNames []string
+	var functionsToInitialize []*ast.FunctionDeclaration
+	for i := len(varDeclarations) - 1; i >= 0; i-- {
+		d, ok := varDeclarations[i].(*ast.FunctionDeclaration)
+		if !ok {
+			continue
+		}
+		fn := boundNames(d)[0]
+		if !containsString(functionNames, fn) {
+			functionNames = append(functionNames, fn)
+			functionsToInitialize = append([]*ast.FunctionDeclaration{d}, functionsToInitialize...)
+		}
+	}
+
+	argumentsObjectNeeded := true
+	switch {
+	case f.thisMode == thisModeLexical:
+		argumentsObjectNeeded = false
+	case containsString(parameterNames, "arguments"):
+		argumentsObjectNeeded = false
+	case !hasParameterExpressions && (containsString(functionNames, "arguments") || containsString(lexicalNames, "arguments")):
+		argumentsObjectNeeded = false
+	}
+
+	for _, paramName := range parameterNames {
+		n := lang.NewString(paramName)
+		if alreadyDeclared, _ := env.HasBinding(n); !alreadyDeclared {
+			_ = env.CreateMutableBinding(n, false)
+			if hasDuplicates {
+				_ = env.InitializeBinding(n, lang.Undefined)
+			}
+		}
+	}
+
+	parameterBindings := parameterNames
+	if argumentsObjectNeeded {
+		var ao *lang.Object
+		if f.strict || !simpleParameterList {
+			ao = r.createUnmappedArgumentsObject(args)
+		} else {
+			ao = f.createMappedArgumentsObject(args, parameterNames, env)
+		}
+		n := lang.NewString("arguments")
+		if f.strict {
+			_ = env.CreateImmutableBinding(n, false)
+		} else {
+			_ = env.CreateMutableBinding(n, false)
+		}
+		_ = env.InitializeBinding(n, ao)
+		parameterBindings = append(parameterBindings[:len(parameterBindings):len(parameterBindings)], "arguments")
+	}
+
+	var paramEnv binding.Environment = env
+	if hasDuplicates {
+		// parameters are bound with PutValue
+		paramEnv = nil
+	}
+	if err := r.formalParametersInitialization(formals, args, paramEnv); err != nil {
+		return err
+	}
+
+	var varEnv binding.Environment
+	instantiatedVarNames := append([]string(nil), parameterBindings...)
+	if !hasParameterExpressions {
+		for _, n := range varNames {
+			if !containsString(instantiatedVarNames, n) {
+				instantiatedVarNames = append(instantiatedVarNames, n)
+				_ = env.CreateMutableBinding(lang.NewString(n), false)
+				_ = env.InitializeBinding(lang.NewString(n), lang.Undefined)
+			}
+		}
+		varEnv = env
+	} else {
+		// a separate environment is needed to ensure that closures created
+		// by expressions in the parameter list do not have visibility of
+		// declarations in the function body
+		varEnvRec := binding.NewDeclarativeEnvironment(env)
+		calleeContext.VariableEnvironment = varEnvRec
+		for _, n := range varNames {
+			if containsString(instantiatedVarNames, n) {
+				continue
+			}
+			instantiatedVarNames = append(instantiatedVarNames, n)
+			_ = varEnvRec.CreateMutableBinding(lang.NewString(n), false)
+			var initialValue lang.Value = lang.Undefined
+			if containsString(parameterBindings, n) && !containsString(functionNames, n) {
+				initialValue, _ = env.GetBindingValue(lang.NewString(n), false)
+			}
+			_ = varEnvRec.InitializeBinding(lang.NewString(n), initialValue)
+		}
+		varEnv = varEnvRec
+	}
+
+	if !f.strict {
+		for _, fd := range blockFunctionDeclarations(body, parameterNames) {
+			fn := boundNames(fd)[0]
+			if !containsString(instantiatedVarNames, fn) && fn != "arguments" {
+				_ = varEnv.CreateMutableBinding(lang.NewString(fn), false)
+				_ = varEnv.InitializeBinding(lang.NewString(fn), lang.Undefined)
+				instantiatedVarNames = append(instantiatedVarNames, fn)
+			}
+			r.markBlockFunction(fd)
+		}
+	}
+
+	lexEnv := varEnv
+	if !f.strict {
+		// non-strict functions use a separate lexical environment for
+		// top-level lexical declarations, so that direct eval can determine
+		// whether var declarations conflict with them
+		lexEnv = binding.NewDeclarativeEnvironment(varEnv)
+	}
+	calleeContext.LexicalEnvironment = lexEnv
+
+	for _, d := range lexDeclarations {
+		for _, dn := range boundNames(d) {
+			if isConstantDeclaration(d) {
+				_ = lexEnv.CreateImmutableBinding(lang.NewString(dn), true)
+			} else {
+				_ = lexEnv.CreateMutableBinding(lang.NewString(dn), false)
+			}
+		}
+	}
+
+	for _, fd := range functionsToInitialize {
+		fn := boundNames(fd)[0]
+		fo := r.instantiateFunctionObject(fd, lexEnv)
+		_ = varEnv.SetMutableBinding(lang.NewString(fn), fo, false)
+	}
+	return nil
+}
@@ -0,0 +1,703 @@
+package runtime
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gojisvm/gojis/internal/parser/ast"
+	"github.com/gojisvm/gojis/internal/runtime/binding"
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+	"github.com/gojisvm/gojis/internal/runtime/realm"
+)
+
+// handler is an exception handler that was installed by a try instruction.
+// If an exception is thrown, the stack is truncated to sp, the lexical
+// environment is restored to env, and execution continues at the catch
+// target, or at the finally target with a pending throw completion.
+type handler struct {
+	catch, finally int
+	sp             int
+	env            binding.Environment
+}
+
+// pendingCompletion is the completion that is resumed at the end of a
+// finally block. If throw is true, thrown is rethrown. Otherwise execution
+// continues at target, or after the finally block if target is -1. The
+// completion value of the script when the finally block was entered is
+// restored.
+type pendingCompletion struct {
+	target     int
+	throw      bool
+	thrown     lang.Value
+	completion lang.Value
+}
+
+// Type returns lang.TypeInternal.
+func (*pendingCompletion) Type() lang.Type { return lang.TypeInternal }
+
+// Value returns the pending completion itself.
+func (p *pendingCompletion) Value() interface{} { return p }
+
+// iteratorValue holds the iterator record of a for-of loop in a slot.
+type iteratorValue struct {
+	record *lang.IteratorRecord
+}
+
+// Type returns lang.TypeInternal.
+func (*iteratorValue) Type() lang.Type { return lang.TypeInternal }
+
+// Value returns the iterator value itself.
+func (i *iteratorValue) Value() interface{} { return i }
+
+// enumeratorValue holds the property enumeration of a for-in loop in a
+// slot.
+type enumeratorValue struct {
+	next func() (lang.Value, bool, errors.Error)
+}
+
+// Type returns lang.TypeInternal.
+func (*enumeratorValue) Type() lang.Type { return lang.TypeInternal }
+
+// Value returns the enumerator value itself.
+func (e *enumeratorValue) Value() interface{} { return e }
+
+// valueList is a list of values on the stack, which holds the arguments of
+// a call with spread arguments or the elements of an array literal. Holes
+// of array literals are nil.
+type valueList struct {
+	values []lang.Value
+}
+
+// Type returns lang.TypeInternal.
+func (*valueList) Type() lang.Type { return lang.TypeInternal }
+
+// Value returns the list itself.
+func (l *valueList) Value() interface{} { return l }
+
+// run executes the given code in the running execution context, and
+// returns the completion of the code. The completion of a script is a
+// normal completion with the completion value of the script, and the
+// completion of a function body is a return completion.
+func (r *Runtime) run(c *code) lang.Completion {
+	ctx := r.context()
+	slots := make([]lang.Value, len(c.slotNames))
+	for _, p := range c.prologue {
+		ref, err := r.agent.ResolveBinding(p.name, nil, r.strict)
+		if err != nil {
+			return r.throw(err)
+		}
+		v, err := ref.GetValue(r.Realm())
+		if err != nil {
+			return r.throw(err)
+		}
+		slots[p.slot] = v
+	}
+
+	stack := make([]lang.Value, 0, 16)
+	var handlers []handler
+	var completion, returnValue lang.Value = lang.Undefined, lang.Undefined
+
+	push := func(v lang.Value) {
+		stack = append(stack, v)
+	}
+	pop := func() lang.Value {
+		v := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		return v
+	}
+
+	for pc := 0; pc < len(c.instructions); {
+		ins := c.instructions[pc]
+		ctx.Position = c.positions[pc]
+		pc++
+
+		var err errors.Error
+		switch ins.op {
+		case opNop:
+		case opConst:
+			push(c.values[ins.a])
+		case opUndefined:
+			push(lang.Undefined)
+		case opPop:
+			stack = stack[:len(stack)-1]
+		case opDup:
+			push(stack[len(stack)-1])
+		case opDup2:
+			stack = append(stack, stack[len(stack)-2], stack[len(stack)-1])
+		case opSwap:
+			top := len(stack) - 1
+			stack[top], stack[top-1] = stack[top-1], stack[top]
+
+		case opLoadSlot:
+			if slots[ins.a] == nil {
+				err = uninitializedError(c.slotNames[ins.a])
+				break
+			}
+			push(slots[ins.a])
+		case opStoreSlot:
+			if slots[ins.a] == nil {
+				err = uninitializedError(c.slotNames[ins.a])
+				break
+			}
+			slots[ins.a] = stack[len(stack)-1]
+		case opInitSlot:
+			slots[ins.a] = pop()
+		case opClearSlot:
+			slots[ins.a] = nil
+		case opThrowConstAssign:
+			if slots[ins.a] == nil {
+				err = uninitializedError(c.slotNames[ins.a])
+				break
+			}
+			err = errors.NewTypeError("Assignment to constant variable.")
+		case opLoadName:
+			var ref *binding.Reference
+			if ref, err = r.agent.ResolveBinding(c.names[ins.a], nil, r.strict); err != nil {
+				break
+			}
+			var v lang.Value
+			if v, err = ref.GetValue(r.Realm()); err == nil {
+				push(v)
+			}
+		case opLoadNameThis:
+			var ref *binding.Reference
+			if ref, err = r.agent.ResolveBinding(c.names[ins.a], nil, r.strict); err != nil {
+				break
+			}
+			var f lang.Value
+			if f, err = ref.GetValue(r.Realm()); err == nil {
+				push(f)
+				push(ref.GetBase().(binding.Environment).WithBaseObject())
+			}
+		case opTypeofName:
+			var ref *binding.Reference
+			if ref, err = r.agent.ResolveBinding(c.names[ins.a], nil, r.strict); err != nil {
+				break
+			}
+			if ref.IsUnresolvableReference() {
+				push(lang.NewString("undefined"))
+				break
+			}
+			var v lang.Value
+			if v, err = ref.GetValue(r.Realm()); err == nil {
+				push(lang.NewString(typeOf(v)))
+			}
+		case opStoreName:
+			var ref *binding.Reference
+			if ref, err = r.agent.ResolveBinding(c.names[ins.a], nil, r.strict); err == nil {
+				err = ref.PutValue(r.Realm(), stack[len(stack)-1])
+			}
+		case opInitBinding:
+			var ref *binding.Reference
+			if ref, err = r.agent.ResolveBinding(c.names[ins.a], ctx.LexicalEnvironment, r.strict); err == nil {
+				err = ref.InitializeReferencedBinding(pop())
+			}
+		case opDeleteName:
+			var ref *binding.Reference
+			if ref, err = r.agent.ResolveBinding(c.names[ins.a], nil, r.strict); err != nil {
+				break
+			}
+			var v lang.Value
+			if v, err = r.deleteReference(ref); err == nil {
+				push(v)
+			}
+		case opThis:
+			var v lang.Value
+			if v, err = r.agent.ResolveThisBinding(); err == nil {
+				push(v)
+			}
+		case opNewTarget:
+			push(r.agent.GetNewTarget())
+
+		case opGetPropNamed:
+			var v lang.Value
+			if v, err = r.getProperty(pop(), c.keys[ins.a]); err == nil {
+				push(v)
+			}
+		case opGetProp:
+			key := pop()
+			obj := pop()
+			var k lang.StringOrSymbol
+			if k, err = propertyReferenceKey(obj, key); err != nil {
+				break
+			}
+			var v lang.Value
+			if v, err = r.getProperty(obj, k); err == nil {
+				push(v)
+			}
+		case opMakeKey:
+			var k lang.StringOrSymbol
+			if k, err = propertyReferenceKey(stack[len(stack)-2], stack[len(stack)-1]); err == nil {
+				stack[len(stack)-1] = k
+			}
+		case opCheckBase:
+			_, err = propertyReferenceKey(stack[len(stack)-1], c.keys[ins.a].Underlying())
+		case opGetPropKey:
+			k := pop().(lang.StringOrSymbol)
+			var v lang.Value
+			if v, err = r.getProperty(pop(), k); err == nil {
+				push(v)
+			}
+		case opSetPropNamed:
+			v := pop()
+			obj := pop()
+			if err = binding.NewReference(c.keys[ins.a], obj, r.strict).PutValue(r.Realm(), v); err == nil {
+				push(v)
+			}
+		case opSetProp:
+			v := pop()
+			k := pop().(lang.StringOrSymbol)
+			obj := pop()
+			if err = binding.NewReference(k, obj, r.strict).PutValue(r.Realm(), v); err == nil {
+				push(v)
+			}
+		case opDeleteProp:
+			key := pop()
+			obj := pop()
+			var k lang.StringOrSymbol
+			if k, err = propertyReferenceKey(obj, key); err != nil {
+				break
+			}
+			var v lang.Value
+			if v, err = r.deleteReference(binding.NewReference(k, obj, r.strict)); err == nil {
+				push(v)
+			}
+		case opGetMethodNamed:
+			obj := pop()
+			var f lang.Value
+			if f, err = r.getProperty(obj, c.keys[ins.a]); err == nil {
+				push(f)
+				push(obj)
+			}
+		case opGetMethod:
+			key := pop()
+			obj := pop()
+			var k lang.StringOrSymbol
+			if k, err = propertyReferenceKey(obj, key); err != nil {
+				break
+			}
+			var f lang.Value
+			if f, err = r.getProperty(obj, k); err == nil {
+				push(f)
+				push(obj)
+			}
+		case opCall:
+			site := c.calls[ins.a]
+			base := len(stack) - site.argc - 2
+			args := append([]lang.Value(nil), stack[base+2:]...)
+			f, thisValue := stack[base], stack[base+1]
+			stack = stack[:base]
+			var v lang.Value
+			if v, err = r.evaluateCallTo(site.callee, f, thisValue, args); err == nil {
+				push(v)
+			}
+		case opCallSpread:
+			args := pop().(*valueList).values
+			thisValue := pop()
+			f := pop()
+			var v lang.Value
+			if v, err = r.evaluateCallTo(c.calls[ins.a].callee, f, thisValue, args); err == nil {
+				push(v)
+			}
+		case opNew:
+			site := c.calls[ins.a]
+			base := len(stack) - site.argc - 1
+			args := append([]lang.Value(nil), stack[base+1:]...)
+			f := stack[base]
+			stack = stack[:base]
+			var v lang.Value
+			if v, err = r.evaluateNewTo(site.callee, f, args); err == nil {
+				push(v)
+			}
+		case opNewSpread:
+			args := pop().(*valueList).values
+			f := pop()
+			var v lang.Value
+			if v, err = r.evaluateNewTo(c.calls[ins.a].callee, f, args); err == nil {
+				push(v)
+			}
+		case opEvalExpr:
+			var v lang.Value
+			if v, err = r.evaluate(c.nodes[ins.a]); err == nil {
+				push(v)
+			}
+		case opEvalStmt:
+			if result := r.evaluateStatement(c.nodes[ins.a].(ast.Statement)); result.Type == lang.CompletionThrow {
+				err = lang.NewThrowError(result.Value)
+			}
+		case opClosure:
+			site := c.closures[ins.a]
+			var v lang.Value
+			if site.hasName {
+				v, err = r.namedEvaluation(site.node, site.name)
+			} else {
+				v, err = r.evaluate(site.node)
+			}
+			if err == nil {
+				push(v)
+			}
+		case opClosureKeyed:
+			var v lang.Value
+			if v, err = r.namedEvaluation(c.closures[ins.a].node, stack[len(stack)-1].(lang.StringOrSymbol)); err == nil {
+				push(v)
+			}
+		case opBindPattern:
+			site := c.patterns[ins.a]
+			var env binding.Environment
+			if site.lexical {
+				env = ctx.LexicalEnvironment
+			}
+			err = r.bindingInitialization(site.node, pop(), env)
+		case opNewObject:
+			push(lang.ObjectCreate(r.intrinsic(realm.IntrinsicNameObjectPrototype)))
+		case opDefineField:
+			v := pop()
+			_, err = lang.CreateDataPropertyOrThrow(stack[len(stack)-1].(*lang.Object), c.keys[ins.a], v)
+		case opToPropertyKey:
+			var k lang.StringOrSymbol
+			if k, err = lang.ToPropertyKey(stack[len(stack)-1]); err == nil {
+				stack[len(stack)-1] = k
+			}
+		case opDefineComputed:
+			v := pop()
+			k := pop().(lang.StringOrSymbol)
+			_, err = lang.CreateDataPropertyOrThrow(stack[len(stack)-1].(*lang.Object), k, v)
+		case opDefineMethod:
+			prop := c.nodes[ins.a].(*ast.Property)
+			err = r.methodDefinitionEvaluation(prop.Key, prop.Computed, prop.Kind, prop.Value.(*ast.FunctionExpression), stack[len(stack)-1].(*lang.Object), true)
+		case opSetProto:
+			if v := pop(); v.Type() == lang.TypeObject || v.Type() == lang.TypeNull {
+				stack[len(stack)-1].(*lang.Object).SetPrototypeOf(v)
+			}
+		case opCopyData:
+			v := pop()
+			_, err = lang.CopyDataProperties(r.Realm(), stack[len(stack)-1].(*lang.Object), v, nil)
+		case opList:
+			push(new(valueList))
+		case opListPush:
+			v := pop()
+			list := stack[len(stack)-1].(*valueList)
+			list.values = append(list.values, v)
+		case opListHole:
+			list := stack[len(stack)-1].(*valueList)
+			list.values = append(list.values, nil)
+		case opListSpread:
+			v := pop()
+			list := stack[len(stack)-1].(*valueList)
+			err = r.iterateValues(v, func(v lang.Value) errors.Error {
+				list.values = append(list.values, v)
+				return nil
+			})
+		case opListToArray:
+			var v lang.Value
+			if v, err = r.listToArray(pop().(*valueList)); err == nil {
+				push(v)
+			}
+
+		case opAdd, opSub, opMul, opDiv, opMod, opExp, opShl, opShr, opUshr, opBitAnd, opBitOr, opBitXor,
+			opLt, opGt, opLe, opGe, opEq, opNe, opStrictEq, opStrictNe, opInstanceof, opIn:
+			rval := pop()
+			lval := stack[len(stack)-1]
+			if v, ok := numberOperation(ins.op, lval, rval); ok {
+				stack[len(stack)-1] = v
+				break
+			}
+			var v lang.Value
+			if v, err = r.applyOperator(binaryOperators[ins.op], lval, rval); err == nil {
+				stack[len(stack)-1] = v
+			}
+
+		case opNot:
+			stack[len(stack)-1] = !lang.ToBoolean(stack[len(stack)-1])
+		case opNeg:
+			var n lang.Number
+			if n, err = lang.ToNumber(stack[len(stack)-1]); err != nil {
+				break
+			}
+			if n.IsNaN() {
+				stack[len(stack)-1] = lang.NaN
+			} else {
+				stack[len(stack)-1] = lang.NewNumber(-n.Float64())
+			}
+		case opToNumber:
+			if _, ok := stack[len(stack)-1].(lang.Number); ok {
+				break
+			}
+			var n lang.Number
+			if n, err = lang.ToNumber(stack[len(stack)-1]); err == nil {
+				stack[len(stack)-1] = n
+			}
+		case opBitNot:
+			var n lang.Number
+			if n, err = lang.ToInt32(stack[len(stack)-1]); err == nil {
+				stack[len(stack)-1] = lang.NewNumber(float64(^int32(n.Float64())))
+			}
+		case opTypeof:
+			stack[len(stack)-1] = lang.NewString(typeOf(stack[len(stack)-1]))
+		case opInc:
+			stack[len(stack)-1] = lang.NewNumber(stack[len(stack)-1].(lang.Number).Float64() + 1)
+		case opDec:
+			stack[len(stack)-1] = lang.NewNumber(stack[len(stack)-1].(lang.Number).Float64() - 1)
+		case opToString:
+			var s lang.String
+			if s, err = lang.ToString(stack[len(stack)-1]); err == nil {
+				stack[len(stack)-1] = s
+			}
+		case opConcat:
+			parts := make([]lang.String, ins.a)
+			for i, v := range stack[len(stack)-ins.a:] {
+				parts[i] = v.(lang.String)
+			}
+			stack = stack[:len(stack)-ins.a]
+			var s lang.String
+			if s, err = r.concat(parts...); err == nil {
+				push(s)
+			}
+
+		case opJump:
+			if ins.a < pc {
+				// back-edge of a loop
+				r.step()
+			}
+			pc = ins.a
+		case opJumpIfFalse:
+			if !lang.ToBoolean(pop()) {
+				pc = ins.a
+			}
+		case opJumpIfTrue:
+			if lang.ToBoolean(pop()) {
+				if ins.a < pc {
+					// back-edge of a do-while loop
+					r.step()
+				}
+				pc = ins.a
+			}
+		case opJumpIfFalseKeep:
+			if !lang.ToBoolean(stack[len(stack)-1]) {
+				pc = ins.a
+			} else {
+				stack = stack[:len(stack)-1]
+			}
+		case opJumpIfTrueKeep:
+			if lang.ToBoolean(stack[len(stack)-1]) {
+				pc = ins.a
+			} else {
+				stack = stack[:len(stack)-1]
+			}
+
+		case opPushScope:
+			ctx.LexicalEnvironment = r.scopeInstantiation(c.scopes[ins.a], ctx.LexicalEnvironment)
+		case opPopScope:
+			ctx.LexicalEnvironment = ctx.LexicalEnvironment.Outer()
+		case opCopyScope:
+			var names []string
+			for _, b := range c.scopes[ins.a].bindings {
+				names = append(names, b.name.String())
+			}
+			r.createPerIterationEnvironment(names)
+		case opWith:
+			var obj *lang.Object
+			if obj, err = lang.ToObject(r.Realm(), pop()); err != nil {
+				break
+			}
+			env := binding.NewObjectEnvironment(ctx.LexicalEnvironment, obj)
+			env.WithEnvironment = true
+			ctx.LexicalEnvironment = env
+
+		case opTry:
+			handlers = append(handlers, handler{ins.a, ins.b, len(stack), ctx.LexicalEnvironment})
+		case opPopTry:
+			handlers = handlers[:len(handlers)-1]
+		case opPushPending:
+			push(&pendingCompletion{target: ins.a, completion: completion})
+		case opEndFinally:
+			p := pop().(*pendingCompletion)
+			completion = p.completion
+			switch {
+			case p.throw:
+				err = lang.NewThrowError(p.thrown)
+			case p.target >= 0:
+				pc = p.target
+			}
+		case opThrow:
+			v := pop()
+			r.recordThrow(v)
+			err = lang.NewThrowError(v)
+		case opSetReturn:
+			returnValue = pop()
+		case opReturn:
+			return lang.Completion{Type: lang.CompletionReturn, Value: returnValue}
+
+		case opSetCompletion:
+			completion = pop()
+		case opCompletionUndefined:
+			completion = lang.Undefined
+
+		case opGetIterator:
+			var record *lang.IteratorRecord
+			if record, err = lang.GetIterator(r.Realm(), pop()); err == nil {
+				slots[ins.a] = &iteratorValue{record}
+			}
+		case opIteratorNext:
+			record := slots[ins.a].(*iteratorValue).record
+			var v lang.Value
+			if v, err = iteratorStepValue(record); err != nil {
+				break
+			}
+			if record.Done {
+				pc = ins.b
+				break
+			}
+			push(v)
+		case opIteratorClose:
+			err = lang.IteratorClose(slots[ins.a].(*iteratorValue).record, nil)
+		case opIteratorCloseThrow:
+			err = lang.IteratorClose(slots[ins.a].(*iteratorValue).record, lang.NewThrowError(pop()))
+		case opEnumerate:
+			v := pop()
+			if v == lang.Undefined || v == lang.Null {
+				slots[ins.a] = &enumeratorValue{func() (lang.Value, bool, errors.Error) { return nil, true, nil }}
+				break
+			}
+			var obj *lang.Object
+			if obj, err = lang.ToObject(r.Realm(), v); err == nil {
+				slots[ins.a] = &enumeratorValue{enumerateObjectProperties(obj)}
+			}
+		case opEnumerateNext:
+			var v lang.Value
+			var done bool
+			if v, done, err = slots[ins.a].(*enumeratorValue).next(); err != nil {
+				break
+			}
+			if done {
+				pc = ins.b
+				break
+			}
+			push(v)
+
+		default:
+			panic(fmt.Sprintf("Unexpected opcode %v", ins.op))
+		}
+
+		if err == nil {
+			continue
+		}
+		if len(handlers) == 0 {
+			return r.throw(err)
+		}
+		h := handlers[len(handlers)-1]
+		handlers = handlers[:len(handlers)-1]
+		stack = stack[:h.sp]
+		ctx.LexicalEnvironment = h.env
+		thrown := r.ErrorValue(err)
+		if h.catch >= 0 {
+			push(thrown)
+			pc = h.catch
+		} else {
+			push(&pendingCompletion{target: -1, throw: true, thrown: thrown, completion: completion})
+			pc = h.finally
+		}
+	}
+
+	if c.script {
+		return lang.NormalCompletion(completion)
+	}
+	return lang.Completion{Type: lang.CompletionReturn, Value: returnValue}
+}
+
+// uninitializedError returns the error for an access of the binding with
+// the given name in its temporal dead zone.
+func uninitializedError(name string) errors.Error {
+	return errors.NewReferenceError(fmt.Sprintf("Cannot access '%v' before initialization", name))
+}
+
+// getProperty returns the value of the property with the given key of the
+// given base value.
+func (r *Runtime) getProperty(base lang.Value, key lang.StringOrSymbol) (lang.Value, errors.Error) {
+	if obj, ok := base.(*lang.Object); ok {
+		return obj.Get(key, obj)
+	}
+	if _, err := propertyReferenceKey(base, key.Underlying()); err != nil {
+		return nil, err
+	}
+	return binding.NewReference(key, base, r.strict).GetValue(r.Realm())
+}
+
+// scopeInstantiation creates the declarative environment of the given
+// scope, whose outer environment is the given one, and instantiates the
+// function declarations of the scope.
+func (r *Runtime) scopeInstantiation(info *scopeInfo, outer binding.Environment) binding.Environment {
+	env := binding.NewDeclarativeEnvironment(outer)
+	for _, b := range info.bindings {
+		if b.constant {
+			_ = env.CreateImmutableBinding(b.name, true)
+		} else {
+			_ = env.CreateMutableBinding(b.name, false)
+		}
+	}
+	for _, f := range info.functions {
+		fo := r.instantiateFunctionObject(f, env)
+		_ = env.InitializeBinding(lang.NewString(boundNames(f)[0]), fo)
+	}
+	return env
+}
+
+// listToArray creates an array with the values of the given list, where
+// holes of the list are left out.
+func (r *Runtime) listToArray(list *valueList) (lang.Value, errors.Error) {
+	array, err := lang.ArrayCreate(0, r.intrinsic(realm.IntrinsicNameArrayPrototype))
+	if err != nil {
+		return nil, err
+	}
+	for i, v := range list.values {
+		if v == nil {
+			continue
+		}
+		if _, err := lang.CreateDataPropertyOrThrow(array, lang.NewStringKey(strconv.Itoa(i)), v); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := lang.Set(array, lang.NewStringKey("length"), lang.NewNumber(float64(len(list.values))), true); err != nil {
+		return nil, err
+	}
+	return array, nil
+}
+
+// numberOperation applies the given binary operation to the given values,
+// if both are numbers and the operation has a fast path for numbers.
+func numberOperation(op opcode, lval, rval lang.Value) (lang.Value, bool) {
+	lnum, ok := lval.(lang.Number)
+	if !ok {
+		return nil, false
+	}
+	rnum, ok := rval.(lang.Number)
+	if !ok {
+		return nil, false
+	}
+
+	l, r := lnum.Float64(), rnum.Float64()
+	switch op {
+	case opAdd:
+		return lang.NewNumber(l + r), true
+	case opSub:
+		return lang.NewNumber(l - r), true
+	case opMul:
+		return lang.NewNumber(l * r), true
+	case opDiv:
+		return lang.NewNumber(l / r), true
+	case opLt:
+		return lang.Boolean(l < r), true
+	case opGt:
+		return lang.Boolean(l > r), true
+	case opLe:
+		return lang.Boolean(l <= r), true
+	case opGe:
+		return lang.Boolean(l >= r), true
+	case opEq, opStrictEq:
+		return lang.Boolean(l == r), true
+	case opNe, opStrictNe:
+		return lang.Boolean(l != r), true
+	}
+	return nil, false
+}
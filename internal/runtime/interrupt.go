@@ -0,0 +1,59 @@
+package runtime
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Interrupted is the value that the runtime panics with, if the evaluation
+// of ECMAScript code is interrupted. The panic unwinds the execution
+// context stack, without evaluating catch and finally blocks, and must be
+// recovered by the caller of the runtime.
+type Interrupted struct {
+	// Reason is the reason that was passed to Runtime#Interrupt.
+	Reason interface{}
+}
+
+// interrupt holds an interrupt that was requested by Runtime#Interrupt.
+type interrupt struct {
+	// requested is 1 if an interrupt has been requested. It is accessed
+	// atomically, so that the evaluation can check it cheaply.
+	requested int32
+
+	mu     sync.Mutex
+	reason interface{}
+}
+
+// Interrupt requests the runtime to stop the evaluation of ECMAScript
+// code with the given reason. The evaluation panics with an *Interrupted
+// when it reaches the next loop iteration or function call, or while a
+// built-in function iterates over its arguments. The interrupt stays
+// requested until ClearInterrupt is called, so the next evaluation is
+// interrupted, if no code is evaluated when Interrupt is called.
+// Interrupt may be called from any goroutine.
+func (r *Runtime) Interrupt(reason interface{}) {
+	r.interrupt.mu.Lock()
+	r.interrupt.reason = reason
+	r.interrupt.mu.Unlock()
+	atomic.StoreInt32(&r.interrupt.requested, 1)
+}
+
+// ClearInterrupt clears the requested interrupt, if any.
+func (r *Runtime) ClearInterrupt() {
+	atomic.StoreInt32(&r.interrupt.requested, 0)
+	r.interrupt.mu.Lock()
+	r.interrupt.reason = nil
+	r.interrupt.mu.Unlock()
+}
+
+// CheckInterrupt panics with an *Interrupted, if an interrupt has been
+// requested.
+func (r *Runtime) CheckInterrupt() {
+	if atomic.LoadInt32(&r.interrupt.requested) == 0 {
+		return
+	}
+	r.interrupt.mu.Lock()
+	reason := r.interrupt.reason
+	r.interrupt.mu.Unlock()
+	panic(&Interrupted{Reason: reason})
+}
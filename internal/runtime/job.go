@@ -0,0 +1,42 @@
+package runtime
+
+import (
+	"fmt"
+
+	"github.com/gojisvm/gojis/internal/parser/ast"
+	"github.com/gojisvm/gojis/internal/runtime/agent"
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+)
+
+// EnqueueScriptEvaluationJob enqueues a job into the ScriptJobs queue of
+// the agent, that evaluates the script of the AST with the given name when
+// the jobs are run by RunJobs. An exception that is thrown by the script is
+// passed to the HostReportErrors hook of the agent. An error is returned if
+// there is no script with the given name.
+// ScriptEvaluationJob is specified in 15.1.12.
+func (r *Runtime) EnqueueScriptEvaluationJob(name string) error {
+	script, ok := r.ast.Root(name)
+	if !ok {
+		return fmt.Errorf("No script with name '%v'", name)
+	}
+	if script.SourceType != ast.SourceTypeScript {
+		return fmt.Errorf("'%v' is a module, not a script", name)
+	}
+
+	r.agent.EnqueueJob(agent.QueueScript, func(...lang.Value) errors.Error {
+		result := r.scriptEvaluation(r.newScriptRecord(name, script, nil))
+		if result.Type == lang.CompletionThrow {
+			return lang.NewThrowError(result.Value)
+		}
+		return nil
+	}, nil)
+	return nil
+}
+
+// RunJobs runs the pending script and promise jobs of the agent, until both
+// queues are empty.
+// RunJobs is specified in 8.6.
+func (r *Runtime) RunJobs() {
+	r.agent.RunJobs()
+}
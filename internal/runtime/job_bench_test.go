@@ -0,0 +1,65 @@
+package runtime
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gojisvm/gojis/internal/parser"
+	"github.com/rs/zerolog"
+)
+
+// BenchmarkPromiseChain runs a single chain of promise reactions, each of
+// which enqueues the next one, so that b.N microtasks pass through the
+// PromiseJobs queue.
+func BenchmarkPromiseChain(b *testing.B) {
+	for _, e := range engines {
+		b.Run(e.name, func(b *testing.B) {
+			p := parser.New()
+			if err := p.ParseString("chain.js", fmt.Sprintf(`
+var n = %d;
+function tick() {
+	if (--n > 0) Promise.resolve().then(tick);
+}
+tick();
+			`, b.N)); err != nil {
+				b.Fatal(err)
+			}
+			r := New(zerolog.Nop(), p.Ast())
+			r.SetEngine(e.engine)
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			if err := r.EnqueueScriptEvaluationJob("chain.js"); err != nil {
+				b.Fatal(err)
+			}
+			r.RunJobs()
+		})
+	}
+}
+
+// BenchmarkPromiseFanOut resolves a single promise with b.N reactions, that
+// are all in the PromiseJobs queue at the same time.
+func BenchmarkPromiseFanOut(b *testing.B) {
+	for _, e := range engines {
+		b.Run(e.name, func(b *testing.B) {
+			p := parser.New()
+			if err := p.ParseString("fan-out.js", fmt.Sprintf(`
+var count = 0, resolve;
+var p = new Promise(r => resolve = r);
+for (var i = 0; i < %d; i++) p.then(() => count++);
+resolve();
+			`, b.N)); err != nil {
+				b.Fatal(err)
+			}
+			r := New(zerolog.Nop(), p.Ast())
+			r.SetEngine(e.engine)
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			if err := r.EnqueueScriptEvaluationJob("fan-out.js"); err != nil {
+				b.Fatal(err)
+			}
+			r.RunJobs()
+		})
+	}
+}
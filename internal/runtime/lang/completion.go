@@ -0,0 +1,132 @@
+package lang
+
+import (
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+)
+
+// CompletionType is the type of a Completion, as specified in 6.2.3.
+type CompletionType uint8
+
+// Available completion types.
+const (
+	CompletionNormal CompletionType = iota
+	CompletionBreak
+	CompletionContinue
+	CompletionReturn
+	CompletionThrow
+)
+
+// Completion is used to explain the runtime propagation of values and
+// control flow, such as the behaviour of statements that perform nonlocal
+// transfers of control.
+// Completion is specified in 6.2.3.
+type Completion struct {
+	Type CompletionType
+	// Value is the value that was produced, or nil if it is empty.
+	Value Value
+	// Target is the label of a break or continue completion, or the
+	// empty string if it is empty.
+	Target string
+}
+
+// NormalCompletion creates a normal completion with the given value, which
+// may be nil.
+// NormalCompletion is specified in 6.2.3.2.
+func NormalCompletion(v Value) Completion {
+	return Completion{
+		Type:  CompletionNormal,
+		Value: v,
+	}
+}
+
+// ThrowCompletion creates a throw completion with the given value.
+// ThrowCompletion is specified in 6.2.3.3.
+func ThrowCompletion(v Value) Completion {
+	return Completion{
+		Type:  CompletionThrow,
+		Value: v,
+	}
+}
+
+// IsAbrupt is used to determine whether the completion is an abrupt
+// completion, that is, any completion whose type is not normal.
+func (c Completion) IsAbrupt() bool {
+	return c.Type != CompletionNormal
+}
+
+// UpdateEmpty returns the completion with its value set to v, if the value
+// of the completion is empty.
+// UpdateEmpty is specified in 6.2.3.4.
+func UpdateEmpty(c Completion, v Value) Completion {
+	if c.Value == nil {
+		c.Value = v
+	}
+	return c
+}
+
+var _ errors.Error = (*ThrowError)(nil) // ensure that ThrowError implements errors.Error
+
+// ThrowError is an errors.Error that carries an arbitrary language value,
+// that was thrown by a throw statement or by a function. It is used to
+// propagate throw completions through operations that return errors.
+type ThrowError struct {
+	Value Value
+}
+
+// NewThrowError creates a new ThrowError that carries the given value.
+func NewThrowError(v Value) *ThrowError {
+	return &ThrowError{v}
+}
+
+// Kind returns errors.ErrorKindThrow.
+func (e *ThrowError) Kind() errors.ErrorKind { return errors.ErrorKindThrow }
+
+// Error returns a description of the thrown value.
+func (e *ThrowError) Error() string {
+	return "Uncaught " + e.Message()
+}
+
+// Message returns a description of the thrown value. For objects, the
+// name and message data properties are used, like Error.prototype.toString
+// does, but no user code is run to obtain them.
+func (e *ThrowError) Message() string {
+	o, ok := e.Value.(*Object)
+	if !ok {
+		if e.Value.Type() == TypeSymbol {
+			return SymbolDescriptiveString(e.Value).String()
+		}
+		s, _ := ToString(e.Value)
+		return s.String()
+	}
+
+	name := dataPropertyString(o, "name")
+	msg := dataPropertyString(o, "message")
+	switch {
+	case name == "":
+		return msg
+	case msg == "":
+		return name
+	}
+	return name + ": " + msg
+}
+
+// dataPropertyString returns the value of the data property with the given
+// name of the given object or its prototype chain, if it is a String.
+func dataPropertyString(o *Object, name string) string {
+	key := NewStringKey(name)
+	for {
+		desc := o.GetOwnProperty(key)
+		if desc != nil {
+			if desc.IsDataDescriptor() && desc.Value().Type() == TypeString {
+				return toStringValue(desc.Value()).String()
+			}
+			return ""
+		}
+
+		proto, ok := o.GetPrototypeOf().(*Object)
+		if !ok {
+			return ""
+		}
+		o = proto
+	}
+}
@@ -0,0 +1,195 @@
+package lang
+
+import "github.com/gojisvm/gojis/internal/runtime/errors"
+
+const (
+	// propertySize is the approximate number of bytes that a property of
+	// an object takes, without its key and value.
+	propertySize = 64
+	// heapReserve is the number of bytes that may be allocated beyond the
+	// limit of a heap, after a RangeError has been thrown because the
+	// limit was exceeded. It allows to create the error object and to
+	// handle the error.
+	heapReserve = 1 << 16
+)
+
+// Heap is a cumulative allocation budget of a realm. It accounts the
+// approximate number of bytes that are allocated for the properties of
+// objects and the entries of Map, Set, WeakMap and WeakSet objects,
+// including the strings that are stored in them, and for the Data Blocks
+// of array buffers. Objects use the heap of their prototype, so that every
+// object of a realm is accounted to its heap.
+//
+// The heap is not a measure of the memory that is in use. Since the garbage
+// collection is not tracked, objects that are no longer reachable are not
+// subtracted from it, and the bytes of a property or an entry are only
+// subtracted if it is deleted or its value is replaced. Values that are
+// only held in bindings, arguments and temporaries are not accounted at
+// all. A new string is checked against the remaining budget before it is
+// created, see realm.Realm#CheckStringLength, but it is only accounted once
+// it is stored in a property or an entry.
+//
+// If the heap has a limit, an allocation that would exceed the limit throws
+// a RangeError. Until the usage falls below the limit again, further
+// allocations may use a small reserve beyond the limit, and if they exceed
+// the reserve, the evaluation is aborted by a panic with a *HeapExceeded.
+type Heap struct {
+	used    int64
+	limited bool
+	limit   int64
+	// exhausted is true if a RangeError has been thrown for an allocation
+	// that would have exceeded the limit, and the usage has not fallen
+	// below the limit since.
+	exhausted bool
+}
+
+// HeapExceeded is the value that is panicked with, if an allocation
+// exceeds the reserve beyond the limit of a heap, after a RangeError has
+// been thrown for exceeding the limit.
+// The panic must be recovered by the host that evaluates ECMAScript code.
+type HeapExceeded struct {
+	// Used is the number of bytes that were used when the limit was
+	// exceeded.
+	Used int64
+	// Limit is the limit of the heap.
+	Limit int64
+}
+
+// NewHeap creates a new heap without a limit.
+func NewHeap() *Heap {
+	return new(Heap)
+}
+
+// Used returns the number of bytes that are used. A nil heap uses no
+// bytes.
+func (h *Heap) Used() int64 {
+	if h == nil {
+		return 0
+	}
+	return h.used
+}
+
+// SetLimit sets the number of bytes that may be used.
+func (h *Heap) SetLimit(limit int64) {
+	h.limited = true
+	h.limit = limit
+	h.exhausted = false
+}
+
+// ClearLimit removes the limit of the heap.
+func (h *Heap) ClearLimit() {
+	h.limited = false
+	h.limit = 0
+	h.exhausted = false
+}
+
+// Check returns a RangeError if the given number of bytes cannot be
+// allocated, or aborts the evaluation, as described by Heap. The bytes are
+// not accounted. Allocations on a nil heap always succeed.
+func (h *Heap) Check(n int64) errors.Error {
+	if h == nil || !h.limited || h.used+n <= h.limit {
+		return nil
+	}
+	if !h.exhausted {
+		h.exhausted = true
+		return errors.NewRangeError("Out of memory")
+	}
+	if h.used+n <= h.limit+heapReserve {
+		return nil
+	}
+	panic(&HeapExceeded{Used: h.used, Limit: h.limit})
+}
+
+// Allocate accounts the given number of bytes, if they can be allocated as
+// described by Check.
+func (h *Heap) Allocate(n int64) errors.Error {
+	if err := h.Check(n); err != nil {
+		return err
+	}
+	if h != nil {
+		h.used += n
+	}
+	return nil
+}
+
+// Release subtracts the given number of bytes from the heap.
+func (h *Heap) Release(n int64) {
+	if h == nil || n <= 0 {
+		return
+	}
+	h.used -= n
+	if h.used < h.limit {
+		h.exhausted = false
+	}
+}
+
+// resize accounts the difference of the given numbers of bytes, without
+// checking the limit.
+func (h *Heap) resize(before, after int64) {
+	if h == nil {
+		return
+	}
+	if after < before {
+		h.Release(before - after)
+		return
+	}
+	h.used += after - before
+}
+
+// Heap returns the heap that the object is accounted to, which may be nil.
+func (o *Object) Heap() *Heap {
+	return o.heap
+}
+
+// SetHeap sets the heap that the object and the objects that are created
+// with it as prototype are accounted to. It must be called before
+// properties are added to the object.
+func (o *Object) SetHeap(h *Heap) {
+	o.heap = h
+}
+
+// allocateProperty checks that the heap of the object can hold the bytes
+// that the given property descriptor would add to the property with the
+// given key, as described by Heap#Check.
+func (o *Object) allocateProperty(p StringOrSymbol, desc *Property) errors.Error {
+	if o.heap == nil || !o.heap.limited {
+		return nil
+	}
+
+	var n int64
+	if current, ok := o.fields[p.key()]; ok {
+		if !desc.Has(FieldNameValue) {
+			return nil
+		}
+		n = valueSize(desc.Value()) - valueSize(current.Value())
+	} else {
+		n = propertyBytes(p, desc)
+	}
+	if n <= 0 {
+		return nil
+	}
+	return o.heap.Check(n)
+}
+
+// propertyBytes returns the approximate number of bytes that the property
+// with the given key takes.
+func propertyBytes(p StringOrSymbol, prop *Property) int64 {
+	return propertySize + valueSize(p.underlying) + valueSize(prop.Value())
+}
+
+// EntrySize returns the approximate number of bytes that an entry of a Map,
+// Set, WeakMap or WeakSet with the given key and value takes, which is the
+// size of a property with that key and value.
+func EntrySize(key, value Value) int64 {
+	return propertySize + valueSize(key) + valueSize(value)
+}
+
+// valueSize returns the number of bytes of the given value, that are not
+// accounted elsewhere. These are the code units of strings, other values
+// are part of the size of the property that holds them.
+func valueSize(v Value) int64 {
+	if s, ok := v.(String); ok {
+		return 2 * int64(len(s))
+	}
+	return 0
+}
@@ -0,0 +1,70 @@
+package lang
+
+import (
+	"testing"
+
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeapAllocate(t *testing.T) {
+	require := require.New(t)
+
+	h := NewHeap()
+	require.NoError(h.Allocate(1 << 20))
+	require.EqualValues(1<<20, h.Used())
+
+	h.SetLimit(1<<20 + 100)
+	require.NoError(h.Allocate(100))
+	err := h.Allocate(1)
+	require.Error(err)
+	require.Equal(errors.ErrorKindRangeError, err.Kind())
+	require.EqualValues(1<<20+100, h.Used())
+
+	// the reserve can be used after the RangeError
+	require.NoError(h.Allocate(heapReserve))
+	func() {
+		defer func() {
+			require.Equal(&HeapExceeded{Used: 1<<20 + 100 + heapReserve, Limit: 1<<20 + 100}, recover())
+		}()
+		_ = h.Allocate(1)
+		require.Fail("allocation beyond the reserve did not panic")
+	}()
+
+	// releasing bytes below the limit throws a RangeError again
+	h.Release(heapReserve + 1)
+	require.Error(h.Allocate(2))
+	require.NoError(h.Allocate(1))
+
+	h.ClearLimit()
+	require.NoError(h.Allocate(1 << 30))
+}
+
+func TestHeapProperties(t *testing.T) {
+	require := require.New(t)
+
+	proto := ObjectCreate(Null)
+	proto.SetHeap(NewHeap())
+	o := ObjectCreate(proto)
+	require.Equal(proto.Heap(), o.Heap())
+
+	key := NewStringKey("key")
+	_, err := CreateDataProperty(o, key, NewString("value"))
+	require.NoError(err)
+	require.EqualValues(propertySize+6+10, o.Heap().Used())
+
+	_, err = o.Set(key, NewString("v"), o)
+	require.NoError(err)
+	require.EqualValues(propertySize+6+2, o.Heap().Used())
+
+	o.Heap().SetLimit(o.Heap().Used() + propertySize)
+	ok, err := o.Set(NewStringKey("other"), NewString("value"), o)
+	require.False(bool(ok))
+	require.Error(err)
+	// replacing values with smaller ones is possible beyond the limit
+	_, err = o.Set(key, Undefined, o)
+	require.NoError(err)
+
+	require.True(bool(o.Delete(key)))
+	require.Zero(o.Heap().Used())
+}
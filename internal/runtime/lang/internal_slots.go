@@ -0,0 +1,16 @@
+package lang
+
+// Names of internal slots that are used by this package, as specified
+// throughout the specification, e.g. [[BooleanData]] in 19.3.
+var (
+	SlotBooleanData         = NewStringKey("BooleanData")
+	SlotNumberData          = NewStringKey("NumberData")
+	SlotStringData          = NewStringKey("StringData")
+	SlotSymbolData          = NewStringKey("SymbolData")
+	SlotErrorData           = NewStringKey("ErrorData")
+	SlotBoundTargetFunction = NewStringKey("BoundTargetFunction")
+	SlotParameterMap        = NewStringKey("ParameterMap")
+	SlotRegExpMatcher       = NewStringKey("RegExpMatcher")
+	SlotProxyHandler        = NewStringKey("ProxyHandler")
+	SlotProxyTarget         = NewStringKey("ProxyTarget")
+)
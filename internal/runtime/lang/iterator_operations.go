@@ -0,0 +1,141 @@
+package lang
+
+import (
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+)
+
+// IteratorRecord holds an iterator object, its next method and whether the
+// iteration is done, as specified in 7.4.1.
+type IteratorRecord struct {
+	Iterator   *Object
+	NextMethod Value
+	Done       bool
+}
+
+// GetIterator retrieves an iterator from the given value, by calling its
+// @@iterator method. The realm is used to look up the method on primitive
+// values.
+// GetIterator is specified in 7.4.1.
+func GetIterator(r Realm, obj Value) (*IteratorRecord, errors.Error) {
+	method, err := GetMethod(r, obj, NewStringOrSymbol(SymbolIterator))
+	if err != nil {
+		return nil, err
+	}
+	if method == Undefined {
+		return nil, errors.NewTypeError("Value is not iterable")
+	}
+
+	iterator, err := CallValue(method, obj)
+	if err != nil {
+		return nil, err
+	}
+	iteratorObj, ok := iterator.(*Object)
+	if !ok {
+		return nil, errors.NewTypeError("Result of the Symbol.iterator method is not an object")
+	}
+
+	nextMethod, err := Get(iteratorObj, NewStringKey("next"))
+	if err != nil {
+		return nil, err
+	}
+	return &IteratorRecord{
+		Iterator:   iteratorObj,
+		NextMethod: nextMethod,
+	}, nil
+}
+
+// IteratorNext calls the next method of the given iterator, and returns
+// the resulting iterator result object.
+// IteratorNext is specified in 7.4.2.
+func IteratorNext(iteratorRecord *IteratorRecord, value ...Value) (*Object, errors.Error) {
+	result, err := CallValue(iteratorRecord.NextMethod, iteratorRecord.Iterator, value...)
+	if err != nil {
+		return nil, err
+	}
+
+	resultObj, ok := result.(*Object)
+	if !ok {
+		return nil, errors.NewTypeError("Iterator result is not an object")
+	}
+	return resultObj, nil
+}
+
+// IteratorComplete returns the value of the done property of the given
+// iterator result object, converted to a Boolean.
+// IteratorComplete is specified in 7.4.3.
+func IteratorComplete(iterResult *Object) (Boolean, errors.Error) {
+	done, err := Get(iterResult, NewStringKey("done"))
+	if err != nil {
+		return False, err
+	}
+	return ToBoolean(done), nil
+}
+
+// IteratorValue returns the value property of the given iterator result
+// object.
+// IteratorValue is specified in 7.4.4.
+func IteratorValue(iterResult *Object) (Value, errors.Error) {
+	return Get(iterResult, NewStringKey("value"))
+}
+
+// IteratorStep calls the next method of the given iterator, and returns
+// the iterator result object, or nil if the iterator is done.
+// IteratorStep is specified in 7.4.5.
+func IteratorStep(iteratorRecord *IteratorRecord) (*Object, errors.Error) {
+	result, err := IteratorNext(iteratorRecord)
+	if err != nil {
+		return nil, err
+	}
+
+	done, err := IteratorComplete(result)
+	if err != nil {
+		return nil, err
+	}
+	if done {
+		return nil, nil
+	}
+	return result, nil
+}
+
+// IteratorClose notifies the given iterator that the iteration is ended
+// prematurely, by calling its return method. The given error is the error
+// of the completion that caused the iteration to end, or nil if it ended
+// normally. If it is not nil, it takes precedence over any error that
+// occurs while closing the iterator.
+// IteratorClose is specified in 7.4.6.
+func IteratorClose(iteratorRecord *IteratorRecord, completion errors.Error) errors.Error {
+	iterator := iteratorRecord.Iterator
+	returnMethod, err := GetMethod(nil, iterator, NewStringKey("return"))
+	if err != nil {
+		if completion != nil {
+			return completion
+		}
+		return err
+	}
+	if returnMethod == Undefined {
+		return completion
+	}
+
+	innerResult, err := CallValue(returnMethod, iterator)
+	if completion != nil {
+		return completion
+	}
+	if err != nil {
+		return err
+	}
+	if innerResult.Type() != TypeObject {
+		return errors.NewTypeError("Iterator result is not an object")
+	}
+	return nil
+}
+
+// CreateIterResultObject creates an iterator result object with the given
+// value and done flag, whose prototype is the %ObjectPrototype% of the
+// given realm.
+// CreateIterResultObject is specified in 7.4.7.
+func CreateIterResultObject(r Realm, value Value, done bool) *Object {
+	obj := ObjectCreate(intrinsicObject(r, IntrinsicNameObjectPrototype))
+	_, _ = CreateDataProperty(obj, NewStringKey("value"), value)
+	_, _ = CreateDataProperty(obj, NewStringKey("done"), Boolean(done))
+	return obj
+}
@@ -0,0 +1,228 @@
+package lang
+
+import (
+	"strconv"
+
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+)
+
+// maxArrayLength is 2^32-1, the largest possible length of an array.
+const maxArrayLength = 1<<32 - 1
+
+var keyLength = NewStringKey("length")
+
+var arrayExoticMethods = &ExoticMethods{
+	DefineOwnProperty: arrayDefineOwnProperty,
+}
+
+// ArrayCreate creates a new array exotic object with the given length and
+// prototype, which usually is the %ArrayPrototype% of the current realm.
+// ArrayCreate is specified in 9.4.2.2.
+func ArrayCreate(length float64, proto *Object) (*Object, errors.Error) {
+	if length > maxArrayLength {
+		return nil, errors.NewRangeError("Invalid array length")
+	}
+
+	a := ObjectCreate(proto)
+	a.Exotic = arrayExoticMethods
+	a.addProperty(keyLength, NewDataProperty(NewNumber(length+0), True, False, False))
+	return a, nil
+}
+
+// arrayDefineOwnProperty is the DefineOwnProperty internal method of array
+// exotic objects.
+// arrayDefineOwnProperty is specified in 9.4.2.1.
+func arrayDefineOwnProperty(a *Object, p StringOrSymbol, desc *Property) (Boolean, errors.Error) {
+	if p.Type() == TypeString {
+		if StringsEqual(p.underlying.(String), keyLength.underlying.(String)) {
+			return ArraySetLength(a, desc)
+		}
+
+		if index, ok := arrayIndex(p.underlying.(String)); ok {
+			oldLenDesc := a.OrdinaryGetOwnProperty(keyLength)
+			oldLen := oldLenDesc.Value().(Number).value
+			if index >= oldLen && !oldLenDesc.Writable() {
+				return False, nil
+			}
+
+			if !a.OrdinaryDefineOwnProperty(p, desc) {
+				return False, nil
+			}
+
+			if index >= oldLen {
+				oldLenDesc.SetField(FieldNameValue, NewNumber(index+1))
+				a.OrdinaryDefineOwnProperty(keyLength, oldLenDesc)
+			}
+			return True, nil
+		}
+	}
+
+	return a.OrdinaryDefineOwnProperty(p, desc), nil
+}
+
+// ArraySetLength sets the length of the given array exotic object, as
+// described by the given property descriptor. Elements whose indices are
+// not smaller than the new length are deleted.
+// ArraySetLength is specified in 9.4.2.4.
+func ArraySetLength(a *Object, desc *Property) (Boolean, errors.Error) {
+	if !desc.Has(FieldNameValue) {
+		return a.OrdinaryDefineOwnProperty(keyLength, desc), nil
+	}
+
+	newLenDesc := desc.copy()
+	newLen, err := ToUint32(desc.Value())
+	if err != nil {
+		return False, err
+	}
+	numberLen, err := ToNumber(desc.Value())
+	if err != nil {
+		return False, err
+	}
+	if numberLen.isNaN || newLen.value != numberLen.value {
+		return False, errors.NewRangeError("Invalid array length")
+	}
+	newLenDesc.SetField(FieldNameValue, newLen)
+
+	oldLenDesc := a.OrdinaryGetOwnProperty(keyLength)
+	oldLen := oldLenDesc.Value().(Number).value
+	if newLen.value >= oldLen {
+		return a.OrdinaryDefineOwnProperty(keyLength, newLenDesc), nil
+	}
+
+	if !oldLenDesc.Writable() {
+		return False, nil
+	}
+
+	newWritable := !newLenDesc.Has(FieldNameWritable) || bool(newLenDesc.Writable())
+	if !newWritable {
+		// Writable is set to false after the elements have been deleted,
+		// in case some of them cannot be deleted.
+		newLenDesc.SetField(FieldNameWritable, True)
+	}
+
+	if !a.OrdinaryDefineOwnProperty(keyLength, newLenDesc) {
+		return False, nil
+	}
+
+	// Instead of deleting every index from the old length down to the new
+	// length, only the existing elements are deleted, in descending order.
+	keys := a.OwnPropertyKeys()
+	for i := len(keys) - 1; i >= 0; i-- {
+		if keys[i].Type() != TypeString {
+			continue
+		}
+		index, ok := arrayIndex(keys[i].underlying.(String))
+		if !ok || index < newLen.value {
+			continue
+		}
+
+		if !a.Delete(keys[i]) {
+			newLenDesc.SetField(FieldNameValue, NewNumber(index+1))
+			if !newWritable {
+				newLenDesc.SetField(FieldNameWritable, False)
+			}
+			a.OrdinaryDefineOwnProperty(keyLength, newLenDesc)
+			return False, nil
+		}
+	}
+
+	if !newWritable {
+		writableDesc := NewProperty()
+		writableDesc.SetField(FieldNameWritable, False)
+		a.OrdinaryDefineOwnProperty(keyLength, writableDesc)
+	}
+	return True, nil
+}
+
+// CreateArrayFromList creates an array whose elements are the given
+// values. Its prototype is the %ArrayPrototype% of the given realm.
+// CreateArrayFromList is specified in 7.3.16.
+func CreateArrayFromList(r Realm, elements []Value) *Object {
+	array, _ := ArrayCreate(0, intrinsicObject(r, IntrinsicNameArrayPrototype))
+	for n, e := range elements {
+		_, _ = CreateDataProperty(array, NewStringKey(strconv.Itoa(n)), e)
+	}
+	return array
+}
+
+// MaxListLength is the maximum number of elements of a list that is
+// created by CreateListFromArrayLike, which is also the maximum number of
+// arguments that can be passed with Function.prototype.apply. It is not
+// part of the specification, and prevents that an array-like object with
+// a huge length exhausts the memory of the host.
+const MaxListLength = 1 << 20
+
+// CreateListFromArrayLike creates a list of the elements of the given
+// array-like object. If element types are given, every element must be of
+// one of these types. If the object has more than MaxListLength elements,
+// a RangeError is returned. The given function is called for every
+// element, to report the work to the host, see Realm#Work in package
+// realm.
+// CreateListFromArrayLike is specified in 7.3.17.
+func CreateListFromArrayLike(obj Value, work func(n int), elementTypes ...Type) ([]Value, errors.Error) {
+	o, ok := obj.(*Object)
+	if !ok {
+		return nil, errors.NewTypeError("CreateListFromArrayLike called on non-object")
+	}
+
+	lenValue, err := Get(o, keyLength)
+	if err != nil {
+		return nil, err
+	}
+	length, err := ToLength(lenValue)
+	if err != nil {
+		return nil, err
+	}
+
+	if length.value > MaxListLength {
+		return nil, errors.NewRangeError("Too many elements in array-like object")
+	}
+
+	var list []Value
+	for index := 0; float64(index) < length.value; index++ {
+		work(1)
+		next, err := Get(o, NewStringKey(strconv.Itoa(index)))
+		if err != nil {
+			return nil, err
+		}
+		if len(elementTypes) > 0 && !TypeIsOneOf(next, elementTypes...) {
+			return nil, errors.NewTypeError("Unexpected element type " + next.Type().String())
+		}
+		list = append(list, next)
+	}
+	return list, nil
+}
+
+// arrayIndex returns the numeric value of the given String, if it is an
+// array index. An array index is an integer index whose numeric value is
+// smaller than 2^32-1, as described in 6.1.7.
+func arrayIndex(s String) (float64, bool) {
+	n, ok := integerIndex(s)
+	if !ok || n >= maxArrayLength {
+		return 0, false
+	}
+	return n, true
+}
+
+// integerIndex returns the numeric value of the given String, if it is an
+// integer index. An integer index is a canonical numeric String whose
+// numeric value is +0 or a positive integer not greater than 2^53-1, as
+// described in 6.1.7.
+func integerIndex(s String) (float64, bool) {
+	if len(s) == 0 || len(s) > 16 || (s[0] == '0' && len(s) > 1) {
+		return 0, false
+	}
+
+	var n float64
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		n = n*10 + float64(c-'0')
+	}
+
+	if n > maxSafeInteger {
+		return 0, false
+	}
+	return n, true
+}
@@ -0,0 +1,47 @@
+package lang
+
+// SetFunctionName adds a name property to the given function object. If
+// the name is a Symbol, the name of the function is the description of the
+// Symbol enclosed in brackets. The prefix, e.g. "get" or "bound", is
+// prepended to the name if it is not empty.
+// SetFunctionName is specified in 9.2.11.
+func SetFunctionName(f *Object, name StringOrSymbol, prefix string) {
+	var nameStr String
+	if name.Type() == TypeSymbol {
+		description := toSymbolValue(name.underlying).Description
+		if description == Undefined || description == nil {
+			nameStr = String{}
+		} else {
+			nameStr = Concat(NewString("["), toStringValue(description), NewString("]"))
+		}
+	} else {
+		nameStr = toStringValue(name.underlying)
+	}
+
+	if prefix != "" {
+		nameStr = Concat(NewString(prefix+" "), nameStr)
+	}
+
+	_, _ = DefinePropertyOrThrow(f, NewStringKey("name"), NewDataProperty(nameStr, False, False, True))
+}
+
+// SetFunctionLength adds a length property to the given function object,
+// which is the number of its expected arguments.
+// SetFunctionLength is specified in 9.2.4.1, as part of FunctionAllocate.
+func SetFunctionLength(f *Object, length int) {
+	_, _ = DefinePropertyOrThrow(f, keyLength, NewDataProperty(NewNumber(float64(length)), False, False, True))
+}
+
+// MakeConstructor adds a prototype property to the given function object.
+// If prototype is nil, a new object whose prototype is the %ObjectPrototype%
+// of the given realm is created, that refers back to the function with its
+// constructor property.
+// MakeConstructor is specified in 9.2.10.
+func MakeConstructor(r Realm, f *Object, writablePrototype bool, prototype *Object) {
+	if prototype == nil {
+		prototype = ObjectCreate(intrinsicObject(r, IntrinsicNameObjectPrototype))
+		_, _ = DefinePropertyOrThrow(prototype, NewStringKey("constructor"), NewDataProperty(f, Boolean(writablePrototype), False, True))
+	}
+
+	_, _ = DefinePropertyOrThrow(f, NewStringKey("prototype"), NewDataProperty(prototype, Boolean(writablePrototype), False, False))
+}
@@ -15,10 +15,15 @@ func Get(o *Object, p StringOrSymbol) (InternalValue, errors.Error) {
 // GetV retrieves the value of a specific property of an object,
 // assuming that the value is an ECMAScript language value.
 // If the value is not an object, the property lookup is performed
-// using a wrapper object appropriate for the type of the value.
+// using a wrapper object appropriate for the type of the value, whose
+// prototype is an intrinsic object of the given realm. The realm may
+// be nil if v is an Object.
 // GetV is specified in 7.3.2.
-func GetV(v Value, p StringOrSymbol) (Value, errors.Error) {
-	o := ToObject(v)
+func GetV(r Realm, v Value, p StringOrSymbol) (Value, errors.Error) {
+	o, err := ToObject(r, v)
+	if err != nil {
+		return nil, err
+	}
 	return o.Get(p, v)
 }
 
@@ -33,7 +38,7 @@ func Set(o *Object, p StringOrSymbol, v Value, throw bool) (Boolean, errors.Erro
 	}
 
 	if !success.Value().(bool) && throw {
-		return False, errors.NewTypeError(fmt.Sprintf("Cannot set '%v' of object", p.Value()))
+		return False, errors.NewTypeError(fmt.Sprintf("Cannot assign to read only property '%v' of object", p.String()))
 	}
 
 	return success, nil
@@ -41,14 +46,14 @@ func Set(o *Object, p StringOrSymbol, v Value, throw bool) (Boolean, errors.Erro
 
 // CreateDataProperty creates a new own property of an object.
 // CreateDataProperty is specified in 7.3.4.
-func CreateDataProperty(o *Object, p StringOrSymbol, v Value) Boolean {
+func CreateDataProperty(o *Object, p StringOrSymbol, v Value) (Boolean, errors.Error) {
 	desc := NewDataProperty(v, True, True, True)
 	return o.DefineOwnProperty(p, desc)
 }
 
 // CreateMethodProperty creates a new own property of an object.
 // CreateMethodProperty is specified in 7.3.5.
-func CreateMethodProperty(o *Object, p StringOrSymbol, v Value) Boolean {
+func CreateMethodProperty(o *Object, p StringOrSymbol, v Value) (Boolean, errors.Error) {
 	desc := NewDataProperty(v, True, False, True)
 	return o.DefineOwnProperty(p, desc)
 }
@@ -58,7 +63,10 @@ func CreateMethodProperty(o *Object, p StringOrSymbol, v Value) Boolean {
 // be performed.
 // CreateDataPropertyOrThrow is specified in 7.3.6.
 func CreateDataPropertyOrThrow(o *Object, p StringOrSymbol, v Value) (Boolean, errors.Error) {
-	success := CreateDataProperty(o, p, v)
+	success, err := CreateDataProperty(o, p, v)
+	if err != nil {
+		return False, err
+	}
 	if !success {
 		return False, errors.NewTypeError(fmt.Sprintf("Unable to create data property '%v'", p.Value()))
 	}
@@ -69,7 +77,10 @@ func CreateDataPropertyOrThrow(o *Object, p StringOrSymbol, v Value) (Boolean, e
 // If the requested property update cannot be performed, a TypeError is returned.
 // DefinePropertyOrThrow is specified in 7.3.7.
 func DefinePropertyOrThrow(o *Object, p StringOrSymbol, desc *Property) (Boolean, errors.Error) {
-	success := o.DefineOwnProperty(p, desc)
+	success, err := o.DefineOwnProperty(p, desc)
+	if err != nil {
+		return False, err
+	}
 	if !success {
 		return False, errors.NewTypeError(fmt.Sprintf("Unable to define property '%v'", p.Value()))
 	}
@@ -92,8 +103,8 @@ func DeletePropertyOrThrow(o *Object, p StringOrSymbol) (Boolean, errors.Error)
 // GetMethod retrieves a callable object of a property of an ECMAScript language value.
 // If no error is returned, the object is guaranteed to be callable.
 // GetMethod is specified in 7.3.9.
-func GetMethod(v Value, p StringOrSymbol) (Value, errors.Error) {
-	f, err := GetV(v, p)
+func GetMethod(r Realm, v Value, p StringOrSymbol) (Value, errors.Error) {
+	f, err := GetV(r, v, p)
 	if err != nil {
 		return nil, err
 	}
@@ -103,7 +114,7 @@ func GetMethod(v Value, p StringOrSymbol) (Value, errors.Error) {
 	}
 
 	if !InternalIsCallable(f) {
-		return nil, errors.NewTypeError("Object is not callable")
+		return nil, errors.NewTypeError(fmt.Sprintf("%v is not a function", p.String().Value()))
 	}
 
 	return f, nil
@@ -233,59 +244,211 @@ func TestIntegrityLevel(o *Object, level string) Boolean {
 	return true
 }
 
-// CreateArrayFromList creates an array whose elements are provided by a List.
-// CreateArrayFromList is specified in 7.3.16.
-func CreateArrayFromList(elements []Value) {
-	panic("TODO: Arrays")
-}
-
-// CreateListFromArrayLike creates a List value whose elements are provided by the indexed
-// properties of an array-like object.
-// CreateListFromArrayLike is specified in 7.3.17.
-func CreateListFromArrayLike(o *Object, elementTypes []Type) {
-	panic("TODO: Arrays")
-}
-
 // Invoke is used to call a method property of an ECMAScript language value.
 // Invoke is specified in 7.3.18.
-func Invoke(v Value, p StringOrSymbol, args ...Value) (Value, errors.Error) {
+func Invoke(r Realm, v Value, p StringOrSymbol, args ...Value) (Value, errors.Error) {
 	if args == nil {
 		args = []Value{}
 	}
 
-	f, err := GetV(v, p)
+	f, err := GetV(r, v, p)
 	if err != nil {
 		return nil, err
 	}
 
-	return Call(f.(*Object), v, args...)
+	return CallValue(f, v, args...)
+}
+
+// CallValue calls the given value if it is callable, or returns a TypeError
+// if not. This is useful for calling values that are retrieved from
+// properties and are not known to be objects.
+func CallValue(f Value, thisValue Value, args ...Value) (Value, errors.Error) {
+	fObj, ok := f.(*Object)
+	if !ok || !InternalIsCallable(fObj) {
+		return nil, errors.NewTypeError("Object is not callable")
+	}
+
+	return Call(fObj, thisValue, args...)
 }
 
 // OrdinaryHasInstance implements the default algorithm for determining if an object o inherits
 // from the instance object inheritance path provided by constructor c.
 // OrdinaryHasInstance is specified in 7.3.19.
-func OrdinaryHasInstance(c, o *Object) Boolean {
-	panic("TODO")
+func OrdinaryHasInstance(c, o Value) (Boolean, errors.Error) {
+	if !InternalIsCallable(c) {
+		return False, nil
+	}
+
+	cObj := c.(*Object)
+	if cObj.HasSlot(SlotBoundTargetFunction) {
+		bc := cObj.GetSlot(SlotBoundTargetFunction)
+		return InstanceofOperator(o, bc)
+	}
+
+	obj, ok := o.(*Object)
+	if !ok {
+		return False, nil
+	}
+
+	p, err := Get(cObj, NewStringKey("prototype"))
+	if err != nil {
+		return False, err
+	}
+	if p.Type() != TypeObject {
+		return False, errors.NewTypeError("Function has non-object prototype in instanceof check")
+	}
+
+	for {
+		proto := obj.GetPrototypeOf()
+		if proto == Null {
+			return False, nil
+		}
+		if proto == p {
+			return True, nil
+		}
+		obj = proto.(*Object)
+	}
+}
+
+// InstanceofOperator determines if the value o is an instance of the
+// target, either by calling the target's @@hasInstance method, or by
+// using OrdinaryHasInstance.
+// InstanceofOperator is specified in 12.10.4.
+func InstanceofOperator(o, target Value) (Boolean, errors.Error) {
+	if target.Type() != TypeObject {
+		return False, errors.NewTypeError("Right-hand side of 'instanceof' is not an object")
+	}
+
+	instOfHandler, err := GetMethod(nil, target, NewStringOrSymbol(SymbolHasInstance))
+	if err != nil {
+		return False, err
+	}
+	if instOfHandler != Undefined {
+		result, err := Call(instOfHandler.(*Object), target, o)
+		if err != nil {
+			return False, err
+		}
+		return ToBoolean(result), nil
+	}
+
+	if !InternalIsCallable(target) {
+		return False, errors.NewTypeError("Right-hand side of 'instanceof' is not callable")
+	}
+	return OrdinaryHasInstance(target, o)
 }
 
 // SpeciesConstructor retrieves the constructor that should be used to create new objects
 // that are derived from the argument object o. The defaultConstructor argument is the
 // constructor to use if a constructor's @@species property cannot be found starting from o.
 // SpeciesConstructor is specified in 7.3.20.
-func SpeciesConstructor() {
-	panic("TODO")
+func SpeciesConstructor(o *Object, defaultConstructor *Object) (*Object, errors.Error) {
+	c, err := Get(o, NewStringKey("constructor"))
+	if err != nil {
+		return nil, err
+	}
+	if c == Undefined {
+		return defaultConstructor, nil
+	}
+	cObj, ok := c.(*Object)
+	if !ok {
+		return nil, errors.NewTypeError("Object's constructor is not an object")
+	}
+
+	s, err := Get(cObj, NewStringOrSymbol(SymbolSpecies))
+	if err != nil {
+		return nil, err
+	}
+	if s == Undefined || s == Null {
+		return defaultConstructor, nil
+	}
+	if InternalIsConstructor(s) {
+		return s.(*Object), nil
+	}
+	return nil, errors.NewTypeError("Object's @@species is not a constructor")
 }
 
+// Kinds of EnumerableOwnPropertyNames as specified in 7.3.21.
+const (
+	EnumerateKey          = "key"
+	EnumerateValue        = "value"
+	EnumerateKeyPlusValue = "key+value"
+)
+
+// EnumerableOwnPropertyNames returns the keys, values or entries of all
+// enumerable own String-keyed properties of the given object, depending
+// on the given kind. Entries are arrays whose prototype is the
+// %ArrayPrototype% of the given realm, which may only be nil if kind is
+// not EnumerateKeyPlusValue.
 // EnumerableOwnPropertyNames is specified in 7.3.21.
-func EnumerableOwnPropertyNames() {
-	panic("TODO")
+func EnumerableOwnPropertyNames(r Realm, o *Object, kind string) ([]Value, errors.Error) {
+	var properties []Value
+	for _, key := range o.OwnPropertyKeys() {
+		if key.Type() != TypeString {
+			continue
+		}
+
+		desc := o.GetOwnProperty(key)
+		if desc == nil || !desc.Enumerable() {
+			continue
+		}
+
+		if kind == EnumerateKey {
+			properties = append(properties, key.underlying)
+			continue
+		}
+
+		value, err := Get(o, key)
+		if err != nil {
+			return nil, err
+		}
+		if kind == EnumerateValue {
+			properties = append(properties, value)
+		} else {
+			properties = append(properties, CreateArrayFromList(r, []Value{key.underlying, value}))
+		}
+	}
+	return properties, nil
 }
 
 // 7.3.22 GetFunctionRealm is not implemented here. You will find it in
 // internal/runtime/realm/relam.go. The reason for that is, that otherwise,
 // an import cycle lang -> realm -> lang rises.
 
+// CopyDataProperties copies all enumerable own properties of the given
+// source value to the target object, except for the properties whose
+// keys are contained in excludedItems. The realm is used to convert the
+// source to an object.
 // CopyDataProperties is specified in 7.3.23.
-func CopyDataProperties() {
-	panic("TODO")
+func CopyDataProperties(r Realm, target *Object, source Value, excludedItems []StringOrSymbol) (*Object, errors.Error) {
+	if source == Undefined || source == Null {
+		return target, nil
+	}
+
+	from, err := ToObject(r, source)
+	if err != nil {
+		return nil, err
+	}
+
+outer:
+	for _, nextKey := range from.OwnPropertyKeys() {
+		for _, e := range excludedItems {
+			if InternalSameValue(e.underlying, nextKey.underlying) {
+				continue outer
+			}
+		}
+
+		desc := from.GetOwnProperty(nextKey)
+		if desc == nil || !desc.Enumerable() {
+			continue
+		}
+
+		propValue, err := Get(from, nextKey)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := CreateDataProperty(target, nextKey, propValue); err != nil {
+			return nil, err
+		}
+	}
+	return target, nil
 }
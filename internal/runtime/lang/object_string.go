@@ -0,0 +1,92 @@
+package lang
+
+import (
+	"strconv"
+
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+)
+
+var stringExoticMethods = &ExoticMethods{
+	GetOwnProperty:    stringGetOwnProperty,
+	DefineOwnProperty: stringDefineOwnProperty,
+	OwnPropertyKeys:   stringOwnPropertyKeys,
+}
+
+// StringCreate creates a new String exotic object, whose [[StringData]] is
+// the given value.
+// StringCreate is specified in 9.4.3.4.
+func StringCreate(value String, proto *Object) *Object {
+	s := ObjectCreate(proto, SlotStringData)
+	s.SetSlot(SlotStringData, value)
+	s.Exotic = stringExoticMethods
+	s.addProperty(keyLength, NewDataProperty(NewNumber(float64(len(value))), False, False, False))
+	return s
+}
+
+// stringGetOwnProperty is the GetOwnProperty internal method of String
+// exotic objects.
+// stringGetOwnProperty is specified in 9.4.3.1.
+func stringGetOwnProperty(s *Object, p StringOrSymbol) *Property {
+	if desc := s.OrdinaryGetOwnProperty(p); desc != nil {
+		return desc
+	}
+	return StringGetOwnProperty(s, p)
+}
+
+// stringDefineOwnProperty is the DefineOwnProperty internal method of
+// String exotic objects.
+// stringDefineOwnProperty is specified in 9.4.3.2.
+func stringDefineOwnProperty(s *Object, p StringOrSymbol, desc *Property) (Boolean, errors.Error) {
+	if stringDesc := StringGetOwnProperty(s, p); stringDesc != nil {
+		return s.IsCompatiblePropertyDescriptor(s.Extensible, desc, stringDesc), nil
+	}
+	return s.OrdinaryDefineOwnProperty(p, desc), nil
+}
+
+// stringOwnPropertyKeys is the OwnPropertyKeys internal method of String
+// exotic objects. The indices of the code units of the string come first.
+// stringOwnPropertyKeys is specified in 9.4.3.3.
+func stringOwnPropertyKeys(s *Object) []StringOrSymbol {
+	str := s.GetSlot(SlotStringData).(String)
+
+	keys := make([]StringOrSymbol, 0, len(str)+len(s.keys))
+	for i := range str {
+		keys = append(keys, NewStringKey(strconv.Itoa(i)))
+	}
+	for _, k := range s.OrdinaryOwnPropertyKeys() {
+		if k.Type() == TypeString {
+			if n, ok := integerIndex(k.underlying.(String)); ok && n < float64(len(str)) {
+				continue
+			}
+		}
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// StringGetOwnProperty returns a property descriptor for the code unit of
+// the [[StringData]] of the given String exotic object at the index p, or
+// nil if p is not such an index.
+// StringGetOwnProperty is specified in 9.4.3.5.
+func StringGetOwnProperty(s *Object, p StringOrSymbol) *Property {
+	if p.Type() != TypeString {
+		return nil
+	}
+
+	index := CanonicalNumericIndexString(p.underlying.(String))
+	if index == Undefined || !InternalIsInteger(index) {
+		return nil
+	}
+	n := index.(Number)
+	if n.IsNegZero() {
+		return nil
+	}
+
+	str := s.GetSlot(SlotStringData).(String)
+	if n.value < 0 || float64(len(str)) <= n.value {
+		return nil
+	}
+
+	resultStr := String{str[int(n.value)]}
+	return NewDataProperty(resultStr, False, True, False)
+}
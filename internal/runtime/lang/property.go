@@ -1,5 +1,7 @@
 package lang
 
+import "github.com/gojisvm/gojis/internal/runtime/errors"
+
 // Available field names for a property.
 //
 // A property is a data property descriptor if the fields
@@ -86,7 +88,7 @@ func (p *Property) Writable() Boolean {
 	return val.(Boolean)
 }
 
-// Get returns the value of the field 'Get', or False
+// Get returns the value of the field 'Get', or Undefined
 // if the field is not set.
 func (p *Property) Get() Value {
 	val, ok := p.GetField(FieldNameGet)
@@ -96,7 +98,7 @@ func (p *Property) Get() Value {
 	return val.(Value)
 }
 
-// Set returns the value of the field 'Set', or False
+// Set returns the value of the field 'Set', or Undefined
 // if the field is not set.
 func (p *Property) Set() Value {
 	val, ok := p.GetField(FieldNameSet)
@@ -166,17 +168,164 @@ func (p *Property) IsGenericDescriptor() Boolean {
 	return False
 }
 
-// FromPropertyDescriptor TODO:
-func FromPropertyDescriptor(desc *Property) *Object {
-	panic("TODO")
+// Has is used to determine whether the field with the given name is
+// present in this property.
+func (p *Property) Has(field string) bool {
+	_, ok := p.GetField(field)
+	return ok
+}
+
+// Getter returns the function object of the field 'Get', or nil if
+// the field is not set or the property does not have a getter.
+func (p *Property) Getter() *Object {
+	return accessorFunction(p.Get())
+}
+
+// Setter returns the function object of the field 'Set', or nil if
+// the field is not set or the property does not have a setter.
+func (p *Property) Setter() *Object {
+	return accessorFunction(p.Set())
+}
+
+func accessorFunction(v Value) *Object {
+	if o, ok := v.(*Object); ok && o != nil {
+		return o
+	}
+	return nil
+}
+
+// copy returns a new property that has the same fields as this
+// property.
+func (p *Property) copy() *Property {
+	c := NewProperty()
+	c.apply(p)
+	return c
+}
+
+// apply sets all fields that are present in desc on this property.
+func (p *Property) apply(desc *Property) {
+	for k, v := range desc.Record.fields {
+		p.Record.fields[k] = v
+	}
+}
+
+// FromPropertyDescriptor creates an object with the properties 'value',
+// 'writable', 'get', 'set', 'enumerable' and 'configurable', for all fields
+// that are present in the given property descriptor. The prototype of the
+// object is %ObjectPrototype% of the given realm. If desc is nil, Undefined
+// is returned.
+// FromPropertyDescriptor is specified in 6.2.5.4.
+func FromPropertyDescriptor(r Realm, desc *Property) Value {
+	if desc == nil {
+		return Undefined
+	}
+
+	obj := ObjectCreate(intrinsicObject(r, IntrinsicNameObjectPrototype))
+	fields := []struct {
+		field string
+		name  string
+		value func() Value
+	}{
+		{FieldNameValue, "value", desc.Value},
+		{FieldNameWritable, "writable", func() Value { return desc.Writable() }},
+		{FieldNameGet, "get", func() Value { return accessorValue(desc.Getter()) }},
+		{FieldNameSet, "set", func() Value { return accessorValue(desc.Setter()) }},
+		{FieldNameEnumerable, "enumerable", func() Value { return desc.Enumerable() }},
+		{FieldNameConfigurable, "configurable", func() Value { return desc.Configurable() }},
+	}
+	for _, f := range fields {
+		if desc.Has(f.field) {
+			_, _ = CreateDataProperty(obj, NewStringKey(f.name), f.value())
+		}
+	}
+	return obj
+}
+
+func accessorValue(f *Object) Value {
+	if f == nil {
+		return Undefined
+	}
+	return f
 }
 
-// ToPropertyDescriptor TODO:
-func ToPropertyDescriptor(obj *Object) *Property {
-	panic("TODO")
+// ToPropertyDescriptor converts the given object to a property descriptor.
+// A TypeError is returned if the argument is not an object, if a getter or
+// setter is not callable, or if the object describes both a data and an
+// accessor property.
+// ToPropertyDescriptor is specified in 6.2.5.5.
+func ToPropertyDescriptor(arg Value) (*Property, errors.Error) {
+	obj, ok := arg.(*Object)
+	if !ok {
+		return nil, errors.NewTypeError("Property description must be an object")
+	}
+
+	desc := NewProperty()
+	fields := []struct {
+		field string
+		name  string
+	}{
+		{FieldNameEnumerable, "enumerable"},
+		{FieldNameConfigurable, "configurable"},
+		{FieldNameValue, "value"},
+		{FieldNameWritable, "writable"},
+		{FieldNameGet, "get"},
+		{FieldNameSet, "set"},
+	}
+	for _, f := range fields {
+		key := NewStringKey(f.name)
+		if !obj.HasProperty(key) {
+			continue
+		}
+
+		val, err := Get(obj, key)
+		if err != nil {
+			return nil, err
+		}
+
+		switch f.field {
+		case FieldNameEnumerable, FieldNameConfigurable, FieldNameWritable:
+			desc.SetField(f.field, ToBoolean(val))
+		case FieldNameGet, FieldNameSet:
+			if !InternalIsCallable(val) && val != Undefined {
+				return nil, errors.NewTypeError("Getter or setter must be a function")
+			}
+			desc.SetField(f.field, val)
+		default:
+			desc.SetField(f.field, val)
+		}
+	}
+
+	if (desc.Has(FieldNameGet) || desc.Has(FieldNameSet)) &&
+		(desc.Has(FieldNameValue) || desc.Has(FieldNameWritable)) {
+		return nil, errors.NewTypeError("Invalid property descriptor. Cannot both specify accessors and a value or writable attribute")
+	}
+	return desc, nil
 }
 
-// CompletePropertyDescriptor TODO:
+// CompletePropertyDescriptor sets all fields that are absent in the given
+// property descriptor to their default values, and returns the descriptor.
+// CompletePropertyDescriptor is specified in 6.2.5.6.
 func CompletePropertyDescriptor(desc *Property) *Property {
-	panic("TODO")
+	if desc.IsGenericDescriptor() || desc.IsDataDescriptor() {
+		if !desc.Has(FieldNameValue) {
+			desc.SetField(FieldNameValue, Undefined)
+		}
+		if !desc.Has(FieldNameWritable) {
+			desc.SetField(FieldNameWritable, False)
+		}
+	} else {
+		if !desc.Has(FieldNameGet) {
+			desc.SetField(FieldNameGet, Undefined)
+		}
+		if !desc.Has(FieldNameSet) {
+			desc.SetField(FieldNameSet, Undefined)
+		}
+	}
+	if !desc.Has(FieldNameEnumerable) {
+		desc.SetField(FieldNameEnumerable, False)
+	}
+	if !desc.Has(FieldNameConfigurable) {
+		desc.SetField(FieldNameConfigurable, False)
+	}
+	return desc
 }
@@ -0,0 +1,34 @@
+package lang
+
+// Realm is implemented by realm records, as specified in 8.2. Operations
+// that create objects whose prototype is an intrinsic object, like ToObject,
+// take the realm that provides the intrinsics as argument, since the realm
+// package depends on this package and cannot be used by it.
+type Realm interface {
+	InternalValue
+
+	// GetIntrinsicObject returns the intrinsic object with the given name,
+	// or Undefined if there is no such intrinsic object.
+	GetIntrinsicObject(name string) Value
+}
+
+// Names of the intrinsic objects that are used by this package. The
+// specification denotes the usage of these names as e.g. %ArrayPrototype%.
+const (
+	IntrinsicNameObjectPrototype  = "ObjectPrototype"
+	IntrinsicNameArrayPrototype   = "ArrayPrototype"
+	IntrinsicNameBooleanPrototype = "BooleanPrototype"
+	IntrinsicNameNumberPrototype  = "NumberPrototype"
+	IntrinsicNameStringPrototype  = "StringPrototype"
+	IntrinsicNameSymbolPrototype  = "SymbolPrototype"
+)
+
+// intrinsicObject returns the intrinsic object with the given name of the
+// given realm. It panics if the realm does not provide such an object.
+func intrinsicObject(r Realm, name string) *Object {
+	o, ok := r.GetIntrinsicObject(name).(*Object)
+	if !ok {
+		panic("Missing intrinsic object %" + name + "%")
+	}
+	return o
+}
@@ -2,7 +2,7 @@ package lang
 
 import (
 	"fmt"
-	"strings"
+	"math"
 
 	"github.com/gojisvm/gojis/internal/runtime/errors"
 )
@@ -34,11 +34,17 @@ func IsArray(arg Value) Boolean { return Boolean(InternalIsArray(arg)) }
 
 // InternalIsArray is used to determine whether the given value is an array.
 func InternalIsArray(arg Value) bool {
-	if arg.Type() != TypeObject {
+	o, ok := arg.(*Object)
+	if !ok {
 		return false
 	}
 
-	panic("TODO: Arrays")
+	if o.HasSlot(SlotProxyHandler) {
+		// the target of a revoked proxy is Null
+		target, ok := o.GetSlot(SlotProxyTarget).(*Object)
+		return ok && InternalIsArray(target)
+	}
+	return o.Exotic == arrayExoticMethods
 }
 
 // IsCallable is used to determine whether the value has a Call internal method.
@@ -107,8 +113,8 @@ func InternalIsInteger(arg Value) bool {
 		return false
 	}
 
-	val := arg.Value().(float64)
-	return val == float64(int(val))
+	val := arg.(Number).value
+	return val == math.Trunc(val)
 }
 
 // IsPropertyKey is used to determine whether the type of the value is String or Symbol.
@@ -130,12 +136,19 @@ func IsRegExp(arg Value) Boolean { return Boolean(InternalIsRegExp(arg)) }
 
 // InternalIsRegExp is used to determine whether the value has a @@match property, or, if not,
 // if it has a RegExpMatcher internal slot.
+// If getting the @@match property throws an exception, it is treated as if
+// the property was undefined.
 func InternalIsRegExp(arg Value) bool {
 	if arg.Type() != TypeObject {
 		return false
 	}
 
-	panic("TODO: 7.2.8 InternalIsRegExp")
+	o := arg.(*Object)
+	matcher, err := o.Get(NewStringOrSymbol(SymbolMatch), o)
+	if err == nil && matcher != Undefined {
+		return bool(ToBoolean(matcher))
+	}
+	return o.HasSlot(SlotRegExpMatcher)
 }
 
 // IsStringPrefix is used to determine whether p is a prefix of q or not.
@@ -145,8 +158,7 @@ func IsStringPrefix(p, q String) Boolean { return Boolean(InternalIsStringPrefix
 
 // InternalIsStringPrefix is used to determine whether p is a prefix of q or not.
 func InternalIsStringPrefix(p, q String) bool {
-	pVal, qVal := p.Value().(string), q.Value().(string)
-	return strings.HasPrefix(qVal, pVal)
+	return len(p) <= len(q) && StringsEqual(p, q[:len(p)])
 }
 
 // SameValue is used to determine, whether x and y have the same value.
@@ -161,19 +173,16 @@ func InternalSameValue(x, y Value) bool {
 	}
 
 	if x.Type() == TypeNumber {
-		if x == NaN && y == NaN {
+		xNum, yNum := x.(Number), y.(Number)
+		if xNum.isNaN && yNum.isNaN {
 			return true
 		}
 
-		if x == PosZero && y == NegZero {
-			return false
-		}
-
-		if x == NegZero && y == PosZero {
+		if xNum.IsNegZero() != yNum.IsNegZero() {
 			return false
 		}
 
-		return x.Value() == y.Value()
+		return xNum == yNum
 	}
 
 	return InternalSameValueNonNumber(x, y)
@@ -191,24 +200,14 @@ func InternalSameValueZero(x, y Value) bool {
 	if x.Type() != y.Type() {
 		return false
 	}
-	if x.Type() != y.Type() {
-		return false
-	}
 
 	if x.Type() == TypeNumber {
-		if x == NaN && y == NaN {
+		xNum, yNum := x.(Number), y.(Number)
+		if xNum.isNaN && yNum.isNaN {
 			return true
 		}
 
-		if x == PosZero && y == NegZero {
-			return true
-		}
-
-		if x == NegZero && y == PosZero {
-			return true
-		}
-
-		return x.Value() == y.Value()
+		return xNum.value == yNum.value && !xNum.isNaN && !yNum.isNaN
 	}
 
 	return InternalSameValueNonNumber(x, y)
@@ -225,26 +224,118 @@ func SameValueNonNumber(x, y Value) Boolean {
 // InternalSameValueNonNumber is used to determine, whether x and y have the same value,
 // assuming their type is not Number.
 func InternalSameValueNonNumber(x, y Value) bool {
-	if x.Type() == TypeUndefined {
+	switch x.Type() {
+	case TypeUndefined,
+		TypeNull:
 		return true
+	case TypeString:
+		return StringsEqual(toStringValue(x), toStringValue(y))
+	case TypeBoolean:
+		return x.(Boolean) == y.(Boolean)
+	case TypeSymbol:
+		return toSymbolValue(x) == toSymbolValue(y)
 	}
 
-	if x.Type() == TypeNull {
-		return true
+	return x == y
+}
+
+// AbstractRelationalComparison compares x and y, and returns True if x is
+// less than y, False if not and Undefined if at least one of the operands
+// is NaN. If leftFirst is false, y is converted to a primitive value before
+// x, which is needed by operators like > that swap their operands.
+// AbstractRelationalComparison is specified in 7.2.13.
+func AbstractRelationalComparison(x, y Value, leftFirst bool) (Value, errors.Error) {
+	var px, py Value
+	var err errors.Error
+	if leftFirst {
+		if px, err = ToPrimitive(x, TypeNumber); err != nil {
+			return nil, err
+		}
+		if py, err = ToPrimitive(y, TypeNumber); err != nil {
+			return nil, err
+		}
+	} else {
+		if py, err = ToPrimitive(y, TypeNumber); err != nil {
+			return nil, err
+		}
+		if px, err = ToPrimitive(x, TypeNumber); err != nil {
+			return nil, err
+		}
+	}
+
+	if px.Type() == TypeString && py.Type() == TypeString {
+		sx, sy := toStringValue(px), toStringValue(py)
+		for i := 0; i < len(sx) && i < len(sy); i++ {
+			if sx[i] != sy[i] {
+				return Boolean(sx[i] < sy[i]), nil
+			}
+		}
+		return Boolean(len(sx) < len(sy)), nil
 	}
 
-	if x.Type() == TypeString {
-		xVal, yVal := x.Value().(String), y.Value().(String)
-		return StringsEqual(xVal, yVal)
+	nx, err := ToNumber(px)
+	if err != nil {
+		return nil, err
+	}
+	ny, err := ToNumber(py)
+	if err != nil {
+		return nil, err
+	}
+	if nx.isNaN || ny.isNaN {
+		return Undefined, nil
 	}
+	return Boolean(nx.value < ny.value), nil
+}
 
-	if x.Type() == TypeBoolean {
-		return x.Value() == y.Value()
+// AbstractEqualityComparison compares x and y as done by the == operator.
+// AbstractEqualityComparison is specified in 7.2.14.
+func AbstractEqualityComparison(x, y Value) (Boolean, errors.Error) {
+	if x.Type() == y.Type() {
+		return StrictEqualityComparison(x, y), nil
 	}
 
-	if x.Type() == TypeSymbol {
-		return x.Value() == y.Value()
+	switch {
+	case (x.Type() == TypeNull && y.Type() == TypeUndefined) ||
+		(x.Type() == TypeUndefined && y.Type() == TypeNull):
+		return True, nil
+	case x.Type() == TypeNumber && y.Type() == TypeString:
+		return AbstractEqualityComparison(x, StringToNumber(toStringValue(y)))
+	case x.Type() == TypeString && y.Type() == TypeNumber:
+		return AbstractEqualityComparison(StringToNumber(toStringValue(x)), y)
+	case x.Type() == TypeBoolean:
+		nx, _ := ToNumber(x)
+		return AbstractEqualityComparison(nx, y)
+	case y.Type() == TypeBoolean:
+		ny, _ := ToNumber(y)
+		return AbstractEqualityComparison(x, ny)
+	case TypeIsOneOf(x, TypeString, TypeNumber, TypeSymbol) && y.Type() == TypeObject:
+		py, err := ToPrimitive(y, nil)
+		if err != nil {
+			return False, err
+		}
+		return AbstractEqualityComparison(x, py)
+	case x.Type() == TypeObject && TypeIsOneOf(y, TypeString, TypeNumber, TypeSymbol):
+		px, err := ToPrimitive(x, nil)
+		if err != nil {
+			return False, err
+		}
+		return AbstractEqualityComparison(px, y)
+	}
+
+	return False, nil
+}
+
+// StrictEqualityComparison compares x and y as done by the === operator.
+// StrictEqualityComparison is specified in 7.2.15.
+func StrictEqualityComparison(x, y Value) Boolean {
+	if x.Type() != y.Type() {
+		return False
+	}
+
+	if x.Type() == TypeNumber {
+		xNum, yNum := x.(Number), y.(Number)
+		return Boolean(!xNum.isNaN && !yNum.isNaN && xNum.value == yNum.value)
 	}
 
-	return x.Value() == y.Value()
+	return SameValueNonNumber(x, y)
 }
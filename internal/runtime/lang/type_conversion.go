@@ -3,6 +3,10 @@ package lang
 import (
 	"fmt"
 	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode"
 
 	"github.com/gojisvm/gojis/internal/runtime/errors"
 )
@@ -32,7 +36,7 @@ func ToPrimitive(input Value, preferredType interface{}) (Value, errors.Error) {
 			panic("input is TypeObject, but not *Object")
 		}
 
-		exoticToPrim, err := GetMethod(o, NewStringOrSymbol(SymbolToPrimitive))
+		exoticToPrim, err := GetMethod(nil, o, NewStringOrSymbol(SymbolToPrimitive))
 		if err != nil {
 			return nil, err
 		}
@@ -106,14 +110,12 @@ func ToBoolean(arg Value) Boolean {
 	case TypeBoolean:
 		return arg.(Boolean)
 	case TypeNumber:
-		if val := arg.Value(); val == PosZero.Value() ||
-			val == NegZero.Value() ||
-			arg == NaN {
+		if n := arg.(Number); n.value == 0 || n.isNaN {
 			return False
 		}
 		return True
 	case TypeString:
-		if arg.Value() == "" {
+		if len(toStringValue(arg)) == 0 {
 			return False
 		}
 		return True
@@ -138,7 +140,7 @@ func ToNumber(arg Value) (Number, errors.Error) {
 	case TypeNumber:
 		return arg.(Number), nil
 	case TypeString:
-		panic("TODO: 7.1.3.1")
+		return StringToNumber(toStringValue(arg)), nil
 	case TypeSymbol:
 		return Zero, errors.NewTypeError("Cannot convert from Symbol to Number")
 	case TypeObject:
@@ -152,7 +154,107 @@ func ToNumber(arg Value) (Number, errors.Error) {
 	panic(unhandledType(arg))
 }
 
-// ToInteger converts the argument to an integer Number value.
+// StringToNumber converts the given String to a Number, as specified by the
+// grammar StringNumericLiteral. If the String does not conform to that
+// grammar, NaN is returned.
+// StringToNumber is specified in 7.1.3.1.
+func StringToNumber(str String) Number {
+	s := strings.TrimFunc(str.String(), isStrWhiteSpaceChar)
+	if s == "" {
+		return PosZero
+	}
+
+	if len(s) > 2 && s[0] == '0' {
+		base := 0
+		switch s[1] {
+		case 'x', 'X':
+			base = 16
+		case 'o', 'O':
+			base = 8
+		case 'b', 'B':
+			base = 2
+		}
+		if base != 0 {
+			i, ok := new(big.Int).SetString(s[2:], base)
+			if !ok || strings.ContainsAny(s[2:], "+-_") {
+				return NaN
+			}
+			f, _ := new(big.Float).SetInt(i).Float64()
+			return NewNumber(f)
+		}
+	}
+
+	unsigned := strings.TrimLeft(s, "+-")
+	if len(s)-len(unsigned) > 1 {
+		return NaN
+	}
+	if unsigned == "Infinity" {
+		if s[0] == '-' {
+			return NegInfinity
+		}
+		return PosInfinity
+	}
+	if !isStrUnsignedDecimalLiteral(unsigned) {
+		return NaN
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil && !isRangeError(err) {
+		return NaN
+	}
+	return NewNumber(f)
+}
+
+// isStrUnsignedDecimalLiteral is used to determine whether the given string
+// conforms to the grammar StrUnsignedDecimalLiteral, except Infinity.
+func isStrUnsignedDecimalLiteral(s string) bool {
+	digits := func() int {
+		n := 0
+		for n < len(s) && s[n] >= '0' && s[n] <= '9' {
+			n++
+		}
+		s = s[n:]
+		return n
+	}
+
+	n := digits()
+	if s != "" && s[0] == '.' {
+		s = s[1:]
+		n += digits()
+	}
+	if n == 0 {
+		return false
+	}
+	if s != "" && (s[0] == 'e' || s[0] == 'E') {
+		s = s[1:]
+		if s != "" && (s[0] == '+' || s[0] == '-') {
+			s = s[1:]
+		}
+		if digits() == 0 {
+			return false
+		}
+	}
+	return s == ""
+}
+
+func isRangeError(err error) bool {
+	numErr, ok := err.(*strconv.NumError)
+	return ok && numErr.Err == strconv.ErrRange
+}
+
+// isStrWhiteSpaceChar is used to determine whether the given rune is a
+// StrWhiteSpaceChar, that is, either a WhiteSpace or a LineTerminator, as
+// specified in 11.2 and 11.3.
+func isStrWhiteSpaceChar(r rune) bool {
+	switch r {
+	case '\t', '\v', '\f', ' ', '\u00a0', '\ufeff',
+		'\n', '\r', '\u2028', '\u2029':
+		return true
+	}
+	return unicode.Is(unicode.Zs, r)
+}
+
+// ToInteger converts the argument to an integral Number value.
 // ToInteger is specified in 7.1.4.
 func ToInteger(arg Value) (Number, errors.Error) {
 	number, err := ToNumber(arg)
@@ -164,16 +266,8 @@ func ToInteger(arg Value) (Number, errors.Error) {
 		return PosZero, nil
 	}
 
-	val := arg.Value()
-
-	if val == PosZero.Value() ||
-		val == NegZero.Value() ||
-		arg == PosInfinity ||
-		arg == NegInfinity {
-		return arg.(Number), nil
-	}
-
-	return NewNumber(math.Floor(val.(float64))), nil
+	// math.Trunc returns ±0 and ±Inf unchanged
+	return NewNumber(math.Trunc(number.value)), nil
 }
 
 func toInt(arg Value, bits uint) (Number, errors.Error) {
@@ -182,7 +276,7 @@ func toInt(arg Value, bits uint) (Number, errors.Error) {
 		return Zero, err
 	}
 
-	float64val := uintval.Value().(float64)
+	float64val := uintval.value
 	if float64val >= float64(int64(1)<<(bits-1)) {
 		return NewNumber(float64val - float64(int64(1)<<bits)), nil
 	}
@@ -196,15 +290,17 @@ func toUint(arg Value, bits uint) (Number, errors.Error) {
 	}
 
 	if number == NaN ||
-		number == PosZero || number == NegZero ||
+		number.value == 0 ||
 		number == PosInfinity || number == NegInfinity {
 		return PosZero, nil
 	}
 
-	floatval := number.Value().(float64)
-	intval := int64(floatval)
-	intXXbit := intval % 1 << bits
-	return NewNumber(float64(intXXbit)), nil
+	modulo := float64(int64(1) << bits)
+	intXXbit := math.Mod(math.Trunc(number.value), modulo)
+	if intXXbit < 0 {
+		intXXbit += modulo
+	}
+	return NewNumber(intXXbit + 0), nil
 }
 
 // ToInt32 converts the argument to an int32 Number value.
@@ -249,14 +345,14 @@ func ToUint8(arg Value) (Number, errors.Error) {
 func ToUint8Clamp(arg Value) (Number, errors.Error) {
 	number, err := ToNumber(arg)
 	if err != nil {
-		return Zero, nil
+		return Zero, err
 	}
 
 	if number == NaN {
 		return PosZero, nil
 	}
 
-	floatval := number.Value().(float64)
+	floatval := number.value
 	if floatval <= 0 {
 		return PosZero, nil
 	}
@@ -266,13 +362,13 @@ func ToUint8Clamp(arg Value) (Number, errors.Error) {
 
 	f := math.Floor(floatval)
 	if f+0.5 < floatval {
-		return NewNumber(floatval + 1), nil
+		return NewNumber(f + 1), nil
 	}
 	if floatval < f+0.5 {
 		return NewNumber(f), nil
 	}
 
-	// f == floatval, also f is an integer because of math.Floor
+	// floatval is exactly between f and f+1, round to even
 	if int64(f)%2 != 0 {
 		return NewNumber(f + 1), nil
 	}
@@ -281,48 +377,195 @@ func ToUint8Clamp(arg Value) (Number, errors.Error) {
 
 // ToString converts the argument to a String.
 // ToString is specified in 7.1.12.
-func ToString(arg Value) String {
-	panic("TODO")
+func ToString(arg Value) (String, errors.Error) {
+	switch arg.Type() {
+	case TypeUndefined:
+		return NewString("undefined"), nil
+	case TypeNull:
+		return NewString("null"), nil
+	case TypeBoolean:
+		if arg.(Boolean) {
+			return NewString("true"), nil
+		}
+		return NewString("false"), nil
+	case TypeNumber:
+		return NumberToString(arg.(Number)), nil
+	case TypeString:
+		return toStringValue(arg), nil
+	case TypeSymbol:
+		return nil, errors.NewTypeError("Cannot convert a Symbol value to a string")
+	case TypeObject:
+		primValue, err := ToPrimitive(arg, TypeString)
+		if err != nil {
+			return nil, err
+		}
+		return ToString(primValue)
+	}
+
+	panic(unhandledType(arg))
 }
 
 // NumberToString converts the given Number to a String.
 // NumberToString is specified in 7.1.12.1.
 func NumberToString(n Number) String {
-	panic("TODO")
+	return NewString(numberToString(n))
+}
+
+func numberToString(n Number) string {
+	m := n.value
+	switch {
+	case n.isNaN:
+		return "NaN"
+	case m == 0:
+		return "0"
+	case m < 0:
+		return "-" + numberToString(NewNumber(-m))
+	case math.IsInf(m, +1):
+		return "Infinity"
+	}
+
+	// the shortest representation that uniquely identifies m has the form
+	// d.ddde±x, the digits are s, k is their count and n is x+1
+	repr := strconv.FormatFloat(m, 'e', -1, 64)
+	mantissa, exponent := repr[:strings.IndexByte(repr, 'e')], repr[strings.IndexByte(repr, 'e')+1:]
+	s := strings.Replace(mantissa, ".", "", 1)
+	k := len(s)
+	e, _ := strconv.Atoi(exponent)
+	pos := e + 1
+
+	switch {
+	case k <= pos && pos <= 21:
+		return s + strings.Repeat("0", pos-k)
+	case 0 < pos && pos <= 21:
+		return s[:pos] + "." + s[pos:]
+	case -6 < pos && pos <= 0:
+		return "0." + strings.Repeat("0", -pos) + s
+	}
+
+	sign := "+"
+	if pos-1 < 0 {
+		sign = "-"
+	}
+	exp := strconv.Itoa(abs(pos - 1))
+	if k == 1 {
+		return s + "e" + sign + exp
+	}
+	return s[:1] + "." + s[1:] + "e" + sign + exp
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
 }
 
-// ToObject converts the given argument to an Object.
+// ToObject converts the given argument to an Object. Wrapper objects for
+// primitive values are created with the intrinsic prototypes of the given
+// realm, which may be nil if the argument is known to be an Object.
 // ToObject is specified in 7.1.13.
-func ToObject(arg Value) *Object {
-	panic("TODO")
+func ToObject(r Realm, arg Value) (*Object, errors.Error) {
+	switch arg.Type() {
+	case TypeUndefined:
+		return nil, errors.NewTypeError("Cannot convert undefined to object")
+	case TypeNull:
+		return nil, errors.NewTypeError("Cannot convert null to object")
+	case TypeBoolean:
+		o := ObjectCreate(intrinsicObject(r, IntrinsicNameBooleanPrototype), SlotBooleanData)
+		o.SetSlot(SlotBooleanData, arg)
+		return o, nil
+	case TypeNumber:
+		o := ObjectCreate(intrinsicObject(r, IntrinsicNameNumberPrototype), SlotNumberData)
+		o.SetSlot(SlotNumberData, arg)
+		return o, nil
+	case TypeString:
+		return StringCreate(toStringValue(arg), intrinsicObject(r, IntrinsicNameStringPrototype)), nil
+	case TypeSymbol:
+		o := ObjectCreate(intrinsicObject(r, IntrinsicNameSymbolPrototype), SlotSymbolData)
+		o.SetSlot(SlotSymbolData, toSymbolValue(arg))
+		return o, nil
+	case TypeObject:
+		return arg.(*Object), nil
+	}
+
+	panic(unhandledType(arg))
 }
 
 // ToPropertyKey converts the given argument to a StringOrSymbol.
 // ToPropertyKey is specified in 7.1.14.
-func ToPropertyKey(arg Value) StringOrSymbol {
-	panic("TODO")
+func ToPropertyKey(arg Value) (StringOrSymbol, errors.Error) {
+	key, err := ToPrimitive(arg, TypeString)
+	if err != nil {
+		return StringOrSymbol{}, err
+	}
+
+	if key.Type() == TypeSymbol {
+		return NewStringOrSymbol(toSymbolValue(key)), nil
+	}
+
+	str, err := ToString(key)
+	if err != nil {
+		return StringOrSymbol{}, err
+	}
+	return NewStringOrSymbol(str), nil
 }
 
 // ToLength converts argument to an integer suitable for use as the length of an
 // array-like object.
 // ToLength is specified in 7.1.15.
-func ToLength(arg Value) Number {
-	panic("TODO")
+func ToLength(arg Value) (Number, errors.Error) {
+	length, err := ToInteger(arg)
+	if err != nil {
+		return Zero, err
+	}
+
+	if length.value <= 0 {
+		return PosZero, nil
+	}
+	return NewNumber(math.Min(length.value, maxSafeInteger)), nil
 }
 
+// maxSafeInteger is 2^53-1, the largest integer n such that n and n+1 are
+// both exactly representable as a Number.
+const maxSafeInteger = 1<<53 - 1
+
 // CanonicalNumericIndexString returns argument converted to a numeric value if
 // it is a String representation of a Number that would be produced by ToString,
 // or the string "-0". Otherwise, it returns Undefined.
 // CanonicalNumericIndexString is specified in 7.1.16.
-func CanonicalNumericIndexString(arg Value) Number {
-	panic("TODO")
+func CanonicalNumericIndexString(arg String) Value {
+	if arg.String() == "-0" {
+		return NegZero
+	}
+
+	n := StringToNumber(arg)
+	if !StringsEqual(NumberToString(n), arg) {
+		return Undefined
+	}
+	return n
 }
 
 // ToIndex returns value argument converted to a numeric value if it is a valid
 // integer index value.
 // ToIndex is specified in 7.1.17.
-func ToIndex(arg Value) Number {
-	panic("TODO")
+func ToIndex(arg Value) (Number, errors.Error) {
+	if arg == Undefined {
+		return PosZero, nil
+	}
+
+	integerIndex, err := ToInteger(arg)
+	if err != nil {
+		return Zero, err
+	}
+	if integerIndex.value < 0 {
+		return Zero, errors.NewRangeError("Invalid index")
+	}
+
+	index, _ := ToLength(integerIndex)
+	if index.value != integerIndex.value {
+		return Zero, errors.NewRangeError("Invalid index")
+	}
+	return index, nil
 }
 
 func unhandledType(arg Value) error {
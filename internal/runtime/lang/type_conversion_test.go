@@ -190,7 +190,7 @@ func TestToIntegerFuzzy(t *testing.T) {
 		return val
 	}
 	expected := func(x float64) Number {
-		return NewNumber(math.Floor(x))
+		return NewNumber(math.Trunc(x))
 	}
 	require.NoError(quick.CheckEqual(conv, expected, nil))
 }
@@ -2,7 +2,6 @@ package lang
 
 import (
 	"math"
-	"math/big"
 )
 
 var _ Value = (*Number)(nil) // ensure that Number implements Value
@@ -20,21 +19,19 @@ var (
 	// PosZero  as specified by the language spec.
 	PosZero = NewNumber(+0)
 	// NegZero as specified by the language spec.
-	NegZero = NewNumber(neg(0))
+	NegZero = NewNumber(math.Copysign(0, -1))
 	// Zero as specified by the language spec.
 	// This is an alias for PosZero.
 	Zero = PosZero
 )
 
-// neg is required to trick Go into making a zero actually negative.
-// I don't really get why this is required. It works though.
-func neg(x float64) float64 {
-	return -x
-}
-
 // Number is a language type as specified by the language spec.
+// Numbers are double-precision 64-bit binary format IEEE 754-2008 values.
+// All NaN values are represented by the same Number, so a Number can be
+// compared to NaN with ==. Note that PosZero == NegZero is true, use
+// IsNegZero to tell them apart.
 type Number struct {
-	value *big.Float
+	value float64
 	isNaN bool
 }
 
@@ -49,28 +46,38 @@ func NewNumber(x float64) Number {
 	}
 
 	return Number{
-		value: big.NewFloat(x),
+		value: x,
 	}
 }
 
 // Value returns the float64 value of the Number.
 // If the Number is NaN, math.NaN() will be returned.
 func (n Number) Value() interface{} {
+	return n.Float64()
+}
+
+// Float64 returns the float64 value of the Number, just like Value does.
+func (n Number) Float64() float64 {
 	if n.isNaN {
 		return math.NaN()
 	}
 
-	val, _ := n.value.Float64()
-	return val
+	return n.value
+}
+
+// IsNaN is used to determine whether the Number is NaN.
+func (n Number) IsNaN() bool {
+	return n.isNaN
+}
+
+// IsNegZero is used to determine whether the Number is -0.
+func (n Number) IsNegZero() bool {
+	return n.value == 0 && math.Signbit(n.value)
 }
 
 // Type returns lang.TypeNumber.
 func (Number) Type() Type { return TypeNumber }
 
 func (n Number) String() string {
-	if n.isNaN {
-		return "NaN"
-	}
-
-	return n.value.String()
+	return NumberToString(n).Value().(string)
 }
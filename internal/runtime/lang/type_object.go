@@ -1,8 +1,7 @@
 package lang
 
 import (
-	"reflect"
-	"strconv"
+	"sort"
 
 	"github.com/gojisvm/gojis/internal/runtime/errors"
 )
@@ -11,12 +10,21 @@ var _ Value = (*Object)(nil) // ensure that Object implements Value
 
 // Object is a language type as specified by the language spec.
 type Object struct {
-	fields map[StringOrSymbol]*Property
-	slots  map[StringOrSymbol]Value
+	fields map[propertyKey]*Property
+	keys   []StringOrSymbol // property keys in creation order
+	slots  map[propertyKey]Value
 
 	Prototype  Value // *Object or Null
 	Extensible bool
 
+	// heap is the heap that the properties of the object are accounted
+	// to, which is the heap of the prototype the object was created with.
+	heap *Heap
+
+	// Exotic holds the internal methods of an exotic object that differ
+	// from the ordinary internal methods. It is nil for ordinary objects.
+	Exotic *ExoticMethods
+
 	// Function Object
 	Realm          InternalValue
 	ScriptOrModule interface{}
@@ -35,24 +43,42 @@ type Object struct {
 	Construct func(*Object, ...Value) (*Object, errors.Error)
 }
 
+// ExoticMethods holds the internal methods of an exotic object, as
+// described in 6.1.7.2. Every method that is nil is the ordinary internal
+// method. The methods receive the object that they are invoked on as first
+// argument, and can use the Ordinary methods of that object to fall back
+// to the ordinary behaviour.
+type ExoticMethods struct {
+	GetPrototypeOf    func(o *Object) Value
+	SetPrototypeOf    func(o *Object, v Value) Boolean
+	IsExtensible      func(o *Object) Boolean
+	PreventExtensions func(o *Object) Boolean
+	GetOwnProperty    func(o *Object, p StringOrSymbol) *Property
+	DefineOwnProperty func(o *Object, p StringOrSymbol, desc *Property) (Boolean, errors.Error)
+	HasProperty       func(o *Object, p StringOrSymbol) Boolean
+	Get               func(o *Object, p StringOrSymbol, receiver Value) (Value, errors.Error)
+	Set               func(o *Object, p StringOrSymbol, v, receiver Value) (Boolean, errors.Error)
+	Delete            func(o *Object, p StringOrSymbol) Boolean
+	OwnPropertyKeys   func(o *Object) []StringOrSymbol
+}
+
 // ObjectCreate creates a new ordinary object at runtime, where proto is the given prototype
 // (must be an Object or Null), and internalSlotsList is a list of the names of additional
 // internal slots that must be defined as part of the object. If none are provided,
 // an empty list is used.
 // ObjectCreate is specified in 9.1.12.
 func ObjectCreate(proto Value, internalSlotsList ...StringOrSymbol) *Object {
-	if internalSlotsList == nil {
-		internalSlotsList = []StringOrSymbol{}
-	}
-
 	obj := new(Object)
-	obj.fields = make(map[StringOrSymbol]*Property)
-	obj.slots = make(map[StringOrSymbol]Value)
+	obj.fields = make(map[propertyKey]*Property)
+	obj.slots = make(map[propertyKey]Value)
 	for _, slot := range internalSlotsList {
-		obj.slots[slot] = Undefined
+		obj.slots[slot.key()] = Undefined
 	}
 	EnsureTypeOneOf(proto, TypeObject, TypeNull) // panic if proto is not TypeObject or TypeNull
 	obj.Prototype = proto
+	if p, ok := proto.(*Object); ok {
+		obj.heap = p.heap
+	}
 	obj.Extensible = true
 
 	return obj
@@ -68,11 +94,37 @@ func (o *Object) Value() interface{} { return o }
 // Type returns lang.TypeObject.
 func (o *Object) Type() Type { return TypeObject }
 
+// HasSlot is used to determine whether the object has an internal slot
+// with the given name.
+func (o *Object) HasSlot(n StringOrSymbol) bool {
+	_, ok := o.slots[n.key()]
+	return ok
+}
+
+// GetSlot returns the value of the internal slot with the given name, or
+// nil if the object does not have such an internal slot.
+func (o *Object) GetSlot(n StringOrSymbol) Value {
+	return o.slots[n.key()]
+}
+
+// SetSlot sets the value of the internal slot with the given name. If the
+// object does not have such an internal slot yet, it is added.
+func (o *Object) SetSlot(n StringOrSymbol, val Value) {
+	if o.slots == nil {
+		o.slots = make(map[propertyKey]Value)
+	}
+	o.slots[n.key()] = val
+}
+
 /* -- 9.1, ordinary object internal methods and internal slots -- */
 
-// GetPrototypeOf delegates to OrdinaryGetPrototypeOf.
+// GetPrototypeOf delegates to OrdinaryGetPrototypeOf, unless the object is an
+// exotic object with its own GetPrototypeOf method.
 // GetPrototypeOf is specified in 9.1.1.
 func (o *Object) GetPrototypeOf() Value {
+	if o.Exotic != nil && o.Exotic.GetPrototypeOf != nil {
+		return o.Exotic.GetPrototypeOf(o)
+	}
 	return o.OrdinaryGetPrototypeOf()
 }
 
@@ -83,9 +135,13 @@ func (o *Object) OrdinaryGetPrototypeOf() Value {
 	return o.Prototype
 }
 
-// SetPrototypeOf delegates to OrdinarySetPrototypeOf.
+// SetPrototypeOf delegates to OrdinarySetPrototypeOf, unless the object is an
+// exotic object with its own SetPrototypeOf method.
 // SetPrototypeOf is specified in 9.1.2.
 func (o *Object) SetPrototypeOf(v Value) Boolean {
+	if o.Exotic != nil && o.Exotic.SetPrototypeOf != nil {
+		return o.Exotic.SetPrototypeOf(o, v)
+	}
 	return o.OrdinarySetPrototypeOf(v)
 }
 
@@ -118,12 +174,11 @@ func (o *Object) OrdinarySetPrototypeOf(v Value) Boolean {
 			done = true
 		} else if InternalSameValue(p, o) {
 			return False
+		} else if pObj := p.(*Object); pObj.Exotic != nil && pObj.Exotic.GetPrototypeOf != nil {
+			// this type assertion cannot fail, since p is checked to be Object or Null, and Null is handled above
+			done = true
 		} else {
-			// FIXME: if p.GetPrototypeOf is not the ordinary object internal method defined in 9.1.1,
-			// set done to true.
-			// else {
-			p = p.(*Object).Prototype // this type assertion cannot fail, since p is checked to be Object or Null, and Null is handled above
-			// }
+			p = pObj.Prototype
 		}
 	}
 
@@ -131,9 +186,13 @@ func (o *Object) OrdinarySetPrototypeOf(v Value) Boolean {
 	return True
 }
 
-// IsExtensible delegates to OrdinaryIsExtensible.
+// IsExtensible delegates to OrdinaryIsExtensible, unless the object is an
+// exotic object with its own IsExtensible method.
 // IsExtensible is specified in 9.1.3.
 func (o *Object) IsExtensible() Boolean {
+	if o.Exotic != nil && o.Exotic.IsExtensible != nil {
+		return o.Exotic.IsExtensible(o)
+	}
 	return o.OrdinaryIsExtensible()
 }
 
@@ -144,9 +203,13 @@ func (o *Object) OrdinaryIsExtensible() Boolean {
 	return Boolean(o.Extensible)
 }
 
-// PreventExtensions delegates to OrdinaryPreventExtensions.
+// PreventExtensions delegates to OrdinaryPreventExtensions, unless the object is an
+// exotic object with its own PreventExtensions method.
 // PreventExtensions is specified in 9.1.4.
 func (o *Object) PreventExtensions() Boolean {
+	if o.Exotic != nil && o.Exotic.PreventExtensions != nil {
+		return o.Exotic.PreventExtensions(o)
+	}
 	return o.OrdinaryPreventExtensions()
 }
 
@@ -159,9 +222,13 @@ func (o *Object) OrdinaryPreventExtensions() Boolean {
 	return True
 }
 
-// GetOwnProperty delegates to OrdinaryGetOwnProperty.
+// GetOwnProperty delegates to OrdinaryGetOwnProperty, unless the
+// object is an exotic object with its own GetOwnProperty method.
 // GetOwnProperty is specified in 9.1.5.
 func (o *Object) GetOwnProperty(p StringOrSymbol) *Property {
+	if o.Exotic != nil && o.Exotic.GetOwnProperty != nil {
+		return o.Exotic.GetOwnProperty(o, p)
+	}
 	return o.OrdinaryGetOwnProperty(p)
 }
 
@@ -170,30 +237,27 @@ func (o *Object) GetOwnProperty(p StringOrSymbol) *Property {
 // pointer is used.
 // OrdinaryGetOwnProperty is specified in 9.1.5.1.
 func (o *Object) OrdinaryGetOwnProperty(p StringOrSymbol) *Property {
-	x, ok := o.fields[p]
+	x, ok := o.fields[p.key()]
 	if !ok {
 		return nil // actually Undefined
 	}
 
-	// TODO: maybe introduce a Property#copy function?
-	d := NewProperty()
-	if x.IsDataDescriptor() {
-		d.SetField(FieldNameValue, x.Value())
-		d.SetField(FieldNameWritable, x.Writable())
-	} else if x.IsAccessorDescriptor() {
-		d.SetField(FieldNameGet, x.Get())
-		d.SetField(FieldNameSet, x.Set())
-	}
-	d.SetField(FieldNameEnumerable, x.Enumerable())
-	d.SetField(FieldNameConfigurable, x.Configurable())
-
-	return d
+	return x.copy()
 }
 
-// DefineOwnProperty delegates to OrdinaryDefineOwnProperty.
+// DefineOwnProperty delegates to OrdinaryDefineOwnProperty, unless the
+// object is an exotic object with its own DefineOwnProperty method. If the
+// property would exceed the limit of the heap of the object, a RangeError
+// is thrown instead.
 // DefineOwnProperty is specified in 9.1.6.
-func (o *Object) DefineOwnProperty(p StringOrSymbol, desc *Property) Boolean {
-	return o.OrdinaryDefineOwnProperty(p, desc)
+func (o *Object) DefineOwnProperty(p StringOrSymbol, desc *Property) (Boolean, errors.Error) {
+	if err := o.allocateProperty(p, desc); err != nil {
+		return False, err
+	}
+	if o.Exotic != nil && o.Exotic.DefineOwnProperty != nil {
+		return o.Exotic.DefineOwnProperty(o, p, desc)
+	}
+	return o.OrdinaryDefineOwnProperty(p, desc), nil
 }
 
 // OrdinaryDefineOwnProperty is used to define an own property of the object.
@@ -209,12 +273,14 @@ func (o *Object) OrdinaryDefineOwnProperty(p StringOrSymbol, desc *Property) Boo
 // the object and the property key being Undefined.
 // IsCompatiblePropertyDescriptor is specified in 9.1.6.2.
 func (o *Object) IsCompatiblePropertyDescriptor(extensible bool, desc, current *Property) Boolean {
-	// TODO: make code more beautiful
 	return ((*Object)(nil)).ValidateAndApplyPropertyDescriptor(StringOrSymbol{}, extensible, desc, current)
 }
 
-// ValidateAndApplyPropertyDescriptor is pretty complicated and should be simplified and/or
-// refactored into multiple sub-functions (TODO:).
+// ValidateAndApplyPropertyDescriptor validates whether the given property
+// descriptor can be applied to the current property of an object with the
+// given extensibility, and if so and the object is not nil, applies it.
+// If current is nil, a new property is created, whose attributes that are
+// absent in desc are set to their default values.
 // ValidateAndApplyPropertyDescriptor is specified in 9.1.6.3.
 func (o *Object) ValidateAndApplyPropertyDescriptor(p StringOrSymbol, extensible bool, desc, current *Property) Boolean {
 	// if o != nil, p is not zero value
@@ -223,26 +289,18 @@ func (o *Object) ValidateAndApplyPropertyDescriptor(p StringOrSymbol, extensible
 			return False
 		}
 
-		if desc.IsGenericDescriptor() || desc.IsDataDescriptor() {
-			if o != nil {
-				/*
-					If O is not undefined, create an own accessor property named P of object O whose [[Get]], [[Set]],
-					[[Enumerable]] and [[Configurable]] attribute values are described by Desc. If the value of an
-					attribute field of Desc is absent, the attribute of the newly created property is set to its default
-					value.
-				*/
-				panic("TODO")
+		if o != nil {
+			var prop *Property
+			if desc.IsGenericDescriptor() || desc.IsDataDescriptor() {
+				prop = NewDataProperty(Undefined, False, False, False)
+			} else {
+				prop = NewAccessorProperty(nil, nil, False, False)
 			}
-		} else {
-			// desc.IsAccessorDescriptor() is true
-			/*
-			   If O is not undefined, create an own accessor property named P of object O whose [[Get]], [[Set]],
-			   [[Enumerable]] and [[Configurable]] attribute values are described by Desc. If the value of an
-			   attribute field of Desc is absent, the attribute of the newly created property is set to its default
-			   value.
-			*/
-			panic("TODO")
+			prop.apply(desc)
+			o.addProperty(p, prop)
+			o.heap.resize(0, propertyBytes(p, prop))
 		}
+		return True
 	}
 
 	if len(desc.Record.fields) == 0 {
@@ -254,32 +312,33 @@ func (o *Object) ValidateAndApplyPropertyDescriptor(p StringOrSymbol, extensible
 			return False
 		}
 
-		if desc.Enumerable() && (current.Enumerable() == !desc.Enumerable()) {
+		if desc.Has(FieldNameEnumerable) && desc.Enumerable() != current.Enumerable() {
 			return False
 		}
 	}
 
 	if desc.IsGenericDescriptor() {
-		// no further valudation is required
+		// no further validation is required
 	} else if current.IsDataDescriptor() != desc.IsDataDescriptor() {
 		if !current.Configurable() {
 			return False
 		}
 
-		if current.IsDataDescriptor() {
+		if o != nil {
 			/*
-			   If O is not undefined, convert the property named P of object O from a data property to an
-			   accessor property. Preserve the existing values of the converted property's [[Configurable]] and
-			   [[Enumerable]] attributes and set the rest of the property's attributes to their default values.
+			   Convert the property named P of object O from a data property to an
+			   accessor property or vice versa. Preserve the existing values of the
+			   converted property's [[Configurable]] and [[Enumerable]] attributes and
+			   set the rest of the property's attributes to their default values.
 			*/
-			panic("TODO")
-		} else {
-			/*
-			   If O is not undefined, convert the property named P of object O from an accessor property to a
-			   data property. Preserve the existing values of the converted property's [[Configurable]] and
-			   [[Enumerable]] attributes and set the rest of the property's attributes to their default values.
-			*/
-			panic("TODO")
+			var converted *Property
+			if current.IsDataDescriptor() {
+				converted = NewAccessorProperty(nil, nil, current.Enumerable(), current.Configurable())
+			} else {
+				converted = NewDataProperty(Undefined, False, current.Enumerable(), current.Configurable())
+			}
+			o.heap.resize(propertyBytes(p, current), propertyBytes(p, converted))
+			o.fields[p.key()] = converted
 		}
 	} else if current.IsDataDescriptor() && desc.IsDataDescriptor() {
 		if !current.Configurable() && !current.Writable() {
@@ -287,7 +346,7 @@ func (o *Object) ValidateAndApplyPropertyDescriptor(p StringOrSymbol, extensible
 				return False
 			}
 
-			if _, ok := desc.GetField(FieldNameValue); ok && !InternalSameValue(desc.Value(), current.Value()) {
+			if desc.Has(FieldNameValue) && !InternalSameValue(desc.Value(), current.Value()) {
 				return False
 			}
 
@@ -295,7 +354,11 @@ func (o *Object) ValidateAndApplyPropertyDescriptor(p StringOrSymbol, extensible
 		}
 	} else if current.IsAccessorDescriptor() && desc.IsAccessorDescriptor() {
 		if !current.Configurable() {
-			if _, ok := desc.GetField(FieldNameSet); ok && reflect.ValueOf(desc.Set()).Pointer() != reflect.ValueOf(current.Set()).Pointer() {
+			if desc.Has(FieldNameSet) && desc.Setter() != current.Setter() {
+				return False
+			}
+
+			if desc.Has(FieldNameGet) && desc.Getter() != current.Getter() {
 				return False
 			}
 
@@ -304,18 +367,33 @@ func (o *Object) ValidateAndApplyPropertyDescriptor(p StringOrSymbol, extensible
 	}
 
 	if o != nil {
-		prop := o.fields[p]
-		for k, v := range desc.Record.fields {
-			prop.fields[k] = v
-		}
+		prop := o.fields[p.key()]
+		before := propertyBytes(p, prop)
+		prop.apply(desc)
+		o.heap.resize(before, propertyBytes(p, prop))
 	}
 
 	return True
 }
 
-// HasProperty delegates to OrdinaryHasProperty.
+// addProperty adds a new own property to the object, without accounting it
+// to the heap of the object. Exotic objects are created with their length
+// property this way, which is considered part of the object itself.
+func (o *Object) addProperty(p StringOrSymbol, prop *Property) {
+	if o.fields == nil {
+		o.fields = make(map[propertyKey]*Property)
+	}
+	o.fields[p.key()] = prop
+	o.keys = append(o.keys, p)
+}
+
+// HasProperty delegates to OrdinaryHasProperty, unless the object is an
+// exotic object with its own HasProperty method.
 // HasProperty is specified in 9.1.7.
 func (o *Object) HasProperty(p StringOrSymbol) Boolean {
+	if o.Exotic != nil && o.Exotic.HasProperty != nil {
+		return o.Exotic.HasProperty(o, p)
+	}
 	return o.OrdinaryHasProperty(p)
 }
 
@@ -336,9 +414,13 @@ func (o *Object) OrdinaryHasProperty(p StringOrSymbol) Boolean {
 	return False
 }
 
-// Get delegates to OrdinaryGet.
+// Get delegates to OrdinaryGet, unless the object is an exotic object
+// with its own Get method.
 // Get is specified in 9.1.8.
 func (o *Object) Get(p StringOrSymbol, receiver Value) (Value, errors.Error) {
+	if o.Exotic != nil && o.Exotic.Get != nil {
+		return o.Exotic.Get(o, p, receiver)
+	}
 	return o.OrdinaryGet(p, receiver)
 }
 
@@ -367,15 +449,19 @@ func (o *Object) OrdinaryGet(p StringOrSymbol, receiver Value) (Value, errors.Er
 		return desc.Value(), nil
 	}
 
-	if getter := desc.Get(); getter != Undefined {
-		return Call(getter.(*Object), receiver)
+	if getter := desc.Getter(); getter != nil {
+		return Call(getter, receiver)
 	}
 	return Undefined, nil
 }
 
-// Set delegates to OrdinarySet.
+// Set delegates to OrdinarySet, unless the object is an exotic object
+// with its own Set method.
 // Set is specified in 9.1.9.
 func (o *Object) Set(p StringOrSymbol, v, receiver Value) (Boolean, errors.Error) {
+	if o.Exotic != nil && o.Exotic.Set != nil {
+		return o.Exotic.Set(o, p, v, receiver)
+	}
 	return o.OrdinarySet(p, v, receiver)
 }
 
@@ -386,8 +472,11 @@ func (o *Object) OrdinarySet(p StringOrSymbol, v, receiver Value) (Boolean, erro
 	return o.OrdinarySetWithOwnDescriptor(p, v, receiver, o.GetOwnProperty(p))
 }
 
-// OrdinarySetWithOwnDescriptor is pretty complicated and should be simplified and/or
-// refactored into multiple sub-functions (TODO:).
+// OrdinarySetWithOwnDescriptor sets the value of the property with the given
+// name, where ownDesc is the own property of the object with that name, or nil.
+// If there is no such property, the prototype chain is searched for a setter
+// or a non-writable property. Otherwise, the property is created on the
+// receiver.
 // OrdinarySetWithOwnDescriptor is specified in 9.1.9.2.
 func (o *Object) OrdinarySetWithOwnDescriptor(p StringOrSymbol, v, receiver Value, ownDesc *Property) (Boolean, errors.Error) {
 	if ownDesc == nil {
@@ -421,16 +510,16 @@ func (o *Object) OrdinarySetWithOwnDescriptor(p StringOrSymbol, v, receiver Valu
 
 			valueDesc := NewProperty()
 			valueDesc.SetField(FieldNameValue, v)
-			return receiverObj.DefineOwnProperty(p, valueDesc), nil
+			return receiverObj.DefineOwnProperty(p, valueDesc)
 		}
 
-		return CreateDataProperty(receiverObj, p, v), nil
+		return CreateDataProperty(receiverObj, p, v)
 	}
 
 	// assert: ownDesc.IsAccessorDescriptor is true
 
-	if setter := ownDesc.Set(); setter != Undefined {
-		_, err := Call(setter.(*Object), receiver, v)
+	if setter := ownDesc.Setter(); setter != nil {
+		_, err := Call(setter, receiver, v)
 		if err != nil {
 			return False, err
 		}
@@ -439,9 +528,13 @@ func (o *Object) OrdinarySetWithOwnDescriptor(p StringOrSymbol, v, receiver Valu
 	return False, nil
 }
 
-// Delete delegates to OrdinaryDelete.
+// Delete delegates to OrdinaryDelete, unless the object is an exotic
+// object with its own Delete method.
 // Delete is specified in 9.1.10.
 func (o *Object) Delete(p StringOrSymbol) Boolean {
+	if o.Exotic != nil && o.Exotic.Delete != nil {
+		return o.Exotic.Delete(o, p)
+	}
 	return o.OrdinaryDelete(p)
 }
 
@@ -455,16 +548,38 @@ func (o *Object) OrdinaryDelete(p StringOrSymbol) Boolean {
 	}
 
 	if desc.Configurable() {
-		delete(o.fields, p)
+		o.removeProperty(p)
 		return True
 	}
 
 	return False
 }
 
-// OwnPropertyKeys delegates to OrdinaryOwnPropertyKeys.
+// removeProperty removes an own property from the object.
+func (o *Object) removeProperty(p StringOrSymbol) {
+	k := p.key()
+	prop, ok := o.fields[k]
+	if !ok {
+		return
+	}
+
+	delete(o.fields, k)
+	o.heap.Release(propertyBytes(p, prop))
+	for i, key := range o.keys {
+		if key.key() == k {
+			o.keys = append(o.keys[:i], o.keys[i+1:]...)
+			break
+		}
+	}
+}
+
+// OwnPropertyKeys delegates to OrdinaryOwnPropertyKeys, unless the object
+// is an exotic object with its own OwnPropertyKeys method.
 // OwnPropertyKeys is specified in 9.1.11.
 func (o *Object) OwnPropertyKeys() []StringOrSymbol {
+	if o.Exotic != nil && o.Exotic.OwnPropertyKeys != nil {
+		return o.Exotic.OwnPropertyKeys(o)
+	}
 	return o.OrdinaryOwnPropertyKeys()
 }
 
@@ -472,36 +587,35 @@ func (o *Object) OwnPropertyKeys() []StringOrSymbol {
 // That is, given an object with the properties 'A', 'B', and 'C', OrdinaryOwnPropertyKeys
 // will return ['A', 'B', 'C'].
 //
-// TODO: add comment on ordering and grouping of property names
+// The keys that are integer indices come first, in ascending numeric index
+// order. They are followed by all other String keys and then by all Symbol
+// keys, both in the order in which the properties were created.
 //
 // OrdinaryOwnPropertyKeys is specified in 9.1.11.1.
 func (o *Object) OrdinaryOwnPropertyKeys() []StringOrSymbol {
-	keys := []StringOrSymbol{}
-	secs := make(map[int][]StringOrSymbol)
-	const (
-		integerIndex int = iota
-		stringIndex
-		symbolIndex
-	)
-
-	for k := range o.fields {
-		if n, err := strconv.ParseInt(k.String().Value().(string), 10, 64); err == nil && n >= 0 {
-			// k is an integer index
-			secs[integerIndex] = append(secs[integerIndex], k)
-			continue
-		}
-
-		if k.Type() == TypeString {
-			secs[stringIndex] = append(secs[stringIndex], k)
-		}
+	type index struct {
+		key StringOrSymbol
+		n   float64
+	}
 
+	var indices []index
+	var strs, syms []StringOrSymbol
+	for _, k := range o.keys {
 		if k.Type() == TypeSymbol {
-			secs[symbolIndex] = append(secs[symbolIndex], k)
+			syms = append(syms, k)
+		} else if n, ok := integerIndex(k.underlying.(String)); ok {
+			indices = append(indices, index{k, n})
+		} else {
+			strs = append(strs, k)
 		}
 	}
+	sort.Slice(indices, func(i, j int) bool { return indices[i].n < indices[j].n })
 
-	keys = append(keys, secs[integerIndex]...)
-	keys = append(keys, secs[stringIndex]...)
-	keys = append(keys, secs[symbolIndex]...)
+	keys := make([]StringOrSymbol, 0, len(o.keys))
+	for _, i := range indices {
+		keys = append(keys, i.key)
+	}
+	keys = append(keys, strs...)
+	keys = append(keys, syms...)
 	return keys
 }
@@ -44,3 +44,49 @@ func StringsEqual(s1, s2 String) bool {
 
 	return true
 }
+
+// String returns the Go string representation of s. Unpaired surrogates
+// are replaced by U+FFFD.
+func (s String) String() string {
+	return string(utf16.Decode(s))
+}
+
+// key returns a Go string holding the code units of s, which can be used
+// as a map key. Different from String, it distinguishes between all
+// Strings, including those with unpaired surrogates.
+func (s String) key() string {
+	b := make([]byte, 2*len(s))
+	for i, c := range s {
+		b[2*i] = byte(c >> 8)
+		b[2*i+1] = byte(c)
+	}
+	return string(b)
+}
+
+// Concat creates a new String that is the concatenation of all given
+// Strings.
+func Concat(strs ...String) String {
+	n := 0
+	for _, s := range strs {
+		n += len(s)
+	}
+
+	result := make(String, 0, n)
+	for _, s := range strs {
+		result = append(result, s...)
+	}
+	return result
+}
+
+// toStringValue returns the String that the given value of type String
+// holds. The value may be a String or a StringOrSymbol wrapping a String.
+func toStringValue(v Value) String {
+	switch s := v.(type) {
+	case String:
+		return s
+	case StringOrSymbol:
+		return toStringValue(s.underlying)
+	}
+
+	panic("Value is not a String")
+}
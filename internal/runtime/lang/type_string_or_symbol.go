@@ -20,9 +20,19 @@ func NewStringOrSymbol(arg Value) StringOrSymbol {
 		panic("Type of argument must be String or Symbol")
 	}
 
+	if s, ok := arg.(StringOrSymbol); ok {
+		return s
+	}
+
 	return StringOrSymbol{arg}
 }
 
+// NewStringKey is a convenience function that creates a StringOrSymbol
+// wrapping a String created from the given Go string.
+func NewStringKey(str string) StringOrSymbol {
+	return StringOrSymbol{NewString(str)}
+}
+
 // Type returns lang.TypeString or lang.TypeSymbol, depending on the
 // wrapped Type.
 func (s StringOrSymbol) Type() Type { return s.underlying.Type() }
@@ -30,12 +40,35 @@ func (s StringOrSymbol) Type() Type { return s.underlying.Type() }
 // Value will return the wrapped value's Value.
 func (s StringOrSymbol) Value() interface{} { return s.underlying.Value() }
 
+// Underlying returns the wrapped String or Symbol.
+func (s StringOrSymbol) Underlying() Value { return s.underlying }
+
 // String is a convenience method to convert the lang.StringOrSymbol
-// to a lang.String.
+// to a lang.String. For a Symbol, its descriptive string as returned
+// by SymbolDescriptiveString is returned.
 func (s StringOrSymbol) String() String {
 	if s.underlying.Type() == TypeSymbol {
-		return s.underlying.(Symbol).String()
+		return SymbolDescriptiveString(s.underlying)
 	}
 
 	return s.underlying.(String)
 }
+
+// propertyKey is the comparable representation of a StringOrSymbol,
+// that is used to key the properties and internal slots of objects.
+// Strings are keyed by their code units, Symbols by their identity.
+type propertyKey struct {
+	str string
+	sym *Symbol
+}
+
+func (s StringOrSymbol) key() propertyKey {
+	switch u := s.underlying.(type) {
+	case String:
+		return propertyKey{str: u.key()}
+	case *Symbol:
+		return propertyKey{sym: u}
+	}
+
+	panic("Symbols must be referenced by pointer to be used as property keys")
+}
@@ -19,10 +19,20 @@ var (
 )
 
 // Symbol is a language type as specified by the language spec.
+// Every Symbol value is unique, so Symbols are compared by their
+// identity. Because of that, Symbols that are used as language values
+// must always be referenced by pointer.
 type Symbol struct {
 	Description Value // either Undefined or a String
 }
 
+// NewSymbol creates a new, unique Symbol with the given description,
+// which must be Undefined or a String.
+func NewSymbol(description Value) *Symbol {
+	EnsureTypeOneOf(description, TypeUndefined, TypeString)
+	return &Symbol{description}
+}
+
 // Value returns the Value of this symbol's description.
 func (s Symbol) Value() interface{} {
 	return s.Description.Value()
@@ -36,3 +46,37 @@ func (Symbol) Type() Type { return TypeSymbol }
 func (s Symbol) String() String {
 	return s.Description.(String)
 }
+
+// SymbolDescriptiveString returns the descriptive string of the given
+// Symbol, e.g. "Symbol(desc)".
+// SymbolDescriptiveString is specified in 19.4.3.2.1.
+func SymbolDescriptiveString(sym Value) String {
+	var desc Value = Undefined
+	switch s := sym.(type) {
+	case *Symbol:
+		desc = s.Description
+	case Symbol:
+		desc = s.Description
+	}
+
+	if desc == Undefined || desc == nil {
+		desc = String{}
+	}
+	return Concat(NewString("Symbol("), desc.(String), NewString(")"))
+}
+
+// toSymbolValue returns the Symbol that the given value of type Symbol
+// refers to. The value may be a *Symbol or a StringOrSymbol wrapping a
+// *Symbol.
+func toSymbolValue(v Value) *Symbol {
+	switch s := v.(type) {
+	case *Symbol:
+		return s
+	case Symbol:
+		return &s
+	case StringOrSymbol:
+		return toSymbolValue(s.underlying)
+	}
+
+	panic("Value is not a Symbol")
+}
@@ -0,0 +1,445 @@
+package runtime
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/gojisvm/gojis/internal/parser/ast"
+	"github.com/gojisvm/gojis/internal/runtime/binding"
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+)
+
+// evaluateUnary evaluates the given unary operation.
+// The evaluation of unary operators is specified in 12.5.
+func (r *Runtime) evaluateUnary(n *ast.UnaryExpression) (lang.Value, errors.Error) {
+	switch n.Operator {
+	case "delete":
+		return r.evaluateDelete(n.Argument)
+	case "typeof":
+		if id, ok := n.Argument.(*ast.Identifier); ok {
+			ref, err := r.evaluateReference(id)
+			if err != nil {
+				return nil, err
+			}
+			if ref.IsUnresolvableReference() {
+				return lang.NewString("undefined"), nil
+			}
+			val, err := ref.GetValue(r.Realm())
+			if err != nil {
+				return nil, err
+			}
+			return lang.NewString(typeOf(val)), nil
+		}
+	}
+
+	val, err := r.evaluate(n.Argument)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.Operator {
+	case "void":
+		return lang.Undefined, nil
+	case "typeof":
+		return lang.NewString(typeOf(val)), nil
+	case "!":
+		return !lang.ToBoolean(val), nil
+	case "+":
+		return lang.ToNumber(val)
+	case "-":
+		oldValue, err := lang.ToNumber(val)
+		if err != nil {
+			return nil, err
+		}
+		if oldValue.IsNaN() {
+			return lang.NaN, nil
+		}
+		return lang.NewNumber(-oldValue.Float64()), nil
+	case "~":
+		oldValue, err := lang.ToInt32(val)
+		if err != nil {
+			return nil, err
+		}
+		return lang.NewNumber(float64(^int32(oldValue.Float64()))), nil
+	}
+	panic(fmt.Sprintf("Unexpected unary operator %v", n.Operator))
+}
+
+// evaluateDelete deletes the property or binding that the given expression
+// refers to.
+// The evaluation of the delete operator is specified in 12.5.3.2.
+func (r *Runtime) evaluateDelete(n ast.Expression) (lang.Value, errors.Error) {
+	switch n.(type) {
+	case *ast.Identifier, *ast.MemberExpression:
+	default:
+		if _, err := r.evaluate(n); err != nil {
+			return nil, err
+		}
+		return lang.True, nil
+	}
+
+	ref, err := r.evaluateReference(n)
+	if err != nil {
+		return nil, err
+	}
+	return r.deleteReference(ref)
+}
+
+// deleteReference deletes the property or binding that the given reference
+// refers to.
+func (r *Runtime) deleteReference(ref *binding.Reference) (lang.Value, errors.Error) {
+	if ref.IsUnresolvableReference() {
+		return lang.True, nil
+	}
+
+	if ref.IsPropertyReference() {
+		if ref.IsSuperReference() {
+			return nil, errors.NewReferenceError("Unsupported reference to 'super'")
+		}
+		baseObj, err := lang.ToObject(r.Realm(), ref.GetBase())
+		if err != nil {
+			return nil, err
+		}
+		deleteStatus := baseObj.Delete(ref.GetReferencedName())
+		if !bool(deleteStatus) && ref.IsStrictReference() {
+			return nil, errors.NewTypeError(fmt.Sprintf("Cannot delete property '%v' of %v", ref.GetReferencedName().String(), describe(ref.GetBase())))
+		}
+		return deleteStatus, nil
+	}
+
+	deleted, err := ref.GetBase().(binding.Environment).DeleteBinding(ref.GetReferencedName().String())
+	if err != nil {
+		return nil, err
+	}
+	return lang.Boolean(deleted), nil
+}
+
+// evaluateUpdate evaluates the given increment or decrement.
+// The evaluation of update expressions is specified in 12.4.4.1, 12.4.5.1,
+// 12.5.7.1 and 12.5.8.1.
+func (r *Runtime) evaluateUpdate(n *ast.UpdateExpression) (lang.Value, errors.Error) {
+	ref, err := r.evaluateReference(n.Argument)
+	if err != nil {
+		return nil, err
+	}
+	val, err := ref.GetValue(r.Realm())
+	if err != nil {
+		return nil, err
+	}
+	oldValue, err := lang.ToNumber(val)
+	if err != nil {
+		return nil, err
+	}
+
+	delta := 1.0
+	if n.Operator == "--" {
+		delta = -1
+	}
+	newValue := lang.NewNumber(oldValue.Float64() + delta)
+	if err := ref.PutValue(r.Realm(), newValue); err != nil {
+		return nil, err
+	}
+
+	if n.Prefix {
+		return newValue, nil
+	}
+	return oldValue, nil
+}
+
+// evaluateBinary evaluates the given binary operation.
+// The evaluation of binary operators is specified in 12.6 to 12.12.
+func (r *Runtime) evaluateBinary(n *ast.BinaryExpression) (lang.Value, errors.Error) {
+	lval, err := r.evaluate(n.Left)
+	if err != nil {
+		return nil, err
+	}
+	rval, err := r.evaluate(n.Right)
+	if err != nil {
+		return nil, err
+	}
+	return r.applyOperator(n.Operator, lval, rval)
+}
+
+// applyOperator applies the given binary operator to the given values.
+func (r *Runtime) applyOperator(op string, lval, rval lang.Value) (lang.Value, errors.Error) {
+	switch op {
+	case "+":
+		return r.add(lval, rval)
+	case "-", "*", "/", "%", "**":
+		lnum, err := lang.ToNumber(lval)
+		if err != nil {
+			return nil, err
+		}
+		rnum, err := lang.ToNumber(rval)
+		if err != nil {
+			return nil, err
+		}
+		return lang.NewNumber(arithmetic(op, lnum.Float64(), rnum.Float64())), nil
+	case "<<", ">>", ">>>":
+		return shift(op, lval, rval)
+	case "&", "|", "^":
+		lnum, err := lang.ToInt32(lval)
+		if err != nil {
+			return nil, err
+		}
+		rnum, err := lang.ToInt32(rval)
+		if err != nil {
+			return nil, err
+		}
+		l, r := int32(lnum.Float64()), int32(rnum.Float64())
+		switch op {
+		case "&":
+			return lang.NewNumber(float64(l & r)), nil
+		case "|":
+			return lang.NewNumber(float64(l | r)), nil
+		}
+		return lang.NewNumber(float64(l ^ r)), nil
+	case "<":
+		return lessThan(lval, rval, true, false)
+	case ">":
+		return lessThan(rval, lval, false, false)
+	case "<=":
+		return lessThan(rval, lval, false, true)
+	case ">=":
+		return lessThan(lval, rval, true, true)
+	case "instanceof":
+		return lang.InstanceofOperator(lval, rval)
+	case "in":
+		obj, ok := rval.(*lang.Object)
+		if !ok {
+			key, _ := lang.ToString(lval)
+			return nil, errors.NewTypeError(fmt.Sprintf("Cannot use 'in' operator to search for '%v' in %v", key, describe(rval)))
+		}
+		key, err := lang.ToPropertyKey(lval)
+		if err != nil {
+			return nil, err
+		}
+		return lang.HasProperty(obj, key), nil
+	case "==", "!=":
+		result, err := lang.AbstractEqualityComparison(rval, lval)
+		if err != nil {
+			return nil, err
+		}
+		if op == "!=" {
+			return !result, nil
+		}
+		return result, nil
+	case "===":
+		return lang.StrictEqualityComparison(rval, lval), nil
+	case "!==":
+		return !lang.StrictEqualityComparison(rval, lval), nil
+	}
+	panic(fmt.Sprintf("Unexpected binary operator %v", op))
+}
+
+// add applies the addition operator, which either concatenates strings or
+// adds numbers.
+// The addition operator is specified in 12.8.3.1.
+func (r *Runtime) add(lval, rval lang.Value) (lang.Value, errors.Error) {
+	lprim, err := lang.ToPrimitive(lval, nil)
+	if err != nil {
+		return nil, err
+	}
+	rprim, err := lang.ToPrimitive(rval, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if lprim.Type() == lang.TypeString || rprim.Type() == lang.TypeString {
+		lstr, err := lang.ToString(lprim)
+		if err != nil {
+			return nil, err
+		}
+		rstr, err := lang.ToString(rprim)
+		if err != nil {
+			return nil, err
+		}
+		return r.concat(lstr, rstr)
+	}
+
+	lnum, err := lang.ToNumber(lprim)
+	if err != nil {
+		return nil, err
+	}
+	rnum, err := lang.ToNumber(rprim)
+	if err != nil {
+		return nil, err
+	}
+	return lang.NewNumber(lnum.Float64() + rnum.Float64()), nil
+}
+
+// arithmetic applies the given arithmetic operator other than addition to
+// the given numbers.
+// The arithmetic operators are specified in 12.6.4, 12.7.3 and 12.8.4.
+func arithmetic(op string, l, r float64) float64 {
+	switch op {
+	case "-":
+		return l - r
+	case "*":
+		return l * r
+	case "/":
+		return l / r
+	case "%":
+		return math.Mod(l, r)
+	}
+
+	// unlike math.Pow, exponentiation is NaN for an exponent of NaN, and
+	// for a base of +-1 with an infinite exponent
+	if math.IsNaN(r) || (math.Abs(l) == 1 && math.IsInf(r, 0)) {
+		return math.NaN()
+	}
+	return math.Pow(l, r)
+}
+
+// shift applies the given bitwise shift operator to the given values.
+// The bitwise shift operators are specified in 12.9.
+func shift(op string, lval, rval lang.Value) (lang.Value, errors.Error) {
+	var lnum lang.Number
+	var err errors.Error
+	if op == ">>>" {
+		lnum, err = lang.ToUint32(lval)
+	} else {
+		lnum, err = lang.ToInt32(lval)
+	}
+	if err != nil {
+		return nil, err
+	}
+	rnum, err := lang.ToUint32(rval)
+	if err != nil {
+		return nil, err
+	}
+
+	shiftCount := uint32(rnum.Float64()) & 0x1F
+	switch op {
+	case "<<":
+		return lang.NewNumber(float64(int32(lnum.Float64()) << shiftCount)), nil
+	case ">>":
+		return lang.NewNumber(float64(int32(lnum.Float64()) >> shiftCount)), nil
+	}
+	return lang.NewNumber(float64(uint32(lnum.Float64()) >> shiftCount)), nil
+}
+
+// lessThan compares the given values with the abstract relational
+// comparison. If negate is true, the result is negated, where an undefined
+// result is always false.
+// The relational operators are specified in 12.10.3.
+func lessThan(x, y lang.Value, leftFirst, negate bool) (lang.Value, errors.Error) {
+	result, err := lang.AbstractRelationalComparison(x, y, leftFirst)
+	if err != nil {
+		return nil, err
+	}
+	if result == lang.Undefined {
+		return lang.False, nil
+	}
+	if negate {
+		return !result.(lang.Boolean), nil
+	}
+	return result, nil
+}
+
+// evaluateLogical evaluates the given logical operation, which only
+// evaluates its right operand if the result is not yet determined by the
+// left operand.
+// Binary logical operators are specified in 12.13.3.
+func (r *Runtime) evaluateLogical(n *ast.LogicalExpression) (lang.Value, errors.Error) {
+	lval, err := r.evaluate(n.Left)
+	if err != nil {
+		return nil, err
+	}
+
+	lbool := lang.ToBoolean(lval)
+	if (n.Operator == "&&" && !lbool) || (n.Operator == "||" && lbool) {
+		return lval, nil
+	}
+	return r.evaluate(n.Right)
+}
+
+// evaluateAssignment evaluates the given simple, compound or destructuring
+// assignment.
+// The evaluation of assignment operators is specified in 12.15.4.
+func (r *Runtime) evaluateAssignment(n *ast.AssignmentExpression) (lang.Value, errors.Error) {
+	if n.Operator == "=" {
+		switch n.Left.(type) {
+		case *ast.ObjectPattern, *ast.ArrayPattern:
+			rval, err := r.evaluate(n.Right)
+			if err != nil {
+				return nil, err
+			}
+			if err := r.bindingInitialization(n.Left, rval, nil); err != nil {
+				return nil, err
+			}
+			return rval, nil
+		}
+	}
+
+	lref, err := r.evaluateReference(n.Left)
+	if err != nil {
+		return nil, err
+	}
+
+	var rval lang.Value
+	if n.Operator == "=" {
+		if id, ok := n.Left.(*ast.Identifier); ok && isAnonymousFunctionDefinition(n.Right) {
+			rval, err = r.namedEvaluation(n.Right, lang.NewStringKey(id.Name))
+		} else {
+			rval, err = r.evaluate(n.Right)
+		}
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		lval, err := lref.GetValue(r.Realm())
+		if err != nil {
+			return nil, err
+		}
+		right, err := r.evaluate(n.Right)
+		if err != nil {
+			return nil, err
+		}
+		if rval, err = r.applyOperator(strings.TrimSuffix(n.Operator, "="), lval, right); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := lref.PutValue(r.Realm(), rval); err != nil {
+		return nil, err
+	}
+	return rval, nil
+}
+
+// typeOf returns the result of the typeof operator for the given value.
+// The typeof operator is specified in 12.5.5.
+func typeOf(val lang.Value) string {
+	switch val.Type() {
+	case lang.TypeUndefined:
+		return "undefined"
+	case lang.TypeNull:
+		return "object"
+	case lang.TypeBoolean:
+		return "boolean"
+	case lang.TypeNumber:
+		return "number"
+	case lang.TypeString:
+		return "string"
+	case lang.TypeSymbol:
+		return "symbol"
+	}
+	if lang.InternalIsCallable(val) {
+		return "function"
+	}
+	return "object"
+}
+
+// describe returns a short description of the given value for error
+// messages.
+func describe(val lang.Value) string {
+	switch val.Type() {
+	case lang.TypeUndefined:
+		return "undefined"
+	case lang.TypeNull:
+		return "null"
+	}
+	return typeOf(val)
+}
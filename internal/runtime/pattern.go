@@ -0,0 +1,209 @@
+package runtime
+
+import (
+	"fmt"
+
+	"github.com/gojisvm/gojis/internal/parser/ast"
+	"github.com/gojisvm/gojis/internal/runtime/binding"
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+	"github.com/gojisvm/gojis/internal/runtime/realm"
+)
+
+// The functions in this file bind values to binding patterns, formal
+// parameters and destructuring assignment targets. If the environment that
+// they are called with is nil, which corresponds to an environment of
+// undefined in the specification, the bindings are assigned with PutValue
+// instead of being initialized. Since the targets of a destructuring
+// assignment are represented by the same patterns as binding patterns,
+// destructuring assignments are evaluated by binding with a nil
+// environment as well.
+
+// formalParametersInitialization binds the given arguments to the given
+// formal parameters. The arguments are not iterated with an iterator, since
+// the list iterator used in the specification is not observable.
+// IteratorBindingInitialization of FormalParameters is specified in 14.1.19.
+func (r *Runtime) formalParametersInitialization(formals []ast.Pattern, args []lang.Value, env binding.Environment) errors.Error {
+	for i, param := range formals {
+		if rest, ok := param.(*ast.RestElement); ok {
+			var restArgs []lang.Value
+			if i < len(args) {
+				restArgs = args[i:]
+			}
+			return r.bindingElementInitialization(rest.Argument, lang.CreateArrayFromList(r.Realm(), restArgs), env)
+		}
+
+		var v lang.Value = lang.Undefined
+		if i < len(args) {
+			v = args[i]
+		}
+		if err := r.bindingElementInitialization(param, v, env); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bindingElementInitialization binds the given value to the given element
+// of a binding pattern. If the element has an initializer, it is used if
+// the value is undefined.
+// BindingElement evaluation is specified in 13.3.3.6 and 13.3.3.8.
+func (r *Runtime) bindingElementInitialization(element ast.Node, value lang.Value, env binding.Environment) errors.Error {
+	var initializer ast.Expression
+	if p, ok := element.(*ast.AssignmentPattern); ok {
+		element = p.Left
+		initializer = p.Right
+	}
+
+	if initializer != nil && value == lang.Undefined {
+		var err errors.Error
+		if id, ok := element.(*ast.Identifier); ok && isAnonymousFunctionDefinition(initializer) {
+			value, err = r.namedEvaluation(initializer, lang.NewStringKey(id.Name))
+		} else {
+			value, err = r.evaluate(initializer)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return r.bindingInitialization(element, value, env)
+}
+
+// bindingInitialization binds the given value to the given identifier,
+// assignment target or binding pattern.
+// BindingInitialization is specified in 12.1.5 and 13.3.3.5.
+func (r *Runtime) bindingInitialization(target ast.Node, value lang.Value, env binding.Environment) errors.Error {
+	switch n := target.(type) {
+	case *ast.ObjectPattern:
+		if value == lang.Undefined || value == lang.Null {
+			return errors.NewTypeError(fmt.Sprintf("Cannot destructure '%v' as it is %[1]v.", describe(value)))
+		}
+		return r.propertyBindingInitialization(n, value, env)
+	case *ast.ArrayPattern:
+		iteratorRecord, err := lang.GetIterator(r.Realm(), value)
+		if err != nil {
+			return err
+		}
+		err = r.iteratorBindingInitialization(n.Elements, iteratorRecord, env)
+		if !iteratorRecord.Done {
+			return lang.IteratorClose(iteratorRecord, err)
+		}
+		return err
+	}
+
+	var ref *binding.Reference
+	var err errors.Error
+	if id, ok := target.(*ast.Identifier); ok {
+		ref, err = r.agent.ResolveBinding(lang.NewString(id.Name), env, r.strict)
+	} else {
+		ref, err = r.evaluateReference(target)
+	}
+	if err != nil {
+		return err
+	}
+
+	if env == nil {
+		return ref.PutValue(r.Realm(), value)
+	}
+	return ref.InitializeReferencedBinding(value)
+}
+
+// propertyBindingInitialization binds the properties of the given value to
+// the properties of the given object pattern.
+// PropertyBindingInitialization and RestBindingInitialization are
+// specified in 13.3.3.6 and 13.3.3.7.
+func (r *Runtime) propertyBindingInitialization(pattern *ast.ObjectPattern, value lang.Value, env binding.Environment) errors.Error {
+	var boundNames []lang.StringOrSymbol
+	for _, prop := range pattern.Properties {
+		switch prop := prop.(type) {
+		case *ast.Property:
+			key, err := r.propertyKey(prop.Key, prop.Computed)
+			if err != nil {
+				return err
+			}
+			boundNames = append(boundNames, key)
+
+			v, err := lang.GetV(r.Realm(), value, key)
+			if err != nil {
+				return err
+			}
+			if err := r.bindingElementInitialization(prop.Value, v, env); err != nil {
+				return err
+			}
+		case *ast.RestElement:
+			restObj := lang.ObjectCreate(r.intrinsic(realm.IntrinsicNameObjectPrototype))
+			if _, err := lang.CopyDataProperties(r.Realm(), restObj, value, boundNames); err != nil {
+				return err
+			}
+			return r.bindingInitialization(prop.Argument, restObj, env)
+		}
+	}
+	return nil
+}
+
+// iteratorBindingInitialization binds the values produced by the given
+// iterator to the given elements of an array pattern. A nil element is an
+// elision.
+// IteratorBindingInitialization is specified in 13.3.3.8.
+func (r *Runtime) iteratorBindingInitialization(elements []ast.Pattern, iteratorRecord *lang.IteratorRecord, env binding.Environment) errors.Error {
+	for _, elem := range elements {
+		if elem == nil {
+			if !iteratorRecord.Done {
+				if _, err := iteratorStepValue(iteratorRecord); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if rest, ok := elem.(*ast.RestElement); ok {
+			var values []lang.Value
+			for !iteratorRecord.Done {
+				v, err := iteratorStepValue(iteratorRecord)
+				if err != nil {
+					return err
+				}
+				if !iteratorRecord.Done {
+					values = append(values, v)
+				}
+			}
+			return r.bindingInitialization(rest.Argument, lang.CreateArrayFromList(r.Realm(), values), env)
+		}
+
+		var v lang.Value = lang.Undefined
+		if !iteratorRecord.Done {
+			var err errors.Error
+			v, err = iteratorStepValue(iteratorRecord)
+			if err != nil {
+				return err
+			}
+		}
+		if err := r.bindingElementInitialization(elem, v, env); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// iteratorStepValue steps the given iterator and returns the value of the
+// iterator result. If the iterator is done, or if stepping it fails, the
+// iterator record is marked as done, so that the iterator is not closed.
+// If the iterator is done, Undefined is returned.
+func iteratorStepValue(iteratorRecord *lang.IteratorRecord) (lang.Value, errors.Error) {
+	next, err := lang.IteratorStep(iteratorRecord)
+	if err != nil {
+		iteratorRecord.Done = true
+		return nil, err
+	}
+	if next == nil {
+		iteratorRecord.Done = true
+		return lang.Undefined, nil
+	}
+
+	v, err := lang.IteratorValue(next)
+	if err != nil {
+		iteratorRecord.Done = true
+		return nil, err
+	}
+	return v, nil
+}
@@ -0,0 +1,1473 @@
+package realm
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+)
+
+// Internal slots of array iterator objects, as specified in 22.1.5.3.
+var (
+	slotIteratedObject         = lang.NewStringKey("IteratedObject")
+	slotArrayIteratorNextIndex = lang.NewStringKey("ArrayIteratorNextIndex")
+	slotArrayIterationKind     = lang.NewStringKey("ArrayIterationKind")
+)
+
+// createIteratorPrototypes creates %IteratorPrototype% and
+// %ArrayIteratorPrototype%, as specified in 25.1.2 and 22.1.5.2.
+func (r *Realm) createIteratorPrototypes() {
+	iterProto := lang.ObjectCreate(r.intrinsic(IntrinsicNameObjectPrototype))
+	iterator := r.NewFunction("[Symbol.iterator]", 0, func(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+		return this, nil
+	})
+	_, _ = lang.CreateMethodProperty(iterProto, lang.NewStringOrSymbol(lang.SymbolIterator), iterator)
+	r.Intrinsics.SetField(IntrinsicNameIteratorPrototype, iterProto)
+
+	arrayIterProto := lang.ObjectCreate(iterProto)
+	r.defineFunction(arrayIterProto, "next", 0, r.arrayIteratorNext)
+	_, _ = lang.DefinePropertyOrThrow(arrayIterProto, lang.NewStringOrSymbol(lang.SymbolToStringTag), lang.NewDataProperty(lang.NewString("Array Iterator"), lang.False, lang.False, lang.True))
+	r.Intrinsics.SetField(IntrinsicNameArrayIteratorPrototype, arrayIterProto)
+}
+
+// CreateArrayIterator creates an iterator over the given array, that
+// produces keys, values or entries depending on the given kind, which is
+// one of lang.EnumerateKey, lang.EnumerateValue and
+// lang.EnumerateKeyPlusValue.
+// CreateArrayIterator is specified in 22.1.5.1.
+func (r *Realm) CreateArrayIterator(array *lang.Object, kind string) *lang.Object {
+	iterator := lang.ObjectCreate(r.intrinsic(IntrinsicNameArrayIteratorPrototype), slotIteratedObject, slotArrayIteratorNextIndex, slotArrayIterationKind)
+	iterator.SetSlot(slotIteratedObject, array)
+	iterator.SetSlot(slotArrayIteratorNextIndex, lang.Zero)
+	iterator.SetSlot(slotArrayIterationKind, lang.NewString(kind))
+	return iterator
+}
+
+// arrayIteratorNext is %ArrayIteratorPrototype%.next.
+// arrayIteratorNext is specified in 22.1.5.2.1.
+func (r *Realm) arrayIteratorNext(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+	o, ok := this.(*lang.Object)
+	if !ok || !o.HasSlot(slotArrayIteratorNextIndex) {
+		return nil, errors.NewTypeError("next method called on incompatible receiver")
+	}
+
+	a := o.GetSlot(slotIteratedObject)
+	if a == lang.Undefined {
+		return lang.CreateIterResultObject(r, lang.Undefined, true), nil
+	}
+	array := a.(*lang.Object)
+	index := o.GetSlot(slotArrayIteratorNextIndex).(lang.Number).Float64()
+	kind := o.GetSlot(slotArrayIterationKind).(lang.String).String()
+
+	var length float64
+	if v, ok := typedArrayView(array); ok {
+		if v.block.detached {
+			return nil, errors.NewTypeError("Cannot perform %ArrayIteratorPrototype%.next on a detached ArrayBuffer")
+		}
+		length = float64(v.length)
+	} else {
+		l, err := lengthOfArrayLike(array)
+		if err != nil {
+			return nil, err
+		}
+		length = l
+	}
+	if index >= length {
+		o.SetSlot(slotIteratedObject, lang.Undefined)
+		return lang.CreateIterResultObject(r, lang.Undefined, true), nil
+	}
+	o.SetSlot(slotArrayIteratorNextIndex, lang.NewNumber(index+1))
+
+	key := lang.NewNumber(index)
+	if kind == lang.EnumerateKey {
+		return lang.CreateIterResultObject(r, key, false), nil
+	}
+
+	elementValue, err := lang.Get(array, lang.NewStringKey(strconv.FormatFloat(index, 'f', -1, 64)))
+	if err != nil {
+		return nil, err
+	}
+	if kind == lang.EnumerateValue {
+		return lang.CreateIterResultObject(r, elementValue, false), nil
+	}
+
+	result := lang.CreateArrayFromList(r, []lang.Value{key, elementValue})
+	return lang.CreateIterResultObject(r, result, false), nil
+}
+
+// createArray creates the Array constructor and %ArrayPrototype%, which is
+// an array exotic object itself, as specified in 22.1.
+func (r *Realm) createArray() {
+	arrayProto, _ := lang.ArrayCreate(0, r.intrinsic(IntrinsicNameObjectPrototype))
+	r.Intrinsics.SetField(IntrinsicNameArrayPrototype, arrayProto)
+
+	var array *lang.Object
+	array = r.newConstructor("Array", 1, arrayProto, func(newTarget lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		proto, err := GetPrototypeFromConstructor(activeFunctionOrNewTarget(newTarget, array), IntrinsicNameArrayPrototype)
+		if err != nil {
+			return nil, err
+		}
+		if len(args) != 1 {
+			a, err := lang.ArrayCreate(float64(len(args)), proto)
+			if err != nil {
+				return nil, err
+			}
+			for k, arg := range args {
+				_, _ = lang.CreateDataProperty(a, indexKey(float64(k)), arg)
+			}
+			return a, nil
+		}
+		length, ok := args[0].(lang.Number)
+		if !ok {
+			a, _ := lang.ArrayCreate(0, proto)
+			_, _ = lang.CreateDataProperty(a, key("0"), args[0])
+			return a, nil
+		}
+		intLen, _ := lang.ToUint32(length)
+		if !lang.InternalSameValueZero(intLen, length) {
+			return nil, errors.NewRangeError("Invalid array length")
+		}
+		return lang.ArrayCreate(intLen.Float64(), proto)
+	})
+	r.Intrinsics.SetField(IntrinsicNameArray, array)
+
+	r.defineFunction(array, "from", 1, r.arrayFrom)
+	r.defineFunction(array, "isArray", 1, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		return lang.IsArray(argument(args, 0)), nil
+	})
+	r.defineFunction(array, "of", 0, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		a, err := r.constructArrayLike(this, float64(len(args)), true)
+		if err != nil {
+			return nil, err
+		}
+		for k, arg := range args {
+			if _, err := lang.CreateDataPropertyOrThrow(a, indexKey(float64(k)), arg); err != nil {
+				return nil, err
+			}
+		}
+		if err := set(a, key("length"), number(float64(len(args)))); err != nil {
+			return nil, err
+		}
+		return a, nil
+	})
+	r.defineSpecies(array)
+
+	r.defineArrayPrototype(arrayProto)
+}
+
+// constructArrayLike creates a new object by constructing c with the given
+// length as argument, if c is a constructor, or a new array otherwise. If
+// withLength is false, c is constructed without arguments. It is used by
+// Array.from and Array.of.
+func (r *Realm) constructArrayLike(c lang.Value, length float64, withLength bool) (*lang.Object, errors.Error) {
+	if lang.InternalIsConstructor(c) {
+		if withLength {
+			return lang.Construct(c.(*lang.Object), nil, number(length))
+		}
+		return lang.Construct(c.(*lang.Object), nil)
+	}
+	if !withLength {
+		length = 0
+	}
+	return lang.ArrayCreate(length, r.intrinsic(IntrinsicNameArrayPrototype))
+}
+
+// arrayFrom is Array.from.
+// Array.from is specified in 22.1.2.1.
+func (r *Realm) arrayFrom(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+	items, mapfn, thisArg := argument(args, 0), argument(args, 1), argument(args, 2)
+	mapping := mapfn != lang.Undefined
+	if mapping && !lang.InternalIsCallable(mapfn) {
+		return nil, errors.NewTypeError("Array.from: when provided, the second argument must be a function")
+	}
+	mapValue := func(v lang.Value, k float64) (lang.Value, errors.Error) {
+		if !mapping {
+			return v, nil
+		}
+		return lang.CallValue(mapfn, thisArg, v, number(k))
+	}
+
+	usingIterator, err := lang.GetMethod(r, items, symbolKey(lang.SymbolIterator))
+	if err != nil {
+		return nil, err
+	}
+	if usingIterator != lang.Undefined {
+		a, err := r.constructArrayLike(this, 0, false)
+		if err != nil {
+			return nil, err
+		}
+		k := 0.0
+		err = r.iterate(items, func(next lang.Value) errors.Error {
+			v, err := mapValue(next, k)
+			if err != nil {
+				return err
+			}
+			if _, err := lang.CreateDataPropertyOrThrow(a, indexKey(k), v); err != nil {
+				return err
+			}
+			k++
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := set(a, key("length"), number(k)); err != nil {
+			return nil, err
+		}
+		return a, nil
+	}
+
+	arrayLike, err := r.toObject(items)
+	if err != nil {
+		return nil, err
+	}
+	length, err := lengthOfArrayLike(arrayLike)
+	if err != nil {
+		return nil, err
+	}
+	a, err := r.constructArrayLike(this, length, true)
+	if err != nil {
+		return nil, err
+	}
+	for k := 0.0; k < length; k++ {
+		r.work(1)
+		kValue, err := get(arrayLike, indexKey(k))
+		if err != nil {
+			return nil, err
+		}
+		v, err := mapValue(kValue, k)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := lang.CreateDataPropertyOrThrow(a, indexKey(k), v); err != nil {
+			return nil, err
+		}
+	}
+	if err := set(a, key("length"), number(length)); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// ArraySpeciesCreate creates a new array with the given length, using the
+// constructor of the given original array.
+// ArraySpeciesCreate is specified in 9.4.2.3.
+func (r *Realm) ArraySpeciesCreate(originalArray *lang.Object, length float64) (*lang.Object, errors.Error) {
+	if !lang.InternalIsArray(originalArray) {
+		return lang.ArrayCreate(length, r.intrinsic(IntrinsicNameArrayPrototype))
+	}
+	c, err := get(originalArray, key("constructor"))
+	if err != nil {
+		return nil, err
+	}
+	if cObj, ok := c.(*lang.Object); ok && lang.InternalIsConstructor(cObj) {
+		if realmC := GetFunctionRealm(cObj); realmC != r && realmC != nil && cObj == realmC.intrinsic(IntrinsicNameArray) {
+			c = lang.Undefined
+		}
+	}
+	if cObj, ok := c.(*lang.Object); ok {
+		if c, err = get(cObj, symbolKey(lang.SymbolSpecies)); err != nil {
+			return nil, err
+		}
+		if c == lang.Null {
+			c = lang.Undefined
+		}
+	}
+	if c == lang.Undefined {
+		return lang.ArrayCreate(length, r.intrinsic(IntrinsicNameArrayPrototype))
+	}
+	if !lang.InternalIsConstructor(c) {
+		return nil, errors.NewTypeError("Array species is not a constructor")
+	}
+	return lang.Construct(c.(*lang.Object), nil, number(length))
+}
+
+// arrayCallback converts the this value of an Array.prototype method that
+// takes a callback function to an object, and returns it together with its
+// length and the callback function.
+func (r *Realm) arrayCallback(this lang.Value, args []lang.Value, method string) (*lang.Object, float64, *lang.Object, errors.Error) {
+	o, err := r.toObject(this)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	length, err := lengthOfArrayLike(o)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	callback, err := callable(argument(args, 0), "Array.prototype."+method+": callback")
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	return o, length, callback, nil
+}
+
+// arrayIterate calls fn for every index of the given object from 0 to
+// length, for which the object has a property, with the value of that
+// property. The iteration stops if fn returns true or an error.
+func (r *Realm) arrayIterate(o *lang.Object, length float64, fn func(k float64, v lang.Value) (bool, errors.Error)) errors.Error {
+	for k := 0.0; k < length; k++ {
+		r.work(1)
+		pk := indexKey(k)
+		if !o.HasProperty(pk) {
+			continue
+		}
+		v, err := get(o, pk)
+		if err != nil {
+			return err
+		}
+		stop, err := fn(k, v)
+		if err != nil {
+			return err
+		}
+		if stop {
+			return nil
+		}
+	}
+	return nil
+}
+
+// defineArrayPrototype defines the properties of %ArrayPrototype%, as
+// specified in 22.1.3.
+func (r *Realm) defineArrayPrototype(proto *lang.Object) {
+	r.defineFunction(proto, "concat", 1, r.arrayPrototypeConcat)
+	r.defineFunction(proto, "copyWithin", 2, r.arrayPrototypeCopyWithin)
+	entries := r.defineFunction(proto, "entries", 0, r.arrayIteratorFunction(lang.EnumerateKeyPlusValue))
+	r.Intrinsics.SetField(IntrinsicNameArrayProtoEntries, entries)
+	r.defineFunction(proto, "every", 1, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		o, length, callback, err := r.arrayCallback(this, args, "every")
+		if err != nil {
+			return nil, err
+		}
+		result := lang.True
+		err = r.arrayIterate(o, length, func(k float64, v lang.Value) (bool, errors.Error) {
+			testResult, err := lang.Call(callback, argument(args, 1), v, number(k), o)
+			if err != nil {
+				return false, err
+			}
+			if !lang.ToBoolean(testResult) {
+				result = lang.False
+				return true, nil
+			}
+			return false, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return result, nil
+	})
+	r.defineFunction(proto, "fill", 1, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		o, err := r.toObject(this)
+		if err != nil {
+			return nil, err
+		}
+		length, err := lengthOfArrayLike(o)
+		if err != nil {
+			return nil, err
+		}
+		k, err := relativeIndex(argument(args, 1), length, 0)
+		if err != nil {
+			return nil, err
+		}
+		final, err := relativeIndex(argument(args, 2), length, length)
+		if err != nil {
+			return nil, err
+		}
+		for ; k < final; k++ {
+			r.work(1)
+			if err := set(o, indexKey(k), argument(args, 0)); err != nil {
+				return nil, err
+			}
+		}
+		return o, nil
+	})
+	r.defineFunction(proto, "filter", 1, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		o, length, callback, err := r.arrayCallback(this, args, "filter")
+		if err != nil {
+			return nil, err
+		}
+		a, err := r.ArraySpeciesCreate(o, 0)
+		if err != nil {
+			return nil, err
+		}
+		to := 0.0
+		err = r.arrayIterate(o, length, func(k float64, v lang.Value) (bool, errors.Error) {
+			selected, err := lang.Call(callback, argument(args, 1), v, number(k), o)
+			if err != nil {
+				return false, err
+			}
+			if lang.ToBoolean(selected) {
+				if _, err := lang.CreateDataPropertyOrThrow(a, indexKey(to), v); err != nil {
+					return false, err
+				}
+				to++
+			}
+			return false, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return a, nil
+	})
+	r.defineFunction(proto, "find", 1, r.arrayFind(false, false))
+	r.defineFunction(proto, "findIndex", 1, r.arrayFind(true, false))
+	r.defineFunction(proto, "flat", 0, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		o, err := r.toObject(this)
+		if err != nil {
+			return nil, err
+		}
+		sourceLen, err := lengthOfArrayLike(o)
+		if err != nil {
+			return nil, err
+		}
+		depthNum := 1.0
+		if depth := argument(args, 0); depth != lang.Undefined {
+			if depthNum, err = toInteger(depth); err != nil {
+				return nil, err
+			}
+			if depthNum < 0 {
+				depthNum = 0
+			}
+		}
+		a, err := r.ArraySpeciesCreate(o, 0)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := r.flattenIntoArray(a, o, sourceLen, 0, depthNum, nil, nil); err != nil {
+			return nil, err
+		}
+		return a, nil
+	})
+	r.defineFunction(proto, "flatMap", 1, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		o, sourceLen, mapper, err := r.arrayCallback(this, args, "flatMap")
+		if err != nil {
+			return nil, err
+		}
+		a, err := r.ArraySpeciesCreate(o, 0)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := r.flattenIntoArray(a, o, sourceLen, 0, 1, mapper, argument(args, 1)); err != nil {
+			return nil, err
+		}
+		return a, nil
+	})
+	forEach := r.defineFunction(proto, "forEach", 1, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		o, length, callback, err := r.arrayCallback(this, args, "forEach")
+		if err != nil {
+			return nil, err
+		}
+		err = r.arrayIterate(o, length, func(k float64, v lang.Value) (bool, errors.Error) {
+			_, err := lang.Call(callback, argument(args, 1), v, number(k), o)
+			return false, err
+		})
+		if err != nil {
+			return nil, err
+		}
+		return lang.Undefined, nil
+	})
+	r.Intrinsics.SetField(IntrinsicNameArrayProtoForEach, forEach)
+	r.defineFunction(proto, "includes", 1, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		o, err := r.toObject(this)
+		if err != nil {
+			return nil, err
+		}
+		length, err := lengthOfArrayLike(o)
+		if err != nil {
+			return nil, err
+		}
+		if length == 0 {
+			return lang.False, nil
+		}
+		k, err := relativeIndex(argument(args, 1), length, 0)
+		if err != nil {
+			return nil, err
+		}
+		for ; k < length; k++ {
+			r.work(1)
+			element, err := get(o, indexKey(k))
+			if err != nil {
+				return nil, err
+			}
+			if lang.InternalSameValueZero(argument(args, 0), element) {
+				return lang.True, nil
+			}
+		}
+		return lang.False, nil
+	})
+	r.defineFunction(proto, "indexOf", 1, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		o, err := r.toObject(this)
+		if err != nil {
+			return nil, err
+		}
+		length, err := lengthOfArrayLike(o)
+		if err != nil {
+			return nil, err
+		}
+		if length == 0 {
+			return number(-1), nil
+		}
+		k, err := relativeIndex(argument(args, 1), length, 0)
+		if err != nil {
+			return nil, err
+		}
+		for ; k < length; k++ {
+			r.work(1)
+			pk := indexKey(k)
+			if !o.HasProperty(pk) {
+				continue
+			}
+			element, err := get(o, pk)
+			if err != nil {
+				return nil, err
+			}
+			if lang.StrictEqualityComparison(argument(args, 0), element) {
+				return number(k), nil
+			}
+		}
+		return number(-1), nil
+	})
+	r.defineFunction(proto, "join", 1, r.arrayPrototypeJoin)
+	keys := r.defineFunction(proto, "keys", 0, r.arrayIteratorFunction(lang.EnumerateKey))
+	r.Intrinsics.SetField(IntrinsicNameArrayProtoKeys, keys)
+	r.defineFunction(proto, "lastIndexOf", 1, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		o, err := r.toObject(this)
+		if err != nil {
+			return nil, err
+		}
+		length, err := lengthOfArrayLike(o)
+		if err != nil {
+			return nil, err
+		}
+		if length == 0 {
+			return number(-1), nil
+		}
+		k := length - 1
+		if len(args) > 1 {
+			n, err := toInteger(args[1])
+			if err != nil {
+				return nil, err
+			}
+			if n >= 0 {
+				k = math.Min(n, length-1)
+			} else {
+				k = length + n
+			}
+		}
+		for ; k >= 0; k-- {
+			r.work(1)
+			pk := indexKey(k)
+			if !o.HasProperty(pk) {
+				continue
+			}
+			element, err := get(o, pk)
+			if err != nil {
+				return nil, err
+			}
+			if lang.StrictEqualityComparison(argument(args, 0), element) {
+				return number(k), nil
+			}
+		}
+		return number(-1), nil
+	})
+	r.defineFunction(proto, "map", 1, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		o, length, callback, err := r.arrayCallback(this, args, "map")
+		if err != nil {
+			return nil, err
+		}
+		a, err := r.ArraySpeciesCreate(o, length)
+		if err != nil {
+			return nil, err
+		}
+		err = r.arrayIterate(o, length, func(k float64, v lang.Value) (bool, errors.Error) {
+			mapped, err := lang.Call(callback, argument(args, 1), v, number(k), o)
+			if err != nil {
+				return false, err
+			}
+			_, err = lang.CreateDataPropertyOrThrow(a, indexKey(k), mapped)
+			return false, err
+		})
+		if err != nil {
+			return nil, err
+		}
+		return a, nil
+	})
+	r.defineFunction(proto, "pop", 0, func(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+		o, err := r.toObject(this)
+		if err != nil {
+			return nil, err
+		}
+		length, err := lengthOfArrayLike(o)
+		if err != nil {
+			return nil, err
+		}
+		if length == 0 {
+			if err := set(o, key("length"), lang.Zero); err != nil {
+				return nil, err
+			}
+			return lang.Undefined, nil
+		}
+		index := indexKey(length - 1)
+		element, err := get(o, index)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := lang.DeletePropertyOrThrow(o, index); err != nil {
+			return nil, err
+		}
+		if err := set(o, key("length"), number(length-1)); err != nil {
+			return nil, err
+		}
+		return element, nil
+	})
+	r.defineFunction(proto, "push", 1, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		o, err := r.toObject(this)
+		if err != nil {
+			return nil, err
+		}
+		length, err := lengthOfArrayLike(o)
+		if err != nil {
+			return nil, err
+		}
+		if length+float64(len(args)) > maxSafeInteger {
+			return nil, errors.NewTypeError("Pushing " + strconv.Itoa(len(args)) + " elements on an array-like of length " + lang.NumberToString(number(length)).String() + " is disallowed, as the total surpasses 2**53-1")
+		}
+		for _, arg := range args {
+			if err := set(o, indexKey(length), arg); err != nil {
+				return nil, err
+			}
+			length++
+		}
+		if err := set(o, key("length"), number(length)); err != nil {
+			return nil, err
+		}
+		return number(length), nil
+	})
+	r.defineFunction(proto, "reduce", 1, r.arrayReduce(false))
+	r.defineFunction(proto, "reduceRight", 1, r.arrayReduce(true))
+	r.defineFunction(proto, "reverse", 0, func(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+		o, err := r.toObject(this)
+		if err != nil {
+			return nil, err
+		}
+		length, err := lengthOfArrayLike(o)
+		if err != nil {
+			return nil, err
+		}
+		middle := math.Floor(length / 2)
+		for lower := 0.0; lower != middle; lower++ {
+			r.work(1)
+			upper := length - lower - 1
+			if err := swapProperties(o, indexKey(lower), indexKey(upper)); err != nil {
+				return nil, err
+			}
+		}
+		return o, nil
+	})
+	r.defineFunction(proto, "shift", 0, func(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+		o, err := r.toObject(this)
+		if err != nil {
+			return nil, err
+		}
+		length, err := lengthOfArrayLike(o)
+		if err != nil {
+			return nil, err
+		}
+		if length == 0 {
+			if err := set(o, key("length"), lang.Zero); err != nil {
+				return nil, err
+			}
+			return lang.Undefined, nil
+		}
+		first, err := get(o, key("0"))
+		if err != nil {
+			return nil, err
+		}
+		if err := r.moveElements(o, 1, 0, length-1); err != nil {
+			return nil, err
+		}
+		if _, err := lang.DeletePropertyOrThrow(o, indexKey(length-1)); err != nil {
+			return nil, err
+		}
+		if err := set(o, key("length"), number(length-1)); err != nil {
+			return nil, err
+		}
+		return first, nil
+	})
+	r.defineFunction(proto, "slice", 2, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		o, err := r.toObject(this)
+		if err != nil {
+			return nil, err
+		}
+		length, err := lengthOfArrayLike(o)
+		if err != nil {
+			return nil, err
+		}
+		k, err := relativeIndex(argument(args, 0), length, 0)
+		if err != nil {
+			return nil, err
+		}
+		final, err := relativeIndex(argument(args, 1), length, length)
+		if err != nil {
+			return nil, err
+		}
+		count := math.Max(final-k, 0)
+		a, err := r.ArraySpeciesCreate(o, count)
+		if err != nil {
+			return nil, err
+		}
+		n := 0.0
+		for ; k < final; k, n = k+1, n+1 {
+			r.work(1)
+			pk := indexKey(k)
+			if !o.HasProperty(pk) {
+				continue
+			}
+			v, err := get(o, pk)
+			if err != nil {
+				return nil, err
+			}
+			if _, err := lang.CreateDataPropertyOrThrow(a, indexKey(n), v); err != nil {
+				return nil, err
+			}
+		}
+		if err := set(a, key("length"), number(n)); err != nil {
+			return nil, err
+		}
+		return a, nil
+	})
+	r.defineFunction(proto, "some", 1, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		o, length, callback, err := r.arrayCallback(this, args, "some")
+		if err != nil {
+			return nil, err
+		}
+		result := lang.False
+		err = r.arrayIterate(o, length, func(k float64, v lang.Value) (bool, errors.Error) {
+			testResult, err := lang.Call(callback, argument(args, 1), v, number(k), o)
+			if err != nil {
+				return false, err
+			}
+			if lang.ToBoolean(testResult) {
+				result = lang.True
+				return true, nil
+			}
+			return false, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return result, nil
+	})
+	r.defineFunction(proto, "sort", 1, r.arrayPrototypeSort)
+	r.defineFunction(proto, "splice", 2, r.arrayPrototypeSplice)
+	r.defineFunction(proto, "toLocaleString", 0, func(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+		o, err := r.toObject(this)
+		if err != nil {
+			return nil, err
+		}
+		length, err := lengthOfArrayLike(o)
+		if err != nil {
+			return nil, err
+		}
+		var result lang.String
+		for k := 0.0; k < length; k++ {
+			r.work(1)
+			if k > 0 {
+				result = append(result, ',')
+			}
+			element, err := get(o, indexKey(k))
+			if err != nil {
+				return nil, err
+			}
+			if element == lang.Undefined || element == lang.Null {
+				continue
+			}
+			s, err := lang.Invoke(r, element, key("toLocaleString"))
+			if err != nil {
+				return nil, err
+			}
+			next, err := lang.ToString(s)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, next...)
+		}
+		return append(lang.String{}, result...), nil
+	})
+	r.defineFunction(proto, "toString", 0, func(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+		o, err := r.toObject(this)
+		if err != nil {
+			return nil, err
+		}
+		join, err := get(o, key("join"))
+		if err != nil {
+			return nil, err
+		}
+		if !lang.InternalIsCallable(join) {
+			return r.objectPrototypeToString(o)
+		}
+		return lang.CallValue(join, o)
+	})
+	r.defineFunction(proto, "unshift", 1, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		o, err := r.toObject(this)
+		if err != nil {
+			return nil, err
+		}
+		length, err := lengthOfArrayLike(o)
+		if err != nil {
+			return nil, err
+		}
+		argCount := float64(len(args))
+		if argCount > 0 {
+			if length+argCount > maxSafeInteger {
+				return nil, errors.NewTypeError("Unshift would exceed the maximum array-like length")
+			}
+			for k := length; k > 0; k-- {
+				r.work(1)
+				if err := moveElement(o, indexKey(k-1), indexKey(k+argCount-1)); err != nil {
+					return nil, err
+				}
+			}
+			for j, arg := range args {
+				if err := set(o, indexKey(float64(j)), arg); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if err := set(o, key("length"), number(length+argCount)); err != nil {
+			return nil, err
+		}
+		return number(length + argCount), nil
+	})
+	values := r.defineFunction(proto, "values", 0, r.arrayIteratorFunction(lang.EnumerateValue))
+	_, _ = lang.CreateMethodProperty(proto, symbolKey(lang.SymbolIterator), values)
+	r.Intrinsics.SetField(IntrinsicNameArrayProtoValues, values)
+
+	unscopables := lang.ObjectCreate(lang.Null)
+	unscopables.SetHeap(r.heap)
+	for _, name := range []string{"copyWithin", "entries", "fill", "find", "findIndex", "flat", "flatMap", "includes", "keys", "values"} {
+		_, _ = lang.CreateDataProperty(unscopables, key(name), lang.True)
+	}
+	_, _ = lang.DefinePropertyOrThrow(proto, symbolKey(lang.SymbolUnscopables), lang.NewDataProperty(unscopables, lang.False, lang.False, lang.True))
+}
+
+// arrayIteratorFunction returns the implementation of Array.prototype.keys,
+// values or entries, depending on the given kind.
+func (r *Realm) arrayIteratorFunction(kind string) BuiltinFunction {
+	return func(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+		o, err := r.toObject(this)
+		if err != nil {
+			return nil, err
+		}
+		return r.CreateArrayIterator(o, kind), nil
+	}
+}
+
+// arrayFind returns the implementation of Array.prototype.find or findIndex,
+// depending on whether the index is returned. If fromEnd is true, the
+// elements are visited from the last to the first.
+// These functions are specified in 22.1.3.8 and 22.1.3.9.
+func (r *Realm) arrayFind(index, fromEnd bool) BuiltinFunction {
+	return func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		method := "find"
+		if index {
+			method = "findIndex"
+		}
+		o, length, predicate, err := r.arrayCallback(this, args, method)
+		if err != nil {
+			return nil, err
+		}
+		for i := 0.0; i < length; i++ {
+			r.work(1)
+			k := i
+			if fromEnd {
+				k = length - 1 - i
+			}
+			kValue, err := get(o, indexKey(k))
+			if err != nil {
+				return nil, err
+			}
+			testResult, err := lang.Call(predicate, argument(args, 1), kValue, number(k), o)
+			if err != nil {
+				return nil, err
+			}
+			if lang.ToBoolean(testResult) {
+				if index {
+					return number(k), nil
+				}
+				return kValue, nil
+			}
+		}
+		if index {
+			return number(-1), nil
+		}
+		return lang.Undefined, nil
+	}
+}
+
+// arrayReduce returns the implementation of Array.prototype.reduce or
+// reduceRight, depending on the direction.
+// These functions are specified in 22.1.3.21 and 22.1.3.22.
+func (r *Realm) arrayReduce(right bool) BuiltinFunction {
+	return func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		method := "reduce"
+		if right {
+			method = "reduceRight"
+		}
+		o, length, callback, err := r.arrayCallback(this, args, method)
+		if err != nil {
+			return nil, err
+		}
+		index := func(i float64) float64 {
+			if right {
+				return length - 1 - i
+			}
+			return i
+		}
+
+		i := 0.0
+		var accumulator lang.Value
+		if len(args) >= 2 {
+			accumulator = args[1]
+		} else {
+			for ; accumulator == nil && i < length; i++ {
+				r.work(1)
+				pk := indexKey(index(i))
+				if !o.HasProperty(pk) {
+					continue
+				}
+				if accumulator, err = get(o, pk); err != nil {
+					return nil, err
+				}
+			}
+			if accumulator == nil {
+				return nil, errors.NewTypeError("Reduce of empty array with no initial value")
+			}
+		}
+		for ; i < length; i++ {
+			r.work(1)
+			k := index(i)
+			pk := indexKey(k)
+			if !o.HasProperty(pk) {
+				continue
+			}
+			kValue, err := get(o, pk)
+			if err != nil {
+				return nil, err
+			}
+			if accumulator, err = lang.Call(callback, lang.Undefined, accumulator, kValue, number(k), o); err != nil {
+				return nil, err
+			}
+		}
+		return accumulator, nil
+	}
+}
+
+// arrayPrototypeConcat is Array.prototype.concat.
+// Array.prototype.concat is specified in 22.1.3.1.
+func (r *Realm) arrayPrototypeConcat(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+	o, err := r.toObject(this)
+	if err != nil {
+		return nil, err
+	}
+	a, err := r.ArraySpeciesCreate(o, 0)
+	if err != nil {
+		return nil, err
+	}
+	n := 0.0
+	for _, item := range append([]lang.Value{o}, args...) {
+		spreadable, err := isConcatSpreadable(item)
+		if err != nil {
+			return nil, err
+		}
+		if !spreadable {
+			if n >= maxSafeInteger {
+				return nil, errors.NewTypeError("Array length exceeds the maximum safe integer")
+			}
+			if _, err := lang.CreateDataPropertyOrThrow(a, indexKey(n), item); err != nil {
+				return nil, err
+			}
+			n++
+			continue
+		}
+		e := item.(*lang.Object)
+		length, err := lengthOfArrayLike(e)
+		if err != nil {
+			return nil, err
+		}
+		if n+length > maxSafeInteger {
+			return nil, errors.NewTypeError("Array length exceeds the maximum safe integer")
+		}
+		for k := 0.0; k < length; k, n = k+1, n+1 {
+			r.work(1)
+			pk := indexKey(k)
+			if !e.HasProperty(pk) {
+				continue
+			}
+			sub, err := get(e, pk)
+			if err != nil {
+				return nil, err
+			}
+			if _, err := lang.CreateDataPropertyOrThrow(a, indexKey(n), sub); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := set(a, key("length"), number(n)); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// isConcatSpreadable is used to determine whether the given value is
+// spread by Array.prototype.concat.
+// IsConcatSpreadable is specified in 22.1.3.1.1.
+func isConcatSpreadable(v lang.Value) (bool, errors.Error) {
+	o, ok := v.(*lang.Object)
+	if !ok {
+		return false, nil
+	}
+	spreadable, err := get(o, symbolKey(lang.SymbolIsConcatSpreadable))
+	if err != nil {
+		return false, err
+	}
+	if spreadable != lang.Undefined {
+		return bool(lang.ToBoolean(spreadable)), nil
+	}
+	return lang.InternalIsArray(o), nil
+}
+
+// arrayPrototypeCopyWithin is Array.prototype.copyWithin.
+// Array.prototype.copyWithin is specified in 22.1.3.3.
+func (r *Realm) arrayPrototypeCopyWithin(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+	o, err := r.toObject(this)
+	if err != nil {
+		return nil, err
+	}
+	length, err := lengthOfArrayLike(o)
+	if err != nil {
+		return nil, err
+	}
+	to, err := relativeIndex(argument(args, 0), length, 0)
+	if err != nil {
+		return nil, err
+	}
+	from, err := relativeIndex(argument(args, 1), length, 0)
+	if err != nil {
+		return nil, err
+	}
+	final, err := relativeIndex(argument(args, 2), length, length)
+	if err != nil {
+		return nil, err
+	}
+	count := math.Min(final-from, length-to)
+	if count <= 0 {
+		return o, nil
+	}
+	if err := r.moveElements(o, from, to, count); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// moveElement sets the property to of the given object to the value of the
+// property from, or deletes it if there is no such property.
+func moveElement(o *lang.Object, from, to lang.StringOrSymbol) errors.Error {
+	if !o.HasProperty(from) {
+		_, err := lang.DeletePropertyOrThrow(o, to)
+		return err
+	}
+	v, err := get(o, from)
+	if err != nil {
+		return err
+	}
+	return set(o, to, v)
+}
+
+// moveElements moves count elements of the given object from the index
+// from to the index to. The ranges may overlap.
+func (r *Realm) moveElements(o *lang.Object, from, to, count float64) errors.Error {
+	direction := 1.0
+	if from < to && to < from+count {
+		direction = -1
+		from += count - 1
+		to += count - 1
+	}
+	for ; count > 0; count-- {
+		r.work(1)
+		if err := moveElement(o, indexKey(from), indexKey(to)); err != nil {
+			return err
+		}
+		from += direction
+		to += direction
+	}
+	return nil
+}
+
+// swapProperties swaps the values of the two properties of the given
+// object, as done by Array.prototype.reverse.
+func swapProperties(o *lang.Object, lower, upper lang.StringOrSymbol) errors.Error {
+	lowerExists, upperExists := o.HasProperty(lower), o.HasProperty(upper)
+	var lowerValue, upperValue lang.Value
+	var err errors.Error
+	if lowerExists {
+		if lowerValue, err = get(o, lower); err != nil {
+			return err
+		}
+	}
+	if upperExists {
+		if upperValue, err = get(o, upper); err != nil {
+			return err
+		}
+	}
+	if upperExists {
+		if err := set(o, lower, upperValue); err != nil {
+			return err
+		}
+	} else if lowerExists {
+		if _, err := lang.DeletePropertyOrThrow(o, lower); err != nil {
+			return err
+		}
+	}
+	if lowerExists {
+		return set(o, upper, lowerValue)
+	} else if upperExists {
+		_, err := lang.DeletePropertyOrThrow(o, upper)
+		return err
+	}
+	return nil
+}
+
+// flattenIntoArray appends the elements of the given source to the target,
+// starting at the given index, and flattens arrays up to the given depth.
+// If a mapper function is given, every element of the source is mapped
+// before it is flattened. The next index of the target is returned.
+// FlattenIntoArray is specified in 22.1.3.10.1.
+func (r *Realm) flattenIntoArray(target, source *lang.Object, sourceLen, start, depth float64, mapper *lang.Object, thisArg lang.Value) (float64, errors.Error) {
+	if err := r.enter(); err != nil {
+		return 0, err
+	}
+	defer r.leave()
+	targetIndex := start
+	for sourceIndex := 0.0; sourceIndex < sourceLen; sourceIndex++ {
+		r.work(1)
+		p := indexKey(sourceIndex)
+		if !source.HasProperty(p) {
+			continue
+		}
+		element, err := get(source, p)
+		if err != nil {
+			return 0, err
+		}
+		if mapper != nil {
+			if element, err = lang.Call(mapper, thisArg, element, number(sourceIndex), source); err != nil {
+				return 0, err
+			}
+		}
+		if depth > 0 && lang.InternalIsArray(element) {
+			elementObj := element.(*lang.Object)
+			elementLen, err := lengthOfArrayLike(elementObj)
+			if err != nil {
+				return 0, err
+			}
+			if targetIndex, err = r.flattenIntoArray(target, elementObj, elementLen, targetIndex, depth-1, nil, nil); err != nil {
+				return 0, err
+			}
+			continue
+		}
+		if targetIndex >= maxSafeInteger {
+			return 0, errors.NewTypeError("Array length exceeds the maximum safe integer")
+		}
+		if _, err := lang.CreateDataPropertyOrThrow(target, indexKey(targetIndex), element); err != nil {
+			return 0, err
+		}
+		targetIndex++
+	}
+	return targetIndex, nil
+}
+
+// arrayPrototypeJoin is Array.prototype.join.
+// Array.prototype.join is specified in 22.1.3.13.
+func (r *Realm) arrayPrototypeJoin(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+	o, err := r.toObject(this)
+	if err != nil {
+		return nil, err
+	}
+	length, err := lengthOfArrayLike(o)
+	if err != nil {
+		return nil, err
+	}
+	sep := lang.NewString(",")
+	if separator := argument(args, 0); separator != lang.Undefined {
+		if sep, err = lang.ToString(separator); err != nil {
+			return nil, err
+		}
+	}
+
+	// a cyclic array is joined as the empty string, like other engines do
+	if r.joining[o] {
+		return lang.String{}, nil
+	}
+	if r.joining == nil {
+		r.joining = make(map[*lang.Object]bool)
+	}
+	r.joining[o] = true
+	defer delete(r.joining, o)
+
+	result := lang.String{}
+	for k := 0.0; k < length; k++ {
+		r.work(1)
+		if k > 0 {
+			result = append(result, sep...)
+		}
+		element, err := get(o, indexKey(k))
+		if err != nil {
+			return nil, err
+		}
+		if element == lang.Undefined || element == lang.Null {
+			continue
+		}
+		next, err := lang.ToString(element)
+		if err != nil {
+			return nil, err
+		}
+		if err := r.CheckStringLength(float64(len(result) + len(next))); err != nil {
+			return nil, err
+		}
+		result = append(result, next...)
+	}
+	return result, nil
+}
+
+// arrayPrototypeSort is Array.prototype.sort.
+// Array.prototype.sort is specified in 22.1.3.27.
+func (r *Realm) arrayPrototypeSort(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+	comparefn := argument(args, 0)
+	if comparefn != lang.Undefined && !lang.InternalIsCallable(comparefn) {
+		return nil, errors.NewTypeError("The comparison function must be either a function or undefined")
+	}
+	o, err := r.toObject(this)
+	if err != nil {
+		return nil, err
+	}
+	length, err := lengthOfArrayLike(o)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []lang.Value
+	err = r.arrayIterate(o, length, func(_ float64, v lang.Value) (bool, errors.Error) {
+		items = append(items, v)
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := sortValues(items, func(x, y lang.Value) (float64, errors.Error) {
+		r.work(1)
+		return sortCompare(x, y, comparefn)
+	}); err != nil {
+		return nil, err
+	}
+
+	i := 0.0
+	for _, item := range items {
+		if err := set(o, indexKey(i), item); err != nil {
+			return nil, err
+		}
+		i++
+	}
+	for ; i < length; i++ {
+		r.work(1)
+		if _, err := lang.DeletePropertyOrThrow(o, indexKey(i)); err != nil {
+			return nil, err
+		}
+	}
+	return o, nil
+}
+
+// sortCompare compares the given values, as done by Array.prototype.sort.
+// Undefined is greater than all other values.
+// SortCompare is specified in 22.1.3.27.1.
+func sortCompare(x, y, comparefn lang.Value) (float64, errors.Error) {
+	switch {
+	case x == lang.Undefined && y == lang.Undefined:
+		return 0, nil
+	case x == lang.Undefined:
+		return 1, nil
+	case y == lang.Undefined:
+		return -1, nil
+	}
+	if comparefn != lang.Undefined {
+		v, err := lang.CallValue(comparefn, lang.Undefined, x, y)
+		if err != nil {
+			return 0, err
+		}
+		n, err := toNumber(v)
+		if err != nil {
+			return 0, err
+		}
+		if math.IsNaN(n) {
+			return 0, nil
+		}
+		return n, nil
+	}
+	xString, err := lang.ToString(x)
+	if err != nil {
+		return 0, err
+	}
+	yString, err := lang.ToString(y)
+	if err != nil {
+		return 0, err
+	}
+	return float64(compareStrings(xString, yString)), nil
+}
+
+// compareStrings compares the code units of the given strings, and returns
+// -1 if x is less than y, 1 if x is greater than y, and 0 otherwise.
+func compareStrings(x, y lang.String) int {
+	for i := 0; i < len(x) && i < len(y); i++ {
+		if x[i] != y[i] {
+			if x[i] < y[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case len(x) < len(y):
+		return -1
+	case len(x) > len(y):
+		return 1
+	}
+	return 0
+}
+
+// sortValues sorts the given values stably with a merge sort, using the
+// given comparison function. If the comparison function returns an error,
+// sorting stops and the error is returned.
+func sortValues(values []lang.Value, compare func(x, y lang.Value) (float64, errors.Error)) errors.Error {
+	if len(values) < 2 {
+		return nil
+	}
+	middle := len(values) / 2
+	left := append([]lang.Value{}, values[:middle]...)
+	right := append([]lang.Value{}, values[middle:]...)
+	if err := sortValues(left, compare); err != nil {
+		return err
+	}
+	if err := sortValues(right, compare); err != nil {
+		return err
+	}
+	i, j := 0, 0
+	for k := range values {
+		if i < len(left) && j < len(right) {
+			c, err := compare(left[i], right[j])
+			if err != nil {
+				return err
+			}
+			if c > 0 {
+				values[k] = right[j]
+				j++
+			} else {
+				values[k] = left[i]
+				i++
+			}
+		} else if i < len(left) {
+			values[k] = left[i]
+			i++
+		} else {
+			values[k] = right[j]
+			j++
+		}
+	}
+	return nil
+}
+
+// arrayPrototypeSplice is Array.prototype.splice.
+// Array.prototype.splice is specified in 22.1.3.28.
+func (r *Realm) arrayPrototypeSplice(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+	o, err := r.toObject(this)
+	if err != nil {
+		return nil, err
+	}
+	length, err := lengthOfArrayLike(o)
+	if err != nil {
+		return nil, err
+	}
+	actualStart, err := relativeIndex(argument(args, 0), length, 0)
+	if err != nil {
+		return nil, err
+	}
+	var items []lang.Value
+	var actualDeleteCount float64
+	switch len(args) {
+	case 0:
+	case 1:
+		actualDeleteCount = length - actualStart
+	default:
+		items = args[2:]
+		dc, err := toInteger(args[1])
+		if err != nil {
+			return nil, err
+		}
+		actualDeleteCount = math.Min(math.Max(dc, 0), length-actualStart)
+	}
+	itemCount := float64(len(items))
+	if length+itemCount-actualDeleteCount > maxSafeInteger {
+		return nil, errors.NewTypeError("Array length exceeds the maximum safe integer")
+	}
+
+	a, err := r.ArraySpeciesCreate(o, actualDeleteCount)
+	if err != nil {
+		return nil, err
+	}
+	for k := 0.0; k < actualDeleteCount; k++ {
+		r.work(1)
+		from := indexKey(actualStart + k)
+		if !o.HasProperty(from) {
+			continue
+		}
+		fromValue, err := get(o, from)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := lang.CreateDataPropertyOrThrow(a, indexKey(k), fromValue); err != nil {
+			return nil, err
+		}
+	}
+	if err := set(a, key("length"), number(actualDeleteCount)); err != nil {
+		return nil, err
+	}
+
+	if itemCount < actualDeleteCount {
+		for k := actualStart; k < length-actualDeleteCount; k++ {
+			r.work(1)
+			if err := moveElement(o, indexKey(k+actualDeleteCount), indexKey(k+itemCount)); err != nil {
+				return nil, err
+			}
+		}
+		for k := length; k > length-actualDeleteCount+itemCount; k-- {
+			r.work(1)
+			if _, err := lang.DeletePropertyOrThrow(o, indexKey(k-1)); err != nil {
+				return nil, err
+			}
+		}
+	} else if itemCount > actualDeleteCount {
+		for k := length - actualDeleteCount; k > actualStart; k-- {
+			r.work(1)
+			if err := moveElement(o, indexKey(k+actualDeleteCount-1), indexKey(k+itemCount-1)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for k, item := range items {
+		if err := set(o, indexKey(actualStart+float64(k)), item); err != nil {
+			return nil, err
+		}
+	}
+	if err := set(o, key("length"), number(length-actualDeleteCount+itemCount)); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
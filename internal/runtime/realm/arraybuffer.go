@@ -0,0 +1,245 @@
+package realm
+
+import (
+	"math"
+
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+)
+
+// slotArrayBufferData is the internal slot of ArrayBuffer and
+// SharedArrayBuffer objects, that holds their Data Block, as specified in
+// 24.1.1.1 and 24.2.1.1.
+var slotArrayBufferData = lang.NewStringKey("ArrayBufferData")
+
+// maxByteLength is the maximum length of a Data Block that can be
+// allocated. Allocating a larger block fails with a RangeError.
+const maxByteLength = math.MaxInt32
+
+// dataBlock is the Data Block or Shared Data Block of an ArrayBuffer or
+// SharedArrayBuffer object, as specified in 6.2.7.
+type dataBlock struct {
+	bytes    []byte
+	shared   bool
+	detached bool
+}
+
+// Type returns lang.TypeInternal.
+func (*dataBlock) Type() lang.Type { return lang.TypeInternal }
+
+// Value returns the data block itself.
+func (b *dataBlock) Value() interface{} { return b }
+
+// arrayBufferData returns the data block of the given value, if it is an
+// ArrayBuffer or SharedArrayBuffer object.
+func arrayBufferData(v lang.Value) (*dataBlock, bool) {
+	if o, ok := v.(*lang.Object); ok && o.HasSlot(slotArrayBufferData) {
+		return o.GetSlot(slotArrayBufferData).(*dataBlock), true
+	}
+	return nil, false
+}
+
+// thisBufferData returns the data block of the this value of a method of
+// ArrayBuffer.prototype or SharedArrayBuffer.prototype, depending on
+// whether shared is true.
+func thisBufferData(this lang.Value, shared bool, method string) (*dataBlock, errors.Error) {
+	block, ok := arrayBufferData(this)
+	if !ok || block.shared != shared {
+		return nil, errors.NewTypeError("Method " + method + " called on incompatible receiver")
+	}
+	return block, nil
+}
+
+// AllocateArrayBuffer creates an ArrayBuffer object, whose prototype is
+// taken from the given constructor, with a zeroed Data Block of the given
+// length.
+// AllocateArrayBuffer is specified in 24.1.1.1.
+func AllocateArrayBuffer(constructor *lang.Object, byteLength float64) (*lang.Object, errors.Error) {
+	return allocateBuffer(constructor, IntrinsicNameArrayBufferPrototype, byteLength, false)
+}
+
+// AllocateSharedArrayBuffer creates a SharedArrayBuffer object, whose
+// prototype is taken from the given constructor, with a zeroed Shared Data
+// Block of the given length.
+// AllocateSharedArrayBuffer is specified in 24.2.1.1.
+func AllocateSharedArrayBuffer(constructor *lang.Object, byteLength float64) (*lang.Object, errors.Error) {
+	return allocateBuffer(constructor, IntrinsicNameSharedArrayBufferPrototype, byteLength, true)
+}
+
+func allocateBuffer(constructor *lang.Object, defaultProto string, byteLength float64, shared bool) (*lang.Object, errors.Error) {
+	buffer, err := OrdinaryCreateFromConstructor(constructor, defaultProto, slotArrayBufferData)
+	if err != nil {
+		return nil, err
+	}
+	if byteLength > maxByteLength {
+		return nil, errors.NewRangeError("Array buffer allocation failed")
+	}
+	if err := buffer.Heap().Allocate(int64(byteLength)); err != nil {
+		return nil, err
+	}
+	buffer.SetSlot(slotArrayBufferData, &dataBlock{
+		bytes:  make([]byte, int(byteLength)),
+		shared: shared,
+	})
+	return buffer, nil
+}
+
+// IsDetachedBuffer determines whether the Data Block of the given
+// ArrayBuffer object has been detached.
+// IsDetachedBuffer is specified in 24.1.1.2.
+func IsDetachedBuffer(buffer *lang.Object) bool {
+	block, ok := arrayBufferData(buffer)
+	return ok && block.detached
+}
+
+// DetachArrayBuffer detaches the Data Block of the given ArrayBuffer
+// object, after which its length is 0 and all views on it throw a
+// TypeError when they are accessed. It can be used by hosts that transfer
+// the contents of an ArrayBuffer.
+// DetachArrayBuffer is specified in 24.1.1.3.
+func DetachArrayBuffer(buffer *lang.Object) errors.Error {
+	block, ok := arrayBufferData(buffer)
+	if !ok || block.shared {
+		return errors.NewTypeError("Only ArrayBuffer objects can be detached")
+	}
+	block.bytes = nil
+	block.detached = true
+	return nil
+}
+
+// toIndex converts the given value to an integer index, which is a
+// RangeError if it is negative or not a safe integer.
+// ToIndex is specified in 7.1.17.
+func toIndex(v lang.Value) (float64, errors.Error) {
+	n, err := lang.ToIndex(v)
+	if err != nil {
+		return 0, err
+	}
+	return n.Float64(), nil
+}
+
+// createArrayBuffer creates the ArrayBuffer constructor and
+// %ArrayBufferPrototype%, as specified in 24.1.
+func (r *Realm) createArrayBuffer() {
+	proto := lang.ObjectCreate(r.intrinsic(IntrinsicNameObjectPrototype))
+	r.Intrinsics.SetField(IntrinsicNameArrayBufferPrototype, proto)
+
+	arrayBuffer := r.newConstructor("ArrayBuffer", 1, proto, func(newTarget lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		nt, ok := newTarget.(*lang.Object)
+		if !ok {
+			return nil, errors.NewTypeError("Constructor ArrayBuffer requires 'new'")
+		}
+		byteLength, err := toIndex(argument(args, 0))
+		if err != nil {
+			return nil, err
+		}
+		return AllocateArrayBuffer(nt, byteLength)
+	})
+	r.Intrinsics.SetField(IntrinsicNameArrayBuffer, arrayBuffer)
+
+	r.defineFunction(arrayBuffer, "isView", 1, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		o, ok := argument(args, 0).(*lang.Object)
+		return lang.Boolean(ok && o.HasSlot(slotViewedArrayBuffer)), nil
+	})
+	r.defineSpecies(arrayBuffer)
+
+	r.defineGetter(proto, key("byteLength"), func(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+		block, err := thisBufferData(this, false, "ArrayBuffer.prototype.byteLength")
+		if err != nil {
+			return nil, err
+		}
+		return number(float64(len(block.bytes))), nil
+	})
+	r.defineFunction(proto, "slice", 2, r.arrayBufferSlice(false))
+	defineToStringTag(proto, "ArrayBuffer")
+}
+
+// createSharedArrayBuffer creates the SharedArrayBuffer constructor and
+// %SharedArrayBufferPrototype%, as specified in 24.2.
+func (r *Realm) createSharedArrayBuffer() {
+	proto := lang.ObjectCreate(r.intrinsic(IntrinsicNameObjectPrototype))
+	r.Intrinsics.SetField(IntrinsicNameSharedArrayBufferPrototype, proto)
+
+	sharedArrayBuffer := r.newConstructor("SharedArrayBuffer", 1, proto, func(newTarget lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		nt, ok := newTarget.(*lang.Object)
+		if !ok {
+			return nil, errors.NewTypeError("Constructor SharedArrayBuffer requires 'new'")
+		}
+		byteLength, err := toIndex(argument(args, 0))
+		if err != nil {
+			return nil, err
+		}
+		return AllocateSharedArrayBuffer(nt, byteLength)
+	})
+	r.Intrinsics.SetField(IntrinsicNameSharedArrayBuffer, sharedArrayBuffer)
+	r.defineSpecies(sharedArrayBuffer)
+
+	r.defineGetter(proto, key("byteLength"), func(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+		block, err := thisBufferData(this, true, "SharedArrayBuffer.prototype.byteLength")
+		if err != nil {
+			return nil, err
+		}
+		return number(float64(len(block.bytes))), nil
+	})
+	r.defineFunction(proto, "slice", 2, r.arrayBufferSlice(true))
+	defineToStringTag(proto, "SharedArrayBuffer")
+}
+
+// arrayBufferSlice returns the implementation of ArrayBuffer.prototype.slice
+// or, if shared is true, SharedArrayBuffer.prototype.slice. Both create a
+// new buffer with the species constructor, and copy the bytes of the given
+// range into it.
+// ArrayBuffer.prototype.slice is specified in 24.1.4.3 and
+// SharedArrayBuffer.prototype.slice in 24.2.4.3.
+func (r *Realm) arrayBufferSlice(shared bool) BuiltinFunction {
+	name, defaultConstructor := "ArrayBuffer", IntrinsicNameArrayBuffer
+	if shared {
+		name, defaultConstructor = "SharedArrayBuffer", IntrinsicNameSharedArrayBuffer
+	}
+	return func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		block, err := thisBufferData(this, shared, name+".prototype.slice")
+		if err != nil {
+			return nil, err
+		}
+		if block.detached {
+			return nil, errors.NewTypeError("Cannot perform " + name + ".prototype.slice on a detached ArrayBuffer")
+		}
+		length := float64(len(block.bytes))
+		first, err := relativeIndex(argument(args, 0), length, 0)
+		if err != nil {
+			return nil, err
+		}
+		final, err := relativeIndex(argument(args, 1), length, length)
+		if err != nil {
+			return nil, err
+		}
+		newLen := math.Max(final-first, 0)
+
+		ctor, err := lang.SpeciesConstructor(this.(*lang.Object), r.intrinsic(defaultConstructor))
+		if err != nil {
+			return nil, err
+		}
+		result, err := lang.Construct(ctor, ctor, number(newLen))
+		if err != nil {
+			return nil, err
+		}
+		newBlock, ok := arrayBufferData(result)
+		if !ok || newBlock.shared != shared {
+			return nil, errors.NewTypeError("Species constructor did not return a " + name)
+		}
+		if newBlock.detached {
+			return nil, errors.NewTypeError("Species constructor returned a detached ArrayBuffer")
+		}
+		if newBlock == block {
+			return nil, errors.NewTypeError("Species constructor returned the same " + name)
+		}
+		if float64(len(newBlock.bytes)) < newLen {
+			return nil, errors.NewTypeError("Species constructor returned a too small " + name)
+		}
+		if block.detached {
+			return nil, errors.NewTypeError("Cannot perform " + name + ".prototype.slice on a detached ArrayBuffer")
+		}
+		copy(newBlock.bytes, block.bytes[int(first):int(first+newLen)])
+		return result, nil
+	}
+}
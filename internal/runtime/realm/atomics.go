@@ -0,0 +1,179 @@
+package realm
+
+import (
+	"encoding/binary"
+
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+)
+
+// createAtomics creates the Atomics object, as specified in 24.4. Agents
+// of this implementation cannot suspend, and run their jobs on a single
+// goroutine, so the operations do not need to be synchronized, and
+// Atomics.wait always throws a TypeError.
+func (r *Realm) createAtomics() {
+	atomics := lang.ObjectCreate(r.intrinsic(IntrinsicNameObjectPrototype))
+	r.Intrinsics.SetField(IntrinsicNameAtomics, atomics)
+
+	r.defineFunction(atomics, "add", 3, r.atomicReadModifyWrite(func(x, y []byte, kind *elementType) {
+		sum := kind.decode(x, binary.LittleEndian) + kind.decode(y, binary.LittleEndian)
+		kind.encode(x, binary.LittleEndian, number(sum))
+	}))
+	r.defineFunction(atomics, "and", 3, r.atomicReadModifyWrite(bytewise(func(x, y byte) byte { return x & y })))
+	r.defineFunction(atomics, "compareExchange", 4, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		v, index, err := r.validateAtomicAccess(argument(args, 0), argument(args, 1), false)
+		if err != nil {
+			return nil, err
+		}
+		expected, err := lang.ToInteger(argument(args, 2))
+		if err != nil {
+			return nil, err
+		}
+		replacement, err := lang.ToInteger(argument(args, 3))
+		if err != nil {
+			return nil, err
+		}
+		if v.block.detached {
+			return nil, errors.NewTypeError("Cannot perform Atomics.compareExchange on a detached ArrayBuffer")
+		}
+		expectedBytes := make([]byte, v.kind.size)
+		v.kind.encode(expectedBytes, binary.LittleEndian, expected)
+		old := v.element(index)
+		offset := v.byteOffset + index*v.kind.size
+		if string(v.block.bytes[offset:offset+v.kind.size]) == string(expectedBytes) {
+			v.setElement(index, replacement)
+		}
+		return old, nil
+	})
+	r.defineFunction(atomics, "exchange", 3, r.atomicReadModifyWrite(func(x, y []byte, _ *elementType) {
+		copy(x, y)
+	}))
+	r.defineFunction(atomics, "isLockFree", 1, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		n, err := toInteger(argument(args, 0))
+		if err != nil {
+			return nil, err
+		}
+		return lang.Boolean(n == 1 || n == 2 || n == 4), nil
+	})
+	r.defineFunction(atomics, "load", 2, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		v, index, err := r.validateAtomicAccess(argument(args, 0), argument(args, 1), false)
+		if err != nil {
+			return nil, err
+		}
+		if v.block.detached {
+			return nil, errors.NewTypeError("Cannot perform Atomics.load on a detached ArrayBuffer")
+		}
+		return v.element(index), nil
+	})
+	r.defineFunction(atomics, "notify", 3, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		_, _, err := r.validateAtomicAccess(argument(args, 0), argument(args, 1), true)
+		if err != nil {
+			return nil, err
+		}
+		if count := argument(args, 2); count != lang.Undefined {
+			if _, err := toInteger(count); err != nil {
+				return nil, err
+			}
+		}
+		// no agent can be waiting, since agents cannot suspend
+		return lang.Zero, nil
+	})
+	r.defineFunction(atomics, "or", 3, r.atomicReadModifyWrite(bytewise(func(x, y byte) byte { return x | y })))
+	r.defineFunction(atomics, "store", 3, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		v, index, err := r.validateAtomicAccess(argument(args, 0), argument(args, 1), false)
+		if err != nil {
+			return nil, err
+		}
+		n, err := lang.ToInteger(argument(args, 2))
+		if err != nil {
+			return nil, err
+		}
+		if v.block.detached {
+			return nil, errors.NewTypeError("Cannot perform Atomics.store on a detached ArrayBuffer")
+		}
+		v.setElement(index, n)
+		return n, nil
+	})
+	r.defineFunction(atomics, "sub", 3, r.atomicReadModifyWrite(func(x, y []byte, kind *elementType) {
+		difference := kind.decode(x, binary.LittleEndian) - kind.decode(y, binary.LittleEndian)
+		kind.encode(x, binary.LittleEndian, number(difference))
+	}))
+	r.defineFunction(atomics, "wait", 4, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		v, _, err := r.validateAtomicAccess(argument(args, 0), argument(args, 1), true)
+		if err != nil {
+			return nil, err
+		}
+		if !v.block.shared {
+			return nil, errors.NewTypeError("Atomics.wait cannot be called on a non-shared Int32Array")
+		}
+		if _, err := lang.ToInt32(argument(args, 2)); err != nil {
+			return nil, err
+		}
+		if _, err := lang.ToNumber(argument(args, 3)); err != nil {
+			return nil, err
+		}
+		return nil, errors.NewTypeError("Atomics.wait cannot be called in this context")
+	})
+	r.defineFunction(atomics, "xor", 3, r.atomicReadModifyWrite(bytewise(func(x, y byte) byte { return x ^ y })))
+	defineToStringTag(atomics, "Atomics")
+}
+
+// validateAtomicAccess validates that the given value is an integer
+// TypedArray, or an Int32Array if waitable is true, and that the given
+// request index is a valid index of it.
+// ValidateSharedIntegerTypedArray is specified in 24.4.1.1 and
+// ValidateAtomicAccess in 24.4.1.2.
+func (r *Realm) validateAtomicAccess(typedArray, requestIndex lang.Value, waitable bool) (*arrayBufferView, int, errors.Error) {
+	v, err := validateTypedArray(typedArray)
+	if err != nil {
+		return nil, 0, err
+	}
+	if waitable && v.kind.name != "Int32" || v.kind.float || v.kind.name == "Uint8Clamped" {
+		return nil, 0, errors.NewTypeError("Atomics operations are not allowed on " + v.kind.constructorName())
+	}
+	accessIndex, err := toIndex(requestIndex)
+	if err != nil {
+		return nil, 0, err
+	}
+	if accessIndex >= float64(v.length) {
+		return nil, 0, errors.NewRangeError("Invalid atomic access index")
+	}
+	return v, int(accessIndex), nil
+}
+
+// atomicReadModifyWrite returns the implementation of an Atomics function,
+// that applies the given operation to the raw bytes of an element and the
+// raw bytes of the converted value, and returns the previous value of the
+// element. The operation stores its result in x.
+// AtomicReadModifyWrite is specified in 24.4.1.11.
+func (r *Realm) atomicReadModifyWrite(op func(x, y []byte, kind *elementType)) BuiltinFunction {
+	return func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		v, index, err := r.validateAtomicAccess(argument(args, 0), argument(args, 1), false)
+		if err != nil {
+			return nil, err
+		}
+		n, err := lang.ToInteger(argument(args, 2))
+		if err != nil {
+			return nil, err
+		}
+		if v.block.detached {
+			return nil, errors.NewTypeError("Cannot perform this operation on a detached ArrayBuffer")
+		}
+		y := make([]byte, v.kind.size)
+		v.kind.encode(y, binary.LittleEndian, n)
+		old := v.element(index)
+		offset := v.byteOffset + index*v.kind.size
+		op(v.block.bytes[offset:offset+v.kind.size], y, v.kind)
+		return old, nil
+	}
+}
+
+// bytewise returns an operation for atomicReadModifyWrite, that applies
+// the given function to each byte.
+func bytewise(fn func(x, y byte) byte) func(x, y []byte, kind *elementType) {
+	return func(x, y []byte, _ *elementType) {
+		for i := range x {
+			x[i] = fn(x[i], y[i])
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package realm
+
+import (
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+)
+
+// createBoolean creates the Boolean constructor and %BooleanPrototype%, as
+// specified in 19.3.
+func (r *Realm) createBoolean() {
+	boolProto := lang.ObjectCreate(r.intrinsic(IntrinsicNameObjectPrototype), lang.SlotBooleanData)
+	boolProto.SetSlot(lang.SlotBooleanData, lang.False)
+	r.Intrinsics.SetField(IntrinsicNameBooleanPrototype, boolProto)
+
+	boolean := r.newConstructor("Boolean", 1, boolProto, func(newTarget lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		b := lang.ToBoolean(argument(args, 0))
+		nt, ok := newTarget.(*lang.Object)
+		if !ok {
+			return b, nil
+		}
+		o, err := OrdinaryCreateFromConstructor(nt, IntrinsicNameBooleanPrototype, lang.SlotBooleanData)
+		if err != nil {
+			return nil, err
+		}
+		o.SetSlot(lang.SlotBooleanData, b)
+		return o, nil
+	})
+	r.Intrinsics.SetField(IntrinsicNameBoolean, boolean)
+
+	r.defineFunction(boolProto, "toString", 0, func(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+		b, err := thisBooleanValue(this)
+		if err != nil {
+			return nil, err
+		}
+		if b {
+			return str("true"), nil
+		}
+		return str("false"), nil
+	})
+	r.defineFunction(boolProto, "valueOf", 0, func(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+		return thisBooleanValue(this)
+	})
+}
+
+// thisBooleanValue returns the given value if it is a Boolean, or the value
+// of its BooleanData internal slot if it is a Boolean object.
+// thisBooleanValue is specified in 19.3.3.
+func thisBooleanValue(v lang.Value) (lang.Boolean, errors.Error) {
+	switch v := v.(type) {
+	case lang.Boolean:
+		return v, nil
+	case *lang.Object:
+		if b, ok := v.GetSlot(lang.SlotBooleanData).(lang.Boolean); ok {
+			return b, nil
+		}
+	}
+	return false, errors.NewTypeError("Boolean.prototype method called on incompatible receiver")
+}
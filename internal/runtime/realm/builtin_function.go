@@ -5,9 +5,15 @@ import (
 	"github.com/gojisvm/gojis/internal/runtime/lang"
 )
 
+// BuiltinFunction is the Go implementation of a built-in function object.
+// It is called with the this value and the arguments of the call.
+type BuiltinFunction = func(lang.Value, ...lang.Value) (lang.Value, errors.Error)
+
 // CreateBuiltinFunction creates a callable object, whose Call internal method will be the passed function fn.
+// Calls of the function are counted against the maximum call depth, see
+// Realm#CallStack.
 // CreateBuiltinFunction is specified in 9.3.3.
-func CreateBuiltinFunction(fn func(lang.Value, ...lang.Value) (lang.Value, errors.Error), realm *Realm, proto lang.Value, internalSlotsList ...lang.StringOrSymbol) *lang.Object {
+func CreateBuiltinFunction(fn BuiltinFunction, realm *Realm, proto lang.Value, internalSlotsList ...lang.StringOrSymbol) *lang.Object {
 	if realm == nil {
 		realm = CurrentRealm()
 	}
@@ -15,9 +21,108 @@ func CreateBuiltinFunction(fn func(lang.Value, ...lang.Value) (lang.Value, error
 		proto = realm.GetIntrinsicObject(IntrinsicNameFunctionPrototype)
 	}
 	fobj := lang.ObjectCreate(proto, internalSlotsList...)
-	fobj.Call = fn
+	fobj.Call = func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		if err := realm.enter(); err != nil {
+			return nil, err
+		}
+		defer realm.leave()
+		return fn(this, args...)
+	}
 	fobj.Realm = realm
 	fobj.Extensible = true
 	fobj.ScriptOrModule = lang.Null
 	return fobj
 }
+
+// NewFunction creates a built-in function object of this realm with the
+// given name and length properties, as described in clause 17.
+func (r *Realm) NewFunction(name string, length int, fn BuiltinFunction) *lang.Object {
+	f := CreateBuiltinFunction(fn, r, nil)
+	lang.SetFunctionLength(f, length)
+	lang.SetFunctionName(f, lang.NewStringKey(name), "")
+	return f
+}
+
+// defineFunction defines a built-in function with the given name and
+// length as method property of the given object.
+func (r *Realm) defineFunction(o *lang.Object, name string, length int, fn BuiltinFunction) *lang.Object {
+	f := r.NewFunction(name, length, fn)
+	defineMethodProperty(o, name, f)
+	return f
+}
+
+// argument returns the argument at the given index, or Undefined if there
+// are not enough arguments.
+func argument(args []lang.Value, i int) lang.Value {
+	if i < len(args) {
+		return args[i]
+	}
+	return lang.Undefined
+}
+
+// builtinConstructor is the Go implementation of a built-in constructor.
+// It is called with the NewTarget, which is Undefined if the constructor
+// is called as a function, and the arguments of the call.
+type builtinConstructor = func(newTarget lang.Value, args ...lang.Value) (lang.Value, errors.Error)
+
+// newConstructor creates a built-in constructor of this realm with the
+// given name and length properties. The prototype property of the
+// constructor is set to proto, and the constructor property of proto is
+// set to the constructor. proto may be nil for constructors without a
+// prototype object.
+func (r *Realm) newConstructor(name string, length int, proto *lang.Object, fn builtinConstructor) *lang.Object {
+	f := r.NewFunction(name, length, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		return fn(lang.Undefined, args...)
+	})
+	f.Construct = func(newTarget *lang.Object, args ...lang.Value) (*lang.Object, errors.Error) {
+		if err := r.enter(); err != nil {
+			return nil, err
+		}
+		defer r.leave()
+		v, err := fn(newTarget, args...)
+		if err != nil {
+			return nil, err
+		}
+		return v.(*lang.Object), nil
+	}
+	if proto != nil {
+		defineValue(f, "prototype", proto)
+		defineMethodProperty(proto, "constructor", f)
+	}
+	return f
+}
+
+// defineSymbolFunction defines a built-in function as method property of
+// the given object, whose key is the given well-known symbol. The name of
+// the function is the description of the symbol in brackets, e.g.
+// "[Symbol.iterator]".
+func (r *Realm) defineSymbolFunction(o *lang.Object, sym *lang.Symbol, length int, fn BuiltinFunction) *lang.Object {
+	f := CreateBuiltinFunction(fn, r, nil)
+	lang.SetFunctionLength(f, length)
+	lang.SetFunctionName(f, lang.NewStringOrSymbol(sym), "")
+	_, _ = lang.CreateMethodProperty(o, lang.NewStringOrSymbol(sym), f)
+	return f
+}
+
+// defineGetter defines an accessor property with the given key on the
+// given object, that has a built-in getter and no setter.
+func (r *Realm) defineGetter(o *lang.Object, key lang.StringOrSymbol, fn BuiltinFunction) {
+	getter := CreateBuiltinFunction(fn, r, nil)
+	lang.SetFunctionLength(getter, 0)
+	lang.SetFunctionName(getter, key, "get")
+	_, _ = lang.DefinePropertyOrThrow(o, key, lang.NewAccessorProperty(getter, nil, lang.False, lang.True))
+}
+
+// defineToStringTag defines the @@toStringTag property of the given
+// object, which is not writable and not enumerable, but configurable.
+func defineToStringTag(o *lang.Object, tag string) {
+	_, _ = lang.DefinePropertyOrThrow(o, lang.NewStringOrSymbol(lang.SymbolToStringTag), lang.NewDataProperty(lang.NewString(tag), lang.False, lang.False, lang.True))
+}
+
+// defineSpecies defines the @@species getter of the given constructor,
+// which returns the this value.
+func (r *Realm) defineSpecies(constructor *lang.Object) {
+	r.defineGetter(constructor, lang.NewStringOrSymbol(lang.SymbolSpecies), func(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+		return this, nil
+	})
+}
@@ -0,0 +1,179 @@
+package realm
+
+import (
+	"encoding/binary"
+
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+)
+
+// thisDataView returns the view of the this value of a method of
+// %DataViewPrototype%.
+func thisDataView(this lang.Value, method string) (*arrayBufferView, errors.Error) {
+	if o, ok := this.(*lang.Object); ok && o.HasSlot(slotViewedArrayBuffer) {
+		if v := o.GetSlot(slotViewedArrayBuffer).(*arrayBufferView); v.kind == nil {
+			return v, nil
+		}
+	}
+	return nil, errors.NewTypeError("Method DataView.prototype." + method + " called on incompatible receiver")
+}
+
+// createDataView creates the DataView constructor and %DataViewPrototype%,
+// as specified in 24.3.
+func (r *Realm) createDataView() {
+	proto := lang.ObjectCreate(r.intrinsic(IntrinsicNameObjectPrototype))
+	r.Intrinsics.SetField(IntrinsicNameDataViewPrototype, proto)
+
+	dataView := r.newConstructor("DataView", 1, proto, func(newTarget lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		nt, ok := newTarget.(*lang.Object)
+		if !ok {
+			return nil, errors.NewTypeError("Constructor DataView requires 'new'")
+		}
+		buffer, ok := argument(args, 0).(*lang.Object)
+		block, isBuffer := arrayBufferData(buffer)
+		if !ok || !isBuffer {
+			return nil, errors.NewTypeError("First argument to DataView constructor must be an ArrayBuffer")
+		}
+		offset, err := toIndex(argument(args, 1))
+		if err != nil {
+			return nil, err
+		}
+		if block.detached {
+			return nil, errors.NewTypeError("Cannot construct a DataView on a detached ArrayBuffer")
+		}
+		bufferByteLength := float64(len(block.bytes))
+		if offset > bufferByteLength {
+			return nil, errors.NewRangeError("Start offset " + numberString(offset) + " is outside the bounds of the buffer")
+		}
+		viewByteLength := bufferByteLength - offset
+		if byteLength := argument(args, 2); byteLength != lang.Undefined {
+			if viewByteLength, err = toIndex(byteLength); err != nil {
+				return nil, err
+			}
+			if offset+viewByteLength > bufferByteLength {
+				return nil, errors.NewRangeError("Invalid DataView length " + numberString(viewByteLength))
+			}
+		}
+		o, err := OrdinaryCreateFromConstructor(nt, IntrinsicNameDataViewPrototype, slotViewedArrayBuffer)
+		if err != nil {
+			return nil, err
+		}
+		if block.detached {
+			return nil, errors.NewTypeError("Cannot construct a DataView on a detached ArrayBuffer")
+		}
+		o.SetSlot(slotViewedArrayBuffer, &arrayBufferView{
+			buffer:     buffer,
+			block:      block,
+			byteOffset: int(offset),
+			byteLength: int(viewByteLength),
+		})
+		return o, nil
+	})
+	r.Intrinsics.SetField(IntrinsicNameDataView, dataView)
+
+	r.defineGetter(proto, key("buffer"), func(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+		v, err := thisDataView(this, "buffer")
+		if err != nil {
+			return nil, err
+		}
+		return v.buffer, nil
+	})
+	r.defineGetter(proto, key("byteLength"), func(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+		v, err := thisDataView(this, "byteLength")
+		if err != nil {
+			return nil, err
+		}
+		if v.block.detached {
+			return nil, errors.NewTypeError("Cannot perform DataView.prototype.byteLength on a detached ArrayBuffer")
+		}
+		return number(float64(v.byteLength)), nil
+	})
+	r.defineGetter(proto, key("byteOffset"), func(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+		v, err := thisDataView(this, "byteOffset")
+		if err != nil {
+			return nil, err
+		}
+		if v.block.detached {
+			return nil, errors.NewTypeError("Cannot perform DataView.prototype.byteOffset on a detached ArrayBuffer")
+		}
+		return number(float64(v.byteOffset)), nil
+	})
+
+	for _, kind := range elementTypes {
+		if kind.name == "Uint8Clamped" {
+			continue
+		}
+		r.defineFunction(proto, "get"+kind.name, 1, r.dataViewGetter(kind))
+		r.defineFunction(proto, "set"+kind.name, 2, r.dataViewSetter(kind))
+	}
+	defineToStringTag(proto, "DataView")
+}
+
+// byteOrder returns the byte order that is selected by the littleEndian
+// argument of the get and set methods of %DataViewPrototype%.
+func byteOrder(littleEndian lang.Value) binary.ByteOrder {
+	if lang.ToBoolean(littleEndian) {
+		return binary.LittleEndian
+	}
+	return binary.BigEndian
+}
+
+// viewIndex returns the index in the buffer of the element of the given
+// type at the given request index, or a RangeError if the element is not
+// within the view.
+func (v *arrayBufferView) viewIndex(getIndex float64, kind *elementType) (int, errors.Error) {
+	if v.block.detached {
+		return 0, errors.NewTypeError("Cannot perform this operation on a detached ArrayBuffer")
+	}
+	if getIndex+float64(kind.size) > float64(v.byteLength) {
+		return 0, errors.NewRangeError("Offset is outside the bounds of the DataView")
+	}
+	return int(getIndex) + v.byteOffset, nil
+}
+
+// dataViewGetter returns the get method of %DataViewPrototype% for the
+// given element type.
+// GetViewValue is specified in 24.3.1.1.
+func (r *Realm) dataViewGetter(kind *elementType) BuiltinFunction {
+	return func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		v, err := thisDataView(this, "get"+kind.name)
+		if err != nil {
+			return nil, err
+		}
+		getIndex, err := toIndex(argument(args, 0))
+		if err != nil {
+			return nil, err
+		}
+		index, err := v.viewIndex(getIndex, kind)
+		if err != nil {
+			return nil, err
+		}
+		return number(kind.decode(v.block.bytes[index:index+kind.size], byteOrder(argument(args, 1)))), nil
+	}
+}
+
+// dataViewSetter returns the set method of %DataViewPrototype% for the
+// given element type.
+// SetViewValue is specified in 24.3.1.2.
+func (r *Realm) dataViewSetter(kind *elementType) BuiltinFunction {
+	return func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		v, err := thisDataView(this, "set"+kind.name)
+		if err != nil {
+			return nil, err
+		}
+		getIndex, err := toIndex(argument(args, 0))
+		if err != nil {
+			return nil, err
+		}
+		n, err := lang.ToNumber(argument(args, 1))
+		if err != nil {
+			return nil, err
+		}
+		index, err := v.viewIndex(getIndex, kind)
+		if err != nil {
+			return nil, err
+		}
+		kind.encode(v.block.bytes[index:index+kind.size], byteOrder(argument(args, 2)), n)
+		return lang.Undefined, nil
+	}
+}
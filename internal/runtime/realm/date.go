@@ -0,0 +1,888 @@
+package realm
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+)
+
+// slotDateValue is the DateValue internal slot of Date objects, that holds
+// the time value of the date.
+var slotDateValue = lang.NewStringKey("DateValue")
+
+// Time related constants, as specified in 20.3.1.
+const (
+	msPerSecond = 1000.0
+	msPerMinute = 60000.0
+	msPerHour   = 3600000.0
+	msPerDay    = 86400000.0
+
+	// maxTimeValue is the largest absolute time value, as specified in
+	// 20.3.1.1.
+	maxTimeValue = 8.64e15
+)
+
+var (
+	weekDayNames = []string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"}
+	monthNames   = []string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"}
+)
+
+// createDate creates the Date constructor and %DatePrototype%, as
+// specified in 20.3.
+func (r *Realm) createDate() {
+	dateProto := lang.ObjectCreate(r.intrinsic(IntrinsicNameObjectPrototype))
+	r.Intrinsics.SetField(IntrinsicNameDatePrototype, dateProto)
+
+	var date *lang.Object
+	date = r.newConstructor("Date", 7, dateProto, func(newTarget lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		nt, ok := newTarget.(*lang.Object)
+		if !ok {
+			return str(dateToString(now())), nil
+		}
+
+		var tv float64
+		switch len(args) {
+		case 0:
+			tv = now()
+		case 1:
+			value := args[0]
+			if o, ok := value.(*lang.Object); ok && o.HasSlot(slotDateValue) {
+				tv = o.GetSlot(slotDateValue).(lang.Number).Float64()
+				break
+			}
+			v, err := lang.ToPrimitive(value, nil)
+			if err != nil {
+				return nil, err
+			}
+			if s, ok := v.(lang.String); ok {
+				tv = parseDate(s.String())
+				break
+			}
+			if tv, err = toNumber(v); err != nil {
+				return nil, err
+			}
+		default:
+			finalDate, err := dateFromComponents(args)
+			if err != nil {
+				return nil, err
+			}
+			tv = utc(finalDate)
+		}
+
+		o, err := OrdinaryCreateFromConstructor(nt, IntrinsicNameDatePrototype, slotDateValue)
+		if err != nil {
+			return nil, err
+		}
+		o.SetSlot(slotDateValue, number(timeClip(tv)))
+		return o, nil
+	})
+	r.Intrinsics.SetField(IntrinsicNameDate, date)
+
+	r.defineFunction(date, "now", 0, func(lang.Value, ...lang.Value) (lang.Value, errors.Error) {
+		return number(now()), nil
+	})
+	r.defineFunction(date, "parse", 1, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		s, err := toString(argument(args, 0))
+		if err != nil {
+			return nil, err
+		}
+		return number(parseDate(s)), nil
+	})
+	r.defineFunction(date, "UTC", 7, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		if len(args) == 0 {
+			return lang.NaN, nil
+		}
+		t, err := dateFromComponents(args)
+		if err != nil {
+			return nil, err
+		}
+		return number(timeClip(t)), nil
+	})
+
+	r.defineDatePrototype(dateProto)
+}
+
+// now returns the current time value.
+func now() float64 {
+	return float64(time.Now().UnixNano() / int64(time.Millisecond))
+}
+
+// dateFromComponents returns the time value of the date with the given
+// year, month, date, hours, minutes, seconds and milliseconds, as done by
+// the Date constructor and Date.UTC. At least one argument must be given.
+func dateFromComponents(args []lang.Value) (float64, errors.Error) {
+	// year, month, date, hours, minutes, seconds, ms
+	components := []float64{0, 0, 1, 0, 0, 0, 0}
+	for i := range components {
+		if i < len(args) {
+			n, err := toNumber(args[i])
+			if err != nil {
+				return 0, err
+			}
+			components[i] = n
+		}
+	}
+	y := components[0]
+	if !math.IsNaN(y) {
+		if yi := toIntegerOrInfinity(y); yi >= 0 && yi <= 99 {
+			y = 1900 + yi
+		}
+	}
+	finalDate := makeDate(makeDay(y, components[1], components[2]), makeTime(components[3], components[4], components[5], components[6]))
+	return finalDate, nil
+}
+
+// toIntegerOrInfinity converts the given number to an integer, where NaN
+// becomes 0.
+func toIntegerOrInfinity(n float64) float64 {
+	if math.IsNaN(n) {
+		return 0
+	}
+	return math.Trunc(n)
+}
+
+// CreateDate creates a new Date object with the given time value, which is
+// clipped like the Date constructor does.
+func (r *Realm) CreateDate(tv float64) *lang.Object {
+	o := lang.ObjectCreate(r.intrinsic(IntrinsicNameDatePrototype), slotDateValue)
+	o.SetSlot(slotDateValue, number(timeClip(tv)))
+	return o
+}
+
+// DateValue returns the time value of the given value and true, if the
+// value is a Date object. The time value is NaN for invalid dates.
+func DateValue(v lang.Value) (float64, bool) {
+	tv, err := thisTimeValue(v)
+	return tv, err == nil
+}
+
+// thisTimeValue returns the time value of the given Date object.
+// thisTimeValue is specified in 20.3.4.
+func thisTimeValue(v lang.Value) (float64, errors.Error) {
+	if o, ok := v.(*lang.Object); ok && o.HasSlot(slotDateValue) {
+		return o.GetSlot(slotDateValue).(lang.Number).Float64(), nil
+	}
+	return 0, errors.NewTypeError("this is not a Date object.")
+}
+
+// defineDatePrototype defines the properties of %DatePrototype%, as
+// specified in 20.3.4.
+func (r *Realm) defineDatePrototype(proto *lang.Object) {
+	// getter returns a function that returns the result of fn applied to
+	// the time value, which is converted to local time if local is true
+	getter := func(local bool, fn func(t float64) float64) BuiltinFunction {
+		return func(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+			t, err := thisTimeValue(this)
+			if err != nil {
+				return nil, err
+			}
+			if math.IsNaN(t) {
+				return lang.NaN, nil
+			}
+			if local {
+				t = localTime(t)
+			}
+			return number(fn(t)), nil
+		}
+	}
+
+	dateGetters := []struct {
+		name string
+		fn   func(t float64) float64
+	}{
+		{"Date", dateFromTime},
+		{"Day", weekDay},
+		{"FullYear", yearFromTime},
+		{"Hours", hourFromTime},
+		{"Milliseconds", msFromTime},
+		{"Minutes", minFromTime},
+		{"Month", monthFromTime},
+		{"Seconds", secFromTime},
+	}
+	for _, g := range dateGetters {
+		r.defineFunction(proto, "get"+g.name, 0, getter(true, g.fn))
+		r.defineFunction(proto, "getUTC"+g.name, 0, getter(false, g.fn))
+	}
+	r.defineFunction(proto, "getTime", 0, func(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+		t, err := thisTimeValue(this)
+		if err != nil {
+			return nil, err
+		}
+		return number(t), nil
+	})
+	r.defineFunction(proto, "getTimezoneOffset", 0, getter(false, func(t float64) float64 {
+		return (t - localTime(t)) / msPerMinute
+	}))
+
+	// setter returns a function that sets the time value to the result of
+	// fn, which gets the time value, converted to local time if local is
+	// true, and the arguments converted to numbers
+	setter := func(local bool, length int, fn func(t float64, args []float64) float64) BuiltinFunction {
+		return func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+			t, err := thisTimeValue(this)
+			if err != nil {
+				return nil, err
+			}
+			if len(args) > length {
+				args = args[:length]
+			}
+			if len(args) == 0 {
+				args = []lang.Value{lang.Undefined}
+			}
+			values, err := numbers(args)
+			if err != nil {
+				return nil, err
+			}
+			if local && !math.IsNaN(t) {
+				t = localTime(t)
+			}
+			newDate := fn(t, values)
+			if local {
+				newDate = utc(newDate)
+			}
+			v := number(timeClip(newDate))
+			this.(*lang.Object).SetSlot(slotDateValue, v)
+			return v, nil
+		}
+	}
+	// arg returns the i-th value, or the default value if there is no
+	// such value
+	arg := func(values []float64, i int, def float64) float64 {
+		if i < len(values) {
+			return values[i]
+		}
+		return def
+	}
+
+	dateSetters := []struct {
+		name   string
+		length int
+		nanOK  bool // whether the setter operates on a NaN time value
+		fn     func(t float64, args []float64) float64
+	}{
+		{"Date", 1, false, func(t float64, args []float64) float64 {
+			return makeDate(makeDay(yearFromTime(t), monthFromTime(t), args[0]), timeWithinDay(t))
+		}},
+		{"FullYear", 3, true, func(t float64, args []float64) float64 {
+			if math.IsNaN(t) {
+				t = 0
+			}
+			return makeDate(makeDay(args[0], arg(args, 1, monthFromTime(t)), arg(args, 2, dateFromTime(t))), timeWithinDay(t))
+		}},
+		{"Hours", 4, false, func(t float64, args []float64) float64 {
+			return makeDate(day(t), makeTime(args[0], arg(args, 1, minFromTime(t)), arg(args, 2, secFromTime(t)), arg(args, 3, msFromTime(t))))
+		}},
+		{"Milliseconds", 1, false, func(t float64, args []float64) float64 {
+			return makeDate(day(t), makeTime(hourFromTime(t), minFromTime(t), secFromTime(t), args[0]))
+		}},
+		{"Minutes", 3, false, func(t float64, args []float64) float64 {
+			return makeDate(day(t), makeTime(hourFromTime(t), args[0], arg(args, 1, secFromTime(t)), arg(args, 2, msFromTime(t))))
+		}},
+		{"Month", 2, false, func(t float64, args []float64) float64 {
+			return makeDate(makeDay(yearFromTime(t), args[0], arg(args, 1, dateFromTime(t))), timeWithinDay(t))
+		}},
+		{"Seconds", 2, false, func(t float64, args []float64) float64 {
+			return makeDate(day(t), makeTime(hourFromTime(t), minFromTime(t), args[0], arg(args, 1, msFromTime(t))))
+		}},
+	}
+	for _, s := range dateSetters {
+		fn := s.fn
+		if !s.nanOK {
+			fn = func(t float64, args []float64) float64 {
+				if math.IsNaN(t) {
+					return math.NaN()
+				}
+				return s.fn(t, args)
+			}
+		}
+		r.defineFunction(proto, "set"+s.name, s.length, setter(true, s.length, fn))
+		r.defineFunction(proto, "setUTC"+s.name, s.length, setter(false, s.length, fn))
+	}
+	r.defineFunction(proto, "setTime", 1, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		if _, err := thisTimeValue(this); err != nil {
+			return nil, err
+		}
+		t, err := toNumber(argument(args, 0))
+		if err != nil {
+			return nil, err
+		}
+		v := number(timeClip(t))
+		this.(*lang.Object).SetSlot(slotDateValue, v)
+		return v, nil
+	})
+
+	// formatter returns a function that formats the time value with fn,
+	// or returns "Invalid Date" if the time value is NaN
+	formatter := func(fn func(t float64) string) BuiltinFunction {
+		return func(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+			t, err := thisTimeValue(this)
+			if err != nil {
+				return nil, err
+			}
+			if math.IsNaN(t) {
+				return str("Invalid Date"), nil
+			}
+			return str(fn(t)), nil
+		}
+	}
+	toDateString := func(t float64) string {
+		return dateString(localTime(t))
+	}
+	toTimeString := func(t float64) string {
+		return timeString(localTime(t)) + timeZoneString(t)
+	}
+	r.defineFunction(proto, "toDateString", 0, formatter(toDateString))
+	r.defineFunction(proto, "toISOString", 0, func(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+		t, err := thisTimeValue(this)
+		if err != nil {
+			return nil, err
+		}
+		if math.IsNaN(t) || math.IsInf(t, 0) {
+			return nil, errors.NewRangeError("Invalid time value")
+		}
+		return str(isoString(t)), nil
+	})
+	r.defineFunction(proto, "toJSON", 1, func(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+		o, err := r.toObject(this)
+		if err != nil {
+			return nil, err
+		}
+		tv, err := lang.ToPrimitive(o, lang.TypeNumber)
+		if err != nil {
+			return nil, err
+		}
+		if n, ok := tv.(lang.Number); ok && (n.IsNaN() || math.IsInf(n.Float64(), 0)) {
+			return lang.Null, nil
+		}
+		return lang.Invoke(r, o, key("toISOString"))
+	})
+	r.defineFunction(proto, "toLocaleDateString", 0, formatter(toDateString))
+	r.defineFunction(proto, "toLocaleString", 0, formatter(dateToString))
+	r.defineFunction(proto, "toLocaleTimeString", 0, formatter(toTimeString))
+	r.defineFunction(proto, "toString", 0, formatter(dateToString))
+	r.defineFunction(proto, "toTimeString", 0, formatter(toTimeString))
+	toUTCString := r.defineFunction(proto, "toUTCString", 0, formatter(utcString))
+	r.defineFunction(proto, "valueOf", 0, func(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+		t, err := thisTimeValue(this)
+		if err != nil {
+			return nil, err
+		}
+		return number(t), nil
+	})
+	toPrimitive := r.NewFunction("[Symbol.toPrimitive]", 1, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		o, ok := this.(*lang.Object)
+		if !ok {
+			return nil, errors.NewTypeError("Date.prototype[Symbol.toPrimitive] called on non-object")
+		}
+		hint, _ := argument(args, 0).(lang.String)
+		switch hint.String() {
+		case "string", "default":
+			return lang.OrdinaryToPrimitive(o, "string")
+		case "number":
+			return lang.OrdinaryToPrimitive(o, "number")
+		}
+		return nil, errors.NewTypeError("Invalid hint")
+	})
+	_, _ = lang.DefinePropertyOrThrow(proto, symbolKey(lang.SymbolToPrimitive), lang.NewDataProperty(toPrimitive, lang.False, lang.False, lang.True))
+
+	// B.2.4, additional properties of the Date.prototype object
+	r.defineFunction(proto, "getYear", 0, getter(true, func(t float64) float64 {
+		return yearFromTime(t) - 1900
+	}))
+	r.defineFunction(proto, "setYear", 1, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		t, err := thisTimeValue(this)
+		if err != nil {
+			return nil, err
+		}
+		if math.IsNaN(t) {
+			t = 0
+		} else {
+			t = localTime(t)
+		}
+		y, err := toNumber(argument(args, 0))
+		if err != nil {
+			return nil, err
+		}
+		if math.IsNaN(y) {
+			this.(*lang.Object).SetSlot(slotDateValue, lang.NaN)
+			return lang.NaN, nil
+		}
+		if yi := toIntegerOrInfinity(y); yi >= 0 && yi <= 99 {
+			y = 1900 + yi
+		}
+		d := makeDay(y, monthFromTime(t), dateFromTime(t))
+		v := number(timeClip(utc(makeDate(d, timeWithinDay(t)))))
+		this.(*lang.Object).SetSlot(slotDateValue, v)
+		return v, nil
+	})
+	defineMethodProperty(proto, "toGMTString", toUTCString)
+}
+
+// day returns the number of the day of the given time value.
+// Day is specified in 20.3.1.2.
+func day(t float64) float64 {
+	return math.Floor(t / msPerDay)
+}
+
+// timeWithinDay returns the milliseconds since the start of the day of the
+// given time value.
+// TimeWithinDay is specified in 20.3.1.2.
+func timeWithinDay(t float64) float64 {
+	return positiveModulo(t, msPerDay)
+}
+
+// positiveModulo returns x modulo y, with the sign of y.
+func positiveModulo(x, y float64) float64 {
+	m := math.Mod(x, y)
+	if m < 0 {
+		m += y
+	}
+	return m
+}
+
+// daysInYear returns the number of days of the given year.
+// DaysInYear is specified in 20.3.1.3.
+func daysInYear(y float64) float64 {
+	if math.Mod(y, 4) != 0 || (math.Mod(y, 100) == 0 && math.Mod(y, 400) != 0) {
+		return 365
+	}
+	return 366
+}
+
+// dayFromYear returns the number of the first day of the given year.
+// DayFromYear is specified in 20.3.1.3.
+func dayFromYear(y float64) float64 {
+	return 365*(y-1970) + math.Floor((y-1969)/4) - math.Floor((y-1901)/100) + math.Floor((y-1601)/400)
+}
+
+// timeFromYear returns the time value of the start of the given year.
+// TimeFromYear is specified in 20.3.1.3.
+func timeFromYear(y float64) float64 {
+	return msPerDay * dayFromYear(y)
+}
+
+// yearFromTime returns the year of the given time value.
+// YearFromTime is specified in 20.3.1.3.
+func yearFromTime(t float64) float64 {
+	y := math.Floor(t/(msPerDay*365.2425)) + 1970
+	for timeFromYear(y) > t {
+		y--
+	}
+	for timeFromYear(y+1) <= t {
+		y++
+	}
+	return y
+}
+
+// inLeapYear returns 1 if the given time value is in a leap year, and 0
+// otherwise.
+// InLeapYear is specified in 20.3.1.3.
+func inLeapYear(t float64) float64 {
+	return daysInYear(yearFromTime(t)) - 365
+}
+
+// monthStartDays holds the day within a non-leap year, on which each month
+// starts.
+var monthStartDays = []float64{0, 31, 59, 90, 120, 151, 181, 212, 243, 273, 304, 334, 365}
+
+// monthStart returns the day within the year, on which the given month
+// starts.
+func monthStart(m, leap float64) float64 {
+	if m >= 2 {
+		return monthStartDays[int(m)] + leap
+	}
+	return monthStartDays[int(m)]
+}
+
+// dayWithinYear returns the day within the year of the given time value.
+// DayWithinYear is specified in 20.3.1.4.
+func dayWithinYear(t float64) float64 {
+	return day(t) - dayFromYear(yearFromTime(t))
+}
+
+// monthFromTime returns the month of the given time value, from 0 for
+// January to 11 for December.
+// MonthFromTime is specified in 20.3.1.4.
+func monthFromTime(t float64) float64 {
+	d, leap := dayWithinYear(t), inLeapYear(t)
+	m := 0.0
+	for d >= monthStart(m+1, leap) {
+		m++
+	}
+	return m
+}
+
+// dateFromTime returns the day of the month of the given time value.
+// DateFromTime is specified in 20.3.1.5.
+func dateFromTime(t float64) float64 {
+	return dayWithinYear(t) - monthStart(monthFromTime(t), inLeapYear(t)) + 1
+}
+
+// weekDay returns the day of the week of the given time value, from 0 for
+// Sunday to 6 for Saturday.
+// WeekDay is specified in 20.3.1.6.
+func weekDay(t float64) float64 {
+	return positiveModulo(day(t)+4, 7)
+}
+
+// localTZA returns the offset of the local time zone from UTC in
+// milliseconds, at the given time value. If isUTC is false, the time value
+// is a local time value.
+// LocalTZA is specified in 20.3.1.7.
+func localTZA(t float64, isUTC bool) float64 {
+	if math.IsNaN(t) || math.IsInf(t, 0) {
+		return 0
+	}
+	offset := func(t float64) float64 {
+		_, seconds := time.Unix(int64(math.Floor(t/msPerSecond)), 0).In(time.Local).Zone()
+		return float64(seconds) * msPerSecond
+	}
+	if isUTC {
+		return offset(t)
+	}
+	return offset(t - offset(t))
+}
+
+// localTime converts the given time value from UTC to local time.
+// LocalTime is specified in 20.3.1.8.
+func localTime(t float64) float64 {
+	return t + localTZA(t, true)
+}
+
+// utc converts the given time value from local time to UTC.
+// UTC is specified in 20.3.1.9.
+func utc(t float64) float64 {
+	return t - localTZA(t, false)
+}
+
+// hourFromTime returns the hour of the given time value.
+// HourFromTime is specified in 20.3.1.10.
+func hourFromTime(t float64) float64 {
+	return positiveModulo(math.Floor(t/msPerHour), 24)
+}
+
+// minFromTime returns the minutes of the given time value.
+// MinFromTime is specified in 20.3.1.10.
+func minFromTime(t float64) float64 {
+	return positiveModulo(math.Floor(t/msPerMinute), 60)
+}
+
+// secFromTime returns the seconds of the given time value.
+// SecFromTime is specified in 20.3.1.10.
+func secFromTime(t float64) float64 {
+	return positiveModulo(math.Floor(t/msPerSecond), 60)
+}
+
+// msFromTime returns the milliseconds of the given time value.
+// msFromTime is specified in 20.3.1.10.
+func msFromTime(t float64) float64 {
+	return positiveModulo(t, msPerSecond)
+}
+
+// isFinite reports whether all given numbers are finite.
+func isFinite(values ...float64) bool {
+	for _, v := range values {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// makeTime returns the milliseconds of the given time of day.
+// MakeTime is specified in 20.3.1.11.
+func makeTime(hour, min, sec, ms float64) float64 {
+	if !isFinite(hour, min, sec, ms) {
+		return math.NaN()
+	}
+	return math.Trunc(hour)*msPerHour + math.Trunc(min)*msPerMinute + math.Trunc(sec)*msPerSecond + math.Trunc(ms)
+}
+
+// makeDay returns the day number of the given date. Months and dates
+// outside of their range overflow into the next year or month.
+// MakeDay is specified in 20.3.1.12.
+func makeDay(year, month, date float64) float64 {
+	if !isFinite(year, month, date) {
+		return math.NaN()
+	}
+	y, m, dt := math.Trunc(year), math.Trunc(month), math.Trunc(date)
+	ym := y + math.Floor(m/12)
+	mn := positiveModulo(m, 12)
+	if math.Abs(ym) > 400000 {
+		return math.NaN()
+	}
+	t := timeFromYear(ym)
+	return day(t) + monthStart(mn, daysInYear(ym)-365) + dt - 1
+}
+
+// makeDate returns the time value of the given day and time within the
+// day.
+// MakeDate is specified in 20.3.1.13.
+func makeDate(day, time float64) float64 {
+	if !isFinite(day, time) {
+		return math.NaN()
+	}
+	return day*msPerDay + time
+}
+
+// timeClip returns the given time value as integer, or NaN if it is out of
+// the range of valid time values.
+// TimeClip is specified in 20.3.1.14.
+func timeClip(t float64) float64 {
+	if !isFinite(t) || math.Abs(t) > maxTimeValue {
+		return math.NaN()
+	}
+	return math.Trunc(t) + 0 // +0 converts -0 to +0
+}
+
+// yearString returns the given year with at least four digits.
+func yearString(y float64) string {
+	if y < 0 {
+		return fmt.Sprintf("-%04d", int64(-y))
+	}
+	return fmt.Sprintf("%04d", int64(y))
+}
+
+// dateString returns the date of the given local time value in the format
+// "Tue Jan 02 2018".
+// DateString is specified in 20.3.4.41.2.
+func dateString(tv float64) string {
+	return fmt.Sprintf("%s %s %02d %s", weekDayNames[int(weekDay(tv))], monthNames[int(monthFromTime(tv))], int(dateFromTime(tv)), yearString(yearFromTime(tv)))
+}
+
+// timeString returns the time of the given local time value in the format
+// "10:00:00 GMT".
+// TimeString is specified in 20.3.4.41.1.
+func timeString(tv float64) string {
+	return fmt.Sprintf("%02d:%02d:%02d GMT", int(hourFromTime(tv)), int(minFromTime(tv)), int(secFromTime(tv)))
+}
+
+// timeZoneString returns the offset of the local time zone at the given
+// time value in the format "+0100".
+// TimeZoneString is specified in 20.3.4.41.3.
+func timeZoneString(tv float64) string {
+	offset := localTZA(tv, true)
+	sign := "+"
+	if offset < 0 {
+		sign = "-"
+		offset = -offset
+	}
+	return fmt.Sprintf("%s%02d%02d", sign, int(hourFromTime(offset)), int(minFromTime(offset)))
+}
+
+// dateToString returns the given time value in the format of
+// Date.prototype.toString.
+// ToDateString is specified in 20.3.4.41.4.
+func dateToString(tv float64) string {
+	if math.IsNaN(tv) {
+		return "Invalid Date"
+	}
+	t := localTime(tv)
+	return dateString(t) + " " + timeString(t) + timeZoneString(tv)
+}
+
+// utcString returns the given time value in the format of
+// Date.prototype.toUTCString, for example "Tue, 02 Jan 2018 10:00:00 GMT".
+// Date.prototype.toUTCString is specified in 20.3.4.43.
+func utcString(tv float64) string {
+	return fmt.Sprintf("%s, %02d %s %s %s", weekDayNames[int(weekDay(tv))], int(dateFromTime(tv)), monthNames[int(monthFromTime(tv))], yearString(yearFromTime(tv)), timeString(tv))
+}
+
+// isoString returns the given time value in the date time string format,
+// for example "2018-01-02T10:00:00.000Z".
+// Date.prototype.toISOString is specified in 20.3.4.36.
+func isoString(tv float64) string {
+	y := yearFromTime(tv)
+	year := fmt.Sprintf("%04d", int64(y))
+	if y < 0 {
+		year = fmt.Sprintf("-%06d", int64(-y))
+	} else if y > 9999 {
+		year = fmt.Sprintf("+%06d", int64(y))
+	}
+	return fmt.Sprintf("%s-%02d-%02dT%02d:%02d:%02d.%03dZ", year, int(monthFromTime(tv))+1, int(dateFromTime(tv)), int(hourFromTime(tv)), int(minFromTime(tv)), int(secFromTime(tv)), int(msFromTime(tv)))
+}
+
+// parseDate parses the given string as date, and returns its time value,
+// or NaN if it cannot be parsed. The date time string format, and the
+// formats produced by Date.prototype.toString and toUTCString are
+// recognized.
+// Date.parse is specified in 20.3.3.2.
+func parseDate(s string) float64 {
+	s = strings.TrimSpace(s)
+	if tv, ok := parseISODate(s); ok {
+		return timeClip(tv)
+	}
+
+	// Date.prototype.toString produces "Tue Jan 02 2018 10:00:00 GMT+0100",
+	// followed by an optional time zone name in parentheses
+	if i := strings.IndexByte(s, '('); i > 0 {
+		s = strings.TrimSpace(s[:i])
+	}
+	layouts := []string{
+		"Mon Jan 02 2006 15:04:05 GMT-0700",
+		"Mon Jan 02 2006 15:04:05 MST",
+		"Mon, 02 Jan 2006 15:04:05 GMT",
+		"Mon, 02 Jan 2006 15:04:05 MST",
+		"Mon Jan 02 2006",
+		"Jan 02 2006",
+		"Jan 2 2006",
+		"Jan 2, 2006",
+		"January 2, 2006",
+		"2006/01/02",
+		"2006/01/02 15:04:05",
+		"01/02/2006",
+		"1/2/2006",
+	}
+	for _, layout := range layouts {
+		t, err := time.Parse(layout, s)
+		if err != nil {
+			continue
+		}
+		tv := float64(t.Unix())*msPerSecond + float64(t.Nanosecond()/int(time.Millisecond))
+		if !strings.Contains(layout, "MST") && !strings.Contains(layout, "GMT") {
+			// formats without time zone are interpreted as local time
+			tv = utc(tv)
+		}
+		return timeClip(tv)
+	}
+	return math.NaN()
+}
+
+// parseISODate parses the given string in the date time string format,
+// YYYY-MM-DDTHH:mm:ss.sssZ, where all parts but the year are optional and
+// the year may be an expanded year with six digits and a sign. Date-only
+// forms are interpreted as UTC, date-time forms without time zone offset
+// as local time.
+// The date time string format is specified in 20.3.1.15.
+func parseISODate(s string) (float64, bool) {
+	p := &isoParser{s: s}
+	sign := 1.0
+	yearDigits := 4
+	if p.accept('+') {
+		yearDigits = 6
+	} else if p.accept('-') {
+		yearDigits = 6
+		sign = -1
+	}
+	year, ok := p.number(yearDigits)
+	if !ok || (sign == -1 && year == 0) {
+		return 0, false
+	}
+	year *= sign
+
+	month, date := 1.0, 1.0
+	if p.accept('-') {
+		if month, ok = p.number(2); !ok || month < 1 || month > 12 {
+			return 0, false
+		}
+		if p.accept('-') {
+			if date, ok = p.number(2); !ok || date < 1 || date > 31 {
+				return 0, false
+			}
+		}
+	}
+
+	var hour, min, sec, ms float64
+	local := false
+	if p.accept('T') {
+		local = true
+		if hour, ok = p.number(2); !ok || hour > 24 || !p.accept(':') {
+			return 0, false
+		}
+		if min, ok = p.number(2); !ok || min > 59 {
+			return 0, false
+		}
+		if p.accept(':') {
+			if sec, ok = p.number(2); !ok || sec > 59 {
+				return 0, false
+			}
+			if p.accept('.') {
+				start := p.i
+				for p.i < len(p.s) && p.s[p.i] >= '0' && p.s[p.i] <= '9' {
+					p.i++
+				}
+				digits := p.s[start:p.i]
+				if digits == "" {
+					return 0, false
+				}
+				if len(digits) > 3 {
+					digits = digits[:3]
+				}
+				for len(digits) < 3 {
+					digits += "0"
+				}
+				n, _ := strconv.Atoi(digits)
+				ms = float64(n)
+			}
+		}
+		if hour == 24 && (min != 0 || sec != 0 || ms != 0) {
+			return 0, false
+		}
+	}
+
+	offset := 0.0
+	if p.accept('Z') {
+		local = false
+	} else if p.i < len(p.s) && (p.s[p.i] == '+' || p.s[p.i] == '-') {
+		offsetSign := 1.0
+		if p.s[p.i] == '-' {
+			offsetSign = -1
+		}
+		p.i++
+		offsetHour, ok := p.number(2)
+		if !ok || !p.accept(':') {
+			return 0, false
+		}
+		offsetMin, ok := p.number(2)
+		if !ok {
+			return 0, false
+		}
+		offset = offsetSign * (offsetHour*msPerHour + offsetMin*msPerMinute)
+		local = false
+	}
+	if p.i != len(p.s) {
+		return 0, false
+	}
+
+	tv := makeDate(makeDay(year, month-1, date), makeTime(hour, min, sec, ms)) - offset
+	if local {
+		tv = utc(tv)
+	}
+	return tv, true
+}
+
+// isoParser is a parser for strings in the date time string format.
+type isoParser struct {
+	s string
+	i int
+}
+
+// accept consumes the given character, if it is the next character.
+func (p *isoParser) accept(c byte) bool {
+	if p.i < len(p.s) && p.s[p.i] == c {
+		p.i++
+		return true
+	}
+	return false
+}
+
+// number consumes a number with exactly the given number of digits.
+func (p *isoParser) number(digits int) (float64, bool) {
+	if p.i+digits > len(p.s) {
+		return 0, false
+	}
+	n := 0
+	for _, c := range []byte(p.s[p.i : p.i+digits]) {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		n = n*10 + int(c-'0')
+	}
+	p.i += digits
+	return float64(n), true
+}
@@ -0,0 +1,294 @@
+package realm
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+)
+
+// nativeErrors holds the names of the NativeError constructors and the
+// intrinsic names of the constructors and their prototypes, as specified in
+// 19.5.5.
+var nativeErrors = []struct {
+	name          string
+	intrinsicName string
+	protoName     string
+}{
+	{"EvalError", IntrinsicNameEvalError, IntrinsicNameEvalErrorPrototype},
+	{"RangeError", IntrinsicNameRangeError, IntrinsicNameRangeErrorPrototype},
+	{"ReferenceError", IntrinsicNameReferenceError, IntrinsicNameReferenceErrorPrototype},
+	{"SyntaxError", IntrinsicNameSyntaxError, IntrinsicNameSyntaxErrorPrototype},
+	{"TypeError", IntrinsicNameTypeError, IntrinsicNameTypeErrorPrototype},
+	{"URIError", IntrinsicNameURIError, IntrinsicNameURIErrorPrototype},
+}
+
+// createErrors creates the Error constructor, the NativeError constructors
+// and their prototypes, as specified in 19.5.
+func (r *Realm) createErrors() {
+	errorProto := lang.ObjectCreate(r.intrinsic(IntrinsicNameObjectPrototype))
+	defineMethodProperty(errorProto, "name", str("Error"))
+	defineMethodProperty(errorProto, "message", str(""))
+	r.defineFunction(errorProto, "toString", 0, errorPrototypeToString)
+	r.Intrinsics.SetField(IntrinsicNameErrorPrototype, errorProto)
+
+	errorCtor := r.newErrorConstructor("Error", errorProto, IntrinsicNameErrorPrototype, nil)
+	_, _ = lang.CreateDataProperty(errorCtor, key("stackTraceLimit"), lang.NewNumber(defaultStackTraceLimit))
+	r.defineFunction(errorCtor, "captureStackTrace", 2, r.errorCaptureStackTrace)
+	r.Intrinsics.SetField(IntrinsicNameError, errorCtor)
+
+	for _, e := range nativeErrors {
+		proto := lang.ObjectCreate(errorProto)
+		defineMethodProperty(proto, "name", str(e.name))
+		defineMethodProperty(proto, "message", str(""))
+		r.Intrinsics.SetField(e.protoName, proto)
+
+		ctor := r.newErrorConstructor(e.name, proto, e.protoName, errorCtor)
+		r.Intrinsics.SetField(e.intrinsicName, ctor)
+	}
+
+	r.createAggregateError(errorProto, errorCtor)
+}
+
+// newErrorConstructor creates the Error constructor or a NativeError
+// constructor with the given name, whose instances inherit from the
+// intrinsic prototype with the given name. The prototype of NativeError
+// constructors is the Error constructor, which is passed as parent.
+// The Error constructor is specified in 19.5.1.1, the NativeError
+// constructors are specified in 19.5.6.1.1.
+func (r *Realm) newErrorConstructor(name string, proto *lang.Object, protoName string, parent *lang.Object) *lang.Object {
+	var ctor *lang.Object
+	ctor = r.newConstructor(name, 1, proto, func(newTarget lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		o, err := OrdinaryCreateFromConstructor(activeFunctionOrNewTarget(newTarget, ctor), protoName, lang.SlotErrorData)
+		if err != nil {
+			return nil, err
+		}
+		o.SetSlot(lang.SlotErrorData, lang.Undefined)
+		if err := setErrorMessage(o, argument(args, 0)); err != nil {
+			return nil, err
+		}
+		if err := r.captureStackTrace(o, lang.Undefined); err != nil {
+			return nil, err
+		}
+		return o, nil
+	})
+	if parent != nil {
+		ctor.Prototype = parent
+	}
+	return ctor
+}
+
+// createAggregateError creates the AggregateError constructor and its
+// prototype. The constructor takes an iterable of errors, which are the
+// value of the errors property of the created object, and a message.
+// AggregateError is specified in 20.5.7 of ES2021.
+func (r *Realm) createAggregateError(errorProto, errorCtor *lang.Object) {
+	proto := lang.ObjectCreate(errorProto)
+	defineMethodProperty(proto, "name", str("AggregateError"))
+	defineMethodProperty(proto, "message", str(""))
+	r.Intrinsics.SetField(IntrinsicNameAggregateErrorPrototype, proto)
+
+	var ctor *lang.Object
+	ctor = r.newConstructor("AggregateError", 2, proto, func(newTarget lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		o, err := OrdinaryCreateFromConstructor(activeFunctionOrNewTarget(newTarget, ctor), IntrinsicNameAggregateErrorPrototype, lang.SlotErrorData)
+		if err != nil {
+			return nil, err
+		}
+		o.SetSlot(lang.SlotErrorData, lang.Undefined)
+		if err := setErrorMessage(o, argument(args, 1)); err != nil {
+			return nil, err
+		}
+		var errs []lang.Value
+		if err := r.iterate(argument(args, 0), func(v lang.Value) errors.Error {
+			errs = append(errs, v)
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+		defineMethodProperty(o, "errors", lang.CreateArrayFromList(r, errs))
+		if err := r.captureStackTrace(o, lang.Undefined); err != nil {
+			return nil, err
+		}
+		return o, nil
+	})
+	ctor.Prototype = errorCtor
+	r.Intrinsics.SetField(IntrinsicNameAggregateError, ctor)
+}
+
+// setErrorMessage sets the message property of the given error object to
+// the given message converted to a string, unless it is Undefined.
+func setErrorMessage(o *lang.Object, message lang.Value) errors.Error {
+	if message == lang.Undefined {
+		return nil
+	}
+	msg, err := lang.ToString(message)
+	if err != nil {
+		return err
+	}
+	defineMethodProperty(o, "message", msg)
+	return nil
+}
+
+// defaultStackTraceLimit is the initial value of Error.stackTraceLimit.
+const defaultStackTraceLimit = 10
+
+// errorCaptureStackTrace is Error.captureStackTrace, which defines the
+// stack property of the object that is passed as first argument, like
+// the one of error objects. If the second argument is a function, the
+// frames up to and including the innermost call of the function are left
+// out. Error.captureStackTrace is not part of the specification, it is
+// compatible with the function of V8.
+func (r *Realm) errorCaptureStackTrace(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+	o, ok := argument(args, 0).(*lang.Object)
+	if !ok {
+		return nil, errors.NewTypeError("Error.captureStackTrace called on non-object")
+	}
+	fn := argument(args, 1)
+	if !lang.InternalIsCallable(fn) {
+		fn = lang.Undefined
+	}
+	if err := r.captureStackTrace(o, fn); err != nil {
+		return nil, err
+	}
+	return lang.Undefined, nil
+}
+
+// captureStackTrace defines the stack property of the given object, whose
+// value is the string of the object, like Error.prototype.toString returns
+// it, followed by one line for each frame of the stack trace of the
+// running execution context, as V8 does. If fn is a function, the frames
+// up to and including its innermost frame are left out. At most
+// Error.stackTraceLimit frames are included, and no stack property is
+// defined if Error.stackTraceLimit is not a number.
+func (r *Realm) captureStackTrace(o *lang.Object, fn lang.Value) errors.Error {
+	desc := r.intrinsic(IntrinsicNameError).GetOwnProperty(key("stackTraceLimit"))
+	if desc == nil || !desc.IsDataDescriptor() {
+		return nil
+	}
+	limit, ok := desc.Value().(lang.Number)
+	if !ok {
+		return nil
+	}
+
+	header, err := errorPrototypeToString(o)
+	if err != nil {
+		return err
+	}
+	var frames []string
+	if r.StackTrace != nil {
+		frames = r.StackTrace(fn)
+	}
+	if n := limit.Float64(); n < float64(len(frames)) {
+		frames = frames[:int(math.Max(n, 0))]
+	}
+
+	var b strings.Builder
+	b.WriteString(header.(lang.String).String())
+	for _, frame := range frames {
+		b.WriteString("\n    at ")
+		b.WriteString(frame)
+	}
+	_, err = lang.DefinePropertyOrThrow(o, key("stack"), lang.NewDataProperty(str(b.String()), lang.True, lang.False, lang.True))
+	return err
+}
+
+// errorPrototypeToString is Error.prototype.toString.
+// Error.prototype.toString is specified in 19.5.3.4.
+func errorPrototypeToString(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+	o, ok := this.(*lang.Object)
+	if !ok {
+		return nil, errors.NewTypeError("Error.prototype.toString called on non-object")
+	}
+	name, err := errorStringProperty(o, "name", "Error")
+	if err != nil {
+		return nil, err
+	}
+	msg, err := errorStringProperty(o, "message", "")
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case name == "":
+		return str(msg), nil
+	case msg == "":
+		return str(name), nil
+	}
+	return str(name + ": " + msg), nil
+}
+
+// errorStringProperty returns the value of the property with the given name
+// of the given error object converted to a string, or def if the value is
+// Undefined.
+func errorStringProperty(o *lang.Object, name, def string) (string, errors.Error) {
+	v, err := get(o, key(name))
+	if err != nil {
+		return "", err
+	}
+	if v == lang.Undefined {
+		return def, nil
+	}
+	return toString(v)
+}
+
+// errorPrototypes maps the kinds of runtime errors to the intrinsic
+// prototypes of the corresponding error objects.
+var errorPrototypes = map[errors.ErrorKind]string{
+	errors.ErrorKindAggregateError: IntrinsicNameAggregateErrorPrototype,
+	errors.ErrorKindEvalError:      IntrinsicNameEvalErrorPrototype,
+	errors.ErrorKindRangeError:     IntrinsicNameRangeErrorPrototype,
+	errors.ErrorKindReferenceError: IntrinsicNameReferenceErrorPrototype,
+	errors.ErrorKindSyntaxError:    IntrinsicNameSyntaxErrorPrototype,
+	errors.ErrorKindTypeError:      IntrinsicNameTypeErrorPrototype,
+	errors.ErrorKindURIError:       IntrinsicNameURIErrorPrototype,
+}
+
+// CreateErrorObject creates a new error object of the given kind with the
+// given message, like the NativeError constructors do when they are called
+// with a message, as specified in 19.5.6.1.1. The errors property of an
+// AggregateError object created for ErrorKindAggregateError is an empty
+// array.
+func (r *Realm) CreateErrorObject(kind errors.ErrorKind, msg string) *lang.Object {
+	intrinsicName, ok := errorPrototypes[kind]
+	if !ok {
+		panic(fmt.Sprintf("No error object for error kind %v", kind))
+	}
+	o := r.newError(intrinsicName, msg)
+	if kind == errors.ErrorKindAggregateError {
+		defineMethodProperty(o, "errors", lang.CreateArrayFromList(r, nil))
+	}
+	return o
+}
+
+// newError creates a new error object, whose prototype is the intrinsic
+// with the given name, with the given message.
+func (r *Realm) newError(protoName, msg string) *lang.Object {
+	o := lang.ObjectCreate(r.intrinsic(protoName), lang.SlotErrorData)
+	o.SetSlot(lang.SlotErrorData, lang.Undefined)
+	defineMethodProperty(o, "message", str(msg))
+	_ = r.captureStackTrace(o, lang.Undefined)
+	return o
+}
+
+// ErrorValue returns the language value that is thrown for the given
+// error. If the error carries a thrown value, that value is returned,
+// otherwise a new error object of the kind of the error is created, from
+// the intrinsics of the realm. The errors property of the AggregateError
+// object that is created for an *errors.AggregateError holds the values
+// of the aggregated errors.
+func (r *Realm) ErrorValue(err errors.Error) lang.Value {
+	switch err := err.(type) {
+	case *lang.ThrowError:
+		return err.Value
+	case *errors.AggregateError:
+		values := make([]lang.Value, len(err.Errors))
+		for i, e := range err.Errors {
+			values[i] = r.ErrorValue(e)
+		}
+		o := r.newError(IntrinsicNameAggregateErrorPrototype, err.Message())
+		defineMethodProperty(o, "errors", lang.CreateArrayFromList(r, values))
+		return o
+	}
+	return r.CreateErrorObject(err.Kind(), err.Message())
+}
@@ -0,0 +1,160 @@
+package realm
+
+import (
+	"math"
+
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+)
+
+// slotBoundThis is the internal slot of bound function exotic objects that
+// holds the this value of calls to the target function, as specified in
+// 9.4.1. The bound arguments are captured by the Call and Construct methods
+// of the bound function.
+var slotBoundThis = lang.NewStringKey("BoundThis")
+
+// createFunction creates the Function constructor and the properties of
+// %FunctionPrototype%, as specified in 19.2.
+func (r *Realm) createFunction() {
+	funcProto := r.intrinsic(IntrinsicNameFunctionPrototype)
+
+	function := r.newConstructor("Function", 1, funcProto, func(newTarget lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		if r.CreateDynamicFunction == nil {
+			return nil, errors.NewSyntaxError("Code generation from strings is not supported")
+		}
+		return r.CreateDynamicFunction(newTarget, args)
+	})
+	r.Intrinsics.SetField(IntrinsicNameFunction, function)
+
+	r.defineFunction(funcProto, "apply", 2, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		f, err := callable(this, "Function.prototype.apply was called on a value that")
+		if err != nil {
+			return nil, err
+		}
+		argArray := argument(args, 1)
+		if argArray == lang.Undefined || argArray == lang.Null {
+			return lang.Call(f, argument(args, 0))
+		}
+		argList, err := lang.CreateListFromArrayLike(argArray, r.work)
+		if err != nil {
+			return nil, err
+		}
+		return lang.Call(f, argument(args, 0), argList...)
+	})
+	r.defineFunction(funcProto, "bind", 1, r.functionPrototypeBind)
+	r.defineFunction(funcProto, "call", 1, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		f, err := callable(this, "Function.prototype.call was called on a value that")
+		if err != nil {
+			return nil, err
+		}
+		if len(args) == 0 {
+			return lang.Call(f, lang.Undefined)
+		}
+		return lang.Call(f, args[0], args[1:]...)
+	})
+	r.defineFunction(funcProto, "toString", 0, func(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+		f, ok := this.(*lang.Object)
+		if !ok || f.Call == nil {
+			return nil, errors.NewTypeError("Function.prototype.toString requires that 'this' be a Function")
+		}
+		name := functionName(f)
+		return str("function " + name + "() { [native code] }"), nil
+	})
+
+	hasInstance := CreateBuiltinFunction(func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		return lang.OrdinaryHasInstance(this, argument(args, 0))
+	}, r, nil)
+	lang.SetFunctionLength(hasInstance, 1)
+	lang.SetFunctionName(hasInstance, symbolKey(lang.SymbolHasInstance), "")
+	_, _ = lang.DefinePropertyOrThrow(funcProto, symbolKey(lang.SymbolHasInstance), lang.NewDataProperty(hasInstance, lang.False, lang.False, lang.False))
+}
+
+// functionPrototypeBind is Function.prototype.bind.
+// Function.prototype.bind is specified in 19.2.3.2.
+func (r *Realm) functionPrototypeBind(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+	target, err := callable(this, "Bind must be called on a function, but the value")
+	if err != nil {
+		return nil, err
+	}
+	var boundArgs []lang.Value
+	if len(args) > 1 {
+		boundArgs = append(boundArgs, args[1:]...)
+	}
+	f := r.BoundFunctionCreate(target, argument(args, 0), boundArgs)
+
+	l := 0.0
+	if lang.HasOwnProperty(target, key("length")) {
+		targetLen, err := get(target, key("length"))
+		if err != nil {
+			return nil, err
+		}
+		if n, ok := targetLen.(lang.Number); ok {
+			switch x := n.Float64(); {
+			case math.IsInf(x, 1):
+				l = math.Inf(1)
+			case math.IsInf(x, -1), n.IsNaN():
+			default:
+				l = math.Max(0, math.Trunc(x)-float64(len(boundArgs)))
+			}
+		}
+	}
+	_, _ = lang.DefinePropertyOrThrow(f, key("length"), lang.NewDataProperty(number(l), lang.False, lang.False, lang.True))
+
+	targetName, err := get(target, key("name"))
+	if err != nil {
+		return nil, err
+	}
+	name, ok := targetName.(lang.String)
+	if !ok {
+		name = lang.String{}
+	}
+	lang.SetFunctionName(f, lang.NewStringOrSymbol(name), "bound")
+	return f, nil
+}
+
+// BoundFunctionCreate creates a bound function exotic object, that wraps
+// the given target function. Calling the bound function calls the target
+// function with the given this value, and the given arguments followed by
+// the arguments of the call. Calls of the bound function are counted
+// against the maximum call depth of the realm, see Realm#CallStack.
+// BoundFunctionCreate is specified in 9.4.1.3.
+func (r *Realm) BoundFunctionCreate(target *lang.Object, boundThis lang.Value, boundArgs []lang.Value) *lang.Object {
+	obj := lang.ObjectCreate(target.GetPrototypeOf(), lang.SlotBoundTargetFunction, slotBoundThis)
+	obj.SetSlot(lang.SlotBoundTargetFunction, target)
+	obj.SetSlot(slotBoundThis, boundThis)
+
+	obj.Call = func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		if err := r.enter(); err != nil {
+			return nil, err
+		}
+		defer r.leave()
+		return lang.Call(target, boundThis, append(append([]lang.Value{}, boundArgs...), args...)...)
+	}
+	if target.Construct != nil {
+		obj.Construct = func(newTarget *lang.Object, args ...lang.Value) (*lang.Object, errors.Error) {
+			if err := r.enter(); err != nil {
+				return nil, err
+			}
+			defer r.leave()
+			if newTarget == obj {
+				newTarget = target
+			}
+			return lang.Construct(target, newTarget, append(append([]lang.Value{}, boundArgs...), args...)...)
+		}
+	}
+	return obj
+}
+
+// functionName returns the value of the name property of the given function
+// object, or the empty string if the function has no name.
+func functionName(f *lang.Object) string {
+	desc := f.GetOwnProperty(key("name"))
+	if desc == nil || !desc.IsDataDescriptor() {
+		return ""
+	}
+	name, ok := desc.Value().(lang.String)
+	if !ok {
+		return ""
+	}
+	return name.String()
+}
@@ -0,0 +1,321 @@
+package realm
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+)
+
+// Character sets used by the URI handling functions, as specified in
+// 18.2.6.1.
+const (
+	uriReserved   = ";/?:@&=+$,"
+	uriUnescaped  = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_.!~*'()"
+	uriUnescapedB = uriUnescaped + uriReserved + "#"
+)
+
+// createGlobalFunctions creates the function properties of the global
+// object, as specified in 18.2.
+func (r *Realm) createGlobalFunctions() {
+	global := func(name string, length int, fn BuiltinFunction) {
+		r.Intrinsics.SetField(name, r.NewFunction(name, length, fn))
+	}
+
+	global(IntrinsicNameEval, 1, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		x := argument(args, 0)
+		if _, ok := x.(lang.String); !ok {
+			return x, nil
+		}
+		if r.PerformEval == nil {
+			return nil, errors.NewSyntaxError("Code generation from strings is not supported")
+		}
+		return r.PerformEval(x)
+	})
+	global(IntrinsicNameIsFinite, 1, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		n, err := toNumber(argument(args, 0))
+		if err != nil {
+			return nil, err
+		}
+		return lang.Boolean(!math.IsNaN(n) && !math.IsInf(n, 0)), nil
+	})
+	global(IntrinsicNameIsNaN, 1, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		n, err := toNumber(argument(args, 0))
+		if err != nil {
+			return nil, err
+		}
+		return lang.Boolean(math.IsNaN(n)), nil
+	})
+	global(IntrinsicNameParseFloat, 1, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		s, err := lang.ToString(argument(args, 0))
+		if err != nil {
+			return nil, err
+		}
+		return number(parseFloat(trimWhiteSpace(s, true, false).String())), nil
+	})
+	global(IntrinsicNameParseInt, 2, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		s, err := lang.ToString(argument(args, 0))
+		if err != nil {
+			return nil, err
+		}
+		radix, err := lang.ToInt32(argument(args, 1))
+		if err != nil {
+			return nil, err
+		}
+		return number(parseInt(trimWhiteSpace(s, true, false).String(), int(radix.Float64()))), nil
+	})
+	global(IntrinsicNameDecodeURI, 1, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		return r.uriFunction(argument(args, 0), func(s lang.String) (lang.String, errors.Error) {
+			return r.decode(s, uriReserved+"#")
+		})
+	})
+	global(IntrinsicNameDecodeURIComponent, 1, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		return r.uriFunction(argument(args, 0), func(s lang.String) (lang.String, errors.Error) {
+			return r.decode(s, "")
+		})
+	})
+	global(IntrinsicNameEncodeURI, 1, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		return r.uriFunction(argument(args, 0), func(s lang.String) (lang.String, errors.Error) {
+			return r.encode(s, uriUnescapedB)
+		})
+	})
+	global(IntrinsicNameEncodeURIComponent, 1, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		return r.uriFunction(argument(args, 0), func(s lang.String) (lang.String, errors.Error) {
+			return r.encode(s, uriUnescaped)
+		})
+	})
+}
+
+// parseFloat returns the number of the longest prefix of the given string,
+// that is a StrDecimalLiteral, or NaN if there is no such prefix.
+// parseFloat is specified in 18.2.4.
+func parseFloat(s string) float64 {
+	i := 0
+	if i < len(s) && (s[i] == '+' || s[i] == '-') {
+		i++
+	}
+	if strings.HasPrefix(s[i:], "Infinity") {
+		if s[0] == '-' {
+			return math.Inf(-1)
+		}
+		return math.Inf(1)
+	}
+
+	digits := func() int {
+		start := i
+		for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			i++
+		}
+		return i - start
+	}
+	n := digits()
+	if i < len(s) && s[i] == '.' {
+		i++
+		n += digits()
+	}
+	if n == 0 {
+		return math.NaN()
+	}
+	end := i
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		i++
+		if i < len(s) && (s[i] == '+' || s[i] == '-') {
+			i++
+		}
+		if digits() > 0 {
+			end = i
+		}
+	}
+
+	f, err := strconv.ParseFloat(strings.TrimSuffix(s[:end], "."), 64)
+	if err != nil && err.(*strconv.NumError).Err != strconv.ErrRange {
+		return math.NaN()
+	}
+	return f
+}
+
+// parseInt returns the integer of the longest prefix of the given string,
+// that consists of digits in the given radix, or NaN if there is no such
+// prefix. If the radix is 0, it is 16 if the string starts with 0x or 0X,
+// and 10 otherwise.
+// parseInt is specified in 18.2.5.
+func parseInt(s string, radix int) float64 {
+	sign := 1.0
+	if s != "" && (s[0] == '+' || s[0] == '-') {
+		if s[0] == '-' {
+			sign = -1
+		}
+		s = s[1:]
+	}
+
+	stripPrefix := true
+	if radix != 0 {
+		if radix < 2 || radix > 36 {
+			return math.NaN()
+		}
+		if radix != 16 {
+			stripPrefix = false
+		}
+	} else {
+		radix = 10
+	}
+	if stripPrefix && len(s) >= 2 && s[0] == '0' && (s[1] == 'x' || s[1] == 'X') {
+		s = s[2:]
+		radix = 16
+	}
+
+	end := 0
+	for end < len(s) && digitValue(s[end]) < radix {
+		end++
+	}
+	if end == 0 {
+		return math.NaN()
+	}
+
+	var f float64
+	if radix == 10 {
+		f, _ = strconv.ParseFloat(s[:end], 64)
+	} else {
+		i, _ := new(big.Int).SetString(strings.ToLower(s[:end]), radix)
+		f, _ = new(big.Float).SetInt(i).Float64()
+	}
+	return sign * f
+}
+
+// digitValue returns the value of the given digit in radix 36, or 36 if
+// the byte is not a digit.
+func digitValue(c byte) int {
+	switch {
+	case c >= '0' && c <= '9':
+		return int(c - '0')
+	case c >= 'a' && c <= 'z':
+		return int(c-'a') + 10
+	case c >= 'A' && c <= 'Z':
+		return int(c-'A') + 10
+	}
+	return 36
+}
+
+// uriFunction converts the given value to a string, and applies the given
+// encoding or decoding function to it.
+func (r *Realm) uriFunction(v lang.Value, fn func(lang.String) (lang.String, errors.Error)) (lang.Value, errors.Error) {
+	s, err := lang.ToString(v)
+	if err != nil {
+		return nil, err
+	}
+	result, err := fn(s)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// encode escapes all code points of the given string that are not in the
+// given set of unescaped characters with the UTF-8 encoding of the code
+// point.
+// Encode is specified in 18.2.6.1.1.
+func (r *Realm) encode(s lang.String, unescapedSet string) (lang.String, errors.Error) {
+	const hex = "0123456789ABCDEF"
+
+	var b strings.Builder
+	for k := 0; k < len(s); k++ {
+		c := s[k]
+		if c < utf8.RuneSelf && strings.IndexByte(unescapedSet, byte(c)) >= 0 {
+			b.WriteByte(byte(c))
+			continue
+		}
+
+		cp := rune(c)
+		switch {
+		case utf16.IsSurrogate(cp) && c >= 0xDC00:
+			return nil, errors.NewURIError("URI malformed")
+		case utf16.IsSurrogate(cp):
+			if k+1 == len(s) || s[k+1] < 0xDC00 || s[k+1] > 0xDFFF {
+				return nil, errors.NewURIError("URI malformed")
+			}
+			k++
+			cp = utf16.DecodeRune(cp, rune(s[k]))
+		}
+
+		var octets [utf8.UTFMax]byte
+		n := utf8.EncodeRune(octets[:], cp)
+		for _, octet := range octets[:n] {
+			b.WriteByte('%')
+			b.WriteByte(hex[octet>>4])
+			b.WriteByte(hex[octet&0xF])
+		}
+	}
+	return str(b.String()), nil
+}
+
+// decode replaces all escape sequences in the given string with the code
+// points they encode in UTF-8, except for code points in the given reserved
+// set, whose escape sequences are kept.
+// Decode is specified in 18.2.6.1.2.
+func (r *Realm) decode(s lang.String, reservedSet string) (lang.String, errors.Error) {
+	hexByte := func(k int) (byte, bool) {
+		if k+2 >= len(s) || s[k] != '%' {
+			return 0, false
+		}
+		hi, lo := s[k+1], s[k+2]
+		if hi >= utf8.RuneSelf || lo >= utf8.RuneSelf || digitValue(byte(hi)) >= 16 || digitValue(byte(lo)) >= 16 {
+			return 0, false
+		}
+		return byte(digitValue(byte(hi))<<4 | digitValue(byte(lo))), true
+	}
+
+	var result lang.String
+	for k := 0; k < len(s); k++ {
+		if s[k] != '%' {
+			result = append(result, s[k])
+			continue
+		}
+		start := k
+		b, ok := hexByte(k)
+		if !ok {
+			return nil, errors.NewURIError("URI malformed")
+		}
+		k += 2
+		if b < utf8.RuneSelf {
+			if strings.IndexByte(reservedSet, b) >= 0 {
+				result = append(result, s[start:k+1]...)
+			} else {
+				result = append(result, uint16(b))
+			}
+			continue
+		}
+
+		var n int
+		switch {
+		case b&0xE0 == 0xC0:
+			n = 2
+		case b&0xF0 == 0xE0:
+			n = 3
+		case b&0xF8 == 0xF0:
+			n = 4
+		default:
+			return nil, errors.NewURIError("URI malformed")
+		}
+		octets := []byte{b}
+		for j := 1; j < n; j++ {
+			k++
+			b, ok := hexByte(k)
+			if !ok || b&0xC0 != 0x80 {
+				return nil, errors.NewURIError("URI malformed")
+			}
+			octets = append(octets, b)
+			k += 2
+		}
+		cp, size := utf8.DecodeRune(octets)
+		if cp == utf8.RuneError || size != n {
+			return nil, errors.NewURIError("URI malformed")
+		}
+		result = append(result, utf16.Encode([]rune{cp})...)
+	}
+	return result, nil
+}
@@ -0,0 +1,176 @@
+package realm
+
+import (
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+)
+
+// Intrinsic names used by the specification.
+// The specification denotes the usage of these names as
+// e.g. %ThrowTypeError% (enclosed in '%').
+// The well-known intrinsics are listed in 6.1.7.4.
+const (
+	IntrinsicNameAggregateError             = "AggregateError"
+	IntrinsicNameAggregateErrorPrototype    = "AggregateErrorPrototype"
+	IntrinsicNameArray                      = "Array"
+	IntrinsicNameArrayBuffer                = "ArrayBuffer"
+	IntrinsicNameArrayBufferPrototype       = "ArrayBufferPrototype"
+	IntrinsicNameArrayIteratorPrototype     = "ArrayIteratorPrototype"
+	IntrinsicNameArrayPrototype             = lang.IntrinsicNameArrayPrototype
+	IntrinsicNameArrayProtoEntries          = "ArrayProto_entries"
+	IntrinsicNameArrayProtoForEach          = "ArrayProto_forEach"
+	IntrinsicNameArrayProtoKeys             = "ArrayProto_keys"
+	IntrinsicNameArrayProtoValues           = "ArrayProto_values"
+	IntrinsicNameAtomics                    = "Atomics"
+	IntrinsicNameBoolean                    = "Boolean"
+	IntrinsicNameBooleanPrototype           = lang.IntrinsicNameBooleanPrototype
+	IntrinsicNameDataView                   = "DataView"
+	IntrinsicNameDataViewPrototype          = "DataViewPrototype"
+	IntrinsicNameDate                       = "Date"
+	IntrinsicNameDatePrototype              = "DatePrototype"
+	IntrinsicNameDecodeURI                  = "decodeURI"
+	IntrinsicNameDecodeURIComponent         = "decodeURIComponent"
+	IntrinsicNameEncodeURI                  = "encodeURI"
+	IntrinsicNameEncodeURIComponent         = "encodeURIComponent"
+	IntrinsicNameError                      = "Error"
+	IntrinsicNameErrorPrototype             = "ErrorPrototype"
+	IntrinsicNameEval                       = "eval"
+	IntrinsicNameEvalError                  = "EvalError"
+	IntrinsicNameEvalErrorPrototype         = "EvalErrorPrototype"
+	IntrinsicNameFloat32Array               = "Float32Array"
+	IntrinsicNameFloat32ArrayPrototype      = "Float32ArrayPrototype"
+	IntrinsicNameFloat64Array               = "Float64Array"
+	IntrinsicNameFloat64ArrayPrototype      = "Float64ArrayPrototype"
+	IntrinsicNameFunction                   = "Function"
+	IntrinsicNameFunctionPrototype          = "FunctionPrototype"
+	IntrinsicNameInt8Array                  = "Int8Array"
+	IntrinsicNameInt8ArrayPrototype         = "Int8ArrayPrototype"
+	IntrinsicNameInt16Array                 = "Int16Array"
+	IntrinsicNameInt16ArrayPrototype        = "Int16ArrayPrototype"
+	IntrinsicNameInt32Array                 = "Int32Array"
+	IntrinsicNameInt32ArrayPrototype        = "Int32ArrayPrototype"
+	IntrinsicNameIsFinite                   = "isFinite"
+	IntrinsicNameIsNaN                      = "isNaN"
+	IntrinsicNameIteratorPrototype          = "IteratorPrototype"
+	IntrinsicNameJSON                       = "JSON"
+	IntrinsicNameJSONParse                  = "JSONParse"
+	IntrinsicNameJSONStringify              = "JSONStringify"
+	IntrinsicNameMap                        = "Map"
+	IntrinsicNameMapIteratorPrototype       = "MapIteratorPrototype"
+	IntrinsicNameMapPrototype               = "MapPrototype"
+	IntrinsicNameMath                       = "Math"
+	IntrinsicNameNumber                     = "Number"
+	IntrinsicNameNumberPrototype            = lang.IntrinsicNameNumberPrototype
+	IntrinsicNameObject                     = "Object"
+	IntrinsicNameObjectPrototype            = lang.IntrinsicNameObjectPrototype
+	IntrinsicNameObjProtoToString           = "ObjProto_toString"
+	IntrinsicNameObjProtoValueOf            = "ObjProto_valueOf"
+	IntrinsicNameParseFloat                 = "parseFloat"
+	IntrinsicNameParseInt                   = "parseInt"
+	IntrinsicNamePromise                    = "Promise"
+	IntrinsicNamePromisePrototype           = "PromisePrototype"
+	IntrinsicNamePromiseProtoThen           = "PromiseProto_then"
+	IntrinsicNamePromiseAll                 = "Promise_all"
+	IntrinsicNamePromiseReject              = "Promise_reject"
+	IntrinsicNamePromiseResolve             = "Promise_resolve"
+	IntrinsicNameProxy                      = "Proxy"
+	IntrinsicNameRangeError                 = "RangeError"
+	IntrinsicNameRangeErrorPrototype        = "RangeErrorPrototype"
+	IntrinsicNameReferenceError             = "ReferenceError"
+	IntrinsicNameReferenceErrorPrototype    = "ReferenceErrorPrototype"
+	IntrinsicNameReflect                    = "Reflect"
+	IntrinsicNameRegExp                     = "RegExp"
+	IntrinsicNameRegExpPrototype            = "RegExpPrototype"
+	IntrinsicNameSet                        = "Set"
+	IntrinsicNameSetIteratorPrototype       = "SetIteratorPrototype"
+	IntrinsicNameSetPrototype               = "SetPrototype"
+	IntrinsicNameSharedArrayBuffer          = "SharedArrayBuffer"
+	IntrinsicNameSharedArrayBufferPrototype = "SharedArrayBufferPrototype"
+	IntrinsicNameString                     = "String"
+	IntrinsicNameStringIteratorPrototype    = "StringIteratorPrototype"
+	IntrinsicNameStringPrototype            = lang.IntrinsicNameStringPrototype
+	IntrinsicNameSymbol                     = "Symbol"
+	IntrinsicNameSymbolPrototype            = lang.IntrinsicNameSymbolPrototype
+	IntrinsicNameSyntaxError                = "SyntaxError"
+	IntrinsicNameSyntaxErrorPrototype       = "SyntaxErrorPrototype"
+	IntrinsicNameThrowTypeError             = "ThrowTypeError"
+	IntrinsicNameTypedArray                 = "TypedArray"
+	IntrinsicNameTypedArrayPrototype        = "TypedArrayPrototype"
+	IntrinsicNameTypeError                  = "TypeError"
+	IntrinsicNameTypeErrorPrototype         = "TypeErrorPrototype"
+	IntrinsicNameUint8Array                 = "Uint8Array"
+	IntrinsicNameUint8ArrayPrototype        = "Uint8ArrayPrototype"
+	IntrinsicNameUint8ClampedArray          = "Uint8ClampedArray"
+	IntrinsicNameUint8ClampedArrayPrototype = "Uint8ClampedArrayPrototype"
+	IntrinsicNameUint16Array                = "Uint16Array"
+	IntrinsicNameUint16ArrayPrototype       = "Uint16ArrayPrototype"
+	IntrinsicNameUint32Array                = "Uint32Array"
+	IntrinsicNameUint32ArrayPrototype       = "Uint32ArrayPrototype"
+	IntrinsicNameURIError                   = "URIError"
+	IntrinsicNameURIErrorPrototype          = "URIErrorPrototype"
+	IntrinsicNameWeakMap                    = "WeakMap"
+	IntrinsicNameWeakMapPrototype           = "WeakMapPrototype"
+	IntrinsicNameWeakSet                    = "WeakSet"
+	IntrinsicNameWeakSetPrototype           = "WeakSetPrototype"
+)
+
+// CreateIntrinsics sets intrinsic objects of a record as specified
+// in 8.2.2.
+func CreateIntrinsics(r *Realm) {
+	r.Intrinsics = lang.NewRecord()
+
+	objProto := lang.ObjectCreate(lang.Null)
+	objProto.SetHeap(r.heap)
+	r.Intrinsics.SetField(IntrinsicNameObjectPrototype, objProto)
+
+	// %FunctionPrototype% is a built-in function that accepts any arguments
+	// and returns undefined, as specified in 19.2.3.
+	funcProto := CreateBuiltinFunction(func(lang.Value, ...lang.Value) (lang.Value, errors.Error) {
+		return lang.Undefined, nil
+	}, r, objProto)
+	lang.SetFunctionLength(funcProto, 0)
+	lang.SetFunctionName(funcProto, lang.NewStringKey(""), "")
+	r.Intrinsics.SetField(IntrinsicNameFunctionPrototype, funcProto)
+
+	r.Intrinsics.SetField(IntrinsicNameThrowTypeError, r.createThrowTypeError())
+
+	r.createObject()
+	r.createFunction()
+	r.createErrors()
+	r.createIteratorPrototypes()
+	r.createSymbol()
+	r.createBoolean()
+	r.createGlobalFunctions()
+	r.createNumber()
+	r.createMath()
+	r.createString()
+	r.createArray()
+	r.createDate()
+	r.createRegExp()
+	r.createMap()
+	r.createSet()
+	r.createWeakMap()
+	r.createWeakSet()
+	r.createArrayBuffer()
+	r.createSharedArrayBuffer()
+	r.createTypedArrays()
+	r.createDataView()
+	r.createAtomics()
+	r.createJSON()
+	r.createReflect()
+	r.createProxy()
+	r.createPromise()
+}
+
+// createThrowTypeError creates the %ThrowTypeError% intrinsic, an anonymous
+// function that throws a TypeError when it is called.
+// %ThrowTypeError% is specified in 9.2.9.1.
+func (r *Realm) createThrowTypeError() *lang.Object {
+	f := CreateBuiltinFunction(func(lang.Value, ...lang.Value) (lang.Value, errors.Error) {
+		return nil, errors.NewTypeError("'caller', 'callee', and 'arguments' properties may not be accessed on strict mode functions or the arguments objects for calls to them")
+	}, r, nil)
+	defineValue(f, "length", lang.Zero)
+	defineValue(f, "name", lang.NewString(""))
+	f.Extensible = false
+	return f
+}
@@ -0,0 +1,666 @@
+package realm
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+)
+
+// createJSON creates the JSON object, as specified in 24.5.
+func (r *Realm) createJSON() {
+	json := lang.ObjectCreate(r.intrinsic(IntrinsicNameObjectPrototype))
+	r.Intrinsics.SetField(IntrinsicNameJSON, json)
+
+	parse := r.defineFunction(json, "parse", 2, r.jsonParse)
+	r.Intrinsics.SetField(IntrinsicNameJSONParse, parse)
+	stringify := r.defineFunction(json, "stringify", 3, r.jsonStringify)
+	r.Intrinsics.SetField(IntrinsicNameJSONStringify, stringify)
+	defineToStringTag(json, "JSON")
+}
+
+// jsonParse is JSON.parse.
+// JSON.parse is specified in 24.5.1.
+func (r *Realm) jsonParse(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+	text, err := lang.ToString(argument(args, 0))
+	if err != nil {
+		return nil, err
+	}
+	p := &jsonParser{r: r, input: text}
+	p.skipWhitespace()
+	unfiltered, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	p.skipWhitespace()
+	if p.pos < len(p.input) {
+		return nil, p.unexpected()
+	}
+
+	reviver, ok := argument(args, 1).(*lang.Object)
+	if !ok || !lang.InternalIsCallable(reviver) {
+		return unfiltered, nil
+	}
+	root := lang.ObjectCreate(r.intrinsic(IntrinsicNameObjectPrototype))
+	_, _ = lang.CreateDataProperty(root, key(""), unfiltered)
+	return r.internalizeJSONProperty(root, key(""), reviver)
+}
+
+// internalizeJSONProperty calls the reviver with the given property of the
+// holder, after the properties of its value have been internalized.
+// InternalizeJSONProperty is specified in 24.5.1.1.
+func (r *Realm) internalizeJSONProperty(holder *lang.Object, name lang.StringOrSymbol, reviver *lang.Object) (lang.Value, errors.Error) {
+	if err := r.enter(); err != nil {
+		return nil, err
+	}
+	defer r.leave()
+	val, err := get(holder, name)
+	if err != nil {
+		return nil, err
+	}
+	if o, ok := val.(*lang.Object); ok {
+		var keys []lang.StringOrSymbol
+		if lang.InternalIsArray(o) {
+			length, err := lengthOfArrayLike(o)
+			if err != nil {
+				return nil, err
+			}
+			for i := 0.0; i < length; i++ {
+				r.work(1)
+				keys = append(keys, indexKey(i))
+			}
+		} else {
+			names, err := lang.EnumerableOwnPropertyNames(r, o, lang.EnumerateKey)
+			if err != nil {
+				return nil, err
+			}
+			for _, n := range names {
+				keys = append(keys, lang.NewStringOrSymbol(n))
+			}
+		}
+		for _, k := range keys {
+			newElement, err := r.internalizeJSONProperty(o, k, reviver)
+			if err != nil {
+				return nil, err
+			}
+			if newElement == lang.Undefined {
+				o.Delete(k)
+			} else if _, err := lang.CreateDataProperty(o, k, newElement); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return lang.Call(reviver, holder, name.Underlying(), val)
+}
+
+// jsonParser parses JSON text, as specified in ECMA-404, into ECMAScript
+// values of its realm.
+type jsonParser struct {
+	r     *Realm
+	input lang.String
+	pos   int
+}
+
+func (p *jsonParser) skipWhitespace() {
+	for p.pos < len(p.input) {
+		switch p.input[p.pos] {
+		case '\t', '\n', '\r', ' ':
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+// unexpected returns a SyntaxError for the code unit at the current
+// position.
+func (p *jsonParser) unexpected() errors.Error {
+	if p.pos >= len(p.input) {
+		return errors.NewSyntaxError("Unexpected end of JSON input")
+	}
+	return errors.NewSyntaxError("Unexpected token " + lang.String(p.input[p.pos:p.pos+1]).String() + " in JSON at position " + strconv.Itoa(p.pos))
+}
+
+// consume consumes the given ASCII text, which must follow at the current
+// position.
+func (p *jsonParser) consume(text string) errors.Error {
+	for i := 0; i < len(text); i++ {
+		if p.pos >= len(p.input) || p.input[p.pos] != uint16(text[i]) {
+			return p.unexpected()
+		}
+		p.pos++
+	}
+	return nil
+}
+
+func (p *jsonParser) parseValue() (lang.Value, errors.Error) {
+	if p.pos >= len(p.input) {
+		return nil, p.unexpected()
+	}
+	switch c := p.input[p.pos]; {
+	case c == '{' || c == '[':
+		if err := p.r.enter(); err != nil {
+			return nil, err
+		}
+		defer p.r.leave()
+		if c == '{' {
+			return p.parseObject()
+		}
+		return p.parseArray()
+	case c == '"':
+		s, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	case c == '-' || c >= '0' && c <= '9':
+		return p.parseNumber()
+	case c == 't':
+		return lang.True, p.consume("true")
+	case c == 'f':
+		return lang.False, p.consume("false")
+	case c == 'n':
+		return lang.Null, p.consume("null")
+	}
+	return nil, p.unexpected()
+}
+
+func (p *jsonParser) parseObject() (lang.Value, errors.Error) {
+	o := lang.ObjectCreate(p.r.intrinsic(IntrinsicNameObjectPrototype))
+	p.pos++ // {
+	p.skipWhitespace()
+	if p.pos < len(p.input) && p.input[p.pos] == '}' {
+		p.pos++
+		return o, nil
+	}
+	for {
+		if p.pos >= len(p.input) || p.input[p.pos] != '"' {
+			return nil, p.unexpected()
+		}
+		name, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		p.skipWhitespace()
+		if err := p.consume(":"); err != nil {
+			return nil, err
+		}
+		p.skipWhitespace()
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		_, _ = lang.CreateDataProperty(o, lang.NewStringOrSymbol(name), value)
+		p.skipWhitespace()
+		if p.pos < len(p.input) && p.input[p.pos] == '}' {
+			p.pos++
+			return o, nil
+		}
+		if err := p.consume(","); err != nil {
+			return nil, err
+		}
+		p.skipWhitespace()
+	}
+}
+
+func (p *jsonParser) parseArray() (lang.Value, errors.Error) {
+	var elements []lang.Value
+	p.pos++ // [
+	p.skipWhitespace()
+	if p.pos < len(p.input) && p.input[p.pos] == ']' {
+		p.pos++
+		return lang.CreateArrayFromList(p.r, elements), nil
+	}
+	for {
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		elements = append(elements, value)
+		p.skipWhitespace()
+		if p.pos < len(p.input) && p.input[p.pos] == ']' {
+			p.pos++
+			return lang.CreateArrayFromList(p.r, elements), nil
+		}
+		if err := p.consume(","); err != nil {
+			return nil, err
+		}
+		p.skipWhitespace()
+	}
+}
+
+func (p *jsonParser) parseString() (lang.String, errors.Error) {
+	p.pos++ // "
+	var s lang.String
+	for {
+		if p.pos >= len(p.input) {
+			return nil, p.unexpected()
+		}
+		c := p.input[p.pos]
+		switch {
+		case c == '"':
+			p.pos++
+			return s, nil
+		case c < 0x20:
+			return nil, p.unexpected()
+		case c != '\\':
+			s = append(s, c)
+			p.pos++
+			continue
+		}
+
+		p.pos++ // backslash
+		if p.pos >= len(p.input) {
+			return nil, p.unexpected()
+		}
+		switch p.input[p.pos] {
+		case '"', '\\', '/':
+			s = append(s, p.input[p.pos])
+		case 'b':
+			s = append(s, '\b')
+		case 'f':
+			s = append(s, '\f')
+		case 'n':
+			s = append(s, '\n')
+		case 'r':
+			s = append(s, '\r')
+		case 't':
+			s = append(s, '\t')
+		case 'u':
+			var u uint16
+			for i := 0; i < 4; i++ {
+				p.pos++
+				if p.pos >= len(p.input) || p.input[p.pos] > 0x7f {
+					return nil, p.unexpected()
+				}
+				d := digitValue(byte(p.input[p.pos]))
+				if d >= 16 {
+					return nil, p.unexpected()
+				}
+				u = u<<4 | uint16(d)
+			}
+			s = append(s, u)
+		default:
+			return nil, p.unexpected()
+		}
+		p.pos++
+	}
+}
+
+func (p *jsonParser) parseNumber() (lang.Value, errors.Error) {
+	start := p.pos
+	digits := func() int {
+		n := 0
+		for p.pos < len(p.input) && p.input[p.pos] >= '0' && p.input[p.pos] <= '9' {
+			p.pos++
+			n++
+		}
+		return n
+	}
+
+	if p.input[p.pos] == '-' {
+		p.pos++
+	}
+	if p.pos < len(p.input) && p.input[p.pos] == '0' {
+		p.pos++
+	} else if digits() == 0 {
+		return nil, p.unexpected()
+	}
+	if p.pos < len(p.input) && p.input[p.pos] == '.' {
+		p.pos++
+		if digits() == 0 {
+			return nil, p.unexpected()
+		}
+	}
+	if p.pos < len(p.input) && (p.input[p.pos] == 'e' || p.input[p.pos] == 'E') {
+		p.pos++
+		if p.pos < len(p.input) && (p.input[p.pos] == '+' || p.input[p.pos] == '-') {
+			p.pos++
+		}
+		if digits() == 0 {
+			return nil, p.unexpected()
+		}
+	}
+
+	f, _ := strconv.ParseFloat(p.input[start:p.pos].String(), 64) // overflows to ±Inf, as in StringToNumber
+	return number(f), nil
+}
+
+// jsonState holds the state of a call to JSON.stringify.
+// JSON.stringify is specified in 24.5.2.
+type jsonState struct {
+	r                *Realm
+	replacerFunction *lang.Object
+	stack            []*lang.Object
+	indent           lang.String
+	gap              lang.String
+	propertyList     []lang.StringOrSymbol
+	hasPropertyList  bool
+}
+
+// jsonStringify is JSON.stringify.
+// JSON.stringify is specified in 24.5.2.
+func (r *Realm) jsonStringify(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+	state := &jsonState{r: r}
+
+	if replacer, ok := argument(args, 1).(*lang.Object); ok {
+		if lang.InternalIsCallable(replacer) {
+			state.replacerFunction = replacer
+		} else if lang.InternalIsArray(replacer) {
+			if err := state.setPropertyList(replacer); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	space := argument(args, 2)
+	if o, ok := space.(*lang.Object); ok {
+		var err errors.Error
+		if o.HasSlot(lang.SlotNumberData) {
+			space, err = lang.ToNumber(space)
+		} else if o.HasSlot(lang.SlotStringData) {
+			space, err = lang.ToString(space)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	switch space := space.(type) {
+	case lang.Number:
+		n, _ := toInteger(space)
+		for i := 0; i < int(math.Min(10, n)); i++ {
+			state.gap = append(state.gap, ' ')
+		}
+	case lang.String:
+		if len(space) > 10 {
+			space = space[:10]
+		}
+		state.gap = space
+	}
+
+	wrapper := lang.ObjectCreate(r.intrinsic(IntrinsicNameObjectPrototype))
+	_, _ = lang.CreateDataProperty(wrapper, key(""), argument(args, 0))
+	result, ok, err := state.serializeProperty(key(""), wrapper)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return lang.Undefined, nil
+	}
+	return result, nil
+}
+
+// setPropertyList sets the property list of the state to the names that
+// are given by the elements of the replacer array.
+func (s *jsonState) setPropertyList(replacer *lang.Object) errors.Error {
+	s.hasPropertyList = true
+	length, err := lengthOfArrayLike(replacer)
+	if err != nil {
+		return err
+	}
+	seen := make(map[string]bool)
+	for k := 0.0; k < length; k++ {
+		s.r.work(1)
+		v, err := get(replacer, indexKey(k))
+		if err != nil {
+			return err
+		}
+		var item lang.Value
+		switch v := v.(type) {
+		case lang.String, lang.Number:
+			item = v
+		case *lang.Object:
+			if v.HasSlot(lang.SlotStringData) || v.HasSlot(lang.SlotNumberData) {
+				item = v
+			}
+		}
+		if item == nil {
+			continue
+		}
+		name, err := lang.ToString(item)
+		if err != nil {
+			return err
+		}
+		if k := mapKey(name).(string); !seen[k] {
+			seen[k] = true
+			s.propertyList = append(s.propertyList, lang.NewStringOrSymbol(name))
+		}
+	}
+	return nil
+}
+
+// serializeProperty returns the JSON text of the property with the given
+// key of the holder, or false if the property is not serializable.
+// SerializeJSONProperty is specified in 24.5.2.1.
+func (s *jsonState) serializeProperty(name lang.StringOrSymbol, holder *lang.Object) (lang.String, bool, errors.Error) {
+	value, err := get(holder, name)
+	if err != nil {
+		return nil, false, err
+	}
+	if o, ok := value.(*lang.Object); ok {
+		toJSON, err := get(o, key("toJSON"))
+		if err != nil {
+			return nil, false, err
+		}
+		if lang.InternalIsCallable(toJSON) {
+			if value, err = lang.Call(toJSON.(*lang.Object), value, name.Underlying()); err != nil {
+				return nil, false, err
+			}
+		}
+	}
+	if s.replacerFunction != nil {
+		if value, err = lang.Call(s.replacerFunction, holder, name.Underlying(), value); err != nil {
+			return nil, false, err
+		}
+	}
+
+	if o, ok := value.(*lang.Object); ok {
+		switch {
+		case o.HasSlot(lang.SlotNumberData):
+			value, err = lang.ToNumber(value)
+		case o.HasSlot(lang.SlotStringData):
+			value, err = lang.ToString(value)
+		case o.HasSlot(lang.SlotBooleanData):
+			value = o.GetSlot(lang.SlotBooleanData)
+		}
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	if value == lang.Null {
+		return lang.NewString("null"), true, nil
+	}
+	switch value := value.(type) {
+	case lang.Boolean:
+		if value {
+			return lang.NewString("true"), true, nil
+		}
+		return lang.NewString("false"), true, nil
+	case lang.String:
+		return quoteJSONString(nil, value), true, nil
+	case lang.Number:
+		if f := value.Float64(); math.IsNaN(f) || math.IsInf(f, 0) {
+			return lang.NewString("null"), true, nil
+		}
+		return lang.NumberToString(value), true, nil
+	case *lang.Object:
+		if lang.InternalIsCallable(value) {
+			return nil, false, nil
+		}
+		if lang.InternalIsArray(value) {
+			result, err := s.serializeArray(value)
+			return result, err == nil, err
+		}
+		result, err := s.serializeObject(value)
+		return result, err == nil, err
+	}
+	return nil, false, nil
+}
+
+// quoteJSONString appends the given string as JSON string literal to dst.
+// Lone surrogates are escaped.
+// QuoteJSONString is specified in 24.5.2.2.
+func quoteJSONString(dst, s lang.String) lang.String {
+	const hex = "0123456789abcdef"
+	escape := func(c uint16) {
+		dst = append(dst, '\\', 'u', uint16(hex[c>>12]), uint16(hex[c>>8&0xf]), uint16(hex[c>>4&0xf]), uint16(hex[c&0xf]))
+	}
+
+	dst = append(dst, '"')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch c {
+		case '\b':
+			dst = append(dst, '\\', 'b')
+		case '\t':
+			dst = append(dst, '\\', 't')
+		case '\n':
+			dst = append(dst, '\\', 'n')
+		case '\f':
+			dst = append(dst, '\\', 'f')
+		case '\r':
+			dst = append(dst, '\\', 'r')
+		case '"', '\\':
+			dst = append(dst, '\\', c)
+		default:
+			switch {
+			case c < 0x20:
+				escape(c)
+			case c >= 0xd800 && c <= 0xdbff && i+1 < len(s) && s[i+1] >= 0xdc00 && s[i+1] <= 0xdfff:
+				dst = append(dst, c, s[i+1])
+				i++
+			case c >= 0xd800 && c <= 0xdfff:
+				escape(c)
+			default:
+				dst = append(dst, c)
+			}
+		}
+	}
+	return append(dst, '"')
+}
+
+// enter pushes the given object on the stack, and returns a TypeError if
+// the object is already on it. The nesting is counted against the maximum
+// call depth of the realm, see Realm#CallStack. Every successful enter must
+// be followed by a call of leave.
+func (s *jsonState) enter(o *lang.Object) errors.Error {
+	if err := s.r.enter(); err != nil {
+		return err
+	}
+	for _, e := range s.stack {
+		if e == o {
+			s.r.leave()
+			return errors.NewTypeError("Converting circular structure to JSON")
+		}
+	}
+	s.stack = append(s.stack, o)
+	return nil
+}
+
+// leave pops the object that was pushed by enter from the stack.
+func (s *jsonState) leave() {
+	s.stack = s.stack[:len(s.stack)-1]
+	s.r.leave()
+}
+
+// join joins the given serialized properties or elements with the given
+// brackets, using the gap and indentation of the state.
+func (s *jsonState) join(partial []lang.String, stepback lang.String, open, close uint16) lang.String {
+	result := lang.String{open}
+	if len(partial) == 0 {
+		return append(result, close)
+	}
+	for i, p := range partial {
+		if i > 0 {
+			result = append(result, ',')
+		}
+		if len(s.gap) > 0 {
+			result = append(result, '\n')
+			result = append(result, s.indent...)
+		}
+		result = append(result, p...)
+	}
+	if len(s.gap) > 0 {
+		result = append(result, '\n')
+		result = append(result, stepback...)
+	}
+	return append(result, close)
+}
+
+// serializeObject returns the JSON text of the given object.
+// SerializeJSONObject is specified in 24.5.2.3.
+func (s *jsonState) serializeObject(value *lang.Object) (lang.String, errors.Error) {
+	if err := s.enter(value); err != nil {
+		return nil, err
+	}
+	defer s.leave()
+	stepback := s.indent
+	s.indent = append(append(lang.String{}, s.indent...), s.gap...)
+
+	keys := s.propertyList
+	if !s.hasPropertyList {
+		names, err := lang.EnumerableOwnPropertyNames(s.r, value, lang.EnumerateKey)
+		if err != nil {
+			return nil, err
+		}
+		keys = make([]lang.StringOrSymbol, len(names))
+		for i, n := range names {
+			keys[i] = lang.NewStringOrSymbol(n)
+		}
+	}
+
+	var partial []lang.String
+	for _, p := range keys {
+		strP, ok, err := s.serializeProperty(p, value)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		member := quoteJSONString(nil, p.String())
+		member = append(member, ':')
+		if len(s.gap) > 0 {
+			member = append(member, ' ')
+		}
+		partial = append(partial, append(member, strP...))
+	}
+
+	result := s.join(partial, stepback, '{', '}')
+	s.indent = stepback
+	return result, nil
+}
+
+// serializeArray returns the JSON text of the given array.
+// SerializeJSONArray is specified in 24.5.2.4.
+func (s *jsonState) serializeArray(value *lang.Object) (lang.String, errors.Error) {
+	if err := s.enter(value); err != nil {
+		return nil, err
+	}
+	defer s.leave()
+	stepback := s.indent
+	s.indent = append(append(lang.String{}, s.indent...), s.gap...)
+
+	length, err := lengthOfArrayLike(value)
+	if err != nil {
+		return nil, err
+	}
+	var partial []lang.String
+	for index := 0.0; index < length; index++ {
+		s.r.work(1)
+		strP, ok, err := s.serializeProperty(indexKey(index), value)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			strP = lang.NewString("null")
+		}
+		partial = append(partial, strP)
+	}
+
+	result := s.join(partial, stepback, '[', ']')
+	s.indent = stepback
+	return result, nil
+}
@@ -0,0 +1,426 @@
+package realm
+
+import (
+	"math"
+
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+)
+
+// Internal slots of Map objects and Map iterators, as specified in 23.1.3.1
+// and 23.1.5.3.
+var (
+	slotMapData              = lang.NewStringKey("MapData")
+	slotMap                  = lang.NewStringKey("Map")
+	slotMapNextIndex         = lang.NewStringKey("MapNextIndex")
+	slotMapIterationKind     = lang.NewStringKey("MapIterationKind")
+	slotSetData              = lang.NewStringKey("SetData")
+	slotIteratedSet          = lang.NewStringKey("IteratedSet")
+	slotSetIterationKind     = lang.NewStringKey("SetIterationKind")
+	slotSetIteratorNextIndex = lang.NewStringKey("SetNextIndex")
+)
+
+// orderedMap is the List of Records of Map and Set objects, that holds the
+// entries in insertion order. Deleted entries are unlinked from the list,
+// but keep their predecessor, so that iterators that are positioned on a
+// deleted entry can continue with the entries that follow it.
+type orderedMap struct {
+	entries     map[interface{}]*mapEntry
+	first, last *mapEntry
+	size        int
+	// heap is the heap that the entries are accounted to, and bytes the
+	// number of bytes that they use on it.
+	heap  *lang.Heap
+	bytes int64
+	// isSet is true for the entries of Set objects, whose value is their
+	// key.
+	isSet bool
+}
+
+// mapEntry is an entry of an orderedMap.
+type mapEntry struct {
+	key, value lang.Value
+	next, prev *mapEntry
+	deleted    bool
+}
+
+// newOrderedMap creates a new, empty orderedMap of a Map or a Set object,
+// whose entries are accounted to the given heap.
+func newOrderedMap(heap *lang.Heap, isSet bool) *orderedMap {
+	return &orderedMap{entries: make(map[interface{}]*mapEntry), heap: heap, isSet: isSet}
+}
+
+// Type returns lang.TypeInternal.
+func (*orderedMap) Type() lang.Type { return lang.TypeInternal }
+
+// Value returns the map itself.
+func (m *orderedMap) Value() interface{} { return m }
+
+// nanKey is the map key of NaN, which is not equal to itself as float64.
+type nanKey struct{}
+
+// mapKey returns the Go map key of the given value, such that two values
+// have the same key if they are equal according to SameValueZero.
+func mapKey(v lang.Value) interface{} {
+	switch v := v.(type) {
+	case lang.Number:
+		f := v.Float64()
+		if math.IsNaN(f) {
+			return nanKey{}
+		}
+		return f + 0 // +0 converts -0 to +0
+	case lang.String:
+		b := make([]byte, 2*len(v))
+		for i, c := range v {
+			b[2*i], b[2*i+1] = byte(c>>8), byte(c)
+		}
+		return string(b)
+	case lang.Boolean:
+		return bool(v)
+	}
+	return v
+}
+
+// get returns the entry with the given key, or nil if there is no such
+// entry.
+func (m *orderedMap) get(key lang.Value) *mapEntry {
+	return m.entries[mapKey(key)]
+}
+
+// set sets the value of the entry with the given key, or appends a new
+// entry if there is no such entry. -0 keys are normalized to +0. An error
+// is returned if the heap cannot hold the entry.
+func (m *orderedMap) set(key, value lang.Value) errors.Error {
+	if e := m.get(key); e != nil {
+		if err := m.account(m.entrySize(key, e.value), m.entrySize(key, value)); err != nil {
+			return err
+		}
+		e.value = value
+		return nil
+	}
+	if err := m.account(0, m.entrySize(key, value)); err != nil {
+		return err
+	}
+	if n, ok := key.(lang.Number); ok && n.IsNegZero() {
+		key = lang.Zero
+	}
+	e := &mapEntry{key: key, value: value, prev: m.last}
+	if m.last != nil {
+		m.last.next = e
+	} else {
+		m.first = e
+	}
+	m.last = e
+	m.entries[mapKey(key)] = e
+	m.size++
+	return nil
+}
+
+// entrySize returns the number of bytes that an entry with the given key
+// and value uses. The value of Set entries is not counted, since it is the
+// key.
+func (m *orderedMap) entrySize(key, value lang.Value) int64 {
+	if m.isSet {
+		return lang.EntrySize(key, lang.Undefined)
+	}
+	return lang.EntrySize(key, value)
+}
+
+// account accounts the change of the size of an entry from before to after
+// bytes on the heap.
+func (m *orderedMap) account(before, after int64) errors.Error {
+	if after > before {
+		if err := m.heap.Allocate(after - before); err != nil {
+			return err
+		}
+	} else {
+		m.heap.Release(before - after)
+	}
+	m.bytes += after - before
+	return nil
+}
+
+// delete removes the entry with the given key, and reports whether there
+// was such an entry.
+func (m *orderedMap) delete(key lang.Value) bool {
+	e := m.get(key)
+	if e == nil {
+		return false
+	}
+	delete(m.entries, mapKey(key))
+	m.unlink(e)
+	_ = m.account(m.entrySize(e.key, e.value), 0)
+	return true
+}
+
+// unlink removes the entry from the list.
+func (m *orderedMap) unlink(e *mapEntry) {
+	e.deleted = true
+	if e.prev != nil {
+		e.prev.next = e.next
+	} else {
+		m.first = e.next
+	}
+	if e.next != nil {
+		e.next.prev = e.prev
+	} else {
+		m.last = e.prev
+	}
+	m.size--
+}
+
+// clear removes all entries.
+func (m *orderedMap) clear() {
+	for e := m.first; e != nil; e = e.next {
+		e.deleted = true
+		e.prev = nil
+	}
+	m.entries = make(map[interface{}]*mapEntry)
+	m.first, m.last, m.size = nil, nil, 0
+	m.heap.Release(m.bytes)
+	m.bytes = 0
+}
+
+// forEach calls fn with every entry, including entries that are added by
+// fn, until fn returns an error.
+func (m *orderedMap) forEach(fn func(e *mapEntry) errors.Error) errors.Error {
+	it := &mapIterator{m: m}
+	for e := it.next(); e != nil; e = it.next() {
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mapIterator is the position of a Map or Set iterator in an orderedMap.
+type mapIterator struct {
+	m       *orderedMap
+	current *mapEntry // the entry that was returned last, or nil
+	started bool
+}
+
+// Type returns lang.TypeInternal.
+func (*mapIterator) Type() lang.Type { return lang.TypeInternal }
+
+// Value returns the iterator itself.
+func (it *mapIterator) Value() interface{} { return it }
+
+// next returns the next entry, or nil if there are no more entries.
+func (it *mapIterator) next() *mapEntry {
+	var e *mapEntry
+	switch {
+	case !it.started:
+		it.started = true
+		e = it.m.first
+	case it.current == nil:
+	case it.current.deleted:
+		// continue after the last entry before the current one, that has
+		// not been deleted since
+		p := it.current.prev
+		for p != nil && p.deleted {
+			p = p.prev
+		}
+		if p == nil {
+			e = it.m.first
+		} else {
+			e = p.next
+		}
+	default:
+		e = it.current.next
+	}
+	// once the iterator is exhausted, current is nil and it never
+	// produces entries again
+	it.current = e
+	return e
+}
+
+// thisMapData returns the entries of the given Map object.
+func thisMapData(v lang.Value, slot lang.StringOrSymbol, method string) (*orderedMap, errors.Error) {
+	if o, ok := v.(*lang.Object); ok && o.HasSlot(slot) {
+		return o.GetSlot(slot).(*orderedMap), nil
+	}
+	return nil, errors.NewTypeError("Method " + method + " called on incompatible receiver")
+}
+
+// createMap creates the Map constructor, %MapPrototype% and
+// %MapIteratorPrototype%, as specified in 23.1.
+func (r *Realm) createMap() {
+	mapProto := lang.ObjectCreate(r.intrinsic(IntrinsicNameObjectPrototype))
+	r.Intrinsics.SetField(IntrinsicNameMapPrototype, mapProto)
+
+	mapConstructor := r.newConstructor("Map", 0, mapProto, func(newTarget lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		nt, ok := newTarget.(*lang.Object)
+		if !ok {
+			return nil, errors.NewTypeError("Constructor Map requires 'new'")
+		}
+		m, err := OrdinaryCreateFromConstructor(nt, IntrinsicNameMapPrototype, slotMapData)
+		if err != nil {
+			return nil, err
+		}
+		m.SetSlot(slotMapData, newOrderedMap(m.Heap(), false))
+		if iterable := argument(args, 0); iterable != lang.Undefined && iterable != lang.Null {
+			if err := r.addEntriesFromIterable(m, iterable, "set"); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	})
+	r.Intrinsics.SetField(IntrinsicNameMap, mapConstructor)
+	r.defineSpecies(mapConstructor)
+
+	r.defineFunction(mapProto, "clear", 0, func(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+		m, err := thisMapData(this, slotMapData, "Map.prototype.clear")
+		if err != nil {
+			return nil, err
+		}
+		m.clear()
+		return lang.Undefined, nil
+	})
+	r.defineFunction(mapProto, "delete", 1, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		m, err := thisMapData(this, slotMapData, "Map.prototype.delete")
+		if err != nil {
+			return nil, err
+		}
+		return lang.Boolean(m.delete(argument(args, 0))), nil
+	})
+	entries := r.defineFunction(mapProto, "entries", 0, r.mapIteratorFunction(lang.EnumerateKeyPlusValue))
+	r.defineFunction(mapProto, "forEach", 1, r.collectionForEach(slotMapData, "Map.prototype.forEach", false))
+	r.defineFunction(mapProto, "get", 1, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		m, err := thisMapData(this, slotMapData, "Map.prototype.get")
+		if err != nil {
+			return nil, err
+		}
+		if e := m.get(argument(args, 0)); e != nil {
+			return e.value, nil
+		}
+		return lang.Undefined, nil
+	})
+	r.defineFunction(mapProto, "has", 1, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		m, err := thisMapData(this, slotMapData, "Map.prototype.has")
+		if err != nil {
+			return nil, err
+		}
+		return lang.Boolean(m.get(argument(args, 0)) != nil), nil
+	})
+	r.defineFunction(mapProto, "keys", 0, r.mapIteratorFunction(lang.EnumerateKey))
+	r.defineFunction(mapProto, "set", 2, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		m, err := thisMapData(this, slotMapData, "Map.prototype.set")
+		if err != nil {
+			return nil, err
+		}
+		if err := m.set(argument(args, 0), argument(args, 1)); err != nil {
+			return nil, err
+		}
+		return this, nil
+	})
+	r.defineGetter(mapProto, key("size"), func(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+		m, err := thisMapData(this, slotMapData, "get Map.prototype.size")
+		if err != nil {
+			return nil, err
+		}
+		return number(float64(m.size)), nil
+	})
+	r.defineFunction(mapProto, "values", 0, r.mapIteratorFunction(lang.EnumerateValue))
+	_, _ = lang.CreateMethodProperty(mapProto, symbolKey(lang.SymbolIterator), entries)
+	defineToStringTag(mapProto, "Map")
+
+	mapIterProto := lang.ObjectCreate(r.intrinsic(IntrinsicNameIteratorPrototype))
+	r.defineFunction(mapIterProto, "next", 0, func(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+		return r.collectionIteratorNext(this, slotMapNextIndex, slotMapIterationKind, "Map Iterator")
+	})
+	defineToStringTag(mapIterProto, "Map Iterator")
+	r.Intrinsics.SetField(IntrinsicNameMapIteratorPrototype, mapIterProto)
+}
+
+// addEntriesFromIterable calls the adder method with the given name of the
+// target for every entry of the iterable, which must be objects, whose
+// properties "0" and "1" are the key and the value.
+// AddEntriesFromIterable is specified in 23.1.1.1.
+func (r *Realm) addEntriesFromIterable(target *lang.Object, iterable lang.Value, adderName string) errors.Error {
+	adderValue, err := get(target, key(adderName))
+	if err != nil {
+		return err
+	}
+	adder, err := callable(adderValue, "'"+adderName+"' of the target")
+	if err != nil {
+		return err
+	}
+	return r.iterate(iterable, func(next lang.Value) errors.Error {
+		k, v, err := entryKeyValue(next)
+		if err != nil {
+			return err
+		}
+		_, err = lang.Call(adder, target, k, v)
+		return err
+	})
+}
+
+// collectionForEach returns the implementation of Map.prototype.forEach or
+// Set.prototype.forEach, which calls the callback with the value, the key
+// and the collection for each entry. The key of Set entries is the value.
+// These functions are specified in 23.1.3.5 and 23.2.3.6.
+func (r *Realm) collectionForEach(slot lang.StringOrSymbol, method string, isSet bool) BuiltinFunction {
+	return func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		m, err := thisMapData(this, slot, method)
+		if err != nil {
+			return nil, err
+		}
+		callback, err := callable(argument(args, 0), method+": callback")
+		if err != nil {
+			return nil, err
+		}
+		err = m.forEach(func(e *mapEntry) errors.Error {
+			value := e.value
+			if isSet {
+				value = e.key
+			}
+			_, err := lang.Call(callback, argument(args, 1), value, e.key, this)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		return lang.Undefined, nil
+	}
+}
+
+// mapIteratorFunction returns the implementation of Map.prototype.keys,
+// values or entries, depending on the given kind.
+// CreateMapIterator is specified in 23.1.5.1.
+func (r *Realm) mapIteratorFunction(kind string) BuiltinFunction {
+	return func(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+		m, err := thisMapData(this, slotMapData, "Map Iterator")
+		if err != nil {
+			return nil, err
+		}
+		iterator := lang.ObjectCreate(r.intrinsic(IntrinsicNameMapIteratorPrototype), slotMap, slotMapNextIndex, slotMapIterationKind)
+		iterator.SetSlot(slotMap, this)
+		iterator.SetSlot(slotMapNextIndex, &mapIterator{m: m})
+		iterator.SetSlot(slotMapIterationKind, lang.NewString(kind))
+		return iterator, nil
+	}
+}
+
+// collectionIteratorNext is the next method of %MapIteratorPrototype% and
+// %SetIteratorPrototype%, which returns the next key, value or entry of the
+// iterated collection.
+// These functions are specified in 23.1.5.2.1 and 23.2.5.2.1.
+func (r *Realm) collectionIteratorNext(this lang.Value, positionSlot, kindSlot lang.StringOrSymbol, name string) (lang.Value, errors.Error) {
+	o, ok := this.(*lang.Object)
+	if !ok || !o.HasSlot(positionSlot) {
+		return nil, errors.NewTypeError(name + " next method called on incompatible receiver")
+	}
+	e := o.GetSlot(positionSlot).(*mapIterator).next()
+	if e == nil {
+		return lang.CreateIterResultObject(r, lang.Undefined, true), nil
+	}
+	switch o.GetSlot(kindSlot).(lang.String).String() {
+	case lang.EnumerateKey:
+		return lang.CreateIterResultObject(r, e.key, false), nil
+	case lang.EnumerateValue:
+		return lang.CreateIterResultObject(r, e.value, false), nil
+	}
+	entry := lang.CreateArrayFromList(r, []lang.Value{e.key, e.value})
+	return lang.CreateIterResultObject(r, entry, false), nil
+}
@@ -0,0 +1,241 @@
+package realm
+
+import (
+	"math"
+	"math/bits"
+	"math/rand"
+
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+)
+
+// mathFunctions holds the functions of the Math object that take a single
+// number argument, as specified in 20.2.2.
+var mathFunctions = []struct {
+	name string
+	fn   func(float64) float64
+}{
+	{"abs", math.Abs},
+	{"acos", math.Acos},
+	{"acosh", math.Acosh},
+	{"asin", math.Asin},
+	{"asinh", math.Asinh},
+	{"atan", math.Atan},
+	{"atanh", math.Atanh},
+	{"cbrt", math.Cbrt},
+	{"ceil", math.Ceil},
+	{"cos", math.Cos},
+	{"cosh", math.Cosh},
+	{"exp", math.Exp},
+	{"expm1", math.Expm1},
+	{"floor", math.Floor},
+	{"fround", func(x float64) float64 { return float64(float32(x)) }},
+	{"log", math.Log},
+	{"log1p", math.Log1p},
+	{"log10", math.Log10},
+	{"log2", math.Log2},
+	{"round", mathRound},
+	{"sign", mathSign},
+	{"sin", math.Sin},
+	{"sinh", math.Sinh},
+	{"sqrt", math.Sqrt},
+	{"tan", math.Tan},
+	{"tanh", math.Tanh},
+	{"trunc", math.Trunc},
+}
+
+// createMath creates the Math object, as specified in 20.2.
+func (r *Realm) createMath() {
+	m := lang.ObjectCreate(r.intrinsic(IntrinsicNameObjectPrototype))
+	r.Intrinsics.SetField(IntrinsicNameMath, m)
+
+	defineValue(m, "E", lang.NewNumber(math.E))
+	defineValue(m, "LN10", lang.NewNumber(math.Ln10))
+	defineValue(m, "LN2", lang.NewNumber(math.Ln2))
+	defineValue(m, "LOG10E", lang.NewNumber(math.Log10E))
+	defineValue(m, "LOG2E", lang.NewNumber(math.Log2E))
+	defineValue(m, "PI", lang.NewNumber(math.Pi))
+	defineValue(m, "SQRT1_2", lang.NewNumber(math.Sqrt2/2))
+	defineValue(m, "SQRT2", lang.NewNumber(math.Sqrt2))
+	defineToStringTag(m, "Math")
+
+	for _, f := range mathFunctions {
+		fn := f.fn
+		r.defineFunction(m, f.name, 1, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+			x, err := toNumber(argument(args, 0))
+			if err != nil {
+				return nil, err
+			}
+			return number(fn(x)), nil
+		})
+	}
+
+	r.defineFunction(m, "atan2", 2, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		y, x, err := twoNumbers(args)
+		if err != nil {
+			return nil, err
+		}
+		return number(math.Atan2(y, x)), nil
+	})
+	r.defineFunction(m, "clz32", 1, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		n, err := lang.ToUint32(argument(args, 0))
+		if err != nil {
+			return nil, err
+		}
+		return number(float64(bits.LeadingZeros32(uint32(n.Float64())))), nil
+	})
+	r.defineFunction(m, "hypot", 2, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		values, err := numbers(args)
+		if err != nil {
+			return nil, err
+		}
+		return number(mathHypot(values)), nil
+	})
+	r.defineFunction(m, "imul", 2, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		a, err := lang.ToUint32(argument(args, 0))
+		if err != nil {
+			return nil, err
+		}
+		b, err := lang.ToUint32(argument(args, 1))
+		if err != nil {
+			return nil, err
+		}
+		return number(float64(int32(uint32(a.Float64()) * uint32(b.Float64())))), nil
+	})
+	r.defineFunction(m, "max", 2, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		values, err := numbers(args)
+		if err != nil {
+			return nil, err
+		}
+		result := math.Inf(-1)
+		for _, v := range values {
+			if math.IsNaN(v) {
+				return lang.NaN, nil
+			}
+			if v > result || (v == 0 && result == 0 && !math.Signbit(v)) {
+				result = v
+			}
+		}
+		return number(result), nil
+	})
+	r.defineFunction(m, "min", 2, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		values, err := numbers(args)
+		if err != nil {
+			return nil, err
+		}
+		result := math.Inf(1)
+		for _, v := range values {
+			if math.IsNaN(v) {
+				return lang.NaN, nil
+			}
+			if v < result || (v == 0 && result == 0 && math.Signbit(v)) {
+				result = v
+			}
+		}
+		return number(result), nil
+	})
+	r.defineFunction(m, "pow", 2, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		base, exponent, err := twoNumbers(args)
+		if err != nil {
+			return nil, err
+		}
+		return number(exponentiate(base, exponent)), nil
+	})
+	r.defineFunction(m, "random", 0, func(lang.Value, ...lang.Value) (lang.Value, errors.Error) {
+		return number(rand.Float64()), nil
+	})
+}
+
+// numbers converts all given values to numbers.
+func numbers(args []lang.Value) ([]float64, errors.Error) {
+	values := make([]float64, len(args))
+	for i, arg := range args {
+		v, err := toNumber(arg)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+// twoNumbers converts the first two arguments to numbers.
+func twoNumbers(args []lang.Value) (float64, float64, errors.Error) {
+	x, err := toNumber(argument(args, 0))
+	if err != nil {
+		return 0, 0, err
+	}
+	y, err := toNumber(argument(args, 1))
+	if err != nil {
+		return 0, 0, err
+	}
+	return x, y, nil
+}
+
+// exponentiate returns base raised to the power of exponent. Unlike
+// math.Pow, the result is NaN for an exponent of NaN, and for a base of +-1
+// with an infinite exponent.
+// Applying the ** operator is specified in 12.6.4.
+func exponentiate(base, exponent float64) float64 {
+	if math.IsNaN(exponent) || (math.Abs(base) == 1 && math.IsInf(exponent, 0)) {
+		return math.NaN()
+	}
+	return math.Pow(base, exponent)
+}
+
+// mathRound is Math.round, which rounds to the closest integer, and rounds
+// up if there are two closest integers.
+// Math.round is specified in 20.2.2.28.
+func mathRound(x float64) float64 {
+	if math.IsNaN(x) || math.IsInf(x, 0) || x == 0 {
+		return x
+	}
+	if x < 0 && x >= -0.5 {
+		return math.Copysign(0, -1)
+	}
+	r := math.Floor(x)
+	if x-r >= 0.5 {
+		r++
+	}
+	return r
+}
+
+// mathSign is Math.sign.
+// Math.sign is specified in 20.2.2.29.
+func mathSign(x float64) float64 {
+	switch {
+	case math.IsNaN(x) || x == 0:
+		return x
+	case x < 0:
+		return -1
+	}
+	return 1
+}
+
+// mathHypot is Math.hypot.
+// Math.hypot is specified in 20.2.2.18.
+func mathHypot(values []float64) float64 {
+	max := 0.0
+	hasNaN := false
+	for _, v := range values {
+		if math.IsInf(v, 0) {
+			return math.Inf(1)
+		}
+		if math.IsNaN(v) {
+			hasNaN = true
+		}
+		max = math.Max(max, math.Abs(v))
+	}
+	if hasNaN {
+		return math.NaN()
+	}
+	if max == 0 {
+		return 0
+	}
+	// scale the values to avoid overflow and underflow
+	sum := 0.0
+	for _, v := range values {
+		sum += (v / max) * (v / max)
+	}
+	return math.Sqrt(sum) * max
+}
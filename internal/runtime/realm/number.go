@@ -0,0 +1,362 @@
+package realm
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+)
+
+const maxSafeInteger = 1<<53 - 1
+
+// createNumber creates the Number constructor and %NumberPrototype%, as
+// specified in 20.1. It must be called after the global functions are
+// created, since Number.parseFloat and Number.parseInt are the same
+// function objects as the global parseFloat and parseInt.
+func (r *Realm) createNumber() {
+	numberProto := lang.ObjectCreate(r.intrinsic(IntrinsicNameObjectPrototype), lang.SlotNumberData)
+	numberProto.SetSlot(lang.SlotNumberData, lang.Zero)
+	r.Intrinsics.SetField(IntrinsicNameNumberPrototype, numberProto)
+
+	number := r.newConstructor("Number", 1, numberProto, func(newTarget lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		n := lang.Zero
+		if len(args) > 0 {
+			var err errors.Error
+			if n, err = lang.ToNumber(args[0]); err != nil {
+				return nil, err
+			}
+		}
+		nt, ok := newTarget.(*lang.Object)
+		if !ok {
+			return n, nil
+		}
+		o, err := OrdinaryCreateFromConstructor(nt, IntrinsicNameNumberPrototype, lang.SlotNumberData)
+		if err != nil {
+			return nil, err
+		}
+		o.SetSlot(lang.SlotNumberData, n)
+		return o, nil
+	})
+	r.Intrinsics.SetField(IntrinsicNameNumber, number)
+
+	defineValue(number, "EPSILON", lang.NewNumber(math.Nextafter(1, 2)-1))
+	defineValue(number, "MAX_SAFE_INTEGER", lang.NewNumber(maxSafeInteger))
+	defineValue(number, "MAX_VALUE", lang.NewNumber(math.MaxFloat64))
+	defineValue(number, "MIN_SAFE_INTEGER", lang.NewNumber(-maxSafeInteger))
+	defineValue(number, "MIN_VALUE", lang.NewNumber(math.SmallestNonzeroFloat64))
+	defineValue(number, "NaN", lang.NaN)
+	defineValue(number, "NEGATIVE_INFINITY", lang.NegInfinity)
+	defineValue(number, "POSITIVE_INFINITY", lang.PosInfinity)
+	r.defineFunction(number, "isFinite", 1, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		n, ok := argument(args, 0).(lang.Number)
+		return lang.Boolean(ok && !n.IsNaN() && !math.IsInf(n.Float64(), 0)), nil
+	})
+	r.defineFunction(number, "isInteger", 1, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		return lang.IsInteger(argument(args, 0)), nil
+	})
+	r.defineFunction(number, "isNaN", 1, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		n, ok := argument(args, 0).(lang.Number)
+		return lang.Boolean(ok && n.IsNaN()), nil
+	})
+	r.defineFunction(number, "isSafeInteger", 1, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		v := argument(args, 0)
+		if !lang.InternalIsInteger(v) {
+			return lang.False, nil
+		}
+		return lang.Boolean(math.Abs(v.(lang.Number).Float64()) <= maxSafeInteger), nil
+	})
+	defineMethodProperty(number, "parseFloat", r.intrinsic(IntrinsicNameParseFloat))
+	defineMethodProperty(number, "parseInt", r.intrinsic(IntrinsicNameParseInt))
+
+	r.defineFunction(numberProto, "toExponential", 1, numberPrototypeToExponential)
+	r.defineFunction(numberProto, "toFixed", 1, numberPrototypeToFixed)
+	r.defineFunction(numberProto, "toLocaleString", 0, func(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+		x, err := thisNumberValue(this)
+		if err != nil {
+			return nil, err
+		}
+		return lang.NumberToString(x), nil
+	})
+	r.defineFunction(numberProto, "toPrecision", 1, numberPrototypeToPrecision)
+	r.defineFunction(numberProto, "toString", 1, numberPrototypeToString)
+	r.defineFunction(numberProto, "valueOf", 0, func(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+		return thisNumberValue(this)
+	})
+}
+
+// thisNumberValue returns the given value if it is a Number, or the value
+// of its NumberData internal slot if it is a Number object.
+// thisNumberValue is specified in 20.1.3.
+func thisNumberValue(v lang.Value) (lang.Number, errors.Error) {
+	switch v := v.(type) {
+	case lang.Number:
+		return v, nil
+	case *lang.Object:
+		if n, ok := v.GetSlot(lang.SlotNumberData).(lang.Number); ok {
+			return n, nil
+		}
+	}
+	return lang.Number{}, errors.NewTypeError("Number.prototype method called on incompatible receiver")
+}
+
+// numberPrototypeToString is Number.prototype.toString.
+// Number.prototype.toString is specified in 20.1.3.6.
+func numberPrototypeToString(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+	x, err := thisNumberValue(this)
+	if err != nil {
+		return nil, err
+	}
+	radix := 10.0
+	if r := argument(args, 0); r != lang.Undefined {
+		if radix, err = toInteger(r); err != nil {
+			return nil, err
+		}
+	}
+	if radix < 2 || radix > 36 {
+		return nil, errors.NewRangeError("toString() radix must be between 2 and 36")
+	}
+	f := x.Float64()
+	if radix == 10 || x.IsNaN() || math.IsInf(f, 0) || f == 0 {
+		return lang.NumberToString(x), nil
+	}
+	return str(numberToRadixString(f, int(radix))), nil
+}
+
+// numberToRadixString returns the representation of the given finite,
+// non-zero number in the given radix. The fraction has as many digits as
+// are needed to identify the number uniquely.
+func numberToRadixString(x float64, radix int) string {
+	const digits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+	neg := x < 0
+	if neg {
+		x = -x
+	}
+	integer := math.Floor(x)
+	fraction := x - integer
+	// delta is half the distance to the next number, the digits of the
+	// fraction are generated until they identify x uniquely
+	delta := math.Max(0.5*(math.Nextafter(x, math.Inf(1))-x), math.SmallestNonzeroFloat64)
+
+	var frac []byte
+	if fraction >= delta {
+		for {
+			fraction *= float64(radix)
+			delta *= float64(radix)
+			digit := int(fraction)
+			frac = append(frac, digits[digit])
+			fraction -= float64(digit)
+			if fraction > 0.5 || (fraction == 0.5 && digit&1 == 1) {
+				if fraction+delta > 1 {
+					// round up and propagate the carry
+					for {
+						i := len(frac) - 1
+						if i < 0 {
+							integer++
+							break
+						}
+						d := strings.IndexByte(digits, frac[i]) + 1
+						frac = frac[:i]
+						if d < radix {
+							frac = append(frac, digits[d])
+							break
+						}
+					}
+					break
+				}
+			}
+			if fraction < delta {
+				break
+			}
+		}
+	}
+
+	intPart, _ := new(big.Float).SetFloat64(integer).Int(nil)
+	s := intPart.Text(radix)
+	if len(frac) > 0 {
+		s += "." + string(frac)
+	}
+	if neg {
+		s = "-" + s
+	}
+	return s
+}
+
+// numberPrototypeToFixed is Number.prototype.toFixed.
+// Number.prototype.toFixed is specified in 20.1.3.3.
+func numberPrototypeToFixed(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+	x, err := thisNumberValue(this)
+	if err != nil {
+		return nil, err
+	}
+	f, err := toInteger(argument(args, 0))
+	if err != nil {
+		return nil, err
+	}
+	if f < 0 || f > 100 {
+		return nil, errors.NewRangeError("toFixed() digits argument must be between 0 and 100")
+	}
+	v := x.Float64()
+	if x.IsNaN() || math.Abs(v) >= 1e21 {
+		return lang.NumberToString(x), nil
+	}
+
+	sign := ""
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+	// n is an integer for which n / 10^f - x is as close to zero as
+	// possible, the larger n is chosen if there are two such n
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(f)), nil)
+	product := new(big.Float).SetPrec(2048).SetFloat64(v)
+	product.Mul(product, new(big.Float).SetPrec(2048).SetInt(scale))
+	product.Add(product, big.NewFloat(0.5))
+	n, _ := product.Int(nil)
+
+	m := n.String()
+	digits := int(f)
+	if digits != 0 {
+		if len(m) <= digits {
+			m = strings.Repeat("0", digits+1-len(m)) + m
+		}
+		m = m[:len(m)-digits] + "." + m[len(m)-digits:]
+	}
+	return str(sign + m), nil
+}
+
+// roundedDigits returns the decimal digits of the given finite, positive
+// number rounded to the given number of significant digits, and the
+// exponent e, such that the number is approximately d.ddd * 10^e. If the
+// number lies exactly between two possible results, it is rounded up.
+func roundedDigits(x float64, precision int) (string, int) {
+	// 767 digits after the point represent every float64 exactly
+	exact := strconv.FormatFloat(x, 'e', 767, 64)
+	mark := strings.IndexByte(exact, 'e')
+	e, _ := strconv.Atoi(exact[mark+1:])
+	digits := []byte(exact[:1] + exact[2:mark])
+
+	if digits[precision] < '5' {
+		return string(digits[:precision]), e
+	}
+	digits = digits[:precision]
+	for i := precision - 1; i >= 0; i-- {
+		if digits[i] != '9' {
+			digits[i]++
+			return string(digits), e
+		}
+		digits[i] = '0'
+	}
+	return "1" + string(digits[:precision-1]), e + 1
+}
+
+// shortestDigits returns the shortest decimal digits that identify the
+// given finite, positive number uniquely, and the exponent e, such that
+// the number is d.ddd * 10^e.
+func shortestDigits(x float64) (string, int) {
+	repr := strconv.FormatFloat(x, 'e', -1, 64)
+	mark := strings.IndexByte(repr, 'e')
+	e, _ := strconv.Atoi(repr[mark+1:])
+	return strings.Replace(repr[:mark], ".", "", 1), e
+}
+
+// exponentString returns the given digits and exponent in exponential
+// notation, like 1.23e+4.
+func exponentString(digits string, e int) string {
+	s := digits[:1]
+	if len(digits) > 1 {
+		s += "." + digits[1:]
+	}
+	if e < 0 {
+		return s + "e-" + strconv.Itoa(-e)
+	}
+	return s + "e+" + strconv.Itoa(e)
+}
+
+// numberPrototypeToExponential is Number.prototype.toExponential.
+// Number.prototype.toExponential is specified in 20.1.3.2.
+func numberPrototypeToExponential(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+	x, err := thisNumberValue(this)
+	if err != nil {
+		return nil, err
+	}
+	fractionDigits := argument(args, 0)
+	f, err := toInteger(fractionDigits)
+	if err != nil {
+		return nil, err
+	}
+	v := x.Float64()
+	if x.IsNaN() || math.IsInf(v, 0) {
+		return lang.NumberToString(x), nil
+	}
+	if f < 0 || f > 100 {
+		return nil, errors.NewRangeError("toExponential() argument must be between 0 and 100")
+	}
+
+	sign := ""
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+	var digits string
+	var e int
+	switch {
+	case v == 0:
+		digits = strings.Repeat("0", int(f)+1)
+	case fractionDigits == lang.Undefined:
+		digits, e = shortestDigits(v)
+	default:
+		digits, e = roundedDigits(v, int(f)+1)
+	}
+	return str(sign + exponentString(digits, e)), nil
+}
+
+// numberPrototypeToPrecision is Number.prototype.toPrecision.
+// Number.prototype.toPrecision is specified in 20.1.3.5.
+func numberPrototypeToPrecision(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+	x, err := thisNumberValue(this)
+	if err != nil {
+		return nil, err
+	}
+	if argument(args, 0) == lang.Undefined {
+		return lang.ToString(x)
+	}
+	p, err := toInteger(argument(args, 0))
+	if err != nil {
+		return nil, err
+	}
+	v := x.Float64()
+	if x.IsNaN() || math.IsInf(v, 0) {
+		return lang.NumberToString(x), nil
+	}
+	if p < 1 || p > 100 {
+		return nil, errors.NewRangeError("toPrecision() argument must be between 1 and 100")
+	}
+
+	precision := int(p)
+	sign := ""
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+	var digits string
+	var e int
+	if v == 0 {
+		digits = strings.Repeat("0", precision)
+	} else {
+		digits, e = roundedDigits(v, precision)
+	}
+
+	switch {
+	case e < -6 || e >= precision:
+		return str(sign + exponentString(digits, e)), nil
+	case e == precision-1:
+		return str(sign + digits), nil
+	case e >= 0:
+		return str(sign + digits[:e+1] + "." + digits[e+1:]), nil
+	}
+	return str(sign + "0." + strings.Repeat("0", -(e+1)) + digits), nil
+}
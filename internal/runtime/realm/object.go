@@ -0,0 +1,472 @@
+package realm
+
+import (
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+)
+
+// createObject creates the Object constructor and the properties of
+// %ObjectPrototype%, as specified in 19.1.
+func (r *Realm) createObject() {
+	objProto := r.intrinsic(IntrinsicNameObjectPrototype)
+
+	var object *lang.Object
+	object = r.newConstructor("Object", 1, objProto, func(newTarget lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		if nt, ok := newTarget.(*lang.Object); ok && nt != object {
+			return OrdinaryCreateFromConstructor(nt, IntrinsicNameObjectPrototype)
+		}
+		value := argument(args, 0)
+		if value == lang.Undefined || value == lang.Null {
+			return lang.ObjectCreate(objProto), nil
+		}
+		return r.toObject(value)
+	})
+	r.Intrinsics.SetField(IntrinsicNameObject, object)
+
+	r.defineFunction(object, "assign", 2, r.objectAssign)
+	r.defineFunction(object, "create", 2, r.objectCreate)
+	r.defineFunction(object, "defineProperties", 2, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		o, ok := argument(args, 0).(*lang.Object)
+		if !ok {
+			return nil, errors.NewTypeError("Object.defineProperties called on non-object")
+		}
+		return objectDefineProperties(r, o, argument(args, 1))
+	})
+	r.defineFunction(object, "defineProperty", 3, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		o, ok := argument(args, 0).(*lang.Object)
+		if !ok {
+			return nil, errors.NewTypeError("Object.defineProperty called on non-object")
+		}
+		p, err := lang.ToPropertyKey(argument(args, 1))
+		if err != nil {
+			return nil, err
+		}
+		desc, err := lang.ToPropertyDescriptor(argument(args, 2))
+		if err != nil {
+			return nil, err
+		}
+		if _, err := lang.DefinePropertyOrThrow(o, p, desc); err != nil {
+			return nil, err
+		}
+		return o, nil
+	})
+	r.defineFunction(object, "entries", 1, r.objectEnumerate(lang.EnumerateKeyPlusValue))
+	r.defineFunction(object, "freeze", 1, objectSetIntegrityLevel(lang.IntegrityLevelFrozen))
+	r.defineFunction(object, "fromEntries", 1, r.objectFromEntries)
+	r.defineFunction(object, "getOwnPropertyDescriptor", 2, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		o, err := r.toObject(argument(args, 0))
+		if err != nil {
+			return nil, err
+		}
+		p, err := lang.ToPropertyKey(argument(args, 1))
+		if err != nil {
+			return nil, err
+		}
+		desc := o.GetOwnProperty(p)
+		if desc == nil {
+			return lang.Undefined, nil
+		}
+		return lang.FromPropertyDescriptor(r, desc), nil
+	})
+	r.defineFunction(object, "getOwnPropertyDescriptors", 1, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		o, err := r.toObject(argument(args, 0))
+		if err != nil {
+			return nil, err
+		}
+		descriptors := lang.ObjectCreate(objProto)
+		for _, p := range o.OwnPropertyKeys() {
+			desc := o.GetOwnProperty(p)
+			if desc == nil {
+				continue
+			}
+			if _, err := lang.CreateDataProperty(descriptors, p, lang.FromPropertyDescriptor(r, desc)); err != nil {
+				return nil, err
+			}
+		}
+		return descriptors, nil
+	})
+	r.defineFunction(object, "getOwnPropertyNames", 1, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		return r.getOwnPropertyKeys(argument(args, 0), lang.TypeString)
+	})
+	r.defineFunction(object, "getOwnPropertySymbols", 1, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		return r.getOwnPropertyKeys(argument(args, 0), lang.TypeSymbol)
+	})
+	r.defineFunction(object, "getPrototypeOf", 1, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		o, err := r.toObject(argument(args, 0))
+		if err != nil {
+			return nil, err
+		}
+		return o.GetPrototypeOf(), nil
+	})
+	r.defineFunction(object, "is", 2, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		return lang.SameValue(argument(args, 0), argument(args, 1)), nil
+	})
+	r.defineFunction(object, "isExtensible", 1, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		o, ok := argument(args, 0).(*lang.Object)
+		if !ok {
+			return lang.False, nil
+		}
+		return o.IsExtensible(), nil
+	})
+	r.defineFunction(object, "isFrozen", 1, objectTestIntegrityLevel(lang.IntegrityLevelFrozen))
+	r.defineFunction(object, "isSealed", 1, objectTestIntegrityLevel(lang.IntegrityLevelSealed))
+	r.defineFunction(object, "keys", 1, r.objectEnumerate(lang.EnumerateKey))
+	r.defineFunction(object, "preventExtensions", 1, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		o, ok := argument(args, 0).(*lang.Object)
+		if !ok {
+			return argument(args, 0), nil
+		}
+		if !o.PreventExtensions() {
+			return nil, errors.NewTypeError("Cannot prevent extensions")
+		}
+		return o, nil
+	})
+	r.defineFunction(object, "seal", 1, objectSetIntegrityLevel(lang.IntegrityLevelSealed))
+	r.defineFunction(object, "setPrototypeOf", 2, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		o, err := lang.RequireObjectCoercible(argument(args, 0))
+		if err != nil {
+			return nil, err
+		}
+		proto := argument(args, 1)
+		if proto.Type() != lang.TypeObject && proto != lang.Null {
+			return nil, errors.NewTypeError("Object prototype may only be an Object or null")
+		}
+		obj, ok := o.(*lang.Object)
+		if !ok {
+			return o, nil
+		}
+		if !obj.SetPrototypeOf(proto) {
+			return nil, errors.NewTypeError("Cannot set prototype")
+		}
+		return obj, nil
+	})
+	r.defineFunction(object, "values", 1, r.objectEnumerate(lang.EnumerateValue))
+
+	r.defineFunction(objProto, "hasOwnProperty", 1, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		p, err := lang.ToPropertyKey(argument(args, 0))
+		if err != nil {
+			return nil, err
+		}
+		o, err := r.toObject(this)
+		if err != nil {
+			return nil, err
+		}
+		return lang.HasOwnProperty(o, p), nil
+	})
+	r.defineFunction(objProto, "isPrototypeOf", 1, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		v, ok := argument(args, 0).(*lang.Object)
+		if !ok {
+			return lang.False, nil
+		}
+		o, err := r.toObject(this)
+		if err != nil {
+			return nil, err
+		}
+		for {
+			proto, ok := v.GetPrototypeOf().(*lang.Object)
+			if !ok {
+				return lang.False, nil
+			}
+			if proto == o {
+				return lang.True, nil
+			}
+			v = proto
+		}
+	})
+	r.defineFunction(objProto, "propertyIsEnumerable", 1, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		p, err := lang.ToPropertyKey(argument(args, 0))
+		if err != nil {
+			return nil, err
+		}
+		o, err := r.toObject(this)
+		if err != nil {
+			return nil, err
+		}
+		desc := o.GetOwnProperty(p)
+		if desc == nil {
+			return lang.False, nil
+		}
+		return desc.Enumerable(), nil
+	})
+	r.defineFunction(objProto, "toLocaleString", 0, func(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+		return lang.Invoke(r, this, key("toString"))
+	})
+	toString := r.defineFunction(objProto, "toString", 0, r.objectPrototypeToString)
+	r.Intrinsics.SetField(IntrinsicNameObjProtoToString, toString)
+	valueOf := r.defineFunction(objProto, "valueOf", 0, func(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+		return r.toObject(this)
+	})
+	r.Intrinsics.SetField(IntrinsicNameObjProtoValueOf, valueOf)
+
+	// Object.prototype.__proto__ is specified in B.2.2.1.
+	getter := r.NewFunction("__proto__", 0, func(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+		o, err := r.toObject(this)
+		if err != nil {
+			return nil, err
+		}
+		return o.GetPrototypeOf(), nil
+	})
+	lang.SetFunctionName(getter, key("__proto__"), "get")
+	setter := r.NewFunction("__proto__", 1, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		if _, err := lang.RequireObjectCoercible(this); err != nil {
+			return nil, err
+		}
+		proto := argument(args, 0)
+		if proto.Type() != lang.TypeObject && proto != lang.Null {
+			return lang.Undefined, nil
+		}
+		o, ok := this.(*lang.Object)
+		if !ok {
+			return lang.Undefined, nil
+		}
+		if !o.SetPrototypeOf(proto) {
+			return nil, errors.NewTypeError("Cannot set prototype")
+		}
+		return lang.Undefined, nil
+	})
+	lang.SetFunctionName(setter, key("__proto__"), "set")
+	_, _ = lang.DefinePropertyOrThrow(objProto, key("__proto__"), lang.NewAccessorProperty(getter, setter, lang.False, lang.True))
+}
+
+// objectAssign is Object.assign.
+// Object.assign is specified in 19.1.2.1.
+func (r *Realm) objectAssign(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+	to, err := r.toObject(argument(args, 0))
+	if err != nil {
+		return nil, err
+	}
+	for i := 1; i < len(args); i++ {
+		source := args[i]
+		if source == lang.Undefined || source == lang.Null {
+			continue
+		}
+		from, err := r.toObject(source)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range from.OwnPropertyKeys() {
+			desc := from.GetOwnProperty(p)
+			if desc == nil || !desc.Enumerable() {
+				continue
+			}
+			v, err := get(from, p)
+			if err != nil {
+				return nil, err
+			}
+			if err := set(to, p, v); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return to, nil
+}
+
+// objectCreate is Object.create.
+// Object.create is specified in 19.1.2.2.
+func (r *Realm) objectCreate(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+	proto := argument(args, 0)
+	if proto.Type() != lang.TypeObject && proto != lang.Null {
+		return nil, errors.NewTypeError("Object prototype may only be an Object or null")
+	}
+	o := lang.ObjectCreate(proto)
+	o.SetHeap(r.heap)
+	if properties := argument(args, 1); properties != lang.Undefined {
+		return objectDefineProperties(r, o, properties)
+	}
+	return o, nil
+}
+
+// objectDefineProperties defines the properties that are described by the
+// given properties object on the given object.
+// ObjectDefineProperties is specified in 19.1.2.3.1.
+func objectDefineProperties(r *Realm, o *lang.Object, properties lang.Value) (*lang.Object, errors.Error) {
+	props, err := r.toObject(properties)
+	if err != nil {
+		return nil, err
+	}
+	type descriptor struct {
+		key  lang.StringOrSymbol
+		desc *lang.Property
+	}
+	var descriptors []descriptor
+	for _, p := range props.OwnPropertyKeys() {
+		propDesc := props.GetOwnProperty(p)
+		if propDesc == nil || !propDesc.Enumerable() {
+			continue
+		}
+		descObj, err := get(props, p)
+		if err != nil {
+			return nil, err
+		}
+		desc, err := lang.ToPropertyDescriptor(descObj)
+		if err != nil {
+			return nil, err
+		}
+		descriptors = append(descriptors, descriptor{p, desc})
+	}
+	for _, d := range descriptors {
+		if _, err := lang.DefinePropertyOrThrow(o, d.key, d.desc); err != nil {
+			return nil, err
+		}
+	}
+	return o, nil
+}
+
+// objectEnumerate returns the implementation of Object.keys, Object.values
+// or Object.entries, depending on the given kind.
+// These functions are specified in 19.1.2.17, 19.1.2.22 and 19.1.2.5.
+func (r *Realm) objectEnumerate(kind string) BuiltinFunction {
+	return func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		o, err := r.toObject(argument(args, 0))
+		if err != nil {
+			return nil, err
+		}
+		list, err := lang.EnumerableOwnPropertyNames(r, o, kind)
+		if err != nil {
+			return nil, err
+		}
+		return lang.CreateArrayFromList(r, list), nil
+	}
+}
+
+// objectFromEntries is Object.fromEntries.
+// Object.fromEntries is specified in 19.1.2.7.
+func (r *Realm) objectFromEntries(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+	iterable, err := lang.RequireObjectCoercible(argument(args, 0))
+	if err != nil {
+		return nil, err
+	}
+	o := lang.ObjectCreate(r.intrinsic(IntrinsicNameObjectPrototype))
+	err = r.iterate(iterable, func(entry lang.Value) errors.Error {
+		k, v, err := entryKeyValue(entry)
+		if err != nil {
+			return err
+		}
+		p, err := lang.ToPropertyKey(k)
+		if err != nil {
+			return err
+		}
+		_, err = lang.CreateDataPropertyOrThrow(o, p, v)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// entryKeyValue returns the elements 0 and 1 of the given entry object, as
+// used by Object.fromEntries and the Map constructor.
+func entryKeyValue(entry lang.Value) (lang.Value, lang.Value, errors.Error) {
+	o, ok := entry.(*lang.Object)
+	if !ok {
+		return nil, nil, errors.NewTypeError("Iterator value is not an entry object")
+	}
+	k, err := get(o, key("0"))
+	if err != nil {
+		return nil, nil, err
+	}
+	v, err := get(o, key("1"))
+	if err != nil {
+		return nil, nil, err
+	}
+	return k, v, nil
+}
+
+// getOwnPropertyKeys returns an array of the own property keys of the
+// given value, that are of the given type.
+// GetOwnPropertyKeys is specified in 19.1.2.10.1.
+func (r *Realm) getOwnPropertyKeys(v lang.Value, t lang.Type) (lang.Value, errors.Error) {
+	o, err := r.toObject(v)
+	if err != nil {
+		return nil, err
+	}
+	var names []lang.Value
+	for _, p := range o.OwnPropertyKeys() {
+		if p.Type() == t {
+			names = append(names, p.Underlying())
+		}
+	}
+	return lang.CreateArrayFromList(r, names), nil
+}
+
+// objectSetIntegrityLevel returns the implementation of Object.freeze or
+// Object.seal, depending on the given level.
+// These functions are specified in 19.1.2.6 and 19.1.2.20.
+func objectSetIntegrityLevel(level string) BuiltinFunction {
+	return func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		o, ok := argument(args, 0).(*lang.Object)
+		if !ok {
+			return argument(args, 0), nil
+		}
+		status, err := lang.SetIntegrityLevel(o, level)
+		if err != nil {
+			return nil, err
+		}
+		if !status {
+			return nil, errors.NewTypeError("Cannot change the integrity level of the object")
+		}
+		return o, nil
+	}
+}
+
+// objectTestIntegrityLevel returns the implementation of Object.isFrozen or
+// Object.isSealed, depending on the given level.
+// These functions are specified in 19.1.2.13 and 19.1.2.14.
+func objectTestIntegrityLevel(level string) BuiltinFunction {
+	return func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		o, ok := argument(args, 0).(*lang.Object)
+		if !ok {
+			return lang.True, nil
+		}
+		return lang.TestIntegrityLevel(o, level), nil
+	}
+}
+
+// objectPrototypeToString is Object.prototype.toString.
+// Object.prototype.toString is specified in 19.1.3.6.
+func (r *Realm) objectPrototypeToString(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+	switch this {
+	case lang.Undefined:
+		return str("[object Undefined]"), nil
+	case lang.Null:
+		return str("[object Null]"), nil
+	}
+	o, err := r.toObject(this)
+	if err != nil {
+		return nil, err
+	}
+
+	var builtinTag string
+	switch {
+	case lang.InternalIsArray(o):
+		builtinTag = "Array"
+	case o.HasSlot(lang.SlotParameterMap):
+		builtinTag = "Arguments"
+	case o.Call != nil:
+		builtinTag = "Function"
+	case o.HasSlot(lang.SlotErrorData):
+		builtinTag = "Error"
+	case o.HasSlot(lang.SlotBooleanData):
+		builtinTag = "Boolean"
+	case o.HasSlot(lang.SlotNumberData):
+		builtinTag = "Number"
+	case o.HasSlot(lang.SlotStringData):
+		builtinTag = "String"
+	case o.HasSlot(slotDateValue):
+		builtinTag = "Date"
+	case o.HasSlot(lang.SlotRegExpMatcher):
+		builtinTag = "RegExp"
+	default:
+		builtinTag = "Object"
+	}
+
+	tag, err := get(o, symbolKey(lang.SymbolToStringTag))
+	if err != nil {
+		return nil, err
+	}
+	if s, ok := tag.(lang.String); ok {
+		builtinTag = s.String()
+	}
+	return str("[object " + builtinTag + "]"), nil
+}
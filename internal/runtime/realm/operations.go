@@ -0,0 +1,170 @@
+package realm
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+)
+
+// key returns the property key for the given name.
+func key(name string) lang.StringOrSymbol {
+	return lang.NewStringKey(name)
+}
+
+// symbolKey returns the property key for the given symbol.
+func symbolKey(sym *lang.Symbol) lang.StringOrSymbol {
+	return lang.NewStringOrSymbol(sym)
+}
+
+// indexKey returns the property key for the given array index.
+func indexKey(i float64) lang.StringOrSymbol {
+	return lang.NewStringKey(strconv.FormatFloat(i, 'f', -1, 64))
+}
+
+// get returns the value of the property with the given key of the given
+// object.
+func get(o *lang.Object, p lang.StringOrSymbol) (lang.Value, errors.Error) {
+	v, err := o.Get(p, o)
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// set sets the value of the property with the given key of the given
+// object, and returns a TypeError if the property could not be set.
+func set(o *lang.Object, p lang.StringOrSymbol, v lang.Value) errors.Error {
+	_, err := lang.Set(o, p, v, true)
+	return err
+}
+
+// toObject converts the given value to an object of the realm.
+func (r *Realm) toObject(v lang.Value) (*lang.Object, errors.Error) {
+	return lang.ToObject(r, v)
+}
+
+// toString converts the given value to a Go string.
+func toString(v lang.Value) (string, errors.Error) {
+	s, err := lang.ToString(v)
+	if err != nil {
+		return "", err
+	}
+	return s.String(), nil
+}
+
+// toNumber converts the given value to a float64.
+func toNumber(v lang.Value) (float64, errors.Error) {
+	n, err := lang.ToNumber(v)
+	if err != nil {
+		return 0, err
+	}
+	return n.Float64(), nil
+}
+
+// toInteger converts the given value to an integral float64, which may
+// be infinite.
+// ToInteger is specified in 7.1.4.
+func toInteger(v lang.Value) (float64, errors.Error) {
+	n, err := lang.ToInteger(v)
+	if err != nil {
+		return 0, err
+	}
+	return n.Float64(), nil
+}
+
+// toLength converts the given value to a valid length of an array-like
+// object.
+// ToLength is specified in 7.1.15.
+func toLength(v lang.Value) (float64, errors.Error) {
+	n, err := lang.ToLength(v)
+	if err != nil {
+		return 0, err
+	}
+	return n.Float64(), nil
+}
+
+// lengthOfArrayLike returns the value of the length property of the given
+// object, converted with ToLength.
+func lengthOfArrayLike(o *lang.Object) (float64, errors.Error) {
+	v, err := get(o, key("length"))
+	if err != nil {
+		return 0, err
+	}
+	return toLength(v)
+}
+
+// relativeIndex converts the given value to an index relative to the
+// given length, as used by methods like Array.prototype.slice. Negative
+// values are counted from the end. The result is clamped to the range
+// from 0 to length. If the value is Undefined, def is returned.
+func relativeIndex(v lang.Value, length, def float64) (float64, errors.Error) {
+	if v == lang.Undefined {
+		return def, nil
+	}
+	n, err := toInteger(v)
+	if err != nil {
+		return 0, err
+	}
+	if n < 0 {
+		return math.Max(length+n, 0), nil
+	}
+	return math.Min(n, length), nil
+}
+
+// number returns the Number for the given float64.
+func number(f float64) lang.Number {
+	return lang.NewNumber(f)
+}
+
+// str returns the String for the given Go string.
+func str(s string) lang.String {
+	return lang.NewString(s)
+}
+
+// callable returns the given value as function object, or a TypeError if
+// it is not callable.
+func callable(v lang.Value, what string) (*lang.Object, errors.Error) {
+	if !lang.InternalIsCallable(v) {
+		return nil, errors.NewTypeError(what + " is not a function")
+	}
+	return v.(*lang.Object), nil
+}
+
+// iterate iterates over the given iterable value, and calls fn with each
+// value that the iterator produces. If fn returns an error, the iterator
+// is closed and the error is returned.
+func (r *Realm) iterate(iterable lang.Value, fn func(lang.Value) errors.Error) errors.Error {
+	record, err := lang.GetIterator(r, iterable)
+	if err != nil {
+		return err
+	}
+	for {
+		r.work(1)
+		next, err := lang.IteratorStep(record)
+		if err != nil {
+			return err
+		}
+		if next == nil {
+			return nil
+		}
+		v, err := lang.IteratorValue(next)
+		if err != nil {
+			return err
+		}
+		if err := fn(v); err != nil {
+			return lang.IteratorClose(record, err)
+		}
+	}
+}
+
+// activeFunctionOrNewTarget returns the given NewTarget, or the given
+// active function if NewTarget is Undefined, which is the case if a
+// constructor is called as a function.
+func activeFunctionOrNewTarget(newTarget lang.Value, active *lang.Object) *lang.Object {
+	if nt, ok := newTarget.(*lang.Object); ok {
+		return nt
+	}
+	return active
+}
@@ -0,0 +1,524 @@
+package realm
+
+import (
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+)
+
+// slotPromiseState is the internal slot of Promise objects, that holds
+// the promiseData with their [[PromiseState]], [[PromiseResult]],
+// [[PromiseFulfillReactions]], [[PromiseRejectReactions]] and
+// [[PromiseIsHandled]], as specified in 25.6.6.
+var slotPromiseState = lang.NewStringKey("PromiseState")
+
+// promiseState is the state of a promise.
+type promiseState uint8
+
+const (
+	promiseStatePending promiseState = iota
+	promiseStateFulfilled
+	promiseStateRejected
+)
+
+// promiseData holds the state of a Promise object.
+type promiseData struct {
+	state            promiseState
+	result           lang.Value
+	fulfillReactions []*promiseReaction
+	rejectReactions  []*promiseReaction
+	isHandled        bool
+}
+
+// Type returns lang.TypeInternal.
+func (*promiseData) Type() lang.Type { return lang.TypeInternal }
+
+// Value returns the data itself.
+func (p *promiseData) Value() interface{} { return p }
+
+// promiseCapability is a PromiseCapability Record, as specified in
+// 25.6.1.1.
+type promiseCapability struct {
+	promise         *lang.Object
+	resolve, reject *lang.Object
+}
+
+// promiseReaction is a PromiseReaction Record, as specified in 25.6.1.2.
+// The handler is nil if the default behaviour, passing on the value or
+// the reason, is used.
+type promiseReaction struct {
+	capability *promiseCapability
+	rejection  bool
+	handler    *lang.Object
+}
+
+// isPromise returns the data of the given value, if it is a Promise.
+// IsPromise is specified in 25.6.1.6.
+func isPromise(v lang.Value) (*promiseData, bool) {
+	if o, ok := v.(*lang.Object); ok && o.HasSlot(slotPromiseState) {
+		return o.GetSlot(slotPromiseState).(*promiseData), true
+	}
+	return nil, false
+}
+
+// enqueuePromiseJob passes the given job to HostEnqueuePromiseJob, if it
+// is set.
+func (r *Realm) enqueuePromiseJob(job BuiltinFunction, args ...lang.Value) {
+	if r.HostEnqueuePromiseJob != nil {
+		r.HostEnqueuePromiseJob(job, args)
+	}
+}
+
+// createPromise creates the Promise constructor and %PromisePrototype%, as
+// specified in 25.6.
+func (r *Realm) createPromise() {
+	proto := lang.ObjectCreate(r.intrinsic(IntrinsicNameObjectPrototype))
+	r.Intrinsics.SetField(IntrinsicNamePromisePrototype, proto)
+
+	promise := r.newConstructor("Promise", 1, proto, func(newTarget lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		nt, ok := newTarget.(*lang.Object)
+		if !ok {
+			return nil, errors.NewTypeError("Constructor Promise requires 'new'")
+		}
+		executor, err := callable(argument(args, 0), "Promise resolver")
+		if err != nil {
+			return nil, err
+		}
+		p, err := OrdinaryCreateFromConstructor(nt, IntrinsicNamePromisePrototype, slotPromiseState)
+		if err != nil {
+			return nil, err
+		}
+		p.SetSlot(slotPromiseState, &promiseData{})
+		resolve, reject := r.createResolvingFunctions(p)
+		if _, err := lang.Call(executor, lang.Undefined, resolve, reject); err != nil {
+			if _, err := lang.Call(reject, lang.Undefined, r.ErrorValue(err)); err != nil {
+				return nil, err
+			}
+		}
+		return p, nil
+	})
+	r.Intrinsics.SetField(IntrinsicNamePromise, promise)
+
+	all := r.defineFunction(promise, "all", 1, r.promiseCombinator(r.performPromiseAll))
+	r.Intrinsics.SetField(IntrinsicNamePromiseAll, all)
+	r.defineFunction(promise, "allSettled", 1, r.promiseCombinator(r.performPromiseAllSettled))
+	r.defineFunction(promise, "race", 1, r.promiseCombinator(r.performPromiseRace))
+	reject := r.defineFunction(promise, "reject", 1, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		capability, err := r.newPromiseCapability(this)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := lang.Call(capability.reject, lang.Undefined, argument(args, 0)); err != nil {
+			return nil, err
+		}
+		return capability.promise, nil
+	})
+	r.Intrinsics.SetField(IntrinsicNamePromiseReject, reject)
+	resolve := r.defineFunction(promise, "resolve", 1, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		c, ok := this.(*lang.Object)
+		if !ok {
+			return nil, errors.NewTypeError("PromiseResolve called on non-object")
+		}
+		return r.promiseResolve(c, argument(args, 0))
+	})
+	r.Intrinsics.SetField(IntrinsicNamePromiseResolve, resolve)
+	r.defineSpecies(promise)
+
+	r.defineFunction(proto, "catch", 1, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		return lang.Invoke(r, this, key("then"), lang.Undefined, argument(args, 0))
+	})
+	r.defineFunction(proto, "finally", 1, r.promisePrototypeFinally)
+	then := r.defineFunction(proto, "then", 2, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		if _, ok := isPromise(this); !ok {
+			return nil, errors.NewTypeError("Method Promise.prototype.then called on incompatible receiver")
+		}
+		p := this.(*lang.Object)
+		c, err := lang.SpeciesConstructor(p, r.intrinsic(IntrinsicNamePromise))
+		if err != nil {
+			return nil, err
+		}
+		capability, err := r.newPromiseCapability(c)
+		if err != nil {
+			return nil, err
+		}
+		return r.performPromiseThen(p, argument(args, 0), argument(args, 1), capability), nil
+	})
+	r.Intrinsics.SetField(IntrinsicNamePromiseProtoThen, then)
+	defineToStringTag(proto, "Promise")
+}
+
+// createResolvingFunctions creates the resolve and reject functions of the
+// given promise, which share the state whether the promise has already
+// been resolved.
+// CreateResolvingFunctions is specified in 25.6.1.3.
+func (r *Realm) createResolvingFunctions(promise *lang.Object) (resolve, reject *lang.Object) {
+	alreadyResolved := false
+
+	resolve = r.NewFunction("", 1, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		if alreadyResolved {
+			return lang.Undefined, nil
+		}
+		alreadyResolved = true
+
+		resolution := argument(args, 0)
+		if resolution == lang.Value(promise) {
+			r.rejectPromise(promise, r.ErrorValue(errors.NewTypeError("Chaining cycle detected for promise")))
+			return lang.Undefined, nil
+		}
+		thenable, ok := resolution.(*lang.Object)
+		if !ok {
+			r.fulfillPromise(promise, resolution)
+			return lang.Undefined, nil
+		}
+		thenAction, err := get(thenable, key("then"))
+		if err != nil {
+			r.rejectPromise(promise, r.ErrorValue(err))
+			return lang.Undefined, nil
+		}
+		if !lang.InternalIsCallable(thenAction) {
+			r.fulfillPromise(promise, resolution)
+			return lang.Undefined, nil
+		}
+		r.enqueuePromiseJob(r.promiseResolveThenableJob, promise, thenable, thenAction)
+		return lang.Undefined, nil
+	})
+
+	reject = r.NewFunction("", 1, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		if alreadyResolved {
+			return lang.Undefined, nil
+		}
+		alreadyResolved = true
+		r.rejectPromise(promise, argument(args, 0))
+		return lang.Undefined, nil
+	})
+	return resolve, reject
+}
+
+// fulfillPromise fulfills the given pending promise with the given value.
+// FulfillPromise is specified in 25.6.1.4.
+func (r *Realm) fulfillPromise(promise *lang.Object, value lang.Value) {
+	data := promise.GetSlot(slotPromiseState).(*promiseData)
+	reactions := data.fulfillReactions
+	data.result = value
+	data.fulfillReactions, data.rejectReactions = nil, nil
+	data.state = promiseStateFulfilled
+	r.triggerPromiseReactions(reactions, value)
+}
+
+// rejectPromise rejects the given pending promise with the given reason.
+// RejectPromise is specified in 25.6.1.7.
+func (r *Realm) rejectPromise(promise *lang.Object, reason lang.Value) {
+	data := promise.GetSlot(slotPromiseState).(*promiseData)
+	reactions := data.rejectReactions
+	data.result = reason
+	data.fulfillReactions, data.rejectReactions = nil, nil
+	data.state = promiseStateRejected
+	r.triggerPromiseReactions(reactions, reason)
+}
+
+// triggerPromiseReactions enqueues a job for each of the given reactions.
+// TriggerPromiseReactions is specified in 25.6.1.8.
+func (r *Realm) triggerPromiseReactions(reactions []*promiseReaction, argument lang.Value) {
+	for _, reaction := range reactions {
+		r.enqueuePromiseJob(r.promiseReactionJob(reaction), argument)
+	}
+}
+
+// promiseReactionJob returns the job that calls the handler of the given
+// reaction with the argument of the job, and resolves or rejects the
+// promise of the reaction with the result.
+// PromiseReactionJob is specified in 25.6.2.1.
+func (r *Realm) promiseReactionJob(reaction *promiseReaction) BuiltinFunction {
+	return func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		argument := argument(args, 0)
+		var handlerResult lang.Value
+		var err errors.Error
+		switch {
+		case reaction.handler != nil:
+			handlerResult, err = lang.Call(reaction.handler, lang.Undefined, argument)
+		case reaction.rejection:
+			err = lang.NewThrowError(argument)
+		default:
+			handlerResult = argument
+		}
+
+		if reaction.capability == nil {
+			return lang.Undefined, err
+		}
+		if err != nil {
+			return lang.Call(reaction.capability.reject, lang.Undefined, r.ErrorValue(err))
+		}
+		return lang.Call(reaction.capability.resolve, lang.Undefined, handlerResult)
+	}
+}
+
+// promiseResolveThenableJob calls the then method of a thenable, which
+// was used to resolve a promise, with the resolving functions of that
+// promise. The arguments of the job are the promise, the thenable and
+// its then method.
+// PromiseResolveThenableJob is specified in 25.6.2.2.
+func (r *Realm) promiseResolveThenableJob(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+	promiseToResolve, thenable, then := args[0].(*lang.Object), args[1], args[2].(*lang.Object)
+	resolve, reject := r.createResolvingFunctions(promiseToResolve)
+	result, err := lang.Call(then, thenable, resolve, reject)
+	if err != nil {
+		return lang.Call(reject, lang.Undefined, r.ErrorValue(err))
+	}
+	return result, nil
+}
+
+// newPromiseCapability creates a new promise with the given constructor,
+// and returns it together with its resolving functions.
+// NewPromiseCapability is specified in 25.6.1.5.
+func (r *Realm) newPromiseCapability(c lang.Value) (*promiseCapability, errors.Error) {
+	if !lang.InternalIsConstructor(c) {
+		return nil, errors.NewTypeError("Promise capability constructor is not a constructor")
+	}
+	capability := &promiseCapability{}
+	var resolve, reject lang.Value = lang.Undefined, lang.Undefined
+	executor := r.NewFunction("", 2, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		if resolve != lang.Undefined || reject != lang.Undefined {
+			return nil, errors.NewTypeError("Promise executor has already been invoked with non-undefined arguments")
+		}
+		resolve, reject = argument(args, 0), argument(args, 1)
+		return lang.Undefined, nil
+	})
+	promise, err := lang.Construct(c.(*lang.Object), c.(*lang.Object), executor)
+	if err != nil {
+		return nil, err
+	}
+	if capability.resolve, err = callable(resolve, "Promise resolve function"); err != nil {
+		return nil, err
+	}
+	if capability.reject, err = callable(reject, "Promise reject function"); err != nil {
+		return nil, err
+	}
+	capability.promise = promise
+	return capability, nil
+}
+
+// promiseResolve returns the given value if it is a promise that was
+// created by the given constructor, or a new promise of the constructor
+// that is resolved with the value.
+// PromiseResolve is specified in 25.6.4.5.1.
+func (r *Realm) promiseResolve(c *lang.Object, x lang.Value) (lang.Value, errors.Error) {
+	if _, ok := isPromise(x); ok {
+		xConstructor, err := get(x.(*lang.Object), key("constructor"))
+		if err != nil {
+			return nil, err
+		}
+		if xConstructor == lang.Value(c) {
+			return x, nil
+		}
+	}
+	capability, err := r.newPromiseCapability(c)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := lang.Call(capability.resolve, lang.Undefined, x); err != nil {
+		return nil, err
+	}
+	return capability.promise, nil
+}
+
+// performPromiseThen adds reactions with the given handlers to the given
+// promise, and returns the promise of the result capability, which may be
+// nil, in which case Undefined is returned.
+// PerformPromiseThen is specified in 25.6.5.4.1.
+func (r *Realm) performPromiseThen(promise *lang.Object, onFulfilled, onRejected lang.Value, resultCapability *promiseCapability) lang.Value {
+	fulfillReaction := &promiseReaction{capability: resultCapability}
+	if lang.InternalIsCallable(onFulfilled) {
+		fulfillReaction.handler = onFulfilled.(*lang.Object)
+	}
+	rejectReaction := &promiseReaction{capability: resultCapability, rejection: true}
+	if lang.InternalIsCallable(onRejected) {
+		rejectReaction.handler = onRejected.(*lang.Object)
+	}
+
+	data := promise.GetSlot(slotPromiseState).(*promiseData)
+	switch data.state {
+	case promiseStatePending:
+		data.fulfillReactions = append(data.fulfillReactions, fulfillReaction)
+		data.rejectReactions = append(data.rejectReactions, rejectReaction)
+	case promiseStateFulfilled:
+		r.enqueuePromiseJob(r.promiseReactionJob(fulfillReaction), data.result)
+	case promiseStateRejected:
+		r.enqueuePromiseJob(r.promiseReactionJob(rejectReaction), data.result)
+	}
+	data.isHandled = true
+
+	if resultCapability == nil {
+		return lang.Undefined
+	}
+	return resultCapability.promise
+}
+
+// promisePrototypeFinally is Promise.prototype.finally.
+// Promise.prototype.finally is specified in 25.6.5.3.
+func (r *Realm) promisePrototypeFinally(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+	promise, ok := this.(*lang.Object)
+	if !ok {
+		return nil, errors.NewTypeError("Method Promise.prototype.finally called on a non-object")
+	}
+	c, err := lang.SpeciesConstructor(promise, r.intrinsic(IntrinsicNamePromise))
+	if err != nil {
+		return nil, err
+	}
+
+	onFinally := argument(args, 0)
+	thenFinally, catchFinally := onFinally, onFinally
+	if lang.InternalIsCallable(onFinally) {
+		// finallyThen calls onFinally, and then passes on the result of the
+		// given function when the promise that it returned is fulfilled.
+		finallyThen := func(continuation BuiltinFunction) BuiltinFunction {
+			return func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+				result, err := lang.Call(onFinally.(*lang.Object), lang.Undefined)
+				if err != nil {
+					return nil, err
+				}
+				p, err := r.promiseResolve(c, result)
+				if err != nil {
+					return nil, err
+				}
+				value := argument(args, 0)
+				return lang.Invoke(r, p, key("then"), r.NewFunction("", 0, func(lang.Value, ...lang.Value) (lang.Value, errors.Error) {
+					return continuation(lang.Undefined, value)
+				}))
+			}
+		}
+		thenFinally = r.NewFunction("", 1, finallyThen(func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+			return args[0], nil
+		}))
+		catchFinally = r.NewFunction("", 1, finallyThen(func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+			return nil, lang.NewThrowError(args[0])
+		}))
+	}
+	return lang.Invoke(r, promise, key("then"), thenFinally, catchFinally)
+}
+
+// promiseCombinator returns the implementation of Promise.all,
+// Promise.allSettled or Promise.race, which iterate over the given
+// iterable with the given perform function, and reject the resulting
+// promise if the iteration fails.
+// Promise.all is specified in 25.6.4.1 and Promise.race in 25.6.4.3.
+func (r *Realm) promiseCombinator(perform func(c *lang.Object, capability *promiseCapability, iterable lang.Value, promiseResolve *lang.Object) errors.Error) BuiltinFunction {
+	return func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		capability, err := r.newPromiseCapability(this)
+		if err != nil {
+			return nil, err
+		}
+		c := this.(*lang.Object)
+		resolveValue, err := get(c, key("resolve"))
+		if err == nil {
+			var promiseResolve *lang.Object
+			if promiseResolve, err = callable(resolveValue, "Promise resolve"); err == nil {
+				err = perform(c, capability, argument(args, 0), promiseResolve)
+			}
+		}
+		if err != nil {
+			if _, err := lang.Call(capability.reject, lang.Undefined, r.ErrorValue(err)); err != nil {
+				return nil, err
+			}
+		}
+		return capability.promise, nil
+	}
+}
+
+// performPromiseAll resolves the promise of the capability with an array
+// of the values of the promises of the iterable, once all of them are
+// fulfilled.
+// PerformPromiseAll is specified in 25.6.4.1.1.
+func (r *Realm) performPromiseAll(c *lang.Object, capability *promiseCapability, iterable lang.Value, promiseResolve *lang.Object) errors.Error {
+	return r.performPromiseAllWith(c, capability, iterable, promiseResolve, func(settle func(lang.Value) errors.Error) (lang.Value, lang.Value) {
+		return r.promiseElementFunction(settle), capability.reject
+	})
+}
+
+// performPromiseAllSettled resolves the promise of the capability with an
+// array of objects, that describe the outcome of each promise of the
+// iterable, once all of them are settled.
+// PerformPromiseAllSettled is specified in 25.6.4.2.1 of ES2020.
+func (r *Realm) performPromiseAllSettled(c *lang.Object, capability *promiseCapability, iterable lang.Value, promiseResolve *lang.Object) errors.Error {
+	return r.performPromiseAllWith(c, capability, iterable, promiseResolve, func(settle func(lang.Value) errors.Error) (lang.Value, lang.Value) {
+		// the resolve and reject element functions share alreadyCalled
+		alreadyCalled := false
+		element := func(status, valueKey string) lang.Value {
+			return r.NewFunction("", 1, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+				if alreadyCalled {
+					return lang.Undefined, nil
+				}
+				alreadyCalled = true
+				obj := lang.ObjectCreate(r.intrinsic(IntrinsicNameObjectPrototype))
+				_, _ = lang.CreateDataProperty(obj, key("status"), str(status))
+				_, _ = lang.CreateDataProperty(obj, key(valueKey), argument(args, 0))
+				return lang.Undefined, settle(obj)
+			})
+		}
+		return element("fulfilled", "value"), element("rejected", "reason")
+	})
+}
+
+// performPromiseAllWith iterates over the iterable and calls the then
+// method of each value, resolved to a promise, with the handlers that are
+// returned by the given function. The handlers call settle with the
+// element of the result array, and once all elements are settled, the
+// promise of the capability is resolved with the result array.
+func (r *Realm) performPromiseAllWith(c *lang.Object, capability *promiseCapability, iterable lang.Value, promiseResolve *lang.Object, handlers func(settle func(lang.Value) errors.Error) (lang.Value, lang.Value)) errors.Error {
+	var values []lang.Value
+	remainingElementsCount := 1
+	remaining := func() errors.Error {
+		remainingElementsCount--
+		if remainingElementsCount == 0 {
+			_, err := lang.Call(capability.resolve, lang.Undefined, lang.CreateArrayFromList(r, values))
+			return err
+		}
+		return nil
+	}
+
+	err := r.iterate(iterable, func(nextValue lang.Value) errors.Error {
+		index := len(values)
+		values = append(values, lang.Undefined)
+		nextPromise, err := lang.Call(promiseResolve, c, nextValue)
+		if err != nil {
+			return err
+		}
+		remainingElementsCount++
+		onFulfilled, onRejected := handlers(func(x lang.Value) errors.Error {
+			values[index] = x
+			return remaining()
+		})
+		_, err = lang.Invoke(r, nextPromise, key("then"), onFulfilled, onRejected)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return remaining()
+}
+
+// performPromiseRace resolves or rejects the promise of the capability as
+// soon as one of the promises of the iterable is settled.
+// PerformPromiseRace is specified in 25.6.4.3.1.
+func (r *Realm) performPromiseRace(c *lang.Object, capability *promiseCapability, iterable lang.Value, promiseResolve *lang.Object) errors.Error {
+	return r.iterate(iterable, func(nextValue lang.Value) errors.Error {
+		nextPromise, err := lang.Call(promiseResolve, c, nextValue)
+		if err != nil {
+			return err
+		}
+		_, err = lang.Invoke(r, nextPromise, key("then"), capability.resolve, capability.reject)
+		return err
+	})
+}
+
+// promiseElementFunction returns a Promise.all resolve element function,
+// that calls the given function with its argument, at most once.
+// Promise.all Resolve Element Functions are specified in 25.6.4.1.2.
+func (r *Realm) promiseElementFunction(fn func(x lang.Value) errors.Error) *lang.Object {
+	alreadyCalled := false
+	return r.NewFunction("", 1, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		if alreadyCalled {
+			return lang.Undefined, nil
+		}
+		alreadyCalled = true
+		return lang.Undefined, fn(argument(args, 0))
+	})
+}
@@ -0,0 +1,537 @@
+package realm
+
+import (
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+)
+
+// slotRevocableProxy is the internal slot of the revocation functions
+// that are created by Proxy.revocable, as specified in 26.2.2.1.1.
+var slotRevocableProxy = lang.NewStringKey("RevocableProxy")
+
+// createProxy creates the Proxy constructor, as specified in 26.2.
+// The constructor does not have a prototype property.
+func (r *Realm) createProxy() {
+	r.proxyMethods = r.newProxyExoticMethods()
+
+	proxy := r.newConstructor("Proxy", 2, nil, func(newTarget lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		if newTarget == lang.Undefined {
+			return nil, errors.NewTypeError("Constructor Proxy requires 'new'")
+		}
+		return r.ProxyCreate(argument(args, 0), argument(args, 1))
+	})
+	r.Intrinsics.SetField(IntrinsicNameProxy, proxy)
+
+	r.defineFunction(proxy, "revocable", 2, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		p, err := r.ProxyCreate(argument(args, 0), argument(args, 1))
+		if err != nil {
+			return nil, err
+		}
+		var revoker *lang.Object
+		revoker = CreateBuiltinFunction(func(lang.Value, ...lang.Value) (lang.Value, errors.Error) {
+			if p, ok := revoker.GetSlot(slotRevocableProxy).(*lang.Object); ok {
+				revoker.SetSlot(slotRevocableProxy, lang.Null)
+				p.SetSlot(lang.SlotProxyTarget, lang.Null)
+				p.SetSlot(lang.SlotProxyHandler, lang.Null)
+			}
+			return lang.Undefined, nil
+		}, r, nil, slotRevocableProxy)
+		revoker.SetSlot(slotRevocableProxy, p)
+		lang.SetFunctionLength(revoker, 0)
+		lang.SetFunctionName(revoker, key(""), "")
+
+		result := lang.ObjectCreate(r.intrinsic(IntrinsicNameObjectPrototype))
+		_, _ = lang.CreateDataProperty(result, key("proxy"), p)
+		_, _ = lang.CreateDataProperty(result, key("revoke"), revoker)
+		return result, nil
+	})
+}
+
+// ProxyCreate creates a proxy exotic object with the given target and
+// handler, which must both be objects.
+// ProxyCreate is specified in 9.5.14.
+func (r *Realm) ProxyCreate(target, handler lang.Value) (*lang.Object, errors.Error) {
+	t, ok := target.(*lang.Object)
+	if !ok {
+		return nil, errors.NewTypeError("Cannot create proxy with a non-object as target or handler")
+	}
+	h, ok := handler.(*lang.Object)
+	if !ok {
+		return nil, errors.NewTypeError("Cannot create proxy with a non-object as target or handler")
+	}
+
+	p := lang.ObjectCreate(lang.Null, lang.SlotProxyHandler, lang.SlotProxyTarget)
+	p.Exotic = r.proxyMethods
+	p.SetSlot(lang.SlotProxyTarget, t)
+	p.SetSlot(lang.SlotProxyHandler, h)
+	if lang.InternalIsCallable(t) {
+		p.Call = func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+			if err := r.enter(); err != nil {
+				return nil, err
+			}
+			defer r.leave()
+			return r.proxyCall(p, this, args)
+		}
+		if lang.InternalIsConstructor(t) {
+			p.Construct = func(newTarget *lang.Object, args ...lang.Value) (*lang.Object, errors.Error) {
+				if err := r.enter(); err != nil {
+					return nil, err
+				}
+				defer r.leave()
+				return r.proxyConstruct(p, newTarget, args)
+			}
+		}
+	}
+	return p, nil
+}
+
+// proxyTrap returns the handler and target of the given proxy, and the
+// trap with the given name, which is nil if the handler does not define
+// it. It returns a TypeError if the proxy has been revoked.
+func proxyTrap(p *lang.Object, name string) (trap, handler, target *lang.Object, err errors.Error) {
+	handler, ok := p.GetSlot(lang.SlotProxyHandler).(*lang.Object)
+	if !ok {
+		return nil, nil, nil, errors.NewTypeError("Cannot perform '" + name + "' on a proxy that has been revoked")
+	}
+	target = p.GetSlot(lang.SlotProxyTarget).(*lang.Object)
+	method, err := get(handler, key(name))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if method == lang.Undefined || method == lang.Null {
+		return nil, handler, target, nil
+	}
+	trap, err = callable(method, "'"+name+"' on proxy")
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return trap, handler, target, nil
+}
+
+// newProxyExoticMethods creates the internal methods of the proxy exotic
+// objects of the realm, as specified in 9.5. Where an internal method
+// cannot fail in this implementation, like [[HasProperty]], an abrupt
+// completion of its trap or a violated invariant makes the operation
+// report failure, e.g. false for [[HasProperty]].
+func (r *Realm) newProxyExoticMethods() *lang.ExoticMethods {
+	return &lang.ExoticMethods{
+		GetPrototypeOf: func(p *lang.Object) lang.Value {
+			proto, err := proxyGetPrototypeOf(p)
+			if err != nil {
+				return lang.Null
+			}
+			return proto
+		},
+		SetPrototypeOf: func(p *lang.Object, v lang.Value) lang.Boolean {
+			ok, err := proxySetPrototypeOf(p, v)
+			return lang.Boolean(ok && err == nil)
+		},
+		IsExtensible: func(p *lang.Object) lang.Boolean {
+			ok, err := proxyIsExtensible(p)
+			return lang.Boolean(ok && err == nil)
+		},
+		PreventExtensions: func(p *lang.Object) lang.Boolean {
+			ok, err := proxyPreventExtensions(p)
+			return lang.Boolean(ok && err == nil)
+		},
+		GetOwnProperty: func(p *lang.Object, key lang.StringOrSymbol) *lang.Property {
+			desc, _ := r.proxyGetOwnProperty(p, key)
+			return desc
+		},
+		DefineOwnProperty: r.proxyDefineOwnProperty,
+		HasProperty: func(p *lang.Object, key lang.StringOrSymbol) lang.Boolean {
+			ok, err := proxyHasProperty(p, key)
+			return lang.Boolean(ok && err == nil)
+		},
+		Get: proxyGet,
+		Set: proxySet,
+		Delete: func(p *lang.Object, key lang.StringOrSymbol) lang.Boolean {
+			ok, err := proxyDelete(p, key)
+			return lang.Boolean(ok && err == nil)
+		},
+		OwnPropertyKeys: func(p *lang.Object) []lang.StringOrSymbol {
+			keys, _ := r.proxyOwnPropertyKeys(p)
+			return keys
+		},
+	}
+}
+
+// proxyGetPrototypeOf is specified in 9.5.1.
+func proxyGetPrototypeOf(p *lang.Object) (lang.Value, errors.Error) {
+	trap, handler, target, err := proxyTrap(p, "getPrototypeOf")
+	if err != nil {
+		return nil, err
+	}
+	if trap == nil {
+		return target.GetPrototypeOf(), nil
+	}
+	handlerProto, err := lang.Call(trap, handler, target)
+	if err != nil {
+		return nil, err
+	}
+	if handlerProto.Type() != lang.TypeObject && handlerProto != lang.Null {
+		return nil, errors.NewTypeError("'getPrototypeOf' on proxy: trap returned neither object nor null")
+	}
+	if target.IsExtensible() {
+		return handlerProto, nil
+	}
+	if !lang.InternalSameValue(handlerProto, target.GetPrototypeOf()) {
+		return nil, errors.NewTypeError("'getPrototypeOf' on proxy: proxy target is non-extensible but the trap did not return its actual prototype")
+	}
+	return handlerProto, nil
+}
+
+// proxySetPrototypeOf is specified in 9.5.2.
+func proxySetPrototypeOf(p *lang.Object, v lang.Value) (bool, errors.Error) {
+	trap, handler, target, err := proxyTrap(p, "setPrototypeOf")
+	if err != nil {
+		return false, err
+	}
+	if trap == nil {
+		return bool(target.SetPrototypeOf(v)), nil
+	}
+	result, err := lang.Call(trap, handler, target, v)
+	if err != nil {
+		return false, err
+	}
+	if !lang.ToBoolean(result) {
+		return false, nil
+	}
+	if target.IsExtensible() {
+		return true, nil
+	}
+	if !lang.InternalSameValue(v, target.GetPrototypeOf()) {
+		return false, errors.NewTypeError("'setPrototypeOf' on proxy: trap returned truish for setting a new prototype on the non-extensible proxy target")
+	}
+	return true, nil
+}
+
+// proxyIsExtensible is specified in 9.5.3.
+func proxyIsExtensible(p *lang.Object) (bool, errors.Error) {
+	trap, handler, target, err := proxyTrap(p, "isExtensible")
+	if err != nil {
+		return false, err
+	}
+	if trap == nil {
+		return bool(target.IsExtensible()), nil
+	}
+	result, err := lang.Call(trap, handler, target)
+	if err != nil {
+		return false, err
+	}
+	if lang.ToBoolean(result) != target.IsExtensible() {
+		return false, errors.NewTypeError("'isExtensible' on proxy: trap result does not reflect extensibility of proxy target")
+	}
+	return bool(lang.ToBoolean(result)), nil
+}
+
+// proxyPreventExtensions is specified in 9.5.4.
+func proxyPreventExtensions(p *lang.Object) (bool, errors.Error) {
+	trap, handler, target, err := proxyTrap(p, "preventExtensions")
+	if err != nil {
+		return false, err
+	}
+	if trap == nil {
+		return bool(target.PreventExtensions()), nil
+	}
+	result, err := lang.Call(trap, handler, target)
+	if err != nil {
+		return false, err
+	}
+	if lang.ToBoolean(result) && target.IsExtensible() {
+		return false, errors.NewTypeError("'preventExtensions' on proxy: trap returned truish but the proxy target is extensible")
+	}
+	return bool(lang.ToBoolean(result)), nil
+}
+
+// proxyGetOwnProperty is specified in 9.5.5.
+func (r *Realm) proxyGetOwnProperty(p *lang.Object, key lang.StringOrSymbol) (*lang.Property, errors.Error) {
+	trap, handler, target, err := proxyTrap(p, "getOwnPropertyDescriptor")
+	if err != nil {
+		return nil, err
+	}
+	if trap == nil {
+		return target.GetOwnProperty(key), nil
+	}
+	trapResultObj, err := lang.Call(trap, handler, target, key.Underlying())
+	if err != nil {
+		return nil, err
+	}
+	if trapResultObj.Type() != lang.TypeObject && trapResultObj != lang.Undefined {
+		return nil, errors.NewTypeError("'getOwnPropertyDescriptor' on proxy: trap returned neither object nor undefined")
+	}
+	targetDesc := target.GetOwnProperty(key)
+	if trapResultObj == lang.Undefined {
+		if targetDesc == nil {
+			return nil, nil
+		}
+		if !targetDesc.Configurable() {
+			return nil, errors.NewTypeError("'getOwnPropertyDescriptor' on proxy: trap returned undefined for a non-configurable property")
+		}
+		if !target.IsExtensible() {
+			return nil, errors.NewTypeError("'getOwnPropertyDescriptor' on proxy: trap returned undefined for a property of the non-extensible proxy target")
+		}
+		return nil, nil
+	}
+	extensibleTarget := target.IsExtensible()
+	resultDesc, err := lang.ToPropertyDescriptor(trapResultObj)
+	if err != nil {
+		return nil, err
+	}
+	resultDesc = lang.CompletePropertyDescriptor(resultDesc)
+	if !target.IsCompatiblePropertyDescriptor(bool(extensibleTarget), resultDesc, targetDesc) {
+		return nil, errors.NewTypeError("'getOwnPropertyDescriptor' on proxy: trap returned a descriptor that is incompatible with the property of the proxy target")
+	}
+	if !resultDesc.Configurable() {
+		if targetDesc == nil || targetDesc.Configurable() {
+			return nil, errors.NewTypeError("'getOwnPropertyDescriptor' on proxy: trap reported non-configurability for a configurable property")
+		}
+		if resultDesc.Has(lang.FieldNameWritable) && !bool(resultDesc.Writable()) && bool(targetDesc.Writable()) {
+			return nil, errors.NewTypeError("'getOwnPropertyDescriptor' on proxy: trap reported a non-writable property for a writable property")
+		}
+	}
+	return resultDesc, nil
+}
+
+// proxyDefineOwnProperty is specified in 9.5.6.
+func (r *Realm) proxyDefineOwnProperty(p *lang.Object, key lang.StringOrSymbol, desc *lang.Property) (lang.Boolean, errors.Error) {
+	trap, handler, target, err := proxyTrap(p, "defineProperty")
+	if err != nil {
+		return lang.False, err
+	}
+	if trap == nil {
+		return target.DefineOwnProperty(key, desc)
+	}
+	result, err := lang.Call(trap, handler, target, key.Underlying(), lang.FromPropertyDescriptor(r, desc))
+	if err != nil {
+		return lang.False, err
+	}
+	if !lang.ToBoolean(result) {
+		return lang.False, nil
+	}
+	targetDesc := target.GetOwnProperty(key)
+	extensibleTarget := target.IsExtensible()
+	settingConfigFalse := desc.Has(lang.FieldNameConfigurable) && !bool(desc.Configurable())
+	if targetDesc == nil {
+		if !extensibleTarget {
+			return lang.False, errors.NewTypeError("'defineProperty' on proxy: trap returned truish for adding a property to the non-extensible proxy target")
+		}
+		if settingConfigFalse {
+			return lang.False, errors.NewTypeError("'defineProperty' on proxy: trap returned truish for defining a non-configurable property which does not exist on the proxy target")
+		}
+		return lang.True, nil
+	}
+	if !target.IsCompatiblePropertyDescriptor(bool(extensibleTarget), desc, targetDesc) {
+		return lang.False, errors.NewTypeError("'defineProperty' on proxy: trap returned truish for adding a property that is incompatible with the property of the proxy target")
+	}
+	if settingConfigFalse && bool(targetDesc.Configurable()) {
+		return lang.False, errors.NewTypeError("'defineProperty' on proxy: trap returned truish for defining a non-configurable property which is configurable on the proxy target")
+	}
+	if targetDesc.IsDataDescriptor() && !targetDesc.Configurable() && targetDesc.Writable() &&
+		lang.Boolean(desc.Has(lang.FieldNameWritable)) && !desc.Writable() {
+		return lang.False, errors.NewTypeError("'defineProperty' on proxy: trap returned truish for defining a non-writable property which is writable on the proxy target")
+	}
+	return lang.True, nil
+}
+
+// proxyHasProperty is specified in 9.5.7.
+func proxyHasProperty(p *lang.Object, key lang.StringOrSymbol) (bool, errors.Error) {
+	trap, handler, target, err := proxyTrap(p, "has")
+	if err != nil {
+		return false, err
+	}
+	if trap == nil {
+		return bool(target.HasProperty(key)), nil
+	}
+	result, err := lang.Call(trap, handler, target, key.Underlying())
+	if err != nil {
+		return false, err
+	}
+	if !lang.ToBoolean(result) {
+		if targetDesc := target.GetOwnProperty(key); targetDesc != nil {
+			if !targetDesc.Configurable() {
+				return false, errors.NewTypeError("'has' on proxy: trap returned falsish for a non-configurable property")
+			}
+			if !target.IsExtensible() {
+				return false, errors.NewTypeError("'has' on proxy: trap returned falsish but the proxy target is not extensible")
+			}
+		}
+	}
+	return bool(lang.ToBoolean(result)), nil
+}
+
+// proxyGet is specified in 9.5.8.
+func proxyGet(p *lang.Object, key lang.StringOrSymbol, receiver lang.Value) (lang.Value, errors.Error) {
+	trap, handler, target, err := proxyTrap(p, "get")
+	if err != nil {
+		return nil, err
+	}
+	if trap == nil {
+		return target.Get(key, receiver)
+	}
+	trapResult, err := lang.Call(trap, handler, target, key.Underlying(), receiver)
+	if err != nil {
+		return nil, err
+	}
+	if targetDesc := target.GetOwnProperty(key); targetDesc != nil && !targetDesc.Configurable() {
+		if bool(targetDesc.IsDataDescriptor() && !targetDesc.Writable()) && !lang.InternalSameValue(trapResult, targetDesc.Value()) {
+			return nil, errors.NewTypeError("'get' on proxy: trap returned a different value for a non-writable, non-configurable property")
+		}
+		if targetDesc.IsAccessorDescriptor() && targetDesc.Getter() == nil && trapResult != lang.Undefined {
+			return nil, errors.NewTypeError("'get' on proxy: trap returned a value for a non-configurable accessor property without a getter")
+		}
+	}
+	return trapResult, nil
+}
+
+// proxySet is specified in 9.5.9.
+func proxySet(p *lang.Object, key lang.StringOrSymbol, v, receiver lang.Value) (lang.Boolean, errors.Error) {
+	trap, handler, target, err := proxyTrap(p, "set")
+	if err != nil {
+		return lang.False, err
+	}
+	if trap == nil {
+		return target.Set(key, v, receiver)
+	}
+	result, err := lang.Call(trap, handler, target, key.Underlying(), v, receiver)
+	if err != nil {
+		return lang.False, err
+	}
+	if !lang.ToBoolean(result) {
+		return lang.False, nil
+	}
+	if targetDesc := target.GetOwnProperty(key); targetDesc != nil && !targetDesc.Configurable() {
+		if bool(targetDesc.IsDataDescriptor() && !targetDesc.Writable()) && !lang.InternalSameValue(v, targetDesc.Value()) {
+			return lang.False, errors.NewTypeError("'set' on proxy: trap returned truish for a non-writable, non-configurable property with a different value")
+		}
+		if targetDesc.IsAccessorDescriptor() && targetDesc.Setter() == nil {
+			return lang.False, errors.NewTypeError("'set' on proxy: trap returned truish for a non-configurable accessor property without a setter")
+		}
+	}
+	return lang.True, nil
+}
+
+// proxyDelete is specified in 9.5.10.
+func proxyDelete(p *lang.Object, key lang.StringOrSymbol) (bool, errors.Error) {
+	trap, handler, target, err := proxyTrap(p, "deleteProperty")
+	if err != nil {
+		return false, err
+	}
+	if trap == nil {
+		return bool(target.Delete(key)), nil
+	}
+	result, err := lang.Call(trap, handler, target, key.Underlying())
+	if err != nil {
+		return false, err
+	}
+	if !lang.ToBoolean(result) {
+		return false, nil
+	}
+	targetDesc := target.GetOwnProperty(key)
+	if targetDesc == nil {
+		return true, nil
+	}
+	if !targetDesc.Configurable() {
+		return false, errors.NewTypeError("'deleteProperty' on proxy: trap returned truish for a non-configurable property")
+	}
+	if !target.IsExtensible() {
+		return false, errors.NewTypeError("'deleteProperty' on proxy: trap returned truish for a property of the non-extensible proxy target")
+	}
+	return true, nil
+}
+
+// proxyOwnPropertyKeys is specified in 9.5.11.
+func (r *Realm) proxyOwnPropertyKeys(p *lang.Object) ([]lang.StringOrSymbol, errors.Error) {
+	trap, handler, target, err := proxyTrap(p, "ownKeys")
+	if err != nil {
+		return nil, err
+	}
+	if trap == nil {
+		return target.OwnPropertyKeys(), nil
+	}
+	trapResultArray, err := lang.Call(trap, handler, target)
+	if err != nil {
+		return nil, err
+	}
+	elements, err := lang.CreateListFromArrayLike(trapResultArray, r.work, lang.TypeString, lang.TypeSymbol)
+	if err != nil {
+		return nil, err
+	}
+	trapResult := make([]lang.StringOrSymbol, len(elements))
+	unchecked := make(map[interface{}]bool, len(elements)) // keys as returned by mapKey
+	for i, e := range elements {
+		if unchecked[mapKey(e)] {
+			return nil, errors.NewTypeError("'ownKeys' on proxy: trap returned duplicate entries")
+		}
+		trapResult[i] = lang.NewStringOrSymbol(e)
+		unchecked[mapKey(e)] = true
+	}
+
+	extensibleTarget := target.IsExtensible()
+	var configurableKeys, nonconfigurableKeys []lang.StringOrSymbol
+	for _, k := range target.OwnPropertyKeys() {
+		if desc := target.GetOwnProperty(k); desc != nil && !desc.Configurable() {
+			nonconfigurableKeys = append(nonconfigurableKeys, k)
+		} else {
+			configurableKeys = append(configurableKeys, k)
+		}
+	}
+	if extensibleTarget && len(nonconfigurableKeys) == 0 {
+		return trapResult, nil
+	}
+	for _, k := range nonconfigurableKeys {
+		if !unchecked[mapKey(k.Underlying())] {
+			return nil, errors.NewTypeError("'ownKeys' on proxy: trap result did not include a non-configurable key")
+		}
+		delete(unchecked, mapKey(k.Underlying()))
+	}
+	if extensibleTarget {
+		return trapResult, nil
+	}
+	for _, k := range configurableKeys {
+		if !unchecked[mapKey(k.Underlying())] {
+			return nil, errors.NewTypeError("'ownKeys' on proxy: trap result did not include a key of the non-extensible proxy target")
+		}
+		delete(unchecked, mapKey(k.Underlying()))
+	}
+	if len(unchecked) != 0 {
+		return nil, errors.NewTypeError("'ownKeys' on proxy: trap returned extra keys but the proxy target is non-extensible")
+	}
+	return trapResult, nil
+}
+
+// proxyCall is the Call internal method of proxies, whose target is
+// callable.
+// proxyCall is specified in 9.5.12.
+func (r *Realm) proxyCall(p *lang.Object, this lang.Value, args []lang.Value) (lang.Value, errors.Error) {
+	trap, handler, target, err := proxyTrap(p, "apply")
+	if err != nil {
+		return nil, err
+	}
+	if trap == nil {
+		return lang.Call(target, this, args...)
+	}
+	return lang.Call(trap, handler, target, this, lang.CreateArrayFromList(r, args))
+}
+
+// proxyConstruct is the Construct internal method of proxies, whose
+// target is a constructor.
+// proxyConstruct is specified in 9.5.13.
+func (r *Realm) proxyConstruct(p *lang.Object, newTarget *lang.Object, args []lang.Value) (*lang.Object, errors.Error) {
+	trap, handler, target, err := proxyTrap(p, "construct")
+	if err != nil {
+		return nil, err
+	}
+	if trap == nil {
+		return lang.Construct(target, newTarget, args...)
+	}
+	newObj, err := lang.Call(trap, handler, target, lang.CreateArrayFromList(r, args), newTarget)
+	if err != nil {
+		return nil, err
+	}
+	o, ok := newObj.(*lang.Object)
+	if !ok {
+		return nil, errors.NewTypeError("'construct' on proxy: trap returned non-object")
+	}
+	return o, nil
+}
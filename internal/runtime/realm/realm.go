@@ -8,21 +8,18 @@ import (
 	"github.com/gojisvm/gojis/internal/runtime/lang"
 )
 
-// Intrinsic names used by the specification.
-// The specification denotes the usage of these names as
-// e.g. %ThrowTypeError% (enclosed in '%').
-const (
-	IntrinsicNameObjectPrototype   = "ObjectPrototype"
-	IntrinsicNameFunctionPrototype = "FunctionPrototype"
-	IntrinsicNameThrowTypeError    = "ThrowTypeError"
-)
-
 var (
 	currentRealm atomic.Value // holds *realm.Realm
 )
 
-// CurrentRealm returns the current realm as used in the specification.
-func CurrentRealm() *Realm { return currentRealm.Load().(*Realm) }
+// CurrentRealm returns the current realm as used in the specification, or
+// nil if no current realm has been set.
+func CurrentRealm() *Realm {
+	r, _ := currentRealm.Load().(*Realm)
+	return r
+}
+
+var _ binding.Realm = (*Realm)(nil) // ensure that Realm can be used to evaluate references
 
 // Realm is a struct that contains fields specified in
 // 8.2.
@@ -32,6 +29,71 @@ type Realm struct {
 	GlobalEnv   lang.Value                   // Object or Undefined
 	TemplateMap map[interface{}]*lang.Object // Parse Node -> Object
 	HostDefined lang.InternalValue
+
+	// CreateDynamicFunction creates a function from the arguments of a call
+	// to the Function constructor, whose last argument is the body and whose
+	// other arguments are the parameters. NewTarget is Undefined if the
+	// constructor was called as a function. It is set by the host that
+	// evaluates ECMAScript code, the Function constructor throws a
+	// SyntaxError if it is nil.
+	// CreateDynamicFunction is specified in 19.2.1.1.1.
+	CreateDynamicFunction func(newTarget lang.Value, args []lang.Value) (lang.Value, errors.Error)
+	// PerformEval evaluates the given source text as indirect eval, in the
+	// global environment of the realm. It is set by the host that evaluates
+	// ECMAScript code, eval throws a SyntaxError if it is nil.
+	// PerformEval is specified in 18.2.1.1.
+	PerformEval func(x lang.Value) (lang.Value, errors.Error)
+	// HostEnqueuePromiseJob adds a job to the PromiseJobs queue of the
+	// agent, that calls the given function with the given arguments. It is
+	// set by the agent that the realm belongs to. If it is nil, promise
+	// reactions are never run.
+	// EnqueueJob is specified in 8.4.1.
+	HostEnqueuePromiseJob func(job BuiltinFunction, args []lang.Value)
+	// StackTrace returns the frames of the stack trace of the running
+	// execution context, starting with the innermost frame, as they are
+	// printed in the stack property of error objects. If fn is a function,
+	// the frames up to and including the innermost frame of fn are left
+	// out, and no frames are returned if there is no frame of fn. It is set
+	// by the host that evaluates ECMAScript code, error objects have no
+	// frames if it is nil. StackTrace is not part of the specification.
+	StackTrace func(fn lang.Value) []string
+	// Work is called by built-in functions while they do work that is
+	// proportional to the size of their arguments, like matching a regular
+	// expression or iterating over the elements of an array, with the
+	// amount of work that has been done since the last call. It is set by
+	// the host that evaluates ECMAScript code, which counts the work as
+	// steps, and may panic to abort the evaluation, if it has been
+	// interrupted or exceeds its budget. Work is not part of the
+	// specification.
+	Work func(n int)
+	// CallStack limits the depth of nested calls of built-in functions,
+	// bound functions and proxies, which have no execution context. It is
+	// set by the agent that the realm belongs to. If it is nil, the depth of
+	// these calls is only limited by the calls of ECMAScript functions
+	// between them. CallStack is not part of the specification.
+	CallStack CallStack
+
+	// symbolRegistry is the GlobalSymbolRegistry of the realm, that maps
+	// the keys of Symbol.for to their symbols, as specified in 19.4.2.2.
+	symbolRegistry map[string]*lang.Symbol
+	// joining holds the objects that Array.prototype.join is currently
+	// called on, to detect cyclic arrays.
+	joining map[*lang.Object]bool
+	// proxyMethods are the internal methods of the proxy exotic objects
+	// of the realm.
+	proxyMethods *lang.ExoticMethods
+	// heap is the heap that the objects of the realm are accounted to.
+	heap *lang.Heap
+}
+
+// CallStack counts nested calls that have no execution context against the
+// maximum depth of the execution context stack.
+type CallStack interface {
+	// Enter counts a nested call, and returns a RangeError if the maximum
+	// depth has been reached.
+	Enter() errors.Error
+	// Leave ends a nested call that was counted by Enter.
+	Leave()
 }
 
 // Type returns lang.TypeInternal.
@@ -46,22 +108,41 @@ func (r *Realm) Value() interface{} { return r }
 // CreateRealm itself is specified in 8.2.1.
 func CreateRealm() *Realm {
 	r := new(Realm)
+	r.heap = lang.NewHeap()
 	CreateIntrinsics(r)
 	r.GlobalObj = lang.Undefined
 	r.GlobalEnv = lang.Undefined
 	r.TemplateMap = make(map[interface{}]*lang.Object)
+	r.HostDefined = lang.Undefined
 	return r
 }
 
-// CreateIntrinsics sets intrinsic objects of a record as specified
-// in 8.2.2.
-func CreateIntrinsics(r *Realm) {
-	r.Intrinsics = lang.NewRecord()
-	objProto := lang.ObjectCreate(lang.Null)
-	r.Intrinsics.SetField(IntrinsicNameObjectPrototype, objProto)
-	// FIXME: %ThrowTypeError% as in 8.2.2 and 9.2.9.1
+// Heap returns the heap that the objects of the realm are accounted to.
+func (r *Realm) Heap() *lang.Heap {
+	return r.heap
+}
 
-	panic("TODO: 8.2.2")
+// work reports the given amount of work to the host, see Realm#Work.
+func (r *Realm) work(n int) {
+	if r.Work != nil {
+		r.Work(n)
+	}
+}
+
+// enter counts a nested call on the CallStack of the realm, see
+// Realm#CallStack.
+func (r *Realm) enter() errors.Error {
+	if r.CallStack == nil {
+		return nil
+	}
+	return r.CallStack.Enter()
+}
+
+// leave ends a nested call that was counted by enter.
+func (r *Realm) leave() {
+	if r.CallStack != nil {
+		r.CallStack.Leave()
+	}
 }
 
 // GetIntrinsicObject returns the intrinsic object of the
@@ -75,6 +156,25 @@ func (r *Realm) GetIntrinsicObject(n string) lang.Value {
 	return val.(lang.Value)
 }
 
+// intrinsic returns the intrinsic object of the realm with the given name.
+// It must only be used for intrinsics that are known to exist.
+func (r *Realm) intrinsic(n string) *lang.Object {
+	return r.GetIntrinsicObject(n).(*lang.Object)
+}
+
+// GlobalObject returns the global object of the realm. It must only be
+// called after the global object has been set with SetRealmGlobalObject.
+func (r *Realm) GlobalObject() *lang.Object {
+	return r.GlobalObj.(*lang.Object)
+}
+
+// GlobalEnvironment returns the global environment of the realm. It must
+// only be called after the global object has been set with
+// SetRealmGlobalObject.
+func (r *Realm) GlobalEnvironment() *binding.GlobalEnvironment {
+	return r.GlobalEnv.(*binding.GlobalEnvironment)
+}
+
 // SetRealmGlobalObject sets the global object of a realm, as specified
 // in 8.2.3.
 // If the passed global object is Undefined, a new object created
@@ -82,7 +182,7 @@ func (r *Realm) GetIntrinsicObject(n string) lang.Value {
 // used instead.
 func (r *Realm) SetRealmGlobalObject(globalObj, thisValue lang.Value) *Realm {
 	if globalObj == lang.Undefined {
-		panic("TODO: 8.2.3")
+		globalObj = lang.ObjectCreate(r.intrinsic(IntrinsicNameObjectPrototype))
 	}
 
 	if thisValue == lang.Undefined {
@@ -97,35 +197,115 @@ func (r *Realm) SetRealmGlobalObject(globalObj, thisValue lang.Value) *Realm {
 	return r
 }
 
+// SetDefaultGlobalBindings defines the properties of the global object
+// that are specified in clause 18 on the global object of the realm, and
+// returns the global object.
 // SetDefaultGlobalBindings is specified in 8.2.4.
-// TODO: improve godoc
 func (r *Realm) SetDefaultGlobalBindings() lang.Value {
-	global := r.GlobalObj.(*lang.Object)
-	_ = global
-	panic("TODO: for every property\n" + `2. For each property of the Global Object specified in clause 18, do
-	a. Let name be the String value of the property name.
-	b. Let desc be the fully populated data property descriptor for the property containing the specified
-	attributes for the property. For properties listed in 18.2, 18.3, or 18.4 the value of the [[Value]] attribute is
-	the corresponding intrinsic object from realmRec.
-	c. Perform ? DefinePropertyOrThrow(global, name, desc).`)
+	global := r.GlobalObject()
+
+	// 18.1, value properties of the global object
+	defineValue(global, "Infinity", lang.PosInfinity)
+	defineValue(global, "NaN", lang.NaN)
+	defineValue(global, "undefined", lang.Undefined)
+	defineMethodProperty(global, "globalThis", r.GlobalEnvironment().GlobalThisValue)
+
+	// 18.2, 18.3 and 18.4, function properties, constructor properties and
+	// other properties of the global object
+	for _, name := range globalBindings {
+		defineMethodProperty(global, name, r.intrinsic(name))
+	}
+
+	return global
+}
+
+// globalBindings holds the names of the function, constructor and other
+// properties of the global object, which are the names of the intrinsics
+// that are their values, as specified in 18.2, 18.3 and 18.4.
+var globalBindings = []string{
+	// 18.2, function properties
+	IntrinsicNameEval,
+	IntrinsicNameIsFinite,
+	IntrinsicNameIsNaN,
+	IntrinsicNameParseFloat,
+	IntrinsicNameParseInt,
+	IntrinsicNameDecodeURI,
+	IntrinsicNameDecodeURIComponent,
+	IntrinsicNameEncodeURI,
+	IntrinsicNameEncodeURIComponent,
+
+	// 18.3, constructor properties
+	IntrinsicNameAggregateError,
+	IntrinsicNameArray,
+	IntrinsicNameArrayBuffer,
+	IntrinsicNameBoolean,
+	IntrinsicNameDataView,
+	IntrinsicNameDate,
+	IntrinsicNameError,
+	IntrinsicNameEvalError,
+	IntrinsicNameFloat32Array,
+	IntrinsicNameFloat64Array,
+	IntrinsicNameFunction,
+	IntrinsicNameInt8Array,
+	IntrinsicNameInt16Array,
+	IntrinsicNameInt32Array,
+	IntrinsicNameMap,
+	IntrinsicNameNumber,
+	IntrinsicNameObject,
+	IntrinsicNamePromise,
+	IntrinsicNameProxy,
+	IntrinsicNameRangeError,
+	IntrinsicNameReferenceError,
+	IntrinsicNameRegExp,
+	IntrinsicNameSet,
+	IntrinsicNameSharedArrayBuffer,
+	IntrinsicNameString,
+	IntrinsicNameSymbol,
+	IntrinsicNameSyntaxError,
+	IntrinsicNameTypeError,
+	IntrinsicNameUint8Array,
+	IntrinsicNameUint8ClampedArray,
+	IntrinsicNameUint16Array,
+	IntrinsicNameUint32Array,
+	IntrinsicNameURIError,
+	IntrinsicNameWeakMap,
+	IntrinsicNameWeakSet,
+
+	// 18.4, other properties
+	IntrinsicNameAtomics,
+	IntrinsicNameJSON,
+	IntrinsicNameMath,
+	IntrinsicNameReflect,
 }
 
 // GetFunctionRealm returns the realm that the object is
 // belonging to. That is, if the object has a Realm internal slot,
 // its value will be returned.
-// If it is a proxy object, the proxy target's function realm will
+// If it is a bound function, the bound target function's realm will
 // be returned.
 // Otherwise, this function returns the current realm record.
 // GetFunctionRealm is specified in 7.3.22.
 func GetFunctionRealm(obj *lang.Object) *Realm {
-	panic("TODO")
+	if r, ok := obj.Realm.(*Realm); ok {
+		return r
+	}
+
+	if target, ok := obj.GetSlot(lang.SlotProxyTarget).(*lang.Object); ok {
+		return GetFunctionRealm(target)
+	}
+
+	if obj.HasSlot(lang.SlotBoundTargetFunction) {
+		return GetFunctionRealm(obj.GetSlot(lang.SlotBoundTargetFunction).(*lang.Object))
+	}
+
+	return CurrentRealm()
 }
 
 // OrdinaryCreateFromConstructor creates an object, whose prototype will be the prototype
 // of the passed constructor object. If that constructor's property is not set,
 // the intrinsic default prototype will be used instead.
 // OrdinaryCreateFromConstructor is specified in 9.1.13.
-func OrdinaryCreateFromConstructor(constructor *lang.Object, intrinsicDefaultProto lang.String, internalSlotsList ...lang.StringOrSymbol) (*lang.Object, errors.Error) {
+func OrdinaryCreateFromConstructor(constructor *lang.Object, intrinsicDefaultProto string, internalSlotsList ...lang.StringOrSymbol) (*lang.Object, errors.Error) {
 	proto, err := GetPrototypeFromConstructor(constructor, intrinsicDefaultProto)
 	if err != nil {
 		return nil, err
@@ -137,15 +317,29 @@ func OrdinaryCreateFromConstructor(constructor *lang.Object, intrinsicDefaultPro
 // GetPrototypeFromConstructor determines the [[Prototype]] value that should be used to create
 // an object corresponding to a specific constructor.
 // GetPrototypeFromConstructor is specified in 9.1.14.
-func GetPrototypeFromConstructor(constructor *lang.Object, intrinsicDefaultProto lang.String) (*lang.Object, errors.Error) {
-	proto, err := lang.Get(constructor, lang.NewStringOrSymbol(lang.NewString("prototype")))
+func GetPrototypeFromConstructor(constructor *lang.Object, intrinsicDefaultProto string) (*lang.Object, errors.Error) {
+	proto, err := lang.Get(constructor, lang.NewStringKey("prototype"))
 	if err != nil {
 		return nil, err
 	}
 
 	if proto.Type() != lang.TypeObject {
 		realm := GetFunctionRealm(constructor)
-		proto = realm.GetIntrinsicObject(intrinsicDefaultProto.Value().(string))
+		proto = realm.GetIntrinsicObject(intrinsicDefaultProto)
 	}
 	return proto.(*lang.Object), nil
 }
+
+// defineValue defines a data property that is neither writable, nor
+// enumerable, nor configurable, like the value properties of the global
+// object.
+func defineValue(o *lang.Object, name string, value lang.Value) {
+	_, _ = lang.DefinePropertyOrThrow(o, lang.NewStringKey(name), lang.NewDataProperty(value, lang.False, lang.False, lang.False))
+}
+
+// defineMethodProperty defines a data property that is writable and
+// configurable but not enumerable, which are the default attributes of
+// properties of built-in objects, as described in clause 17.
+func defineMethodProperty(o *lang.Object, name string, value lang.Value) {
+	_, _ = lang.CreateMethodProperty(o, lang.NewStringKey(name), value)
+}
@@ -0,0 +1,168 @@
+package realm
+
+import (
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+)
+
+// reflectTarget returns the given value as object, or a TypeError if it
+// is not an object.
+func reflectTarget(v lang.Value, method string) (*lang.Object, errors.Error) {
+	o, ok := v.(*lang.Object)
+	if !ok {
+		return nil, errors.NewTypeError("Reflect." + method + " called on non-object")
+	}
+	return o, nil
+}
+
+// reflectTargetAndKey returns the target and the property key that are
+// the first two arguments of most Reflect functions.
+func reflectTargetAndKey(args []lang.Value, method string) (*lang.Object, lang.StringOrSymbol, errors.Error) {
+	target, err := reflectTarget(argument(args, 0), method)
+	if err != nil {
+		return nil, lang.StringOrSymbol{}, err
+	}
+	p, err := lang.ToPropertyKey(argument(args, 1))
+	if err != nil {
+		return nil, lang.StringOrSymbol{}, err
+	}
+	return target, p, nil
+}
+
+// createReflect creates the Reflect object, as specified in 26.1.
+func (r *Realm) createReflect() {
+	reflect := lang.ObjectCreate(r.intrinsic(IntrinsicNameObjectPrototype))
+	r.Intrinsics.SetField(IntrinsicNameReflect, reflect)
+
+	r.defineFunction(reflect, "apply", 3, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		target, err := callable(argument(args, 0), "Reflect.apply target")
+		if err != nil {
+			return nil, err
+		}
+		argList, err := lang.CreateListFromArrayLike(argument(args, 2), r.work)
+		if err != nil {
+			return nil, err
+		}
+		return lang.Call(target, argument(args, 1), argList...)
+	})
+	r.defineFunction(reflect, "construct", 2, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		target := argument(args, 0)
+		if !lang.InternalIsConstructor(target) {
+			return nil, errors.NewTypeError("Reflect.construct target is not a constructor")
+		}
+		newTarget := target
+		if len(args) > 2 {
+			newTarget = args[2]
+			if !lang.InternalIsConstructor(newTarget) {
+				return nil, errors.NewTypeError("Reflect.construct newTarget is not a constructor")
+			}
+		}
+		argList, err := lang.CreateListFromArrayLike(argument(args, 1), r.work)
+		if err != nil {
+			return nil, err
+		}
+		return lang.Construct(target.(*lang.Object), newTarget.(*lang.Object), argList...)
+	})
+	r.defineFunction(reflect, "defineProperty", 3, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		target, p, err := reflectTargetAndKey(args, "defineProperty")
+		if err != nil {
+			return nil, err
+		}
+		desc, err := lang.ToPropertyDescriptor(argument(args, 2))
+		if err != nil {
+			return nil, err
+		}
+		return target.DefineOwnProperty(p, desc)
+	})
+	r.defineFunction(reflect, "deleteProperty", 2, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		target, p, err := reflectTargetAndKey(args, "deleteProperty")
+		if err != nil {
+			return nil, err
+		}
+		return target.Delete(p), nil
+	})
+	r.defineFunction(reflect, "get", 2, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		target, p, err := reflectTargetAndKey(args, "get")
+		if err != nil {
+			return nil, err
+		}
+		receiver := lang.Value(target)
+		if len(args) > 2 {
+			receiver = args[2]
+		}
+		return target.Get(p, receiver)
+	})
+	r.defineFunction(reflect, "getOwnPropertyDescriptor", 2, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		target, p, err := reflectTargetAndKey(args, "getOwnPropertyDescriptor")
+		if err != nil {
+			return nil, err
+		}
+		desc := target.GetOwnProperty(p)
+		if desc == nil {
+			return lang.Undefined, nil
+		}
+		return lang.FromPropertyDescriptor(r, desc), nil
+	})
+	r.defineFunction(reflect, "getPrototypeOf", 1, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		target, err := reflectTarget(argument(args, 0), "getPrototypeOf")
+		if err != nil {
+			return nil, err
+		}
+		return target.GetPrototypeOf(), nil
+	})
+	r.defineFunction(reflect, "has", 2, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		target, p, err := reflectTargetAndKey(args, "has")
+		if err != nil {
+			return nil, err
+		}
+		return target.HasProperty(p), nil
+	})
+	r.defineFunction(reflect, "isExtensible", 1, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		target, err := reflectTarget(argument(args, 0), "isExtensible")
+		if err != nil {
+			return nil, err
+		}
+		return target.IsExtensible(), nil
+	})
+	r.defineFunction(reflect, "ownKeys", 1, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		target, err := reflectTarget(argument(args, 0), "ownKeys")
+		if err != nil {
+			return nil, err
+		}
+		var keys []lang.Value
+		for _, k := range target.OwnPropertyKeys() {
+			keys = append(keys, k.Underlying())
+		}
+		return lang.CreateArrayFromList(r, keys), nil
+	})
+	r.defineFunction(reflect, "preventExtensions", 1, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		target, err := reflectTarget(argument(args, 0), "preventExtensions")
+		if err != nil {
+			return nil, err
+		}
+		return target.PreventExtensions(), nil
+	})
+	r.defineFunction(reflect, "set", 3, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		target, p, err := reflectTargetAndKey(args, "set")
+		if err != nil {
+			return nil, err
+		}
+		receiver := lang.Value(target)
+		if len(args) > 3 {
+			receiver = args[3]
+		}
+		return target.Set(p, argument(args, 2), receiver)
+	})
+	r.defineFunction(reflect, "setPrototypeOf", 2, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		target, err := reflectTarget(argument(args, 0), "setPrototypeOf")
+		if err != nil {
+			return nil, err
+		}
+		proto := argument(args, 1)
+		if proto.Type() != lang.TypeObject && proto != lang.Null {
+			return nil, errors.NewTypeError("Object prototype may only be an Object or null")
+		}
+		return target.SetPrototypeOf(proto), nil
+	})
+	defineToStringTag(reflect, "Reflect")
+}
@@ -0,0 +1,850 @@
+package realm
+
+import (
+	"math"
+
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+)
+
+// Internal slots of RegExp objects, besides lang.SlotRegExpMatcher, as
+// specified in 21.2.3.2.1.
+var (
+	slotOriginalSource = lang.NewStringKey("OriginalSource")
+	slotOriginalFlags  = lang.NewStringKey("OriginalFlags")
+)
+
+// regexpFlagGetters holds the names of the accessor properties of
+// %RegExpPrototype%, that return whether a flag is set, with their flag,
+// ordered like the flags getter returns them. See 21.2.5.
+var regexpFlagGetters = []struct {
+	name string
+	flag uint16
+}{
+	{"global", 'g'},
+	{"ignoreCase", 'i'},
+	{"multiline", 'm'},
+	{"dotAll", 's'},
+	{"unicode", 'u'},
+	{"sticky", 'y'},
+}
+
+// createRegExp creates the RegExp constructor and %RegExpPrototype%, as
+// specified in 21.2.
+func (r *Realm) createRegExp() {
+	regexpProto := lang.ObjectCreate(r.intrinsic(IntrinsicNameObjectPrototype))
+	r.Intrinsics.SetField(IntrinsicNameRegExpPrototype, regexpProto)
+
+	var regexp *lang.Object
+	regexp = r.newConstructor("RegExp", 2, regexpProto, func(newTarget lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		pattern, flags := argument(args, 0), argument(args, 1)
+		patternIsRegExp, err := IsRegExp(pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		nt, ok := newTarget.(*lang.Object)
+		if !ok {
+			nt = regexp
+			if patternIsRegExp && flags == lang.Undefined {
+				patternConstructor, err := get(pattern.(*lang.Object), key("constructor"))
+				if err != nil {
+					return nil, err
+				}
+				if patternConstructor == nt {
+					return pattern, nil
+				}
+			}
+		}
+
+		var p, f lang.Value
+		if po, ok := pattern.(*lang.Object); ok && po.HasSlot(lang.SlotRegExpMatcher) {
+			p = po.GetSlot(slotOriginalSource)
+			f = flags
+			if flags == lang.Undefined {
+				f = po.GetSlot(slotOriginalFlags)
+			}
+		} else if patternIsRegExp {
+			po := pattern.(*lang.Object)
+			if p, err = get(po, key("source")); err != nil {
+				return nil, err
+			}
+			f = flags
+			if flags == lang.Undefined {
+				if f, err = get(po, key("flags")); err != nil {
+					return nil, err
+				}
+			}
+		} else {
+			p, f = pattern, flags
+		}
+
+		o, err := RegExpAlloc(nt)
+		if err != nil {
+			return nil, err
+		}
+		return RegExpInitialize(o, p, f)
+	})
+	r.Intrinsics.SetField(IntrinsicNameRegExp, regexp)
+	r.defineSpecies(regexp)
+
+	r.defineRegExpPrototype(regexpProto)
+}
+
+// RegExpAlloc allocates a new RegExp object, whose prototype is determined
+// by the given constructor.
+// RegExpAlloc is specified in 21.2.3.2.1.
+func RegExpAlloc(newTarget *lang.Object) (*lang.Object, errors.Error) {
+	obj, err := OrdinaryCreateFromConstructor(newTarget, IntrinsicNameRegExpPrototype, lang.SlotRegExpMatcher, slotOriginalSource, slotOriginalFlags)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := lang.DefinePropertyOrThrow(obj, key("lastIndex"), lang.NewDataProperty(lang.Undefined, lang.True, lang.False, lang.False)); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// RegExpInitialize compiles the given pattern with the given flags, and
+// stores the result in the given RegExp object.
+// RegExpInitialize is specified in 21.2.3.2.2.
+func RegExpInitialize(obj *lang.Object, pattern, flags lang.Value) (*lang.Object, errors.Error) {
+	p, f := lang.String{}, lang.String{}
+	var err errors.Error
+	if pattern != lang.Undefined {
+		if p, err = lang.ToString(pattern); err != nil {
+			return nil, err
+		}
+	}
+	if flags != lang.Undefined {
+		if f, err = lang.ToString(flags); err != nil {
+			return nil, err
+		}
+	}
+	parsedFlags, err := parseRegExpFlags(f)
+	if err != nil {
+		return nil, err
+	}
+	prog, err := compileRegExp(p, parsedFlags)
+	if err != nil {
+		return nil, err
+	}
+	obj.SetSlot(slotOriginalSource, p)
+	obj.SetSlot(slotOriginalFlags, f)
+	obj.SetSlot(lang.SlotRegExpMatcher, prog)
+	if err := set(obj, key("lastIndex"), lang.Zero); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// RegExpCreate creates a new RegExp object from the given pattern and
+// flags, as done by regular expression literals.
+// RegExpCreate is specified in 21.2.3.2.3.
+func (r *Realm) RegExpCreate(pattern, flags lang.Value) (*lang.Object, errors.Error) {
+	obj, err := RegExpAlloc(r.intrinsic(IntrinsicNameRegExp))
+	if err != nil {
+		return nil, err
+	}
+	return RegExpInitialize(obj, pattern, flags)
+}
+
+// IsRegExp is used to determine whether the value has a truthy @@match
+// property, or, if it has no such property, a RegExpMatcher internal slot.
+// Unlike lang.InternalIsRegExp, an exception thrown by the @@match getter
+// is returned.
+// IsRegExp is specified in 7.2.8.
+func IsRegExp(v lang.Value) (bool, errors.Error) {
+	o, ok := v.(*lang.Object)
+	if !ok {
+		return false, nil
+	}
+	matcher, err := get(o, symbolKey(lang.SymbolMatch))
+	if err != nil {
+		return false, err
+	}
+	if matcher != lang.Undefined {
+		return bool(lang.ToBoolean(matcher)), nil
+	}
+	return o.HasSlot(lang.SlotRegExpMatcher), nil
+}
+
+// EscapeRegExpPattern returns the given pattern in a form, that can be
+// used as the body of a regular expression literal.
+// EscapeRegExpPattern is specified in 21.2.3.2.4.
+func EscapeRegExpPattern(p lang.String) lang.String {
+	if len(p) == 0 {
+		return lang.NewString("(?:)")
+	}
+	var result lang.String
+	inClass := false
+	for i := 0; i < len(p); i++ {
+		switch c := p[i]; c {
+		case '\\':
+			result = append(result, c)
+			if i+1 < len(p) {
+				i++
+				result = append(result, escapeLineTerminator(p[i])...)
+			}
+			continue
+		case '[':
+			inClass = true
+		case ']':
+			inClass = false
+		case '/':
+			if !inClass {
+				result = append(result, '\\', '/')
+				continue
+			}
+		}
+		result = append(result, escapeLineTerminator(p[i])...)
+	}
+	return result
+}
+
+// escapeLineTerminator returns an escape sequence for the given character,
+// if it is a line terminator, or the character itself otherwise.
+func escapeLineTerminator(c uint16) lang.String {
+	switch c {
+	case '\n':
+		return lang.NewString(`\n`)
+	case '\r':
+		return lang.NewString(`\r`)
+	case 0x2028:
+		return lang.NewString(`\u2028`)
+	case 0x2029:
+		return lang.NewString(`\u2029`)
+	}
+	return lang.String{c}
+}
+
+// thisRegExpObject returns the given value as object, if it is an object.
+func thisRegExpObject(v lang.Value, method string) (*lang.Object, errors.Error) {
+	if o, ok := v.(*lang.Object); ok {
+		return o, nil
+	}
+	return nil, errors.NewTypeError("RegExp.prototype." + method + " called on incompatible receiver")
+}
+
+// thisRegExp returns the given value as RegExp object, if it is one.
+func thisRegExp(v lang.Value, method string) (*lang.Object, errors.Error) {
+	if o, ok := v.(*lang.Object); ok && o.HasSlot(lang.SlotRegExpMatcher) {
+		return o, nil
+	}
+	return nil, errors.NewTypeError("RegExp.prototype." + method + " called on incompatible receiver")
+}
+
+// defineRegExpPrototype defines the properties of %RegExpPrototype%, as
+// specified in 21.2.5.
+func (r *Realm) defineRegExpPrototype(proto *lang.Object) {
+	r.defineFunction(proto, "exec", 1, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		R, err := thisRegExp(this, "exec")
+		if err != nil {
+			return nil, err
+		}
+		s, err := lang.ToString(argument(args, 0))
+		if err != nil {
+			return nil, err
+		}
+		return r.RegExpBuiltinExec(R, s)
+	})
+	r.defineGetter(proto, key("flags"), func(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+		R, err := thisRegExpObject(this, "flags")
+		if err != nil {
+			return nil, err
+		}
+		var result lang.String
+		for _, g := range regexpFlagGetters {
+			v, err := get(R, key(g.name))
+			if err != nil {
+				return nil, err
+			}
+			if lang.ToBoolean(v) {
+				result = append(result, g.flag)
+			}
+		}
+		return append(lang.String{}, result...), nil
+	})
+	for _, g := range regexpFlagGetters {
+		name, flag := g.name, g.flag
+		r.defineGetter(proto, key(name), func(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+			R, err := thisRegExpObject(this, name)
+			if err != nil {
+				return nil, err
+			}
+			if !R.HasSlot(slotOriginalFlags) {
+				if R == proto {
+					return lang.Undefined, nil
+				}
+				return nil, errors.NewTypeError("RegExp.prototype." + name + " getter called on non-RegExp object")
+			}
+			for _, c := range R.GetSlot(slotOriginalFlags).(lang.String) {
+				if c == flag {
+					return lang.True, nil
+				}
+			}
+			return lang.False, nil
+		})
+	}
+	r.defineGetter(proto, key("source"), func(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+		R, err := thisRegExpObject(this, "source")
+		if err != nil {
+			return nil, err
+		}
+		if !R.HasSlot(slotOriginalSource) {
+			if R == proto {
+				return str("(?:)"), nil
+			}
+			return nil, errors.NewTypeError("RegExp.prototype.source getter called on non-RegExp object")
+		}
+		return EscapeRegExpPattern(R.GetSlot(slotOriginalSource).(lang.String)), nil
+	})
+	r.defineFunction(proto, "test", 1, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		R, err := thisRegExpObject(this, "test")
+		if err != nil {
+			return nil, err
+		}
+		s, err := lang.ToString(argument(args, 0))
+		if err != nil {
+			return nil, err
+		}
+		match, err := r.RegExpExec(R, s)
+		if err != nil {
+			return nil, err
+		}
+		return lang.Boolean(match != lang.Null), nil
+	})
+	r.defineFunction(proto, "toString", 0, func(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+		R, err := thisRegExpObject(this, "toString")
+		if err != nil {
+			return nil, err
+		}
+		pattern, err := get(R, key("source"))
+		if err != nil {
+			return nil, err
+		}
+		p, err := lang.ToString(pattern)
+		if err != nil {
+			return nil, err
+		}
+		flags, err := get(R, key("flags"))
+		if err != nil {
+			return nil, err
+		}
+		f, err := lang.ToString(flags)
+		if err != nil {
+			return nil, err
+		}
+		return lang.Concat(lang.NewString("/"), p, lang.NewString("/"), f), nil
+	})
+	r.defineSymbolFunction(proto, lang.SymbolMatch, 1, r.regexpPrototypeMatch)
+	r.defineSymbolFunction(proto, lang.SymbolReplace, 2, r.regexpPrototypeReplace)
+	r.defineSymbolFunction(proto, lang.SymbolSearch, 1, r.regexpPrototypeSearch)
+	r.defineSymbolFunction(proto, lang.SymbolSplit, 2, r.regexpPrototypeSplit)
+
+	// B.2.5, additional properties of the RegExp.prototype object
+	r.defineFunction(proto, "compile", 2, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		o, err := thisRegExp(this, "compile")
+		if err != nil {
+			return nil, err
+		}
+		pattern, flags := argument(args, 0), argument(args, 1)
+		if po, ok := pattern.(*lang.Object); ok && po.HasSlot(lang.SlotRegExpMatcher) {
+			if flags != lang.Undefined {
+				return nil, errors.NewTypeError("Cannot supply flags when constructing one RegExp from another")
+			}
+			pattern, flags = po.GetSlot(slotOriginalSource), po.GetSlot(slotOriginalFlags)
+		}
+		return RegExpInitialize(o, pattern, flags)
+	})
+}
+
+// RegExpExec calls the exec method of the given object with the given
+// string, or RegExpBuiltinExec if the object has no callable exec property.
+// The result is an object or Null.
+// RegExpExec is specified in 21.2.5.2.1.
+func (r *Realm) RegExpExec(R *lang.Object, s lang.String) (lang.Value, errors.Error) {
+	exec, err := get(R, key("exec"))
+	if err != nil {
+		return nil, err
+	}
+	if lang.InternalIsCallable(exec) {
+		result, err := lang.CallValue(exec, R, s)
+		if err != nil {
+			return nil, err
+		}
+		if result != lang.Null && result.Type() != lang.TypeObject {
+			return nil, errors.NewTypeError("The result of RegExp exec must be an object or null")
+		}
+		return result, nil
+	}
+	if !R.HasSlot(lang.SlotRegExpMatcher) {
+		return nil, errors.NewTypeError("RegExp exec method called on incompatible receiver")
+	}
+	return r.RegExpBuiltinExec(R, s)
+}
+
+// RegExpBuiltinExec matches the given RegExp object against the given
+// string, starting at its lastIndex, and returns the match array, or Null
+// if there is no match.
+// RegExpBuiltinExec is specified in 21.2.5.2.2.
+func (r *Realm) RegExpBuiltinExec(R *lang.Object, s lang.String) (lang.Value, errors.Error) {
+	length := len(s)
+	li, err := get(R, key("lastIndex"))
+	if err != nil {
+		return nil, err
+	}
+	lastIndex, err := toLength(li)
+	if err != nil {
+		return nil, err
+	}
+	prog := R.GetSlot(lang.SlotRegExpMatcher).(*regexpProgram)
+	global, sticky := prog.flags.global, prog.flags.sticky
+	if !global && !sticky {
+		lastIndex = 0
+	}
+
+	var captures []int
+	for {
+		r.work(1)
+		if lastIndex > float64(length) {
+			if global || sticky {
+				if err := set(R, key("lastIndex"), lang.Zero); err != nil {
+					return nil, err
+				}
+			}
+			return lang.Null, nil
+		}
+		var ok bool
+		if captures, ok = prog.match(s, int(lastIndex), r.work); ok {
+			break
+		}
+		if sticky {
+			if err := set(R, key("lastIndex"), lang.Zero); err != nil {
+				return nil, err
+			}
+			return lang.Null, nil
+		}
+		lastIndex = float64(AdvanceStringIndex(s, int(lastIndex), prog.flags.unicode))
+	}
+
+	e := captures[1]
+	if global || sticky {
+		if err := set(R, key("lastIndex"), number(float64(e))); err != nil {
+			return nil, err
+		}
+	}
+
+	n := prog.groupCount
+	A, _ := lang.ArrayCreate(float64(n+1), r.intrinsic(IntrinsicNameArrayPrototype))
+	_, _ = lang.CreateDataProperty(A, key("index"), number(lastIndex))
+	_, _ = lang.CreateDataProperty(A, key("input"), s)
+	_, _ = lang.CreateDataProperty(A, key("0"), append(lang.String{}, s[captures[0]:e]...))
+	var groups lang.Value = lang.Undefined
+	if prog.hasNames {
+		g := lang.ObjectCreate(lang.Null)
+		g.SetHeap(r.heap)
+		groups = g
+	}
+	_, _ = lang.CreateDataProperty(A, key("groups"), groups)
+	for i := 1; i <= n; i++ {
+		var capturedValue lang.Value = lang.Undefined
+		if start, end := captures[2*i], captures[2*i+1]; start >= 0 && end >= 0 {
+			capturedValue = append(lang.String{}, s[start:end]...)
+		}
+		_, _ = lang.CreateDataProperty(A, indexKey(float64(i)), capturedValue)
+		if name := prog.groupNames[i]; name != "" {
+			_, _ = lang.CreateDataProperty(groups.(*lang.Object), key(name), capturedValue)
+		}
+	}
+	return A, nil
+}
+
+// AdvanceStringIndex returns the index after the given index of the string,
+// which skips surrogate pairs if unicode is true.
+// AdvanceStringIndex is specified in 21.2.5.2.3.
+func AdvanceStringIndex(s lang.String, index int, unicode bool) int {
+	if !unicode || index+1 >= len(s) {
+		return index + 1
+	}
+	_, size := codePointAt(s, index)
+	return index + size
+}
+
+// regexpPrototypeMatch is RegExp.prototype[@@match].
+// RegExp.prototype[@@match] is specified in 21.2.5.6.
+func (r *Realm) regexpPrototypeMatch(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+	rx, err := thisRegExpObject(this, "[Symbol.match]")
+	if err != nil {
+		return nil, err
+	}
+	s, err := lang.ToString(argument(args, 0))
+	if err != nil {
+		return nil, err
+	}
+	global, err := get(rx, key("global"))
+	if err != nil {
+		return nil, err
+	}
+	if !lang.ToBoolean(global) {
+		return r.RegExpExec(rx, s)
+	}
+
+	fullUnicode, err := get(rx, key("unicode"))
+	if err != nil {
+		return nil, err
+	}
+	if err := set(rx, key("lastIndex"), lang.Zero); err != nil {
+		return nil, err
+	}
+	A, _ := lang.ArrayCreate(0, r.intrinsic(IntrinsicNameArrayPrototype))
+	n := 0.0
+	for {
+		r.work(1)
+		result, err := r.RegExpExec(rx, s)
+		if err != nil {
+			return nil, err
+		}
+		if result == lang.Null {
+			if n == 0 {
+				return lang.Null, nil
+			}
+			return A, nil
+		}
+		matchStr, err := getString(result.(*lang.Object), key("0"))
+		if err != nil {
+			return nil, err
+		}
+		_, _ = lang.CreateDataProperty(A, indexKey(n), matchStr)
+		if len(matchStr) == 0 {
+			if err := r.advanceLastIndex(rx, s, fullUnicode); err != nil {
+				return nil, err
+			}
+		}
+		n++
+	}
+}
+
+// getString returns the value of the given property, converted to a
+// string.
+func getString(o *lang.Object, p lang.StringOrSymbol) (lang.String, errors.Error) {
+	v, err := get(o, p)
+	if err != nil {
+		return nil, err
+	}
+	return lang.ToString(v)
+}
+
+// advanceLastIndex advances the lastIndex of the given regular expression
+// after an empty match, as done by the global replace and match methods.
+func (r *Realm) advanceLastIndex(rx *lang.Object, s lang.String, fullUnicode lang.Value) errors.Error {
+	li, err := get(rx, key("lastIndex"))
+	if err != nil {
+		return err
+	}
+	thisIndex, err := toLength(li)
+	if err != nil {
+		return err
+	}
+	next := thisIndex + 1
+	if thisIndex < float64(len(s)) {
+		next = float64(AdvanceStringIndex(s, int(thisIndex), bool(lang.ToBoolean(fullUnicode))))
+	}
+	return set(rx, key("lastIndex"), number(next))
+}
+
+// regexpPrototypeReplace is RegExp.prototype[@@replace].
+// RegExp.prototype[@@replace] is specified in 21.2.5.8.
+func (r *Realm) regexpPrototypeReplace(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+	rx, err := thisRegExpObject(this, "[Symbol.replace]")
+	if err != nil {
+		return nil, err
+	}
+	s, err := lang.ToString(argument(args, 0))
+	if err != nil {
+		return nil, err
+	}
+	replaceValue := argument(args, 1)
+	functionalReplace := lang.InternalIsCallable(replaceValue)
+	var replaceString lang.String
+	if !functionalReplace {
+		if replaceString, err = lang.ToString(replaceValue); err != nil {
+			return nil, err
+		}
+	}
+
+	globalValue, err := get(rx, key("global"))
+	if err != nil {
+		return nil, err
+	}
+	global := bool(lang.ToBoolean(globalValue))
+	var fullUnicode lang.Value = lang.False
+	if global {
+		if fullUnicode, err = get(rx, key("unicode")); err != nil {
+			return nil, err
+		}
+		if err := set(rx, key("lastIndex"), lang.Zero); err != nil {
+			return nil, err
+		}
+	}
+
+	var results []*lang.Object
+	for {
+		r.work(1)
+		result, err := r.RegExpExec(rx, s)
+		if err != nil {
+			return nil, err
+		}
+		if result == lang.Null {
+			break
+		}
+		results = append(results, result.(*lang.Object))
+		if !global {
+			break
+		}
+		matchStr, err := getString(result.(*lang.Object), key("0"))
+		if err != nil {
+			return nil, err
+		}
+		if len(matchStr) == 0 {
+			if err := r.advanceLastIndex(rx, s, fullUnicode); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var accumulatedResult lang.String
+	nextSourcePosition := 0
+	for _, result := range results {
+		nCaptures, err := lengthOfArrayLike(result)
+		if err != nil {
+			return nil, err
+		}
+		nCaptures = math.Max(nCaptures-1, 0)
+		if nCaptures > lang.MaxListLength {
+			// the length comes from the result of a user-defined exec
+			return nil, errors.NewRangeError("Too many elements in array-like object")
+		}
+		matched, err := getString(result, key("0"))
+		if err != nil {
+			return nil, err
+		}
+		positionValue, err := get(result, key("index"))
+		if err != nil {
+			return nil, err
+		}
+		position, err := toInteger(positionValue)
+		if err != nil {
+			return nil, err
+		}
+		position = math.Max(math.Min(position, float64(len(s))), 0)
+
+		var captures []lang.Value
+		for n := 1.0; n <= nCaptures; n++ {
+			r.work(1)
+			capN, err := get(result, indexKey(n))
+			if err != nil {
+				return nil, err
+			}
+			if capN != lang.Undefined {
+				if capN, err = lang.ToString(capN); err != nil {
+					return nil, err
+				}
+			}
+			captures = append(captures, capN)
+		}
+		namedCaptures, err := get(result, key("groups"))
+		if err != nil {
+			return nil, err
+		}
+
+		var replacement lang.String
+		if functionalReplace {
+			replacerArgs := append([]lang.Value{matched}, captures...)
+			replacerArgs = append(replacerArgs, number(position), s)
+			if namedCaptures != lang.Undefined {
+				replacerArgs = append(replacerArgs, namedCaptures)
+			}
+			replValue, err := lang.CallValue(replaceValue, lang.Undefined, replacerArgs...)
+			if err != nil {
+				return nil, err
+			}
+			if replacement, err = lang.ToString(replValue); err != nil {
+				return nil, err
+			}
+		} else {
+			if namedCaptures != lang.Undefined {
+				if namedCaptures, err = r.toObject(namedCaptures); err != nil {
+					return nil, err
+				}
+			}
+			if replacement, err = r.GetSubstitution(matched, s, int(position), captures, namedCaptures, replaceString); err != nil {
+				return nil, err
+			}
+		}
+
+		if int(position) >= nextSourcePosition {
+			accumulatedResult = append(accumulatedResult, s[nextSourcePosition:int(position)]...)
+			accumulatedResult = append(accumulatedResult, replacement...)
+			nextSourcePosition = int(position) + len(matched)
+		}
+	}
+	if nextSourcePosition >= len(s) {
+		return append(lang.String{}, accumulatedResult...), nil
+	}
+	return lang.Concat(accumulatedResult, s[nextSourcePosition:]), nil
+}
+
+// regexpPrototypeSearch is RegExp.prototype[@@search].
+// RegExp.prototype[@@search] is specified in 21.2.5.9.
+func (r *Realm) regexpPrototypeSearch(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+	rx, err := thisRegExpObject(this, "[Symbol.search]")
+	if err != nil {
+		return nil, err
+	}
+	s, err := lang.ToString(argument(args, 0))
+	if err != nil {
+		return nil, err
+	}
+	previousLastIndex, err := get(rx, key("lastIndex"))
+	if err != nil {
+		return nil, err
+	}
+	if !lang.InternalSameValue(previousLastIndex, lang.Zero) {
+		if err := set(rx, key("lastIndex"), lang.Zero); err != nil {
+			return nil, err
+		}
+	}
+	result, err := r.RegExpExec(rx, s)
+	if err != nil {
+		return nil, err
+	}
+	currentLastIndex, err := get(rx, key("lastIndex"))
+	if err != nil {
+		return nil, err
+	}
+	if !lang.InternalSameValue(currentLastIndex, previousLastIndex) {
+		if err := set(rx, key("lastIndex"), previousLastIndex); err != nil {
+			return nil, err
+		}
+	}
+	if result == lang.Null {
+		return number(-1), nil
+	}
+	return get(result.(*lang.Object), key("index"))
+}
+
+// regexpPrototypeSplit is RegExp.prototype[@@split].
+// RegExp.prototype[@@split] is specified in 21.2.5.11.
+func (r *Realm) regexpPrototypeSplit(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+	rx, err := thisRegExpObject(this, "[Symbol.split]")
+	if err != nil {
+		return nil, err
+	}
+	s, err := lang.ToString(argument(args, 0))
+	if err != nil {
+		return nil, err
+	}
+	c, err := lang.SpeciesConstructor(rx, r.intrinsic(IntrinsicNameRegExp))
+	if err != nil {
+		return nil, err
+	}
+	flags, err := getString(rx, key("flags"))
+	if err != nil {
+		return nil, err
+	}
+	unicodeMatching := false
+	sticky := false
+	for _, f := range flags {
+		unicodeMatching = unicodeMatching || f == 'u'
+		sticky = sticky || f == 'y'
+	}
+	newFlags := flags
+	if !sticky {
+		newFlags = lang.Concat(flags, lang.NewString("y"))
+	}
+	splitter, err := lang.Construct(c, nil, rx, newFlags)
+	if err != nil {
+		return nil, err
+	}
+
+	A, _ := lang.ArrayCreate(0, r.intrinsic(IntrinsicNameArrayPrototype))
+	lengthA := 0.0
+	lim := float64(math.MaxUint32)
+	if limit := argument(args, 1); limit != lang.Undefined {
+		l, err := lang.ToUint32(limit)
+		if err != nil {
+			return nil, err
+		}
+		lim = l.Float64()
+	}
+	if lim == 0 {
+		return A, nil
+	}
+	size := len(s)
+	if size == 0 {
+		z, err := r.RegExpExec(splitter, s)
+		if err != nil {
+			return nil, err
+		}
+		if z != lang.Null {
+			return A, nil
+		}
+		_, _ = lang.CreateDataProperty(A, key("0"), s)
+		return A, nil
+	}
+
+	p, q := 0, 0
+	for q < size {
+		r.work(1)
+		if err := set(splitter, key("lastIndex"), number(float64(q))); err != nil {
+			return nil, err
+		}
+		z, err := r.RegExpExec(splitter, s)
+		if err != nil {
+			return nil, err
+		}
+		if z == lang.Null {
+			q = AdvanceStringIndex(s, q, unicodeMatching)
+			continue
+		}
+		li, err := get(splitter, key("lastIndex"))
+		if err != nil {
+			return nil, err
+		}
+		e, err := toLength(li)
+		if err != nil {
+			return nil, err
+		}
+		e = math.Min(e, float64(size))
+		if int(e) == p {
+			q = AdvanceStringIndex(s, q, unicodeMatching)
+			continue
+		}
+		_, _ = lang.CreateDataProperty(A, indexKey(lengthA), append(lang.String{}, s[p:q]...))
+		lengthA++
+		if lengthA == lim {
+			return A, nil
+		}
+		p = int(e)
+		zo := z.(*lang.Object)
+		numberOfCaptures, err := lengthOfArrayLike(zo)
+		if err != nil {
+			return nil, err
+		}
+		for i := 1.0; i < numberOfCaptures; i++ {
+			nextCapture, err := get(zo, indexKey(i))
+			if err != nil {
+				return nil, err
+			}
+			_, _ = lang.CreateDataProperty(A, indexKey(lengthA), nextCapture)
+			lengthA++
+			if lengthA == lim {
+				return A, nil
+			}
+		}
+		q = p
+	}
+	_, _ = lang.CreateDataProperty(A, indexKey(lengthA), append(lang.String{}, s[p:]...))
+	return A, nil
+}
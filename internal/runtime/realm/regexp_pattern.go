@@ -0,0 +1,1350 @@
+package realm
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"unicode"
+	"unicode/utf16"
+
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+)
+
+// maxCodePoint is the largest Unicode code point.
+const maxCodePoint = unicode.MaxRune
+
+// regexpFlags are the flags of a regular expression, as specified in
+// 21.2.3.2.2.
+type regexpFlags struct {
+	global, ignoreCase, multiline, dotAll, unicode, sticky bool
+}
+
+// parseRegExpFlags parses the given flags, and returns a SyntaxError if
+// they contain unknown or duplicate flags.
+func parseRegExpFlags(f lang.String) (regexpFlags, errors.Error) {
+	var flags regexpFlags
+	for _, c := range f {
+		var flag *bool
+		switch c {
+		case 'g':
+			flag = &flags.global
+		case 'i':
+			flag = &flags.ignoreCase
+		case 'm':
+			flag = &flags.multiline
+		case 's':
+			flag = &flags.dotAll
+		case 'u':
+			flag = &flags.unicode
+		case 'y':
+			flag = &flags.sticky
+		}
+		if flag == nil || *flag {
+			return flags, errors.NewSyntaxError("Invalid regular expression flags '" + f.String() + "'")
+		}
+		*flag = true
+	}
+	return flags, nil
+}
+
+// regexpProgram is the value of the RegExpMatcher internal slot of RegExp
+// objects, that is the compiled pattern of the regular expression.
+// The pattern semantics are specified in 21.2.2.
+type regexpProgram struct {
+	m          matcher
+	flags      regexpFlags
+	groupCount int
+	// groupNames holds the names of the capturing groups by their index,
+	// which is the empty string for unnamed groups.
+	groupNames []string
+	hasNames   bool
+}
+
+// Type returns lang.TypeInternal.
+func (*regexpProgram) Type() lang.Type { return lang.TypeInternal }
+
+// Value returns the program itself.
+func (p *regexpProgram) Value() interface{} { return p }
+
+// regexpWorkUnit is the number of steps of a match, after which they are
+// reported as work. A step is the match of a character or an iteration of
+// a quantifier, which includes the ones that are backtracked.
+const regexpWorkUnit = 1 << 10
+
+// match matches the program against the given input, starting at the given
+// index. If it matches, the captures are returned, as pairs of start and
+// end indices into the input, where the first pair is the whole match.
+// Undefined captures have the indices -1. The steps of the match are
+// reported to the given work function, see Realm#Work.
+func (p *regexpProgram) match(input lang.String, index int, work func(n int)) ([]int, bool) {
+	x := &matchState{
+		input:    input,
+		end:      index,
+		captures: make([]int, 2*(p.groupCount+1)),
+		flags:    p.flags,
+		work:     work,
+	}
+	for i := range x.captures {
+		x.captures[i] = -1
+	}
+	matched := p.m(x, func(*matchState) bool { return true })
+	if x.steps > 0 {
+		work(x.steps)
+	}
+	if !matched {
+		return nil, false
+	}
+	x.captures[0], x.captures[1] = index, x.end
+	return x.captures, true
+}
+
+// matchState is the State of a match, which is mutated while matching and
+// restored on backtracking.
+// The State is specified in 21.2.2.1.
+type matchState struct {
+	input    lang.String
+	end      int
+	captures []int
+	flags    regexpFlags
+
+	// steps is the number of steps that have not been reported to work
+	// yet.
+	steps int
+	work  func(n int)
+}
+
+// step counts the given number of steps of the match, and reports them
+// once there are regexpWorkUnit of them, so that matches that backtrack a
+// lot can be interrupted.
+func (x *matchState) step(n int) {
+	x.steps += n
+	if x.steps >= regexpWorkUnit {
+		steps := x.steps
+		x.steps = 0
+		x.work(steps)
+	}
+}
+
+// continuation is called with the state after a matcher matched, and
+// reports whether the rest of the pattern matched.
+// Continuations are specified in 21.2.2.1.
+type continuation func(x *matchState) bool
+
+// matcher matches a part of the pattern at the state, and calls the
+// continuation if it matched. If the match fails, the state is left
+// unchanged.
+// Matchers are specified in 21.2.2.1.
+type matcher func(x *matchState, c continuation) bool
+
+// charAt returns the character at the given index of the input, and its
+// length in code units. If the forward flag is false, the character that
+// ends at the given index is returned.
+func (x *matchState) charAt(e int, forward bool) (rune, int, bool) {
+	s := x.input
+	if forward {
+		if e >= len(s) {
+			return 0, 0, false
+		}
+		if x.flags.unicode && utf16.IsSurrogate(rune(s[e])) && s[e] < 0xDC00 && e+1 < len(s) && s[e+1] >= 0xDC00 && s[e+1] <= 0xDFFF {
+			return utf16.DecodeRune(rune(s[e]), rune(s[e+1])), 2, true
+		}
+		return rune(s[e]), 1, true
+	}
+	if e <= 0 {
+		return 0, 0, false
+	}
+	if x.flags.unicode && s[e-1] >= 0xDC00 && s[e-1] <= 0xDFFF && e >= 2 && s[e-2] >= 0xD800 && s[e-2] < 0xDC00 {
+		return utf16.DecodeRune(rune(s[e-2]), rune(s[e-1])), 2, true
+	}
+	return rune(s[e-1]), 1, true
+}
+
+// canonicalize returns the canonical form of the given character, which is
+// used to compare characters case-insensitively.
+// Canonicalize is specified in 21.2.2.8.2.
+func canonicalize(ch rune, flags regexpFlags) rune {
+	if !flags.ignoreCase {
+		return ch
+	}
+	if flags.unicode {
+		// all characters that fold to the same character are in the same
+		// orbit, of which the smallest character is the canonical one
+		min := ch
+		for f := unicode.SimpleFold(ch); f != ch; f = unicode.SimpleFold(f) {
+			if f < min {
+				min = f
+			}
+		}
+		return min
+	}
+	if ch == 'ß' || ch > 0xFFFF || utf16.IsSurrogate(ch) {
+		return ch
+	}
+	u := unicode.ToUpper(ch)
+	if u > 0xFFFF || (ch >= 128 && u < 128) {
+		return ch
+	}
+	return u
+}
+
+// runeRange is a closed range of characters.
+type runeRange struct {
+	lo, hi rune
+}
+
+// charSet is a CharSet, a set of characters, as specified in 21.2.2.1.
+type charSet []runeRange
+
+// contains reports whether the set contains the character.
+func (cs charSet) contains(ch rune) bool {
+	i := sort.Search(len(cs), func(i int) bool { return cs[i].hi >= ch })
+	return i < len(cs) && cs[i].lo <= ch
+}
+
+// normalize sorts the ranges of the set and merges overlapping ones.
+func (cs charSet) normalize() charSet {
+	sort.Slice(cs, func(i, j int) bool { return cs[i].lo < cs[j].lo })
+	var result charSet
+	for _, r := range cs {
+		if n := len(result); n > 0 && r.lo <= result[n-1].hi+1 {
+			if r.hi > result[n-1].hi {
+				result[n-1].hi = r.hi
+			}
+			continue
+		}
+		result = append(result, r)
+	}
+	return result
+}
+
+// complement returns the characters up to max that are not in the set.
+// The set must be normalized.
+func (cs charSet) complement(max rune) charSet {
+	var result charSet
+	next := rune(0)
+	for _, r := range cs {
+		if r.lo > next {
+			result = append(result, runeRange{next, r.lo - 1})
+		}
+		next = r.hi + 1
+	}
+	if next <= max {
+		result = append(result, runeRange{next, max})
+	}
+	return result
+}
+
+// fromRangeTable returns the characters of the given Unicode range table.
+func fromRangeTable(t *unicode.RangeTable) charSet {
+	var cs charSet
+	for _, r := range t.R16 {
+		for c := rune(r.Lo); c <= rune(r.Hi); c += rune(r.Stride) {
+			if r.Stride == 1 {
+				cs = append(cs, runeRange{rune(r.Lo), rune(r.Hi)})
+				break
+			}
+			cs = append(cs, runeRange{c, c})
+		}
+	}
+	for _, r := range t.R32 {
+		for c := rune(r.Lo); c <= rune(r.Hi); c += rune(r.Stride) {
+			if r.Stride == 1 {
+				cs = append(cs, runeRange{rune(r.Lo), rune(r.Hi)})
+				break
+			}
+			cs = append(cs, runeRange{c, c})
+		}
+	}
+	return cs
+}
+
+// Character sets of the character class escapes, as specified in
+// 21.2.2.12.
+var (
+	digitSet = charSet{{'0', '9'}}
+	wordSet  = charSet{{'0', '9'}, {'A', 'Z'}, {'_', '_'}, {'a', 'z'}}
+	spaceSet = charSet{
+		{'\t', '\r'}, {' ', ' '}, {0xA0, 0xA0}, {0x1680, 0x1680}, {0x2000, 0x200A},
+		{0x2028, 0x2029}, {0x202F, 0x202F}, {0x205F, 0x205F}, {0x3000, 0x3000}, {0xFEFF, 0xFEFF},
+	}
+	lineTerminatorSet = charSet{{'\n', '\n'}, {'\r', '\r'}, {0x2028, 0x2029}}
+)
+
+// isWordChar reports whether the character at the given index of the
+// input is a word character.
+// IsWordChar is specified in 21.2.2.6.2.
+func (x *matchState) isWordChar(e int) bool {
+	if e < 0 || e >= len(x.input) {
+		return false
+	}
+	c := rune(x.input[e])
+	if wordSet.contains(c) {
+		return true
+	}
+	// with the unicode and ignoreCase flags, characters that are canonicalized
+	// to a word character are word characters as well
+	return x.flags.unicode && x.flags.ignoreCase && (c == 0x017F || c == 0x212A)
+}
+
+// regexpParser parses a pattern into a matcher. The pattern is a sequence
+// of code points if the unicode flag is set, and a sequence of code units
+// otherwise. Without the unicode flag, the syntax extensions of B.1.4 are
+// accepted.
+// Patterns are specified in 21.2.1.
+type regexpParser struct {
+	src        []rune
+	pos        int
+	flags      regexpFlags
+	groupCount int
+	groupNames []string
+	hasNames   bool
+
+	// backreferences holds the named backreferences, which are resolved
+	// after the whole pattern has been parsed
+	backreferences []*namedBackreference
+}
+
+// namedBackreference is a backreference to a named group.
+type namedBackreference struct {
+	name  string
+	index int
+}
+
+// regexpSyntaxError is a syntax error in a pattern.
+type regexpSyntaxError struct {
+	msg string
+}
+
+// compileRegExp parses the given pattern with the given flags, and
+// returns the program of the pattern, or a SyntaxError if the pattern is
+// not valid.
+func compileRegExp(pattern lang.String, flags regexpFlags) (prog *regexpProgram, err errors.Error) {
+	p := &regexpParser{flags: flags}
+	if flags.unicode {
+		p.src = utf16.Decode(pattern)
+	} else {
+		p.src = make([]rune, len(pattern))
+		for i, c := range pattern {
+			p.src[i] = rune(c)
+		}
+	}
+
+	defer func() {
+		if e := recover(); e != nil {
+			syntaxErr, ok := e.(regexpSyntaxError)
+			if !ok {
+				panic(e)
+			}
+			prog, err = nil, errors.NewSyntaxError(fmt.Sprintf("Invalid regular expression: /%s/: %s", pattern.String(), syntaxErr.msg))
+		}
+	}()
+
+	p.scanGroups()
+	n := p.parseDisjunction()
+	if p.pos < len(p.src) {
+		if p.src[p.pos] == ')' {
+			p.fail("Unmatched ')'")
+		}
+		p.fail("Unexpected character")
+	}
+	for _, ref := range p.backreferences {
+		ref.index = p.groupIndex(ref.name)
+		if ref.index < 0 {
+			p.fail("Invalid named capture referenced")
+		}
+	}
+
+	return &regexpProgram{
+		m:          n.compile(true),
+		flags:      flags,
+		groupCount: p.groupCount,
+		groupNames: p.groupNames,
+		hasNames:   p.hasNames,
+	}, nil
+}
+
+// fail aborts parsing with a syntax error.
+func (p *regexpParser) fail(msg string) {
+	panic(regexpSyntaxError{msg})
+}
+
+// scanGroups counts the capturing groups of the pattern, and determines
+// their names, before the pattern is parsed. This is needed, because
+// decimal escapes are backreferences only if there are enough groups.
+func (p *regexpParser) scanGroups() {
+	p.groupNames = []string{""}
+	inClass := false
+	for i := 0; i < len(p.src); i++ {
+		switch c := p.src[i]; {
+		case c == '\\':
+			i++
+		case c == '[':
+			inClass = true
+		case c == ']':
+			inClass = false
+		case c == '(' && !inClass:
+			if i+1 < len(p.src) && p.src[i+1] == '?' {
+				if i+2 < len(p.src) && p.src[i+2] == '<' && i+3 < len(p.src) && p.src[i+3] != '=' && p.src[i+3] != '!' {
+					end := i + 3
+					for end < len(p.src) && p.src[end] != '>' {
+						end++
+					}
+					p.groupNames = append(p.groupNames, string(p.src[i+3:end]))
+					p.hasNames = true
+				}
+				continue
+			}
+			p.groupNames = append(p.groupNames, "")
+		}
+	}
+	p.groupCount = len(p.groupNames) - 1
+}
+
+// groupIndex returns the index of the group with the given name, or -1 if
+// there is no such group.
+func (p *regexpParser) groupIndex(name string) int {
+	for i, n := range p.groupNames {
+		if i > 0 && n == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// peek returns the next character, or -1 at the end of the pattern.
+func (p *regexpParser) peek() rune {
+	if p.pos < len(p.src) {
+		return p.src[p.pos]
+	}
+	return -1
+}
+
+// peekAt returns the character at the given offset from the current
+// position, or -1 if it is outside of the pattern.
+func (p *regexpParser) peekAt(offset int) rune {
+	if p.pos+offset < len(p.src) {
+		return p.src[p.pos+offset]
+	}
+	return -1
+}
+
+// accept consumes the given sequence of characters, if it follows.
+func (p *regexpParser) accept(s string) bool {
+	i := p.pos
+	for _, c := range s {
+		if i >= len(p.src) || p.src[i] != c {
+			return false
+		}
+		i++
+	}
+	p.pos = i
+	return true
+}
+
+// next consumes and returns the next character.
+func (p *regexpParser) next() rune {
+	if p.pos >= len(p.src) {
+		p.fail("\\ at end of pattern")
+	}
+	c := p.src[p.pos]
+	p.pos++
+	return c
+}
+
+// regexpNode is a node of a parsed pattern, that can be compiled to a
+// matcher that matches forward or backward.
+type regexpNode interface {
+	compile(forward bool) matcher
+}
+
+type (
+	// disjunctionNode is a Disjunction, as specified in 21.2.2.3.
+	disjunctionNode []regexpNode
+	// alternativeNode is an Alternative, as specified in 21.2.2.4.
+	alternativeNode []regexpNode
+	// assertionNode is one of the assertions ^, $, \b and \B, as
+	// specified in 21.2.2.6.
+	assertionNode rune
+	// lookaroundNode is a lookahead or lookbehind assertion, as specified
+	// in 21.2.2.6.
+	lookaroundNode struct {
+		n        regexpNode
+		ahead    bool
+		negative bool
+	}
+	// quantifierNode is an Atom followed by a Quantifier, as specified in
+	// 21.2.2.5.
+	quantifierNode struct {
+		atom       regexpNode
+		min, max   int // max is -1 for unbounded quantifiers
+		greedy     bool
+		parenIndex int
+		parenCount int
+	}
+	// charSetNode is an Atom that matches a single character of a set, as
+	// specified in 21.2.2.8.1.
+	charSetNode struct {
+		set    charSet
+		invert bool
+	}
+	// groupNode is a capturing group, as specified in 21.2.2.8.
+	groupNode struct {
+		n     regexpNode
+		index int
+	}
+	// backreferenceNode is an AtomEscape that refers to a capturing group,
+	// as specified in 21.2.2.9.
+	backreferenceNode struct {
+		ref *namedBackreference
+	}
+)
+
+// parseDisjunction parses a Disjunction.
+func (p *regexpParser) parseDisjunction() regexpNode {
+	alternatives := disjunctionNode{p.parseAlternative()}
+	for p.accept("|") {
+		alternatives = append(alternatives, p.parseAlternative())
+	}
+	if len(alternatives) == 1 {
+		return alternatives[0]
+	}
+	return alternatives
+}
+
+// parseAlternative parses an Alternative.
+func (p *regexpParser) parseAlternative() regexpNode {
+	var terms alternativeNode
+	for p.pos < len(p.src) && p.peek() != '|' && p.peek() != ')' {
+		terms = append(terms, p.parseTerm())
+	}
+	return terms
+}
+
+// parseTerm parses a Term, that is an Assertion or an Atom with an
+// optional Quantifier.
+func (p *regexpParser) parseTerm() regexpNode {
+	parenIndex := p.parenIndex()
+	quantifiable := true
+
+	var atom regexpNode
+	switch {
+	case p.accept("^"):
+		return assertionNode('^')
+	case p.accept("$"):
+		return assertionNode('$')
+	case p.accept(`\b`):
+		return assertionNode('b')
+	case p.accept(`\B`):
+		return assertionNode('B')
+	case p.accept("(?="), p.accept("(?!"):
+		negative := p.src[p.pos-1] == '!'
+		atom = lookaroundNode{p.parseGroupBody(), true, negative}
+		// lookaheads are quantifiable without the unicode flag, as
+		// specified in B.1.4
+		quantifiable = !p.flags.unicode
+	case p.accept("(?<="), p.accept("(?<!"):
+		negative := p.src[p.pos-1] == '!'
+		return lookaroundNode{p.parseGroupBody(), false, negative}
+	default:
+		atom = p.parseAtom()
+	}
+
+	min, max := 0, 0
+	switch c := p.peek(); {
+	case c == '*':
+		p.pos++
+		min, max = 0, -1
+	case c == '+':
+		p.pos++
+		min, max = 1, -1
+	case c == '?':
+		p.pos++
+		min, max = 0, 1
+	case c == '{':
+		var ok bool
+		if min, max, ok = p.parseBraceQuantifier(); !ok {
+			return atom
+		}
+	default:
+		return atom
+	}
+	if !quantifiable {
+		p.fail("Invalid quantifier")
+	}
+	if max != -1 && max < min {
+		p.fail("numbers out of order in {} quantifier")
+	}
+	greedy := !p.accept("?")
+	return quantifierNode{
+		atom:       atom,
+		min:        min,
+		max:        max,
+		greedy:     greedy,
+		parenIndex: parenIndex,
+		parenCount: p.parenIndex() - parenIndex,
+	}
+}
+
+// parenIndex returns the number of capturing groups that are opened before
+// the current position.
+func (p *regexpParser) parenIndex() int {
+	n := 0
+	inClass := false
+	for i := 0; i < p.pos; i++ {
+		switch c := p.src[i]; {
+		case c == '\\':
+			i++
+		case c == '[':
+			inClass = true
+		case c == ']':
+			inClass = false
+		case c == '(' && !inClass:
+			if i+1 < len(p.src) && p.src[i+1] == '?' {
+				if !(i+2 < len(p.src) && p.src[i+2] == '<' && i+3 < len(p.src) && p.src[i+3] != '=' && p.src[i+3] != '!') {
+					continue
+				}
+			}
+			n++
+		}
+	}
+	return n
+}
+
+// parseBraceQuantifier parses a quantifier of the form {n}, {n,} or {n,m}.
+// If the brace does not start a quantifier, false is returned and the
+// brace is left as is, which is only valid without the unicode flag.
+func (p *regexpParser) parseBraceQuantifier() (int, int, bool) {
+	start := p.pos
+	p.pos++
+	min, ok := p.parseDecimal()
+	max := min
+	if ok && p.accept(",") {
+		if p.peek() == '}' {
+			max = -1
+		} else {
+			max, ok = p.parseDecimal()
+		}
+	}
+	if ok && p.accept("}") {
+		return min, max, true
+	}
+	p.pos = start
+	if p.flags.unicode {
+		p.fail("Incomplete quantifier")
+	}
+	return 0, 0, false
+}
+
+// parseDecimal parses a sequence of decimal digits. Values that do not fit
+// into an int are clamped.
+func (p *regexpParser) parseDecimal() (int, bool) {
+	start := p.pos
+	n := 0
+	for c := p.peek(); c >= '0' && c <= '9'; c = p.peek() {
+		if n < math.MaxInt32 {
+			n = n*10 + int(c-'0')
+		}
+		p.pos++
+	}
+	return n, p.pos > start
+}
+
+// parseGroupBody parses the Disjunction of a group and the closing
+// parenthesis.
+func (p *regexpParser) parseGroupBody() regexpNode {
+	n := p.parseDisjunction()
+	if !p.accept(")") {
+		p.fail("Unterminated group")
+	}
+	return n
+}
+
+// parseAtom parses an Atom.
+func (p *regexpParser) parseAtom() regexpNode {
+	switch c := p.peek(); c {
+	case '.':
+		p.pos++
+		if p.flags.dotAll {
+			return charSetNode{charSet{{0, maxCodePoint}}, false}
+		}
+		return charSetNode{lineTerminatorSet, true}
+	case '(':
+		if p.accept("(?:") {
+			return p.parseGroupBody()
+		}
+		p.pos++
+		index := p.parenIndex()
+		if p.accept("?<") {
+			name := p.parseGroupName()
+			if p.groupIndex(name) != index {
+				p.fail("Duplicate capture group name")
+			}
+		} else if p.peek() == '?' {
+			p.fail("Invalid group")
+		}
+		return groupNode{p.parseGroupBody(), index}
+	case '[':
+		return p.parseClass()
+	case '\\':
+		p.pos++
+		return p.parseAtomEscape()
+	case '*', '+', '?':
+		p.fail("Nothing to repeat")
+	case '{':
+		if p.flags.unicode {
+			p.fail("Nothing to repeat")
+		}
+		if _, _, ok := p.parseBraceQuantifier(); ok {
+			p.fail("Nothing to repeat")
+		}
+	case ']', '}':
+		if p.flags.unicode {
+			p.fail("Lone quantifier brackets")
+		}
+	}
+	return p.charNode(p.next())
+}
+
+// charNode returns a node that matches the given character.
+func (p *regexpParser) charNode(c rune) regexpNode {
+	return charSetNode{charSet{{c, c}}, false}
+}
+
+// parseGroupName parses a GroupName without the leading '<'.
+func (p *regexpParser) parseGroupName() string {
+	start := p.pos
+	for p.pos < len(p.src) && p.src[p.pos] != '>' {
+		c := p.src[p.pos]
+		if !(c == '$' || c == '_' || unicode.IsLetter(c) || (p.pos > start && (unicode.IsDigit(c) || c == 0x200C || c == 0x200D))) {
+			p.fail("Invalid capture group name")
+		}
+		p.pos++
+	}
+	if p.pos == start || !p.accept(">") {
+		p.fail("Invalid capture group name")
+	}
+	return string(p.src[start : p.pos-1])
+}
+
+// parseAtomEscape parses an AtomEscape after the backslash.
+func (p *regexpParser) parseAtomEscape() regexpNode {
+	c := p.peek()
+	switch {
+	case c >= '1' && c <= '9':
+		start := p.pos
+		n, _ := p.parseDecimal()
+		if n <= p.groupCount {
+			return backreferenceNode{&namedBackreference{index: n}}
+		}
+		if p.flags.unicode {
+			p.fail("Invalid escape")
+		}
+		// legacy octal escapes or identity escapes, as specified in B.1.4
+		p.pos = start
+		if c >= '8' {
+			p.pos++
+			return p.charNode(c)
+		}
+		return p.charNode(p.parseLegacyOctal())
+	case c == 'k' && (p.flags.unicode || p.hasNames):
+		p.pos++
+		if !p.accept("<") {
+			p.fail("Invalid named reference")
+		}
+		ref := &namedBackreference{name: p.parseGroupName()}
+		p.backreferences = append(p.backreferences, ref)
+		return backreferenceNode{ref}
+	}
+	if set, ok := p.parseClassEscape(); ok {
+		return charSetNode{set, false}
+	}
+	return p.charNode(p.parseCharacterEscape(false))
+}
+
+// parseLegacyOctal parses a LegacyOctalEscapeSequence, as specified in
+// B.1.2.
+func (p *regexpParser) parseLegacyOctal() rune {
+	n := rune(0)
+	for i := 0; i < 3 && p.peek() >= '0' && p.peek() <= '7'; i++ {
+		next := n*8 + p.peek() - '0'
+		if next > 0377 {
+			break
+		}
+		n = next
+		p.pos++
+	}
+	return n
+}
+
+// parseClassEscape parses a CharacterClassEscape, if one follows.
+func (p *regexpParser) parseClassEscape() (charSet, bool) {
+	var set charSet
+	switch p.peek() {
+	case 'd', 'D':
+		set = digitSet
+	case 's', 'S':
+		set = spaceSet
+	case 'w', 'W':
+		set = wordSet
+		if p.flags.unicode && p.flags.ignoreCase {
+			set = append(charSet{{0x017F, 0x017F}, {0x212A, 0x212A}}, set...).normalize()
+		}
+	case 'p', 'P':
+		if !p.flags.unicode {
+			return nil, false
+		}
+		negate := p.peek() == 'P'
+		p.pos++
+		set = p.parseUnicodeProperty()
+		if negate {
+			set = set.complement(maxCodePoint)
+		}
+		return set, true
+	default:
+		return nil, false
+	}
+	c := p.next()
+	if unicode.IsUpper(c) {
+		max := rune(0xFFFF)
+		if p.flags.unicode {
+			max = maxCodePoint
+		}
+		set = set.complement(max)
+	}
+	return set, true
+}
+
+// parseUnicodeProperty parses the {name} or {name=value} of a unicode
+// property escape, and returns the characters with that property.
+// UnicodeMatchProperty is specified in 21.2.2.12.
+func (p *regexpParser) parseUnicodeProperty() charSet {
+	if !p.accept("{") {
+		p.fail("Invalid property name")
+	}
+	start := p.pos
+	for p.pos < len(p.src) && p.src[p.pos] != '}' {
+		p.pos++
+	}
+	if !p.accept("}") {
+		p.fail("Invalid property name")
+	}
+	name, value := string(p.src[start:p.pos-1]), ""
+	for i, c := range name {
+		if c == '=' {
+			name, value = name[:i], name[i+1:]
+			break
+		}
+	}
+
+	var table *unicode.RangeTable
+	switch name {
+	case "General_Category", "gc":
+		table = unicode.Categories[generalCategoryAliases(value)]
+	case "Script", "sc", "Script_Extensions", "scx":
+		table = unicode.Scripts[value]
+	default:
+		if value != "" {
+			break
+		}
+		switch name {
+		case "Any":
+			return charSet{{0, maxCodePoint}}
+		case "ASCII":
+			return charSet{{0, 0x7F}}
+		case "Assigned":
+			var cs charSet
+			for _, t := range unicode.Categories {
+				cs = append(cs, fromRangeTable(t)...)
+			}
+			return cs.normalize()
+		}
+		if table = unicode.Categories[generalCategoryAliases(name)]; table == nil {
+			table = unicode.Properties[name]
+		}
+	}
+	if table == nil {
+		p.fail("Invalid property name")
+	}
+	return fromRangeTable(table).normalize()
+}
+
+// generalCategoryAliases returns the short name of the given general
+// category, which is the name that the unicode package uses.
+func generalCategoryAliases(name string) string {
+	aliases := map[string]string{
+		"Letter": "L", "Cased_Letter": "LC", "Uppercase_Letter": "Lu", "Lowercase_Letter": "Ll",
+		"Titlecase_Letter": "Lt", "Modifier_Letter": "Lm", "Other_Letter": "Lo",
+		"Mark": "M", "Combining_Mark": "M", "Nonspacing_Mark": "Mn", "Spacing_Mark": "Mc", "Enclosing_Mark": "Me",
+		"Number": "N", "Decimal_Number": "Nd", "digit": "Nd", "Letter_Number": "Nl", "Other_Number": "No",
+		"Punctuation": "P", "punct": "P", "Connector_Punctuation": "Pc", "Dash_Punctuation": "Pd",
+		"Open_Punctuation": "Ps", "Close_Punctuation": "Pe", "Initial_Punctuation": "Pi",
+		"Final_Punctuation": "Pf", "Other_Punctuation": "Po",
+		"Symbol": "S", "Math_Symbol": "Sm", "Currency_Symbol": "Sc", "Modifier_Symbol": "Sk", "Other_Symbol": "So",
+		"Separator": "Z", "Space_Separator": "Zs", "Line_Separator": "Zl", "Paragraph_Separator": "Zp",
+		"Other": "C", "Control": "Cc", "cntrl": "Cc", "Format": "Cf", "Surrogate": "Cs",
+		"Private_Use": "Co", "Unassigned": "Cn",
+	}
+	if alias, ok := aliases[name]; ok {
+		return alias
+	}
+	return name
+}
+
+// parseCharacterEscape parses a CharacterEscape after the backslash, and
+// returns the character it denotes. If inClass is true, the escape is part
+// of a character class.
+func (p *regexpParser) parseCharacterEscape(inClass bool) rune {
+	c := p.next()
+	switch c {
+	case 'f':
+		return '\f'
+	case 'n':
+		return '\n'
+	case 'r':
+		return '\r'
+	case 't':
+		return '\t'
+	case 'v':
+		return '\v'
+	case 'c':
+		if l := p.peek(); (l >= 'a' && l <= 'z') || (l >= 'A' && l <= 'Z') ||
+			(inClass && !p.flags.unicode && ((l >= '0' && l <= '9') || l == '_')) {
+			p.pos++
+			return l % 32
+		}
+		if p.flags.unicode {
+			p.fail("Invalid unicode escape")
+		}
+		// \c without a control letter is a backslash followed by c, as
+		// specified in B.1.4
+		p.pos--
+		return '\\'
+	case '0':
+		if d := p.peek(); d < '0' || d > '9' {
+			return 0
+		}
+		if p.flags.unicode {
+			p.fail("Invalid decimal escape")
+		}
+		p.pos--
+		return p.parseLegacyOctal()
+	case 'x':
+		if v, ok := p.parseHex(2); ok {
+			return v
+		}
+		if p.flags.unicode {
+			p.fail("Invalid escape")
+		}
+		return 'x'
+	case 'u':
+		if v, ok := p.parseUnicodeEscape(); ok {
+			return v
+		}
+		if p.flags.unicode {
+			p.fail("Invalid unicode escape")
+		}
+		return 'u'
+	}
+	if p.flags.unicode {
+		if c == '/' || (c == '-' && inClass) || isSyntaxCharacter(c) {
+			return c
+		}
+		p.fail("Invalid escape")
+	}
+	if inClass && c >= '1' && c <= '9' {
+		if c >= '8' {
+			return c
+		}
+		p.pos--
+		return p.parseLegacyOctal()
+	}
+	return c
+}
+
+// isSyntaxCharacter reports whether the given character is a
+// SyntaxCharacter, as specified in 21.2.1.
+func isSyntaxCharacter(c rune) bool {
+	switch c {
+	case '^', '$', '\\', '.', '*', '+', '?', '(', ')', '[', ']', '{', '}', '|':
+		return true
+	}
+	return false
+}
+
+// parseHex parses the given number of hexadecimal digits.
+func (p *regexpParser) parseHex(digits int) (rune, bool) {
+	if p.pos+digits > len(p.src) {
+		return 0, false
+	}
+	v := rune(0)
+	for _, c := range p.src[p.pos : p.pos+digits] {
+		if c > 0x7F || digitValue(byte(c)) >= 16 {
+			return 0, false
+		}
+		v = v*16 + rune(digitValue(byte(c)))
+	}
+	p.pos += digits
+	return v, true
+}
+
+// parseUnicodeEscape parses a RegExpUnicodeEscapeSequence after the u. With
+// the unicode flag, surrogate pairs of escapes are combined, and code point
+// escapes of the form u{...} are accepted.
+func (p *regexpParser) parseUnicodeEscape() (rune, bool) {
+	if p.flags.unicode && p.accept("{") {
+		start := p.pos
+		v := rune(0)
+		for c := p.peek(); c != '}'; c = p.peek() {
+			if c < 0 || c > 0x7F || digitValue(byte(c)) >= 16 {
+				return 0, false
+			}
+			v = v*16 + rune(digitValue(byte(c)))
+			if v > maxCodePoint {
+				p.fail("Invalid unicode escape")
+			}
+			p.pos++
+		}
+		if p.pos == start {
+			return 0, false
+		}
+		p.pos++
+		return v, true
+	}
+	lead, ok := p.parseHex(4)
+	if !ok {
+		return 0, false
+	}
+	if p.flags.unicode && lead >= 0xD800 && lead < 0xDC00 && p.peek() == '\\' && p.peekAt(1) == 'u' {
+		start := p.pos
+		p.pos += 2
+		if trail, ok := p.parseHex(4); ok && trail >= 0xDC00 && trail <= 0xDFFF {
+			return utf16.DecodeRune(lead, trail), true
+		}
+		p.pos = start
+	}
+	return lead, true
+}
+
+// parseClass parses a CharacterClass.
+func (p *regexpParser) parseClass() regexpNode {
+	p.pos++
+	invert := p.accept("^")
+	var set charSet
+	for !p.accept("]") {
+		if p.pos >= len(p.src) {
+			p.fail("Unterminated character class")
+		}
+		lo, loSet := p.parseClassAtom()
+		if p.peek() != '-' || p.peekAt(1) == ']' || p.peekAt(1) == -1 {
+			set = append(set, loSet...)
+			continue
+		}
+		p.pos++
+		hi, hiSet := p.parseClassAtom()
+		if len(loSet) != 1 || loSet[0].lo != lo || len(hiSet) != 1 || hiSet[0].lo != hi {
+			// ranges of class escapes are only allowed without the unicode
+			// flag, where they match the dash as well, as specified in B.1.4
+			if p.flags.unicode {
+				p.fail("Invalid character class")
+			}
+			set = append(set, loSet...)
+			set = append(set, runeRange{'-', '-'})
+			set = append(set, hiSet...)
+			continue
+		}
+		if lo > hi {
+			p.fail("Range out of order in character class")
+		}
+		set = append(set, runeRange{lo, hi})
+	}
+	return charSetNode{set.normalize(), invert}
+}
+
+// parseClassAtom parses a ClassAtom, and returns the set of characters it
+// matches, and the character if it is a single character.
+func (p *regexpParser) parseClassAtom() (rune, charSet) {
+	c := p.next()
+	if c != '\\' {
+		return c, charSet{{c, c}}
+	}
+	if p.accept("b") {
+		return '\b', charSet{{'\b', '\b'}}
+	}
+	if set, ok := p.parseClassEscape(); ok {
+		return -1, set
+	}
+	c = p.parseCharacterEscape(true)
+	return c, charSet{{c, c}}
+}
+
+// compile returns a matcher that matches one of the alternatives.
+func (n disjunctionNode) compile(forward bool) matcher {
+	ms := make([]matcher, len(n))
+	for i, alternative := range n {
+		ms[i] = alternative.compile(forward)
+	}
+	return func(x *matchState, c continuation) bool {
+		for _, m := range ms {
+			if m(x, c) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// compile returns a matcher that matches all terms in sequence. Backward
+// matchers match the terms from the last to the first.
+func (n alternativeNode) compile(forward bool) matcher {
+	if len(n) == 0 {
+		return func(x *matchState, c continuation) bool { return c(x) }
+	}
+	ms := make([]matcher, len(n))
+	for i, term := range n {
+		ms[i] = term.compile(forward)
+	}
+	if !forward {
+		for i, j := 0, len(ms)-1; i < j; i, j = i+1, j-1 {
+			ms[i], ms[j] = ms[j], ms[i]
+		}
+	}
+	m := ms[len(ms)-1]
+	for i := len(ms) - 2; i >= 0; i-- {
+		first, rest := ms[i], m
+		m = func(x *matchState, c continuation) bool {
+			return first(x, func(y *matchState) bool {
+				return rest(y, c)
+			})
+		}
+	}
+	return m
+}
+
+// compile returns a matcher that checks the assertion.
+func (n assertionNode) compile(bool) matcher {
+	var test func(x *matchState) bool
+	switch n {
+	case '^':
+		test = func(x *matchState) bool {
+			return x.end == 0 || (x.flags.multiline && lineTerminatorSet.contains(rune(x.input[x.end-1])))
+		}
+	case '$':
+		test = func(x *matchState) bool {
+			return x.end == len(x.input) || (x.flags.multiline && lineTerminatorSet.contains(rune(x.input[x.end])))
+		}
+	case 'b':
+		test = func(x *matchState) bool {
+			return x.isWordChar(x.end-1) != x.isWordChar(x.end)
+		}
+	case 'B':
+		test = func(x *matchState) bool {
+			return x.isWordChar(x.end-1) == x.isWordChar(x.end)
+		}
+	}
+	return func(x *matchState, c continuation) bool {
+		return test(x) && c(x)
+	}
+}
+
+// compile returns a matcher for the lookaround. The captures of a positive
+// lookaround are kept, while a negative lookaround never captures.
+func (n lookaroundNode) compile(bool) matcher {
+	m := n.n.compile(n.ahead)
+	succeed := func(*matchState) bool { return true }
+	return func(x *matchState, c continuation) bool {
+		end := x.end
+		captures := append([]int{}, x.captures...)
+		matched := m(x, succeed)
+		x.end = end
+		if n.negative {
+			if matched {
+				copy(x.captures, captures)
+				return false
+			}
+			return c(x)
+		}
+		if !matched {
+			return false
+		}
+		if c(x) {
+			return true
+		}
+		copy(x.captures, captures)
+		return false
+	}
+}
+
+// compile returns a matcher that matches a single character of the set.
+func (n charSetNode) compile(forward bool) matcher {
+	return func(x *matchState, c continuation) bool {
+		x.step(1)
+		e := x.end
+		ch, size, ok := x.charAt(e, forward)
+		if !ok || n.matches(ch, x.flags) == n.invert {
+			return false
+		}
+		if forward {
+			x.end = e + size
+		} else {
+			x.end = e - size
+		}
+		if c(x) {
+			return true
+		}
+		x.end = e
+		return false
+	}
+}
+
+// matches reports whether the set contains a character, whose canonical
+// form is the canonical form of the given character.
+// CharacterSetMatcher is specified in 21.2.2.8.1.
+func (n charSetNode) matches(ch rune, flags regexpFlags) bool {
+	if n.set.contains(ch) {
+		return true
+	}
+	if !flags.ignoreCase {
+		return false
+	}
+	cc := canonicalize(ch, flags)
+	candidates := []rune{cc, unicode.ToLower(ch), unicode.ToUpper(ch)}
+	for f := unicode.SimpleFold(ch); f != ch; f = unicode.SimpleFold(f) {
+		candidates = append(candidates, f)
+	}
+	for _, candidate := range candidates {
+		if n.set.contains(candidate) && canonicalize(candidate, flags) == cc {
+			return true
+		}
+	}
+	return false
+}
+
+// compile returns a matcher that captures the match of the group.
+func (n groupNode) compile(forward bool) matcher {
+	m := n.n.compile(forward)
+	i := 2 * n.index
+	return func(x *matchState, c continuation) bool {
+		start := x.end
+		return m(x, func(y *matchState) bool {
+			oldStart, oldEnd := y.captures[i], y.captures[i+1]
+			if forward {
+				y.captures[i], y.captures[i+1] = start, y.end
+			} else {
+				y.captures[i], y.captures[i+1] = y.end, start
+			}
+			if c(y) {
+				return true
+			}
+			y.captures[i], y.captures[i+1] = oldStart, oldEnd
+			return false
+		})
+	}
+}
+
+// compile returns a matcher that matches the text that the group captured.
+// BackreferenceMatcher is specified in 21.2.2.9.1.
+func (n backreferenceNode) compile(forward bool) matcher {
+	return func(x *matchState, c continuation) bool {
+		i := 2 * n.ref.index
+		s, e := x.captures[i], x.captures[i+1]
+		if s < 0 || e < 0 {
+			return c(x)
+		}
+		length := e - s
+		start := x.end
+		f := start + length
+		if !forward {
+			f = start - length
+		}
+		if f < 0 || f > len(x.input) {
+			return false
+		}
+		g := start
+		if f < g {
+			g = f
+		}
+		x.step(length)
+		for k := 0; k < length; k++ {
+			if canonicalize(rune(x.input[s+k]), x.flags) != canonicalize(rune(x.input[g+k]), x.flags) {
+				return false
+			}
+		}
+		x.end = f
+		if c(x) {
+			return true
+		}
+		x.end = start
+		return false
+	}
+}
+
+// compile returns a matcher that matches the atom repeatedly.
+// RepeatMatcher is specified in 21.2.2.5.1.
+func (n quantifierNode) compile(forward bool) matcher {
+	m := n.atom.compile(forward)
+	first, last := 2*(n.parenIndex+1), 2*(n.parenIndex+n.parenCount+1)
+
+	var repeat func(x *matchState, min, max int, c continuation) bool
+	repeat = func(x *matchState, min, max int, c continuation) bool {
+		x.step(1)
+		if max == 0 {
+			return c(x)
+		}
+		end := x.end
+		d := func(y *matchState) bool {
+			if min == 0 && y.end == end {
+				return false
+			}
+			newMin, newMax := min, max
+			if newMin > 0 {
+				newMin--
+			}
+			if newMax > 0 {
+				newMax--
+			}
+			return repeat(y, newMin, newMax, c)
+		}
+
+		// the captures of the atom are reset before every iteration
+		captures := append([]int{}, x.captures[first:last]...)
+		clear := func() {
+			for i := first; i < last; i++ {
+				x.captures[i] = -1
+			}
+		}
+		restore := func() {
+			copy(x.captures[first:last], captures)
+		}
+
+		if min != 0 {
+			clear()
+			if m(x, d) {
+				return true
+			}
+			restore()
+			return false
+		}
+		if !n.greedy {
+			if c(x) {
+				return true
+			}
+			clear()
+			if m(x, d) {
+				return true
+			}
+			restore()
+			return false
+		}
+		clear()
+		if m(x, d) {
+			return true
+		}
+		restore()
+		return c(x)
+	}
+
+	return func(x *matchState, c continuation) bool {
+		return repeat(x, n.min, n.max, c)
+	}
+}
@@ -0,0 +1,124 @@
+package realm
+
+import (
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+)
+
+// createSet creates the Set constructor, %SetPrototype% and
+// %SetIteratorPrototype%, as specified in 23.2.
+func (r *Realm) createSet() {
+	setProto := lang.ObjectCreate(r.intrinsic(IntrinsicNameObjectPrototype))
+	r.Intrinsics.SetField(IntrinsicNameSetPrototype, setProto)
+
+	setConstructor := r.newConstructor("Set", 0, setProto, func(newTarget lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		nt, ok := newTarget.(*lang.Object)
+		if !ok {
+			return nil, errors.NewTypeError("Constructor Set requires 'new'")
+		}
+		set, err := OrdinaryCreateFromConstructor(nt, IntrinsicNameSetPrototype, slotSetData)
+		if err != nil {
+			return nil, err
+		}
+		set.SetSlot(slotSetData, newOrderedMap(set.Heap(), true))
+		if iterable := argument(args, 0); iterable != lang.Undefined && iterable != lang.Null {
+			if err := r.addValuesFromIterable(set, iterable); err != nil {
+				return nil, err
+			}
+		}
+		return set, nil
+	})
+	r.Intrinsics.SetField(IntrinsicNameSet, setConstructor)
+	r.defineSpecies(setConstructor)
+
+	r.defineFunction(setProto, "add", 1, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		s, err := thisMapData(this, slotSetData, "Set.prototype.add")
+		if err != nil {
+			return nil, err
+		}
+		if value := argument(args, 0); s.get(value) == nil {
+			if err := s.set(value, value); err != nil {
+				return nil, err
+			}
+		}
+		return this, nil
+	})
+	r.defineFunction(setProto, "clear", 0, func(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+		s, err := thisMapData(this, slotSetData, "Set.prototype.clear")
+		if err != nil {
+			return nil, err
+		}
+		s.clear()
+		return lang.Undefined, nil
+	})
+	r.defineFunction(setProto, "delete", 1, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		s, err := thisMapData(this, slotSetData, "Set.prototype.delete")
+		if err != nil {
+			return nil, err
+		}
+		return lang.Boolean(s.delete(argument(args, 0))), nil
+	})
+	r.defineFunction(setProto, "entries", 0, r.setIteratorFunction(lang.EnumerateKeyPlusValue))
+	r.defineFunction(setProto, "forEach", 1, r.collectionForEach(slotSetData, "Set.prototype.forEach", true))
+	r.defineFunction(setProto, "has", 1, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		s, err := thisMapData(this, slotSetData, "Set.prototype.has")
+		if err != nil {
+			return nil, err
+		}
+		return lang.Boolean(s.get(argument(args, 0)) != nil), nil
+	})
+	r.defineGetter(setProto, key("size"), func(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+		s, err := thisMapData(this, slotSetData, "get Set.prototype.size")
+		if err != nil {
+			return nil, err
+		}
+		return number(float64(s.size)), nil
+	})
+	values := r.defineFunction(setProto, "values", 0, r.setIteratorFunction(lang.EnumerateValue))
+	defineMethodProperty(setProto, "keys", values)
+	_, _ = lang.CreateMethodProperty(setProto, symbolKey(lang.SymbolIterator), values)
+	defineToStringTag(setProto, "Set")
+
+	setIterProto := lang.ObjectCreate(r.intrinsic(IntrinsicNameIteratorPrototype))
+	r.defineFunction(setIterProto, "next", 0, func(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+		return r.collectionIteratorNext(this, slotSetIteratorNextIndex, slotSetIterationKind, "Set Iterator")
+	})
+	defineToStringTag(setIterProto, "Set Iterator")
+	r.Intrinsics.SetField(IntrinsicNameSetIteratorPrototype, setIterProto)
+}
+
+// addValuesFromIterable calls the add method of the target for every value
+// of the iterable, as done by the Set and WeakSet constructors.
+// These constructors are specified in 23.2.1.1 and 23.4.1.1.
+func (r *Realm) addValuesFromIterable(target *lang.Object, iterable lang.Value) errors.Error {
+	adderValue, err := get(target, key("add"))
+	if err != nil {
+		return err
+	}
+	adder, err := callable(adderValue, "'add' of the target")
+	if err != nil {
+		return err
+	}
+	return r.iterate(iterable, func(next lang.Value) errors.Error {
+		_, err := lang.Call(adder, target, next)
+		return err
+	})
+}
+
+// setIteratorFunction returns the implementation of Set.prototype.values
+// or entries, depending on the given kind. The entries of a Set iterator
+// have the value as key.
+// CreateSetIterator is specified in 23.2.5.1.
+func (r *Realm) setIteratorFunction(kind string) BuiltinFunction {
+	return func(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+		s, err := thisMapData(this, slotSetData, "Set Iterator")
+		if err != nil {
+			return nil, err
+		}
+		iterator := lang.ObjectCreate(r.intrinsic(IntrinsicNameSetIteratorPrototype), slotIteratedSet, slotSetIteratorNextIndex, slotSetIterationKind)
+		iterator.SetSlot(slotIteratedSet, this)
+		iterator.SetSlot(slotSetIteratorNextIndex, &mapIterator{m: s})
+		iterator.SetSlot(slotSetIterationKind, lang.NewString(kind))
+		return iterator, nil
+	}
+}
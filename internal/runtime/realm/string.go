@@ -0,0 +1,931 @@
+package realm
+
+import (
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Internal slots of string iterator objects, as specified in 21.1.5.3.
+var (
+	slotIteratedString          = lang.NewStringKey("IteratedString")
+	slotStringIteratorNextIndex = lang.NewStringKey("StringIteratorNextIndex")
+)
+
+// createString creates the String constructor, %StringPrototype% and
+// %StringIteratorPrototype%, as specified in 21.1.
+func (r *Realm) createString() {
+	stringProto := lang.StringCreate(lang.String{}, r.intrinsic(IntrinsicNameObjectPrototype))
+	r.Intrinsics.SetField(IntrinsicNameStringPrototype, stringProto)
+
+	stringCtor := r.newConstructor("String", 1, stringProto, func(newTarget lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		s := lang.String{}
+		if len(args) > 0 {
+			if sym, ok := args[0].(*lang.Symbol); ok && newTarget == lang.Undefined {
+				return lang.SymbolDescriptiveString(sym), nil
+			}
+			var err errors.Error
+			if s, err = lang.ToString(args[0]); err != nil {
+				return nil, err
+			}
+		}
+		nt, ok := newTarget.(*lang.Object)
+		if !ok {
+			return s, nil
+		}
+		proto, err := GetPrototypeFromConstructor(nt, IntrinsicNameStringPrototype)
+		if err != nil {
+			return nil, err
+		}
+		return lang.StringCreate(s, proto), nil
+	})
+	r.Intrinsics.SetField(IntrinsicNameString, stringCtor)
+
+	r.defineFunction(stringCtor, "fromCharCode", 1, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		result := make(lang.String, len(args))
+		for i, arg := range args {
+			c, err := lang.ToUint16(arg)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = uint16(c.Float64())
+		}
+		return result, nil
+	})
+	r.defineFunction(stringCtor, "fromCodePoint", 1, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		var result lang.String
+		for _, arg := range args {
+			next, err := toNumber(arg)
+			if err != nil {
+				return nil, err
+			}
+			if next != math.Trunc(next) || next < 0 || next > unicode.MaxRune {
+				return nil, errors.NewRangeError("Invalid code point " + lang.NumberToString(lang.NewNumber(next)).String())
+			}
+			result = append(result, utf16EncodeCodePoint(rune(next))...)
+		}
+		return result, nil
+	})
+	r.defineFunction(stringCtor, "raw", 1, r.stringRaw)
+
+	r.defineStringPrototype(stringProto)
+
+	stringIterProto := lang.ObjectCreate(r.intrinsic(IntrinsicNameIteratorPrototype))
+	r.defineFunction(stringIterProto, "next", 0, r.stringIteratorNext)
+	defineToStringTag(stringIterProto, "String Iterator")
+	r.Intrinsics.SetField(IntrinsicNameStringIteratorPrototype, stringIterProto)
+}
+
+// stringRaw is String.raw.
+// String.raw is specified in 21.1.2.4.
+func (r *Realm) stringRaw(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+	cooked, err := r.toObject(argument(args, 0))
+	if err != nil {
+		return nil, err
+	}
+	rawValue, err := get(cooked, key("raw"))
+	if err != nil {
+		return nil, err
+	}
+	raw, err := r.toObject(rawValue)
+	if err != nil {
+		return nil, err
+	}
+	literalSegments, err := lengthOfArrayLike(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if literalSegments <= 0 {
+		return lang.String{}, nil
+	}
+	var result lang.String
+	for i := 0; ; i++ {
+		r.work(1)
+		segment, err := get(raw, indexKey(float64(i)))
+		if err != nil {
+			return nil, err
+		}
+		s, err := lang.ToString(segment)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, s...)
+		if float64(i+1) == literalSegments {
+			break
+		}
+		if i+1 < len(args) {
+			sub, err := lang.ToString(args[i+1])
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, sub...)
+		}
+	}
+	if result == nil {
+		result = lang.String{}
+	}
+	return result, nil
+}
+
+// defineStringPrototype defines the properties of %StringPrototype%, as
+// specified in 21.1.3 and B.2.3.
+func (r *Realm) defineStringPrototype(proto *lang.Object) {
+	r.defineFunction(proto, "charAt", 1, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		s, pos, err := stringAndPosition(this, args)
+		if err != nil {
+			return nil, err
+		}
+		if pos < 0 || pos >= float64(len(s)) {
+			return lang.String{}, nil
+		}
+		return lang.String{s[int(pos)]}, nil
+	})
+	r.defineFunction(proto, "charCodeAt", 1, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		s, pos, err := stringAndPosition(this, args)
+		if err != nil {
+			return nil, err
+		}
+		if pos < 0 || pos >= float64(len(s)) {
+			return lang.NaN, nil
+		}
+		return number(float64(s[int(pos)])), nil
+	})
+	r.defineFunction(proto, "codePointAt", 1, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		s, pos, err := stringAndPosition(this, args)
+		if err != nil {
+			return nil, err
+		}
+		if pos < 0 || pos >= float64(len(s)) {
+			return lang.Undefined, nil
+		}
+		cp, _ := codePointAt(s, int(pos))
+		return number(float64(cp)), nil
+	})
+	r.defineFunction(proto, "concat", 1, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		s, err := thisString(this)
+		if err != nil {
+			return nil, err
+		}
+		result := append(lang.String{}, s...)
+		for _, arg := range args {
+			next, err := lang.ToString(arg)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, next...)
+		}
+		return result, nil
+	})
+	r.defineFunction(proto, "endsWith", 1, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		s, search, err := stringAndSearchString(this, args, "endsWith")
+		if err != nil {
+			return nil, err
+		}
+		end := float64(len(s))
+		if endPosition := argument(args, 1); endPosition != lang.Undefined {
+			pos, err := toInteger(endPosition)
+			if err != nil {
+				return nil, err
+			}
+			end = math.Min(math.Max(pos, 0), float64(len(s)))
+		}
+		start := int(end) - len(search)
+		if start < 0 {
+			return lang.False, nil
+		}
+		return lang.Boolean(lang.StringsEqual(s[start:int(end)], search)), nil
+	})
+	r.defineFunction(proto, "includes", 1, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		s, search, err := stringAndSearchString(this, args, "includes")
+		if err != nil {
+			return nil, err
+		}
+		pos, err := toInteger(argument(args, 1))
+		if err != nil {
+			return nil, err
+		}
+		start := int(math.Min(math.Max(pos, 0), float64(len(s))))
+		return lang.Boolean(r.stringIndexOf(s, search, start) >= 0), nil
+	})
+	r.defineFunction(proto, "indexOf", 1, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		s, err := thisString(this)
+		if err != nil {
+			return nil, err
+		}
+		search, err := lang.ToString(argument(args, 0))
+		if err != nil {
+			return nil, err
+		}
+		pos, err := toInteger(argument(args, 1))
+		if err != nil {
+			return nil, err
+		}
+		start := int(math.Min(math.Max(pos, 0), float64(len(s))))
+		return number(float64(r.stringIndexOf(s, search, start))), nil
+	})
+	r.defineFunction(proto, "lastIndexOf", 1, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		s, err := thisString(this)
+		if err != nil {
+			return nil, err
+		}
+		search, err := lang.ToString(argument(args, 0))
+		if err != nil {
+			return nil, err
+		}
+		numPos, err := toNumber(argument(args, 1))
+		if err != nil {
+			return nil, err
+		}
+		pos := math.Inf(1)
+		if !math.IsNaN(numPos) {
+			pos = math.Trunc(numPos)
+		}
+		start := int(math.Min(math.Max(pos, 0), float64(len(s))))
+		for k := int(math.Min(float64(start), float64(len(s)-len(search)))); k >= 0; k-- {
+			r.work(len(search) + 1)
+			if lang.StringsEqual(s[k:k+len(search)], search) {
+				return number(float64(k)), nil
+			}
+		}
+		return number(-1), nil
+	})
+	r.defineFunction(proto, "localeCompare", 1, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		s, err := thisString(this)
+		if err != nil {
+			return nil, err
+		}
+		that, err := lang.ToString(argument(args, 0))
+		if err != nil {
+			return nil, err
+		}
+		return number(float64(strings.Compare(norm.NFC.String(s.String()), norm.NFC.String(that.String())))), nil
+	})
+	r.defineFunction(proto, "match", 1, r.stringDelegateToRegExp(lang.SymbolMatch, "match"))
+	r.defineFunction(proto, "normalize", 0, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		s, err := thisString(this)
+		if err != nil {
+			return nil, err
+		}
+		f := "NFC"
+		if form := argument(args, 0); form != lang.Undefined {
+			if f, err = toString(form); err != nil {
+				return nil, err
+			}
+		}
+		forms := map[string]norm.Form{"NFC": norm.NFC, "NFD": norm.NFD, "NFKC": norm.NFKC, "NFKD": norm.NFKD}
+		nf, ok := forms[f]
+		if !ok {
+			return nil, errors.NewRangeError("The normalization form should be one of NFC, NFD, NFKC, NFKD")
+		}
+		return str(nf.String(s.String())), nil
+	})
+	r.defineFunction(proto, "padEnd", 1, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		return r.stringPad(this, args, false)
+	})
+	r.defineFunction(proto, "padStart", 1, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		return r.stringPad(this, args, true)
+	})
+	r.defineFunction(proto, "repeat", 1, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		s, err := thisString(this)
+		if err != nil {
+			return nil, err
+		}
+		n, err := toInteger(argument(args, 0))
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 || math.IsInf(n, 1) {
+			return nil, errors.NewRangeError("Invalid count value: " + lang.NumberToString(lang.NewNumber(n)).String())
+		}
+		if len(s) == 0 || n == 0 {
+			return lang.String{}, nil
+		}
+		if err := r.CheckStringLength(n * float64(len(s))); err != nil {
+			return nil, err
+		}
+		return r.appendRepeated(nil, s, int(n)*len(s)), nil
+	})
+	r.defineFunction(proto, "replace", 2, r.stringPrototypeReplace)
+	r.defineFunction(proto, "search", 1, r.stringDelegateToRegExp(lang.SymbolSearch, "search"))
+	r.defineFunction(proto, "slice", 2, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		s, err := thisString(this)
+		if err != nil {
+			return nil, err
+		}
+		length := float64(len(s))
+		from, err := relativeIndex(argument(args, 0), length, 0)
+		if err != nil {
+			return nil, err
+		}
+		to, err := relativeIndex(argument(args, 1), length, length)
+		if err != nil {
+			return nil, err
+		}
+		if from >= to {
+			return lang.String{}, nil
+		}
+		return append(lang.String{}, s[int(from):int(to)]...), nil
+	})
+	r.defineFunction(proto, "split", 2, r.stringPrototypeSplit)
+	r.defineFunction(proto, "startsWith", 1, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		s, search, err := stringAndSearchString(this, args, "startsWith")
+		if err != nil {
+			return nil, err
+		}
+		pos, err := toInteger(argument(args, 1))
+		if err != nil {
+			return nil, err
+		}
+		start := int(math.Min(math.Max(pos, 0), float64(len(s))))
+		if start+len(search) > len(s) {
+			return lang.False, nil
+		}
+		return lang.Boolean(lang.StringsEqual(s[start:start+len(search)], search)), nil
+	})
+	r.defineFunction(proto, "substring", 2, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		s, err := thisString(this)
+		if err != nil {
+			return nil, err
+		}
+		length := float64(len(s))
+		start, err := toInteger(argument(args, 0))
+		if err != nil {
+			return nil, err
+		}
+		end := length
+		if argument(args, 1) != lang.Undefined {
+			if end, err = toInteger(args[1]); err != nil {
+				return nil, err
+			}
+		}
+		from := math.Min(math.Max(start, 0), length)
+		to := math.Min(math.Max(end, 0), length)
+		from, to = math.Min(from, to), math.Max(from, to)
+		return append(lang.String{}, s[int(from):int(to)]...), nil
+	})
+	r.defineFunction(proto, "toLocaleLowerCase", 0, stringCaseConversion(false))
+	r.defineFunction(proto, "toLocaleUpperCase", 0, stringCaseConversion(true))
+	r.defineFunction(proto, "toLowerCase", 0, stringCaseConversion(false))
+	r.defineFunction(proto, "toString", 0, func(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+		return thisStringValue(this)
+	})
+	r.defineFunction(proto, "toUpperCase", 0, stringCaseConversion(true))
+	r.defineFunction(proto, "trim", 0, stringTrim(true, true))
+	trimEnd := r.defineFunction(proto, "trimEnd", 0, stringTrim(false, true))
+	trimStart := r.defineFunction(proto, "trimStart", 0, stringTrim(true, false))
+	r.defineFunction(proto, "valueOf", 0, func(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+		return thisStringValue(this)
+	})
+	r.defineSymbolFunction(proto, lang.SymbolIterator, 0, func(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+		s, err := thisString(this)
+		if err != nil {
+			return nil, err
+		}
+		iterator := lang.ObjectCreate(r.intrinsic(IntrinsicNameStringIteratorPrototype), slotIteratedString, slotStringIteratorNextIndex)
+		iterator.SetSlot(slotIteratedString, s)
+		iterator.SetSlot(slotStringIteratorNextIndex, lang.Zero)
+		return iterator, nil
+	})
+
+	// String.prototype.substr, trimLeft and trimRight are specified in
+	// B.2.3.1, B.2.3.15 and B.2.3.16.
+	r.defineFunction(proto, "substr", 2, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		s, err := thisString(this)
+		if err != nil {
+			return nil, err
+		}
+		size := float64(len(s))
+		start, err := relativeIndex(argument(args, 0), size, 0)
+		if err != nil {
+			return nil, err
+		}
+		length := size
+		if argument(args, 1) != lang.Undefined {
+			if length, err = toInteger(args[1]); err != nil {
+				return nil, err
+			}
+		}
+		end := math.Min(start+math.Max(length, 0), size)
+		if start >= end {
+			return lang.String{}, nil
+		}
+		return append(lang.String{}, s[int(start):int(end)]...), nil
+	})
+	defineMethodProperty(proto, "trimLeft", trimStart)
+	defineMethodProperty(proto, "trimRight", trimEnd)
+}
+
+// maxStringLength is the maximum length of strings that are created by the
+// built-in functions.
+const maxStringLength = 1<<30 - 25
+
+// CheckStringLength returns a RangeError if no string of the given length
+// can be created, because the length exceeds the maximum length of
+// strings, or the string would not fit into the heap of the realm, as
+// described by lang.Heap#Check.
+func (r *Realm) CheckStringLength(length float64) errors.Error {
+	if length > maxStringLength {
+		return errors.NewRangeError("Invalid string length")
+	}
+	return r.heap.Check(2 * int64(length))
+}
+
+// stringChunkSize is the number of code units that the built-in functions
+// copy into a string that they build, before they report the work of the
+// next chunk.
+const stringChunkSize = 1 << 16
+
+// appendRepeated appends n code units to dst, which repeat the given
+// non-empty string. The code units are copied in chunks, and each chunk is
+// reported as work before it is copied, so that the evaluation can be
+// interrupted or exceed its budget before the whole string is allocated.
+func (r *Realm) appendRepeated(dst, s lang.String, n int) lang.String {
+	pos := 0
+	for n > 0 {
+		chunk := n
+		if chunk > stringChunkSize {
+			chunk = stringChunkSize
+		}
+		r.work(chunk)
+		n -= chunk
+		for chunk > 0 {
+			k := len(s) - pos
+			if k > chunk {
+				k = chunk
+			}
+			dst = append(dst, s[pos:pos+k]...)
+			pos = (pos + k) % len(s)
+			chunk -= k
+		}
+	}
+	return dst
+}
+
+// thisStringValue returns the given value if it is a String, or the value
+// of its StringData internal slot if it is a String object.
+// thisStringValue is specified in 21.1.3.
+func thisStringValue(v lang.Value) (lang.String, errors.Error) {
+	switch v := v.(type) {
+	case lang.String:
+		return v, nil
+	case *lang.Object:
+		if s, ok := v.GetSlot(lang.SlotStringData).(lang.String); ok {
+			return s, nil
+		}
+	}
+	return nil, errors.NewTypeError("String.prototype method called on incompatible receiver")
+}
+
+// thisString converts the this value of a String.prototype method to a
+// String, which fails for Undefined and Null.
+func thisString(this lang.Value) (lang.String, errors.Error) {
+	o, err := lang.RequireObjectCoercible(this)
+	if err != nil {
+		return nil, err
+	}
+	return lang.ToString(o)
+}
+
+// stringAndPosition converts the this value of a String.prototype method to
+// a String, and its first argument to an integer.
+func stringAndPosition(this lang.Value, args []lang.Value) (lang.String, float64, errors.Error) {
+	s, err := thisString(this)
+	if err != nil {
+		return nil, 0, err
+	}
+	pos, err := toInteger(argument(args, 0))
+	if err != nil {
+		return nil, 0, err
+	}
+	return s, pos, nil
+}
+
+// stringAndSearchString converts the this value of a String.prototype
+// method to a String, and its first argument to the String to search for,
+// which must not be a regular expression.
+func stringAndSearchString(this lang.Value, args []lang.Value, method string) (lang.String, lang.String, errors.Error) {
+	s, err := thisString(this)
+	if err != nil {
+		return nil, nil, err
+	}
+	isRegExp, err := IsRegExp(argument(args, 0))
+	if err != nil {
+		return nil, nil, err
+	}
+	if isRegExp {
+		return nil, nil, errors.NewTypeError("First argument to String.prototype." + method + " must not be a regular expression")
+	}
+	search, err := lang.ToString(argument(args, 0))
+	if err != nil {
+		return nil, nil, err
+	}
+	return s, search, nil
+}
+
+// stringIndexOf returns the index of the first occurrence of search in s,
+// that is not less than start, or -1 if there is no such occurrence.
+func (r *Realm) stringIndexOf(s, search lang.String, start int) int {
+	for k := start; k+len(search) <= len(s); k++ {
+		r.work(len(search) + 1)
+		if lang.StringsEqual(s[k:k+len(search)], search) {
+			return k
+		}
+	}
+	return -1
+}
+
+// codePointAt returns the code point at the given index of the string, and
+// the number of code units it consists of. Unpaired surrogates are returned
+// as they are.
+// CodePointAt is specified in 10.1.4.
+func codePointAt(s lang.String, pos int) (rune, int) {
+	first := rune(s[pos])
+	if !utf16.IsSurrogate(first) || first >= 0xDC00 || pos+1 == len(s) {
+		return first, 1
+	}
+	second := rune(s[pos+1])
+	if second < 0xDC00 || second > 0xDFFF {
+		return first, 1
+	}
+	return utf16.DecodeRune(first, second), 2
+}
+
+// utf16EncodeCodePoint returns the UTF-16 encoding of the given code point.
+// UTF16Encoding is specified in 10.1.1.
+func utf16EncodeCodePoint(cp rune) lang.String {
+	if cp < 0x10000 {
+		return lang.String{uint16(cp)}
+	}
+	hi, lo := utf16.EncodeRune(cp)
+	return lang.String{uint16(hi), uint16(lo)}
+}
+
+// isWhiteSpace is used to determine whether the given code unit is a
+// WhiteSpace or LineTerminator code point, as specified in 11.2 and 11.3.
+func isWhiteSpace(c uint16) bool {
+	switch c {
+	case '\t', '\v', '\f', ' ', 0xA0, 0xFEFF, '\n', '\r', 0x2028, 0x2029:
+		return true
+	}
+	return unicode.Is(unicode.Zs, rune(c))
+}
+
+// trimWhiteSpace removes white space and line terminators from the start
+// and/or the end of the given string.
+// TrimString is specified in 21.1.3.27.1.
+func trimWhiteSpace(s lang.String, start, end bool) lang.String {
+	from, to := 0, len(s)
+	if start {
+		for from < to && isWhiteSpace(s[from]) {
+			from++
+		}
+	}
+	if end {
+		for to > from && isWhiteSpace(s[to-1]) {
+			to--
+		}
+	}
+	return append(lang.String{}, s[from:to]...)
+}
+
+// stringTrim returns the implementation of String.prototype.trim, trimStart
+// or trimEnd.
+func stringTrim(start, end bool) BuiltinFunction {
+	return func(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+		s, err := thisString(this)
+		if err != nil {
+			return nil, err
+		}
+		return trimWhiteSpace(s, start, end), nil
+	}
+}
+
+// stringCaseConversion returns the implementation of a String.prototype
+// method that converts every code point of the string to upper or lower
+// case. Unpaired surrogates are kept as they are.
+func stringCaseConversion(upper bool) BuiltinFunction {
+	mapping := unicode.ToLower
+	if upper {
+		mapping = unicode.ToUpper
+	}
+	return func(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+		s, err := thisString(this)
+		if err != nil {
+			return nil, err
+		}
+		result := make(lang.String, 0, len(s))
+		for k := 0; k < len(s); {
+			cp, n := codePointAt(s, k)
+			k += n
+			if utf16.IsSurrogate(cp) {
+				result = append(result, uint16(cp))
+				continue
+			}
+			if cp == 'ß' && upper {
+				result = append(result, 'S', 'S')
+				continue
+			}
+			result = append(result, utf16EncodeCodePoint(mapping(cp))...)
+		}
+		return result, nil
+	}
+}
+
+// stringPad is String.prototype.padStart or padEnd, depending on atStart.
+// StringPad is specified in 21.1.3.16.1.
+func (r *Realm) stringPad(this lang.Value, args []lang.Value, atStart bool) (lang.Value, errors.Error) {
+	s, err := thisString(this)
+	if err != nil {
+		return nil, err
+	}
+	maxLength, err := toLength(argument(args, 0))
+	if err != nil {
+		return nil, err
+	}
+	if maxLength <= float64(len(s)) {
+		return s, nil
+	}
+	filler := lang.NewString(" ")
+	if fillString := argument(args, 1); fillString != lang.Undefined {
+		if filler, err = lang.ToString(fillString); err != nil {
+			return nil, err
+		}
+	}
+	if len(filler) == 0 {
+		return s, nil
+	}
+	if err := r.CheckStringLength(maxLength); err != nil {
+		return nil, err
+	}
+	fill := r.appendRepeated(nil, filler, int(maxLength)-len(s))
+	if atStart {
+		return lang.Concat(fill, s), nil
+	}
+	return lang.Concat(s, fill), nil
+}
+
+// stringDelegateToRegExp returns the implementation of String.prototype.match
+// or search, which call the method with the given symbol of the regular
+// expression argument, or of a new regular expression that is created from
+// the argument.
+// These functions are specified in 21.1.3.11 and 21.1.3.17.
+func (r *Realm) stringDelegateToRegExp(sym *lang.Symbol, name string) BuiltinFunction {
+	return func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		o, err := lang.RequireObjectCoercible(this)
+		if err != nil {
+			return nil, err
+		}
+		regexp := argument(args, 0)
+		if regexp != lang.Undefined && regexp != lang.Null {
+			method, err := lang.GetMethod(r, regexp, symbolKey(sym))
+			if err != nil {
+				return nil, err
+			}
+			if method != lang.Undefined {
+				return lang.CallValue(method, regexp, o)
+			}
+		}
+		s, err := lang.ToString(o)
+		if err != nil {
+			return nil, err
+		}
+		rx, err := r.RegExpCreate(regexp, lang.Undefined)
+		if err != nil {
+			return nil, err
+		}
+		return lang.Invoke(r, rx, symbolKey(sym), s)
+	}
+}
+
+// stringPrototypeReplace is String.prototype.replace.
+// String.prototype.replace is specified in 21.1.3.16.
+func (r *Realm) stringPrototypeReplace(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+	o, err := lang.RequireObjectCoercible(this)
+	if err != nil {
+		return nil, err
+	}
+	searchValue, replaceValue := argument(args, 0), argument(args, 1)
+	if searchValue != lang.Undefined && searchValue != lang.Null {
+		replacer, err := lang.GetMethod(r, searchValue, symbolKey(lang.SymbolReplace))
+		if err != nil {
+			return nil, err
+		}
+		if replacer != lang.Undefined {
+			return lang.CallValue(replacer, searchValue, o, replaceValue)
+		}
+	}
+	s, err := lang.ToString(o)
+	if err != nil {
+		return nil, err
+	}
+	searchString, err := lang.ToString(searchValue)
+	if err != nil {
+		return nil, err
+	}
+	functionalReplace := lang.InternalIsCallable(replaceValue)
+	var replaceString lang.String
+	if !functionalReplace {
+		if replaceString, err = lang.ToString(replaceValue); err != nil {
+			return nil, err
+		}
+	}
+
+	pos := r.stringIndexOf(s, searchString, 0)
+	if pos < 0 {
+		return s, nil
+	}
+	var replacement lang.String
+	if functionalReplace {
+		replValue, err := lang.CallValue(replaceValue, lang.Undefined, searchString, number(float64(pos)), s)
+		if err != nil {
+			return nil, err
+		}
+		if replacement, err = lang.ToString(replValue); err != nil {
+			return nil, err
+		}
+	} else {
+		if replacement, err = r.GetSubstitution(searchString, s, pos, nil, lang.Undefined, replaceString); err != nil {
+			return nil, err
+		}
+	}
+	return lang.Concat(s[:pos], replacement, s[pos+len(searchString):]), nil
+}
+
+// GetSubstitution returns the replacement string for a match in str at the
+// given position, by replacing the $-patterns in the given replacement with
+// the matched string, the captures and the named captures.
+// GetSubstitution is specified in 21.1.3.16.1.
+func (r *Realm) GetSubstitution(matched, s lang.String, position int, captures []lang.Value, namedCaptures lang.Value, replacement lang.String) (lang.String, errors.Error) {
+	tailPos := position + len(matched)
+	m := len(captures)
+
+	var result lang.String
+	for i := 0; i < len(replacement); i++ {
+		c := replacement[i]
+		if c != '$' || i+1 == len(replacement) {
+			result = append(result, c)
+			continue
+		}
+		next := replacement[i+1]
+		switch {
+		case next == '$':
+			result = append(result, '$')
+			i++
+		case next == '&':
+			result = append(result, matched...)
+			i++
+		case next == '`':
+			result = append(result, s[:position]...)
+			i++
+		case next == '\'':
+			if tailPos < len(s) {
+				result = append(result, s[tailPos:]...)
+			}
+			i++
+		case next >= '0' && next <= '9':
+			n := int(next - '0')
+			digits := 1
+			if i+2 < len(replacement) && replacement[i+2] >= '0' && replacement[i+2] <= '9' {
+				if nn := n*10 + int(replacement[i+2]-'0'); nn >= 1 && nn <= m {
+					n = nn
+					digits = 2
+				}
+			}
+			if n < 1 || n > m {
+				result = append(result, c)
+				continue
+			}
+			if capture, ok := captures[n-1].(lang.String); ok {
+				result = append(result, capture...)
+			}
+			i += digits
+		case next == '<':
+			if namedCaptures == lang.Undefined {
+				result = append(result, c)
+				continue
+			}
+			end := -1
+			for j := i + 2; j < len(replacement); j++ {
+				if replacement[j] == '>' {
+					end = j
+					break
+				}
+			}
+			if end < 0 {
+				result = append(result, c)
+				continue
+			}
+			groups, err := r.toObject(namedCaptures)
+			if err != nil {
+				return nil, err
+			}
+			capture, err := get(groups, lang.NewStringOrSymbol(append(lang.String{}, replacement[i+2:end]...)))
+			if err != nil {
+				return nil, err
+			}
+			if capture != lang.Undefined {
+				captureString, err := lang.ToString(capture)
+				if err != nil {
+					return nil, err
+				}
+				result = append(result, captureString...)
+			}
+			i = end
+		default:
+			result = append(result, c)
+		}
+	}
+	if result == nil {
+		result = lang.String{}
+	}
+	return result, nil
+}
+
+// stringPrototypeSplit is String.prototype.split.
+// String.prototype.split is specified in 21.1.3.19.
+func (r *Realm) stringPrototypeSplit(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+	o, err := lang.RequireObjectCoercible(this)
+	if err != nil {
+		return nil, err
+	}
+	separator, limit := argument(args, 0), argument(args, 1)
+	if separator != lang.Undefined && separator != lang.Null {
+		splitter, err := lang.GetMethod(r, separator, symbolKey(lang.SymbolSplit))
+		if err != nil {
+			return nil, err
+		}
+		if splitter != lang.Undefined {
+			return lang.CallValue(splitter, separator, o, limit)
+		}
+	}
+	s, err := lang.ToString(o)
+	if err != nil {
+		return nil, err
+	}
+	lim := uint32(math.MaxUint32)
+	if limit != lang.Undefined {
+		n, err := lang.ToUint32(limit)
+		if err != nil {
+			return nil, err
+		}
+		lim = uint32(n.Float64())
+	}
+	sep, err := lang.ToString(separator)
+	if err != nil {
+		return nil, err
+	}
+
+	var parts []lang.Value
+	switch {
+	case lim == 0:
+	case separator == lang.Undefined:
+		parts = append(parts, s)
+	case len(s) == 0:
+		if len(sep) != 0 {
+			parts = append(parts, s)
+		}
+	case len(sep) == 0:
+		for i := 0; i < len(s) && uint32(len(parts)) < lim; i++ {
+			r.work(1)
+			parts = append(parts, lang.String{s[i]})
+		}
+	default:
+		p := 0
+		for q := r.stringIndexOf(s, sep, 0); q >= 0; q = r.stringIndexOf(s, sep, p) {
+			parts = append(parts, append(lang.String{}, s[p:q]...))
+			if uint32(len(parts)) == lim {
+				return lang.CreateArrayFromList(r, parts), nil
+			}
+			p = q + len(sep)
+		}
+		parts = append(parts, append(lang.String{}, s[p:]...))
+	}
+	return lang.CreateArrayFromList(r, parts), nil
+}
+
+// stringIteratorNext is %StringIteratorPrototype%.next.
+// %StringIteratorPrototype%.next is specified in 21.1.5.2.1.
+func (r *Realm) stringIteratorNext(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+	o, ok := this.(*lang.Object)
+	if !ok || !o.HasSlot(slotStringIteratorNextIndex) {
+		return nil, errors.NewTypeError("next method called on incompatible receiver")
+	}
+	s, ok := o.GetSlot(slotIteratedString).(lang.String)
+	if !ok {
+		return lang.CreateIterResultObject(r, lang.Undefined, true), nil
+	}
+	position := int(o.GetSlot(slotStringIteratorNextIndex).(lang.Number).Float64())
+	if position >= len(s) {
+		o.SetSlot(slotIteratedString, lang.Undefined)
+		return lang.CreateIterResultObject(r, lang.Undefined, true), nil
+	}
+	_, n := codePointAt(s, position)
+	o.SetSlot(slotStringIteratorNextIndex, number(float64(position+n)))
+	return lang.CreateIterResultObject(r, append(lang.String{}, s[position:position+n]...), false), nil
+}
@@ -0,0 +1,120 @@
+package realm
+
+import (
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+)
+
+// wellKnownSymbols maps the names of the properties of the Symbol
+// constructor to the well-known symbols, as specified in 19.4.2.
+var wellKnownSymbols = []struct {
+	name   string
+	symbol *lang.Symbol
+}{
+	{"asyncIterator", lang.SymbolAsyncIterator},
+	{"hasInstance", lang.SymbolHasInstance},
+	{"isConcatSpreadable", lang.SymbolIsConcatSpreadable},
+	{"iterator", lang.SymbolIterator},
+	{"match", lang.SymbolMatch},
+	{"replace", lang.SymbolReplace},
+	{"search", lang.SymbolSearch},
+	{"species", lang.SymbolSpecies},
+	{"split", lang.SymbolSplit},
+	{"toPrimitive", lang.SymbolToPrimitive},
+	{"toStringTag", lang.SymbolToStringTag},
+	{"unscopables", lang.SymbolUnscopables},
+}
+
+// createSymbol creates the Symbol constructor and %SymbolPrototype%, as
+// specified in 19.4.
+func (r *Realm) createSymbol() {
+	symbolProto := lang.ObjectCreate(r.intrinsic(IntrinsicNameObjectPrototype))
+	r.Intrinsics.SetField(IntrinsicNameSymbolPrototype, symbolProto)
+
+	symbol := r.newConstructor("Symbol", 0, symbolProto, func(newTarget lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		if newTarget != lang.Undefined {
+			return nil, errors.NewTypeError("Symbol is not a constructor")
+		}
+		description := argument(args, 0)
+		if description == lang.Undefined {
+			return lang.NewSymbol(lang.Undefined), nil
+		}
+		descString, err := lang.ToString(description)
+		if err != nil {
+			return nil, err
+		}
+		return lang.NewSymbol(descString), nil
+	})
+	r.Intrinsics.SetField(IntrinsicNameSymbol, symbol)
+
+	for _, s := range wellKnownSymbols {
+		defineValue(symbol, s.name, s.symbol)
+	}
+	r.defineFunction(symbol, "for", 1, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		k, err := toString(argument(args, 0))
+		if err != nil {
+			return nil, err
+		}
+		if sym, ok := r.symbolRegistry[k]; ok {
+			return sym, nil
+		}
+		sym := lang.NewSymbol(str(k))
+		if r.symbolRegistry == nil {
+			r.symbolRegistry = make(map[string]*lang.Symbol)
+		}
+		r.symbolRegistry[k] = sym
+		return sym, nil
+	})
+	r.defineFunction(symbol, "keyFor", 1, func(_ lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		sym, ok := argument(args, 0).(*lang.Symbol)
+		if !ok {
+			return nil, errors.NewTypeError("Symbol.keyFor requires a symbol")
+		}
+		for k, s := range r.symbolRegistry {
+			if s == sym {
+				return str(k), nil
+			}
+		}
+		return lang.Undefined, nil
+	})
+
+	r.defineGetter(symbolProto, key("description"), func(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+		sym, err := thisSymbolValue(this)
+		if err != nil {
+			return nil, err
+		}
+		return sym.Description, nil
+	})
+	r.defineFunction(symbolProto, "toString", 0, func(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+		sym, err := thisSymbolValue(this)
+		if err != nil {
+			return nil, err
+		}
+		return lang.SymbolDescriptiveString(sym), nil
+	})
+	r.defineFunction(symbolProto, "valueOf", 0, func(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+		return thisSymbolValue(this)
+	})
+	toPrimitive := CreateBuiltinFunction(func(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+		return thisSymbolValue(this)
+	}, r, nil)
+	lang.SetFunctionLength(toPrimitive, 1)
+	lang.SetFunctionName(toPrimitive, symbolKey(lang.SymbolToPrimitive), "")
+	_, _ = lang.DefinePropertyOrThrow(symbolProto, symbolKey(lang.SymbolToPrimitive), lang.NewDataProperty(toPrimitive, lang.False, lang.False, lang.True))
+	defineToStringTag(symbolProto, "Symbol")
+}
+
+// thisSymbolValue returns the given value if it is a Symbol, or the value of
+// its SymbolData internal slot if it is a Symbol object.
+// thisSymbolValue is specified in 19.4.3.
+func thisSymbolValue(v lang.Value) (*lang.Symbol, errors.Error) {
+	switch v := v.(type) {
+	case *lang.Symbol:
+		return v, nil
+	case *lang.Object:
+		if sym, ok := v.GetSlot(lang.SlotSymbolData).(*lang.Symbol); ok {
+			return sym, nil
+		}
+	}
+	return nil, errors.NewTypeError("Symbol.prototype method called on incompatible receiver")
+}
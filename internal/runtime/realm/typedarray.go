@@ -0,0 +1,1038 @@
+package realm
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+)
+
+// slotViewedArrayBuffer is the internal slot of TypedArray and DataView
+// objects, that holds the arrayBufferView with the [[ViewedArrayBuffer]],
+// [[ByteLength]], [[ByteOffset]], [[ArrayLength]] and [[TypedArrayName]]
+// of the object, as specified in 22.2.4.2.1 and 24.3.2.1.
+var slotViewedArrayBuffer = lang.NewStringKey("ViewedArrayBuffer")
+
+// elementType is an element type of TypedArrays, as listed in table 59 of
+// 22.2.
+type elementType struct {
+	name    string // the TypedArray constructor is name+"Array"
+	size    int
+	signed  bool
+	float   bool
+	convert func(lang.Value) (lang.Number, errors.Error)
+}
+
+// elementTypes holds the element types of the TypedArray constructors.
+var elementTypes = []*elementType{
+	{name: "Int8", size: 1, signed: true, convert: lang.ToInt8},
+	{name: "Uint8", size: 1, convert: lang.ToUint8},
+	{name: "Uint8Clamped", size: 1, convert: lang.ToUint8Clamp},
+	{name: "Int16", size: 2, signed: true, convert: lang.ToInt16},
+	{name: "Uint16", size: 2, convert: lang.ToUint16},
+	{name: "Int32", size: 4, signed: true, convert: lang.ToInt32},
+	{name: "Uint32", size: 4, convert: lang.ToUint32},
+	{name: "Float32", size: 4, float: true, convert: lang.ToNumber},
+	{name: "Float64", size: 8, float: true, convert: lang.ToNumber},
+}
+
+// constructorName returns the name of the TypedArray constructor of the
+// element type, which is also the name of its intrinsic.
+func (t *elementType) constructorName() string {
+	return t.name + "Array"
+}
+
+// decode converts the given raw bytes of an element to a number.
+// RawBytesToNumber is specified in 24.1.1.5.
+func (t *elementType) decode(b []byte, order binary.ByteOrder) float64 {
+	if t.float {
+		if t.size == 4 {
+			return float64(math.Float32frombits(order.Uint32(b)))
+		}
+		return math.Float64frombits(order.Uint64(b))
+	}
+
+	var u uint64
+	switch t.size {
+	case 1:
+		u = uint64(b[0])
+	case 2:
+		u = uint64(order.Uint16(b))
+	case 4:
+		u = uint64(order.Uint32(b))
+	}
+	if t.signed {
+		shift := uint(64 - 8*t.size)
+		return float64(int64(u<<shift) >> shift)
+	}
+	return float64(u)
+}
+
+// encode converts the given number to the raw bytes of an element, and
+// stores them in b.
+// NumberToRawBytes is specified in 24.1.1.7.
+func (t *elementType) encode(b []byte, order binary.ByteOrder, n lang.Number) {
+	converted, _ := t.convert(n) // cannot fail, since n is a Number
+	f := converted.Float64()
+	if t.float {
+		if t.size == 4 {
+			order.PutUint32(b, math.Float32bits(float32(f)))
+		} else {
+			order.PutUint64(b, math.Float64bits(f))
+		}
+		return
+	}
+
+	u := uint64(int64(f))
+	switch t.size {
+	case 1:
+		b[0] = byte(u)
+	case 2:
+		order.PutUint16(b, uint16(u))
+	case 4:
+		order.PutUint32(b, uint32(u))
+	}
+}
+
+// arrayBufferView holds the internal slots of a TypedArray or DataView
+// object, that describe the part of the viewed buffer that is accessed
+// through the object.
+type arrayBufferView struct {
+	buffer     *lang.Object
+	block      *dataBlock
+	kind       *elementType // nil for DataView objects
+	byteOffset int
+	byteLength int
+	length     int // the number of elements of a TypedArray
+}
+
+// Type returns lang.TypeInternal.
+func (*arrayBufferView) Type() lang.Type { return lang.TypeInternal }
+
+// Value returns the view itself.
+func (v *arrayBufferView) Value() interface{} { return v }
+
+// typedArrayView returns the view of the given value, if it is a
+// TypedArray object.
+func typedArrayView(v lang.Value) (*arrayBufferView, bool) {
+	if o, ok := v.(*lang.Object); ok && o.HasSlot(slotViewedArrayBuffer) {
+		view := o.GetSlot(slotViewedArrayBuffer).(*arrayBufferView)
+		return view, view.kind != nil
+	}
+	return nil, false
+}
+
+// currentLength returns the number of elements of the TypedArray, which
+// is 0 if its buffer has been detached.
+func (v *arrayBufferView) currentLength() int {
+	if v.block.detached {
+		return 0
+	}
+	return v.length
+}
+
+// elementIndex returns the given numeric index as int, if it is a valid
+// integer index of the TypedArray.
+// IsValidIntegerIndex is specified in 9.4.5.9.
+func (v *arrayBufferView) elementIndex(index lang.Number) (int, bool) {
+	f := index.Float64()
+	if !lang.InternalIsInteger(index) || index.IsNegZero() || f < 0 || f >= float64(v.currentLength()) {
+		return 0, false
+	}
+	return int(f), true
+}
+
+// element returns the element at the given valid index.
+func (v *arrayBufferView) element(i int) lang.Number {
+	offset := v.byteOffset + i*v.kind.size
+	return number(v.kind.decode(v.block.bytes[offset:offset+v.kind.size], binary.LittleEndian))
+}
+
+// setElement sets the element at the given valid index.
+func (v *arrayBufferView) setElement(i int, n lang.Number) {
+	offset := v.byteOffset + i*v.kind.size
+	v.kind.encode(v.block.bytes[offset:offset+v.kind.size], binary.LittleEndian, n)
+}
+
+var integerIndexedExoticMethods = &lang.ExoticMethods{
+	GetOwnProperty:    integerIndexedGetOwnProperty,
+	DefineOwnProperty: integerIndexedDefineOwnProperty,
+	HasProperty:       integerIndexedHasProperty,
+	Get:               integerIndexedGet,
+	Set:               integerIndexedSet,
+	Delete:            integerIndexedDelete,
+	OwnPropertyKeys:   integerIndexedOwnPropertyKeys,
+}
+
+// numericIndex returns the given property key as numeric index, if it is
+// a canonical numeric string.
+func numericIndex(p lang.StringOrSymbol) (lang.Number, bool) {
+	if p.Type() != lang.TypeString {
+		return lang.Zero, false
+	}
+	index, ok := lang.CanonicalNumericIndexString(p.Underlying().(lang.String)).(lang.Number)
+	return index, ok
+}
+
+// view returns the view of the given integer-indexed exotic object.
+func view(o *lang.Object) *arrayBufferView {
+	return o.GetSlot(slotViewedArrayBuffer).(*arrayBufferView)
+}
+
+// integerIndexedGetOwnProperty is specified in 9.4.5.1.
+func integerIndexedGetOwnProperty(o *lang.Object, p lang.StringOrSymbol) *lang.Property {
+	if index, ok := numericIndex(p); ok {
+		v := view(o)
+		i, ok := v.elementIndex(index)
+		if !ok {
+			return nil
+		}
+		return lang.NewDataProperty(v.element(i), lang.True, lang.True, lang.True)
+	}
+	return o.OrdinaryGetOwnProperty(p)
+}
+
+// integerIndexedHasProperty is specified in 9.4.5.2.
+func integerIndexedHasProperty(o *lang.Object, p lang.StringOrSymbol) lang.Boolean {
+	if index, ok := numericIndex(p); ok {
+		_, ok := view(o).elementIndex(index)
+		return lang.Boolean(ok)
+	}
+	return o.OrdinaryHasProperty(p)
+}
+
+// integerIndexedDefineOwnProperty is specified in 9.4.5.3.
+func integerIndexedDefineOwnProperty(o *lang.Object, p lang.StringOrSymbol, desc *lang.Property) (lang.Boolean, errors.Error) {
+	index, ok := numericIndex(p)
+	if !ok {
+		return o.OrdinaryDefineOwnProperty(p, desc), nil
+	}
+	v := view(o)
+	if _, ok := v.elementIndex(index); !ok {
+		return lang.False, nil
+	}
+	if desc.Has(lang.FieldNameConfigurable) && !bool(desc.Configurable()) ||
+		desc.Has(lang.FieldNameEnumerable) && !bool(desc.Enumerable()) ||
+		bool(desc.IsAccessorDescriptor()) ||
+		desc.Has(lang.FieldNameWritable) && !bool(desc.Writable()) {
+		return lang.False, nil
+	}
+	if desc.Has(lang.FieldNameValue) {
+		if err := integerIndexedElementSet(v, index, desc.Value()); err != nil {
+			return lang.False, err
+		}
+	}
+	return lang.True, nil
+}
+
+// integerIndexedGet is specified in 9.4.5.4.
+func integerIndexedGet(o *lang.Object, p lang.StringOrSymbol, receiver lang.Value) (lang.Value, errors.Error) {
+	if index, ok := numericIndex(p); ok {
+		v := view(o)
+		i, ok := v.elementIndex(index)
+		if !ok {
+			return lang.Undefined, nil
+		}
+		return v.element(i), nil
+	}
+	return o.OrdinaryGet(p, receiver)
+}
+
+// integerIndexedSet is specified in 9.4.5.5.
+func integerIndexedSet(o *lang.Object, p lang.StringOrSymbol, value, receiver lang.Value) (lang.Boolean, errors.Error) {
+	if index, ok := numericIndex(p); ok {
+		v := view(o)
+		if receiver == lang.Value(o) {
+			if err := integerIndexedElementSet(v, index, value); err != nil {
+				return lang.False, err
+			}
+			return lang.True, nil
+		}
+		if _, ok := v.elementIndex(index); !ok {
+			return lang.True, nil
+		}
+	}
+	return o.OrdinarySet(p, value, receiver)
+}
+
+// integerIndexedDelete is specified in 9.4.5.6.
+func integerIndexedDelete(o *lang.Object, p lang.StringOrSymbol) lang.Boolean {
+	if index, ok := numericIndex(p); ok {
+		_, ok := view(o).elementIndex(index)
+		return lang.Boolean(!ok)
+	}
+	return o.OrdinaryDelete(p)
+}
+
+// integerIndexedOwnPropertyKeys returns the indices of the elements,
+// followed by the ordinary own property keys.
+// integerIndexedOwnPropertyKeys is specified in 9.4.5.7.
+func integerIndexedOwnPropertyKeys(o *lang.Object) []lang.StringOrSymbol {
+	length := view(o).currentLength()
+	ordinary := o.OrdinaryOwnPropertyKeys()
+	keys := make([]lang.StringOrSymbol, 0, length+len(ordinary))
+	for i := 0; i < length; i++ {
+		keys = append(keys, indexKey(float64(i)))
+	}
+	return append(keys, ordinary...)
+}
+
+// integerIndexedElementSet converts the given value to a Number and stores
+// it at the given index, if it is a valid integer index.
+// IntegerIndexedElementSet is specified in 9.4.5.9.
+func integerIndexedElementSet(v *arrayBufferView, index lang.Number, value lang.Value) errors.Error {
+	n, err := lang.ToNumber(value)
+	if err != nil {
+		return err
+	}
+	if i, ok := v.elementIndex(index); ok {
+		v.setElement(i, n)
+	}
+	return nil
+}
+
+// integerIndexedObjectCreate creates a TypedArray object with the given
+// prototype and element type, that has no buffer yet.
+// IntegerIndexedObjectCreate is specified in 9.4.5.8.
+func integerIndexedObjectCreate(proto *lang.Object, kind *elementType) *lang.Object {
+	o := lang.ObjectCreate(proto, slotViewedArrayBuffer)
+	o.SetSlot(slotViewedArrayBuffer, &arrayBufferView{kind: kind, block: &dataBlock{}})
+	o.Exotic = integerIndexedExoticMethods
+	return o
+}
+
+// ValidateTypedArray returns a TypeError if the given value is not a
+// TypedArray object or if its buffer has been detached.
+// ValidateTypedArray is specified in 22.2.3.5.1.
+func ValidateTypedArray(v lang.Value) errors.Error {
+	_, err := validateTypedArray(v)
+	return err
+}
+
+func validateTypedArray(v lang.Value) (*arrayBufferView, errors.Error) {
+	view, ok := typedArrayView(v)
+	if !ok {
+		return nil, errors.NewTypeError("this is not a typed array.")
+	}
+	if view.block.detached {
+		return nil, errors.NewTypeError("Cannot perform this operation on a detached ArrayBuffer")
+	}
+	return view, nil
+}
+
+// createTypedArrays creates %TypedArray%, %TypedArrayPrototype% and the
+// TypedArray constructors with their prototypes, as specified in 22.2.
+func (r *Realm) createTypedArrays() {
+	proto := lang.ObjectCreate(r.intrinsic(IntrinsicNameObjectPrototype))
+	r.Intrinsics.SetField(IntrinsicNameTypedArrayPrototype, proto)
+
+	typedArray := r.newConstructor("TypedArray", 0, proto, func(lang.Value, ...lang.Value) (lang.Value, errors.Error) {
+		return nil, errors.NewTypeError("Abstract class TypedArray not directly constructable")
+	})
+	r.Intrinsics.SetField(IntrinsicNameTypedArray, typedArray)
+	r.defineFunction(typedArray, "from", 1, r.typedArrayFrom)
+	r.defineFunction(typedArray, "of", 0, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		if !lang.InternalIsConstructor(this) {
+			return nil, errors.NewTypeError("TypedArray.of: this is not a constructor")
+		}
+		newObj, err := r.typedArrayCreate(this.(*lang.Object), float64(len(args)))
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range args {
+			if err := set(newObj, indexKey(float64(k)), v); err != nil {
+				return nil, err
+			}
+		}
+		return newObj, nil
+	})
+	r.defineSpecies(typedArray)
+	r.defineTypedArrayPrototype(proto)
+
+	for _, kind := range elementTypes {
+		r.createTypedArrayConstructor(kind, typedArray, proto)
+	}
+}
+
+// createTypedArrayConstructor creates the TypedArray constructor of the
+// given element type and its prototype, as specified in 22.2.4, 22.2.5
+// and 22.2.6.
+func (r *Realm) createTypedArrayConstructor(kind *elementType, typedArray, typedArrayProto *lang.Object) {
+	name := kind.constructorName()
+	proto := lang.ObjectCreate(typedArrayProto)
+	r.Intrinsics.SetField(name+"Prototype", proto)
+
+	constructor := r.newConstructor(name, 3, proto, func(newTarget lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		nt, ok := newTarget.(*lang.Object)
+		if !ok {
+			return nil, errors.NewTypeError("Constructor " + name + " requires 'new'")
+		}
+		return r.constructTypedArray(kind, nt, args)
+	})
+	constructor.Prototype = typedArray
+	r.Intrinsics.SetField(name, constructor)
+
+	bytesPerElement := number(float64(kind.size))
+	defineValue(constructor, "BYTES_PER_ELEMENT", bytesPerElement)
+	defineValue(proto, "BYTES_PER_ELEMENT", bytesPerElement)
+}
+
+// constructTypedArray implements the TypedArray constructors, which accept
+// a length, a TypedArray, an ArrayBuffer with offset and length, an
+// iterable or an array-like object.
+// The TypedArray constructors are specified in 22.2.4.
+func (r *Realm) constructTypedArray(kind *elementType, newTarget *lang.Object, args []lang.Value) (lang.Value, errors.Error) {
+	proto, err := GetPrototypeFromConstructor(newTarget, kind.constructorName()+"Prototype")
+	if err != nil {
+		return nil, err
+	}
+
+	first, ok := argument(args, 0).(*lang.Object)
+	if !ok {
+		elementLength, err := toIndex(argument(args, 0))
+		if err != nil {
+			return nil, err
+		}
+		return r.allocateTypedArray(proto, kind, elementLength)
+	}
+
+	if src, ok := typedArrayView(first); ok {
+		if src.block.detached {
+			return nil, errors.NewTypeError("Cannot construct a typed array from a detached ArrayBuffer")
+		}
+		o, err := r.allocateTypedArray(proto, kind, float64(src.length))
+		if err != nil {
+			return nil, err
+		}
+		v := view(o)
+		if src.kind == kind {
+			copy(v.block.bytes, src.block.bytes[src.byteOffset:src.byteOffset+src.byteLength])
+		} else {
+			for i := 0; i < src.length; i++ {
+				r.work(1)
+				v.setElement(i, src.element(i))
+			}
+		}
+		return o, nil
+	}
+
+	if block, ok := arrayBufferData(first); ok {
+		return r.typedArrayFromBuffer(proto, kind, first, block, argument(args, 1), argument(args, 2))
+	}
+
+	usingIterator, err := lang.GetMethod(r, first, symbolKey(lang.SymbolIterator))
+	if err != nil {
+		return nil, err
+	}
+	if usingIterator != lang.Undefined {
+		var values []lang.Value
+		err = r.iterate(first, func(v lang.Value) errors.Error {
+			values = append(values, v)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		o, err := r.allocateTypedArray(proto, kind, float64(len(values)))
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range values {
+			r.work(1)
+			if err := set(o, indexKey(float64(k)), v); err != nil {
+				return nil, err
+			}
+		}
+		return o, nil
+	}
+
+	// the object is an array-like object, whose elements are copied
+	// without creating a list of them first
+	length, err := lengthOfArrayLike(first)
+	if err != nil {
+		return nil, err
+	}
+	o, err := r.allocateTypedArray(proto, kind, length)
+	if err != nil {
+		return nil, err
+	}
+	for k := 0.0; k < length; k++ {
+		r.work(1)
+		v, err := get(first, indexKey(k))
+		if err != nil {
+			return nil, err
+		}
+		if err := set(o, indexKey(k), v); err != nil {
+			return nil, err
+		}
+	}
+	return o, nil
+}
+
+// allocateTypedArray creates a TypedArray object of the given element
+// type and prototype, with a new buffer for the given number of elements.
+// AllocateTypedArray is specified in 22.2.4.2.1.
+func (r *Realm) allocateTypedArray(proto *lang.Object, kind *elementType, length float64) (*lang.Object, errors.Error) {
+	buffer, err := AllocateArrayBuffer(r.intrinsic(IntrinsicNameArrayBuffer), length*float64(kind.size))
+	if err != nil {
+		return nil, err
+	}
+	o := integerIndexedObjectCreate(proto, kind)
+	v := view(o)
+	v.buffer = buffer
+	v.block = buffer.GetSlot(slotArrayBufferData).(*dataBlock)
+	v.byteLength = int(length) * kind.size
+	v.length = int(length)
+	return o, nil
+}
+
+// typedArrayFromBuffer creates a TypedArray object that views the given
+// buffer, starting at byteOffset, with the given length.
+// InitializeTypedArrayFromArrayBuffer is specified in 22.2.4.5.
+func (r *Realm) typedArrayFromBuffer(proto *lang.Object, kind *elementType, buffer *lang.Object, block *dataBlock, byteOffset, length lang.Value) (lang.Value, errors.Error) {
+	offset, err := toIndex(byteOffset)
+	if err != nil {
+		return nil, err
+	}
+	if math.Mod(offset, float64(kind.size)) != 0 {
+		return nil, errors.NewRangeError("start offset of " + kind.constructorName() + " should be a multiple of " + numberString(float64(kind.size)))
+	}
+	var newLength float64
+	if length != lang.Undefined {
+		if newLength, err = toIndex(length); err != nil {
+			return nil, err
+		}
+	}
+	if block.detached {
+		return nil, errors.NewTypeError("Cannot construct a typed array from a detached ArrayBuffer")
+	}
+	bufferByteLength := float64(len(block.bytes))
+	var newByteLength float64
+	if length == lang.Undefined {
+		if math.Mod(bufferByteLength, float64(kind.size)) != 0 {
+			return nil, errors.NewRangeError("byte length of " + kind.constructorName() + " should be a multiple of " + numberString(float64(kind.size)))
+		}
+		newByteLength = bufferByteLength - offset
+		if newByteLength < 0 {
+			return nil, errors.NewRangeError("Start offset " + numberString(offset) + " is outside the bounds of the buffer")
+		}
+	} else {
+		newByteLength = newLength * float64(kind.size)
+		if offset+newByteLength > bufferByteLength {
+			return nil, errors.NewRangeError("Invalid typed array length: " + numberString(newLength))
+		}
+	}
+
+	o := integerIndexedObjectCreate(proto, kind)
+	v := view(o)
+	v.buffer = buffer
+	v.block = block
+	v.byteOffset = int(offset)
+	v.byteLength = int(newByteLength)
+	v.length = int(newByteLength) / kind.size
+	return o, nil
+}
+
+// numberString returns the string representation of the given number, as
+// used in error messages.
+func numberString(f float64) string {
+	return lang.NumberToString(number(f)).String()
+}
+
+// typedArrayCreate constructs a TypedArray with the given constructor and
+// length, and returns a TypeError if the result is not a TypedArray with
+// at least that length.
+// TypedArrayCreate is specified in 22.2.4.6.
+func (r *Realm) typedArrayCreate(constructor *lang.Object, length float64) (*lang.Object, errors.Error) {
+	return r.typedArrayCreateWithArgs(constructor, []lang.Value{number(length)})
+}
+
+func (r *Realm) typedArrayCreateWithArgs(constructor *lang.Object, args []lang.Value) (*lang.Object, errors.Error) {
+	newTypedArray, err := lang.Construct(constructor, constructor, args...)
+	if err != nil {
+		return nil, err
+	}
+	v, err := validateTypedArray(newTypedArray)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) == 1 {
+		if n, ok := args[0].(lang.Number); ok && float64(v.length) < n.Float64() {
+			return nil, errors.NewTypeError("Derived TypedArray constructor created an array which was too small")
+		}
+	}
+	return newTypedArray, nil
+}
+
+// typedArraySpeciesCreate constructs a TypedArray with the species
+// constructor of the given exemplar, whose default is the constructor of
+// the element type of the exemplar.
+// TypedArraySpeciesCreate is specified in 22.2.4.7.
+func (r *Realm) typedArraySpeciesCreate(exemplar *lang.Object, args ...lang.Value) (*lang.Object, errors.Error) {
+	defaultConstructor := r.intrinsic(view(exemplar).kind.constructorName())
+	constructor, err := lang.SpeciesConstructor(exemplar, defaultConstructor)
+	if err != nil {
+		return nil, err
+	}
+	return r.typedArrayCreateWithArgs(constructor, args)
+}
+
+// typedArrayFrom is %TypedArray%.from.
+// %TypedArray%.from is specified in 22.2.2.1.
+func (r *Realm) typedArrayFrom(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+	if !lang.InternalIsConstructor(this) {
+		return nil, errors.NewTypeError("TypedArray.from: this is not a constructor")
+	}
+	c := this.(*lang.Object)
+	source, mapfn, thisArg := argument(args, 0), argument(args, 1), argument(args, 2)
+	mapping := mapfn != lang.Undefined
+	if mapping && !lang.InternalIsCallable(mapfn) {
+		return nil, errors.NewTypeError("TypedArray.from: when provided, the second argument must be a function")
+	}
+
+	usingIterator, err := lang.GetMethod(r, source, symbolKey(lang.SymbolIterator))
+	if err != nil {
+		return nil, err
+	}
+	var values []lang.Value
+	if usingIterator != lang.Undefined {
+		err = r.iterate(source, func(v lang.Value) errors.Error {
+			values = append(values, v)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var arrayLike *lang.Object
+	length := float64(len(values))
+	if usingIterator == lang.Undefined {
+		if arrayLike, err = r.toObject(source); err != nil {
+			return nil, err
+		}
+		if length, err = lengthOfArrayLike(arrayLike); err != nil {
+			return nil, err
+		}
+	}
+
+	target, err := r.typedArrayCreate(c, length)
+	if err != nil {
+		return nil, err
+	}
+	for k := 0.0; k < length; k++ {
+		r.work(1)
+		var kValue lang.Value
+		if arrayLike == nil {
+			kValue = values[int(k)]
+		} else if kValue, err = get(arrayLike, indexKey(k)); err != nil {
+			return nil, err
+		}
+		if mapping {
+			if kValue, err = lang.CallValue(mapfn, thisArg, kValue, number(k)); err != nil {
+				return nil, err
+			}
+		}
+		if err := set(target, indexKey(k), kValue); err != nil {
+			return nil, err
+		}
+	}
+	return target, nil
+}
+
+// typedArrayMethods holds the names of the methods of %TypedArrayPrototype%
+// whose algorithm is the same as the method of %ArrayPrototype% with the
+// same name, except that the this value must be a valid TypedArray, as
+// specified in 22.2.3.
+var typedArrayMethods = []string{
+	"copyWithin", "every", "find", "findIndex", "forEach", "includes",
+	"indexOf", "join", "lastIndexOf", "reduce", "reduceRight", "reverse",
+	"some", "toLocaleString",
+}
+
+// defineTypedArrayPrototype defines the properties of %TypedArrayPrototype%,
+// as specified in 22.2.3.
+func (r *Realm) defineTypedArrayPrototype(proto *lang.Object) {
+	r.defineGetter(proto, key("buffer"), func(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+		v, ok := typedArrayView(this)
+		if !ok {
+			return nil, errors.NewTypeError("Method get TypedArray.prototype.buffer called on incompatible receiver")
+		}
+		return v.buffer, nil
+	})
+	r.defineGetter(proto, key("byteLength"), r.typedArrayLengthGetter("byteLength", func(v *arrayBufferView) int { return v.byteLength }))
+	r.defineGetter(proto, key("byteOffset"), r.typedArrayLengthGetter("byteOffset", func(v *arrayBufferView) int { return v.byteOffset }))
+	r.defineGetter(proto, key("length"), r.typedArrayLengthGetter("length", func(v *arrayBufferView) int { return v.length }))
+	r.defineGetter(proto, symbolKey(lang.SymbolToStringTag), func(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+		if v, ok := typedArrayView(this); ok {
+			return str(v.kind.constructorName()), nil
+		}
+		return lang.Undefined, nil
+	})
+
+	arrayProto := r.intrinsic(IntrinsicNameArrayPrototype)
+	for _, name := range typedArrayMethods {
+		method := arrayProto.GetOwnProperty(key(name)).Value().(*lang.Object)
+		length, _ := get(method, key("length"))
+		r.defineFunction(proto, name, int(length.(lang.Number).Float64()), func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+			if _, err := validateTypedArray(this); err != nil {
+				return nil, err
+			}
+			return lang.Call(method, this, args...)
+		})
+	}
+
+	r.defineFunction(proto, "entries", 0, r.typedArrayIteratorFunction(lang.EnumerateKeyPlusValue))
+	r.defineFunction(proto, "fill", 1, r.typedArrayFill)
+	r.defineFunction(proto, "filter", 1, r.typedArrayFilter)
+	r.defineFunction(proto, "keys", 0, r.typedArrayIteratorFunction(lang.EnumerateKey))
+	r.defineFunction(proto, "map", 1, r.typedArrayMap)
+	r.defineFunction(proto, "set", 1, r.typedArraySet)
+	r.defineFunction(proto, "slice", 2, r.typedArraySlice)
+	r.defineFunction(proto, "sort", 1, r.typedArraySort)
+	r.defineFunction(proto, "subarray", 2, r.typedArraySubarray)
+	defineMethodProperty(proto, "toString", arrayProto.GetOwnProperty(key("toString")).Value())
+	values := r.defineFunction(proto, "values", 0, r.typedArrayIteratorFunction(lang.EnumerateValue))
+	_, _ = lang.CreateMethodProperty(proto, symbolKey(lang.SymbolIterator), values)
+}
+
+// typedArrayLengthGetter returns the getter of the byteLength, byteOffset
+// or length property of %TypedArrayPrototype%, which return 0 if the
+// buffer of the TypedArray has been detached.
+func (r *Realm) typedArrayLengthGetter(name string, field func(*arrayBufferView) int) BuiltinFunction {
+	return func(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+		v, ok := typedArrayView(this)
+		if !ok {
+			return nil, errors.NewTypeError("Method get TypedArray.prototype." + name + " called on incompatible receiver")
+		}
+		if v.block.detached {
+			return lang.Zero, nil
+		}
+		return number(float64(field(v))), nil
+	}
+}
+
+// typedArrayIteratorFunction returns the implementation of the entries,
+// keys and values methods of %TypedArrayPrototype%, depending on the given
+// kind.
+func (r *Realm) typedArrayIteratorFunction(kind string) BuiltinFunction {
+	return func(this lang.Value, _ ...lang.Value) (lang.Value, errors.Error) {
+		if _, err := validateTypedArray(this); err != nil {
+			return nil, err
+		}
+		return r.CreateArrayIterator(this.(*lang.Object), kind), nil
+	}
+}
+
+// typedArrayFill is %TypedArray%.prototype.fill.
+// %TypedArray%.prototype.fill is specified in 22.2.3.8.
+func (r *Realm) typedArrayFill(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+	v, err := validateTypedArray(this)
+	if err != nil {
+		return nil, err
+	}
+	value, err := lang.ToNumber(argument(args, 0))
+	if err != nil {
+		return nil, err
+	}
+	length := float64(v.length)
+	k, err := relativeIndex(argument(args, 1), length, 0)
+	if err != nil {
+		return nil, err
+	}
+	final, err := relativeIndex(argument(args, 2), length, length)
+	if err != nil {
+		return nil, err
+	}
+	if v.block.detached {
+		return nil, errors.NewTypeError("Cannot perform %TypedArray%.prototype.fill on a detached ArrayBuffer")
+	}
+	for i := int(k); i < int(final); i++ {
+		r.work(1)
+		v.setElement(i, value)
+	}
+	return this, nil
+}
+
+// typedArrayFilter is %TypedArray%.prototype.filter.
+// %TypedArray%.prototype.filter is specified in 22.2.3.9.
+func (r *Realm) typedArrayFilter(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+	v, err := validateTypedArray(this)
+	if err != nil {
+		return nil, err
+	}
+	callbackfn, err := callable(argument(args, 0), "callback")
+	if err != nil {
+		return nil, err
+	}
+	o := this.(*lang.Object)
+	var kept []lang.Value
+	for k := 0; k < v.length; k++ {
+		r.work(1)
+		kValue, err := get(o, indexKey(float64(k)))
+		if err != nil {
+			return nil, err
+		}
+		selected, err := lang.Call(callbackfn, argument(args, 1), kValue, number(float64(k)), o)
+		if err != nil {
+			return nil, err
+		}
+		if lang.ToBoolean(selected) {
+			kept = append(kept, kValue)
+		}
+	}
+	a, err := r.typedArraySpeciesCreate(o, number(float64(len(kept))))
+	if err != nil {
+		return nil, err
+	}
+	for n, e := range kept {
+		if err := set(a, indexKey(float64(n)), e); err != nil {
+			return nil, err
+		}
+	}
+	return a, nil
+}
+
+// typedArrayMap is %TypedArray%.prototype.map.
+// %TypedArray%.prototype.map is specified in 22.2.3.19.
+func (r *Realm) typedArrayMap(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+	v, err := validateTypedArray(this)
+	if err != nil {
+		return nil, err
+	}
+	callbackfn, err := callable(argument(args, 0), "callback")
+	if err != nil {
+		return nil, err
+	}
+	o := this.(*lang.Object)
+	length := v.length
+	a, err := r.typedArraySpeciesCreate(o, number(float64(length)))
+	if err != nil {
+		return nil, err
+	}
+	for k := 0; k < length; k++ {
+		r.work(1)
+		pk := indexKey(float64(k))
+		kValue, err := get(o, pk)
+		if err != nil {
+			return nil, err
+		}
+		mapped, err := lang.Call(callbackfn, argument(args, 1), kValue, number(float64(k)), o)
+		if err != nil {
+			return nil, err
+		}
+		if err := set(a, pk, mapped); err != nil {
+			return nil, err
+		}
+	}
+	return a, nil
+}
+
+// typedArraySet is %TypedArray%.prototype.set, which copies the elements
+// of a TypedArray or array-like object into the TypedArray.
+// %TypedArray%.prototype.set is specified in 22.2.3.23.
+func (r *Realm) typedArraySet(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+	target, ok := typedArrayView(this)
+	if !ok {
+		return nil, errors.NewTypeError("this is not a typed array.")
+	}
+	targetOffset, err := toInteger(argument(args, 1))
+	if err != nil {
+		return nil, err
+	}
+	if targetOffset < 0 {
+		return nil, errors.NewRangeError("offset is out of bounds")
+	}
+	if target.block.detached {
+		return nil, errors.NewTypeError("Cannot perform %TypedArray%.prototype.set on a detached ArrayBuffer")
+	}
+	targetLength := float64(target.length)
+
+	if src, ok := typedArrayView(argument(args, 0)); ok {
+		if src.block.detached {
+			return nil, errors.NewTypeError("Cannot perform %TypedArray%.prototype.set on a detached ArrayBuffer")
+		}
+		if float64(src.length)+targetOffset > targetLength {
+			return nil, errors.NewRangeError("offset is out of bounds")
+		}
+		offset := int(targetOffset)
+		if src.kind == target.kind {
+			// copy handles overlapping ranges of the same buffer
+			start := target.byteOffset + offset*target.kind.size
+			copy(target.block.bytes[start:start+src.byteLength], src.block.bytes[src.byteOffset:src.byteOffset+src.byteLength])
+			return lang.Undefined, nil
+		}
+		values := make([]lang.Number, src.length)
+		for i := range values {
+			values[i] = src.element(i)
+		}
+		for i, n := range values {
+			target.setElement(offset+i, n)
+		}
+		return lang.Undefined, nil
+	}
+
+	src, err := r.toObject(argument(args, 0))
+	if err != nil {
+		return nil, err
+	}
+	srcLength, err := lengthOfArrayLike(src)
+	if err != nil {
+		return nil, err
+	}
+	if srcLength+targetOffset > targetLength {
+		return nil, errors.NewRangeError("offset is out of bounds")
+	}
+	for k := 0.0; k < srcLength; k++ {
+		r.work(1)
+		value, err := get(src, indexKey(k))
+		if err != nil {
+			return nil, err
+		}
+		if err := integerIndexedElementSet(target, number(targetOffset+k), value); err != nil {
+			return nil, err
+		}
+	}
+	return lang.Undefined, nil
+}
+
+// typedArraySlice is %TypedArray%.prototype.slice.
+// %TypedArray%.prototype.slice is specified in 22.2.3.24.
+func (r *Realm) typedArraySlice(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+	v, err := validateTypedArray(this)
+	if err != nil {
+		return nil, err
+	}
+	length := float64(v.length)
+	k, err := relativeIndex(argument(args, 0), length, 0)
+	if err != nil {
+		return nil, err
+	}
+	final, err := relativeIndex(argument(args, 1), length, length)
+	if err != nil {
+		return nil, err
+	}
+	count := math.Max(final-k, 0)
+	a, err := r.typedArraySpeciesCreate(this.(*lang.Object), number(count))
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return a, nil
+	}
+	if v.block.detached {
+		return nil, errors.NewTypeError("Cannot perform %TypedArray%.prototype.slice on a detached ArrayBuffer")
+	}
+	target := view(a)
+	if target.kind == v.kind {
+		srcStart := v.byteOffset + int(k)*v.kind.size
+		srcEnd := v.byteOffset + int(math.Min(final, float64(v.length)))*v.kind.size
+		copy(target.block.bytes[target.byteOffset:], v.block.bytes[srcStart:srcEnd])
+		return a, nil
+	}
+	for n := 0; k < final; k, n = k+1, n+1 {
+		r.work(1)
+		kValue, err := get(this.(*lang.Object), indexKey(k))
+		if err != nil {
+			return nil, err
+		}
+		if err := set(a, indexKey(float64(n)), kValue); err != nil {
+			return nil, err
+		}
+	}
+	return a, nil
+}
+
+// typedArraySort is %TypedArray%.prototype.sort, which sorts numerically
+// if no comparison function is given.
+// %TypedArray%.prototype.sort is specified in 22.2.3.26.
+func (r *Realm) typedArraySort(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+	comparefn := argument(args, 0)
+	if comparefn != lang.Undefined && !lang.InternalIsCallable(comparefn) {
+		return nil, errors.NewTypeError("The comparison function must be either a function or undefined")
+	}
+	v, err := validateTypedArray(this)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]lang.Value, v.length)
+	for i := range items {
+		items[i] = v.element(i)
+	}
+	err = sortValues(items, func(x, y lang.Value) (float64, errors.Error) {
+		r.work(1)
+		return typedArraySortCompare(x.(lang.Number), y.(lang.Number), comparefn)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i, item := range items {
+		if i < v.currentLength() {
+			v.setElement(i, item.(lang.Number))
+		}
+	}
+	return this, nil
+}
+
+// typedArraySortCompare compares the given numbers, as done by
+// %TypedArray%.prototype.sort. -0 is less than +0, and NaN is greater than
+// all other numbers.
+// TypedArraySortCompare is specified in 22.2.3.26.1.
+func typedArraySortCompare(x, y lang.Number, comparefn lang.Value) (float64, errors.Error) {
+	if comparefn != lang.Undefined {
+		v, err := lang.CallValue(comparefn, lang.Undefined, x, y)
+		if err != nil {
+			return 0, err
+		}
+		n, err := toNumber(v)
+		if err != nil {
+			return 0, err
+		}
+		if math.IsNaN(n) {
+			return 0, nil
+		}
+		return n, nil
+	}
+	xf, yf := x.Float64(), y.Float64()
+	switch {
+	case math.IsNaN(xf) && math.IsNaN(yf):
+		return 0, nil
+	case math.IsNaN(xf):
+		return 1, nil
+	case math.IsNaN(yf):
+		return -1, nil
+	case xf < yf:
+		return -1, nil
+	case xf > yf:
+		return 1, nil
+	case x.IsNegZero() && !y.IsNegZero():
+		return -1, nil
+	case !x.IsNegZero() && y.IsNegZero():
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// typedArraySubarray is %TypedArray%.prototype.subarray, which creates a
+// TypedArray that views the same buffer.
+// %TypedArray%.prototype.subarray is specified in 22.2.3.27.
+func (r *Realm) typedArraySubarray(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+	v, ok := typedArrayView(this)
+	if !ok {
+		return nil, errors.NewTypeError("this is not a typed array.")
+	}
+	srcLength := float64(v.currentLength())
+	begin, err := relativeIndex(argument(args, 0), srcLength, 0)
+	if err != nil {
+		return nil, err
+	}
+	end, err := relativeIndex(argument(args, 1), srcLength, srcLength)
+	if err != nil {
+		return nil, err
+	}
+	newLength := math.Max(end-begin, 0)
+	beginByteOffset := float64(v.byteOffset) + begin*float64(v.kind.size)
+	return r.typedArraySpeciesCreate(this.(*lang.Object), v.buffer, number(beginByteOffset), number(newLength))
+}
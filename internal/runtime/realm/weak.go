@@ -0,0 +1,209 @@
+package realm
+
+import (
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+)
+
+// Internal slots of WeakMap and WeakSet objects, as specified in 23.3.3.1
+// and 23.4.3.1.
+var (
+	slotWeakMapData = lang.NewStringKey("WeakMapData")
+	slotWeakSetData = lang.NewStringKey("WeakSetData")
+)
+
+// weakData is the List of Records of WeakMap and WeakSet objects. The keys
+// are held strongly, so entries are only removed when they are deleted,
+// which is not observable by ECMAScript code.
+type weakData struct {
+	entries map[*lang.Object]lang.Value
+	// heap is the heap that the entries are accounted to.
+	heap *lang.Heap
+}
+
+// newWeakData creates a new, empty weakData, whose entries are accounted
+// to the given heap.
+func newWeakData(heap *lang.Heap) *weakData {
+	return &weakData{entries: make(map[*lang.Object]lang.Value), heap: heap}
+}
+
+// Type returns lang.TypeInternal.
+func (*weakData) Type() lang.Type { return lang.TypeInternal }
+
+// Value returns the data itself.
+func (d *weakData) Value() interface{} { return d }
+
+// set sets the value of the entry with the given key, and returns an error
+// if the heap cannot hold the entry.
+func (d *weakData) set(k *lang.Object, v lang.Value) errors.Error {
+	before := int64(0)
+	if current, exists := d.entries[k]; exists {
+		before = lang.EntrySize(k, current)
+	}
+	if after := lang.EntrySize(k, v); after > before {
+		if err := d.heap.Allocate(after - before); err != nil {
+			return err
+		}
+	} else {
+		d.heap.Release(before - after)
+	}
+	d.entries[k] = v
+	return nil
+}
+
+// delete removes the entry with the given key, and reports whether there
+// was such an entry.
+func (d *weakData) delete(k *lang.Object) bool {
+	v, exists := d.entries[k]
+	if !exists {
+		return false
+	}
+	delete(d.entries, k)
+	d.heap.Release(lang.EntrySize(k, v))
+	return true
+}
+
+// thisWeakData returns the entries of the given WeakMap or WeakSet object.
+func thisWeakData(v lang.Value, slot lang.StringOrSymbol, method string) (*weakData, errors.Error) {
+	if o, ok := v.(*lang.Object); ok && o.HasSlot(slot) {
+		return o.GetSlot(slot).(*weakData), nil
+	}
+	return nil, errors.NewTypeError("Method " + method + " called on incompatible receiver")
+}
+
+// weakKey returns the given value as object, if it can be used as key of a
+// WeakMap or value of a WeakSet.
+func weakKey(v lang.Value) (*lang.Object, bool) {
+	o, ok := v.(*lang.Object)
+	return o, ok
+}
+
+// createWeakMap creates the WeakMap constructor and %WeakMapPrototype%, as
+// specified in 23.3.
+func (r *Realm) createWeakMap() {
+	weakMapProto := lang.ObjectCreate(r.intrinsic(IntrinsicNameObjectPrototype))
+	r.Intrinsics.SetField(IntrinsicNameWeakMapPrototype, weakMapProto)
+
+	weakMap := r.newConstructor("WeakMap", 0, weakMapProto, func(newTarget lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		nt, ok := newTarget.(*lang.Object)
+		if !ok {
+			return nil, errors.NewTypeError("Constructor WeakMap requires 'new'")
+		}
+		m, err := OrdinaryCreateFromConstructor(nt, IntrinsicNameWeakMapPrototype, slotWeakMapData)
+		if err != nil {
+			return nil, err
+		}
+		m.SetSlot(slotWeakMapData, newWeakData(m.Heap()))
+		if iterable := argument(args, 0); iterable != lang.Undefined && iterable != lang.Null {
+			if err := r.addEntriesFromIterable(m, iterable, "set"); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	})
+	r.Intrinsics.SetField(IntrinsicNameWeakMap, weakMap)
+
+	r.defineFunction(weakMapProto, "delete", 1, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		m, err := thisWeakData(this, slotWeakMapData, "WeakMap.prototype.delete")
+		if err != nil {
+			return nil, err
+		}
+		k, ok := weakKey(argument(args, 0))
+		return lang.Boolean(ok && m.delete(k)), nil
+	})
+	r.defineFunction(weakMapProto, "get", 1, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		m, err := thisWeakData(this, slotWeakMapData, "WeakMap.prototype.get")
+		if err != nil {
+			return nil, err
+		}
+		if k, ok := weakKey(argument(args, 0)); ok {
+			if v, exists := m.entries[k]; exists {
+				return v, nil
+			}
+		}
+		return lang.Undefined, nil
+	})
+	r.defineFunction(weakMapProto, "has", 1, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		m, err := thisWeakData(this, slotWeakMapData, "WeakMap.prototype.has")
+		if err != nil {
+			return nil, err
+		}
+		k, ok := weakKey(argument(args, 0))
+		_, exists := m.entries[k]
+		return lang.Boolean(ok && exists), nil
+	})
+	r.defineFunction(weakMapProto, "set", 2, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		m, err := thisWeakData(this, slotWeakMapData, "WeakMap.prototype.set")
+		if err != nil {
+			return nil, err
+		}
+		k, ok := weakKey(argument(args, 0))
+		if !ok {
+			return nil, errors.NewTypeError("Invalid value used as weak map key")
+		}
+		if err := m.set(k, argument(args, 1)); err != nil {
+			return nil, err
+		}
+		return this, nil
+	})
+	defineToStringTag(weakMapProto, "WeakMap")
+}
+
+// createWeakSet creates the WeakSet constructor and %WeakSetPrototype%, as
+// specified in 23.4.
+func (r *Realm) createWeakSet() {
+	weakSetProto := lang.ObjectCreate(r.intrinsic(IntrinsicNameObjectPrototype))
+	r.Intrinsics.SetField(IntrinsicNameWeakSetPrototype, weakSetProto)
+
+	weakSet := r.newConstructor("WeakSet", 0, weakSetProto, func(newTarget lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		nt, ok := newTarget.(*lang.Object)
+		if !ok {
+			return nil, errors.NewTypeError("Constructor WeakSet requires 'new'")
+		}
+		s, err := OrdinaryCreateFromConstructor(nt, IntrinsicNameWeakSetPrototype, slotWeakSetData)
+		if err != nil {
+			return nil, err
+		}
+		s.SetSlot(slotWeakSetData, newWeakData(s.Heap()))
+		if iterable := argument(args, 0); iterable != lang.Undefined && iterable != lang.Null {
+			if err := r.addValuesFromIterable(s, iterable); err != nil {
+				return nil, err
+			}
+		}
+		return s, nil
+	})
+	r.Intrinsics.SetField(IntrinsicNameWeakSet, weakSet)
+
+	r.defineFunction(weakSetProto, "add", 1, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		s, err := thisWeakData(this, slotWeakSetData, "WeakSet.prototype.add")
+		if err != nil {
+			return nil, err
+		}
+		v, ok := weakKey(argument(args, 0))
+		if !ok {
+			return nil, errors.NewTypeError("Invalid value used in weak set")
+		}
+		if err := s.set(v, lang.True); err != nil {
+			return nil, err
+		}
+		return this, nil
+	})
+	r.defineFunction(weakSetProto, "delete", 1, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		s, err := thisWeakData(this, slotWeakSetData, "WeakSet.prototype.delete")
+		if err != nil {
+			return nil, err
+		}
+		v, ok := weakKey(argument(args, 0))
+		return lang.Boolean(ok && s.delete(v)), nil
+	})
+	r.defineFunction(weakSetProto, "has", 1, func(this lang.Value, args ...lang.Value) (lang.Value, errors.Error) {
+		s, err := thisWeakData(this, slotWeakSetData, "WeakSet.prototype.has")
+		if err != nil {
+			return nil, err
+		}
+		v, ok := weakKey(argument(args, 0))
+		_, exists := s.entries[v]
+		return lang.Boolean(ok && exists), nil
+	})
+	defineToStringTag(weakSetProto, "WeakSet")
+}
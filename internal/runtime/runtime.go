@@ -1,8 +1,16 @@
 package runtime
 
 import (
-	"github.com/rs/zerolog"
+	"fmt"
+
 	"github.com/gojisvm/gojis/internal/parser"
+	"github.com/gojisvm/gojis/internal/parser/ast"
+	"github.com/gojisvm/gojis/internal/runtime/agent"
+	"github.com/gojisvm/gojis/internal/runtime/binding"
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+	"github.com/gojisvm/gojis/internal/runtime/realm"
+	"github.com/rs/zerolog"
 )
 
 // Runtime is an object that will evaluate
@@ -11,13 +19,164 @@ type Runtime struct {
 	log zerolog.Logger
 
 	ast *parser.Ast
+
+	agent *agent.Agent
+
+	// strict is true while strict mode code is evaluated. It is set by
+	// the evaluation of scripts, functions and classes, and restored when
+	// their evaluation is done.
+	strict bool
+
+	engine Engine
+
+	// thrown is the value that was thrown most recently.
+	thrown thrownValue
+	// dynamicCount is the number of scripts that have been parsed for eval
+	// and the Function constructor, which is used to name them.
+	dynamicCount int
+
+	// interrupt is the interrupt that was requested by Interrupt.
+	interrupt interrupt
+	// budget counts the steps of the evaluation, and limits them to the
+	// budget set by SetBudget.
+	budget budget
+	// nesting is the number of nested expressions and statements that the
+	// tree-walking engine is evaluating, see nest.
+	nesting int
 }
 
+// Engine determines how a runtime executes ECMAScript code.
+type Engine uint8
+
+// Available engines.
+const (
+	// EngineBytecode compiles scripts and function bodies to bytecode
+	// before executing them.
+	EngineBytecode Engine = iota
+	// EngineTreeWalking evaluates the AST directly.
+	EngineTreeWalking
+)
+
 // New creates a new runtime using the given logger and
-// evaluating the given AST.
+// evaluating the given AST. The runtime has its own agent,
+// whose realm is initialized by InitializeHostDefinedRealm.
 func New(log zerolog.Logger, ast *parser.Ast) *Runtime {
 	r := new(Runtime)
 	r.log = log
 	r.ast = ast
+	r.agent = agent.New()
+	r.agent.InitializeHostDefinedRealm()
+	r.setHostHooks(r.Realm())
 	return r
 }
+
+// SetEngine sets the engine that executes code. Functions that are running
+// when the engine is changed continue to be executed by the engine they
+// were started with.
+func (r *Runtime) SetEngine(engine Engine) {
+	r.engine = engine
+}
+
+// Agent returns the agent that the runtime evaluates code on.
+func (r *Runtime) Agent() *agent.Agent {
+	return r.agent
+}
+
+// Realm returns the realm of the running execution context.
+func (r *Runtime) Realm() *realm.Realm {
+	return r.agent.RunningExecutionContext().Realm
+}
+
+// ScriptEvaluation evaluates the script of the AST with the given name,
+// and returns the completion of the evaluation. A throw completion is
+// returned if the script throws an exception. An error is returned if
+// there is no script with the given name.
+// ScriptEvaluation is specified in 15.1.10.
+func (r *Runtime) ScriptEvaluation(name string) (lang.Completion, error) {
+	script, ok := r.ast.Root(name)
+	if !ok {
+		return lang.Completion{}, fmt.Errorf("No script with name '%v'", name)
+	}
+	if script.SourceType != ast.SourceTypeScript {
+		return lang.Completion{}, fmt.Errorf("'%v' is a module, not a script", name)
+	}
+	r.log.Debug().Str("script", name).Msg("evaluate script")
+
+	return r.scriptEvaluation(r.newScriptRecord(name, script, nil)), nil
+}
+
+// EvaluateProgram evaluates the given script with the given name, which has
+// been parsed by the host, and returns the completion of the evaluation,
+// just like ScriptEvaluation does. The runtime does not keep the script, its
+// bytecode is released together with the functions that are defined in it.
+func (r *Runtime) EvaluateProgram(name string, script *ast.Program) lang.Completion {
+	return r.scriptEvaluation(r.newScriptRecord(name, script, nil))
+}
+
+// scriptEvaluation evaluates the script of the given script record, and
+// returns the completion of the evaluation.
+// ScriptEvaluation is specified in 15.1.10.
+func (r *Runtime) scriptEvaluation(record *scriptRecord) lang.Completion {
+	script := record.code
+	globalEnv := r.Realm().GlobalEnvironment()
+	scriptCtx := &agent.ExecutionContext{
+		Function:            lang.Null,
+		Realm:               r.Realm(),
+		ScriptOrModule:      record,
+		VariableEnvironment: globalEnv,
+		LexicalEnvironment:  globalEnv,
+		Position:            script.Loc().Start,
+	}
+	if err := r.agent.ExecutionContextStack.Push(scriptCtx); err != nil {
+		return r.throw(err)
+	}
+	defer r.agent.ExecutionContextStack.Pop()
+
+	strict := r.strict
+	r.strict = script.Strict
+	defer func() { r.strict = strict }()
+
+	if err := r.globalDeclarationInstantiation(script, globalEnv); err != nil {
+		return r.throw(err)
+	}
+
+	if r.engine == EngineBytecode {
+		return r.run(record.compiled(script))
+	}
+
+	result := r.evaluateStatementList(script.Body)
+	if result.Type == lang.CompletionNormal && result.Value == nil {
+		result.Value = lang.Undefined
+	}
+	return result
+}
+
+// context returns the running execution context.
+func (r *Runtime) context() *agent.ExecutionContext {
+	return r.agent.RunningExecutionContext()
+}
+
+// lexicalEnvironment returns the LexicalEnvironment of the running
+// execution context.
+func (r *Runtime) lexicalEnvironment() binding.Environment {
+	return r.context().LexicalEnvironment
+}
+
+// setLexicalEnvironment sets the LexicalEnvironment of the running
+// execution context.
+func (r *Runtime) setLexicalEnvironment(env binding.Environment) {
+	r.context().LexicalEnvironment = env
+}
+
+// intrinsic returns the intrinsic object with the given name of the
+// current realm.
+func (r *Runtime) intrinsic(name string) *lang.Object {
+	return r.Realm().GetIntrinsicObject(name).(*lang.Object)
+}
+
+// throw returns a throw completion for the given error. Errors that do
+// not carry a thrown value are converted to error objects of the current
+// realm.
+func (r *Runtime) throw(err errors.Error) lang.Completion {
+	return lang.ThrowCompletion(r.ErrorValue(err))
+}
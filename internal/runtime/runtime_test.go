@@ -0,0 +1,355 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/gojisvm/gojis/internal/parser"
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+	"github.com/gojisvm/gojis/internal/runtime/realm"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+// engines holds the engines that scripts are evaluated with in the tests,
+// so that both engines are checked against the same expectations.
+var engines = []struct {
+	name   string
+	engine Engine
+}{
+	{"bytecode", EngineBytecode},
+	{"tree-walking", EngineTreeWalking},
+}
+
+func evaluateScript(t *testing.T, engine Engine, src string) lang.Completion {
+	require := require.New(t)
+
+	p := parser.New()
+	require.NoError(p.ParseString("test.js", src))
+	r := New(zerolog.Nop(), p.Ast())
+	r.SetEngine(engine)
+	completion, err := r.ScriptEvaluation("test.js")
+	require.NoError(err)
+	return completion
+}
+
+func TestScriptEvaluation(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		expected lang.Value
+	}{
+		{"empty script", ``, lang.Undefined},
+		{"number literal", `1.5`, lang.NewNumber(1.5)},
+		{"string concatenation", `"a" + 1 + 2`, lang.NewString("a12")},
+		{"arithmetic", `(1 + 2) * 3 - 4 / 2 % 3`, lang.NewNumber(7)},
+		{"exponentiation", `2 ** 10`, lang.NewNumber(1024)},
+		{"exponentiation NaN", `1 ** Infinity`, lang.NaN},
+		{"bitwise", `(5 & 3) | (1 << 4) ^ (-16 >> 2) >>> 28`, lang.NewNumber(1 | (16 ^ 15))},
+		{"comparison", `1 < 2 && 2 <= 2 && !(NaN >= NaN) && "b" > "a"`, lang.True},
+		{"equality", `null == undefined && "1" == 1 && null !== undefined`, lang.True},
+		{"typeof", `typeof x + typeof null + typeof function(){} + typeof 1`, lang.NewString("undefinedobjectfunctionnumber")},
+		{"var hoisting", `var x = y; var y = 2; x`, lang.Undefined},
+		{"function hoisting", `f(); function f() { return 3; }`, lang.NewNumber(3)},
+		{"closure", `function counter() { var i = 0; return () => ++i; } var c = counter(); c(); c()`, lang.NewNumber(2)},
+		{"let per iteration", `var fs = []; for (let i = 0; i < 3; i++) fs[i] = () => i; fs[0]() + fs[2]()`, lang.NewNumber(2)},
+		{"while with break", `var i = 0; while (true) { if (++i > 4) break; } i`, lang.NewNumber(5)},
+		{"do while", `var i = 0; do { i++; } while (i < 3); i`, lang.NewNumber(3)},
+		{"labelled continue", `var n = 0; outer: for (var i = 0; i < 3; i++) { for (var j = 0; j < 3; j++) { if (j == 1) continue outer; n++; } } n`, lang.NewNumber(3)},
+		{"completion value of loop", `var i = 0; while (i < 2) { i++; "v" + i; }`, lang.NewString("v2")},
+		{"switch fallthrough", `var s = ""; switch (2) { case 1: s += "a"; case 2: s += "b"; default: s += "c"; case 3: s += "d"; break; case 4: s += "e"; } s`, lang.NewString("bcd")},
+		{"switch default", `var s = ""; switch (5) { case 1: s += "a"; default: s += "c"; case 3: s += "d"; } s`, lang.NewString("cd")},
+		{"try catch", `try { throw 1; } catch (e) { e + 1 }`, lang.NewNumber(2)},
+		{"try finally overrides", `(function() { try { return 1; } finally { return 2; } })()`, lang.NewNumber(2)},
+		{"catch runtime error", `try { undefinedVariable; } catch ({message}) { message }`, lang.NewString("undefinedVariable is not defined")},
+		{"object literal", `var o = {a: 1, ["b" + 1]: 2, get c() { return this.a + 1; }}; o.a + o.b1 + o.c`, lang.NewNumber(5)},
+		{"getter and setter", `var o = {x: 0, get v() { return this.x; }, set v(x) { this.x = x + 1; }}; o.v = 1; o.v`, lang.NewNumber(2)},
+		{"setter and getter", `var o = {set v(x) { this.x = x; }, get v() { return this.x * 2; }}; o.v = 2; o.v`, lang.NewNumber(4)},
+		{"object spread", `var o = {...{a: 1, b: 2}, b: 3}; o.a + o.b`, lang.NewNumber(4)},
+		{"array literal", `var a = [1, , 3, ...[4, 5]]; a.length`, lang.NewNumber(5)},
+		{"array destructuring", `var [a, , b = 5, ...rest] = [1, 2, undefined, 4, 6]; a + b + rest[1]`, lang.NewNumber(12)},
+		{"object destructuring", `let {a, b: {c}, d = 4, ...rest} = {a: 1, b: {c: 2}, e: 5}; a + c + d + rest.e`, lang.NewNumber(12)},
+		{"destructuring assignment", `var a, b; [a, b] = [b, a] = [1, 2]; a * 10 + b`, lang.NewNumber(12)},
+		{"default parameters", `function f(a, b = a + 1) { return a + b; } f(1)`, lang.NewNumber(3)},
+		{"rest parameters", `function f(a, ...b) { return b.length; } f(1, 2, 3)`, lang.NewNumber(2)},
+		{"arguments", `function f() { return arguments.length + arguments[1]; } f(1, 2, 3)`, lang.NewNumber(5)},
+		{"mapped arguments", `(function(a) { arguments[0] = 5; return a; })(1)`, lang.NewNumber(5)},
+		{"mapped parameters", `(function(a, b) { a = 2; b = 3; return arguments[0] + "," + arguments[1] + "," + arguments.length; })(1)`, lang.NewString("2,undefined,1")},
+		{"mapped duplicate parameters", `(function(a, a) { a = 3; return arguments[0] + "," + arguments[1]; })(1, 2)`, lang.NewString("1,3")},
+		{"unmapped after delete", `(function(a) { delete arguments[0]; arguments[0] = 2; return a; })(1)`, lang.NewNumber(1)},
+		{"unmapped after defineProperty", `(function(a) { Object.defineProperty(arguments, "0", {value: 2, writable: false}); a = 3; return arguments[0]; })(1)`, lang.NewNumber(2)},
+		{"mapped arguments in arrow function", `(function(a) { (() => arguments[0] = 4)(); return a; })(1)`, lang.NewNumber(4)},
+		{"unmapped strict arguments", `(function(a) { "use strict"; arguments[0] = 5; return a; })(1)`, lang.NewNumber(1)},
+		{"unmapped arguments with default", `(function(a = 0) { arguments[0] = 5; return a; })(1)`, lang.NewNumber(1)},
+		{"block function", `(function() { var before = typeof f; { function f() { return 1; } } return before + f(); })()`, lang.NewString("undefined1")},
+		{"block function not evaluated", `(function() { if (false) { function f() {} } return typeof f; })()`, lang.NewString("undefined")},
+		{"block function in switch", `(function() { switch (1) { case 1: function f() { return 2; } } return f(); })()`, lang.NewNumber(2)},
+		{"block function shadowed by let", `(function() { let f = 1; { function f() {} } return f; })()`, lang.NewNumber(1)},
+		{"block function shadowed by parameter", `(function(f) { { function f() {} } return f; })(3)`, lang.NewNumber(3)},
+		{"block function in strict code", `(function() { "use strict"; { function f() {} } return typeof f; })()`, lang.NewString("undefined")},
+		{"function in if statement", `(function() { if (true) function f() { return 6; } else function g() {} return f() + typeof g; })()`, lang.NewString("6undefined")},
+		{"global block function", `{ function gbf() { return 4; } } gbf()`, lang.NewNumber(4)},
+		{"eval block function", `(function() { eval("{ function ebf() { return 5; } }"); return ebf(); })()`, lang.NewNumber(5)},
+		{"arguments tag", `(function() { return Object.prototype.toString.call(arguments); })()`, lang.NewString("[object Arguments]")},
+		{"spread arguments", `function f(a, b, c) { return a + b + c; } f(...[1, 2], 3)`, lang.NewNumber(6)},
+		{"template literal", "var x = 2; `a${x}b${x + 1}`", lang.NewString("a2b3")},
+		{"tagged template", "function tag(s, v) { return s.raw[0] + v + s[1]; } tag`\\n${1}b`", lang.NewString("\\n1b")},
+		{"for in", `var keys = ""; var o = {a: 1, b: 2}; for (var k in o) keys += k; keys`, lang.NewString("ab")},
+		{"for of", `var sum = 0; for (const x of [1, 2, 3]) sum += x; sum`, lang.NewNumber(6)},
+		{"for of break", `var n = 0; for (let x of [1, 2, 3]) { if (x == 2) break; n += x; } n`, lang.NewNumber(1)},
+		{"new", `function P(x) { this.x = x; } P.prototype.get = function() { return this.x; }; new P(7).get()`, lang.NewNumber(7)},
+		{"new.target", `function F() { return new.target === F; } new F() instanceof F`, lang.True},
+		{"class", `class A { constructor(x) { this.x = x; } get double() { return this.x * 2; } static make() { return new A(2); } } A.make().double`, lang.NewNumber(4)},
+		{"class getter and setter", `class C { get v() { return this.x; } set v(x) { this.x = x * 2; } } var c = new C(); c.v = 3; c.v`, lang.NewNumber(6)},
+		{"static getter and setter", `class C { static set v(x) { this.x = x; } static get v() { return this.x + 1; } } C.v = 1; C.v`, lang.NewNumber(2)},
+		{"class inheritance", `class A { f() { return 1; } } class B extends A { f() { return super.f() + 1; } } new B().f()`, lang.NewNumber(2)},
+		{"derived default constructor", `class A { constructor(x) { this.x = x; } } class B extends A {} new B(3).x`, lang.NewNumber(3)},
+		{"function name", `var f = function() {}; var g = () => {}; f.name + g.name`, lang.NewString("fg")},
+		{"with", `var o = {x: 1}; with (o) { x = 2; } o.x`, lang.NewNumber(2)},
+		{"delete", `var o = {x: 1}; delete o.x; "x" in o`, lang.False},
+		{"update", `var i = 1; var j = i++ + ++i; i * 10 + j`, lang.NewNumber(34)},
+		{"compound assignment", `var x = 2; x **= 3; x -= 1; x`, lang.NewNumber(7)},
+		{"sequence and conditional", `(1, 2) ? 3 : 4`, lang.NewNumber(3)},
+		{"this in sloppy function", `(function() { return this; })() === globalThis`, lang.True},
+		{"this in strict function", `(function() { "use strict"; return this; })()`, lang.Undefined},
+		{"arrow this", `var o = {f() { return (() => this)(); }}; o.f() === o`, lang.True},
+	}
+	for _, e := range engines {
+		for _, tt := range tests {
+			t.Run(e.name+"/"+tt.name, func(t *testing.T) {
+				require := require.New(t)
+
+				completion := evaluateScript(t, e.engine, tt.src)
+				require.Equal(lang.CompletionNormal, completion.Type, "unexpected completion with value %v", completion.Value)
+				require.Equal(tt.expected, completion.Value)
+			})
+		}
+	}
+}
+
+func TestStandardBuiltIns(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		expected lang.Value
+	}{
+		{"global object", `globalThis.Object === Object && Object.getPrototypeOf(globalThis) === Object.prototype`, lang.True},
+		{"global property attributes", `var d = Object.getOwnPropertyDescriptor(globalThis, "Array"); d.writable && !d.enumerable && d.configurable`, lang.True},
+		{"global value properties", `var d = Object.getOwnPropertyDescriptor(globalThis, "NaN"); !d.writable && !d.configurable && isNaN(NaN) && Infinity > 0`, lang.True},
+		{"constructors", `[Object, Function, Array, String, Boolean, Number, Symbol, Error, TypeError, Date, RegExp, Map, Set, WeakMap, WeakSet, Promise, Proxy, ArrayBuffer, DataView, Uint8Array].every(c => typeof c == "function")`, lang.True},
+		{"namespaces", `typeof Math + typeof JSON + typeof Reflect + typeof Atomics`, lang.NewString("objectobjectobjectobject")},
+		{"array methods", `[3, 1, 2].sort().map(x => x * 2).filter(x => x > 2).join("-")`, lang.NewString("4-6")},
+		{"string methods", `"  Hello World ".trim().toUpperCase().split(" ").reverse().join(",")`, lang.NewString("WORLD,HELLO")},
+		{"number formatting", `(255).toString(16) + (1.005).toFixed(1) + Number("0x10")`, lang.NewString("ff1.016")},
+		{"math", `Math.max(1, 3, 2) + Math.floor(-1.5) + Math.abs(-4)`, lang.NewNumber(5)},
+		{"json round trip", `JSON.stringify(JSON.parse('{"a":[1,true,null],"b":"x"}'))`, lang.NewString(`{"a":[1,true,null],"b":"x"}`)},
+		{"map and set", `var m = new Map([[1, "a"]]); var s = new Set([1, 1, 2]); m.get(1) + m.size + s.size`, lang.NewString("a12")},
+		{"regexp", `/(\d+)-(\d+)/.exec("a 12-34 b")[2]`, lang.NewString("34")},
+		{"regexp replace with user-defined exec", `var re = /a/; re.exec = function() { re.exec = () => null; return {length: 3, 0: "a", 1: "x", index: 0}; }; "ab".replace(re, "[$1$2]")`, lang.NewString("[x]b")},
+		{"regexp replace with huge captures length", `var re = /a/; re.exec = () => ({length: 2 ** 53, 0: "a", index: 0}); try { "a".replace(re, "b"); } catch (e) { String(e) }`, lang.NewString("RangeError: Too many elements in array-like object")},
+		{"typed arrays", `var a = new Uint8Array([1, 2, 300]); a[2] + new DataView(a.buffer).getUint16(0, true)`, lang.NewNumber(44 + 513)},
+		{"error objects", `var e = new RangeError("x"); e instanceof Error && e.name + ": " + e.message`, lang.NewString("RangeError: x")},
+		{"uri errors", `try { decodeURI("%"); } catch (e) { e instanceof URIError && String(e) }`, lang.NewString("URIError: URI malformed")},
+		{"aggregate errors", `var e = new AggregateError(new Set([1, 2]), "x"); Object.getPrototypeOf(AggregateError) === Error && e instanceof Error && e.errors.join() + e.message + AggregateError.length`, lang.NewString("1,2x2")},
+		{"symbols", `Symbol.for("a") === Symbol.for("a") && Symbol("a").toString()`, lang.NewString("Symbol(a)")},
+		{"proxy and reflect", `var p = new Proxy({}, {get: (t, k) => k + "!"}); p.x + Reflect.ownKeys({a: 1, [Symbol.iterator]: 2}).length`, lang.NewString("x!2")},
+		{"eval", `var x = 1; (0, eval)("var x = x + 1; x * 10")`, lang.NewNumber(20)},
+		{"indirect eval lexical declarations", `(0, eval)("let y = 1; const z = 2; y + z") + typeof y + typeof z`, lang.NewString("3undefinedundefined")},
+		{"direct eval", `(function() { var x = 5; return eval("x"); })()`, lang.NewNumber(5)},
+		{"direct eval this", `({f() { return eval("this"); }}).f().f !== undefined`, lang.True},
+		{"direct eval var declarations", `(function() { eval("var v = 1; function g() { return v + 1; }"); return v + g(); })() + typeof v + typeof g`, lang.NewString("3undefinedundefined")},
+		{"direct eval in block", `(function() { let a = 1; { let a = 2; return eval("a"); } })()`, lang.NewNumber(2)},
+		{"direct eval in strict code", `"use strict"; eval("var q = 1"); typeof q`, lang.NewString("undefined")},
+		{"direct eval strict code", `(function() { eval("'use strict'; var s = 1"); return typeof s; })()`, lang.NewString("undefined")},
+		{"direct eval strictness of caller", `(function() { "use strict"; try { eval("undeclared = 1"); } catch (e) { return e.name; } })()`, lang.NewString("ReferenceError")},
+		{"direct eval conflicting var", `(function() { let c; try { eval("var c"); } catch (e) { return e.name; } })()`, lang.NewString("SyntaxError")},
+		{"direct eval non-string", `eval(42) + eval()`, lang.NaN},
+		{"eval as property", `var o = {eval}; var e = 1; (function() { var e = 2; return o.eval("e"); })()`, lang.NewNumber(1)},
+		{"function constructor", `new Function("a", "b", "return a + b")(1, 2)`, lang.NewNumber(3)},
+		{"apply", `Math.max.apply(null, [1, 3, 2]) + Reflect.apply(Math.min, null, {length: 2, 0: 5, 1: 4}) + Reflect.construct(Array, {length: 1, 0: 2}).length`, lang.NewNumber(9)},
+		{"apply with huge length", `try { (function() {}).apply(null, {length: 2 ** 53 - 1}); } catch (e) { String(e) }`, lang.NewString("RangeError: Too many elements in array-like object")},
+		{"Reflect.apply with huge length", `try { Reflect.apply(function() {}, null, {length: 2 ** 31}); } catch (e) { String(e) }`, lang.NewString("RangeError: Too many elements in array-like object")},
+		{"Reflect.construct with huge length", `try { Reflect.construct(Array, {length: 2 ** 31}); } catch (e) { String(e) }`, lang.NewString("RangeError: Too many elements in array-like object")},
+		{"apply with many arguments", `try { [].concat.apply([], new Array(1e7)); } catch (e) { String(e) }`, lang.NewString("RangeError: Too many elements in array-like object")},
+		{"typed array from array-like", `new Uint8Array({length: 3, 0: 1, 2: 3}).join()`, lang.NewString("1,0,3")},
+	}
+	for _, e := range engines {
+		for _, tt := range tests {
+			t.Run(e.name+"/"+tt.name, func(t *testing.T) {
+				require := require.New(t)
+
+				completion := evaluateScript(t, e.engine, tt.src)
+				require.Equal(lang.CompletionNormal, completion.Type, "unexpected completion with value %v", completion.Value)
+				require.Equal(tt.expected, completion.Value)
+			})
+		}
+	}
+}
+
+func TestErrorStack(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		expected string
+	}{
+		{"constructed error", "function f() {\n  return new TypeError(\"x\");\n}\nf().stack", "TypeError: x\n    at f (test.js:2:10)\n    at test.js:4:1"},
+		{"thrown error", "try { null.x; } catch (e) { e.stack }", "TypeError: Cannot read properties of null (reading 'x')\n    at test.js:1:7"},
+		{"subclass", "class E extends Error {}\nnew E(\"x\").stack", "Error: x\n    at E (test.js:1:23)\n    at test.js:2:1"},
+		{"capture stack trace", "function C() { Error.captureStackTrace(this, C); }\nfunction f() { return new C(); }\nvar o = f(); o.stack", "Error\n    at f (test.js:2:23)\n    at test.js:3:9"},
+		{"capture stack trace without function", "var o = {name: \"N\", message: \"m\"};\nError.captureStackTrace(o); o.stack", "N: m\n    at test.js:2:1"},
+		{"capture stack trace of inactive function", "var o = {}; Error.captureStackTrace(o, function() {}); o.stack", "Error"},
+		{"stack trace limit", "Error.stackTraceLimit = 1;\nfunction f() { return new Error(); }\nf().stack", "Error\n    at f (test.js:2:23)"},
+		{"no stack trace", "Error.stackTraceLimit = undefined; String(\"stack\" in new Error())", "false"},
+		{"not enumerable", "Object.keys(new Error(\"x\")).length + String(Object.getOwnPropertyDescriptor(new Error(), \"stack\").writable)", "0true"},
+		{"capture stack trace of non-object", "try { Error.captureStackTrace(1); } catch (e) { e.name }", "TypeError"},
+	}
+	for _, e := range engines {
+		for _, tt := range tests {
+			t.Run(e.name+"/"+tt.name, func(t *testing.T) {
+				require := require.New(t)
+
+				completion := evaluateScript(t, e.engine, tt.src)
+				require.Equal(lang.CompletionNormal, completion.Type, "unexpected completion with value %v", completion.Value)
+				require.Equal(lang.NewString(tt.expected), completion.Value)
+			})
+		}
+	}
+}
+
+func TestScriptEvaluationThrow(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		message string
+	}{
+		{"unresolvable reference", `x`, "x is not defined"},
+		{"not a function", `var o = {}; o.f()`, "o.f is not a function"},
+		{"not a constructor", `var f = () => {}; new f()`, "f is not a constructor"},
+		{"property of undefined", `var o; o.x`, "Cannot read properties of undefined (reading 'x')"},
+		{"class without new", `class A {} A()`, "Class constructor A cannot be invoked without 'new'"},
+		{"const assignment", `const c = 1; c = 2`, "Assignment to constant variable."},
+		{"temporal dead zone", `x; let x = 1`, "Cannot access 'x' before initialization"},
+		{"destructure null", `var {a} = null`, "Cannot destructure 'null' as it is null."},
+		// generators and async functions are parsed, but cannot be called
+		// yet
+		{"generator function", `function* g() { yield 1; } g()`, "Generator functions are not supported yet"},
+		{"async function", `(async () => 1)()`, "Async functions are not supported yet"},
+	}
+	for _, e := range engines {
+		for _, tt := range tests {
+			t.Run(e.name+"/"+tt.name, func(t *testing.T) {
+				require := require.New(t)
+
+				completion := evaluateScript(t, e.engine, tt.src)
+				require.Equal(lang.CompletionThrow, completion.Type)
+				err, ok := completion.Value.(*lang.Object)
+				require.True(ok, "thrown value is not an object: %v", completion.Value)
+				msg, _ := lang.Get(err, lang.NewStringKey("message"))
+				require.Equal(lang.NewString(tt.message), msg)
+			})
+		}
+	}
+}
+
+func TestScriptEvaluationThrowValue(t *testing.T) {
+	for _, e := range engines {
+		t.Run(e.name, func(t *testing.T) {
+			completion := evaluateScript(t, e.engine, `throw "error";`)
+			require.Equal(t, lang.ThrowCompletion(lang.NewString("error")), completion)
+		})
+	}
+}
+
+func TestErrorValue(t *testing.T) {
+	require := require.New(t)
+
+	r := New(zerolog.Nop(), parser.New().Ast())
+	thrown := lang.NewString("thrown")
+	v := r.ErrorValue(errors.NewAggregateError([]errors.Error{errors.NewEvalError("a"), lang.NewThrowError(thrown)}, "all failed"))
+	err, ok := v.(*lang.Object)
+	require.True(ok)
+	require.Equal(r.Realm().GetIntrinsicObject(realm.IntrinsicNameAggregateErrorPrototype), err.GetPrototypeOf())
+
+	get := func(o *lang.Object, name string) lang.Value {
+		v, e := lang.Get(o, lang.NewStringKey(name))
+		require.Nil(e)
+		return v.(lang.Value)
+	}
+	require.Equal(lang.NewString("all failed"), get(err, "message"))
+	errs := get(err, "errors").(*lang.Object)
+	require.Equal(lang.NewNumber(2), get(errs, "length"))
+	require.Equal(r.Realm().GetIntrinsicObject(realm.IntrinsicNameEvalErrorPrototype), get(errs, "0").(*lang.Object).GetPrototypeOf())
+	require.Equal(thrown, get(errs, "1"))
+}
+
+func TestScriptEvaluationRedeclaration(t *testing.T) {
+	require := require.New(t)
+
+	p := parser.New()
+	require.NoError(p.ParseString("first.js", `let x = 1;`))
+	require.NoError(p.ParseString("second.js", `var x = 2;`))
+	r := New(zerolog.Nop(), p.Ast())
+
+	completion, err := r.ScriptEvaluation("first.js")
+	require.NoError(err)
+	require.Equal(lang.NormalCompletion(lang.Undefined), completion)
+
+	completion, err = r.ScriptEvaluation("second.js")
+	require.NoError(err)
+	require.Equal(lang.CompletionThrow, completion.Type)
+	msg, _ := lang.Get(completion.Value.(*lang.Object), lang.NewStringKey("message"))
+	require.Equal(lang.NewString("Identifier 'x' has already been declared"), msg)
+}
+
+func TestScriptEvaluationUnknownScript(t *testing.T) {
+	require := require.New(t)
+
+	r := New(zerolog.Nop(), parser.NewEmptyAst())
+	_, err := r.ScriptEvaluation("unknown.js")
+	require.EqualError(err, "No script with name 'unknown.js'")
+}
+
+func TestRunJobs(t *testing.T) {
+	for _, e := range engines {
+		t.Run(e.name, func(t *testing.T) {
+			require := require.New(t)
+
+			p := parser.New()
+			require.NoError(p.ParseString("first.js", `
+var log = [];
+Promise.resolve().then(() => log.push("first then")).then(() => log.push("first then then"));
+log.push("first");
+var count = 0;
+for (var i = 0; i < 20; i++) Promise.resolve().then(() => count++);
+			`))
+			require.NoError(p.ParseString("second.js", `
+Promise.reject("rejected").catch(v => log.push(v));
+log.push("second");
+throw "thrown";
+			`))
+			r := New(zerolog.Nop(), p.Ast())
+			r.SetEngine(e.engine)
+			var reported []errors.Error
+			r.Agent().HostReportErrors = func(err errors.Error) {
+				reported = append(reported, err)
+			}
+
+			require.NoError(r.EnqueueScriptEvaluationJob("first.js"))
+			require.NoError(r.EnqueueScriptEvaluationJob("second.js"))
+			require.EqualError(r.EnqueueScriptEvaluationJob("unknown.js"), "No script with name 'unknown.js'")
+			r.RunJobs()
+
+			require.Equal([]errors.Error{lang.NewThrowError(lang.NewString("thrown"))}, reported)
+			require.Equal(1, r.Agent().ExecutionContextStack.Len())
+			log, err := lang.Get(r.Realm().GlobalObject(), lang.NewStringKey("log"))
+			require.Nil(err)
+			joined, err := lang.Invoke(r.Realm(), log.(lang.Value), lang.NewStringKey("join"), lang.NewString(","))
+			require.Nil(err)
+			require.Equal(lang.NewString("first,first then,first then then,second,rejected"), joined)
+			// the queues grow with the number of pending reactions
+			count, err := lang.Get(r.Realm().GlobalObject(), lang.NewStringKey("count"))
+			require.Nil(err)
+			require.Equal(lang.NewNumber(20), count)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package runtime
+
+import (
+	"github.com/gojisvm/gojis/internal/parser/ast"
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+)
+
+// Script is a script that was compiled ahead of its evaluation. The bytecode
+// of the script and of all functions in it is compiled when the script is
+// created. A Script is never modified afterwards, so it can be evaluated by
+// any number of runtimes, also concurrently.
+type Script struct {
+	name    string
+	program *ast.Program
+	codes   map[interface{}]*code
+}
+
+// CompileScript compiles the given script with the given name and all
+// functions that are defined in it to bytecode. The program must not be
+// modified afterwards.
+func CompileScript(name string, program *ast.Program) *Script {
+	s := new(Script)
+	s.name = name
+	s.program = program
+	s.codes = make(map[interface{}]*code)
+	s.codes[program] = compileScript(program)
+	ast.Inspect(program, func(n ast.Node) bool {
+		var fn *ast.Function
+		switch n := n.(type) {
+		case nil:
+			return false
+		case *ast.FunctionDeclaration:
+			fn = &n.Function
+		case *ast.FunctionExpression:
+			fn = &n.Function
+		case *ast.ArrowFunctionExpression:
+			fn = &n.Function
+		}
+		if fn != nil {
+			s.codes[fn] = compileFunction(fn)
+		}
+		return true
+	})
+	return s
+}
+
+// EvaluateScript evaluates the given compiled script, and returns the
+// completion of the evaluation, just like ScriptEvaluation does.
+func (r *Runtime) EvaluateScript(s *Script) lang.Completion {
+	return r.scriptEvaluation(r.newScriptRecord(s.name, s.program, s.codes))
+}
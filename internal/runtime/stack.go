@@ -0,0 +1,222 @@
+package runtime
+
+import (
+	"fmt"
+
+	"github.com/gojisvm/gojis/internal/parser/ast"
+	"github.com/gojisvm/gojis/internal/runtime/agent"
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+	"github.com/gojisvm/gojis/internal/runtime/realm"
+)
+
+// scriptRecord encapsulates information about a script being evaluated.
+// The name of the script is the host defined information of the record.
+// The functions that are defined in the script refer to the record, so that
+// the bytecode of the script is kept as long as its functions are.
+// Script records are specified in 15.1.8.
+type scriptRecord struct {
+	realm *realm.Realm
+	code  *ast.Program
+	name  string
+
+	// precompiled holds the bytecode of a Script, which is shared by all
+	// runtimes that evaluate it and must not be modified. codes caches the
+	// bytecode that is compiled while the script is evaluated.
+	precompiled map[interface{}]*code
+	codes       map[interface{}]*code
+
+	// blockFunctions holds the function declarations in blocks of the
+	// script, that assign their function object to a var binding as well
+	// when they are evaluated, see blockFunctionDeclarations.
+	blockFunctions map[*ast.FunctionDeclaration]bool
+}
+
+// newScriptRecord creates a script record for the given script with the
+// given name in the current realm. The given bytecode, which may be nil,
+// has been compiled ahead of the evaluation.
+func (r *Runtime) newScriptRecord(name string, script *ast.Program, precompiled map[interface{}]*code) *scriptRecord {
+	return &scriptRecord{
+		realm:       r.Realm(),
+		code:        script,
+		name:        name,
+		precompiled: precompiled,
+		codes:       make(map[interface{}]*code),
+
+		blockFunctions: make(map[*ast.FunctionDeclaration]bool),
+	}
+}
+
+// compiled returns the bytecode of the given *ast.Program or *ast.Function
+// of the script, which is compiled when it is needed for the first time.
+func (s *scriptRecord) compiled(n interface{}) *code {
+	if c, ok := s.precompiled[n]; ok {
+		return c
+	}
+	if c, ok := s.codes[n]; ok {
+		return c
+	}
+
+	var c *code
+	switch n := n.(type) {
+	case *ast.Program:
+		c = compileScript(n)
+	case *ast.Function:
+		c = compileFunction(n)
+	}
+	s.codes[n] = c
+	return c
+}
+
+// Type returns lang.TypeInternal.
+func (*scriptRecord) Type() lang.Type { return lang.TypeInternal }
+
+// Value returns the script record itself.
+func (s *scriptRecord) Value() interface{} { return s }
+
+// StackFrame is a frame of the stack trace of a thrown value, that is, an
+// execution context of a script or function that was on the execution
+// context stack when the value was thrown.
+type StackFrame struct {
+	// Function is the name of the function of the frame. It is empty for
+	// frames of script code and of anonymous functions.
+	Function string
+	// Script is the name of the script that contains the code of the
+	// frame.
+	Script string
+	// Line and Column are the position of the statement, call or new
+	// expression that was evaluated by the frame. Lines and columns start
+	// at 1. Both are 0 if the position is not known.
+	Line, Column int
+}
+
+func (f StackFrame) String() string {
+	location := f.Script
+	if f.Line > 0 {
+		location += fmt.Sprintf(":%v:%v", f.Line, f.Column)
+	}
+	if f.Function == "" {
+		return location
+	}
+	return f.Function + " (" + location + ")"
+}
+
+// thrownValue is the value that was thrown most recently, together with
+// the stack trace at the point where it was thrown.
+type thrownValue struct {
+	value lang.Value
+	stack []StackFrame
+}
+
+// stackTrace returns the stack trace of the execution context stack,
+// starting with the running execution context.
+func (r *Runtime) stackTrace() []StackFrame {
+	frames, _ := r.stackTraceBelow(lang.Undefined)
+	return frames
+}
+
+// stackTraceBelow returns the stack trace of the execution context stack,
+// starting with the running execution context. If fn is a function, the
+// frames up to and including the innermost execution context of fn are
+// left out, and false is returned if there is no execution context of fn.
+func (r *Runtime) stackTraceBelow(fn lang.Value) ([]StackFrame, bool) {
+	var frames []StackFrame
+	found := fn == lang.Undefined
+	r.agent.ExecutionContextStack.Range(func(ctx *agent.ExecutionContext) bool {
+		if !found && ctx.Function == fn {
+			frames, found = frames[:0], true
+			return true
+		}
+		script, ok := ctx.ScriptOrModule.(*scriptRecord)
+		if !ok {
+			return true
+		}
+		frame := StackFrame{Script: script.name}
+		if ctx.Position.IsValid() {
+			frame.Line, frame.Column = ctx.Position.Line, ctx.Position.Column+1
+		}
+		if fn, ok := ctx.Function.(*lang.Object); ok {
+			frame.Function = functionName(fn)
+		}
+		frames = append(frames, frame)
+		return true
+	})
+	return frames, found
+}
+
+// errorStackTrace returns the frames of the stack trace for the stack
+// property of error objects, as described by realm.Realm#StackTrace.
+func (r *Runtime) errorStackTrace(fn lang.Value) []string {
+	frames, found := r.stackTraceBelow(fn)
+	if !found {
+		return nil
+	}
+	lines := make([]string, len(frames))
+	for i, frame := range frames {
+		lines[i] = frame.String()
+	}
+	return lines
+}
+
+// enter sets the position of the running execution context to the start
+// of the given node, and returns the previous position, which is restored
+// by leave once the node has been evaluated. The position is not restored
+// if the evaluation throws, so that the stack trace refers to the node.
+func (r *Runtime) enter(n ast.Node) ast.Position {
+	ctx := r.context()
+	pos := ctx.Position
+	ctx.Position = n.Loc().Start
+	return pos
+}
+
+// leave restores the given position of the running execution context,
+// which was returned by enter.
+func (r *Runtime) leave(pos ast.Position) {
+	r.context().Position = pos
+}
+
+// functionName returns the value of the name property of the given function
+// object, or the empty string if the function has no name.
+func functionName(fn *lang.Object) string {
+	desc := fn.GetOwnProperty(lang.NewStringKey("name"))
+	if desc == nil || !desc.IsDataDescriptor() || desc.Value().Type() != lang.TypeString {
+		return ""
+	}
+	name, _ := lang.ToString(desc.Value())
+	return name.String()
+}
+
+// ErrorValue returns the language value that is thrown for the given error.
+// If the error does not carry a thrown value, a new error object is created,
+// and the stack trace at this point is recorded for it.
+func (r *Runtime) ErrorValue(err errors.Error) lang.Value {
+	if thrown, ok := err.(*lang.ThrowError); ok {
+		return thrown.Value
+	}
+	v := r.Realm().ErrorValue(err)
+	r.recordThrow(v)
+	return v
+}
+
+// recordThrow records the stack trace for the given value, which is about
+// to be thrown. An object that is rethrown keeps the stack trace of the
+// point where it was thrown first.
+func (r *Runtime) recordThrow(v lang.Value) {
+	if obj, ok := v.(*lang.Object); ok {
+		if prev, ok := r.thrown.value.(*lang.Object); ok && prev == obj {
+			return
+		}
+	}
+	r.thrown = thrownValue{value: v, stack: r.stackTrace()}
+}
+
+// StackTrace returns the stack trace of the given value at the point where
+// it was thrown, starting with the innermost frame. Only the stack trace of
+// the value that was thrown most recently is known, nil is returned for
+// all other values.
+func (r *Runtime) StackTrace(thrown lang.Value) []StackFrame {
+	if r.thrown.value == nil || !lang.InternalSameValue(r.thrown.value, thrown) {
+		return nil
+	}
+	return r.thrown.stack
+}
@@ -0,0 +1,660 @@
+package runtime
+
+import (
+	"fmt"
+
+	"github.com/gojisvm/gojis/internal/parser/ast"
+	"github.com/gojisvm/gojis/internal/runtime/binding"
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+)
+
+// evaluateStatementList evaluates the given statements in order, and
+// returns the completion of the last statement that produced a value.
+// The evaluation of StatementList is specified in 13.2.13.
+func (r *Runtime) evaluateStatementList(list []ast.Statement) lang.Completion {
+	var v lang.Value
+	for _, stmt := range list {
+		s := r.evaluateStatement(stmt)
+		if s.IsAbrupt() {
+			return lang.UpdateEmpty(s, v)
+		}
+		if s.Value != nil {
+			v = s.Value
+		}
+	}
+	return lang.NormalCompletion(v)
+}
+
+// evaluateStatement evaluates the given statement or declaration at the
+// position of the statement, which is left in place if the statement
+// throws.
+func (r *Runtime) evaluateStatement(n ast.Statement) lang.Completion {
+	if err := r.nest(); err != nil {
+		return r.throw(err)
+	}
+	defer r.unnest()
+	pos := r.enter(n)
+	c := r.evaluateStatementNode(n)
+	if c.Type != lang.CompletionThrow {
+		r.leave(pos)
+	}
+	return c
+}
+
+// evaluateStatementNode evaluates the given statement or declaration.
+func (r *Runtime) evaluateStatementNode(n ast.Statement) lang.Completion {
+	switch n := n.(type) {
+	case *ast.ExpressionStatement:
+		v, err := r.evaluate(n.Expression)
+		if err != nil {
+			return r.throw(err)
+		}
+		return lang.NormalCompletion(v)
+	case *ast.VariableDeclaration:
+		if err := r.evaluateVariableDeclaration(n); err != nil {
+			return r.throw(err)
+		}
+		return lang.NormalCompletion(nil)
+	case *ast.FunctionDeclaration:
+		// functions are instantiated by the declaration instantiation of
+		// the enclosing scope
+		r.evaluateBlockFunction(n)
+		return lang.NormalCompletion(nil)
+	case *ast.ClassDeclaration:
+		return r.evaluateClassDeclaration(n)
+	case *ast.BlockStatement:
+		return r.evaluateBlock(n)
+	case *ast.EmptyStatement, *ast.DebuggerStatement:
+		return lang.NormalCompletion(nil)
+	case *ast.IfStatement:
+		return r.evaluateIf(n)
+	case *ast.ReturnStatement:
+		var v lang.Value = lang.Undefined
+		if n.Argument != nil {
+			var err errors.Error
+			if v, err = r.evaluate(n.Argument); err != nil {
+				return r.throw(err)
+			}
+		}
+		return lang.Completion{Type: lang.CompletionReturn, Value: v}
+	case *ast.ThrowStatement:
+		v, err := r.evaluate(n.Argument)
+		if err != nil {
+			return r.throw(err)
+		}
+		r.recordThrow(v)
+		return lang.ThrowCompletion(v)
+	case *ast.BreakStatement:
+		return lang.Completion{Type: lang.CompletionBreak, Target: labelName(n.Label)}
+	case *ast.ContinueStatement:
+		return lang.Completion{Type: lang.CompletionContinue, Target: labelName(n.Label)}
+	case *ast.WithStatement:
+		return r.evaluateWith(n)
+	case *ast.TryStatement:
+		return r.evaluateTry(n)
+	case *ast.LabeledStatement, *ast.WhileStatement, *ast.DoWhileStatement, *ast.ForStatement,
+		*ast.ForInStatement, *ast.ForOfStatement, *ast.SwitchStatement:
+		return r.labelledEvaluation(n, nil)
+	}
+	panic(fmt.Sprintf("Unexpected statement %T", n))
+}
+
+// labelName returns the name of the given label, or the empty string if
+// the label is nil.
+func labelName(label *ast.Identifier) string {
+	if label == nil {
+		return ""
+	}
+	return label.Name
+}
+
+// labelledEvaluation evaluates the given labelled or breakable statement,
+// where labelSet holds the labels of the statement.
+// LabelledEvaluation is specified in 13.7.1.2, 13.12.7 and 13.13.14.
+func (r *Runtime) labelledEvaluation(n ast.Statement, labelSet []string) lang.Completion {
+	var stmtResult lang.Completion
+	switch n := n.(type) {
+	case *ast.LabeledStatement:
+		label := n.Label.Name
+		stmtResult = r.labelledEvaluation(n.Body, append(labelSet[:len(labelSet):len(labelSet)], label))
+		if stmtResult.Type == lang.CompletionBreak && stmtResult.Target == label {
+			return lang.NormalCompletion(stmtResult.Value)
+		}
+		return stmtResult
+	case *ast.WhileStatement:
+		stmtResult = r.evaluateWhile(n, labelSet)
+	case *ast.DoWhileStatement:
+		stmtResult = r.evaluateDoWhile(n, labelSet)
+	case *ast.ForStatement:
+		stmtResult = r.evaluateFor(n, labelSet)
+	case *ast.ForInStatement:
+		stmtResult = r.evaluateForInOf(n.Left, n.Right, n.Body, false, labelSet)
+	case *ast.ForOfStatement:
+		if n.Await {
+			return r.throw(errors.NewSyntaxError("for await is not supported yet"))
+		}
+		stmtResult = r.evaluateForInOf(n.Left, n.Right, n.Body, true, labelSet)
+	case *ast.SwitchStatement:
+		stmtResult = r.evaluateSwitch(n)
+	default:
+		// labelled statements that are not breakable
+		return r.evaluateStatement(n)
+	}
+
+	if stmtResult.Type == lang.CompletionBreak && stmtResult.Target == "" {
+		if stmtResult.Value == nil {
+			return lang.NormalCompletion(lang.Undefined)
+		}
+		return lang.NormalCompletion(stmtResult.Value)
+	}
+	return stmtResult
+}
+
+// loopContinues returns whether a loop with the given label set continues
+// after its body completed with the given completion.
+// LoopContinues is specified in 13.7.1.2.
+func loopContinues(completion lang.Completion, labelSet []string) bool {
+	if completion.Type == lang.CompletionNormal {
+		return true
+	}
+	if completion.Type != lang.CompletionContinue {
+		return false
+	}
+	return completion.Target == "" || containsString(labelSet, completion.Target)
+}
+
+// evaluateVariableDeclaration evaluates the initializers of the given
+// variable declaration, and assigns or initializes the bindings.
+// The evaluation of variable declarations is specified in 13.3.1.4 and
+// 13.3.2.4.
+func (r *Runtime) evaluateVariableDeclaration(n *ast.VariableDeclaration) errors.Error {
+	var env binding.Environment
+	if n.Kind != ast.VariableKindVar {
+		env = r.lexicalEnvironment()
+	}
+
+	for _, decl := range n.Declarations {
+		if decl.Init == nil {
+			if n.Kind == ast.VariableKindVar {
+				continue
+			}
+			// let x; initializes x with undefined
+			if err := r.bindingInitialization(decl.ID, lang.Undefined, env); err != nil {
+				return err
+			}
+			continue
+		}
+
+		var value lang.Value
+		var err errors.Error
+		if id, ok := decl.ID.(*ast.Identifier); ok && isAnonymousFunctionDefinition(decl.Init) {
+			value, err = r.namedEvaluation(decl.Init, lang.NewStringKey(id.Name))
+		} else {
+			value, err = r.evaluate(decl.Init)
+		}
+		if err != nil {
+			return err
+		}
+		if err := r.bindingInitialization(decl.ID, value, env); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// evaluateBlock evaluates the given block in a new declarative environment
+// that holds the lexically scoped declarations of the block.
+// The evaluation of Block is specified in 13.2.13.
+func (r *Runtime) evaluateBlock(n *ast.BlockStatement) lang.Completion {
+	if len(lexicallyScopedDeclarations(n.Body, false)) == 0 {
+		return r.evaluateStatementList(n.Body)
+	}
+
+	oldEnv := r.lexicalEnvironment()
+	blockEnv := binding.NewDeclarativeEnvironment(oldEnv)
+	r.blockDeclarationInstantiation(n.Body, blockEnv)
+	r.setLexicalEnvironment(blockEnv)
+	blockValue := r.evaluateStatementList(n.Body)
+	r.setLexicalEnvironment(oldEnv)
+	return blockValue
+}
+
+// evaluateIf evaluates the given if statement.
+// The evaluation of IfStatement is specified in 13.6.7.
+func (r *Runtime) evaluateIf(n *ast.IfStatement) lang.Completion {
+	exprValue, err := r.evaluate(n.Test)
+	if err != nil {
+		return r.throw(err)
+	}
+
+	if lang.ToBoolean(exprValue) {
+		return lang.UpdateEmpty(r.evaluateStatement(ifClause(n.Consequent)), lang.Undefined)
+	}
+	if n.Alternate == nil {
+		return lang.NormalCompletion(lang.Undefined)
+	}
+	return lang.UpdateEmpty(r.evaluateStatement(ifClause(n.Alternate)), lang.Undefined)
+}
+
+// evaluateWhile evaluates the given while loop.
+// LabelledEvaluation of while loops is specified in 13.7.3.6.
+func (r *Runtime) evaluateWhile(n *ast.WhileStatement, labelSet []string) lang.Completion {
+	var v lang.Value = lang.Undefined
+	for {
+		exprValue, err := r.evaluate(n.Test)
+		if err != nil {
+			return r.throw(err)
+		}
+		if !lang.ToBoolean(exprValue) {
+			return lang.NormalCompletion(v)
+		}
+
+		stmtResult := r.evaluateStatement(n.Body)
+		if !loopContinues(stmtResult, labelSet) {
+			return lang.UpdateEmpty(stmtResult, v)
+		}
+		if stmtResult.Value != nil {
+			v = stmtResult.Value
+		}
+		r.step()
+	}
+}
+
+// evaluateDoWhile evaluates the given do-while loop.
+// LabelledEvaluation of do-while loops is specified in 13.7.2.6.
+func (r *Runtime) evaluateDoWhile(n *ast.DoWhileStatement, labelSet []string) lang.Completion {
+	var v lang.Value = lang.Undefined
+	for {
+		stmtResult := r.evaluateStatement(n.Body)
+		if !loopContinues(stmtResult, labelSet) {
+			return lang.UpdateEmpty(stmtResult, v)
+		}
+		if stmtResult.Value != nil {
+			v = stmtResult.Value
+		}
+
+		exprValue, err := r.evaluate(n.Test)
+		if err != nil {
+			return r.throw(err)
+		}
+		if !lang.ToBoolean(exprValue) {
+			return lang.NormalCompletion(v)
+		}
+		r.step()
+	}
+}
+
+// evaluateFor evaluates the given for loop. If the loop declares let
+// bindings, every iteration gets a copy of the bindings, so that closures
+// created in the body capture the bindings of their iteration.
+// LabelledEvaluation of for loops is specified in 13.7.4.7.
+func (r *Runtime) evaluateFor(n *ast.ForStatement, labelSet []string) lang.Completion {
+	decl, ok := n.Init.(*ast.VariableDeclaration)
+	if !ok || decl.Kind == ast.VariableKindVar {
+		switch init := n.Init.(type) {
+		case nil:
+		case *ast.VariableDeclaration:
+			if err := r.evaluateVariableDeclaration(init); err != nil {
+				return r.throw(err)
+			}
+		default:
+			if _, err := r.evaluate(init); err != nil {
+				return r.throw(err)
+			}
+		}
+		return r.forBodyEvaluation(n, nil, labelSet)
+	}
+
+	oldEnv := r.lexicalEnvironment()
+	loopEnv := binding.NewDeclarativeEnvironment(oldEnv)
+	isConst := decl.Kind == ast.VariableKindConst
+	boundNames := boundNames(decl)
+	for _, dn := range boundNames {
+		if isConst {
+			_ = loopEnv.CreateImmutableBinding(lang.NewString(dn), true)
+		} else {
+			_ = loopEnv.CreateMutableBinding(lang.NewString(dn), false)
+		}
+	}
+	r.setLexicalEnvironment(loopEnv)
+	defer r.setLexicalEnvironment(oldEnv)
+
+	if err := r.evaluateVariableDeclaration(decl); err != nil {
+		return r.throw(err)
+	}
+
+	var perIterationLets []string
+	if !isConst {
+		perIterationLets = boundNames
+	}
+	return r.forBodyEvaluation(n, perIterationLets, labelSet)
+}
+
+// forBodyEvaluation evaluates the test, body and update of the given for
+// loop, until the test is false or the body completes abruptly.
+// ForBodyEvaluation is specified in 13.7.4.8.
+func (r *Runtime) forBodyEvaluation(n *ast.ForStatement, perIterationBindings []string, labelSet []string) lang.Completion {
+	var v lang.Value = lang.Undefined
+	r.createPerIterationEnvironment(perIterationBindings)
+	for {
+		if n.Test != nil {
+			testValue, err := r.evaluate(n.Test)
+			if err != nil {
+				return r.throw(err)
+			}
+			if !lang.ToBoolean(testValue) {
+				return lang.NormalCompletion(v)
+			}
+		}
+
+		result := r.evaluateStatement(n.Body)
+		if !loopContinues(result, labelSet) {
+			return lang.UpdateEmpty(result, v)
+		}
+		if result.Value != nil {
+			v = result.Value
+		}
+
+		r.createPerIterationEnvironment(perIterationBindings)
+		if n.Update != nil {
+			if _, err := r.evaluate(n.Update); err != nil {
+				return r.throw(err)
+			}
+		}
+		r.step()
+	}
+}
+
+// createPerIterationEnvironment replaces the running lexical environment
+// with a copy that holds the given bindings with their current values.
+// CreatePerIterationEnvironment is specified in 13.7.4.9.
+func (r *Runtime) createPerIterationEnvironment(perIterationBindings []string) {
+	if len(perIterationBindings) == 0 {
+		return
+	}
+
+	lastIterationEnv := r.lexicalEnvironment()
+	thisIterationEnv := binding.NewDeclarativeEnvironment(lastIterationEnv.Outer())
+	for _, bn := range perIterationBindings {
+		n := lang.NewString(bn)
+		_ = thisIterationEnv.CreateMutableBinding(n, false)
+		lastValue, _ := lastIterationEnv.GetBindingValue(n, true)
+		_ = thisIterationEnv.InitializeBinding(n, lastValue)
+	}
+	r.setLexicalEnvironment(thisIterationEnv)
+}
+
+// evaluateForInOf evaluates a for-in loop, or a for-of loop if iterate is
+// true, with the given left hand side, expression and body.
+// LabelledEvaluation of for-in and for-of loops is specified in 13.7.5.11.
+func (r *Runtime) evaluateForInOf(lhs ast.Node, expr ast.Expression, stmt ast.Statement, iterate bool, labelSet []string) lang.Completion {
+	// ForIn/OfHeadEvaluation, as specified in 13.7.5.12
+	var target ast.Node = lhs
+	var decl *ast.VariableDeclaration
+	if d, ok := lhs.(*ast.VariableDeclaration); ok {
+		decl = d
+		target = d.Declarations[0].ID
+	}
+	lexical := decl != nil && decl.Kind != ast.VariableKindVar
+
+	oldEnv := r.lexicalEnvironment()
+	if lexical {
+		// the bound names are in the temporal dead zone while the
+		// expression is evaluated
+		tdz := binding.NewDeclarativeEnvironment(oldEnv)
+		for _, name := range boundNames(decl) {
+			_ = tdz.CreateMutableBinding(lang.NewString(name), false)
+		}
+		r.setLexicalEnvironment(tdz)
+	}
+	exprValue, err := r.evaluate(expr)
+	r.setLexicalEnvironment(oldEnv)
+	if err != nil {
+		return r.throw(err)
+	}
+
+	var next func() (lang.Value, bool, errors.Error)
+	closeIterator := func(err errors.Error) errors.Error { return err }
+	if iterate {
+		iteratorRecord, err := lang.GetIterator(r.Realm(), exprValue)
+		if err != nil {
+			return r.throw(err)
+		}
+		next = func() (lang.Value, bool, errors.Error) {
+			v, err := iteratorStepValue(iteratorRecord)
+			return v, iteratorRecord.Done, err
+		}
+		closeIterator = func(err errors.Error) errors.Error {
+			return lang.IteratorClose(iteratorRecord, err)
+		}
+	} else {
+		if exprValue == lang.Undefined || exprValue == lang.Null {
+			return lang.Completion{Type: lang.CompletionBreak}
+		}
+		obj, err := lang.ToObject(r.Realm(), exprValue)
+		if err != nil {
+			return r.throw(err)
+		}
+		next = enumerateObjectProperties(obj)
+	}
+
+	// ForIn/OfBodyEvaluation, as specified in 13.7.5.13
+	var v lang.Value = lang.Undefined
+	for {
+		nextValue, done, err := next()
+		if err != nil {
+			return r.throw(err)
+		}
+		if done {
+			return lang.NormalCompletion(v)
+		}
+
+		var env binding.Environment
+		if lexical {
+			iterationEnv := binding.NewDeclarativeEnvironment(oldEnv)
+			for _, name := range boundNames(decl) {
+				if decl.Kind == ast.VariableKindConst {
+					_ = iterationEnv.CreateImmutableBinding(lang.NewString(name), true)
+				} else {
+					_ = iterationEnv.CreateMutableBinding(lang.NewString(name), false)
+				}
+			}
+			r.setLexicalEnvironment(iterationEnv)
+			env = iterationEnv
+		}
+		if err := r.bindingInitialization(target, nextValue, env); err != nil {
+			r.setLexicalEnvironment(oldEnv)
+			return r.throw(closeIterator(err))
+		}
+
+		result := r.evaluateStatement(stmt)
+		r.setLexicalEnvironment(oldEnv)
+		if !loopContinues(result, labelSet) {
+			result = lang.UpdateEmpty(result, v)
+			if result.Type == lang.CompletionThrow {
+				_ = closeIterator(lang.NewThrowError(result.Value))
+				return result
+			}
+			if err := closeIterator(nil); err != nil {
+				return r.throw(err)
+			}
+			return result
+		}
+		if result.Value != nil {
+			v = result.Value
+		}
+		r.step()
+	}
+}
+
+// enumerateObjectProperties returns a function that returns the names of
+// the enumerable string keyed properties of the given object and its
+// prototype chain, one by one. Shadowed properties, and properties that are
+// deleted before they are visited, are skipped. The second return value of
+// the function is true when all properties have been visited.
+// EnumerateObjectProperties is specified in 13.7.5.15.
+func enumerateObjectProperties(obj *lang.Object) func() (lang.Value, bool, errors.Error) {
+	visited := make(map[string]bool)
+	current := obj
+	keys := obj.OwnPropertyKeys()
+	return func() (lang.Value, bool, errors.Error) {
+		for current != nil {
+			for len(keys) > 0 {
+				key := keys[0]
+				keys = keys[1:]
+				if key.Type() == lang.TypeSymbol {
+					continue
+				}
+				name := key.String()
+				if visited[name.String()] {
+					continue
+				}
+				desc := current.GetOwnProperty(key)
+				if desc == nil {
+					continue
+				}
+				visited[name.String()] = true
+				if desc.Enumerable() {
+					return name, false, nil
+				}
+			}
+
+			proto, ok := current.GetPrototypeOf().(*lang.Object)
+			if !ok {
+				current = nil
+				break
+			}
+			current = proto
+			keys = proto.OwnPropertyKeys()
+		}
+		return nil, true, nil
+	}
+}
+
+// evaluateWith evaluates the body of the given with statement in an object
+// environment for the object.
+// The evaluation of WithStatement is specified in 13.11.7.
+func (r *Runtime) evaluateWith(n *ast.WithStatement) lang.Completion {
+	val, err := r.evaluate(n.Object)
+	if err != nil {
+		return r.throw(err)
+	}
+	obj, err := lang.ToObject(r.Realm(), val)
+	if err != nil {
+		return r.throw(err)
+	}
+
+	oldEnv := r.lexicalEnvironment()
+	newEnv := binding.NewObjectEnvironment(oldEnv, obj)
+	newEnv.WithEnvironment = true
+	r.setLexicalEnvironment(newEnv)
+	c := r.evaluateStatement(n.Body)
+	r.setLexicalEnvironment(oldEnv)
+	return lang.UpdateEmpty(c, lang.Undefined)
+}
+
+// evaluateSwitch evaluates the given switch statement. The case clauses
+// share one block environment for their lexically scoped declarations.
+// The evaluation of SwitchStatement is specified in 13.12.11.
+func (r *Runtime) evaluateSwitch(n *ast.SwitchStatement) lang.Completion {
+	switchValue, err := r.evaluate(n.Discriminant)
+	if err != nil {
+		return r.throw(err)
+	}
+
+	var code []ast.Statement
+	for _, c := range n.Cases {
+		code = append(code, c.Consequent...)
+	}
+	oldEnv := r.lexicalEnvironment()
+	blockEnv := binding.NewDeclarativeEnvironment(oldEnv)
+	r.blockDeclarationInstantiation(code, blockEnv)
+	r.setLexicalEnvironment(blockEnv)
+	result := r.caseBlockEvaluation(n.Cases, switchValue)
+	r.setLexicalEnvironment(oldEnv)
+	return result
+}
+
+// caseBlockEvaluation evaluates the given case clauses. The selectors of
+// the clauses are evaluated in source order, and evaluation starts at the
+// first clause whose selector is strictly equal to the input, or at the
+// default clause if there is no such clause. All following clauses are
+// evaluated as well, until one completes abruptly.
+// CaseBlockEvaluation is specified in 13.12.9.
+func (r *Runtime) caseBlockEvaluation(cases []*ast.SwitchCase, input lang.Value) lang.Completion {
+	var v lang.Value = lang.Undefined
+
+	start, defaultIndex := -1, -1
+	for i, c := range cases {
+		if c.Test == nil {
+			defaultIndex = i
+			continue
+		}
+		clauseSelector, err := r.evaluate(c.Test)
+		if err != nil {
+			return r.throw(err)
+		}
+		if lang.StrictEqualityComparison(input, clauseSelector) {
+			start = i
+			break
+		}
+	}
+	if start == -1 {
+		// all selectors were evaluated, so the default clause was seen
+		if defaultIndex == -1 {
+			return lang.NormalCompletion(v)
+		}
+		start = defaultIndex
+	}
+
+	for _, c := range cases[start:] {
+		result := r.evaluateStatementList(c.Consequent)
+		if result.Value != nil {
+			v = result.Value
+		}
+		if result.IsAbrupt() {
+			return lang.UpdateEmpty(result, v)
+		}
+	}
+	return lang.NormalCompletion(v)
+}
+
+// evaluateTry evaluates the given try statement.
+// The evaluation of TryStatement is specified in 13.15.8.
+func (r *Runtime) evaluateTry(n *ast.TryStatement) lang.Completion {
+	result := r.evaluateBlock(n.Block)
+	if n.Handler != nil && result.Type == lang.CompletionThrow {
+		result = r.catchClauseEvaluation(n.Handler, result.Value)
+	}
+
+	if n.Finalizer != nil {
+		f := r.evaluateBlock(n.Finalizer)
+		if f.Type != lang.CompletionNormal {
+			result = f
+		}
+	}
+	return lang.UpdateEmpty(result, lang.Undefined)
+}
+
+// catchClauseEvaluation binds the given thrown value to the parameter of
+// the given catch clause, and evaluates its body.
+// CatchClauseEvaluation is specified in 13.15.7.
+func (r *Runtime) catchClauseEvaluation(n *ast.CatchClause, thrownValue lang.Value) lang.Completion {
+	if n.Param == nil {
+		return r.evaluateBlock(n.Body)
+	}
+
+	oldEnv := r.lexicalEnvironment()
+	catchEnv := binding.NewDeclarativeEnvironment(oldEnv)
+	for _, argName := range boundNames(n.Param) {
+		_ = catchEnv.CreateMutableBinding(lang.NewString(argName), false)
+	}
+	r.setLexicalEnvironment(catchEnv)
+	defer r.setLexicalEnvironment(oldEnv)
+
+	if err := r.bindingInitialization(n.Param, thrownValue, catchEnv); err != nil {
+		return r.throw(err)
+	}
+	return r.evaluateBlock(n.Body)
+}
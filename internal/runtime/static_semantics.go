@@ -0,0 +1,394 @@
+package runtime
+
+import (
+	"github.com/gojisvm/gojis/internal/parser/ast"
+)
+
+// boundNames returns the names of all bindings that are created by the
+// given declaration, binding pattern or formal parameter.
+// BoundNames is specified in 13.3.1.2, 13.3.3.1, 14.1.3 and 14.6.2.
+func boundNames(n ast.Node) []string {
+	switch n := n.(type) {
+	case *ast.Identifier:
+		return []string{n.Name}
+	case *ast.ObjectPattern:
+		var names []string
+		for _, prop := range n.Properties {
+			switch prop := prop.(type) {
+			case *ast.Property:
+				names = append(names, boundNames(prop.Value)...)
+			case *ast.RestElement:
+				names = append(names, boundNames(prop.Argument)...)
+			}
+		}
+		return names
+	case *ast.ArrayPattern:
+		var names []string
+		for _, elem := range n.Elements {
+			if elem != nil {
+				names = append(names, boundNames(elem)...)
+			}
+		}
+		return names
+	case *ast.RestElement:
+		return boundNames(n.Argument)
+	case *ast.AssignmentPattern:
+		return boundNames(n.Left)
+	case *ast.VariableDeclaration:
+		var names []string
+		for _, d := range n.Declarations {
+			names = append(names, boundNames(d.ID)...)
+		}
+		return names
+	case *ast.FunctionDeclaration:
+		if n.ID == nil {
+			return []string{ast.DefaultExportName}
+		}
+		return []string{n.ID.Name}
+	case *ast.ClassDeclaration:
+		if n.ID == nil {
+			return []string{ast.DefaultExportName}
+		}
+		return []string{n.ID.Name}
+	}
+	return nil
+}
+
+// parameterNames returns the bound names of all given formal parameters.
+func parameterNames(params []ast.Pattern) []string {
+	var names []string
+	for _, p := range params {
+		names = append(names, boundNames(p)...)
+	}
+	return names
+}
+
+// declarationNames returns the bound names of all given declarations.
+func declarationNames(decls []ast.Node) []string {
+	var names []string
+	for _, d := range decls {
+		names = append(names, boundNames(d)...)
+	}
+	return names
+}
+
+// isConstantDeclaration is used to determine whether the given declaration
+// is a const declaration.
+// IsConstantDeclaration is specified in 13.3.1.3.
+func isConstantDeclaration(d ast.Node) bool {
+	decl, ok := d.(*ast.VariableDeclaration)
+	return ok && decl.Kind == ast.VariableKindConst
+}
+
+// varScopedDeclarations returns the var declarations of the given
+// statement list, including the ones nested in other statements, but not
+// the ones in nested functions. If topLevel is true, the statement list is
+// the body of a script or function, so that its function declarations are
+// var scoped as well.
+// VarScopedDeclarations and TopLevelVarScopedDeclarations are specified
+// in 13.2.9 and 13.2.11.
+func varScopedDeclarations(list []ast.Statement, topLevel bool) []ast.Node {
+	var decls []ast.Node
+	for _, stmt := range list {
+		decls = appendVarScopedDeclarations(decls, stmt, topLevel)
+	}
+	return decls
+}
+
+func appendVarScopedDeclarations(decls []ast.Node, stmt ast.Node, topLevel bool) []ast.Node {
+	switch n := stmt.(type) {
+	case *ast.VariableDeclaration:
+		if n.Kind == ast.VariableKindVar {
+			decls = append(decls, n)
+		}
+	case *ast.FunctionDeclaration:
+		if topLevel {
+			decls = append(decls, n)
+		}
+	case *ast.LabeledStatement:
+		// a labelled function declaration is treated like a function
+		// declaration
+		decls = appendVarScopedDeclarations(decls, n.Body, topLevel)
+	case *ast.BlockStatement:
+		decls = append(decls, varScopedDeclarations(n.Body, false)...)
+	case *ast.IfStatement:
+		decls = appendVarScopedDeclarations(decls, n.Consequent, false)
+		if n.Alternate != nil {
+			decls = appendVarScopedDeclarations(decls, n.Alternate, false)
+		}
+	case *ast.WhileStatement:
+		decls = appendVarScopedDeclarations(decls, n.Body, false)
+	case *ast.DoWhileStatement:
+		decls = appendVarScopedDeclarations(decls, n.Body, false)
+	case *ast.ForStatement:
+		if n.Init != nil {
+			decls = appendVarScopedDeclarations(decls, n.Init, false)
+		}
+		decls = appendVarScopedDeclarations(decls, n.Body, false)
+	case *ast.ForInStatement:
+		decls = appendVarScopedDeclarations(decls, n.Left, false)
+		decls = appendVarScopedDeclarations(decls, n.Body, false)
+	case *ast.ForOfStatement:
+		decls = appendVarScopedDeclarations(decls, n.Left, false)
+		decls = appendVarScopedDeclarations(decls, n.Body, false)
+	case *ast.WithStatement:
+		decls = appendVarScopedDeclarations(decls, n.Body, false)
+	case *ast.SwitchStatement:
+		for _, c := range n.Cases {
+			decls = append(decls, varScopedDeclarations(c.Consequent, false)...)
+		}
+	case *ast.TryStatement:
+		decls = appendVarScopedDeclarations(decls, n.Block, false)
+		if n.Handler != nil {
+			decls = appendVarScopedDeclarations(decls, n.Handler.Body, false)
+		}
+		if n.Finalizer != nil {
+			decls = appendVarScopedDeclarations(decls, n.Finalizer, false)
+		}
+	}
+	return decls
+}
+
+// lexicallyScopedDeclarations returns the let, const and class
+// declarations of the given statement list. If topLevel is false, the
+// function declarations of the list are lexically scoped as well.
+// LexicallyScopedDeclarations and TopLevelLexicallyScopedDeclarations are
+// specified in 13.2.6 and 13.2.10.
+func lexicallyScopedDeclarations(list []ast.Statement, topLevel bool) []ast.Node {
+	var decls []ast.Node
+	for _, stmt := range list {
+		var item ast.Node = stmt
+		for {
+			labelled, ok := item.(*ast.LabeledStatement)
+			if !ok {
+				break
+			}
+			item = labelled.Body
+		}
+
+		switch n := item.(type) {
+		case *ast.VariableDeclaration:
+			if n.Kind != ast.VariableKindVar {
+				decls = append(decls, n)
+			}
+		case *ast.ClassDeclaration:
+			decls = append(decls, n)
+		case *ast.FunctionDeclaration:
+			if !topLevel {
+				decls = append(decls, n)
+			}
+		}
+	}
+	return decls
+}
+
+// blockFunctionDeclarations returns the function declarations in blocks and
+// case blocks of the given body of non-strict code, which are also bound
+// in the var scope of the code. These are the functions that could be
+// replaced by a var declaration without causing an early error, because no
+// enclosing block declares their name lexically, and whose names are not
+// one of the given excluded names. Generators and async functions are
+// never bound in the var scope.
+// Block-level function declarations are specified in B.3.3.
+func blockFunctionDeclarations(body []ast.Statement, excluded []string) []*ast.FunctionDeclaration {
+	var functions []*ast.FunctionDeclaration
+	var walkList func(list []ast.Statement, outer []string, topLevel bool)
+	var walk func(stmt ast.Node, outer []string)
+
+	walkList = func(list []ast.Statement, outer []string, topLevel bool) {
+		inner := outer[:len(outer):len(outer)]
+		inner = append(inner, declarationNames(lexicallyScopedDeclarations(list, topLevel))...)
+		for _, stmt := range list {
+			if !topLevel {
+				if f, ok := unlabelled(stmt).(*ast.FunctionDeclaration); ok {
+					name := boundNames(f)[0]
+					if !f.Generator && !f.Async && !containsString(outer, name) && !containsString(excluded, name) {
+						functions = append(functions, f)
+					}
+					continue
+				}
+			}
+			walk(stmt, inner)
+		}
+	}
+	walk = func(stmt ast.Node, outer []string) {
+		switch n := stmt.(type) {
+		case *ast.BlockStatement:
+			walkList(n.Body, outer, false)
+		case *ast.LabeledStatement:
+			walk(n.Body, outer)
+		case *ast.IfStatement:
+			walk(ifClause(n.Consequent), outer)
+			if n.Alternate != nil {
+				walk(ifClause(n.Alternate), outer)
+			}
+		case *ast.WhileStatement:
+			walk(n.Body, outer)
+		case *ast.DoWhileStatement:
+			walk(n.Body, outer)
+		case *ast.ForStatement:
+			walk(n.Body, append(outer[:len(outer):len(outer)], lexicalLoopNames(n.Init)...))
+		case *ast.ForInStatement:
+			walk(n.Body, append(outer[:len(outer):len(outer)], lexicalLoopNames(n.Left)...))
+		case *ast.ForOfStatement:
+			walk(n.Body, append(outer[:len(outer):len(outer)], lexicalLoopNames(n.Left)...))
+		case *ast.WithStatement:
+			walk(n.Body, outer)
+		case *ast.SwitchStatement:
+			var code []ast.Statement
+			for _, c := range n.Cases {
+				code = append(code, c.Consequent...)
+			}
+			walkList(code, outer, false)
+		case *ast.TryStatement:
+			walk(n.Block, outer)
+			if n.Handler != nil {
+				walk(n.Handler.Body, outer)
+			}
+			if n.Finalizer != nil {
+				walk(n.Finalizer, outer)
+			}
+		}
+	}
+
+	walkList(body, nil, true)
+	return functions
+}
+
+// ifClause returns the given consequent or alternate of an if statement.
+// A function declaration is wrapped in a block, since it is evaluated as if
+// it were the only statement of a block.
+// FunctionDeclarations in IfStatement Statement Clauses are specified in
+// B.3.4.
+func ifClause(stmt ast.Statement) ast.Statement {
+	if f, ok := stmt.(*ast.FunctionDeclaration); ok {
+		return &ast.BlockStatement{Location: f.Location, Body: []ast.Statement{f}}
+	}
+	return stmt
+}
+
+// lexicalLoopNames returns the names bound by the given let or const
+// declaration in the head of a for loop, or nil if the loop does not
+// declare lexical bindings.
+func lexicalLoopNames(n ast.Node) []string {
+	if decl, ok := n.(*ast.VariableDeclaration); ok && decl.Kind != ast.VariableKindVar {
+		return boundNames(decl)
+	}
+	return nil
+}
+
+// unlabelled returns the statement that is labelled by the given statement,
+// or the statement itself if it is not labelled.
+func unlabelled(stmt ast.Node) ast.Node {
+	for {
+		labelled, ok := stmt.(*ast.LabeledStatement)
+		if !ok {
+			return stmt
+		}
+		stmt = labelled.Body
+	}
+}
+
+// isSimpleParameterList is used to determine whether the given formal
+// parameters are plain identifiers, without patterns, initializers or rest
+// parameters.
+// IsSimpleParameterList is specified in 14.1.13.
+func isSimpleParameterList(params []ast.Pattern) bool {
+	for _, p := range params {
+		if _, ok := p.(*ast.Identifier); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// containsExpression is used to determine whether the given binding
+// patterns contain initializers or computed property names.
+// ContainsExpression is specified in 13.3.3.3 and 14.1.5.
+func containsExpression(params []ast.Pattern) bool {
+	for _, p := range params {
+		if patternContainsExpression(p) {
+			return true
+		}
+	}
+	return false
+}
+
+func patternContainsExpression(p ast.Node) bool {
+	switch n := p.(type) {
+	case *ast.AssignmentPattern:
+		return true
+	case *ast.RestElement:
+		return patternContainsExpression(n.Argument)
+	case *ast.ArrayPattern:
+		for _, elem := range n.Elements {
+			if elem != nil && patternContainsExpression(elem) {
+				return true
+			}
+		}
+	case *ast.ObjectPattern:
+		for _, prop := range n.Properties {
+			switch prop := prop.(type) {
+			case *ast.Property:
+				if prop.Computed || patternContainsExpression(prop.Value) {
+					return true
+				}
+			case *ast.RestElement:
+				if patternContainsExpression(prop.Argument) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// expectedArgumentCount returns the number of formal parameters before the
+// first rest parameter or parameter with an initializer.
+// ExpectedArgumentCount is specified in 14.1.7.
+func expectedArgumentCount(params []ast.Pattern) int {
+	for i, p := range params {
+		switch p.(type) {
+		case *ast.AssignmentPattern, *ast.RestElement:
+			return i
+		}
+	}
+	return len(params)
+}
+
+// isAnonymousFunctionDefinition is used to determine whether the given
+// expression is a function or class expression without name, which gets
+// its name from the context it is defined in.
+// IsAnonymousFunctionDefinition is specified in 14.1.12.
+func isAnonymousFunctionDefinition(expr ast.Node) bool {
+	switch n := expr.(type) {
+	case *ast.FunctionExpression:
+		return n.ID == nil
+	case *ast.ArrowFunctionExpression:
+		return true
+	case *ast.ClassExpression:
+		return n.ID == nil
+	}
+	return false
+}
+
+// constructorMethod returns the constructor method of the given class
+// body, or nil if the class does not define a constructor.
+// ConstructorMethod is specified in 14.6.3.
+func constructorMethod(body *ast.ClassBody) *ast.MethodDefinition {
+	for _, m := range body.Body {
+		if m.Kind == ast.KindConstructor {
+			return m
+		}
+	}
+	return nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
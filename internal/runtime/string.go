@@ -0,0 +1,50 @@
+package runtime
+
+import (
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+)
+
+// stringValue converts the value of a string literal or template to a
+// String. The parser encodes lone surrogates, which cannot be represented
+// in UTF-8, in the generalized UTF-8 form (WTF-8). They are restored as
+// single code units, so that string values are exactly the code units of
+// the source.
+func stringValue(s string) lang.String {
+	str := make(lang.String, 0, len(s))
+	for i := 0; i < len(s); {
+		if s[i] == 0xED && i+2 < len(s) && s[i+1] >= 0xA0 && s[i+1] <= 0xBF {
+			// U+D800 to U+DFFF, a lone surrogate
+			str = append(str, uint16(s[i]&0x0F)<<12|uint16(s[i+1]&0x3F)<<6|uint16(s[i+2]&0x3F))
+			i += 3
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r >= 0x10000 {
+			r1, r2 := utf16.EncodeRune(r)
+			str = append(str, uint16(r1), uint16(r2))
+		} else {
+			str = append(str, uint16(r))
+		}
+		i += size
+	}
+	return str
+}
+
+// concat concatenates the given strings. A RangeError is returned if the
+// result would be longer than the maximum length of strings, or would not
+// fit into the heap of the current realm.
+func (r *Runtime) concat(parts ...lang.String) (lang.String, errors.Error) {
+	n := 0
+	for _, s := range parts {
+		n += len(s)
+	}
+	if err := r.Realm().CheckStringLength(float64(n)); err != nil {
+		return nil, err
+	}
+	return lang.Concat(parts...), nil
+}
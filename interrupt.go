@@ -0,0 +1,126 @@
+package gojis
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gojisvm/gojis/internal/runtime"
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+)
+
+// InterruptedError is the error that is returned if the evaluation of
+// ECMAScript code is interrupted by VM#Interrupt, or because the context
+// of VM#EvalContext is done.
+type InterruptedError struct {
+	reason interface{}
+}
+
+// Reason returns the reason of the interrupt, which is the value passed to
+// VM#Interrupt, or the error of the context passed to VM#EvalContext.
+func (e *InterruptedError) Reason() interface{} {
+	return e.reason
+}
+
+// Unwrap returns the reason of the interrupt, if it is an error, so that
+// errors.Is(err, context.DeadlineExceeded) reports whether the evaluation
+// timed out.
+func (e *InterruptedError) Unwrap() error {
+	err, _ := e.reason.(error)
+	return err
+}
+
+func (e *InterruptedError) Error() string {
+	if e.reason == nil {
+		return "Evaluation interrupted"
+	}
+	return fmt.Sprintf("Evaluation interrupted: %v", e.reason)
+}
+
+// Interrupt stops the evaluation of ECMAScript code with the given reason.
+// The evaluation is stopped when it reaches the next loop iteration or
+// function call, or while a built-in function like RegExp.prototype.exec
+// or Array.prototype.join does its work. Catch and finally blocks are not
+// evaluated, and the evaluation returns an *InterruptedError with the
+// given reason. If the VM does not evaluate code when Interrupt is called,
+// the next evaluation is interrupted. Interrupt may be called from any
+// goroutine, and the VM can be used again once the interrupted evaluation
+// has returned.
+func (vm *VM) Interrupt(reason interface{}) {
+	vm.runtime.Interrupt(reason)
+}
+
+// EvalContext evaluates the given ECMAScript code like Eval, but
+// interrupts the evaluation once the given context is done. In that case,
+// an *InterruptedError is returned, whose reason is the error of the
+// context.
+func (vm *VM) EvalContext(ctx context.Context, script string) (Object, error) {
+	if err := ctx.Err(); err != nil {
+		return Undefined, &InterruptedError{err}
+	}
+	if ctx.Done() == nil {
+		return vm.Eval(script)
+	}
+
+	stop := make(chan struct{})
+	interrupted := make(chan bool)
+	go func() {
+		select {
+		case <-ctx.Done():
+			vm.Interrupt(ctx.Err())
+			interrupted <- true
+		case <-stop:
+			interrupted <- false
+		}
+	}()
+
+	result, err := vm.Eval(script)
+	close(stop)
+	if <-interrupted && vm.running == 0 {
+		// the context may be done after the evaluation has returned
+		vm.runtime.ClearInterrupt()
+	}
+	return result, err
+}
+
+// evaluate calls the given function, which evaluates ECMAScript code, and
+// returns its error. If the evaluation is interrupted, an
+// *InterruptedError is returned instead, if it exceeds the budget, a
+// *BudgetExceededError, and if it exceeds the heap limit, a
+// *HeapExceededError. Evaluations may be nested, if host functions
+// evaluate code. The outermost evaluation runs the pending promise jobs
+// after fn returns. The interrupt is cleared once the outermost evaluation
+// returns, so that the VM can be used again.
+func (vm *VM) evaluate(fn func() error) (err error) {
+	vm.running++
+	defer func() {
+		vm.running--
+		if r := recover(); r != nil {
+			switch r := r.(type) {
+			case *runtime.Interrupted:
+				if vm.running == 0 {
+					vm.runtime.ClearInterrupt()
+				}
+				err = &InterruptedError{r.Reason}
+			case *runtime.BudgetExceeded:
+				err = &BudgetExceededError{r.Steps}
+			case *lang.HeapExceeded:
+				err = &HeapExceededError{uint64(r.Used), uint64(r.Limit)}
+			default:
+				panic(r)
+			}
+		}
+	}()
+
+	if vm.running > 1 {
+		return fn()
+	}
+	// an interrupt that was requested while no code was evaluated, or
+	// after the code has been evaluated, interrupts this evaluation
+	vm.runtime.CheckInterrupt()
+	err = fn()
+	// the promise jobs that were enqueued by the evaluation are run before
+	// the control returns to the host
+	vm.runtime.RunJobs()
+	vm.runtime.CheckInterrupt()
+	return err
+}
@@ -0,0 +1,157 @@
+package gojis_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gojisvm/gojis"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterrupt(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{"while", `while (true) {}`},
+		{"do while", `do {} while (true);`},
+		{"for", `for (var i = 0; ; i++) {}`},
+		{"for in", `var o = {a: 1}; for (;;) { for (var k in o) {} }`},
+		{"recursion", `function f() { return f() + 1; } f();`},
+		{"catch and finally", `
+try {
+	while (true) {}
+} catch (e) {
+	done = true;
+} finally {
+	done = true;
+}
+		`},
+		{"host function", `run(() => { while (true) {} });`},
+		{"regexp backtracking", `/(a+)+$/.test("a".repeat(40) + "b");`},
+		{"array builtin", `Array.prototype.indexOf.call({length: 2 ** 53 - 1}, 1);`},
+		{"string builtin", `"a".repeat(1e6).indexOf("a".repeat(5e5) + "b");`},
+		{"apply", `(function() {}).apply(null, {length: 2 ** 20});`},
+		{"string padding", `"a".padStart(5e8);`},
+		{"string repeat", `"ab".repeat(2.5e8);`},
+	}
+	for _, e := range engines {
+		for _, tt := range tests {
+			t.Run(e.name+"/"+tt.name, func(t *testing.T) {
+				require := require.New(t)
+
+				vm := gojis.NewVM()
+				vm.SetEngine(e.engine)
+				vm.SetObject("done", vm.ToValue(false))
+				vm.SetFunctionWithError("run", func(args gojis.Args) (gojis.Object, error) {
+					return args.Func(0).CallWithArgs()
+				})
+
+				timer := time.AfterFunc(10*time.Millisecond, func() {
+					vm.Interrupt("stop")
+				})
+				defer timer.Stop()
+
+				result, err := vm.Eval(tt.src)
+				require.Equal(gojis.Undefined, result)
+				require.IsType(&gojis.InterruptedError{}, err)
+				require.Equal("stop", err.(*gojis.InterruptedError).Reason())
+				require.Equal("Evaluation interrupted: stop", err.Error())
+				require.Equal(false, vm.Lookup("done").Value())
+
+				// the VM remains usable after the interrupt
+				result, err = vm.Eval(`[1, 2, 3].map(x => x * 2).join()`)
+				require.NoError(err)
+				require.Equal("2,4,6", result.Value())
+			})
+		}
+	}
+}
+
+func TestInterruptBeforeEval(t *testing.T) {
+	require := require.New(t)
+
+	vm := gojis.NewVM()
+	vm.Interrupt(nil)
+	_, err := vm.Eval(`1`)
+	require.IsType(&gojis.InterruptedError{}, err)
+	require.Nil(err.(*gojis.InterruptedError).Reason())
+	require.Equal("Evaluation interrupted", err.Error())
+
+	result, err := vm.Eval(`1`)
+	require.NoError(err)
+	require.Equal(1.0, result.Value())
+}
+
+func TestInterruptCallWithArgs(t *testing.T) {
+	require := require.New(t)
+
+	vm := gojis.NewVM()
+	loop, err := vm.Eval(`(function() { while (true) {} })`)
+	require.NoError(err)
+
+	reason := errors.New("shutdown")
+	timer := time.AfterFunc(10*time.Millisecond, func() {
+		vm.Interrupt(reason)
+	})
+	defer timer.Stop()
+
+	_, err = loop.CallWithArgs()
+	require.True(errors.Is(err, reason))
+
+	var export func() error
+	require.NoError(loop.Export(&export))
+	vm.Interrupt(reason)
+	require.True(errors.Is(export(), reason))
+}
+
+func TestInterruptRun(t *testing.T) {
+	require := require.New(t)
+
+	prog, err := gojis.Compile("loop.js", `while (true) {}`)
+	require.NoError(err)
+
+	vm := gojis.NewVM()
+	timer := time.AfterFunc(10*time.Millisecond, func() {
+		vm.Interrupt("stop")
+	})
+	defer timer.Stop()
+
+	_, err = vm.Run(prog)
+	require.IsType(&gojis.InterruptedError{}, err)
+	require.Equal("stop", err.(*gojis.InterruptedError).Reason())
+}
+
+func TestEvalContext(t *testing.T) {
+	for _, e := range engines {
+		t.Run(e.name, func(t *testing.T) {
+			require := require.New(t)
+
+			vm := gojis.NewVM()
+			vm.SetEngine(e.engine)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			defer cancel()
+			_, err := vm.EvalContext(ctx, `for (;;) {}`)
+			require.IsType(&gojis.InterruptedError{}, err)
+			require.True(errors.Is(err, context.DeadlineExceeded))
+
+			// the done context interrupts before the code is evaluated
+			_, err = vm.EvalContext(ctx, `x = 1`)
+			require.True(errors.Is(err, context.DeadlineExceeded))
+			require.True(vm.Lookup("x").IsUndefined())
+
+			ctx, cancel = context.WithCancel(context.Background())
+			defer cancel()
+			result, err := vm.EvalContext(ctx, `6 * 7`)
+			require.NoError(err)
+			require.Equal(42.0, result.Value())
+
+			result, err = vm.EvalContext(context.Background(), `"done"`)
+			require.NoError(err)
+			require.Equal("done", result.Value())
+		})
+	}
+}
@@ -10,12 +10,25 @@ type Object interface {
 	Lookup(string) Object
 	// SetFunction adds a property (more specific, a function object) to this
 	// object. When the function object is invoked, the given function will be
-	// executed, with all parameters wrapped into the Args object.
+	// executed, with all parameters wrapped into the Args object. If the function
+	// returns nil, the function object returns Undefined. Exceptions that are
+	// thrown by the typed accessors and Throw helpers of Args are thrown into
+	// the calling script.
 	SetFunction(string, func(Args) Object)
+	// SetFunctionWithError adds a function object like SetFunction does, whose
+	// function can return an error, which is thrown into the calling script.
+	// An *Error is thrown as error object of its kind, like a TypeError, an
+	// *Exception as the value that it holds, and all other errors as Error
+	// objects with the message of the error. If the function panics, the
+	// panic is recovered, and the panic value is thrown like a returned error
+	// if it is an error, or as an Error object with the formatted value as
+	// message otherwise. The same holds for panics of SetFunction functions.
+	SetFunctionWithError(string, func(Args) (Object, error))
 	// CallWithArgs attempts to invoke this objects 'Call' property. Note that this
 	// property is only set if this object is a function or constructor object. If
 	// this object does not have a 'Call' property that is callable, an error will
-	// be returned.
+	// be returned. If the function throws an exception, an *Exception is
+	// returned.
 	CallWithArgs(...interface{}) (Object, error)
 	// SetBbject adds a property with the given name to this object. The property's
 	// value will be the given object.
@@ -27,7 +40,8 @@ type Object interface {
 	// IsNull is used to determine whether this object represents the Null value.
 	IsNull() bool
 	// IsFunction is used to determine whether this object can be invoked. If this
-	// returns true, CallWithArgs will not return an error.
+	// returns true, CallWithArgs will only return an error if the function
+	// throws an exception.
 	IsFunction() bool
 
 	// Type returns the ECMAScript language type of this object.
@@ -35,4 +49,45 @@ type Object interface {
 	// Value returns the Go value corresponding to the ECMAScript language value of
 	// this object.
 	Value() interface{}
+	// Export stores the Go value of this object in the value that target
+	// points to, converting it to the type of that value. Target must be a
+	// non-nil pointer. The value is converted as follows.
+	//
+	//   - Undefined and Null become the zero value of the target type.
+	//   - An Object target receives this object.
+	//   - Booleans and strings are converted as by ToBoolean and ToString.
+	//   - Integer targets require an integral Number that fits into the
+	//     target type, an error is returned otherwise. Floating point
+	//     targets receive the Number converted as by ToNumber.
+	//   - A time.Time target requires a Date object or a Number, which is
+	//     the time value in milliseconds. The time is in UTC.
+	//   - Slice and array targets require an array-like object, whose
+	//     elements are exported into the elements of the slice or array.
+	//     Elements that don't fit into an array are ignored.
+	//   - Map targets receive the enumerable own properties of an object.
+	//     The property names are exported into the key type like strings,
+	//     so integer keys are parsed from the names.
+	//   - Struct targets receive the properties named like their exported
+	//     fields, as described by VM#ToValue. Fields whose property is
+	//     Undefined are left unchanged.
+	//   - Pointer targets receive a pointer to a new value, into which this
+	//     object is exported.
+	//   - Function targets require a function object, and receive a Go
+	//     function that calls it with the arguments converted as by
+	//     VM#ToValue, and exports the result into the results of the
+	//     function. If there is more than one result, the elements of the
+	//     returned array are exported. If the function returns an error as
+	//     its last result, a thrown exception is returned as *Exception and
+	//     a failed conversion as error, otherwise the function panics with
+	//     them.
+	//   - An empty interface target receives nil for Undefined and Null, a
+	//     bool, float64 or string for primitive values, an Object for
+	//     Symbols, a time.Time for Date objects, a []interface{} for arrays,
+	//     a func(...interface{}) (interface{}, error) for functions, and a
+	//     map[string]interface{} for all other objects.
+	//
+	// An object that is reached more than once while exporting is exported
+	// into the same Go value, if that is a pointer, slice or map of the same
+	// type, so that cyclic objects can be exported into cyclic Go values.
+	Export(target interface{}) error
 }
@@ -1,5 +1,11 @@
 package gojis
 
+import (
+	"fmt"
+
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+)
+
 const (
 	// Null represents the Null ECMAScript language value.
 	Null = null(0)
@@ -7,10 +13,11 @@ const (
 
 type null uint8
 
-func (u null) Lookup(objName string) Object                  { return Undefined }
-func (u null) SetFunction(name string, fn func(Args) Object) { /* no-op */ }
+func (u null) Lookup(objName string) Object                                    { return Undefined }
+func (u null) SetFunction(name string, fn func(Args) Object)                   { /* no-op */ }
+func (u null) SetFunctionWithError(name string, fn func(Args) (Object, error)) { /* no-op */ }
 func (u null) CallWithArgs(args ...interface{}) (Object, error) {
-	panic("TODO: return API error 'not callable'")
+	return Undefined, fmt.Errorf("Object is not a function")
 }
 func (u null) SetObject(name string, obj Object) { /* no-op */ }
 func (u null) IsUndefined() bool                 { return false }
@@ -18,3 +25,6 @@ func (u null) IsNull() bool                      { return true }
 func (u null) IsFunction() bool                  { return false }
 func (u null) Type() Type                        { return TypeNull }
 func (u null) Value() interface{}                { return nil }
+func (u null) Export(target interface{}) error {
+	return (*VM)(nil).export(lang.Null, target)
+}
@@ -20,12 +20,25 @@ func TestNull(t *testing.T) {
 		Null.SetFunction("nothing", func(Args) Object { return Undefined })
 	})
 
+	t.Run("SetFunctionWithError", func(t *testing.T) {
+		// this should just not panic
+		Null.SetFunctionWithError("some_func", func(Args) (Object, error) { return Undefined, nil })
+	})
+
 	t.Run("SetObject", func(t *testing.T) {
 		// this should just not panic
 		Null.SetObject("some_obj", Undefined)
 		Null.SetObject("nothing", Undefined)
 	})
 
+	t.Run("CallWithArgs", func(t *testing.T) {
+		require := require.New(t)
+
+		result, err := Null.CallWithArgs(1, "foo")
+		require.EqualError(err, "Object is not a function")
+		require.Equal(Undefined, result)
+	})
+
 	t.Run("IsXXX", func(t *testing.T) {
 		require := require.New(t)
 
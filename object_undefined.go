@@ -1,5 +1,11 @@
 package gojis
 
+import (
+	"fmt"
+
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+)
+
 const (
 	// Undefined represents the Undefined ECMAScript language value.
 	Undefined = undefined(0)
@@ -7,10 +13,11 @@ const (
 
 type undefined uint8
 
-func (u undefined) Lookup(objName string) Object                  { return Undefined }
-func (u undefined) SetFunction(name string, fn func(Args) Object) { /* no-op */ }
+func (u undefined) Lookup(objName string) Object                                    { return Undefined }
+func (u undefined) SetFunction(name string, fn func(Args) Object)                   { /* no-op */ }
+func (u undefined) SetFunctionWithError(name string, fn func(Args) (Object, error)) { /* no-op */ }
 func (u undefined) CallWithArgs(args ...interface{}) (Object, error) {
-	panic("TODO: return API error not callable")
+	return Undefined, fmt.Errorf("Object is not a function")
 }
 func (u undefined) SetObject(name string, obj Object) { /* no-op */ }
 func (u undefined) IsUndefined() bool                 { return true }
@@ -18,3 +25,6 @@ func (u undefined) IsNull() bool                      { return false }
 func (u undefined) IsFunction() bool                  { return false }
 func (u undefined) Type() Type                        { return TypeUndefined }
 func (u undefined) Value() interface{}                { return nil }
+func (u undefined) Export(target interface{}) error {
+	return (*VM)(nil).export(lang.Undefined, target)
+}
@@ -20,12 +20,25 @@ func TestUndefined(t *testing.T) {
 		Undefined.SetFunction("nothing", func(Args) Object { return Null })
 	})
 
+	t.Run("SetFunctionWithError", func(t *testing.T) {
+		// this should just not panic
+		Undefined.SetFunctionWithError("some_func", func(Args) (Object, error) { return Undefined, nil })
+	})
+
 	t.Run("SetObject", func(t *testing.T) {
 		// this should just not panic
 		Undefined.SetObject("some_obj", Null)
 		Undefined.SetObject("nothing", Null)
 	})
 
+	t.Run("CallWithArgs", func(t *testing.T) {
+		require := require.New(t)
+
+		result, err := Undefined.CallWithArgs(1, "foo")
+		require.EqualError(err, "Object is not a function")
+		require.Equal(Undefined, result)
+	})
+
 	t.Run("IsXXX", func(t *testing.T) {
 		require := require.New(t)
 
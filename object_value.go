@@ -0,0 +1,166 @@
+package gojis
+
+import (
+	"fmt"
+
+	"github.com/gojisvm/gojis/internal/runtime/errors"
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+	"github.com/gojisvm/gojis/internal/runtime/realm"
+)
+
+// object is an Object that wraps an ECMAScript language value of a VM,
+// other than Null and Undefined.
+type object struct {
+	vm    *VM
+	value lang.Value
+}
+
+// wrap returns an Object representing the given language value. Null and
+// Undefined are represented by the Null and Undefined constants.
+func (vm *VM) wrap(v lang.Value) Object {
+	switch v {
+	case nil, lang.Undefined:
+		return Undefined
+	case lang.Null:
+		return Null
+	}
+	return &object{vm: vm, value: v}
+}
+
+// unwrap returns the language value that the given Object represents. A
+// nil Object represents Undefined.
+func (vm *VM) unwrap(o Object) lang.Value {
+	switch o := o.(type) {
+	case nil:
+		return lang.Undefined
+	case *object:
+		return o.value
+	}
+	if o.IsNull() {
+		return lang.Null
+	}
+	return lang.Undefined
+}
+
+func (o *object) realm() *realm.Realm {
+	return o.vm.runtime.Realm()
+}
+
+func (o *object) Lookup(name string) Object {
+	var v lang.Value
+	err := o.vm.evaluate(func() error {
+		var thrown errors.Error
+		v, thrown = lang.GetV(o.realm(), o.value, lang.NewStringKey(name))
+		if thrown != nil {
+			return thrown
+		}
+		return nil
+	})
+	if err != nil {
+		return Undefined
+	}
+	return o.vm.wrap(v)
+}
+
+func (o *object) SetFunction(name string, fn func(Args) Object) {
+	o.SetFunctionWithError(name, func(args Args) (Object, error) {
+		return fn(args), nil
+	})
+}
+
+func (o *object) SetFunctionWithError(name string, fn func(Args) (Object, error)) {
+	obj, ok := o.value.(*lang.Object)
+	if !ok {
+		return
+	}
+
+	f := o.realm().NewFunction(name, 0, func(this lang.Value, args ...lang.Value) (result lang.Value, err errors.Error) {
+		defer o.vm.recoverHostPanic(&err)
+
+		wrapped := make([]Object, len(args))
+		for i, arg := range args {
+			wrapped[i] = o.vm.wrap(arg)
+		}
+		value, goErr := fn(Args{o: wrapped, name: name, this: o.vm.wrap(this)})
+		if goErr != nil {
+			return nil, o.vm.throwError(goErr)
+		}
+		return o.vm.unwrap(value), nil
+	})
+	_, _ = lang.CreateDataProperty(obj, lang.NewStringKey(name), f)
+}
+
+func (o *object) CallWithArgs(args ...interface{}) (Object, error) {
+	if !o.IsFunction() {
+		return Undefined, fmt.Errorf("Object is not a function")
+	}
+
+	values := make([]lang.Value, len(args))
+	for i, arg := range args {
+		v, err := o.vm.toValue(arg)
+		if err != nil {
+			return Undefined, err
+		}
+		values[i] = v
+	}
+
+	var result lang.Value
+	err := o.vm.evaluate(func() error {
+		var thrown errors.Error
+		result, thrown = lang.Call(o.value.(*lang.Object), lang.Undefined, values...)
+		if thrown != nil {
+			return o.vm.exception(thrown)
+		}
+		return nil
+	})
+	if err != nil {
+		return Undefined, err
+	}
+	return o.vm.wrap(result), nil
+}
+
+func (o *object) SetObject(name string, obj Object) {
+	if target, ok := o.value.(*lang.Object); ok {
+		_, _ = lang.CreateDataProperty(target, lang.NewStringKey(name), o.vm.unwrap(obj))
+	}
+}
+
+func (o *object) IsUndefined() bool { return false }
+func (o *object) IsNull() bool      { return false }
+func (o *object) IsFunction() bool  { return lang.InternalIsCallable(o.value) }
+
+func (o *object) Type() Type {
+	switch o.value.Type() {
+	case lang.TypeBoolean:
+		return TypeBoolean
+	case lang.TypeString:
+		return TypeString
+	case lang.TypeNumber:
+		return TypeNumber
+	case lang.TypeSymbol:
+		return TypeSymbol
+	case lang.TypeObject:
+		return TypeObject
+	}
+	return TypeUnknown
+}
+
+// Value returns a bool for booleans, a string for strings and symbol
+// descriptions, a float64 for numbers, and the Object itself for objects.
+func (o *object) Value() interface{} {
+	switch v := o.value.(type) {
+	case lang.Boolean:
+		return bool(v)
+	case lang.Number:
+		return v.Float64()
+	case *lang.Object:
+		return o
+	}
+	return o.value.Value()
+}
+
+func (o *object) Export(target interface{}) error {
+	return o.vm.evaluate(func() error {
+		return o.vm.export(o.value, target)
+	})
+}
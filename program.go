@@ -0,0 +1,37 @@
+package gojis
+
+import (
+	"github.com/gojisvm/gojis/internal/parser"
+	"github.com/gojisvm/gojis/internal/runtime"
+)
+
+// Program is a script that has been parsed and compiled by Compile.
+// A Program is immutable, so it is safe to share it between goroutines,
+// and it can be run by any number of VMs with VM#Run, without parsing it
+// again.
+type Program struct {
+	name   string
+	script *runtime.Script
+}
+
+// Compile parses and compiles the given ECMAScript code as a script.
+// The given name is used to identify the script in error messages. If
+// the code contains syntax errors, a *SyntaxError holding all of them is
+// returned.
+func Compile(name, src string) (*Program, error) {
+	p := parser.New()
+	if err := p.ParseString(name, src); err != nil {
+		return nil, syntaxError(err)
+	}
+	root, _ := p.Ast().Root(name)
+
+	prog := new(Program)
+	prog.name = name
+	prog.script = runtime.CompileScript(name, root)
+	return prog, nil
+}
+
+// Name returns the name that the program was compiled with.
+func (p *Program) Name() string {
+	return p.name
+}
@@ -0,0 +1,70 @@
+package gojis_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/gojisvm/gojis"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompile(t *testing.T) {
+	require := require.New(t)
+
+	prog, err := gojis.Compile("rule.js", `
+var count = (typeof count === "undefined" ? 0 : count) + 1;
+function square(x) { return x * x; }
+square(count);
+	`)
+	require.NoError(err)
+	require.Equal("rule.js", prog.Name())
+
+	vm := gojis.NewVM()
+	for _, expected := range []float64{1, 4, 9} {
+		result, err := vm.Run(prog)
+		require.NoError(err)
+		require.Equal(expected, result.Value())
+	}
+
+	// another VM does not share any state with the first one
+	result, err := gojis.NewVM().Run(prog)
+	require.NoError(err)
+	require.Equal(1.0, result.Value())
+}
+
+func TestCompileSyntaxError(t *testing.T) {
+	require := require.New(t)
+
+	prog, err := gojis.Compile("broken.js", `var = 1;`)
+	require.Nil(prog)
+	require.IsType(&gojis.SyntaxError{}, err)
+	require.Equal("broken.js", err.(*gojis.SyntaxError).File())
+}
+
+func TestCompileConcurrent(t *testing.T) {
+	prog, err := gojis.Compile("loop.js", `
+let sum = 0;
+for (let i = 0; i < 100; i++) {
+	sum += (x => x * 2)(i);
+}
+sum;
+	`)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 10; j++ {
+				// the program declares a let binding, which cannot be
+				// redeclared in the same VM, so every run needs a new VM
+				result, err := gojis.NewVM().Run(prog)
+				require.NoError(t, err)
+				require.Equal(t, 9900.0, result.Value())
+			}
+		}()
+	}
+	wg.Wait()
+}
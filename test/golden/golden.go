@@ -18,12 +18,6 @@ var (
 	update = flag.Bool("update", false, "Update golden test files.")
 )
 
-func init() {
-	if !flag.Parsed() {
-		flag.Parse()
-	}
-}
-
 // Equal asserts that the actual bytes are exactly the same as the bytes in the golden
 // file with the given name.
 //
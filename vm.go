@@ -1,6 +1,16 @@
 package gojis
 
-import "io"
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+
+	"github.com/gojisvm/gojis/internal/parser"
+	"github.com/gojisvm/gojis/internal/runtime"
+	"github.com/gojisvm/gojis/internal/runtime/lang"
+	"github.com/rs/zerolog"
+)
 
 // Type represents the ECMAScript language types.
 type Type uint8
@@ -13,24 +23,146 @@ const (
 	TypeUndefined
 	TypeNull
 	TypeString
+	TypeBoolean
+	TypeNumber
+	TypeSymbol
+	TypeObject
+)
+
+// Engine determines how a VM executes ECMAScript code.
+type Engine uint8
+
+// Available engines. EngineBytecode is the default engine of a VM.
+const (
+	// EngineBytecode compiles scripts and functions to bytecode, which is
+	// then executed by an interpreter.
+	EngineBytecode Engine = iota
+	// EngineTreeWalking evaluates scripts and functions directly on
+	// their syntax tree.
+	EngineTreeWalking
 )
 
 // VM represents an instance of the GojisVM.
 // It can be used to evaluate ECMAScript code.
 type VM struct {
 	Object // the global object
+
+	runtime *runtime.Runtime
+
+	console   io.Writer
+	evalCount int
+	// running is the number of evaluations of ECMAScript code that are
+	// currently running. It is greater than one, if host functions
+	// evaluate code.
+	running int
+
+	// hostObjects holds the host objects of the VM by the struct they are
+	// bound to, until they are released. hostChildren holds, for each of
+	// them, the structs that were reached through its fields, which are
+	// released with it. hostTypes holds the types of the structs, and
+	// hostMethods the internal methods of the host objects.
+	hostObjects  map[seenKey]*lang.Object
+	hostChildren map[seenKey]map[seenKey]bool
+	hostTypes    map[reflect.Type]*hostType
+	hostMethods  *lang.ExoticMethods
 }
 
 // NewVM creates a new, initialized VM that is ready to use.
-func NewVM() *VM { panic("TODO") }
+// The console of the VM writes to os.Stdout.
+func NewVM() *VM {
+	vm := new(VM)
+	vm.runtime = runtime.New(zerolog.Nop(), parser.NewEmptyAst())
+	vm.console = os.Stdout
+	vm.hostObjects = make(map[seenKey]*lang.Object)
+	vm.hostChildren = make(map[seenKey]map[seenKey]bool)
+	vm.hostTypes = make(map[reflect.Type]*hostType)
+	vm.hostMethods = newHostObjectMethods()
+	vm.Object = vm.wrap(vm.runtime.Realm().GlobalObject())
+	vm.SetObject("console", vm.newConsole())
+	return vm
+}
 
-// Eval evaluates the given ECMAScript code, and returns an Object, representing the
-// result of the evaluation. The result may be Null or Undefined.
-//
-// Internally, this function directly delegates to the method 'eval' that is specified
-// by the ECMAScript language specification.
-func (vm *VM) Eval(script string) Object { return vm.Lookup("eval") }
+// Eval evaluates the given ECMAScript code as a script, and returns an Object,
+// representing the result of the evaluation. The result may be Null or
+// Undefined. Promise reactions that are triggered by the evaluation are run
+// before Eval returns. If the code contains syntax errors, a *SyntaxError is
+// returned.
+// If the evaluation throws an exception that is not caught, an *Exception is
+// returned. If the evaluation is interrupted, an *InterruptedError is
+// returned, if it exceeds the budget, a *BudgetExceededError, and if it
+// exceeds the heap limit, a *HeapExceededError.
+func (vm *VM) Eval(script string) (Object, error) {
+	vm.evalCount++
+	name := fmt.Sprintf("<eval-%d>", vm.evalCount)
+	// the script is parsed by its own parser, so that it is released once
+	// it has been evaluated and its functions are no longer reachable
+	p := parser.New()
+	if err := p.ParseString(name, script); err != nil {
+		return Undefined, syntaxError(err)
+	}
+	program, _ := p.Ast().Root(name)
+
+	var result lang.Completion
+	err := vm.evaluate(func() error {
+		result = vm.runtime.EvaluateProgram(name, program)
+		return nil
+	})
+	if err != nil {
+		return Undefined, err
+	}
+	return vm.completion(result)
+}
+
+// completion returns the result of an evaluation with the given completion,
+// or an *Exception if the completion is a throw completion.
+func (vm *VM) completion(result lang.Completion) (Object, error) {
+	if result.Type == lang.CompletionThrow {
+		return Undefined, vm.newException(result.Value)
+	}
+	return vm.wrap(result.Value), nil
+}
 
 // SetConsole is used to change the console of the VM.
 // Calls like 'console.log' will be written to the given writer.
-func (vm *VM) SetConsole(w io.Writer) { panic("TODO") }
+func (vm *VM) SetConsole(w io.Writer) {
+	vm.console = w
+}
+
+// SetEngine is used to change the engine that executes ECMAScript code.
+// Both engines produce the same results, the tree-walking engine is
+// mainly useful to check the bytecode engine against.
+func (vm *VM) SetEngine(engine Engine) {
+	switch engine {
+	case EngineTreeWalking:
+		vm.runtime.SetEngine(runtime.EngineTreeWalking)
+	default:
+		vm.runtime.SetEngine(runtime.EngineBytecode)
+	}
+}
+
+// SetMaxCallDepth sets the maximum depth of the call stack, which counts
+// the calls of ECMAScript functions and the evaluated scripts. A call that
+// exceeds the depth throws a RangeError, which can be caught by the script.
+// The default depth is 10000. The depth is limited to 25000, so that deep
+// recursion cannot overflow the stack of the goroutine that evaluates the
+// code. The tree-walking engine also limits the number of nested
+// expressions and statements that it evaluates across all calls, and
+// throws the same RangeError when they exceed the limit.
+func (vm *VM) SetMaxCallDepth(depth int) {
+	vm.runtime.Agent().ExecutionContextStack.SetMaxDepth(depth)
+}
+
+// Run runs the given program, and returns an Object, representing the
+// result of the evaluation, just like Eval does. The program may be
+// run any number of times, and by any number of VMs.
+func (vm *VM) Run(prog *Program) (Object, error) {
+	var result lang.Completion
+	err := vm.evaluate(func() error {
+		result = vm.runtime.EvaluateScript(prog.script)
+		return nil
+	})
+	if err != nil {
+		return Undefined, err
+	}
+	return vm.completion(result)
+}
@@ -2,17 +2,17 @@ package gojis_test
 
 import (
 	"bytes"
+	"fmt"
+	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/gojisvm/gojis"
 	"github.com/gojisvm/gojis/test/golden"
+	"github.com/stretchr/testify/require"
 )
 
 func TestHelloWorld(t *testing.T) {
-	if testing.Short() {
-		t.SkipNow() // skipped until API is implemented
-	}
-
 	vm := gojis.NewVM()
 
 	var buf bytes.Buffer
@@ -23,12 +23,26 @@ func TestHelloWorld(t *testing.T) {
 	golden.Equal(t, "TestHelloWorld", buf.Bytes())
 }
 
+// engines holds the engines that the evaluation tests are run with.
+var engines = []struct {
+	name   string
+	engine gojis.Engine
+}{
+	{"bytecode", gojis.EngineBytecode},
+	{"tree-walking", gojis.EngineTreeWalking},
+}
+
 func TestInlineObject(t *testing.T) {
-	if testing.Short() {
-		t.SkipNow()
+	for _, e := range engines {
+		t.Run(e.name, func(t *testing.T) {
+			testInlineObject(t, e.engine)
+		})
 	}
+}
 
+func testInlineObject(t *testing.T, engine gojis.Engine) {
 	vm := gojis.NewVM()
+	vm.SetEngine(engine)
 
 	var buf bytes.Buffer
 	vm.SetConsole(&buf)
@@ -47,3 +61,309 @@ foo(gen({func: () => "Output"})());
 
 	golden.Equal(t, "TestInlineObject", buf.Bytes())
 }
+
+func TestEvalSyntaxError(t *testing.T) {
+	require := require.New(t)
+
+	vm := gojis.NewVM()
+	result, err := vm.Eval(`var = 1;`)
+	require.Equal(gojis.Undefined, result)
+	require.IsType(&gojis.SyntaxError{}, err)
+	syntaxErr := err.(*gojis.SyntaxError)
+	require.Equal("<eval-1>", syntaxErr.File())
+	require.Len(syntaxErr.Errors(), 1)
+}
+
+func TestEvalException(t *testing.T) {
+	tests := []struct {
+		name      string
+		src       string
+		message   string
+		stack     string
+		valueType gojis.Type
+	}{
+		{"runtime error", `
+function inner() { return null.foo; }
+function outer() { return inner(); }
+outer();
+		`, "TypeError: Cannot read properties of null (reading 'foo')", "" +
+			"    at inner (<eval-1>:2:20)\n" +
+			"    at outer (<eval-1>:3:27)\n" +
+			"    at <eval-1>:4:1", gojis.TypeObject},
+		{"thrown string", `
+var f = function() { throw "error"; };
+f();
+		`, "error", "" +
+			"    at f (<eval-1>:2:22)\n" +
+			"    at <eval-1>:3:1", gojis.TypeString},
+		{"thrown error object", `
+try {
+	throw { name: "MyError", message: "boom" };
+} catch (e) {
+	(() => { throw e; })();
+}
+		`, "MyError: boom", "    at <eval-1>:3:2", gojis.TypeObject},
+	}
+	for _, e := range engines {
+		for _, tt := range tests {
+			t.Run(e.name+"/"+tt.name, func(t *testing.T) {
+				require := require.New(t)
+
+				vm := gojis.NewVM()
+				vm.SetEngine(e.engine)
+				result, err := vm.Eval(tt.src)
+				require.Equal(gojis.Undefined, result)
+				require.IsType(&gojis.Exception{}, err)
+				exception := err.(*gojis.Exception)
+				require.Equal(tt.message, exception.Message())
+				require.Equal(tt.message, exception.Error())
+				require.Equal(tt.stack, exception.Stack())
+				require.Equal(tt.valueType, exception.Value().Type())
+			})
+		}
+	}
+}
+
+func TestEvalExceptionWithoutMessage(t *testing.T) {
+	for _, src := range []string{`throw { a: 1 }`, `throw ""`} {
+		t.Run(src, func(t *testing.T) {
+			require := require.New(t)
+
+			vm := gojis.NewVM()
+			_, err := vm.Eval(src)
+			require.IsType(&gojis.Exception{}, err)
+			require.Equal("", err.(*gojis.Exception).Message())
+			require.EqualError(err, "Uncaught exception")
+		})
+	}
+}
+
+func TestExceptionFrames(t *testing.T) {
+	for _, e := range engines {
+		t.Run(e.name, func(t *testing.T) {
+			require := require.New(t)
+
+			vm := gojis.NewVM()
+			vm.SetEngine(e.engine)
+			_, err := vm.Eval(`
+function Point(x) {
+	if (x < 0) {
+		throw new RangeError("negative");
+	}
+}
+var create = x => new Point(x);
+for (var i = 1; i >= -1; i--) {
+	[create(i)];
+}
+			`)
+			require.IsType(&gojis.Exception{}, err)
+			require.Equal([]gojis.StackFrame{
+				{Function: "Point", Script: "<eval-1>", Line: 4, Column: 3},
+				{Function: "create", Script: "<eval-1>", Line: 7, Column: 19},
+				{Script: "<eval-1>", Line: 9, Column: 3},
+			}, err.(*gojis.Exception).Frames())
+		})
+	}
+}
+
+func TestCallWithArgsException(t *testing.T) {
+	require := require.New(t)
+
+	vm := gojis.NewVM()
+	f, err := vm.Eval(`(function check(x) {
+	if (typeof x !== "number") throw new TypeError("not a number");
+	return x;
+})`)
+	require.NoError(err)
+
+	_, err = f.CallWithArgs("a")
+	require.IsType(&gojis.Exception{}, err)
+	exception := err.(*gojis.Exception)
+	require.Equal("TypeError: not a number", exception.Error())
+	require.Equal("    at check (<eval-1>:2:29)", exception.Stack())
+	require.EqualValues(gojis.TypeObject, exception.Value().Type())
+
+	toString := vm.Lookup("Symbol").Lookup("prototype").Lookup("toString")
+	_, err = toString.CallWithArgs()
+	require.IsType(&gojis.Exception{}, err)
+	require.Empty(err.(*gojis.Exception).Frames())
+}
+
+func TestGlobalObject(t *testing.T) {
+	require := require.New(t)
+
+	vm := gojis.NewVM()
+	for _, name := range []string{"Object", "Array", "JSON", "Math", "Promise", "parseInt", "NaN", "globalThis"} {
+		require.False(vm.Lookup(name).IsUndefined(), name)
+	}
+	require.True(vm.Lookup("parseInt").IsFunction())
+
+	vm.SetFunction("answer", func(gojis.Args) gojis.Object {
+		result, _ := vm.Eval(`42`)
+		return result
+	})
+	result, err := vm.Eval(`globalThis.answer() + Object.keys(globalThis).indexOf("Object")`)
+	require.NoError(err)
+	require.Equal(float64(41), result.Value())
+}
+
+func TestMaxCallDepth(t *testing.T) {
+	for _, e := range engines {
+		t.Run(e.name, func(t *testing.T) {
+			require := require.New(t)
+
+			vm := gojis.NewVM()
+			vm.SetEngine(e.engine)
+
+			// deep recursion throws a RangeError, which can be caught
+			result, err := vm.Eval(`
+var depth = 0;
+function f() {
+	depth++;
+	f();
+}
+try {
+	f();
+} catch (e) {
+	[e.name, e.message, depth].join()
+}
+			`)
+			require.NoError(err)
+			require.Equal("RangeError,Maximum call stack size exceeded,9998", result.Value())
+
+			vm.SetMaxCallDepth(100)
+			result, err = vm.Eval(`depth = 0; try { f(); } catch (e) { depth }`)
+			require.NoError(err)
+			// the stack also holds the contexts of the realm and the script
+			require.Equal(98.0, result.Value())
+
+			_, err = vm.Eval(`(function g() { new g(); })()`)
+			require.IsType(&gojis.Exception{}, err)
+			require.Equal("RangeError: Maximum call stack size exceeded", err.Error())
+
+			result, err = vm.Eval(`[1, 2, 3].map(x => x * 2).join()`)
+			require.NoError(err)
+			require.Equal("2,4,6", result.Value())
+
+			// nested calls of built-in and bound functions are counted as well
+			_, err = vm.Eval(`var a = []; for (var i = 0; i < 1000; i++) a = [a];`)
+			require.NoError(err)
+			for _, src := range []string{
+				`String(a)`,
+				`JSON.stringify(a)`,
+				`JSON.parse("[".repeat(1000) + "]".repeat(1000))`,
+				`a.flat(Infinity)`,
+				`var b = function() {}; for (var i = 0; i < 1000; i++) b = b.bind(); b()`,
+			} {
+				_, err = vm.Eval(src)
+				require.IsType(&gojis.Exception{}, err, src)
+				require.Equal("RangeError: Maximum call stack size exceeded", err.Error(), src)
+			}
+		})
+	}
+}
+
+func TestMaxNesting(t *testing.T) {
+	for _, e := range engines {
+		t.Run(e.name, func(t *testing.T) {
+			require := require.New(t)
+
+			vm := gojis.NewVM()
+			vm.SetEngine(e.engine)
+
+			// deep expressions in deep recursion throw a RangeError instead
+			// of overflowing the stack of the goroutine
+			body := strings.Repeat("-(", 400) + "f(k + 1)" + strings.Repeat(")", 400)
+			result, err := vm.Eval(`
+function f(k) {
+	if (k > 100000) return 0;
+	return ` + body + `;
+}
+try {
+	f(0);
+} catch (e) {
+	e.name + ": " + e.message
+}
+			`)
+			require.NoError(err)
+			require.Equal("RangeError: Maximum call stack size exceeded", result.Value())
+
+			result, err = vm.Eval(`f(99990)`)
+			require.NoError(err)
+			require.Equal(0.0, result.Value())
+		})
+	}
+}
+
+func TestPromiseJobs(t *testing.T) {
+	for _, e := range engines {
+		t.Run(e.name, func(t *testing.T) {
+			require := require.New(t)
+
+			vm := gojis.NewVM()
+			vm.SetEngine(e.engine)
+
+			// the reactions run after the script, before Eval returns
+			result, err := vm.Eval(`
+var log = [];
+Promise.resolve(1)
+	.then(v => { log.push(v); throw new Error("failed"); })
+	.catch(e => log.push(e.message));
+Promise.resolve({ then(resolve) { resolve(2); } }).then(v => log.push(v));
+log.push(0);
+log.join()
+			`)
+			require.NoError(err)
+			require.Equal("0", result.Value())
+			result, err = vm.Eval(`log.join()`)
+			require.NoError(err)
+			require.Equal("0,1,failed,2", result.Value())
+
+			// calls from the host run the reactions as well
+			resolve, err := vm.Eval(`log = []; var done; new Promise(r => done = r).then(v => log.push(v)); done`)
+			require.NoError(err)
+			_, err = resolve.CallWithArgs(vm.ToValue("resolved"))
+			require.NoError(err)
+			result, err = vm.Eval(`log.join()`)
+			require.NoError(err)
+			require.Equal("resolved", result.Value())
+
+			// any number of reactions can be pending at the same time
+			_, err = vm.Eval(`
+var count = 0, p = Promise.resolve();
+for (var i = 0; i < 1000; i++) p.then(() => count++);
+			`)
+			require.NoError(err)
+			result, err = vm.Eval(`count`)
+			require.NoError(err)
+			require.Equal(1000.0, result.Value())
+		})
+	}
+}
+
+func TestEvalReleasesScripts(t *testing.T) {
+	for _, e := range engines {
+		t.Run(e.name, func(t *testing.T) {
+			require := require.New(t)
+
+			vm := gojis.NewVM()
+			vm.SetEngine(e.engine)
+
+			var stats runtime.MemStats
+			runtime.GC()
+			runtime.ReadMemStats(&stats)
+			before := stats.HeapAlloc
+			for i := 0; i < 5000; i++ {
+				_, err := vm.Eval(fmt.Sprintf(`(function(a) { for (var i = 0; i < 3; i++) a += i; return a; })(%d)`, i))
+				require.NoError(err)
+			}
+			runtime.GC()
+			runtime.ReadMemStats(&stats)
+			runtime.KeepAlive(vm)
+
+			// the scripts and their bytecode took about 25MB when they
+			// were kept by the VM
+			require.True(int64(stats.HeapAlloc)-int64(before) < 5<<20, "heap grew from %v to %v bytes", before, stats.HeapAlloc)
+		})
+	}
+}